@@ -0,0 +1,145 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package actuator_tests
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/actuator/actuator_tests/testdata"
+	"github.com/cisco-open/go-lanai/pkg/actuator/auditevents"
+	"github.com/cisco-open/go-lanai/pkg/security/audit"
+	auditinit "github.com/cisco-open/go-lanai/pkg/security/audit/init"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/actuatortest"
+	"github.com/cisco-open/go-lanai/test/apptest"
+	"github.com/cisco-open/go-lanai/test/sectest"
+	"github.com/cisco-open/go-lanai/test/webtest"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+/*************************
+	Tests
+ *************************/
+
+func TestAuditEventsEndpoint(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		webtest.WithMockedServer(webtest.AddDefaultRequestOptions(v3RequestOptions())),
+		sectest.WithMockedMiddleware(),
+		actuatortest.WithEndpoints(actuatortest.DisableAllEndpoints()),
+		apptest.WithModules(auditinit.Module, auditevents.Module),
+		apptest.WithConfigFS(testdata.TestConfigFS),
+		apptest.WithProperties(
+			"management.endpoint.auditevents.enabled: true",
+			"security.audit.exclude-types: EXCLUDED",
+		),
+		test.GomegaSubTest(SubTestAuditEventsWithAccess(), "TestAuditEventsWithAccess"),
+		test.GomegaSubTest(SubTestAuditEventsFilters(), "TestAuditEventsFilters"),
+		test.GomegaSubTest(SubTestAuditEventsAccessDenied(), "TestAuditEventsAccessDenied"),
+		test.GomegaSubTest(SubTestAuditEventsWithoutAuth(), "TestAuditEventsWithoutAuth"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestAuditEventsWithAccess() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		audit.Publish(ctx, audit.NewAuditEvent("user-1", audit.TypeAuthenticationSuccess, nil))
+		audit.Publish(ctx, audit.NewAuditEvent("user-1", "EXCLUDED", nil))
+
+		ctx = sectest.ContextWithSecurity(ctx, mockedSecurityAdmin())
+		out := readAuditEvents(ctx, g, url.Values{"principal": []string{"user-1"}})
+		g.Expect(out.Events).To(HaveLen(1), "excluded event types should not be recorded")
+		g.Expect(out.Events[0].Principal).To(Equal("user-1"), "event should have correct principal")
+		g.Expect(out.Events[0].Type).To(Equal(audit.TypeAuthenticationSuccess), "event should have correct type")
+	}
+}
+
+func SubTestAuditEventsFilters() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		before := time.Now().UTC().Add(-time.Second)
+		old := audit.NewAuditEvent("user-2", audit.TypeAuthenticationFailure, nil)
+		old.Timestamp = before.Add(-time.Hour)
+		audit.Publish(ctx, old)
+		audit.Publish(ctx, audit.NewAuditEvent("user-2", audit.TypeAuthenticationFailure, nil))
+		audit.Publish(ctx, audit.NewAuditEvent("user-2", audit.TypeTokenIssued, nil))
+
+		ctx = sectest.ContextWithSecurity(ctx, mockedSecurityAdmin())
+		out := readAuditEvents(ctx, g, url.Values{"principal": []string{"user-2"}})
+		g.Expect(out.Events).To(HaveLen(3), "events should be filtered by principal")
+		g.Expect(out.Events[0].Type).To(Equal(audit.TypeTokenIssued), "most recent event should be first")
+
+		out = readAuditEvents(ctx, g, url.Values{"principal": []string{"user-2"}, "type": []string{audit.TypeAuthenticationFailure}})
+		g.Expect(out.Events).To(HaveLen(2), "events should be filtered by type")
+
+		out = readAuditEvents(ctx, g, url.Values{"principal": []string{"user-2"}, "after": []string{before.Format(time.RFC3339)}})
+		g.Expect(out.Events).To(HaveLen(2), "events should be filtered by time")
+
+		out = readAuditEvents(ctx, g, url.Values{"principal": []string{"user-2"}, "limit": []string{"1"}})
+		g.Expect(out.Events).To(HaveLen(1), "events should be limited")
+
+		req := webtest.NewRequest(ctx, http.MethodGet, "/admin/auditevents?after=yesterday", nil)
+		resp := webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusBadRequest)
+	}
+}
+
+func SubTestAuditEventsAccessDenied() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		nonAdmin := sectest.MockedAuthentication(func(d *sectest.SecurityDetailsMock) {
+			d.Username = "non-admin"
+			d.Permissions = utils.NewStringSet("not_worthy")
+		})
+		req := webtest.NewRequest(sectest.ContextWithSecurity(ctx, nonAdmin), http.MethodGet, "/admin/auditevents", nil)
+		resp := webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusForbidden)
+
+		// access denial should be audited
+		ctx = sectest.ContextWithSecurity(ctx, mockedSecurityAdmin())
+		out := readAuditEvents(ctx, g, url.Values{"principal": []string{"non-admin"}})
+		g.Expect(out.Events).To(HaveLen(1), "access denial should be audited")
+		g.Expect(out.Events[0].Type).To(Equal(audit.TypeAuthorizationFailure), "event should have correct type")
+		g.Expect(out.Events[0].Data).To(HaveKeyWithValue(audit.DataKeyPath, "/test/admin/auditevents"), "event should have request path")
+	}
+}
+
+func SubTestAuditEventsWithoutAuth() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		req := webtest.NewRequest(ctx, http.MethodGet, "/admin/auditevents", nil)
+		resp := webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusUnauthorized)
+	}
+}
+
+/*************************
+	Common Helpers
+ *************************/
+
+func readAuditEvents(ctx context.Context, g *WithT, query url.Values) *auditevents.Output {
+	req := webtest.NewRequest(ctx, http.MethodGet, "/admin/auditevents?"+query.Encode(), nil)
+	resp := webtest.MustExec(ctx, req)
+	g.Expect(resp.Response.StatusCode).To(Equal(http.StatusOK), "status code should be correct")
+	var out auditevents.Output
+	decodeJsonBody(g, resp.Response, &out)
+	return &out
+}
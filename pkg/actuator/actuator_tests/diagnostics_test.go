@@ -0,0 +1,218 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package actuator_tests
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/cisco-open/go-lanai/pkg/actuator/actuator_tests/testdata"
+	"github.com/cisco-open/go-lanai/pkg/actuator/configprops"
+	"github.com/cisco-open/go-lanai/pkg/actuator/goroutines"
+	"github.com/cisco-open/go-lanai/pkg/actuator/memstats"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/actuatortest"
+	"github.com/cisco-open/go-lanai/test/apptest"
+	"github.com/cisco-open/go-lanai/test/sectest"
+	"github.com/cisco-open/go-lanai/test/webtest"
+	. "github.com/onsi/gomega"
+	"go.uber.org/fx"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+/*************************
+	Setup
+ *************************/
+
+type DiagnosticsTestProperties struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+	Timeout  string `json:"timeout"`
+	Retries  int    `json:"retries"`
+}
+
+func BindDiagnosticsTestProperties(ctx *bootstrap.ApplicationContext) DiagnosticsTestProperties {
+	props := DiagnosticsTestProperties{Timeout: "10s", Retries: 3}
+	if e := ctx.Config().Bind(&props, "diagnostics.test"); e != nil {
+		panic(e)
+	}
+	return props
+}
+
+/*************************
+	Tests
+ *************************/
+
+func TestDiagnosticsEndpoints(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		webtest.WithMockedServer(webtest.AddDefaultRequestOptions(v3RequestOptions())),
+		sectest.WithMockedMiddleware(),
+		actuatortest.WithEndpoints(actuatortest.DisableAllEndpoints()),
+		apptest.WithModules(goroutines.Module, memstats.Module, configprops.Module),
+		apptest.WithConfigFS(testdata.TestConfigFS),
+		apptest.WithProperties(
+			"management.endpoint.goroutines.enabled: true",
+			"management.endpoint.memstats.enabled: true",
+			"management.endpoint.configprops.enabled: true",
+			"diagnostics.test.name: diagnostics",
+			"diagnostics.test.password: very-secret",
+			"diagnostics.test.timeout: 20s",
+		),
+		apptest.WithFxOptions(
+			fx.Invoke(BindDiagnosticsTestProperties),
+		),
+		test.GomegaSubTest(SubTestGoroutineDump(mockedSecurityAdmin()), "TestGoroutineDump"),
+		test.GomegaSubTest(SubTestMemStats(mockedSecurityAdmin()), "TestMemStats"),
+		test.GomegaSubTest(SubTestConfigProps(mockedSecurityAdmin()), "TestConfigProps"),
+		test.GomegaSubTest(SubTestDiagnosticsWithoutAccess(mockedSecurityNonAdmin()), "TestDiagnosticsWithoutAccess"),
+		test.GomegaSubTest(SubTestDiagnosticsWithoutAuth(), "TestDiagnosticsWithoutAuth"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestGoroutineDump(secOpts sectest.SecurityContextOptions) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		// start some goroutines with identical stack
+		const count = 5
+		done := make(chan struct{})
+		defer close(done)
+		for i := 0; i < count; i++ {
+			go func() { <-done }()
+		}
+		// give goroutines a chance to park
+		time.Sleep(10 * time.Millisecond)
+
+		ctx = sectest.ContextWithSecurity(ctx, secOpts)
+		req := webtest.NewRequest(ctx, http.MethodGet, "/admin/goroutines", nil)
+		resp := webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusOK)
+		var dump goroutines.DumpDescriptor
+		decodeJsonBody(g, resp.Response, &dump)
+		g.Expect(dump.Total).To(BeNumerically(">=", count), "total should be correct")
+		g.Expect(dump.Groups).ToNot(BeEmpty(), "groups should not be empty")
+		var found bool
+		for _, group := range dump.Groups {
+			g.Expect(group.IDs).To(HaveLen(group.Count), "IDs should match count")
+			g.Expect(group.Stack).ToNot(BeEmpty(), "stack should not be empty")
+			if group.States["chan receive"] == count {
+				found = true
+			}
+		}
+		g.Expect(found).To(BeTrue(), "goroutines with identical stack should be grouped")
+
+		// with state filter
+		req = webtest.NewRequest(ctx, http.MethodGet, "/admin/goroutines?state=running", nil)
+		resp = webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusOK)
+		dump = goroutines.DumpDescriptor{}
+		decodeJsonBody(g, resp.Response, &dump)
+		for _, group := range dump.Groups {
+			g.Expect(group.States).To(HaveLen(1), "filtered groups should have single state")
+			g.Expect(group.States).To(HaveKey("running"), "filtered groups should have correct state")
+		}
+	}
+}
+
+func SubTestMemStats(secOpts sectest.SecurityContextOptions) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		ctx = sectest.ContextWithSecurity(ctx, secOpts)
+		req := webtest.NewRequest(ctx, http.MethodGet, "/admin/memstats", nil)
+		resp := webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusOK)
+		var stats memstats.MemStatsDescriptor
+		decodeJsonBody(g, resp.Response, &stats)
+		g.Expect(stats.Runtime.Version).ToNot(BeEmpty(), "runtime version should be present")
+		g.Expect(stats.Runtime.Goroutines).To(BeNumerically(">", 0), "goroutines should be present")
+		g.Expect(stats.Heap.Alloc).To(BeNumerically(">", 0), "heap alloc should be present")
+		g.Expect(stats.Sys).To(BeNumerically(">", 0), "sys should be present")
+	}
+}
+
+func SubTestConfigProps(secOpts sectest.SecurityContextOptions) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		ctx = sectest.ContextWithSecurity(ctx, secOpts)
+		req := webtest.NewRequest(ctx, http.MethodGet, "/admin/configprops", nil)
+		resp := webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusOK)
+		var desc configprops.ConfigPropsDescriptor
+		decodeJsonBody(g, resp.Response, &desc)
+		g.Expect(desc.Beans).To(HaveKey("actuator.ManagementProperties"), "management properties should be present")
+		g.Expect(desc.Beans).To(HaveKey("actuator_tests.DiagnosticsTestProperties"), "test properties should be present")
+
+		bean := desc.Beans["actuator_tests.DiagnosticsTestProperties"]
+		g.Expect(bean.Prefix).To(Equal("diagnostics.test"), "prefix should be correct")
+		g.Expect(bean.Properties).To(HaveKeyWithValue("name", configprops.PValueDescriptor{
+			Value: "diagnostics", Origin: "test-properties",
+		}), "value and origin should be correct")
+		g.Expect(bean.Properties).To(HaveKeyWithValue("password", configprops.PValueDescriptor{
+			Value: "********", Origin: "test-properties",
+		}), "sensitive value should be sanitized")
+		// "timeout" is set by both test properties and application-test.yml
+		g.Expect(bean.Properties).To(HaveKeyWithValue("timeout", configprops.PValueDescriptor{
+			Value: "30s", Origin: "file:application-test.yml",
+		}), "value and origin should be from the source with highest precedence")
+		g.Expect(bean.Properties).To(HaveKeyWithValue("retries", configprops.PValueDescriptor{
+			Value: float64(3), Origin: configprops.OriginDefault,
+		}), "default value should have default origin")
+
+		// with prefix filter
+		req = webtest.NewRequest(ctx, http.MethodGet, "/admin/configprops?prefix=diagnostics", nil)
+		resp = webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusOK)
+		desc = configprops.ConfigPropsDescriptor{}
+		decodeJsonBody(g, resp.Response, &desc)
+		g.Expect(desc.Beans).To(HaveLen(1), "filtered beans should be correct")
+	}
+}
+
+func SubTestDiagnosticsWithoutAccess(secOpts sectest.SecurityContextOptions) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		ctx = sectest.ContextWithSecurity(ctx, secOpts)
+		for _, path := range []string{"/admin/goroutines", "/admin/memstats", "/admin/configprops"} {
+			req := webtest.NewRequest(ctx, http.MethodGet, path, nil)
+			resp := webtest.MustExec(ctx, req)
+			assertResponse(t, g, resp.Response, http.StatusForbidden)
+		}
+	}
+}
+
+func SubTestDiagnosticsWithoutAuth() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		for _, path := range []string{"/admin/goroutines", "/admin/memstats", "/admin/configprops"} {
+			req := webtest.NewRequest(ctx, http.MethodGet, path, nil)
+			resp := webtest.MustExec(ctx, req)
+			assertResponse(t, g, resp.Response, http.StatusUnauthorized)
+		}
+	}
+}
+
+/*************************
+	Common Helpers
+ *************************/
+
+func decodeJsonBody(g *WithT, resp *http.Response, v interface{}) {
+	body, e := io.ReadAll(resp.Body)
+	g.Expect(e).To(Succeed(), "response body should be readable")
+	g.Expect(json.Unmarshal(body, v)).To(Succeed(), "response body should be valid JSON")
+}
@@ -35,6 +35,7 @@ import (
     "go.uber.org/fx"
     "net/http"
     "testing"
+    "time"
 )
 
 /*************************
@@ -114,6 +115,61 @@ func TestHealthWithCustomDisclosure(t *testing.T) {
 	)
 }
 
+type HealthGroupTestDI struct {
+	fx.In
+	HealthTestDI
+	Availability *health.ApplicationAvailability
+}
+
+func TestHealthGroups(t *testing.T) {
+	di := &HealthGroupTestDI{}
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		webtest.WithMockedServer(webtest.AddDefaultRequestOptions(v3RequestOptions())),
+		sectest.WithMockedMiddleware(),
+		actuatortest.WithEndpoints(actuatortest.DisableAllEndpoints()),
+		apptest.WithModules(health.Module, healthep.Module),
+		apptest.WithConfigFS(testdata.TestConfigFS),
+		apptest.WithProperties(
+			"management.endpoint.health.group.readiness.include: readinessState, test",
+			"management.endpoint.health.group.custom.exclude: test",
+			"management.endpoint.health.group.custom.show-details: never",
+		),
+		apptest.WithFxOptions(
+			fx.Provide(testdata.NewMockedHealthIndicator),
+			fx.Invoke(ConfigureHealth),
+		),
+		apptest.WithDI(di),
+		test.GomegaSubTest(SubTestHealthProbes(), "TestHealthProbes"),
+		test.GomegaSubTest(SubTestHealthGroupWithIndicatorDown(di), "TestHealthGroupWithIndicatorDown"),
+		test.GomegaSubTest(SubTestHealthGroupRefusingTraffic(di), "TestHealthGroupRefusingTraffic"),
+		test.GomegaSubTest(SubTestHealthGroupOverrides(), "TestHealthGroupOverrides"),
+		test.GomegaSubTest(SubTestHealthGroupNotFound(), "TestHealthGroupNotFound"),
+	)
+}
+
+func TestHealthIndicatorTimeoutAndCache(t *testing.T) {
+	di := &HealthTestDI{}
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		webtest.WithMockedServer(),
+		sectest.WithMockedMiddleware(),
+		actuatortest.WithEndpoints(actuatortest.DisableAllEndpoints()),
+		apptest.WithModules(health.Module, healthep.Module),
+		apptest.WithConfigFS(testdata.TestConfigFS),
+		apptest.WithProperties(
+			"management.endpoint.health.timeout: 100ms",
+			"management.endpoint.health.indicator.test.cache-ttl: 1h",
+		),
+		apptest.WithFxOptions(
+			fx.Provide(testdata.NewMockedHealthIndicator),
+			fx.Invoke(ConfigureHealth),
+		),
+		apptest.WithDI(di),
+		test.GomegaSubTest(SubTestHealthIndicatorCache(di), "TestHealthIndicatorCache"),
+	)
+}
+
 /*************************
 	Sub Tests
  *************************/
@@ -246,6 +302,107 @@ func SubTestHealthIndicator(di *HealthTestDI) test.GomegaSubTestFunc {
 	}
 }
 
+func SubTestHealthProbes() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		ctx = sectest.ContextWithSecurity(ctx, mockedSecurityAdmin())
+		for _, group := range []string{"liveness", "startup"} {
+			req := webtest.NewRequest(ctx, http.MethodGet, "/admin/health/"+group, nil)
+			resp := webtest.MustExec(ctx, req)
+			assertResponse(t, g, resp.Response, http.StatusOK, "Content-Type", actuator.ContentTypeSpringBootV3)
+			AssertHealthResponse(t, resp.Response, ExpectHealthDetails(), ExpectHealthComponents(group+"State"))
+		}
+
+		req := webtest.NewRequest(ctx, http.MethodGet, "/admin/health/readiness", nil)
+		resp := webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusOK, "Content-Type", actuator.ContentTypeSpringBootV3)
+		AssertHealthResponse(t, resp.Response, ExpectHealthDetails(), ExpectHealthComponents("readinessState", "test"))
+	}
+}
+
+func SubTestHealthGroupWithIndicatorDown(di *HealthGroupTestDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		defer func() {
+			di.MockedIndicator.Status = health.StatusUp
+		}()
+		di.MockedIndicator.Status = health.StatusDown
+		// readiness includes "test" indicator
+		req := webtest.NewRequest(ctx, http.MethodGet, "/admin/health/readiness", nil)
+		resp := webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusServiceUnavailable)
+		AssertHealthResponse(t, resp.Response, ExpectHealth(health.StatusDown))
+
+		// liveness is not affected
+		req = webtest.NewRequest(ctx, http.MethodGet, "/admin/health/liveness", nil)
+		resp = webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusOK)
+		AssertHealthResponse(t, resp.Response, ExpectHealth(health.StatusUp))
+	}
+}
+
+func SubTestHealthGroupRefusingTraffic(di *HealthGroupTestDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		g.Expect(di.Availability.ReadinessState()).To(Equal(health.ReadinessAcceptingTraffic), "readiness should be accepting traffic after startup")
+		g.Expect(di.Availability.Started()).To(BeTrue(), "application should be started")
+		defer di.Availability.SetReadinessState(ctx, health.ReadinessAcceptingTraffic)
+		di.Availability.SetReadinessState(ctx, health.ReadinessRefusingTraffic)
+
+		req := webtest.NewRequest(ctx, http.MethodGet, "/admin/health/readiness", nil)
+		resp := webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusServiceUnavailable)
+		AssertHealthResponse(t, resp.Response, ExpectHealth(health.StatusOutOfService))
+
+		// root health is not affected
+		req = webtest.NewRequest(ctx, http.MethodGet, "/admin/health", nil)
+		resp = webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusOK)
+	}
+}
+
+func SubTestHealthGroupOverrides() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		ctx = sectest.ContextWithSecurity(ctx, mockedSecurityAdmin())
+		// "custom" group includes everything except "test", without details
+		req := webtest.NewRequest(ctx, http.MethodGet, "/admin/health/custom", nil)
+		resp := webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusOK)
+		AssertHealthResponse(t, resp.Response, ExpectHealthComponents("ping", "livenessState", "readinessState", "startupState"))
+	}
+}
+
+func SubTestHealthGroupNotFound() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		req := webtest.NewRequest(ctx, http.MethodGet, "/admin/health/unknown", nil)
+		resp := webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusNotFound)
+	}
+}
+
+func SubTestHealthIndicatorCache(di *HealthTestDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		opts := health.Options{ShowDetails: true, ShowComponents: true}
+		h := di.HealthIndicator.Health(ctx, opts)
+		g.Expect(h.Status()).To(Equal(health.StatusUp), "health should be UP")
+
+		// cached result
+		defer func() {
+			di.MockedIndicator.Status = health.StatusUp
+			di.MockedIndicator.Delay = 0
+		}()
+		di.MockedIndicator.Status = health.StatusDown
+		h = di.HealthIndicator.Health(ctx, opts)
+		g.Expect(h.Status()).To(Equal(health.StatusUp), "cached health should be returned")
+
+		// timeout, options not cached yet
+		di.MockedIndicator.Delay = time.Second
+		opts.ShowDetails = false
+		h = di.HealthIndicator.Health(ctx, opts)
+		g.Expect(h.Status()).To(Equal(health.StatusDown), "timed out indicator should be DOWN")
+		comps := h.(*health.CompositeHealth).Components
+		g.Expect(comps).To(HaveKey("test"))
+		g.Expect(comps["test"].Description()).To(ContainSubstring("timed out"), "description should indicate timeout")
+	}
+}
+
 /*************************
 	Common Helpers
  *************************/
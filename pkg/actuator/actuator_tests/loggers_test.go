@@ -18,21 +18,29 @@ package actuator_tests
 
 import (
     "context"
+    "encoding/json"
     "fmt"
     "github.com/cisco-open/go-lanai/pkg/actuator"
     "github.com/cisco-open/go-lanai/pkg/actuator/actuator_tests/testdata"
+    "github.com/cisco-open/go-lanai/pkg/bootstrap"
     "github.com/cisco-open/go-lanai/pkg/actuator/loggers"
+    redisloggers "github.com/cisco-open/go-lanai/pkg/actuator/loggers/redis"
     "github.com/cisco-open/go-lanai/pkg/log"
+    "github.com/cisco-open/go-lanai/pkg/redis"
+    "github.com/cisco-open/go-lanai/pkg/utils"
     "github.com/cisco-open/go-lanai/test"
     "github.com/cisco-open/go-lanai/test/actuatortest"
     . "github.com/cisco-open/go-lanai/test/actuatortest"
     "github.com/cisco-open/go-lanai/test/apptest"
+    "github.com/cisco-open/go-lanai/test/embedded"
     "github.com/cisco-open/go-lanai/test/sectest"
     "github.com/cisco-open/go-lanai/test/webtest"
     . "github.com/onsi/gomega"
+    "go.uber.org/fx"
     "net/http"
     "strings"
     "testing"
+    "time"
 )
 
 var _ = log.New("Test")
@@ -56,6 +64,32 @@ func TestLoggersEndpoint(t *testing.T) {
 	)
 }
 
+type LoggersClusterTestDI struct {
+	fx.In
+	AppCtx      *bootstrap.ApplicationContext
+	RedisClient redis.Client
+}
+
+func TestLoggersCluster(t *testing.T) {
+	di := &LoggersClusterTestDI{}
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		embedded.WithRedis(),
+		webtest.WithMockedServer(webtest.AddDefaultRequestOptions(v3RequestOptions())),
+		sectest.WithMockedMiddleware(),
+		actuatortest.WithEndpoints(actuatortest.DisableAllEndpoints()),
+		apptest.WithModules(loggers.Module, redisloggers.Module, redis.Module),
+		apptest.WithConfigFS(testdata.TestConfigFS),
+		apptest.WithProperties(
+			"management.endpoint.loggers.cluster.enabled: true",
+			"management.endpoint.loggers.cluster.type: redis",
+		),
+		apptest.WithDI(di),
+		test.GomegaSubTest(SubTestClusterChangeWithTTL(mockedSecurityAdmin()), "TestClusterChangeWithTTL"),
+		test.GomegaSubTest(SubTestClusterReceiveChange(di, mockedSecurityAdmin()), "TestClusterReceiveChange"),
+	)
+}
+
 /*************************
 	Sub Tests
  *************************/
@@ -126,6 +160,86 @@ func SubTestChangeLoggers(secOpts sectest.SecurityContextOptions) test.GomegaSub
 	}
 }
 
+func SubTestClusterChangeWithTTL(secOpts sectest.SecurityContextOptions) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		const loggerName = "bootstrap"
+		ctx = sectest.ContextWithSecurity(ctx, secOpts)
+		original := log.Levels(loggerName)[loggerName].ConfiguredLevel
+
+		// send POST with TTL
+		body := `{"configuredLevel":"WARN","ttl":"500ms"}`
+		req := webtest.NewRequest(ctx, http.MethodPost, "/admin/loggers/"+loggerName, strings.NewReader(body),
+			webtest.ContentType("application/json"))
+		resp := webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusNoContent)
+
+		// check result, current instance should be reported
+		req = webtest.NewRequest(ctx, http.MethodGet, "/admin/loggers/"+loggerName, nil)
+		resp = webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusOK, "Content-Type", actuator.ContentTypeSpringBootV3)
+		var out loggers.LoggerLevel
+		decodeJsonBody(g, resp.Response, &out)
+		g.Expect(out.ConfiguredLevel).To(HaveValue(Equal(log.LevelWarn)), "configured level should be changed")
+		g.Expect(out.Instances).To(HaveLen(1), "response should have levels of current instance")
+		for _, v := range out.Instances {
+			g.Expect(v.EffectiveLevel).To(HaveValue(Equal(log.LevelWarn)), "instance effective level should be changed")
+			g.Expect(v.ConfiguredLevel).To(HaveValue(Equal(log.LevelWarn)), "instance configured level should be changed")
+		}
+
+		// level should be restored after TTL
+		g.Eventually(func() *log.LoggingLevel {
+			return log.Levels(loggerName)[loggerName].ConfiguredLevel
+		}).WithTimeout(3 * time.Second).Should(Equal(original), "level should be restored after TTL")
+	}
+}
+
+func SubTestClusterReceiveChange(di *LoggersClusterTestDI, secOpts sectest.SecurityContextOptions) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		const loggerName = "bootstrap"
+		const otherInstance = "other-instance"
+		ctx = sectest.ContextWithSecurity(ctx, secOpts)
+		other := redisloggers.NewRedisBroadcaster(di.RedisClient, redisloggers.DefaultKeyPrefix+":"+di.AppCtx.Name())
+
+		// another instance reports its levels and broadcasts a change
+		lvl := log.LevelError
+		e := other.Report(ctx, loggers.InstanceLevels{
+			InstanceID: otherInstance,
+			Levels:     map[string]log.LoggingLevel{"default": log.LevelInfo, loggerName: lvl},
+			Timestamp:  time.Now(),
+		})
+		g.Expect(e).To(Succeed(), "report should not fail")
+		e = other.Broadcast(ctx, loggers.LevelChange{
+			Prefix: loggerName,
+			Level:  &lvl,
+			TTL:    utils.Duration(time.Minute),
+			Source: otherInstance,
+		})
+		g.Expect(e).To(Succeed(), "broadcast should not fail")
+
+		// current instance should apply the change
+		g.Eventually(func() *log.LoggingLevel {
+			return log.Levels(loggerName)[loggerName].ConfiguredLevel
+		}).WithTimeout(3 * time.Second).Should(HaveValue(Equal(lvl)), "change from other instance should be applied")
+
+		// endpoint should report both instances, once current instance re-reported its levels
+		g.Eventually(func(g Gomega) {
+			req := webtest.NewRequest(ctx, http.MethodGet, "/admin/loggers/"+loggerName, nil)
+			resp := webtest.MustExec(ctx, req)
+			g.Expect(resp.Response.StatusCode).To(Equal(http.StatusOK), "response should have correct status code")
+			var out loggers.LoggerLevel
+			g.Expect(json.NewDecoder(resp.Response.Body).Decode(&out)).To(Succeed(), "response should be valid JSON")
+			g.Expect(out.Instances).To(HaveLen(2), "response should have levels of all instances")
+			g.Expect(out.Instances).To(HaveKey(otherInstance), "response should have levels of other instance")
+			for _, v := range out.Instances {
+				g.Expect(v.EffectiveLevel).To(HaveValue(Equal(lvl)), "instance effective level should be correct")
+			}
+		}).WithTimeout(3 * time.Second).Should(Succeed())
+
+		// unset
+		log.SetLevel(loggerName, nil)
+	}
+}
+
 /*************************
 	Common Helpers
  *************************/
@@ -0,0 +1,117 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package actuator_tests
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/actuator/actuator_tests/testdata"
+	"github.com/cisco-open/go-lanai/pkg/actuator/prometheus"
+	"github.com/cisco-open/go-lanai/pkg/metrics"
+	metricsinit "github.com/cisco-open/go-lanai/pkg/metrics/init"
+	webmetrics "github.com/cisco-open/go-lanai/pkg/web/metrics"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/actuatortest"
+	"github.com/cisco-open/go-lanai/test/apptest"
+	"github.com/cisco-open/go-lanai/test/sectest"
+	"github.com/cisco-open/go-lanai/test/webtest"
+	. "github.com/onsi/gomega"
+	"go.uber.org/fx"
+	"io"
+	"net/http"
+	"testing"
+)
+
+/*************************
+	Tests
+ *************************/
+
+type PrometheusTestDI struct {
+	fx.In
+	Registry metrics.Registry
+}
+
+func TestPrometheusEndpoint(t *testing.T) {
+	di := &PrometheusTestDI{}
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		webtest.WithMockedServer(),
+		sectest.WithMockedMiddleware(),
+		actuatortest.WithEndpoints(actuatortest.DisableAllEndpoints()),
+		apptest.WithModules(metricsinit.Module, prometheus.Module, webmetrics.Module),
+		apptest.WithConfigFS(testdata.TestConfigFS),
+		apptest.WithDI(di),
+		test.GomegaSubTest(SubTestPrometheusTextFormat(di, mockedSecurityAdmin()), "TestPrometheusTextFormat"),
+		test.GomegaSubTest(SubTestPrometheusOpenMetricsFormat(mockedSecurityAdmin()), "TestPrometheusOpenMetricsFormat"),
+		test.GomegaSubTest(SubTestPrometheusWithoutAccess(mockedSecurityNonAdmin()), "TestPrometheusWithoutAccess"),
+		test.GomegaSubTest(SubTestPrometheusWithoutAuth(), "TestPrometheusWithoutAuth"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestPrometheusTextFormat(di *PrometheusTestDI, secOpts sectest.SecurityContextOptions) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		di.Registry.Counter("test.counter", metrics.WithTags("key")).Inc(metrics.Tags{"key": "value"})
+		ctx = sectest.ContextWithSecurity(ctx, secOpts)
+		// first request to make sure web metrics are recorded
+		webtest.MustExec(ctx, webtest.NewRequest(ctx, http.MethodGet, "/admin/prometheus", nil))
+
+		req := webtest.NewRequest(ctx, http.MethodGet, "/admin/prometheus", nil, webtest.Headers("Accept", "text/plain"))
+		resp := webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusOK), "status code should be correct")
+		g.Expect(resp.Response.Header.Get("Content-Type")).To(HavePrefix("text/plain"), "content type should be correct")
+		body, e := io.ReadAll(resp.Response.Body)
+		g.Expect(e).To(Succeed(), "response body should be readable")
+		g.Expect(string(body)).To(ContainSubstring(`test_counter_total{application="`), "body should contain custom counter with common tags")
+		g.Expect(string(body)).To(ContainSubstring(`key="value"`), "body should contain custom counter's tags")
+		g.Expect(string(body)).To(ContainSubstring(`http_server_requests_seconds_count{`), "body should contain web metrics")
+		g.Expect(string(body)).To(ContainSubstring(`go_goroutines`), "body should contain runtime metrics")
+	}
+}
+
+func SubTestPrometheusOpenMetricsFormat(secOpts sectest.SecurityContextOptions) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		ctx = sectest.ContextWithSecurity(ctx, secOpts)
+		req := webtest.NewRequest(ctx, http.MethodGet, "/admin/prometheus", nil,
+			webtest.Headers("Accept", "application/openmetrics-text; version=1.0.0"))
+		resp := webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusOK), "status code should be correct")
+		g.Expect(resp.Response.Header.Get("Content-Type")).To(HavePrefix("application/openmetrics-text"), "content type should be correct")
+		body, e := io.ReadAll(resp.Response.Body)
+		g.Expect(e).To(Succeed(), "response body should be readable")
+		g.Expect(string(body)).To(HaveSuffix("# EOF\n"), "OpenMetrics body should be terminated")
+	}
+}
+
+func SubTestPrometheusWithoutAccess(secOpts sectest.SecurityContextOptions) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		ctx = sectest.ContextWithSecurity(ctx, secOpts)
+		req := webtest.NewRequest(ctx, http.MethodGet, "/admin/prometheus", nil, v3RequestOptions())
+		resp := webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusForbidden)
+	}
+}
+
+func SubTestPrometheusWithoutAuth() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		req := webtest.NewRequest(ctx, http.MethodGet, "/admin/prometheus", nil, v3RequestOptions())
+		resp := webtest.MustExec(ctx, req)
+		assertResponse(t, g, resp.Response, http.StatusUnauthorized)
+	}
+}
@@ -19,12 +19,14 @@ package testdata
 import (
 	"context"
 	"github.com/cisco-open/go-lanai/pkg/actuator/health"
+	"time"
 )
 
 type MockedHealthIndicator struct {
 	Status      health.Status
 	Description string
 	Details     map[string]interface{}
+	Delay       time.Duration
 }
 
 func NewMockedHealthIndicator() *MockedHealthIndicator {
@@ -41,7 +43,13 @@ func (i *MockedHealthIndicator) Name() string {
 	return "test"
 }
 
-func (i *MockedHealthIndicator) Health(_ context.Context, opts health.Options) health.Health {
+func (i *MockedHealthIndicator) Health(ctx context.Context, opts health.Options) health.Health {
+	if i.Delay > 0 {
+		select {
+		case <-time.After(i.Delay):
+		case <-ctx.Done():
+		}
+	}
 	ret := health.CompositeHealth{
 		SimpleHealth: health.SimpleHealth{
 			Stat: i.Status,
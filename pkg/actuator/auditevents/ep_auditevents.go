@@ -0,0 +1,92 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auditevents
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/actuator"
+	"github.com/cisco-open/go-lanai/pkg/security/audit"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"net/http"
+	"time"
+)
+
+const (
+	ID              = "auditevents"
+	EnableByDefault = false
+	DefaultLimit    = 100
+)
+
+type Input struct {
+	Principal string `form:"principal"`
+	Type      string `form:"type"`
+	// After is in RFC3339 format, e.g. "2006-01-02T15:04:05Z"
+	After string `form:"after"`
+	Limit int    `form:"limit"`
+}
+
+type Output struct {
+	Events []*audit.AuditEvent `json:"events"`
+}
+
+// AuditEventsEndpoint implements actuator.Endpoint, actuator.WebEndpoint
+//
+//goland:noinspection GoNameStartsWithPackageName
+type AuditEventsEndpoint struct {
+	actuator.WebEndpointBase
+	repo audit.AuditEventRepository
+}
+
+func newEndpoint(di regDI) *AuditEventsEndpoint {
+	ep := AuditEventsEndpoint{
+		repo: di.Repository,
+	}
+	ep.WebEndpointBase = actuator.MakeWebEndpointBase(func(opt *actuator.EndpointOption) {
+		opt.Id = ID
+		opt.Ops = []actuator.Operation{
+			actuator.NewReadOperation(ep.Read),
+		}
+		opt.Properties = &di.MgtProperties.Endpoints
+		opt.EnabledByDefault = EnableByDefault
+	})
+	return &ep
+}
+
+// Read returns audit events filtered by principal, type and time, most recent events first
+func (ep *AuditEventsEndpoint) Read(ctx context.Context, input *Input) (*Output, error) {
+	query := audit.Query{
+		Principal: input.Principal,
+		Type:      input.Type,
+		Limit:     input.Limit,
+	}
+	if query.Limit <= 0 {
+		query.Limit = DefaultLimit
+	}
+	if input.After != "" {
+		after, e := time.Parse(time.RFC3339, input.After)
+		if e != nil {
+			return nil, web.NewHttpError(http.StatusBadRequest, fmt.Errorf(`invalid "after" [%s]: expect RFC3339 format`, input.After))
+		}
+		query.After = after
+	}
+	events, e := ep.repo.Find(ctx, query)
+	if e != nil {
+		return nil, e
+	}
+	return &Output{Events: events}, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package configprops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/actuator"
+	"github.com/cisco-open/go-lanai/pkg/actuator/env"
+	"github.com/cisco-open/go-lanai/pkg/appconfig"
+	"strings"
+)
+
+const (
+	ID              = "configprops"
+	EnableByDefault = false
+	// OriginDefault is the origin of values that are not set by any property source
+	OriginDefault = "default"
+)
+
+type Input struct {
+	// Prefix filters properties structs by their binding prefix
+	Prefix string `form:"prefix"`
+}
+
+// ConfigPropsDescriptor describes all bound properties structs, keyed by type name.
+// When same type is bound to multiple prefixes, the prefix is appended to the key, e.g. "pkg.Properties[some.prefix]"
+type ConfigPropsDescriptor struct {
+	Beans map[string]BeanDescriptor `json:"beans"`
+}
+
+type BeanDescriptor struct {
+	Prefix string `json:"prefix"`
+	// Properties are flattened effective values, keyed by property name relative to the prefix
+	Properties map[string]PValueDescriptor `json:"properties"`
+}
+
+type PValueDescriptor struct {
+	Value  interface{} `json:"value"`
+	Origin string      `json:"origin"`
+}
+
+// ConfigPropsEndpoint implements actuator.Endpoint, actuator.WebEndpoint
+//
+//goland:noinspection GoNameStartsWithPackageName
+type ConfigPropsEndpoint struct {
+	actuator.WebEndpointBase
+	appConfig appconfig.ConfigAccessor
+	sanitizer *env.Sanitizer
+}
+
+func newEndpoint(di regDI) *ConfigPropsEndpoint {
+	ep := ConfigPropsEndpoint{
+		appConfig: di.AppContext.Config().(appconfig.ConfigAccessor),
+		sanitizer: env.NewSanitizer(di.Properties.KeysToSanitize.Values()),
+	}
+	ep.WebEndpointBase = actuator.MakeWebEndpointBase(func(opt *actuator.EndpointOption) {
+		opt.Id = ID
+		opt.Ops = []actuator.Operation{
+			actuator.NewReadOperation(ep.Read),
+		}
+		opt.Properties = &di.MgtProperties.Endpoints
+		opt.EnabledByDefault = EnableByDefault
+	})
+	return &ep
+}
+
+// Read returns effective values of all properties structs bound via bootstrap.ApplicationConfig.
+// The origin of each value is the name of the highest precedence property source that contains the key,
+// or OriginDefault if the value is not from any property source.
+func (ep *ConfigPropsEndpoint) Read(ctx context.Context, input *Input) (*ConfigPropsDescriptor, error) {
+	origins := ep.resolveOrigins()
+	desc := ConfigPropsDescriptor{
+		Beans: map[string]BeanDescriptor{},
+	}
+	for _, binding := range ep.appConfig.Bindings() {
+		if input.Prefix != "" && !strings.HasPrefix(binding.Prefix, input.Prefix) {
+			continue
+		}
+		bean := BeanDescriptor{
+			Prefix:     binding.Prefix,
+			Properties: map[string]PValueDescriptor{},
+		}
+		values, e := toMap(binding.Target)
+		if e != nil {
+			logger.WithContext(ctx).Debugf("unable to describe properties [%T]: %v", binding.Target, e)
+			continue
+		}
+		_ = appconfig.VisitEach(values, func(k string, v interface{}) error {
+			fullKey := joinKey(binding.Prefix, k)
+			origin, ok := origins[fullKey]
+			if !ok {
+				origin = OriginDefault
+			}
+			bean.Properties[k] = PValueDescriptor{
+				Value:  ep.sanitizer.Sanitize(ctx, fullKey, v),
+				Origin: origin,
+			}
+			return nil
+		})
+
+		name := fmt.Sprintf("%T", binding.Target)
+		name = strings.TrimPrefix(name, "*")
+		if _, ok := desc.Beans[name]; ok {
+			name = fmt.Sprintf("%s[%s]", name, binding.Prefix)
+		}
+		desc.Beans[name] = bean
+	}
+	return &desc, nil
+}
+
+// resolveOrigins returns map of normalized flat key to provider's name.
+// Providers are visited in the same order as they are merged (lowest precedence first), so the last one wins.
+// Note: appconfig.Config.Providers returns providers in highest precedence first order
+func (ep *ConfigPropsEndpoint) resolveOrigins() map[string]string {
+	origins := map[string]string{}
+	providers := ep.appConfig.Providers()
+	for i := len(providers) - 1; i >= 0; i-- {
+		provider := providers[i]
+		if !provider.IsLoaded() || provider.GetSettings() == nil {
+			continue
+		}
+		settings, e := appconfig.ProcessKeyFormat(provider.GetSettings(), appconfig.NormalizeKey)
+		if e != nil {
+			continue
+		}
+		_ = appconfig.VisitEach(settings, func(k string, _ interface{}) error {
+			origins[k] = provider.Name()
+			return nil
+		})
+	}
+	return origins
+}
+
+func toMap(target interface{}) (map[string]interface{}, error) {
+	data, e := json.Marshal(target)
+	if e != nil {
+		return nil, e
+	}
+	var values map[string]interface{}
+	if e := json.Unmarshal(data, &values); e != nil {
+		return nil, e
+	}
+	return values, nil
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" || prefix == "." {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package configprops
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	ConfigPropsPropertiesPrefix = "management.endpoint.configprops"
+)
+
+type ConfigPropsProperties struct {
+	// KeysToSanitize holds list of regular expressions, in addition to env.DefaultKeysToSanitize
+	KeysToSanitize utils.StringSet `json:"keys-to-sanitize"`
+}
+
+func NewConfigPropsProperties() *ConfigPropsProperties {
+	return &ConfigPropsProperties{
+		KeysToSanitize: utils.NewStringSet(),
+	}
+}
+
+func BindConfigPropsProperties(ctx *bootstrap.ApplicationContext) ConfigPropsProperties {
+	props := NewConfigPropsProperties()
+	if err := ctx.Config().Bind(props, ConfigPropsPropertiesPrefix); err != nil {
+		panic(errors.Wrap(err, "failed to bind ConfigPropsProperties"))
+	}
+	return *props
+}
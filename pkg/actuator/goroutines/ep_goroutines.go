@@ -0,0 +1,185 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package goroutines
+
+import (
+	"bytes"
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/actuator"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	ID              = "goroutines"
+	EnableByDefault = false
+)
+
+var (
+	headerRegex  = regexp.MustCompile(`^goroutine (\d+) \[([^,\]]+)(?:, ([^\]]+))?\]:$`)
+	argsRegex    = regexp.MustCompile(`\([^()]*\)$`)
+	offsetRegex  = regexp.MustCompile(` \+0x[0-9a-f]+$`)
+	createdRegex = regexp.MustCompile(` in goroutine \d+$`)
+)
+
+type Input struct {
+	// State filters goroutines by state, e.g. "running", "chan receive", "IO wait"
+	State string `form:"state"`
+}
+
+// DumpDescriptor is the response of goroutine dump, goroutines with identical stack are grouped together
+type DumpDescriptor struct {
+	Total  int               `json:"total"`
+	Groups []GroupDescriptor `json:"groups"`
+}
+
+type GroupDescriptor struct {
+	Count  int            `json:"count"`
+	States map[string]int `json:"states"`
+	IDs    []int          `json:"ids"`
+	Stack  []string       `json:"stack"`
+}
+
+// GoroutinesEndpoint implements actuator.Endpoint, actuator.WebEndpoint
+//
+//goland:noinspection GoNameStartsWithPackageName
+type GoroutinesEndpoint struct {
+	actuator.WebEndpointBase
+}
+
+func newEndpoint(di regDI) *GoroutinesEndpoint {
+	ep := GoroutinesEndpoint{}
+	ep.WebEndpointBase = actuator.MakeWebEndpointBase(func(opt *actuator.EndpointOption) {
+		opt.Id = ID
+		opt.Ops = []actuator.Operation{
+			actuator.NewReadOperation(ep.Read),
+		}
+		opt.Properties = &di.MgtProperties.Endpoints
+		opt.EnabledByDefault = EnableByDefault
+	})
+	return &ep
+}
+
+// Read takes a dump of all goroutines and group them by stack
+func (ep *GoroutinesEndpoint) Read(_ context.Context, input *Input) (*DumpDescriptor, error) {
+	return Dump(input.State), nil
+}
+
+// Dump collects stack traces of all goroutines and group them by identical stack.
+// Function arguments and PC offsets are stripped from stacks, so goroutines running same code path are grouped together.
+// When state is not empty, only goroutines in given state are included.
+// Groups are sorted by count in descending order.
+func Dump(state string) *DumpDescriptor {
+	groups := map[string]*GroupDescriptor{}
+	var total int
+	for _, g := range parseStacks(allStacks()) {
+		if state != "" && !strings.EqualFold(g.state, state) {
+			continue
+		}
+		total++
+		key := strings.Join(g.stack, "\n")
+		group, ok := groups[key]
+		if !ok {
+			group = &GroupDescriptor{
+				States: map[string]int{},
+				Stack:  g.stack,
+			}
+			groups[key] = group
+		}
+		group.Count++
+		group.States[g.state]++
+		group.IDs = append(group.IDs, g.id)
+	}
+
+	dump := DumpDescriptor{
+		Total:  total,
+		Groups: make([]GroupDescriptor, 0, len(groups)),
+	}
+	for _, group := range groups {
+		sort.Ints(group.IDs)
+		dump.Groups = append(dump.Groups, *group)
+	}
+	sort.SliceStable(dump.Groups, func(i, j int) bool {
+		if dump.Groups[i].Count != dump.Groups[j].Count {
+			return dump.Groups[i].Count > dump.Groups[j].Count
+		}
+		return dump.Groups[i].IDs[0] < dump.Groups[j].IDs[0]
+	})
+	return &dump
+}
+
+/*********************
+	Helpers
+ *********************/
+
+type goroutine struct {
+	id    int
+	state string
+	stack []string
+}
+
+func allStacks() []byte {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// parseStacks parses output of runtime.Stack. Each goroutine is in following format:
+//
+//	goroutine 1 [chan receive, 5 minutes]:
+//	main.main(0x1, 0x2)
+//		/path/to/main.go:10 +0x1d
+//	created by main.init in goroutine 1
+//		/path/to/main.go:5 +0x25
+func parseStacks(raw []byte) []goroutine {
+	blocks := bytes.Split(bytes.TrimSpace(raw), []byte("\n\n"))
+	goroutines := make([]goroutine, 0, len(blocks))
+	for _, block := range blocks {
+		lines := strings.Split(string(block), "\n")
+		matches := headerRegex.FindStringSubmatch(strings.TrimSpace(lines[0]))
+		if matches == nil {
+			continue
+		}
+		id, _ := strconv.Atoi(matches[1])
+		g := goroutine{
+			id:    id,
+			state: matches[2],
+			stack: make([]string, 0, len(lines)/2),
+		}
+		// each frame is a function line optionally followed by an indented file line
+		for _, line := range lines[1:] {
+			switch {
+			case strings.HasPrefix(line, "\t") && len(g.stack) != 0:
+				file := offsetRegex.ReplaceAllString(strings.TrimSpace(line), "")
+				g.stack[len(g.stack)-1] = g.stack[len(g.stack)-1] + " " + file
+			default:
+				fn := argsRegex.ReplaceAllString(strings.TrimSpace(line), "")
+				g.stack = append(g.stack, createdRegex.ReplaceAllString(fn, ""))
+			}
+		}
+		goroutines = append(goroutines, g)
+	}
+	return goroutines
+}
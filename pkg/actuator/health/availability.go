@@ -0,0 +1,187 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	GroupLiveness  = "liveness"
+	GroupReadiness = "readiness"
+	GroupStartup   = "startup"
+)
+
+const (
+	IndicatorNameLiveness  = "livenessState"
+	IndicatorNameReadiness = "readinessState"
+	IndicatorNameStartup   = "startupState"
+)
+
+const (
+	LivenessCorrect LivenessState = iota
+	LivenessBroken
+)
+
+// LivenessState tells whether the application is running correctly, or is in a broken state that requires restart.
+type LivenessState int
+
+// fmt.Stringer
+func (s LivenessState) String() string {
+	switch s {
+	case LivenessCorrect:
+		return "CORRECT"
+	default:
+		return "BROKEN"
+	}
+}
+
+const (
+	ReadinessRefusingTraffic ReadinessState = iota
+	ReadinessAcceptingTraffic
+)
+
+// ReadinessState tells whether the application is ready to accept traffic.
+type ReadinessState int
+
+// fmt.Stringer
+func (s ReadinessState) String() string {
+	switch s {
+	case ReadinessAcceptingTraffic:
+		return "ACCEPTING_TRAFFIC"
+	default:
+		return "REFUSING_TRAFFIC"
+	}
+}
+
+// ApplicationAvailability holds availability states of the application, which drive liveness, readiness and startup probes.
+// By default, readiness becomes ReadinessAcceptingTraffic once the application is fully started,
+// and turns back to ReadinessRefusingTraffic when graceful shutdown starts.
+type ApplicationAvailability struct {
+	mtx       sync.RWMutex
+	liveness  LivenessState
+	readiness ReadinessState
+	started   bool
+}
+
+func NewApplicationAvailability() *ApplicationAvailability {
+	return &ApplicationAvailability{
+		liveness:  LivenessCorrect,
+		readiness: ReadinessRefusingTraffic,
+	}
+}
+
+func (a *ApplicationAvailability) LivenessState() LivenessState {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.liveness
+}
+
+func (a *ApplicationAvailability) SetLivenessState(ctx context.Context, state LivenessState) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if a.liveness != state {
+		logger.WithContext(ctx).Infof("Liveness state changed: %v -> %v", a.liveness, state)
+	}
+	a.liveness = state
+}
+
+func (a *ApplicationAvailability) ReadinessState() ReadinessState {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.readiness
+}
+
+func (a *ApplicationAvailability) SetReadinessState(ctx context.Context, state ReadinessState) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if a.readiness != state {
+		logger.WithContext(ctx).Infof("Readiness state changed: %v -> %v", a.readiness, state)
+	}
+	a.readiness = state
+}
+
+// Started returns true if all application lifecycle start hooks are finished
+func (a *ApplicationAvailability) Started() bool {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.started
+}
+
+func (a *ApplicationAvailability) markStarted() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.started = true
+}
+
+/*******************************
+	Availability Indicators
+********************************/
+
+// AvailabilityIndicators returns indicators reporting liveness, readiness and startup state of given ApplicationAvailability
+func AvailabilityIndicators(a *ApplicationAvailability) []Indicator {
+	return []Indicator{
+		availabilityIndicator{
+			name: IndicatorNameLiveness,
+			stateFunc: func() (Status, string) {
+				state := a.LivenessState()
+				if state == LivenessCorrect {
+					return StatusUp, state.String()
+				}
+				return StatusDown, state.String()
+			},
+		},
+		availabilityIndicator{
+			name: IndicatorNameReadiness,
+			stateFunc: func() (Status, string) {
+				state := a.ReadinessState()
+				if state == ReadinessAcceptingTraffic {
+					return StatusUp, state.String()
+				}
+				return StatusOutOfService, state.String()
+			},
+		},
+		availabilityIndicator{
+			name: IndicatorNameStartup,
+			stateFunc: func() (Status, string) {
+				if a.Started() {
+					return StatusUp, "STARTED"
+				}
+				return StatusDown, "STARTING"
+			},
+		},
+	}
+}
+
+type availabilityIndicator struct {
+	name      string
+	stateFunc func() (Status, string)
+}
+
+func (i availabilityIndicator) Name() string {
+	return i.name
+}
+
+func (i availabilityIndicator) Health(_ context.Context, opts Options) Health {
+	status, state := i.stateFunc()
+	var details map[string]interface{}
+	if opts.ShowDetails {
+		details = map[string]interface{}{"state": state}
+	}
+	return NewDetailedHealth(status, i.name, details)
+}
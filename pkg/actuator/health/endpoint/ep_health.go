@@ -19,8 +19,11 @@ package healthep
 import (
     "context"
     "encoding/json"
+    "fmt"
     "github.com/cisco-open/go-lanai/pkg/actuator"
     "github.com/cisco-open/go-lanai/pkg/actuator/health"
+    "github.com/cisco-open/go-lanai/pkg/web"
+    "net/http"
 )
 
 const (
@@ -30,6 +33,10 @@ const (
 
 type Input struct{}
 
+type GroupInput struct {
+	Group string `uri:"group" binding:"required"`
+}
+
 type Output struct {
 	health.Health
 	sc int
@@ -63,6 +70,7 @@ type EndpointOption struct {
 	Properties        health.HealthProperties
 	DetailsControl    health.DetailsDisclosureControl
 	ComponentsControl health.ComponentsDisclosureControl
+	Groups            map[string]*health.GroupIndicator
 }
 
 // HealthEndpoint implements actuator.Endpoint, actuator.WebEndpoint
@@ -72,6 +80,15 @@ type HealthEndpoint struct {
 	scMapper          health.StatusCodeMapper
 	detailsControl    health.DetailsDisclosureControl
 	componentsControl health.ComponentsDisclosureControl
+	groups            map[string]*healthGroup
+	pathSuffix        map[actuator.Operation]string
+}
+
+// healthGroup holds a health group and its disclosure control
+type healthGroup struct {
+	indicator         health.Indicator
+	detailsControl    health.DetailsDisclosureControl
+	componentsControl health.ComponentsDisclosureControl
 }
 
 func newEndpoint(opts ...EndpointOptions) (*HealthEndpoint, error) {
@@ -98,14 +115,40 @@ func newEndpoint(opts ...EndpointOptions) (*HealthEndpoint, error) {
 		scMapper:          opt.StatusCodeMapper,
 		detailsControl:    disclosureCtrl,
 		componentsControl: disclosureCtrl,
+		groups:            map[string]*healthGroup{},
+	}
+
+	for name, group := range opt.Groups {
+		groupProps := opt.Properties
+		if group.Properties.ShowDetails != nil {
+			groupProps.ShowDetails = *group.Properties.ShowDetails
+		}
+		if group.Properties.ShowComponents != nil {
+			groupProps.ShowComponents = group.Properties.ShowComponents
+		}
+		groupCtrl, e := newDefaultDisclosureControl(&groupProps, opt.DetailsControl, opt.ComponentsControl)
+		if e != nil {
+			return nil, fmt.Errorf("invalid health group [%s]: %v", name, e)
+		}
+		ep.groups[name] = &healthGroup{
+			indicator:         group,
+			detailsControl:    groupCtrl,
+			componentsControl: groupCtrl,
+		}
 	}
 
+	ep.pathSuffix = map[actuator.Operation]string{
+		actuator.NewReadOperation(ep.Read):      "",
+		actuator.NewReadOperation(ep.ReadGroup): "/:group",
+	}
+	ops := make([]actuator.Operation, 0, len(ep.pathSuffix))
+	for k := range ep.pathSuffix {
+		ops = append(ops, k)
+	}
 	properties := opt.MgtProperties
 	ep.WebEndpointBase = actuator.MakeWebEndpointBase(func(opt *actuator.EndpointOption) {
 		opt.Id = ID
-		opt.Ops = []actuator.Operation{
-			actuator.NewReadOperation(ep.Read),
-		}
+		opt.Ops = ops
 		opt.Properties = &properties.Endpoints
 		opt.EnabledByDefault = EnableByDefault
 	})
@@ -113,13 +156,43 @@ func newEndpoint(opts ...EndpointOptions) (*HealthEndpoint, error) {
 	return &ep, nil
 }
 
+// MappingPath override actuator.WebEndpointBase
+func (ep *HealthEndpoint) MappingPath(op actuator.Operation, props *actuator.WebEndpointsProperties) string {
+	path := ep.WebEndpointBase.MappingPath(op, props)
+	return path + ep.pathSuffix[op]
+}
+
+// Mappings implements actuator.WebEndpoint
+func (ep *HealthEndpoint) Mappings(op actuator.Operation, group string) ([]web.Mapping, error) {
+	builder, e := ep.RestMappingBuilder(op, group, ep.MappingPath, ep.MappingName)
+	if e != nil {
+		return nil, e
+	}
+	return []web.Mapping{builder.Build()}, nil
+}
+
 // Read never returns error
 func (ep *HealthEndpoint) Read(ctx context.Context, _ *Input) (*Output, error) {
+	return ep.read(ctx, ep.contributor, ep.detailsControl, ep.componentsControl)
+}
+
+// ReadGroup returns health of given group, e.g. "liveness" or "readiness".
+// Returns 404 if the group is not configured
+func (ep *HealthEndpoint) ReadGroup(ctx context.Context, input *GroupInput) (*Output, error) {
+	group, ok := ep.groups[input.Group]
+	if !ok {
+		return nil, web.NewHttpError(http.StatusNotFound, fmt.Errorf("health group [%s] not found", input.Group))
+	}
+	return ep.read(ctx, group.indicator, group.detailsControl, group.componentsControl)
+}
+
+func (ep *HealthEndpoint) read(ctx context.Context, indicator health.Indicator,
+	detailsCtrl health.DetailsDisclosureControl, compsCtrl health.ComponentsDisclosureControl) (*Output, error) {
 	opts := health.Options{
-		ShowDetails:    ep.detailsControl.ShouldShowDetails(ctx),
-		ShowComponents: ep.componentsControl.ShouldShowComponents(ctx),
+		ShowDetails:    detailsCtrl.ShouldShowDetails(ctx),
+		ShowComponents: compsCtrl.ShouldShowComponents(ctx),
 	}
-	h := ep.contributor.Health(ctx, opts)
+	h := indicator.Health(ctx, opts)
 	switch f := ep.WebEndpointBase.NegotiateFormat(ctx); f {
 	case actuator.ContentTypeSpringBootV2:
 		h = ep.toSpringBootV2(h)
@@ -64,6 +64,7 @@ func register(di regDI) {
 		opt.Properties = di.Properties
 		opt.DetailsControl = healthReg.DetailsDisclosure
 		opt.ComponentsControl = healthReg.ComponentsDisclosure
+		opt.Groups = healthReg.Groups
 	})
 	if e != nil {
 		panic(e)
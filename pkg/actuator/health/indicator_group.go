@@ -0,0 +1,83 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+)
+
+/*******************************
+	GroupIndicator
+********************************/
+
+// GroupIndicator implements Indicator. It aggregates a subset of indicators selected by names.
+// Members are resolved on every health check, so indicators registered after the group is created are also considered.
+type GroupIndicator struct {
+	name       string
+	system     *CompositeIndicator
+	extras     []Indicator
+	include    utils.StringSet
+	exclude    utils.StringSet
+	Properties GroupProperties
+}
+
+// NewGroupIndicator creates a GroupIndicator that selects members from given system indicator and extra indicators.
+// Extra indicators, such as AvailabilityIndicators, are only visible to health groups.
+func NewGroupIndicator(name string, system *CompositeIndicator, props GroupProperties, extras ...Indicator) *GroupIndicator {
+	return &GroupIndicator{
+		name:       name,
+		system:     system,
+		extras:     extras,
+		include:    utils.NewStringSet(props.Include...),
+		exclude:    utils.NewStringSet(props.Exclude...),
+		Properties: props,
+	}
+}
+
+func (g *GroupIndicator) Name() string {
+	return g.name
+}
+
+// Members returns indicators currently included in this group
+func (g *GroupIndicator) Members() []Indicator {
+	members := make([]Indicator, 0, len(g.system.delegates)+len(g.extras))
+	for _, candidates := range [][]Indicator{g.system.delegates, g.extras} {
+		for _, indicator := range candidates {
+			if g.isMember(indicator.Name()) {
+				members = append(members, indicator)
+			}
+		}
+	}
+	return members
+}
+
+func (g *GroupIndicator) Health(ctx context.Context, options Options) Health {
+	composite := CompositeIndicator{
+		name:       g.name,
+		delegates:  g.Members(),
+		aggregator: g.system.aggregator,
+	}
+	return composite.Health(ctx, options)
+}
+
+func (g *GroupIndicator) isMember(name string) bool {
+	if g.exclude.Has("*") || g.exclude.Has(name) {
+		return false
+	}
+	return len(g.include) == 0 || g.include.Has("*") || g.include.Has(name)
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*******************************
+	managedIndicator
+********************************/
+
+// managedIndicator decorates an Indicator with timeout and result caching
+type managedIndicator struct {
+	Indicator
+	timeout time.Duration
+	ttl     time.Duration
+	mtx     sync.Mutex
+	cache   map[Options]cachedHealth
+}
+
+type cachedHealth struct {
+	health  Health
+	expires time.Time
+}
+
+func newManagedIndicator(delegate Indicator, timeout, ttl time.Duration) Indicator {
+	if timeout <= 0 && ttl <= 0 {
+		return delegate
+	}
+	return &managedIndicator{
+		Indicator: delegate,
+		timeout:   timeout,
+		ttl:       ttl,
+		cache:     map[Options]cachedHealth{},
+	}
+}
+
+func (i *managedIndicator) Health(ctx context.Context, options Options) Health {
+	if i.ttl <= 0 {
+		return i.healthWithTimeout(ctx, options)
+	}
+
+	now := time.Now()
+	i.mtx.Lock()
+	cached, ok := i.cache[options]
+	i.mtx.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.health
+	}
+
+	h := i.healthWithTimeout(ctx, options)
+	i.mtx.Lock()
+	i.cache[options] = cachedHealth{health: h, expires: now.Add(i.ttl)}
+	i.mtx.Unlock()
+	return h
+}
+
+func (i *managedIndicator) healthWithTimeout(ctx context.Context, options Options) Health {
+	if i.timeout <= 0 {
+		return i.Indicator.Health(ctx, options)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, i.timeout)
+	defer cancel()
+	result := make(chan Health, 1)
+	go func() {
+		result <- i.Indicator.Health(timeoutCtx, options)
+	}()
+
+	select {
+	case h := <-result:
+		return h
+	case <-timeoutCtx.Done():
+		logger.WithContext(ctx).Warnf("health indicator [%s] did not respond within %v", i.Name(), i.timeout)
+		return NewDetailedHealth(StatusDown, fmt.Sprintf("health check timed out after %v", i.timeout), nil)
+	}
+}
@@ -17,20 +17,37 @@
 package health
 
 import (
+	"context"
 	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/log"
 	"go.uber.org/fx"
+	"time"
 )
 
+var logger = log.New("ACTR.Health")
+
 var Module = &bootstrap.Module{
 	Name:       "actuator-health",
 	Precedence: bootstrap.ActuatorPrecedence,
 	Options: []fx.Option{
 		fx.Provide(
 			BindHealthProperties,
+			NewApplicationAvailability,
 			NewSystemHealthRegistrar,
 			provideInterfaces,
 		),
 	},
+	Modules: []*bootstrap.Module{availabilityModule},
+}
+
+// availabilityModule updates ApplicationAvailability after all other lifecycle hooks are started,
+// and before any of them is stopped.
+var availabilityModule = &bootstrap.Module{
+	Name:       "actuator-health-availability",
+	Precedence: bootstrap.StartupSummaryPrecedence,
+	Options: []fx.Option{
+		fx.Invoke(manageAvailability),
+	},
 }
 
 func Use() {
@@ -41,3 +58,23 @@ func provideInterfaces(reg *SystemHealthRegistrar) (Registrar, Indicator) {
 	return reg, reg.Indicator
 }
 
+func manageAvailability(lc fx.Lifecycle, availability *ApplicationAvailability, props HealthProperties) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			availability.markStarted()
+			availability.SetReadinessState(ctx, ReadinessAcceptingTraffic)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			availability.SetReadinessState(ctx, ReadinessRefusingTraffic)
+			if delay := time.Duration(props.Probes.DrainDelay); delay > 0 {
+				logger.WithContext(ctx).Infof("Waiting %v for traffic draining...", delay)
+				select {
+				case <-time.After(delay):
+				case <-ctx.Done():
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/cisco-open/go-lanai/pkg/utils"
 	"github.com/pkg/errors"
 	"strings"
+	"time"
 )
 
 const (
@@ -39,6 +40,50 @@ type HealthProperties struct {
 	// Permisions used to determine whether or not a user is authorized to be shown details.
 	// When empty, all authenticated users are authorized.
 	Permissions utils.CommaSeparatedSlice `json:"permissions"`
+
+	// Health groups keyed by group name. Each group is exposed as sub-path of the health endpoint, e.g. "/health/readiness"
+	Groups map[string]GroupProperties `json:"group"`
+
+	// Liveness, readiness and startup probes driven by application lifecycle.
+	Probes ProbesProperties `json:"probes"`
+
+	// Default timeout of each health indicator. Indicators not responding in time are reported as DOWN.
+	// Zero means no timeout.
+	Timeout utils.Duration `json:"timeout"`
+
+	// Default time-to-live of cached indicator results. Zero disables caching.
+	CacheTTL utils.Duration `json:"cache-ttl"`
+
+	// Per-indicator overrides of Timeout and CacheTTL, keyed by indicator name.
+	Indicators map[string]IndicatorProperties `json:"indicator"`
+}
+
+type GroupProperties struct {
+	// Names of indicators included in this group, or '*' for all. When empty, all indicators are included.
+	Include utils.CommaSeparatedSlice `json:"include"`
+
+	// Names of indicators excluded from this group, or '*' for all.
+	Exclude utils.CommaSeparatedSlice `json:"exclude"`
+
+	// When to show components of this group. If not specified, the endpoint's setting will be used.
+	ShowComponents *ShowMode `json:"show-components"`
+
+	// When to show full health details of this group. If not specified, the endpoint's setting will be used.
+	ShowDetails *ShowMode `json:"show-details"`
+}
+
+type ProbesProperties struct {
+	// Whether to add "liveness", "readiness" and "startup" groups, unless they are explicitly configured.
+	Enabled bool `json:"enabled"`
+
+	// How long the readiness probe reports OUT_OF_SERVICE before the shutdown continues,
+	// which gives load balancers time to stop routing traffic to this instance.
+	DrainDelay utils.Duration `json:"drain-delay"`
+}
+
+type IndicatorProperties struct {
+	Timeout  *utils.Duration `json:"timeout"`
+	CacheTTL *utils.Duration `json:"cache-ttl"`
 }
 
 type StatusOrders []Status
@@ -76,6 +121,12 @@ func NewHealthProperties() *HealthProperties {
 			ScMapping: map[Status]int{},
 		},
 		Permissions: []string{},
+		Groups:      map[string]GroupProperties{},
+		Probes: ProbesProperties{
+			Enabled: true,
+		},
+		Timeout:    utils.Duration(10 * time.Second),
+		Indicators: map[string]IndicatorProperties{},
 	}
 }
 
@@ -85,5 +136,46 @@ func BindHealthProperties(ctx *bootstrap.ApplicationContext) HealthProperties {
 	if err := ctx.Config().Bind(props, HealthPropertiesPrefix); err != nil {
 		panic(errors.Wrap(err, "failed to bind HealthProperties"))
 	}
+	if props.Probes.Enabled {
+		addProbeGroups(props)
+	}
 	return *props
 }
+
+func addProbeGroups(props *HealthProperties) {
+	probes := map[string]string{
+		GroupLiveness:  IndicatorNameLiveness,
+		GroupReadiness: IndicatorNameReadiness,
+		GroupStartup:   IndicatorNameStartup,
+	}
+	for group, indicator := range probes {
+		if _, ok := props.Groups[group]; !ok {
+			props.Groups[group] = GroupProperties{Include: []string{indicator}}
+		}
+	}
+}
+
+// IndicatorTimeout returns effective timeout of the indicator with given name
+func (p HealthProperties) IndicatorTimeout(name string) time.Duration {
+	if v, ok := p.indicatorProperties(name); ok && v.Timeout != nil {
+		return time.Duration(*v.Timeout)
+	}
+	return time.Duration(p.Timeout)
+}
+
+// IndicatorCacheTTL returns effective cache time-to-live of the indicator with given name
+func (p HealthProperties) IndicatorCacheTTL(name string) time.Duration {
+	if v, ok := p.indicatorProperties(name); ok && v.CacheTTL != nil {
+		return time.Duration(*v.CacheTTL)
+	}
+	return time.Duration(p.CacheTTL)
+}
+
+// indicatorProperties lookup by name as-is or normalized, because property keys are normalized from camelCase
+func (p HealthProperties) indicatorProperties(name string) (IndicatorProperties, bool) {
+	if v, ok := p.Indicators[name]; ok {
+		return v, true
+	}
+	v, ok := p.Indicators[utils.CamelToSnakeCase(name)]
+	return v, ok
+}
@@ -24,17 +24,23 @@ import (
 // SystemHealthRegistrar implements Registrar
 type SystemHealthRegistrar struct {
 	Indicator            *CompositeIndicator
+	// Groups are health groups keyed by group name
+	Groups               map[string]*GroupIndicator
 	DetailsDisclosure    DetailsDisclosureControl
 	ComponentsDisclosure ComponentsDisclosureControl
+	properties           HealthProperties
 }
 
 type regDI struct {
 	fx.In
 	Properties    HealthProperties
+	Availability  *ApplicationAvailability
 }
 
 func NewSystemHealthRegistrar(di regDI) *SystemHealthRegistrar {
-	return &SystemHealthRegistrar{
+	reg := &SystemHealthRegistrar{
+		Groups:     map[string]*GroupIndicator{},
+		properties: di.Properties,
 		Indicator: &CompositeIndicator{
 			name: "system",
 			delegates: []Indicator{
@@ -47,6 +53,13 @@ func NewSystemHealthRegistrar(di regDI) *SystemHealthRegistrar {
 			}),
 		},
 	}
+
+	// availability indicators are only visible to health groups
+	availability := AvailabilityIndicators(di.Availability)
+	for name, props := range di.Properties.Groups {
+		reg.Groups[name] = NewGroupIndicator(name, reg.Indicator, props, availability...)
+	}
+	return reg
 }
 
 // Register configure SystemHealthRegistrar
@@ -76,7 +89,7 @@ func (i *SystemHealthRegistrar) register(item interface{}) error {
 	case []interface{}:
 		return i.Register(v...)
 	case Indicator:
-		i.Indicator.Add(v)
+		i.Indicator.Add(newManagedIndicator(v, i.properties.IndicatorTimeout(v.Name()), i.properties.IndicatorCacheTTL(v.Name())))
 	case StatusAggregator:
 		i.Indicator.aggregator = v
 	case DisclosureControl:
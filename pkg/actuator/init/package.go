@@ -21,10 +21,14 @@ import (
     "github.com/cisco-open/go-lanai/pkg/actuator"
     "github.com/cisco-open/go-lanai/pkg/actuator/alive"
     "github.com/cisco-open/go-lanai/pkg/actuator/apilist"
+    "github.com/cisco-open/go-lanai/pkg/actuator/auditevents"
+    "github.com/cisco-open/go-lanai/pkg/actuator/configprops"
     "github.com/cisco-open/go-lanai/pkg/actuator/env"
+    "github.com/cisco-open/go-lanai/pkg/actuator/goroutines"
     health "github.com/cisco-open/go-lanai/pkg/actuator/health/endpoint"
     "github.com/cisco-open/go-lanai/pkg/actuator/info"
     "github.com/cisco-open/go-lanai/pkg/actuator/loggers"
+    "github.com/cisco-open/go-lanai/pkg/actuator/memstats"
     appconfig "github.com/cisco-open/go-lanai/pkg/appconfig/init"
     "github.com/cisco-open/go-lanai/pkg/bootstrap"
     "go.uber.org/fx"
@@ -50,6 +54,10 @@ func Use() {
 	alive.Register()
 	apilist.Register()
 	loggers.Register()
+	configprops.Register()
+	goroutines.Register()
+	memstats.Register()
+	auditevents.Register()
 }
 
 /**************************
@@ -0,0 +1,250 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package loggers
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/cisco-open/go-lanai/pkg/utils/cryptoutils"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	FxGroup = "actuator-loggers"
+	// keyRoot is the key of root level in InstanceLevels, same as log.Levels
+	keyRoot = "default"
+	// staleHeartbeats number of missed heartbeats after which an instance is considered gone
+	staleHeartbeats = 3
+)
+
+// LevelChange is broadcast to all instances of the service when logging level is changed via the endpoint
+type LevelChange struct {
+	Prefix string `json:"prefix"`
+	// Level to set. nil means unset
+	Level *log.LoggingLevel `json:"level,omitempty"`
+	// TTL after which the previously configured level is restored. Zero means permanent
+	TTL utils.Duration `json:"ttl,omitempty"`
+	// Source is the instance ID where the change originated
+	Source string `json:"source"`
+}
+
+// InstanceLevels is the logging levels configuration reported by each instance.
+// Levels are keyed by lower case of logger name, same as log.Levels. Root level is keyed by "default"
+type InstanceLevels struct {
+	InstanceID string                      `json:"instanceId"`
+	Levels     map[string]log.LoggingLevel `json:"levels"`
+	Timestamp  time.Time                   `json:"timestamp"`
+}
+
+// EffectiveLevel resolves the effective level of given logger name using the configured level with longest prefix
+func (l InstanceLevels) EffectiveLevel(name string) *log.LoggingLevel {
+	key := strings.ToLower(name)
+	for prefix := key; len(prefix) > 0; {
+		if lvl, ok := l.Levels[prefix]; ok {
+			return &lvl
+		}
+		i := strings.LastIndex(prefix, ".")
+		if i < 0 {
+			break
+		}
+		prefix = prefix[:i]
+	}
+	if lvl, ok := l.Levels[keyRoot]; ok {
+		return &lvl
+	}
+	return nil
+}
+
+// ConfiguredLevel returns the level configured for exactly given logger name, nil if not configured
+func (l InstanceLevels) ConfiguredLevel(name string) *log.LoggingLevel {
+	if lvl, ok := l.Levels[strings.ToLower(name)]; ok {
+		return &lvl
+	}
+	return nil
+}
+
+// Broadcaster delivers LevelChange to all instances of the service and keeps track of each instance's levels.
+type Broadcaster interface {
+	// Broadcast sends the LevelChange to all instances, including the current one.
+	Broadcast(ctx context.Context, change LevelChange) error
+	// Listen registers handler for LevelChange sent by any instance. The function doesn't block.
+	// Listening stops when given context is cancelled.
+	Listen(ctx context.Context, handler func(ctx context.Context, change LevelChange)) error
+	// Report records logging levels of an instance
+	Report(ctx context.Context, levels InstanceLevels) error
+	// Remove deletes the record of given instance
+	Remove(ctx context.Context, instanceID string) error
+	// Instances returns the most recent reports of all instances
+	Instances(ctx context.Context) ([]InstanceLevels, error)
+}
+
+// BroadcasterFactory creates Broadcaster of particular type. Implementations are provided with FxGroup
+type BroadcasterFactory interface {
+	Type() string
+	NewBroadcaster(props ClusterProperties) (Broadcaster, error)
+}
+
+// Cluster applies LevelChange received from the Broadcaster and periodically reports levels of current instance.
+type Cluster struct {
+	instanceID  string
+	props       ClusterProperties
+	broadcaster Broadcaster
+	cancel      context.CancelFunc
+	done        <-chan struct{}
+}
+
+func NewCluster(broadcaster Broadcaster, props ClusterProperties) *Cluster {
+	return &Cluster{
+		instanceID:  newInstanceID(),
+		props:       props,
+		broadcaster: broadcaster,
+	}
+}
+
+// InstanceID of the current instance
+func (c *Cluster) InstanceID() string {
+	return c.instanceID
+}
+
+func (c *Cluster) Start(_ context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = ctx.Done()
+	if e := c.broadcaster.Listen(ctx, c.apply); e != nil {
+		cancel()
+		return fmt.Errorf("unable to listen logging level changes: %v", e)
+	}
+	c.report(ctx)
+	go c.heartbeat(ctx)
+	return nil
+}
+
+func (c *Cluster) Stop(ctx context.Context) error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	return c.broadcaster.Remove(ctx, c.instanceID)
+}
+
+// Change applies the LevelChange on current instance and broadcasts it to other instances
+func (c *Cluster) Change(ctx context.Context, change LevelChange) error {
+	change.Source = c.instanceID
+	if c.props.MaxTTL > 0 && (change.TTL <= 0 || change.TTL > c.props.MaxTTL) {
+		change.TTL = c.props.MaxTTL
+	}
+	c.applyAndReport(ctx, change)
+	return c.broadcaster.Broadcast(ctx, change)
+}
+
+// Instances returns levels reported by all live instances
+func (c *Cluster) Instances(ctx context.Context) ([]InstanceLevels, error) {
+	all, e := c.broadcaster.Instances(ctx)
+	if e != nil {
+		return nil, e
+	}
+	threshold := time.Now().Add(-staleHeartbeats * time.Duration(c.props.Heartbeat))
+	ret := make([]InstanceLevels, 0, len(all))
+	for _, inst := range all {
+		if c.props.Heartbeat > 0 && inst.Timestamp.Before(threshold) {
+			continue
+		}
+		ret = append(ret, inst)
+	}
+	return ret, nil
+}
+
+func (c *Cluster) apply(ctx context.Context, change LevelChange) {
+	if change.Source == c.instanceID {
+		return
+	}
+	logger.WithContext(ctx).Infof(`Applying logging level change from [%s]: %s=%v (TTL=%v)`,
+		change.Source, change.Prefix, change.Level, time.Duration(change.TTL))
+	c.applyAndReport(ctx, change)
+}
+
+// applyAndReport applies the change and reports levels of current instance.
+// When TTL is set, levels are reported again after the level is restored, so other instances don't see stale levels
+func (c *Cluster) applyAndReport(ctx context.Context, change LevelChange) {
+	applyLevelChange(change)
+	c.report(ctx)
+	if change.TTL <= 0 {
+		return
+	}
+	go func() {
+		// small delay to make sure the level is restored before reporting
+		timer := time.NewTimer(time.Duration(change.TTL) + time.Second)
+		defer timer.Stop()
+		select {
+		case <-c.done:
+		case <-timer.C:
+			c.report(context.Background())
+		}
+	}()
+}
+
+func (c *Cluster) report(ctx context.Context) {
+	if e := c.broadcaster.Report(ctx, CurrentLevels(c.instanceID)); e != nil {
+		logger.WithContext(ctx).Warnf(`Unable to report logging levels: %v`, e)
+	}
+}
+
+func (c *Cluster) heartbeat(ctx context.Context) {
+	if c.props.Heartbeat <= 0 {
+		return
+	}
+	ticker := time.NewTicker(time.Duration(c.props.Heartbeat))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.report(ctx)
+		}
+	}
+}
+
+// CurrentLevels collects configured levels of current instance
+func CurrentLevels(instanceID string) InstanceLevels {
+	ret := InstanceLevels{
+		InstanceID: instanceID,
+		Levels:     map[string]log.LoggingLevel{},
+		Timestamp:  time.Now(),
+	}
+	for k, v := range log.Levels("") {
+		if v.ConfiguredLevel != nil {
+			ret.Levels[k] = *v.ConfiguredLevel
+		}
+	}
+	return ret
+}
+
+func applyLevelChange(change LevelChange) {
+	log.SetLevelWithTTL(change.Prefix, change.Level, time.Duration(change.TTL))
+}
+
+func newInstanceID() string {
+	host, e := os.Hostname()
+	if e != nil || len(host) == 0 {
+		host = "instance"
+	}
+	return fmt.Sprintf("%s-%x", host, cryptoutils.RandomBytes(4))
+}
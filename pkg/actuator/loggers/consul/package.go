@@ -0,0 +1,185 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package consulloggers provides loggers.Broadcaster backed by Consul KV,
+// enabled with "management.endpoint.loggers.cluster.type: consul"
+package consulloggers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/actuator/loggers"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/consul"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/hashicorp/consul/api"
+	"go.uber.org/fx"
+	"strings"
+	"time"
+)
+
+var logger = log.New("ACTR.LoggerLevel")
+
+const (
+	BroadcasterType  = "consul"
+	DefaultKeyPrefix = "loggers"
+	// retryInterval wait time before retrying failed blocking query
+	retryInterval = 5 * time.Second
+)
+
+var Module = &bootstrap.Module{
+	Name:       "actuator-loggers-consul",
+	Precedence: bootstrap.ActuatorPrecedence,
+	Options: []fx.Option{
+		fx.Provide(FxProvider()),
+	},
+}
+
+func Use() {
+	bootstrap.Register(Module)
+}
+
+type factoryDI struct {
+	fx.In
+	AppCtx     *bootstrap.ApplicationContext
+	Connection *consul.Connection
+}
+
+func FxProvider() fx.Annotated {
+	return fx.Annotated{
+		Group: loggers.FxGroup,
+		Target: func(di factoryDI) loggers.BroadcasterFactory {
+			return factory{appName: di.AppCtx.Name(), conn: di.Connection}
+		},
+	}
+}
+
+type factory struct {
+	appName string
+	conn    *consul.Connection
+}
+
+func (f factory) Type() string {
+	return BroadcasterType
+}
+
+func (f factory) NewBroadcaster(props loggers.ClusterProperties) (loggers.Broadcaster, error) {
+	prefix := props.KeyPrefix
+	if len(prefix) == 0 {
+		prefix = DefaultKeyPrefix
+	}
+	return NewConsulBroadcaster(f.conn, fmt.Sprintf("%s/%s", strings.TrimSuffix(prefix, "/"), f.appName)), nil
+}
+
+// ConsulBroadcaster implements loggers.Broadcaster.
+// Latest level change of each logger prefix is stored at "<prefix>/changes/<logger prefix>" and watched with blocking queries.
+// Instance levels are stored at "<prefix>/instances/<instance ID>"
+type ConsulBroadcaster struct {
+	conn        *consul.Connection
+	changesPath string
+	instsPath   string
+}
+
+func NewConsulBroadcaster(conn *consul.Connection, keyPrefix string) *ConsulBroadcaster {
+	return &ConsulBroadcaster{
+		conn:        conn,
+		changesPath: keyPrefix + "/changes",
+		instsPath:   keyPrefix + "/instances",
+	}
+}
+
+func (b *ConsulBroadcaster) Broadcast(ctx context.Context, change loggers.LevelChange) error {
+	data, e := json.Marshal(change)
+	if e != nil {
+		return e
+	}
+	return b.conn.SetKeyValue(ctx, b.changesPath+"/"+strings.ToLower(change.Prefix), data)
+}
+
+func (b *ConsulBroadcaster) Listen(ctx context.Context, handler func(ctx context.Context, change loggers.LevelChange)) error {
+	// changes made before this instance started are not applied
+	_, meta, e := b.conn.Client().KV().List(b.changesPath, (&api.QueryOptions{}).WithContext(ctx))
+	if e != nil {
+		return e
+	}
+	go b.watch(ctx, meta.LastIndex, handler)
+	return nil
+}
+
+func (b *ConsulBroadcaster) watch(ctx context.Context, lastIndex uint64, handler func(ctx context.Context, change loggers.LevelChange)) {
+	for ctx.Err() == nil {
+		opts := &api.QueryOptions{WaitIndex: lastIndex}
+		pairs, meta, e := b.conn.Client().KV().List(b.changesPath, opts.WithContext(ctx))
+		if e != nil {
+			if ctx.Err() == nil {
+				logger.WithContext(ctx).Warnf(`Unable to watch logging level changes: %v`, e)
+				select {
+				case <-ctx.Done():
+				case <-time.After(retryInterval):
+				}
+			}
+			continue
+		}
+		for _, pair := range pairs {
+			if pair.ModifyIndex <= lastIndex {
+				continue
+			}
+			var change loggers.LevelChange
+			if e := json.Unmarshal(pair.Value, &change); e != nil {
+				logger.WithContext(ctx).Warnf(`Invalid logging level change: %v`, e)
+				continue
+			}
+			handler(ctx, change)
+		}
+		// index could go backwards, e.g. after consul snapshot restore
+		if meta.LastIndex < lastIndex {
+			lastIndex = 0
+		} else {
+			lastIndex = meta.LastIndex
+		}
+	}
+}
+
+func (b *ConsulBroadcaster) Report(ctx context.Context, levels loggers.InstanceLevels) error {
+	data, e := json.Marshal(levels)
+	if e != nil {
+		return e
+	}
+	return b.conn.SetKeyValue(ctx, b.instsPath+"/"+levels.InstanceID, data)
+}
+
+func (b *ConsulBroadcaster) Remove(ctx context.Context, instanceID string) error {
+	_, e := b.conn.Client().KV().Delete(b.instsPath+"/"+instanceID, (&api.WriteOptions{}).WithContext(ctx))
+	return e
+}
+
+func (b *ConsulBroadcaster) Instances(ctx context.Context) ([]loggers.InstanceLevels, error) {
+	pairs, _, e := b.conn.Client().KV().List(b.instsPath, (&api.QueryOptions{}).WithContext(ctx))
+	if e != nil {
+		return nil, e
+	}
+	ret := make([]loggers.InstanceLevels, 0, len(pairs))
+	for _, pair := range pairs {
+		var inst loggers.InstanceLevels
+		if e := json.Unmarshal(pair.Value, &inst); e != nil {
+			logger.WithContext(ctx).Warnf(`Invalid instance logging levels: %v`, e)
+			continue
+		}
+		ret = append(ret, inst)
+	}
+	return ret, nil
+}
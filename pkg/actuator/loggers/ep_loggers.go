@@ -21,9 +21,11 @@ import (
     "fmt"
     "github.com/cisco-open/go-lanai/pkg/actuator"
     "github.com/cisco-open/go-lanai/pkg/log"
+    "github.com/cisco-open/go-lanai/pkg/utils"
     "github.com/cisco-open/go-lanai/pkg/web"
     "net/http"
     "strings"
+    "time"
 )
 
 const (
@@ -44,6 +46,8 @@ type ReadInput struct {
 type WriteInput struct {
 	Prefix string `uri:"name" binding:"required"`
 	ConfiguredLevel *log.LoggingLevel `json:"configuredLevel"`
+	// TTL optional duration after which the previously configured level is restored, e.g. "10m"
+	TTL utils.Duration `json:"ttl"`
 }
 
 type ReadOutput struct {
@@ -54,6 +58,8 @@ type ReadOutput struct {
 type LoggerLevel struct {
 	EffectiveLevel  *log.LoggingLevel  `json:"effectiveLevel,omitempty"`
 	ConfiguredLevel *log.LoggingLevel `json:"configuredLevel,omitempty"`
+	// Instances levels of each instance keyed by instance ID, only available when cluster is enabled
+	Instances map[string]LoggerLevel `json:"instances,omitempty"`
 }
 
 // LoggersEndpoint implements actuator.Endpoint, actuator.WebEndpoint
@@ -61,10 +67,13 @@ type LoggerLevel struct {
 type LoggersEndpoint struct {
 	actuator.WebEndpointBase
 	pathSuffix map[actuator.Operation]string
+	cluster    *Cluster
 }
 
-func newEndpoint(di regDI) *LoggersEndpoint {
-	ep := LoggersEndpoint{}
+func newEndpoint(di regDI, cluster *Cluster) *LoggersEndpoint {
+	ep := LoggersEndpoint{
+		cluster: cluster,
+	}
 	ep.pathSuffix = map[actuator.Operation]string{
 		actuator.NewReadOperation(ep.ReadAll):    "",
 		actuator.NewReadOperation(ep.ReadAll):    "/",
@@ -119,26 +128,60 @@ func (ep *LoggersEndpoint) ReadAll(_ context.Context, _ *struct{}) (interface{},
 }
 
 // ReadByName find one logger by name
-func (ep *LoggersEndpoint) ReadByName(_ context.Context, in *ReadInput) (interface{}, error) {
+// When cluster is enabled, levels of each instance are included
+func (ep *LoggersEndpoint) ReadByName(ctx context.Context, in *ReadInput) (interface{}, error) {
 	cfgs := log.Levels(in.Name)
 	for k, v := range cfgs {
 		if k == strings.ToLower(in.Name) || v.Name == in.Name {
-			return &LoggerLevel{
+			ret := &LoggerLevel{
 				EffectiveLevel:  v.EffectiveLevel,
 				ConfiguredLevel: v.ConfiguredLevel,
-			}, nil
+			}
+			if e := ep.populateInstances(ctx, v.Name, ret); e != nil {
+				return nil, e
+			}
+			return ret, nil
 		}
 	}
 	return nil, web.NewHttpError(http.StatusNotFound, fmt.Errorf("logger with name %s not found", in.Name))
 }
 
-// Write update logger levels
-func (ep *LoggersEndpoint) Write(_ context.Context, in *WriteInput) (interface{}, error) {
-	log.SetLevel(in.Prefix, in.ConfiguredLevel)
+// Write update logger levels. When cluster is enabled, the change is broadcast to all instances
+func (ep *LoggersEndpoint) Write(ctx context.Context, in *WriteInput) (interface{}, error) {
+	if ep.cluster == nil {
+		log.SetLevelWithTTL(in.Prefix, in.ConfiguredLevel, time.Duration(in.TTL))
+		return nil, nil
+	}
+	change := LevelChange{
+		Prefix: in.Prefix,
+		Level:  in.ConfiguredLevel,
+		TTL:    in.TTL,
+	}
+	if e := ep.cluster.Change(ctx, change); e != nil {
+		return nil, web.NewHttpError(http.StatusInternalServerError, fmt.Errorf("unable to broadcast logging level change: %v", e))
+	}
 	return nil, nil
 }
 
 func (ep *LoggersEndpoint) WriteEncodeResponse(_ context.Context, rw http.ResponseWriter, _ interface{}) error {
 	rw.WriteHeader(http.StatusNoContent)
 	return nil
-}
\ No newline at end of file
+}
+
+func (ep *LoggersEndpoint) populateInstances(ctx context.Context, name string, out *LoggerLevel) error {
+	if ep.cluster == nil {
+		return nil
+	}
+	instances, e := ep.cluster.Instances(ctx)
+	if e != nil {
+		return web.NewHttpError(http.StatusInternalServerError, fmt.Errorf("unable to retrieve logging levels of instances: %v", e))
+	}
+	out.Instances = map[string]LoggerLevel{}
+	for _, inst := range instances {
+		out.Instances[inst.InstanceID] = LoggerLevel{
+			EffectiveLevel:  inst.EffectiveLevel(name),
+			ConfiguredLevel: inst.ConfiguredLevel(name),
+		}
+	}
+	return nil
+}
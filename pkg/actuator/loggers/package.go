@@ -17,17 +17,20 @@
 package loggers
 
 import (
+	"fmt"
 	"github.com/cisco-open/go-lanai/pkg/actuator"
 	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/log"
 	"go.uber.org/fx"
 )
 
-//var logger = log.New("ACTR.LoggerLevel")
+var logger = log.New("ACTR.LoggerLevel")
 
 var Module = &bootstrap.Module{
 	Name:       "actuator-loggers",
 	Precedence: actuator.MinActuatorPrecedence,
 	Options: []fx.Option{
+		fx.Provide(BindLoggersProperties),
 		fx.Invoke(register),
 	},
 }
@@ -38,11 +41,43 @@ func Register() {
 
 type regDI struct {
 	fx.In
+	Lifecycle     fx.Lifecycle
 	Registrar     *actuator.Registrar
 	MgtProperties actuator.ManagementProperties
+	Properties    LoggersProperties
+	Factories     []BroadcasterFactory `group:"actuator-loggers"`
 }
 
-func register(di regDI) {
-	ep := newEndpoint(di)
+func register(di regDI) error {
+	cluster, e := newClusterIfEnabled(di)
+	if e != nil {
+		return e
+	}
+	if cluster != nil {
+		di.Lifecycle.Append(fx.Hook{
+			OnStart: cluster.Start,
+			OnStop:  cluster.Stop,
+		})
+	}
+	ep := newEndpoint(di, cluster)
 	di.Registrar.MustRegister(ep)
+	return nil
+}
+
+func newClusterIfEnabled(di regDI) (*Cluster, error) {
+	props := di.Properties.Cluster
+	if !props.Enabled {
+		return nil, nil
+	}
+	for _, f := range di.Factories {
+		if f == nil || f.Type() != props.Type {
+			continue
+		}
+		broadcaster, e := f.NewBroadcaster(props)
+		if e != nil {
+			return nil, fmt.Errorf("unable to create loggers broadcaster: %v", e)
+		}
+		return NewCluster(broadcaster, props), nil
+	}
+	return nil, fmt.Errorf(`unsupported loggers broadcaster type [%s]`, props.Type)
 }
@@ -0,0 +1,71 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package loggers
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	LoggersPropertiesPrefix = "management.endpoint.loggers"
+)
+
+type LoggersProperties struct {
+	// Cluster settings of broadcasting log level changes to all instances of the same service
+	Cluster ClusterProperties `json:"cluster"`
+}
+
+type ClusterProperties struct {
+	// Enabled whether level changes are broadcast to all instances.
+	// When disabled, changes only apply to the instance receiving the request.
+	Enabled bool `json:"enabled"`
+
+	// Type of the Broadcaster, e.g. "redis" or "consul". The corresponding module need to be included.
+	Type string `json:"type"`
+
+	// KeyPrefix used to build channel names or KV paths. Application name is always appended.
+	// When empty, each Broadcaster uses its own default.
+	KeyPrefix string `json:"key-prefix"`
+
+	// Heartbeat how often each instance reports its logging levels.
+	// Instances not reporting within 3 heartbeats are considered gone.
+	Heartbeat utils.Duration `json:"heartbeat"`
+
+	// MaxTTL upper limit of TTL of level changes. Zero means no limit.
+	// When set, changes without TTL are also restored after MaxTTL.
+	MaxTTL utils.Duration `json:"max-ttl"`
+}
+
+func NewLoggersProperties() *LoggersProperties {
+	return &LoggersProperties{
+		Cluster: ClusterProperties{
+			Type:      "redis",
+			Heartbeat: utils.Duration(30 * time.Second),
+		},
+	}
+}
+
+func BindLoggersProperties(ctx *bootstrap.ApplicationContext) LoggersProperties {
+	props := NewLoggersProperties()
+	if err := ctx.Config().Bind(props, LoggersPropertiesPrefix); err != nil {
+		panic(errors.Wrap(err, "failed to bind LoggersProperties"))
+	}
+	return *props
+}
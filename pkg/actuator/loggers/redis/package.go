@@ -0,0 +1,164 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redisloggers provides loggers.Broadcaster backed by Redis pub/sub,
+// enabled with "management.endpoint.loggers.cluster.type: redis"
+package redisloggers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/actuator/loggers"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/redis"
+	"go.uber.org/fx"
+)
+
+var logger = log.New("ACTR.LoggerLevel")
+
+const (
+	BroadcasterType  = "redis"
+	DefaultKeyPrefix = "LOGGERS"
+)
+
+var Module = &bootstrap.Module{
+	Name:       "actuator-loggers-redis",
+	Precedence: bootstrap.ActuatorPrecedence,
+	Options: []fx.Option{
+		fx.Provide(FxProvider()),
+	},
+}
+
+func Use() {
+	bootstrap.Register(Module)
+}
+
+type factoryDI struct {
+	fx.In
+	AppCtx *bootstrap.ApplicationContext
+	Client redis.Client
+}
+
+func FxProvider() fx.Annotated {
+	return fx.Annotated{
+		Group: loggers.FxGroup,
+		Target: func(di factoryDI) loggers.BroadcasterFactory {
+			return factory{appName: di.AppCtx.Name(), client: di.Client}
+		},
+	}
+}
+
+type factory struct {
+	appName string
+	client  redis.Client
+}
+
+func (f factory) Type() string {
+	return BroadcasterType
+}
+
+func (f factory) NewBroadcaster(props loggers.ClusterProperties) (loggers.Broadcaster, error) {
+	prefix := props.KeyPrefix
+	if len(prefix) == 0 {
+		prefix = DefaultKeyPrefix
+	}
+	return NewRedisBroadcaster(f.client, fmt.Sprintf("%s:%s", prefix, f.appName)), nil
+}
+
+// RedisBroadcaster implements loggers.Broadcaster.
+// Level changes are published to channel "<prefix>:changes" and instance levels are stored in hash "<prefix>:instances"
+type RedisBroadcaster struct {
+	client      redis.Client
+	channel     string
+	instanceKey string
+}
+
+func NewRedisBroadcaster(client redis.Client, keyPrefix string) *RedisBroadcaster {
+	return &RedisBroadcaster{
+		client:      client,
+		channel:     keyPrefix + ":changes",
+		instanceKey: keyPrefix + ":instances",
+	}
+}
+
+func (b *RedisBroadcaster) Broadcast(ctx context.Context, change loggers.LevelChange) error {
+	data, e := json.Marshal(change)
+	if e != nil {
+		return e
+	}
+	return b.client.Publish(ctx, b.channel, data).Err()
+}
+
+func (b *RedisBroadcaster) Listen(ctx context.Context, handler func(ctx context.Context, change loggers.LevelChange)) error {
+	sub := b.client.Subscribe(ctx, b.channel)
+	// wait for subscription confirmation, so no message is missed after this function returns
+	if _, e := sub.Receive(ctx); e != nil {
+		_ = sub.Close()
+		return e
+	}
+	go func() {
+		defer func() { _ = sub.Close() }()
+		ch := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				var change loggers.LevelChange
+				if e := json.Unmarshal([]byte(msg.Payload), &change); e != nil {
+					logger.WithContext(ctx).Warnf(`Invalid logging level change: %v`, e)
+					continue
+				}
+				handler(ctx, change)
+			}
+		}
+	}()
+	return nil
+}
+
+func (b *RedisBroadcaster) Report(ctx context.Context, levels loggers.InstanceLevels) error {
+	data, e := json.Marshal(levels)
+	if e != nil {
+		return e
+	}
+	return b.client.HSet(ctx, b.instanceKey, levels.InstanceID, data).Err()
+}
+
+func (b *RedisBroadcaster) Remove(ctx context.Context, instanceID string) error {
+	return b.client.HDel(ctx, b.instanceKey, instanceID).Err()
+}
+
+func (b *RedisBroadcaster) Instances(ctx context.Context) ([]loggers.InstanceLevels, error) {
+	values, e := b.client.HGetAll(ctx, b.instanceKey).Result()
+	if e != nil {
+		return nil, e
+	}
+	ret := make([]loggers.InstanceLevels, 0, len(values))
+	for _, v := range values {
+		var inst loggers.InstanceLevels
+		if e := json.Unmarshal([]byte(v), &inst); e != nil {
+			logger.WithContext(ctx).Warnf(`Invalid instance logging levels: %v`, e)
+			continue
+		}
+		ret = append(ret, inst)
+	}
+	return ret, nil
+}
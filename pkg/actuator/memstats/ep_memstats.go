@@ -0,0 +1,145 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package memstats
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/actuator"
+	"runtime"
+	"time"
+)
+
+const (
+	ID              = "memstats"
+	EnableByDefault = false
+)
+
+// MemStatsDescriptor is a summary of runtime.MemStats with some runtime information.
+// All sizes are in bytes.
+//
+//goland:noinspection GoNameStartsWithPackageName
+type MemStatsDescriptor struct {
+	Runtime RuntimeDescriptor `json:"runtime"`
+	Heap    HeapDescriptor    `json:"heap"`
+	Stack   StackDescriptor   `json:"stack"`
+	GC      GCDescriptor      `json:"gc"`
+	// Sys is the total bytes of memory obtained from the OS
+	Sys uint64 `json:"sys"`
+	// TotalAlloc is cumulative bytes allocated for heap objects
+	TotalAlloc uint64 `json:"totalAlloc"`
+	Mallocs    uint64 `json:"mallocs"`
+	Frees      uint64 `json:"frees"`
+}
+
+type RuntimeDescriptor struct {
+	Version    string `json:"version"`
+	GOOS       string `json:"goos"`
+	GOARCH     string `json:"goarch"`
+	NumCPU     int    `json:"numCPU"`
+	GOMAXPROCS int    `json:"gomaxprocs"`
+	Goroutines int    `json:"goroutines"`
+}
+
+type HeapDescriptor struct {
+	Alloc    uint64 `json:"alloc"`
+	Sys      uint64 `json:"sys"`
+	Idle     uint64 `json:"idle"`
+	InUse    uint64 `json:"inUse"`
+	Released uint64 `json:"released"`
+	Objects  uint64 `json:"objects"`
+}
+
+type StackDescriptor struct {
+	InUse uint64 `json:"inUse"`
+	Sys   uint64 `json:"sys"`
+}
+
+type GCDescriptor struct {
+	NumGC       uint32        `json:"numGC"`
+	NumForcedGC uint32        `json:"numForcedGC"`
+	NextGC      uint64        `json:"nextGC"`
+	LastGC      *time.Time    `json:"lastGC,omitempty"`
+	PauseTotal  time.Duration `json:"pauseTotalNs"`
+	LastPause   time.Duration `json:"lastPauseNs"`
+	CPUFraction float64       `json:"cpuFraction"`
+}
+
+// MemStatsEndpoint implements actuator.Endpoint, actuator.WebEndpoint
+//
+//goland:noinspection GoNameStartsWithPackageName
+type MemStatsEndpoint struct {
+	actuator.WebEndpointBase
+}
+
+func newEndpoint(di regDI) *MemStatsEndpoint {
+	ep := MemStatsEndpoint{}
+	ep.WebEndpointBase = actuator.MakeWebEndpointBase(func(opt *actuator.EndpointOption) {
+		opt.Id = ID
+		opt.Ops = []actuator.Operation{
+			actuator.NewReadOperation(ep.Read),
+		}
+		opt.Properties = &di.MgtProperties.Endpoints
+		opt.EnabledByDefault = EnableByDefault
+	})
+	return &ep
+}
+
+// Read returns a summary of current memory statistics.
+// Note: runtime.ReadMemStats briefly stops the world.
+func (ep *MemStatsEndpoint) Read(_ context.Context, _ *struct{}) (*MemStatsDescriptor, error) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	desc := MemStatsDescriptor{
+		Runtime: RuntimeDescriptor{
+			Version:    runtime.Version(),
+			GOOS:       runtime.GOOS,
+			GOARCH:     runtime.GOARCH,
+			NumCPU:     runtime.NumCPU(),
+			GOMAXPROCS: runtime.GOMAXPROCS(0),
+			Goroutines: runtime.NumGoroutine(),
+		},
+		Heap: HeapDescriptor{
+			Alloc:    ms.HeapAlloc,
+			Sys:      ms.HeapSys,
+			Idle:     ms.HeapIdle,
+			InUse:    ms.HeapInuse,
+			Released: ms.HeapReleased,
+			Objects:  ms.HeapObjects,
+		},
+		Stack: StackDescriptor{
+			InUse: ms.StackInuse,
+			Sys:   ms.StackSys,
+		},
+		GC: GCDescriptor{
+			NumGC:       ms.NumGC,
+			NumForcedGC: ms.NumForcedGC,
+			NextGC:      ms.NextGC,
+			PauseTotal:  time.Duration(ms.PauseTotalNs),
+			CPUFraction: ms.GCCPUFraction,
+		},
+		Sys:        ms.Sys,
+		TotalAlloc: ms.TotalAlloc,
+		Mallocs:    ms.Mallocs,
+		Frees:      ms.Frees,
+	}
+	if ms.NumGC != 0 {
+		lastGC := time.Unix(0, int64(ms.LastGC))
+		desc.GC.LastGC = &lastGC
+		desc.GC.LastPause = time.Duration(ms.PauseNs[(ms.NumGC+255)%256])
+	}
+	return &desc, nil
+}
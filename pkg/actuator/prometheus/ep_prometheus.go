@@ -0,0 +1,111 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/actuator"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	promclient "github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"net/http"
+)
+
+const (
+	ID              = "prometheus"
+	EnableByDefault = true
+)
+
+var logger = log.New("ACTR.Prometheus")
+
+type Output struct {
+	Families []*dto.MetricFamily
+}
+
+// PrometheusEndpoint implements actuator.Endpoint, actuator.WebEndpoint.
+// It serves metrics in Prometheus text format, or OpenMetrics format if requested via "Accept" header
+//
+//goland:noinspection GoNameStartsWithPackageName
+type PrometheusEndpoint struct {
+	actuator.WebEndpointBase
+	gatherer promclient.Gatherer
+}
+
+func newEndpoint(di regDI) *PrometheusEndpoint {
+	ep := PrometheusEndpoint{
+		gatherer: di.Gatherer,
+	}
+	ep.WebEndpointBase = actuator.MakeWebEndpointBase(func(opt *actuator.EndpointOption) {
+		opt.Id = ID
+		opt.Ops = []actuator.Operation{
+			actuator.NewReadOperation(ep.Read),
+		}
+		opt.Properties = &di.MgtProperties.Endpoints
+		opt.EnabledByDefault = EnableByDefault
+	})
+	return &ep
+}
+
+// Mappings implements WebEndpoint
+func (ep *PrometheusEndpoint) Mappings(op actuator.Operation, group string) ([]web.Mapping, error) {
+	builder, e := ep.RestMappingBuilder(op, group, ep.MappingPath, ep.MappingName)
+	if e != nil {
+		return nil, e
+	}
+	builder.EncodeResponseFunc(ep.EncodeResponse)
+	return []web.Mapping{builder.Build()}, nil
+}
+
+// Read gathers all metrics. Partial result is returned if some collectors failed
+func (ep *PrometheusEndpoint) Read(ctx context.Context, _ *struct{}) (*Output, error) {
+	families, e := ep.gatherer.Gather()
+	if e != nil {
+		if len(families) == 0 {
+			return nil, e
+		}
+		logger.WithContext(ctx).Warnf("some metrics are not gathered: %v", e)
+	}
+	return &Output{Families: families}, nil
+}
+
+// EncodeResponse negotiates exposition format with "Accept" header
+func (ep *PrometheusEndpoint) EncodeResponse(ctx context.Context, rw http.ResponseWriter, v interface{}) error {
+	out, ok := v.(*Output)
+	if !ok {
+		return fmt.Errorf("unsupported response type %T", v)
+	}
+	var header http.Header
+	if gc := web.GinContext(ctx); gc != nil {
+		header = gc.Request.Header
+	}
+	format := expfmt.NegotiateIncludingOpenMetrics(header)
+	rw.Header().Set("Content-Type", string(format))
+	rw.WriteHeader(http.StatusOK)
+	enc := expfmt.NewEncoder(rw, format)
+	for _, f := range out.Families {
+		if e := enc.Encode(f); e != nil {
+			return e
+		}
+	}
+	if closer, ok := enc.(expfmt.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -25,8 +25,10 @@ import (
     "github.com/cisco-open/go-lanai/pkg/utils"
     "github.com/cisco-open/go-lanai/pkg/utils/order"
     "github.com/pkg/errors"
+    "reflect"
     "strconv"
     "strings"
+    "sync"
 )
 
 var (
@@ -72,39 +74,62 @@ func (p properties) Bind(target interface{}, prefix string) error {
 	return e
 }
 
+// Binding records a struct that has been bound via Bind, with the prefix it was bound with.
+type Binding struct {
+	Prefix string
+	Target interface{}
+}
+
 type config struct {
 	properties
-	groups    []ProviderGroup
-	providers []Provider //such as yaml auth, commandline etc.
-	profiles  utils.StringSet
-	isLoaded  bool
+	groups     []ProviderGroup
+	providers  []Provider //such as yaml auth, commandline etc.
+	profiles   utils.StringSet
+	isLoaded   bool
+	bindings   []Binding
+	bindingMtx sync.RWMutex
+	// propsMtx guards properties, providers, profiles and isLoaded, which are replaced during Refresh
+	propsMtx   sync.RWMutex
+	refreshMtx sync.Mutex
+	listeners  []ChangeListener
 }
 
 //Load will fail if place holder cannot be resolved due to circular dependency
 func (c *config) Load(ctx context.Context, force bool) (err error) {
-	defer func() {
-		if err != nil {
-			c.isLoaded = false
-		} else {
-			c.isLoaded = true
-		}
-	}()
-
-	// sort groups based on order, we process lower priority first
-	order.SortStable(c.groups, order.OrderedFirstCompareReverse)
+	c.refreshMtx.Lock()
+	defer c.refreshMtx.Unlock()
 
 	// reset all groups if force == true
 	if force {
+		c.propsMtx.Lock()
 		c.isLoaded = false
 		c.profiles = nil
+		c.propsMtx.Unlock()
 		for _, g := range c.groups {
 			g.Reset()
 		}
 	}
 
+	final, providers, err := c.load(ctx)
+	c.propsMtx.Lock()
+	defer c.propsMtx.Unlock()
+	if err != nil {
+		c.isLoaded = false
+		return
+	}
+	c.apply(final, providers)
+	c.isLoaded = true
+	return
+}
+
+// load processes all provider groups and returns merged and resolved properties, as well as effective providers.
+// It doesn't change the loaded properties
+func (c *config) load(ctx context.Context) (final properties, providers []Provider, err error) {
+	// sort groups based on order, we process lower priority first
+	order.SortStable(c.groups, order.OrderedFirstCompareReverse)
+
 	// repeatedly process provider groups until list of provider become stable and all loaded
-	var providers []Provider
-	final := makeInitialProperties()
+	final = makeInitialProperties()
 	// Note about hasNew check: when transiting from bootstrap config to application config,
 	// and all initial providers are from bootstrap config, all providers are loaded initially.
 	// However, we still need to re-collect/merge all properties.
@@ -158,13 +183,13 @@ func (c *config) Load(ctx context.Context, force bool) (err error) {
 
 			// special treatments:
 			// 	- PropertyKeyAdditionalProfiles need to be appended instead of overridden
-			if additionalProfiles, e = mergeAdditionalProfiles(additionalProfiles, formatted); e != nil {
-				return e
+			if additionalProfiles, err = mergeAdditionalProfiles(additionalProfiles, formatted); err != nil {
+				return
 			}
 		}
 
-		if e := setValue(merged, PropertyKeyAdditionalProfiles, additionalProfiles, true); e != nil {
-			return e
+		if err = setValue(merged, PropertyKeyAdditionalProfiles, additionalProfiles, true); err != nil {
+			return
 		}
 		final = merged
 	}
@@ -173,6 +198,11 @@ func (c *config) Load(ctx context.Context, force bool) (err error) {
 	if err = resolve(ctx, final); err != nil {
 		return
 	}
+	return
+}
+
+// apply replaces loaded properties, profiles and providers. propsMtx should be held by caller
+func (c *config) apply(final properties, providers []Provider) {
 	c.properties = final
 
 	// resolve profiles
@@ -189,11 +219,11 @@ func (c *config) Load(ctx context.Context, force bool) (err error) {
 	for i, v := range providers {
 		c.providers[l-i-1] = v
 	}
-
-	return
 }
 
 func (c *config) Value(key string) interface{} {
+	c.propsMtx.RLock()
+	defer c.propsMtx.RUnlock()
 	if !c.isLoaded {
 		return nil
 	}
@@ -202,27 +232,70 @@ func (c *config) Value(key string) interface{} {
 }
 
 func (c *config) Bind(target interface{}, prefix string) error {
+	c.propsMtx.RLock()
 	if !c.isLoaded {
+		c.propsMtx.RUnlock()
 		return errBindWithConfigBeforeLoaded
 	}
-	return c.properties.Bind(target, prefix)
+	props := c.properties
+	c.propsMtx.RUnlock()
+	if e := props.Bind(target, prefix); e != nil {
+		return e
+	}
+	c.recordBinding(target, prefix)
+	return nil
+}
+
+// Bindings returns all structs bound via Bind, in the order they were first bound.
+// When same type is bound to same prefix multiple times, only the latest target is kept.
+func (c *config) Bindings() []Binding {
+	c.bindingMtx.RLock()
+	defer c.bindingMtx.RUnlock()
+	bindings := make([]Binding, len(c.bindings))
+	copy(bindings, c.bindings)
+	return bindings
+}
+
+func (c *config) recordBinding(target interface{}, prefix string) {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return
+	}
+	c.bindingMtx.Lock()
+	defer c.bindingMtx.Unlock()
+	for i := range c.bindings {
+		if c.bindings[i].Prefix == prefix && reflect.TypeOf(c.bindings[i].Target) == rv.Type() {
+			c.bindings[i].Target = target
+			return
+		}
+	}
+	c.bindings = append(c.bindings, Binding{Prefix: prefix, Target: target})
 }
 
 // Each go through all properties and apply given function.
 // It stops at the first error
 func (c *config) Each(apply func(string, interface{}) error) error {
-	return VisitEach(c.properties, apply)
+	c.propsMtx.RLock()
+	props := c.properties
+	c.propsMtx.RUnlock()
+	return VisitEach(props, apply)
 }
 
 func (c *config) Providers() []Provider {
+	c.propsMtx.RLock()
+	defer c.propsMtx.RUnlock()
 	return c.providers
 }
 
 func (c *config) Profiles() []string {
+	c.propsMtx.RLock()
+	defer c.propsMtx.RUnlock()
 	return c.profiles.Values()
 }
 
 func (c *config) HasProfile(profile string) bool {
+	c.propsMtx.RLock()
+	defer c.propsMtx.RUnlock()
 	return c.profiles.Has(profile)
 }
 
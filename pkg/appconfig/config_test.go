@@ -307,6 +307,35 @@ func TestConfigBind(t *testing.T) {
 	}
 }
 
+func TestConfigBindings(t *testing.T) {
+	type testProperties struct {
+		Name string `json:"name"`
+	}
+	g := NewWithT(t)
+	c := &config{
+		properties: map[string]interface{}{
+			"a": map[string]interface{}{"name": "a"},
+			"b": map[string]interface{}{"name": "b"},
+		},
+		isLoaded: true,
+	}
+	first := testProperties{}
+	g.Expect(c.Bind(&first, "a")).To(Succeed(), "Bind() should not fail")
+	second := testProperties{}
+	g.Expect(c.Bind(&second, "b")).To(Succeed(), "Bind() should not fail")
+	again := testProperties{}
+	g.Expect(c.Bind(&again, "a")).To(Succeed(), "Bind() should not fail")
+	var m map[string]interface{}
+	g.Expect(c.Bind(&m, "a")).To(Succeed(), "Bind() should not fail")
+
+	bindings := c.Bindings()
+	g.Expect(bindings).To(HaveLen(2), "non-struct targets and duplicates should not be recorded")
+	g.Expect(bindings[0].Prefix).To(Equal("a"), "bindings should be in order")
+	g.Expect(bindings[0].Target).To(BeIdenticalTo(&again), "latest target should be kept")
+	g.Expect(bindings[1].Prefix).To(Equal("b"), "bindings should be in order")
+	g.Expect(bindings[1].Target).To(BeIdenticalTo(&second), "target should be recorded")
+}
+
 /*********************
 	SubTests
  *********************/
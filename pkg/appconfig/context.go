@@ -34,6 +34,8 @@ type ConfigAccessor interface {
 	Providers() []Provider
 	Profiles() []string
 	HasProfile(profile string) bool
+	// Bindings gives all properties structs bound so far, see Binding
+	Bindings() []Binding
 }
 
 type BootstrapConfig struct {
@@ -67,6 +67,9 @@ var Module = &bootstrap.Module{
 			newGlobalProperties,
 		),
 	},
+	Options: []fx.Option{
+		fx.Invoke(initializeRefresh),
+	},
 }
 
 // Use Entrypoint of appconfig package
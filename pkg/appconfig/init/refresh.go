@@ -0,0 +1,179 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package appconfig
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/appconfig"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"go.uber.org/fx"
+	"sync"
+	"time"
+)
+
+const (
+	RefreshPropertiesPrefix = "config.refresh"
+)
+
+// RefreshProperties controls dynamic refresh of application properties.
+// Refresh is triggered by appconfig.WatchableProvider, e.g. Consul KV store or Vault KV engine when watching is enabled.
+type RefreshProperties struct {
+	Enabled bool `json:"enabled"`
+	// Debounce is the delay to coalesce multiple change notifications into single refresh
+	Debounce utils.Duration `json:"debounce"`
+}
+
+type refreshDI struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	AppCtx    *bootstrap.ApplicationContext
+	AppConfig *appconfig.ApplicationConfig
+}
+
+func initializeRefresh(di refreshDI) error {
+	props := RefreshProperties{
+		Enabled:  true,
+		Debounce: utils.Duration(time.Second),
+	}
+	if e := di.AppConfig.Bind(&props, RefreshPropertiesPrefix); e != nil {
+		return e
+	}
+	if !props.Enabled {
+		return nil
+	}
+
+	// logging properties are applied during bootstrap, we keep them up to date
+	logProps, e := appconfig.NewRefreshable(di.AppConfig, "log", func() log.Properties { return log.Properties{} })
+	if e != nil {
+		return e
+	}
+	logProps.OnRefresh(func(ctx context.Context, _, current log.Properties) {
+		if e := log.UpdateLoggingConfiguration(&current); e != nil {
+			logger.WithContext(ctx).Warnf("Unable to apply refreshed logging configuration: %v", e)
+		}
+	})
+
+	refresher := newRefresher(di.AppConfig, time.Duration(props.Debounce))
+	di.Lifecycle.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			refresher.Start(di.AppCtx)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			refresher.Stop()
+			return nil
+		},
+	})
+	return nil
+}
+
+// refresher watches all appconfig.WatchableProvider of the application config and refreshes properties on changes
+type refresher struct {
+	config   appconfig.RefreshableConfig
+	debounce time.Duration
+	trigger  chan struct{}
+	mtx      sync.Mutex
+	watches  map[string]context.CancelFunc
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func newRefresher(cfg appconfig.RefreshableConfig, debounce time.Duration) *refresher {
+	return &refresher{
+		config:   cfg,
+		debounce: debounce,
+		trigger:  make(chan struct{}, 1),
+		watches:  map[string]context.CancelFunc{},
+	}
+}
+
+func (r *refresher) Start(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.watchProviders(ctx)
+	r.wg.Add(1)
+	go r.loop(ctx)
+}
+
+func (r *refresher) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+}
+
+func (r *refresher) notify() {
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (r *refresher) loop(ctx context.Context) {
+	defer r.wg.Done()
+	var timer <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.trigger:
+			if timer == nil {
+				timer = time.After(r.debounce)
+			}
+		case <-timer:
+			timer = nil
+			if _, e := r.config.Refresh(ctx); e != nil {
+				logger.WithContext(ctx).Warnf("Unable to refresh properties: %v", e)
+			}
+			// effective providers might be changed, e.g. when active profiles are changed
+			r.watchProviders(ctx)
+		}
+	}
+}
+
+// watchProviders starts watching new providers and stops watching providers that are no longer in use
+func (r *refresher) watchProviders(ctx context.Context) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	inUse := utils.NewStringSet()
+	for _, p := range r.config.Providers() {
+		wp, ok := p.(appconfig.WatchableProvider)
+		if !ok {
+			continue
+		}
+		inUse.Add(wp.Name())
+		if _, ok := r.watches[wp.Name()]; ok {
+			continue
+		}
+		watchCtx, cancel := context.WithCancel(ctx)
+		r.watches[wp.Name()] = cancel
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			if e := wp.Watch(watchCtx, r.notify); e != nil {
+				logger.WithContext(ctx).Warnf("Stopped watching [%s]: %v", wp.Name(), e)
+			}
+		}()
+	}
+	for name, cancel := range r.watches {
+		if !inUse.Has(name) {
+			cancel()
+			delete(r.watches, name)
+		}
+	}
+}
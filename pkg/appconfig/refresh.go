@@ -0,0 +1,141 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package appconfig
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ConfigChangedEvent is published to ChangeListener after properties are refreshed and at least one key is changed.
+type ConfigChangedEvent struct {
+	// Keys are sorted flattened keys that were added, modified or removed
+	Keys []string
+}
+
+// Affects returns true if any changed key equals to or is nested under given prefix.
+// e.g. prefix "log.levels" is affected by key "log.levels.web" but not "log.levels-extra"
+func (e ConfigChangedEvent) Affects(prefix string) bool {
+	prefix = NormalizeKey(prefix)
+	if len(prefix) == 0 || prefix == "." {
+		return len(e.Keys) != 0
+	}
+	for _, k := range e.Keys {
+		if k == prefix || strings.HasPrefix(k, prefix+".") || strings.HasPrefix(k, prefix+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeListener is notified when properties are changed by Refresh
+type ChangeListener interface {
+	OnConfigChanged(ctx context.Context, event ConfigChangedEvent)
+}
+
+// ChangeListenerFunc implements ChangeListener
+type ChangeListenerFunc func(ctx context.Context, event ConfigChangedEvent)
+
+func (fn ChangeListenerFunc) OnConfigChanged(ctx context.Context, event ConfigChangedEvent) {
+	fn(ctx, event)
+}
+
+// WatchableProvider is a Provider that is able to detect changes of its property source, e.g. Consul KV store.
+type WatchableProvider interface {
+	Provider
+	// Watch blocks until given context is cancelled and invokes notify function whenever the property source
+	// is changed. Implementations may return immediately when watching is disabled.
+	Watch(ctx context.Context, notify func()) error
+}
+
+// RefreshableConfig is a bootstrap.ApplicationConfig that can be refreshed at runtime
+type RefreshableConfig interface {
+	ConfigAccessor
+	// Refresh reloads all providers, re-merges properties and notifies ChangeListener if anything is changed.
+	// Returned event is nil if nothing is changed. Current properties are kept when reloading fails.
+	Refresh(ctx context.Context) (*ConfigChangedEvent, error)
+	// AddChangeListener registers a ChangeListener. Listeners are invoked in registration order
+	AddChangeListener(listener ChangeListener)
+}
+
+func (c *config) Refresh(ctx context.Context) (*ConfigChangedEvent, error) {
+	c.refreshMtx.Lock()
+	defer c.refreshMtx.Unlock()
+	for _, g := range c.groups {
+		g.Reset()
+	}
+
+	final, providers, e := c.load(ctx)
+	if e != nil {
+		return nil, e
+	}
+
+	c.propsMtx.Lock()
+	keys := changedKeys(c.properties, final)
+	c.apply(final, providers)
+	c.isLoaded = true
+	listeners := make([]ChangeListener, len(c.listeners))
+	copy(listeners, c.listeners)
+	c.propsMtx.Unlock()
+
+	if len(keys) == 0 {
+		logger.WithContext(ctx).Debugf("Properties refreshed without changes")
+		return nil, nil
+	}
+	logger.WithContext(ctx).Infof("Properties refreshed with %d changed keys", len(keys))
+	event := ConfigChangedEvent{Keys: keys}
+	for _, l := range listeners {
+		l.OnConfigChanged(ctx, event)
+	}
+	return &event, nil
+}
+
+func (c *config) AddChangeListener(listener ChangeListener) {
+	c.propsMtx.Lock()
+	defer c.propsMtx.Unlock()
+	c.listeners = append(c.listeners, listener)
+}
+
+// changedKeys compares two nested properties and returns sorted flattened keys that are different
+func changedKeys(prev, current map[string]interface{}) []string {
+	before := flatten(prev)
+	after := flatten(current)
+	keys := make([]string, 0)
+	for k, v := range after {
+		if old, ok := before[k]; !ok || !reflect.DeepEqual(old, v) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func flatten(nested map[string]interface{}) map[string]interface{} {
+	flat := map[string]interface{}{}
+	_ = VisitEach(nested, func(k string, v interface{}) error {
+		flat[k] = v
+		return nil
+	})
+	return flat
+}
@@ -0,0 +1,252 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package appconfig
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"sync"
+	"testing"
+)
+
+/*************************
+	Test Setup
+ *************************/
+
+type TestRefreshProperties struct {
+	Value   string   `json:"value"`
+	Default string   `json:"default"`
+	List    []string `json:"list"`
+}
+
+type TestRefreshDI struct {
+	Provider *FailingTestProvider
+	Config   *ApplicationConfig
+}
+
+func SetupTestRefreshConfig(di *TestRefreshDI) test.SetupFunc {
+	return func(ctx context.Context, t *testing.T) (context.Context, error) {
+		di.Provider = &FailingTestProvider{
+			TestProvider: TestProvider{
+				name: "refresh-test",
+				mocked: map[string]interface{}{
+					"test": map[string]interface{}{
+						"value": "initial",
+						"list":  []interface{}{"a", "b"},
+					},
+					"other": "initial",
+				},
+			},
+		}
+		di.Config = NewApplicationConfig(NewStaticProviderGroup(0, di.Provider))
+		return ctx, di.Config.Load(ctx, false)
+	}
+}
+
+/*************************
+	Tests
+ *************************/
+
+func TestConfigRefresh(t *testing.T) {
+	di := TestRefreshDI{}
+	test.RunTest(context.Background(), t,
+		test.SubTestSetup(SetupTestRefreshConfig(&di)),
+		test.GomegaSubTest(SubTestRefreshWithoutChanges(&di), "RefreshWithoutChanges"),
+		test.GomegaSubTest(SubTestRefreshWithChanges(&di), "RefreshWithChanges"),
+		test.GomegaSubTest(SubTestRefreshWithError(&di), "RefreshWithError"),
+		test.GomegaSubTest(SubTestRefreshableProperties(&di), "RefreshableProperties"),
+		test.GomegaSubTest(SubTestRefreshableConcurrency(&di), "RefreshableConcurrency"),
+		test.GomegaSubTest(SubTestChangedEventAffects(), "ChangedEventAffects"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestRefreshWithoutChanges(di *TestRefreshDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		var events []ConfigChangedEvent
+		di.Config.AddChangeListener(ChangeListenerFunc(func(_ context.Context, event ConfigChangedEvent) {
+			events = append(events, event)
+		}))
+		event, e := di.Config.Refresh(ctx)
+		g.Expect(e).To(Succeed(), "refresh should not fail")
+		g.Expect(event).To(BeNil(), "refresh without changes should not return event")
+		g.Expect(events).To(BeEmpty(), "listeners should not be notified")
+		g.Expect(di.Provider.loadCount).To(Equal(2), "provider should be reloaded")
+	}
+}
+
+func SubTestRefreshWithChanges(di *TestRefreshDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		var events []ConfigChangedEvent
+		di.Config.AddChangeListener(ChangeListenerFunc(func(_ context.Context, event ConfigChangedEvent) {
+			events = append(events, event)
+		}))
+		di.Provider.mocked = map[string]interface{}{
+			"test": map[string]interface{}{
+				"value":   "initial",
+				"default": "added",
+				"list":    []interface{}{"a", "c"},
+			},
+		}
+		event, e := di.Config.Refresh(ctx)
+		g.Expect(e).To(Succeed(), "refresh should not fail")
+		g.Expect(event).ToNot(BeNil(), "refresh with changes should return event")
+		g.Expect(event.Keys).To(Equal([]string{"other", "test.default", "test.list[1]"}), "event should have correct changed keys")
+		g.Expect(events).To(HaveLen(1), "listeners should be notified")
+		g.Expect(events[0]).To(Equal(*event), "listeners should receive same event")
+		g.Expect(di.Config.Value("test.default")).To(Equal("added"), "added value should be available")
+		g.Expect(di.Config.Value("other")).To(BeNil(), "removed value should not be available")
+	}
+}
+
+func SubTestRefreshWithError(di *TestRefreshDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		di.Provider.mocked = map[string]interface{}{
+			"test": map[string]interface{}{"value": "changed"},
+		}
+		di.Provider.fail = true
+		event, e := di.Config.Refresh(ctx)
+		g.Expect(e).To(HaveOccurred(), "refresh should fail")
+		g.Expect(event).To(BeNil(), "failed refresh should not return event")
+		g.Expect(di.Config.Value("test.value")).To(Equal("initial"), "previous value should be kept")
+
+		di.Provider.fail = false
+		event, e = di.Config.Refresh(ctx)
+		g.Expect(e).To(Succeed(), "refresh should not fail after recovered")
+		g.Expect(event).ToNot(BeNil(), "refresh with changes should return event")
+		g.Expect(di.Config.Value("test.value")).To(Equal("changed"), "changed value should be available")
+	}
+}
+
+func SubTestRefreshableProperties(di *TestRefreshDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		props, e := NewRefreshable(di.Config, "test", func() TestRefreshProperties {
+			return TestRefreshProperties{Default: "default", List: []string{"default"}}
+		})
+		g.Expect(e).To(Succeed(), "creating refreshable should not fail")
+		g.Expect(props.Get()).To(Equal(TestRefreshProperties{Value: "initial", Default: "default", List: []string{"a", "b"}}))
+
+		type change struct{ prev, current TestRefreshProperties }
+		var changes []change
+		props.OnRefresh(func(_ context.Context, prev, current TestRefreshProperties) {
+			changes = append(changes, change{prev: prev, current: current})
+		})
+
+		// unrelated changes
+		di.Provider.mocked = map[string]interface{}{
+			"test":  map[string]interface{}{"value": "initial", "list": []interface{}{"a", "b"}},
+			"other": "changed",
+		}
+		_, e = di.Config.Refresh(ctx)
+		g.Expect(e).To(Succeed(), "refresh should not fail")
+		g.Expect(changes).To(BeEmpty(), "hooks should not be invoked for unrelated changes")
+
+		// related changes, removed keys should fall back to defaults
+		di.Provider.mocked = map[string]interface{}{
+			"test": map[string]interface{}{"value": "changed"},
+		}
+		_, e = di.Config.Refresh(ctx)
+		g.Expect(e).To(Succeed(), "refresh should not fail")
+		expected := TestRefreshProperties{Value: "changed", Default: "default", List: []string{"default"}}
+		g.Expect(props.Get()).To(Equal(expected), "refreshable should be re-bound")
+		g.Expect(changes).To(HaveLen(1), "hooks should be invoked")
+		g.Expect(changes[0].prev.Value).To(Equal("initial"), "hooks should receive previous value")
+		g.Expect(changes[0].current).To(Equal(expected), "hooks should receive current value")
+	}
+}
+
+func SubTestRefreshableConcurrency(di *TestRefreshDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		di.Provider.mocked = map[string]interface{}{
+			"test": map[string]interface{}{"value": "v0", "list": []interface{}{"v0"}},
+		}
+		_, e := di.Config.Refresh(ctx)
+		g.Expect(e).To(Succeed(), "refresh should not fail")
+		props, e := NewRefreshable(di.Config, "test", func() TestRefreshProperties { return TestRefreshProperties{} })
+		g.Expect(e).To(Succeed(), "creating refreshable should not fail")
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-done:
+						return
+					default:
+						p := props.Get()
+						// value and list are always changed together
+						if len(p.List) != 1 || p.Value != p.List[0] {
+							t.Errorf("inconsistent value: %v", p)
+							return
+						}
+						_ = di.Config.Value("test.value")
+					}
+				}
+			}()
+		}
+		for _, v := range []string{"v1", "v2", "v3", "v4", "v5"} {
+			di.Provider.mocked = map[string]interface{}{
+				"test": map[string]interface{}{"value": v, "list": []interface{}{v}},
+			}
+			_, e := di.Config.Refresh(ctx)
+			g.Expect(e).To(Succeed(), "refresh should not fail")
+			g.Expect(props.Get().Value).To(Equal(v), "refreshable should be re-bound")
+		}
+		close(done)
+		wg.Wait()
+	}
+}
+
+func SubTestChangedEventAffects() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		event := ConfigChangedEvent{Keys: []string{"log.levels.web", "test.list[0]"}}
+		g.Expect(event.Affects("log")).To(BeTrue())
+		g.Expect(event.Affects("log.levels")).To(BeTrue())
+		g.Expect(event.Affects("log.levels.web")).To(BeTrue())
+		g.Expect(event.Affects("log.levels.data")).To(BeFalse())
+		g.Expect(event.Affects("lo")).To(BeFalse())
+		g.Expect(event.Affects("test.list")).To(BeTrue())
+		g.Expect(event.Affects("")).To(BeTrue())
+		g.Expect(ConfigChangedEvent{}.Affects("")).To(BeFalse())
+	}
+}
+
+/*************************
+	Mocks
+ *************************/
+
+type FailingTestProvider struct {
+	TestProvider
+	fail      bool
+	loadCount int
+}
+
+func (p *FailingTestProvider) Load(ctx context.Context) error {
+	if p.fail {
+		return errors.New("oops")
+	}
+	p.loadCount++
+	return p.TestProvider.Load(ctx)
+}
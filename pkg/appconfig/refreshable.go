@@ -0,0 +1,104 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package appconfig
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// RefreshHook is invoked after a Refreshable is re-bound with changed properties
+type RefreshHook[T any] func(ctx context.Context, prev, current T)
+
+// Refreshable holds properties bound with a prefix, and re-binds them when any key under the prefix is changed.
+// The new value is bound into a fresh instance and swapped atomically, so readers of Get always see a complete value.
+type Refreshable[T any] struct {
+	prefix     string
+	defaultsFn func() T
+	config     RefreshableConfig
+	value      atomic.Pointer[T]
+	hooksMtx   sync.RWMutex
+	hooks      []RefreshHook[T]
+}
+
+// NewRefreshable binds properties with given prefix and registers itself as ChangeListener of given config.
+// defaultsFn is used to create a fresh instance with default values for each binding
+func NewRefreshable[T any](cfg RefreshableConfig, prefix string, defaultsFn func() T) (*Refreshable[T], error) {
+	r := &Refreshable[T]{
+		prefix:     prefix,
+		defaultsFn: defaultsFn,
+		config:     cfg,
+	}
+	v, e := r.bind()
+	if e != nil {
+		return nil, e
+	}
+	r.value.Store(v)
+	cfg.AddChangeListener(r)
+	return r, nil
+}
+
+// Get returns current bound value
+func (r *Refreshable[T]) Get() T {
+	return *r.value.Load()
+}
+
+// Prefix returns the properties prefix this Refreshable is bound with
+func (r *Refreshable[T]) Prefix() string {
+	return r.prefix
+}
+
+// OnRefresh registers a RefreshHook, which is invoked in registration order after the value is changed
+func (r *Refreshable[T]) OnRefresh(hooks ...RefreshHook[T]) {
+	r.hooksMtx.Lock()
+	defer r.hooksMtx.Unlock()
+	r.hooks = append(r.hooks, hooks...)
+}
+
+// OnConfigChanged implements ChangeListener
+func (r *Refreshable[T]) OnConfigChanged(ctx context.Context, event ConfigChangedEvent) {
+	if !event.Affects(r.prefix) {
+		return
+	}
+	v, e := r.bind()
+	if e != nil {
+		logger.WithContext(ctx).Warnf(`Unable to re-bind properties with prefix "%s", previous values are kept: %v`, r.prefix, e)
+		return
+	}
+	prev := r.value.Swap(v)
+	logger.WithContext(ctx).Infof(`Properties with prefix "%s" are refreshed`, r.prefix)
+
+	r.hooksMtx.RLock()
+	hooks := make([]RefreshHook[T], len(r.hooks))
+	copy(hooks, r.hooks)
+	r.hooksMtx.RUnlock()
+	for _, hook := range hooks {
+		hook(ctx, *prev, *v)
+	}
+}
+
+func (r *Refreshable[T]) bind() (*T, error) {
+	var v T
+	if r.defaultsFn != nil {
+		v = r.defaultsFn()
+	}
+	if e := r.config.Bind(&v, r.prefix); e != nil {
+		return nil, e
+	}
+	return &v, nil
+}
@@ -34,6 +34,7 @@ const (
 	_ = FrameworkModulePrecedence + iota*(FrameworkModulePrecedenceBandwidth+1)
 	AppConfigPrecedence
 	TracingPrecedence
+	MetricsPrecedence
 	ActuatorPrecedence
 	ConsulPrecedence
 	VaultPrecedence
@@ -45,6 +46,7 @@ const (
 	OpenSearchPrecedence
 	WebPrecedence
 	SecurityPrecedence
+	GrpcPrecedence
 	DebugPrecedence
 	ServiceDiscoveryPrecedence
 	DistributedLockPrecedence
@@ -52,6 +54,7 @@ const (
 	TenantHierarchyLoaderPrecedence
 	TenantHierarchyModifierPrecedence
 	HttpClientPrecedence
+	GrpcClientPrecedence
 	SecurityIntegrationPrecedence
 	SwaggerPrecedence
 	StartupSummaryPrecedence
@@ -21,6 +21,7 @@ package certs
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 )
 
@@ -37,7 +38,13 @@ type SourceType string
 
 type TLSOptions func(opt *TLSOption)
 type TLSOption struct {
-	// For now, there is no configurable options
+	// Server indicates the tls.Config is for TLS server (e.g. web server).
+	// When set, the certificate is presented via tls.Config.GetCertificate. The certificate is resolved per handshake,
+	// so renewed certificates take effect without restarting the server.
+	Server bool
+	// ClientCAs is used to verify client certificates when Server is set (tls.Config.ClientCAs).
+	// Root CAs of the source are NOT used for client verification, so this has to be set explicitly for mutual TLS.
+	ClientCAs *x509.CertPool
 }
 
 type Source interface {
@@ -16,6 +16,8 @@
 
 package certs
 
+import "crypto/x509"
+
 func WithSourceProperties(props *SourceProperties) Options {
     return func(opt *Option) {
         if len(props.Preset) != 0 {
@@ -50,3 +52,17 @@ func WithType(srcType SourceType, cfg interface{}) Options {
         opt.RawConfig = cfg
     }
 }
+
+// AsServer is a TLSOptions requesting tls.Config for TLS server. See TLSOption.Server
+func AsServer() TLSOptions {
+    return func(opt *TLSOption) {
+        opt.Server = true
+    }
+}
+
+// WithClientCAs is a TLSOptions setting CAs for verifying client certificates. See TLSOption.ClientCAs
+func WithClientCAs(pool *x509.CertPool) TLSOptions {
+    return func(opt *TLSOption) {
+        opt.ClientCAs = pool
+    }
+}
@@ -69,7 +69,7 @@ func (a *AcmProvider) Close() error {
 	return nil
 }
 
-func (a *AcmProvider) TLSConfig(ctx context.Context, _ ...certs.TLSOptions) (*tls.Config, error) {
+func (a *AcmProvider) TLSConfig(ctx context.Context, opts ...certs.TLSOptions) (*tls.Config, error) {
 	if e := a.LazyInit(ctx); e != nil {
 		return nil, e
 	}
@@ -82,11 +82,11 @@ func (a *AcmProvider) TLSConfig(ctx context.Context, _ ...certs.TLSOptions) (*tl
 		return nil, e
 	}
 	//nolint:gosec // false positive -  G402: TLS MinVersion too low
-	return &tls.Config{
+	return certsource.ApplyTLSOptions(&tls.Config{
 		GetClientCertificate: a.toGetClientCertificateFunc(),
 		RootCAs:              rootCAs,
 		MinVersion:           minVer,
-	}, nil
+	}, a.currentCertificate, opts...), nil
 }
 
 func (a *AcmProvider) Files(ctx context.Context) (*certs.CertificateFiles, error) {
@@ -178,6 +178,15 @@ func (a *AcmProvider) toGetClientCertificateFunc() func(*tls.CertificateRequestI
 	}
 }
 
+func (a *AcmProvider) currentCertificate() (*tls.Certificate, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	if a.cachedCertificate == nil {
+		return nil, fmt.Errorf("certificate from source [%s] is not available", sourceType)
+	}
+	return a.cachedCertificate, nil
+}
+
 func (a *AcmProvider) generateClientCertificate(ctx context.Context) (*tls.Certificate, error) {
 	input := &acm.ExportCertificateInput{
 		CertificateArn: &a.props.ARN,
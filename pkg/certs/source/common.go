@@ -94,6 +94,24 @@ func ParseConfigWithDefaults[T any](defaults T, rawConfig json.RawMessage) (T, e
 	return defaults, nil
 }
 
+// ApplyTLSOptions apply certs.TLSOptions to the given client side tls.Config.
+// "getCert" should return the latest certificate of the source, it's invoked per TLS handshake when certs.TLSOption.Server is set.
+func ApplyTLSOptions(cfg *tls.Config, getCert func() (*tls.Certificate, error), opts ...certs.TLSOptions) *tls.Config {
+	opt := certs.TLSOption{}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	if !opt.Server {
+		return cfg
+	}
+	cfg.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return getCert()
+	}
+	cfg.ClientCAs = opt.ClientCAs
+	cfg.GetClientCertificate = nil
+	return cfg
+}
+
 func ParseTLSVersion(verStr string) (uint16, error) {
 	if v, ok := tlsVersions[verStr]; ok {
 		return v, nil
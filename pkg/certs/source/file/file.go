@@ -23,13 +23,25 @@ import (
     "encoding/pem"
     "github.com/cisco-open/go-lanai/pkg/certs"
     certsource "github.com/cisco-open/go-lanai/pkg/certs/source"
-    "io"
     "os"
     "path/filepath"
+    "sync"
+    "time"
 )
 
 type FileProvider struct {
-	p SourceProperties
+	p     SourceProperties
+	mtx   sync.Mutex
+	cache *cachedCertificate
+}
+
+// cachedCertificate is the parsed certificate along with file stats used to detect changes
+type cachedCertificate struct {
+	cert    *tls.Certificate
+	certMod time.Time
+	certLen int64
+	keyMod  time.Time
+	keyLen  int64
 }
 
 func NewFileProvider(p SourceProperties) certs.Source {
@@ -38,7 +50,7 @@ func NewFileProvider(p SourceProperties) certs.Source {
 	}
 }
 
-func (f *FileProvider) TLSConfig(ctx context.Context, _ ...certs.TLSOptions) (*tls.Config, error) {
+func (f *FileProvider) TLSConfig(ctx context.Context, opts ...certs.TLSOptions) (*tls.Config, error) {
 	rootCAs, e := f.RootCAs(ctx)
 	if e != nil {
 		return nil, e
@@ -48,11 +60,11 @@ func (f *FileProvider) TLSConfig(ctx context.Context, _ ...certs.TLSOptions) (*t
 		return nil, e
 	}
 	//nolint:gosec // false positive -  G402: TLS MinVersion too low
-	return &tls.Config{
+	return certsource.ApplyTLSOptions(&tls.Config{
 		GetClientCertificate: f.toGetClientCertificateFunc(),
 		RootCAs: rootCAs,
 		MinVersion: minVer,
-	}, nil
+	}, f.loadCertificate, opts...), nil
 }
 
 func (f *FileProvider) Files(_ context.Context) (*certs.CertificateFiles, error) {
@@ -76,48 +88,82 @@ func (f *FileProvider) RootCAs(_ context.Context) (*x509.CertPool, error) {
 
 func (f *FileProvider) toGetClientCertificateFunc() func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
 	return func(certificateReq *tls.CertificateRequestInfo) (*tls.Certificate, error) {
-		keyFile, err := os.Open(f.p.KeyFile)
+		clientCert, err := f.loadCertificate()
 		if err != nil {
 			return nil, err
 		}
 
-		keyBytes, err := io.ReadAll(keyFile)
-		if err != nil {
-			return nil, err
-		}
-		if f.p.KeyPass != "" {
-			keyBlock, _ := pem.Decode(keyBytes)
-			//nolint:staticcheck
-			unEncryptedKey, e := x509.DecryptPEMBlock(keyBlock, []byte(f.p.KeyPass))
-			if e != nil {
-				return nil, e
-			}
-			keyBlock.Bytes = unEncryptedKey
-			keyBlock.Headers = nil
-			keyBytes = pem.EncodeToMemory(keyBlock)
-		}
-		certfile, err := os.Open(f.p.CertFile)
-		if err != nil {
-			return nil, err
-		}
-		certBytes, err := io.ReadAll(certfile)
-		if err != nil {
-			return nil, err
-		}
-		clientCert, err := tls.X509KeyPair(certBytes, keyBytes)
-		if err != nil {
-			return nil, err
-		}
-
-		e := certificateReq.SupportsCertificate(&clientCert)
+		e := certificateReq.SupportsCertificate(clientCert)
 		if e != nil {
 			// No acceptable certificate found. Don't send a certificate. Don't need to treat as error.
 			// see tls package's tls.Conn.getClientCertificate(cri *CertificateRequestInfo) (*Certificate, error)
 			return new(tls.Certificate), nil //nolint:nilerr
 		} else {
-			return &clientCert, nil
+			return clientCert, nil
+		}
+	}
+}
+
+// loadCertificate returns the cached certificate. Files are re-read only when their modification time or size changes,
+// so replaced files take effect on next handshake without reading and decrypting the key every time.
+func (f *FileProvider) loadCertificate() (*tls.Certificate, error) {
+	certStat, e := os.Stat(f.p.CertFile)
+	if e != nil {
+		return nil, e
+	}
+	keyStat, e := os.Stat(f.p.KeyFile)
+	if e != nil {
+		return nil, e
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if c := f.cache; c != nil &&
+		c.certMod.Equal(certStat.ModTime()) && c.certLen == certStat.Size() &&
+		c.keyMod.Equal(keyStat.ModTime()) && c.keyLen == keyStat.Size() {
+		return c.cert, nil
+	}
+
+	cert, e := f.readCertificate()
+	if e != nil {
+		return nil, e
+	}
+	f.cache = &cachedCertificate{
+		cert:    cert,
+		certMod: certStat.ModTime(),
+		certLen: certStat.Size(),
+		keyMod:  keyStat.ModTime(),
+		keyLen:  keyStat.Size(),
+	}
+	return cert, nil
+}
+
+// readCertificate reads and parses certificate and key from files.
+func (f *FileProvider) readCertificate() (*tls.Certificate, error) {
+	keyBytes, err := os.ReadFile(f.p.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if f.p.KeyPass != "" {
+		keyBlock, _ := pem.Decode(keyBytes)
+		//nolint:staticcheck
+		unEncryptedKey, e := x509.DecryptPEMBlock(keyBlock, []byte(f.p.KeyPass))
+		if e != nil {
+			return nil, e
 		}
+		keyBlock.Bytes = unEncryptedKey
+		keyBlock.Headers = nil
+		keyBytes = pem.EncodeToMemory(keyBlock)
+	}
+	certBytes, err := os.ReadFile(f.p.CertFile)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(certBytes, keyBytes)
+	if err != nil {
+		return nil, err
 	}
+	return &cert, nil
 }
 
 func (f *FileProvider) toAbsPath(path string) string {
@@ -28,7 +28,9 @@ import (
     "github.com/cisco-open/go-lanai/test/apptest"
     "go.uber.org/fx"
     "os"
+    "path/filepath"
     "testing"
+    "time"
 )
 import . "github.com/onsi/gomega"
 
@@ -74,6 +76,8 @@ func TestFileCertificateSource(t *testing.T) {
 			fx.Provide(ProvideTestManager, BindTestProperties, filecerts.FxProvider()),
 		),
 		test.GomegaSubTest(SubTestTLSConfig(di), "SubTestTLSConfig"),
+		test.GomegaSubTest(SubTestServerTLSConfig(di), "TestServerTLSConfig"),
+		test.GomegaSubTest(SubTestServerCertificateReload(di), "TestServerCertificateReload"),
 		test.GomegaSubTest(SubTestFiles(di), "TestFiles"),
 	)
 }
@@ -136,6 +140,70 @@ func SubTestTLSConfig(di *FileTestDi) test.GomegaSubTestFunc {
 	}
 }
 
+func SubTestServerTLSConfig(di *FileTestDi) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		p := filecerts.SourceProperties{
+			CACertFile: "testdata/ca-cert-test.pem",
+			CertFile:   "testdata/client-cert-signed-test.pem",
+			KeyFile:    "testdata/client-key-test.pem",
+			KeyPass:    "foobar",
+		}
+
+		tlsSrc, err := di.CertsManager.Source(ctx, certs.WithType(certs.SourceFile, p))
+		g.Expect(err).NotTo(HaveOccurred())
+
+		tlsCfg, err := tlsSrc.TLSConfig(ctx, certs.AsServer())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(tlsCfg.GetClientCertificate).To(BeNil())
+		g.Expect(tlsCfg.GetCertificate).ToNot(BeNil())
+		g.Expect(tlsCfg.ClientCAs).To(BeNil(), "root CAs should not be used to verify clients implicitly")
+
+		clientCAs, err := tlsSrc.(*filecerts.FileProvider).RootCAs(ctx)
+		g.Expect(err).NotTo(HaveOccurred())
+		tlsCfg, err = tlsSrc.TLSConfig(ctx, certs.AsServer(), certs.WithClientCAs(clientCAs))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(tlsCfg.ClientCAs).ToNot(BeNil())
+		g.Expect(len(tlsCfg.ClientCAs.Subjects())).To(Equal(1))
+
+		cert, err := tlsCfg.GetCertificate(&tls.ClientHelloInfo{})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(cert).NotTo(BeNil())
+		g.Expect(len(cert.Certificate)).To(Equal(1))
+	}
+}
+
+func SubTestServerCertificateReload(di *FileTestDi) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		dir := t.TempDir()
+		p := filecerts.SourceProperties{
+			CACertFile: "testdata/ca-cert-test.pem",
+			CertFile:   filepath.Join(dir, "cert.pem"),
+			KeyFile:    filepath.Join(dir, "key.pem"),
+			KeyPass:    "foobar",
+		}
+		CopyFile(g, "testdata/client-cert-signed-test.pem", p.CertFile)
+		CopyFile(g, "testdata/client-key-test.pem", p.KeyFile)
+
+		tlsSrc, err := di.CertsManager.Source(ctx, certs.WithType(certs.SourceFile, p))
+		g.Expect(err).NotTo(HaveOccurred())
+		tlsCfg, err := tlsSrc.TLSConfig(ctx, certs.AsServer())
+		g.Expect(err).NotTo(HaveOccurred())
+
+		cert, err := tlsCfg.GetCertificate(&tls.ClientHelloInfo{})
+		g.Expect(err).NotTo(HaveOccurred())
+		cached, err := tlsCfg.GetCertificate(&tls.ClientHelloInfo{})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(cached).To(BeIdenticalTo(cert), "certificate should be cached when files are not changed")
+
+		later := time.Now().Add(time.Minute)
+		g.Expect(os.Chtimes(p.CertFile, later, later)).To(Succeed())
+		reloaded, err := tlsCfg.GetCertificate(&tls.ClientHelloInfo{})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(reloaded).NotTo(BeIdenticalTo(cert), "certificate should be reloaded when files are changed")
+		g.Expect(reloaded.Certificate).To(Equal(cert.Certificate))
+	}
+}
+
 func SubTestFiles(di *FileTestDi) test.GomegaSubTestFunc {
 	return func(ctx context.Context, t *testing.T, g *WithT) {
 		p := filecerts.SourceProperties{
@@ -164,6 +232,12 @@ func SubTestFiles(di *FileTestDi) test.GomegaSubTestFunc {
 	Helpers
  *************************/
 
+func CopyFile(g *WithT, src, dst string) {
+	data, e := os.ReadFile(src)
+	g.Expect(e).NotTo(HaveOccurred())
+	g.Expect(os.WriteFile(dst, data, 0600)).To(Succeed())
+}
+
 func AssertFilesExist(g *WithT, paths []string) {
 	for _, path := range paths {
 		AssertFileExists(g, path)
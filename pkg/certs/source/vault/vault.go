@@ -66,7 +66,7 @@ func NewVaultProvider(ctx context.Context, vc *vault.Client, p SourceProperties)
 	}
 }
 
-func (v *VaultProvider) TLSConfig(ctx context.Context, _ ...certs.TLSOptions) (*tls.Config, error) {
+func (v *VaultProvider) TLSConfig(ctx context.Context, opts ...certs.TLSOptions) (*tls.Config, error) {
 	if e := v.LazyInit(ctx); e != nil {
 		return nil, e
 	}
@@ -79,11 +79,11 @@ func (v *VaultProvider) TLSConfig(ctx context.Context, _ ...certs.TLSOptions) (*
 		return nil, e
 	}
 	//nolint:gosec // false positive -  G402: TLS MinVersion too low
-	return &tls.Config{
+	return certsource.ApplyTLSOptions(&tls.Config{
 		GetClientCertificate: v.toGetClientCertificateFunc(),
 		RootCAs:              rootCAs,
 		MinVersion:           minVer,
-	}, nil
+	}, v.currentCertificate, opts...), nil
 }
 
 func (v *VaultProvider) Files(ctx context.Context) (*certs.CertificateFiles, error) {
@@ -184,6 +184,15 @@ func (v *VaultProvider) toGetClientCertificateFunc() func(*tls.CertificateReques
 	}
 }
 
+func (v *VaultProvider) currentCertificate() (*tls.Certificate, error) {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	if v.cachedCertificate == nil {
+		return nil, fmt.Errorf("certificate from source [%s] is not available", sourceType)
+	}
+	return v.cachedCertificate, nil
+}
+
 func (v *VaultProvider) tryRenew(loopCtx context.Context) loop.TaskFunc {
 	return func(_ context.Context, l *loop.Loop) (ret interface{}, err error) {
 		//ignore error since we will just schedule another renew
@@ -22,6 +22,7 @@ import (
 	appconfiginit "github.com/cisco-open/go-lanai/pkg/appconfig/init"
 	"github.com/cisco-open/go-lanai/pkg/bootstrap"
 	"github.com/cisco-open/go-lanai/pkg/consul"
+	"time"
 )
 
 type ProviderGroupOptions func(opt *ProviderGroupOption)
@@ -32,6 +33,9 @@ type ProviderGroupOption struct {
 	Path             string
 	ProfileSeparator string
 	Connection       *consul.Connection
+	// Watch enables watching KV changes with Consul blocking queries, see WatchWaitTime
+	Watch         bool
+	WatchWaitTime time.Duration
 }
 
 // NewProviderGroup create a Consul KV store backed appconfig.ProviderGroup.
@@ -48,6 +52,7 @@ func NewProviderGroup(opts ...ProviderGroupOptions) appconfig.ProviderGroup {
 		Prefix:           DefaultConfigPathPrefix,
 		Path:             DefaultConfigPath,
 		ProfileSeparator: DefaultProfileSeparator,
+		WatchWaitTime:    DefaultWatchWaitTime,
 	}
 	for _, fn := range opts {
 		fn(&opt)
@@ -61,7 +66,11 @@ func NewProviderGroup(opts ...ProviderGroupOptions) appconfig.ProviderGroup {
 		return fmt.Sprintf("%s/%s%s%s", opt.Prefix, opt.Path, opt.ProfileSeparator, profile)
 	}
 	group.CreateFunc = func(name string, order int, _ bootstrap.ApplicationConfig) appconfig.Provider {
-		ptr := NewConfigProvider(order, name, opt.Connection)
+		var providerOpts []ConfigProviderOptions
+		if opt.Watch {
+			providerOpts = append(providerOpts, WithWatch(opt.WatchWaitTime))
+		}
+		ptr := NewConfigProvider(order, name, opt.Connection, providerOpts...)
 		if ptr == nil {
 			return nil
 		}
@@ -22,6 +22,7 @@ import (
 	"github.com/cisco-open/go-lanai/pkg/bootstrap"
 	"github.com/cisco-open/go-lanai/pkg/consul"
 	"go.uber.org/fx"
+	"time"
 )
 
 var Module = &bootstrap.Module{
@@ -53,6 +54,8 @@ func withProperties(props *ConsulConfigProperties) ProviderGroupOptions {
 		opt.Prefix = props.Prefix
 		opt.Path = props.DefaultContext
 		opt.ProfileSeparator = props.ProfileSeparator
+		opt.Watch = props.Watch.Enabled
+		opt.WatchWaitTime = time.Duration(props.Watch.WaitTime)
 	}
 }
 
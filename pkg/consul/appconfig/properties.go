@@ -16,7 +16,10 @@
 
 package consulappconfig
 
-import "github.com/cisco-open/go-lanai/pkg/appconfig"
+import (
+	"github.com/cisco-open/go-lanai/pkg/appconfig"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+)
 
 const (
 	PropertiesPrefix = "cloud.consul.config"
@@ -26,10 +29,17 @@ const (
 )
 
 type ConsulConfigProperties struct {
-	Enabled          bool   `json:"enabled"`
-	Prefix           string `json:"prefix"`
-	DefaultContext   string `json:"default-context"`
-	ProfileSeparator string `json:"profile-separator"`
+	Enabled          bool            `json:"enabled"`
+	Prefix           string          `json:"prefix"`
+	DefaultContext   string          `json:"default-context"`
+	ProfileSeparator string          `json:"profile-separator"`
+	Watch            WatchProperties `json:"watch"`
+}
+
+// WatchProperties controls whether application properties are refreshed when KV pairs are changed in Consul
+type WatchProperties struct {
+	Enabled  bool           `json:"enabled"`
+	WaitTime utils.Duration `json:"wait-time"`
 }
 
 func bindConsulConfigProperties(bootstrapConfig *appconfig.BootstrapConfig) (ConsulConfigProperties, error) {
@@ -38,6 +48,9 @@ func bindConsulConfigProperties(bootstrapConfig *appconfig.BootstrapConfig) (Con
 		DefaultContext:   DefaultConfigPath,
 		ProfileSeparator: DefaultProfileSeparator,
 		Enabled:          true,
+		Watch: WatchProperties{
+			WaitTime: utils.Duration(DefaultWatchWaitTime),
+		},
 	}
 	if e := bootstrapConfig.Bind(&p, PropertiesPrefix); e != nil {
 		return p, e
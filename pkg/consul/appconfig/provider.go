@@ -22,14 +22,34 @@ import (
     "github.com/cisco-open/go-lanai/pkg/appconfig"
     "github.com/cisco-open/go-lanai/pkg/consul"
     "github.com/cisco-open/go-lanai/pkg/log"
+    "github.com/hashicorp/consul/api"
+    "time"
 )
 
 var logger = log.New("Config.Consul")
 
+const (
+	DefaultWatchWaitTime = 55 * time.Second
+	watchRetryInterval   = 5 * time.Second
+)
+
+type ConfigProviderOptions func(p *ConfigProvider)
+
+// WithWatch enables watching the context path using Consul blocking queries with given wait time.
+func WithWatch(waitTime time.Duration) ConfigProviderOptions {
+	return func(p *ConfigProvider) {
+		p.watch = true
+		p.watchWaitTime = waitTime
+	}
+}
+
+// ConfigProvider implements appconfig.WatchableProvider.
 type ConfigProvider struct {
 	appconfig.ProviderMeta
-	contextPath  string
-	connection   *consul.Connection
+	contextPath   string
+	connection    *consul.Connection
+	watch         bool
+	watchWaitTime time.Duration
 }
 
 func (configProvider *ConfigProvider) Name() string {
@@ -68,10 +88,50 @@ func (configProvider *ConfigProvider) Load(ctx context.Context) (loadError error
 	return nil
 }
 
-func NewConfigProvider(precedence int, contextPath string, conn *consul.Connection) *ConfigProvider {
-	return &ConfigProvider{
-			ProviderMeta: appconfig.ProviderMeta{Precedence: precedence},
-			contextPath:  contextPath, //fmt.Sprintf("%s/%s", f.sourceConfig.Prefix, f.contextPath)
-			connection:   conn,
+// Watch implements appconfig.WatchableProvider. It returns immediately if watching is not enabled.
+// Consul blocking queries are used to detect any change of keys under the context path.
+func (configProvider *ConfigProvider) Watch(ctx context.Context, notify func()) error {
+	if !configProvider.watch {
+		return nil
+	}
+	logger.WithContext(ctx).Debugf("Watching consul: %s", configProvider.contextPath)
+	var index uint64
+	prefix := configProvider.contextPath + "/"
+	for {
+		opts := &api.QueryOptions{WaitIndex: index, WaitTime: configProvider.watchWaitTime}
+		_, meta, e := configProvider.connection.Client().KV().List(prefix, opts.WithContext(ctx))
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case e != nil:
+			logger.WithContext(ctx).Warnf("Failed to watch consul [%s], retry in %v: %v", configProvider.contextPath, watchRetryInterval, e)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(watchRetryInterval):
+			}
+			continue
+		case meta.LastIndex < index:
+			// index went backwards (e.g. consul cluster is reset), start over
+			index = 0
+			notify()
+			continue
+		case index != 0 && meta.LastIndex != index:
+			logger.WithContext(ctx).Debugf("Detected changes in consul: %s", configProvider.contextPath)
+			notify()
 		}
+		index = meta.LastIndex
+	}
+}
+
+func NewConfigProvider(precedence int, contextPath string, conn *consul.Connection, opts ...ConfigProviderOptions) *ConfigProvider {
+	p := &ConfigProvider{
+		ProviderMeta: appconfig.ProviderMeta{Precedence: precedence},
+		contextPath:  contextPath, //fmt.Sprintf("%s/%s", f.sourceConfig.Prefix, f.contextPath)
+		connection:   conn,
+	}
+	for _, fn := range opts {
+		fn(p)
+	}
+	return p
 }
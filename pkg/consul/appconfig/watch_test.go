@@ -0,0 +1,207 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package consulappconfig_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/consul"
+	consulappconfig "github.com/cisco-open/go-lanai/pkg/consul/appconfig"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/*************************
+	Test Setup
+ *************************/
+
+const TestWatchPath = "testconfig/test-app"
+
+type TestWatchDI struct {
+	Server     *MockedKVServer
+	Connection *consul.Connection
+}
+
+func SetupTestMockedConsul(di *TestWatchDI) test.SetupFunc {
+	return func(ctx context.Context, t *testing.T) (context.Context, error) {
+		di.Server = NewMockedKVServer()
+		t.Cleanup(di.Server.Close)
+		host, port, e := net.SplitHostPort(di.Server.Listener.Addr().String())
+		if e != nil {
+			return ctx, e
+		}
+		portNum, _ := strconv.Atoi(port)
+		di.Connection, e = consul.New(consul.WithProperties(consul.ConnectionProperties{
+			Host:   host,
+			Port:   portNum,
+			Scheme: "http",
+		}))
+		return ctx, e
+	}
+}
+
+/*************************
+	Tests
+ *************************/
+
+func TestConfigWatch(t *testing.T) {
+	di := TestWatchDI{}
+	test.RunTest(context.Background(), t,
+		test.SubTestSetup(SetupTestMockedConsul(&di)),
+		test.GomegaSubTest(SubTestWatchDisabled(&di), "WatchDisabled"),
+		test.GomegaSubTest(SubTestWatchChanges(&di), "WatchChanges"),
+		test.GomegaSubTest(SubTestWatchIndexReset(&di), "WatchIndexReset"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestWatchDisabled(di *TestWatchDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		p := consulappconfig.NewConfigProvider(0, TestWatchPath, di.Connection)
+		e := p.Watch(ctx, func() {
+			t.Errorf("notify should not be invoked")
+		})
+		g.Expect(e).To(Succeed(), "watch should return immediately when disabled")
+	}
+}
+
+func SubTestWatchChanges(di *TestWatchDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		p := consulappconfig.NewConfigProvider(0, TestWatchPath, di.Connection, consulappconfig.WithWatch(200*time.Millisecond))
+		var count int64
+		stop := StartWatch(ctx, p, &count)
+		defer stop()
+
+		g.Consistently(func() int64 { return atomic.LoadInt64(&count) }, 500*time.Millisecond).
+			Should(BeZero(), "notify should not be invoked without changes")
+		g.Expect(di.Server.Requests()).To(BeNumerically(">", 1), "blocking queries should be repeated")
+
+		di.Server.Change()
+		g.Eventually(func() int64 { return atomic.LoadInt64(&count) }).
+			Should(Equal(int64(1)), "notify should be invoked when KV is changed")
+		di.Server.Change()
+		g.Eventually(func() int64 { return atomic.LoadInt64(&count) }).
+			Should(Equal(int64(2)), "notify should be invoked when KV is changed again")
+		g.Expect(di.Server.LastPath()).To(Equal("/v1/kv/"+TestWatchPath+"/"), "KV should be watched with correct prefix")
+	}
+}
+
+func SubTestWatchIndexReset(di *TestWatchDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		p := consulappconfig.NewConfigProvider(0, TestWatchPath, di.Connection, consulappconfig.WithWatch(200*time.Millisecond))
+		var count int64
+		stop := StartWatch(ctx, p, &count)
+		defer stop()
+
+		g.Eventually(di.Server.Requests).Should(BeNumerically(">", 1), "watch should be started")
+		di.Server.Reset()
+		g.Eventually(func() int64 { return atomic.LoadInt64(&count) }).
+			Should(BeNumerically(">=", 1), "notify should be invoked when index goes backwards")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func StartWatch(ctx context.Context, p *consulappconfig.ConfigProvider, count *int64) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = p.Watch(ctx, func() { atomic.AddInt64(count, 1) })
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// MockedKVServer mimics Consul KV list API with blocking queries
+type MockedKVServer struct {
+	*httptest.Server
+	index    atomic.Uint64
+	requests atomic.Int64
+	lastPath atomic.Value
+}
+
+func NewMockedKVServer() *MockedKVServer {
+	s := &MockedKVServer{}
+	s.index.Store(10)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+func (s *MockedKVServer) Change() {
+	s.index.Add(1)
+}
+
+func (s *MockedKVServer) Reset() {
+	s.index.Store(1)
+}
+
+func (s *MockedKVServer) Requests() int64 {
+	return s.requests.Load()
+}
+
+func (s *MockedKVServer) LastPath() string {
+	v, _ := s.lastPath.Load().(string)
+	return v
+}
+
+func (s *MockedKVServer) handle(rw http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+	s.lastPath.Store(r.URL.Path)
+	waitIndex, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+	wait, e := time.ParseDuration(r.URL.Query().Get("wait"))
+	if e != nil {
+		wait = time.Second
+	}
+	timeout := time.After(wait)
+	for waitIndex != 0 && s.index.Load() == waitIndex {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timeout:
+			s.write(rw)
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	s.write(rw)
+}
+
+func (s *MockedKVServer) write(rw http.ResponseWriter) {
+	index := s.index.Load()
+	rw.Header().Set("X-Consul-Index", strconv.FormatUint(index, 10))
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode([]map[string]interface{}{
+		{"Key": TestWatchPath + "/test.value", "Value": []byte(fmt.Sprintf("value-%d", index)), "ModifyIndex": index},
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/cisco-open/go-lanai/pkg/actuator/health"
 	"github.com/cisco-open/go-lanai/pkg/bootstrap"
 	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/metrics"
 	"github.com/opentracing/opentracing-go"
 	"go.uber.org/fx"
 	"gorm.io/gorm"
@@ -52,6 +53,7 @@ type gormInitDI struct {
 	Configurers []GormConfigurer   `group:"gorm_config"`
 	Translators []ErrorTranslator  `group:"gorm_config"`
 	Tracer      opentracing.Tracer `optional:"true"`
+	Registry    metrics.Registry   `optional:"true"`
 }
 
 func provideGorm(di gormInitDI) *gorm.DB {
@@ -62,6 +64,9 @@ func provideGorm(di gormInitDI) *gorm.DB {
 		if di.Tracer != nil {
 			cfg.Configurers = append(cfg.Configurers, NewGormTracingConfigurer(di.Tracer))
 		}
+		if di.Registry != nil {
+			cfg.Configurers = append(cfg.Configurers, NewGormMetricsConfigurer(di.Registry))
+		}
 		cfg.Configurers = append(cfg.Configurers, di.Configurers...)
 		if di.Properties.Logging.SlowThreshold > 0 {
 			cfg.LogSlowQueryThreshold = time.Duration(di.Properties.Logging.SlowThreshold)
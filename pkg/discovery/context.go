@@ -46,6 +46,7 @@ const (
 	InstanceMetaKeyVersion     = `version`
 	InstanceMetaKeyContextPath = `context`
 	InstanceMetaKeySMCR        = `SMCR`
+	InstanceMetaKeyGrpcPort    = `grpcPort`
 	//InstanceMetaKey = ``
 )
 
@@ -0,0 +1,149 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+// ErrorCodeMapping maps errors matching Error (via errors.Is) to given gRPC status code.
+// Since errorutils.CodedError matches by code mask, Error can be an error type, sub-type or a specific coded error.
+type ErrorCodeMapping struct {
+	Error error
+	Code  codes.Code
+}
+
+var defaultErrorCodeMappings = []ErrorCodeMapping{
+	{Error: security.ErrorSubTypeInsufficientAuth, Code: codes.Unauthenticated},
+	{Error: security.ErrorTypeAccessControl, Code: codes.PermissionDenied},
+	{Error: security.ErrorTypeAuthentication, Code: codes.Unauthenticated},
+	{Error: security.ErrorTenantAccessDenied, Code: codes.PermissionDenied},
+	{Error: security.ErrorInvalidTenantId, Code: codes.InvalidArgument},
+}
+
+// StatusFromError converts given error to gRPC status. The conversion is performed in following order:
+//   - errors already carrying a gRPC status are returned as is
+//   - context cancellation and deadline errors
+//   - given ErrorCodeMapping, followed by default mappings of security errors
+//   - errors implementing web.StatusCoder, using HTTPStatusToCode
+//
+// Anything else is converted to codes.Internal with a generic message, so internal details are not exposed to clients
+func StatusFromError(err error, mappings ...ErrorCodeMapping) *status.Status {
+	if err == nil {
+		return nil
+	}
+	if s, ok := status.FromError(err); ok {
+		return s
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, err.Error())
+	}
+
+	for _, list := range [][]ErrorCodeMapping{mappings, defaultErrorCodeMappings} {
+		for _, m := range list {
+			if errors.Is(err, m.Error) {
+				return status.New(m.Code, err.Error())
+			}
+		}
+	}
+
+	var sc web.StatusCoder
+	if errors.As(err, &sc) {
+		return status.New(HTTPStatusToCode(sc.StatusCode()), err.Error())
+	}
+	return status.New(codes.Internal, "internal error")
+}
+
+// HTTPStatusToCode converts HTTP status code to closest gRPC status code
+func HTTPStatusToCode(sc int) codes.Code {
+	switch sc {
+	case http.StatusOK:
+		return codes.OK
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	switch {
+	case sc >= 400 && sc < 500:
+		return codes.InvalidArgument
+	case sc >= 500:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
+
+/**********************
+	Interceptor
+ **********************/
+
+func newErrorHandlingInterceptor(mappings []ErrorCodeMapping) *Interceptor {
+	return &Interceptor{
+		Order: InterceptorOrderErrorHandling,
+		Unary: func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			resp, e := handler(ctx, req)
+			if e != nil {
+				return resp, translateError(ctx, info.FullMethod, e, mappings)
+			}
+			return resp, nil
+		},
+		Stream: func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if e := handler(srv, ss); e != nil {
+				return translateError(ss.Context(), info.FullMethod, e, mappings)
+			}
+			return nil
+		},
+	}
+}
+
+// translateError converts error to gRPC status error. Errors not recognized by StatusFromError are logged,
+// since their details are not returned to clients.
+func translateError(ctx context.Context, method string, err error, mappings []ErrorCodeMapping) error {
+	s := StatusFromError(err, mappings...)
+	if _, ok := status.FromError(err); !ok && s.Code() == codes.Internal {
+		logger.WithContext(ctx).Errorf("gRPC method [%s] failed: %v", method, err)
+	}
+	return s.Err()
+}
@@ -0,0 +1,212 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc_test
+
+import (
+	"context"
+	"fmt"
+	lanaigrpc "github.com/cisco-open/go-lanai/pkg/grpc"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/access"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	errorutils "github.com/cisco-open/go-lanai/pkg/utils/error"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/apptest"
+	"github.com/cisco-open/go-lanai/test/sectest"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"go.uber.org/fx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+/*************************
+	Setup
+ *************************/
+
+const (
+	TestUser       = "test-user"
+	TestGuest      = "test-guest"
+	TestPermission = "HEALTH_CHECK"
+	SvcPanic       = "panic"
+	SvcDenied      = "denied"
+	SvcNotFound    = "not-found"
+	SvcError       = "error"
+)
+
+var ErrTestNotFound = errorutils.NewCodedError(1<<24, "service not found")
+
+type testHealthServer struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (s testHealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	switch req.Service {
+	case SvcPanic:
+		panic("oops")
+	case SvcDenied:
+		return nil, security.NewAccessDeniedError("denied by service")
+	case SvcNotFound:
+		return nil, ErrTestNotFound
+	case SvcError:
+		return nil, fmt.Errorf("internal details")
+	}
+	if username, e := security.GetUsername(security.Get(ctx)); e != nil || username != TestUser {
+		return nil, fmt.Errorf("unexpected security context")
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func provideTestService() *lanaigrpc.Service {
+	return &lanaigrpc.Service{
+		Desc: &healthpb.Health_ServiceDesc,
+		Impl: testHealthServer{},
+		AccessControl: map[string]access.ControlFunc{
+			"Check": access.HasPermissions(TestPermission),
+		},
+	}
+}
+
+func provideTokenStoreReader() oauth2.TokenStoreReader {
+	return sectest.NewMockedTokenStoreReader(map[string]*sectest.MockedAccountProperties{
+		TestUser:  {UserId: "user-id", Username: TestUser, Perms: []string{TestPermission}},
+		TestGuest: {UserId: "guest-id", Username: TestGuest},
+	}, nil)
+}
+
+type testDI struct {
+	fx.In
+	Registrar *lanaigrpc.Registrar
+}
+
+/*************************
+	Tests
+ *************************/
+
+func TestGrpcServer(t *testing.T) {
+	var di testDI
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		apptest.WithModules(lanaigrpc.Module),
+		apptest.WithProperties("grpc.server.port: 0"),
+		apptest.WithDI(&di),
+		apptest.WithFxOptions(
+			fx.Provide(provideTokenStoreReader),
+			lanaigrpc.FxServiceProviders(provideTestService),
+			fx.Invoke(func(r *lanaigrpc.Registrar) {
+				r.MustRegister(lanaigrpc.ErrorCodeMapping{Error: ErrTestNotFound, Code: codes.NotFound})
+			}),
+		),
+		test.GomegaSubTest(SubTestAuthenticated(&di), "TestAuthenticated"),
+		test.GomegaSubTest(SubTestUnauthenticated(&di), "TestUnauthenticated"),
+		test.GomegaSubTest(SubTestPermissionDenied(&di), "TestPermissionDenied"),
+		test.GomegaSubTest(SubTestErrorMapping(&di), "TestErrorMapping"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestAuthenticated(di *testDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		client := newTestClient(ctx, g, di)
+		resp, e := client.Check(withToken(ctx, TestUser), &healthpb.HealthCheckRequest{})
+		g.Expect(e).To(Succeed(), "authenticated call should succeed")
+		g.Expect(resp.Status).To(Equal(healthpb.HealthCheckResponse_SERVING), "response should be correct")
+	}
+}
+
+func SubTestUnauthenticated(di *testDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		client := newTestClient(ctx, g, di)
+		_, e := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		assertStatusCode(g, e, codes.Unauthenticated)
+
+		invalidCtx := metadata.AppendToOutgoingContext(ctx, lanaigrpc.MetadataKeyAuthorization, "Bearer invalid-token")
+		_, e = client.Check(invalidCtx, &healthpb.HealthCheckRequest{})
+		assertStatusCode(g, e, codes.Unauthenticated)
+
+		// Watch is not explicitly configured, default access requires authentication
+		stream, e := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+		g.Expect(e).To(Succeed(), "opening stream should not fail")
+		_, e = stream.Recv()
+		assertStatusCode(g, e, codes.Unauthenticated)
+	}
+}
+
+func SubTestPermissionDenied(di *testDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		client := newTestClient(ctx, g, di)
+		_, e := client.Check(withToken(ctx, TestGuest), &healthpb.HealthCheckRequest{})
+		assertStatusCode(g, e, codes.PermissionDenied)
+	}
+}
+
+func SubTestErrorMapping(di *testDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		client := newTestClient(ctx, g, di)
+		ctx = withToken(ctx, TestUser)
+		_, e := client.Check(ctx, &healthpb.HealthCheckRequest{Service: SvcDenied})
+		assertStatusCode(g, e, codes.PermissionDenied)
+		_, e = client.Check(ctx, &healthpb.HealthCheckRequest{Service: SvcNotFound})
+		assertStatusCode(g, e, codes.NotFound)
+		_, e = client.Check(ctx, &healthpb.HealthCheckRequest{Service: SvcPanic})
+		assertStatusCode(g, e, codes.Internal)
+		_, e = client.Check(ctx, &healthpb.HealthCheckRequest{Service: SvcError})
+		assertStatusCode(g, e, codes.Internal)
+		g.Expect(status.Convert(e).Message()).NotTo(ContainSubstring("internal details"), "unknown error should be sanitized")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func newTestClient(ctx context.Context, g *gomega.WithT, di *testDI) healthpb.HealthClient {
+	conn, e := grpc.DialContext(ctx, fmt.Sprintf("localhost:%d", di.Registrar.ServerPort()),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	g.Expect(e).To(Succeed(), "dial should not fail")
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+	return healthpb.NewHealthClient(conn)
+}
+
+func withToken(ctx context.Context, username string) context.Context {
+	token := &sectest.MockedToken{
+		MockedTokenInfo: sectest.MockedTokenInfo{
+			ClientID: "test-client",
+			UName:    username,
+		},
+		ExpTime: time.Now().Add(time.Hour),
+		IssTime: time.Now(),
+	}
+	return metadata.AppendToOutgoingContext(ctx, lanaigrpc.MetadataKeyAuthorization, "Bearer "+token.Value())
+}
+
+func assertStatusCode(g *gomega.WithT, err error, expected codes.Code) {
+	g.Expect(err).To(HaveOccurred(), "call should fail")
+	g.Expect(status.Code(err)).To(Equal(expected), "status code should be %v", expected)
+}
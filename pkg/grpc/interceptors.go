@@ -0,0 +1,129 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/utils/order"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"runtime/debug"
+	"time"
+)
+
+const (
+	LogKeyGrpc       = "grpc"
+	LogKeyGrpcMethod = "method"
+	LogKeyGrpcCode   = "code"
+	LogKeyGrpcPeer   = "peer"
+	LogKeyGrpcError  = "error"
+)
+
+// Interceptor orders. Lower order is invoked first
+const (
+	InterceptorOrderTracing = order.Highest + iota*100
+	InterceptorOrderLogging
+	InterceptorOrderErrorHandling
+	InterceptorOrderRecovery
+	InterceptorOrderAuthentication
+	InterceptorOrderAccessControl
+)
+
+// ContextServerStream wraps grpc.ServerStream and overrides its Context.
+// Stream interceptors use it to pass modified context to downstream handlers.
+type ContextServerStream struct {
+	grpc.ServerStream
+	Ctx context.Context
+}
+
+func (s *ContextServerStream) Context() context.Context {
+	return s.Ctx
+}
+
+/**********************
+	Logging
+ **********************/
+
+func newLoggingInterceptor(props LoggingProperties) *Interceptor {
+	return &Interceptor{
+		Order: InterceptorOrderLogging,
+		Unary: func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			start := time.Now()
+			resp, e := handler(ctx, req)
+			logRPC(ctx, props.Level, info.FullMethod, start, e)
+			return resp, e
+		},
+		Stream: func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			start := time.Now()
+			e := handler(srv, ss)
+			logRPC(ss.Context(), props.Level, info.FullMethod, start, e)
+			return e
+		},
+	}
+}
+
+func logRPC(ctx context.Context, lvl log.LoggingLevel, method string, start time.Time, err error) {
+	if lvl == log.LevelOff {
+		return
+	}
+	code := status.Code(err)
+	var peerAddr, errMsg string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		peerAddr = p.Addr.String()
+	}
+	if err != nil {
+		errMsg = status.Convert(err).Message()
+	}
+	latency := time.Since(start).Truncate(time.Microsecond)
+	entry := map[string]interface{}{
+		LogKeyGrpcMethod: method,
+		LogKeyGrpcCode:   code.String(),
+		LogKeyGrpcPeer:   peerAddr,
+		LogKeyGrpcError:  errMsg,
+	}
+	msg := fmt.Sprintf("[gRPC] %-16s | %10v | %s %s", code.String(), latency, method, errMsg)
+	logger.WithContext(ctx).WithLevel(lvl).WithKV(LogKeyGrpc, entry).Printf(msg)
+}
+
+/**********************
+	Recovery
+ **********************/
+
+func newRecoveryInterceptor() *Interceptor {
+	return &Interceptor{
+		Order: InterceptorOrderRecovery,
+		Unary: func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+			defer recoverRPC(ctx, info.FullMethod, &err)
+			return handler(ctx, req)
+		},
+		Stream: func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+			defer recoverRPC(ss.Context(), info.FullMethod, &err)
+			return handler(srv, ss)
+		},
+	}
+}
+
+func recoverRPC(ctx context.Context, method string, err *error) {
+	if r := recover(); r != nil {
+		logger.WithContext(ctx).Errorf("panic recovered in gRPC method [%s]: %v\n%s", method, r, debug.Stack())
+		*err = status.Errorf(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	appconfig "github.com/cisco-open/go-lanai/pkg/appconfig/init"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/certs"
+	"github.com/cisco-open/go-lanai/pkg/discovery"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/tokenauth"
+	"github.com/opentracing/opentracing-go"
+	"go.uber.org/fx"
+)
+
+var logger = log.New("gRPC")
+
+const (
+	FxGroupServices     = "grpc_services"
+	FxGroupInterceptors = "grpc_interceptors"
+	FxGroupCustomizers  = "grpc_customizers"
+)
+
+var Module = &bootstrap.Module{
+	Name:       "grpc",
+	Precedence: bootstrap.GrpcPrecedence,
+	Options: []fx.Option{
+		appconfig.FxEmbeddedDefaults(defaultConfigFS),
+		fx.Provide(BindServerProperties, NewRegistrar,
+			fx.Annotate(newDiscoveryCustomizer, fx.ResultTags(fmt.Sprintf(`group:"%s"`, discovery.FxGroup))),
+		),
+		fx.Invoke(setup),
+	},
+}
+
+// Use Allow service to include this module in main()
+func Use() {
+	bootstrap.Register(Module)
+}
+
+// FxServiceProviders takes providers of *Service and wrap them with FxGroupServices
+func FxServiceProviders(providers ...interface{}) fx.Option {
+	return fx.Provide(groupedProviders(FxGroupServices, providers)...)
+}
+
+// FxInterceptorProviders takes providers of *Interceptor and wrap them with FxGroupInterceptors
+func FxInterceptorProviders(providers ...interface{}) fx.Option {
+	return fx.Provide(groupedProviders(FxGroupInterceptors, providers)...)
+}
+
+// FxCustomizerProviders takes providers of Customizer and wrap them with FxGroupCustomizers
+func FxCustomizerProviders(providers ...interface{}) fx.Option {
+	return fx.Provide(groupedProviders(FxGroupCustomizers, providers)...)
+}
+
+func groupedProviders(group string, providers []interface{}) []interface{} {
+	annotated := make([]interface{}, len(providers))
+	for i, t := range providers {
+		annotated[i] = fx.Annotated{
+			Group:  group,
+			Target: t,
+		}
+	}
+	return annotated
+}
+
+/**************************
+	Setup
+***************************/
+
+// newDiscoveryCustomizer registers gRPC port as discovery.InstanceMetaKeyGrpcPort, so clients can resolve the gRPC
+// endpoint of each instance. Random port (0) is not registered.
+func newDiscoveryCustomizer(props ServerProperties) discovery.ServiceRegistrationCustomizer {
+	return discovery.ServiceRegistrationCustomizerFunc(func(_ context.Context, reg discovery.ServiceRegistration) {
+		if props.Port > 0 {
+			reg.SetMeta(discovery.InstanceMetaKeyGrpcPort, props.Port)
+		}
+	})
+}
+
+type initDI struct {
+	fx.In
+	Registrar        *Registrar
+	Properties       ServerProperties
+	Services         []*Service              `group:"grpc_services"`
+	Interceptors     []*Interceptor          `group:"grpc_interceptors"`
+	Customizers      []Customizer            `group:"grpc_customizers"`
+	TokenStoreReader oauth2.TokenStoreReader `optional:"true"`
+	Tracer           opentracing.Tracer      `optional:"true"`
+	CertsManager     certs.Manager           `optional:"true"`
+}
+
+func setup(lc fx.Lifecycle, di initDI) {
+	di.Registrar.MustRegister(newRecoveryInterceptor())
+	if di.Properties.Logging.Enabled {
+		di.Registrar.MustRegister(newLoggingInterceptor(di.Properties.Logging))
+	}
+	if di.Tracer != nil {
+		di.Registrar.MustRegister(newTracingInterceptor(di.Tracer))
+	}
+	if di.TokenStoreReader != nil {
+		authenticator := tokenauth.NewAuthenticator(func(opt *tokenauth.AuthenticatorOption) {
+			opt.TokenStoreReader = di.TokenStoreReader
+		})
+		di.Registrar.MustRegister(NewAuthenticationInterceptor(authenticator))
+	}
+	di.Registrar.MustRegister(NewTLSCustomizer(di.Properties, di.CertsManager))
+	di.Registrar.MustRegister(di.Services, di.Interceptors, di.Customizers)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return di.Registrar.Run(ctx)
+		},
+		OnStop: func(ctx context.Context) error {
+			return di.Registrar.Stop(ctx)
+		},
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"embed"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"github.com/pkg/errors"
+)
+
+const (
+	PropertiesPrefix = "grpc.server"
+)
+
+//go:embed defaults-grpc.yml
+var defaultConfigFS embed.FS
+
+type ServerProperties struct {
+	Port int `json:"port"`
+	// Reflection enables gRPC server reflection service
+	Reflection bool `json:"reflection"`
+	// TLS configures server TLS. See web.TLSProperties
+	TLS     web.TLSProperties `json:"tls"`
+	Logging LoggingProperties `json:"logging"`
+}
+
+type LoggingProperties struct {
+	Enabled bool             `json:"enabled"`
+	Level   log.LoggingLevel `json:"level"`
+}
+
+// NewServerProperties create a ServerProperties with default values
+func NewServerProperties() *ServerProperties {
+	return &ServerProperties{
+		Port: 9090,
+		TLS: web.TLSProperties{
+			ClientAuth: web.ClientAuthNone,
+		},
+		Logging: LoggingProperties{
+			Enabled: true,
+			Level:   log.LevelInfo,
+		},
+	}
+}
+
+// BindServerProperties create and bind a ServerProperties using default prefix
+func BindServerProperties(ctx *bootstrap.ApplicationContext) ServerProperties {
+	props := NewServerProperties()
+	if err := ctx.Config().Bind(props, PropertiesPrefix); err != nil {
+		panic(errors.Wrap(err, "failed to bind gRPC ServerProperties"))
+	}
+	return *props
+}
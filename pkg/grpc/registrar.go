@@ -0,0 +1,267 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security/access"
+	"github.com/cisco-open/go-lanai/pkg/utils/order"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+	"net"
+	"sort"
+)
+
+// Service is a gRPC service implementation to be registered with the server.
+// AccessControl is keyed by method name without service prefix, e.g. "GetUser".
+// Methods without an entry use DefaultAccess, or Registrar's default access if DefaultAccess is nil.
+type Service struct {
+	Desc          *grpc.ServiceDesc
+	Impl          interface{}
+	AccessControl map[string]access.ControlFunc
+	DefaultAccess access.ControlFunc
+}
+
+// Interceptor groups unary and stream server interceptors of same purpose.
+// Interceptors with lower Order are invoked first. Either Unary or Stream can be nil.
+type Interceptor struct {
+	Order  int
+	Unary  grpc.UnaryServerInterceptor
+	Stream grpc.StreamServerInterceptor
+}
+
+// Customizer is invoked before the gRPC server is created.
+// Customizers can register additional interceptors, services and server options to the Registrar
+type Customizer interface {
+	Customize(ctx context.Context, r *Registrar) error
+}
+
+// Registrar collects gRPC services, interceptors and server options, and manages lifecycle of the gRPC server
+type Registrar struct {
+	properties    ServerProperties
+	services      []*Service
+	interceptors  []*Interceptor
+	customizers   []Customizer
+	errorMappings []ErrorCodeMapping
+	options       []grpc.ServerOption
+	tlsConfig     *tls.Config
+	defaultAccess access.ControlFunc
+	server        *grpc.Server
+	listener      net.Listener
+}
+
+func NewRegistrar(props ServerProperties) *Registrar {
+	return &Registrar{
+		properties:    props,
+		defaultAccess: access.Authenticated,
+	}
+}
+
+// Register accepts *Service, *Interceptor, Customizer, ErrorCodeMapping and grpc.ServerOption, or slices of them
+func (r *Registrar) Register(items ...interface{}) error {
+	if r.server != nil {
+		return fmt.Errorf("cannot register after gRPC server is initialized")
+	}
+	for _, item := range items {
+		if e := r.register(item); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// MustRegister is the panicking version of Register
+func (r *Registrar) MustRegister(items ...interface{}) {
+	if e := r.Register(items...); e != nil {
+		panic(e)
+	}
+}
+
+// SetDefaultAccess set access.ControlFunc for methods that don't have explicit access control.
+// Default is access.Authenticated, methods that should be public have to be configured with access.PermitAll explicitly
+func (r *Registrar) SetDefaultAccess(cf access.ControlFunc) {
+	r.defaultAccess = cf
+}
+
+// SetServerTLSConfig set TLS config of the server. Should be called before server is initialized, e.g. in Customizer
+func (r *Registrar) SetServerTLSConfig(cfg *tls.Config) error {
+	if r.server != nil {
+		return fmt.Errorf("cannot set TLS config after gRPC server is initialized")
+	}
+	r.tlsConfig = cfg
+	return nil
+}
+
+// Server returns the underlying grpc.Server. Returns nil before Run
+func (r *Registrar) Server() *grpc.Server {
+	return r.server
+}
+
+// ServerPort returns the actual listening port. Returns 0 if server is not running
+func (r *Registrar) ServerPort() int {
+	if r.listener == nil {
+		return 0
+	}
+	if addr, ok := r.listener.Addr().(*net.TCPAddr); ok {
+		return addr.Port
+	}
+	return 0
+}
+
+// Run initializes and starts the gRPC server in background
+func (r *Registrar) Run(ctx context.Context) (err error) {
+	if err = r.initialize(ctx); err != nil {
+		return
+	}
+	if r.listener, err = net.Listen("tcp", fmt.Sprintf(":%d", r.properties.Port)); err != nil {
+		return
+	}
+	go func() {
+		if e := r.server.Serve(r.listener); e != nil && !errors.Is(e, grpc.ErrServerStopped) {
+			logger.Errorf("gRPC server stopped with error: %v", e)
+		}
+	}()
+	logger.WithContext(ctx).Infof("gRPC server started on port %d", r.ServerPort())
+	return nil
+}
+
+// Stop gracefully stops the gRPC server. Pending RPCs are terminated when given context is done
+func (r *Registrar) Stop(ctx context.Context) error {
+	if r.server == nil {
+		return nil
+	}
+	done := make(chan struct{})
+	go func() {
+		r.server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		r.server.Stop()
+	}
+	logger.WithContext(ctx).Infof("gRPC server stopped")
+	return nil
+}
+
+func (r *Registrar) register(item interface{}) error {
+	switch v := item.(type) {
+	case *Service:
+		if v.Desc == nil || v.Impl == nil {
+			return fmt.Errorf("gRPC service registration requires both ServiceDesc and implementation")
+		}
+		r.services = append(r.services, v)
+	case []*Service:
+		for _, svc := range v {
+			if e := r.register(svc); e != nil {
+				return e
+			}
+		}
+	case *Interceptor:
+		r.interceptors = append(r.interceptors, v)
+	case []*Interceptor:
+		r.interceptors = append(r.interceptors, v...)
+	case Customizer:
+		r.customizers = append(r.customizers, v)
+	case []Customizer:
+		r.customizers = append(r.customizers, v...)
+	case ErrorCodeMapping:
+		r.errorMappings = append(r.errorMappings, v)
+	case []ErrorCodeMapping:
+		r.errorMappings = append(r.errorMappings, v...)
+	case grpc.ServerOption:
+		r.options = append(r.options, v)
+	case []grpc.ServerOption:
+		r.options = append(r.options, v...)
+	default:
+		return fmt.Errorf("unsupported gRPC registration type: %T", item)
+	}
+	return nil
+}
+
+func (r *Registrar) initialize(ctx context.Context) error {
+	order.SortStable(r.customizers, order.OrderedFirstCompare)
+	for _, c := range r.customizers {
+		if e := c.Customize(ctx, r); e != nil {
+			return e
+		}
+	}
+
+	r.interceptors = append(r.interceptors,
+		newErrorHandlingInterceptor(r.errorMappings),
+		newAccessControlInterceptor(r.accessControls(), r.defaultAccess),
+	)
+	sort.SliceStable(r.interceptors, func(i, j int) bool {
+		return r.interceptors[i].Order < r.interceptors[j].Order
+	})
+	unary := make([]grpc.UnaryServerInterceptor, 0, len(r.interceptors))
+	stream := make([]grpc.StreamServerInterceptor, 0, len(r.interceptors))
+	for _, i := range r.interceptors {
+		if i.Unary != nil {
+			unary = append(unary, i.Unary)
+		}
+		if i.Stream != nil {
+			stream = append(stream, i.Stream)
+		}
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+	if r.tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(r.tlsConfig)))
+	}
+	r.server = grpc.NewServer(append(opts, r.options...)...)
+	for _, svc := range r.services {
+		r.server.RegisterService(svc.Desc, svc.Impl)
+	}
+	if r.properties.Reflection {
+		reflection.Register(r.server)
+	}
+	return nil
+}
+
+// accessControls resolves access.ControlFunc of all registered methods, keyed by full method name.
+func (r *Registrar) accessControls() map[string]access.ControlFunc {
+	acl := map[string]access.ControlFunc{}
+	for _, svc := range r.services {
+		methods := make([]string, 0, len(svc.Desc.Methods)+len(svc.Desc.Streams))
+		for _, m := range svc.Desc.Methods {
+			methods = append(methods, m.MethodName)
+		}
+		for _, s := range svc.Desc.Streams {
+			methods = append(methods, s.StreamName)
+		}
+		for _, name := range methods {
+			cf, ok := svc.AccessControl[name]
+			switch {
+			case ok:
+			case svc.DefaultAccess != nil:
+				cf = svc.DefaultAccess
+			default:
+				cf = r.defaultAccess
+			}
+			acl[fmt.Sprintf("/%s/%s", svc.Desc.ServiceName, name)] = cf
+		}
+	}
+	return acl
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/access"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/tokenauth"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"strings"
+)
+
+const (
+	MetadataKeyAuthorization = "authorization"
+	bearerPrefix             = "bearer "
+)
+
+/**********************
+	Authentication
+ **********************/
+
+// NewAuthenticationInterceptor returns Interceptor that authenticates bearer token found in "authorization" metadata
+// using given security.Authenticator (typically tokenauth.Authenticator).
+// Calls without bearer token proceed as anonymous and are subject to access control.
+// Certificate-bound tokens are verified against the peer's TLS client certificate; DPoP-bound tokens are rejected.
+func NewAuthenticationInterceptor(authenticator security.Authenticator) *Interceptor {
+	return &Interceptor{
+		Order: InterceptorOrderAuthentication,
+		Unary: func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			ctx, e := authenticate(ctx, authenticator)
+			if e != nil {
+				return nil, e
+			}
+			return handler(ctx, req)
+		},
+		Stream: func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			ctx, e := authenticate(ss.Context(), authenticator)
+			if e != nil {
+				return e
+			}
+			return handler(srv, &ContextServerStream{ServerStream: ss, Ctx: ctx})
+		},
+	}
+}
+
+func authenticate(ctx context.Context, authenticator security.Authenticator) (context.Context, error) {
+	mc := utils.MakeMutableContext(ctx)
+	token := bearerToken(ctx)
+	if token == "" {
+		return mc, nil
+	}
+	candidate := tokenauth.BearerToken{
+		Token:      token,
+		DetailsMap: map[string]interface{}{},
+	}
+	auth, e := authenticator.Authenticate(mc, &candidate)
+	if e != nil {
+		if !errors.Is(e, oauth2.ErrorTypeOAuth2) {
+			e = oauth2.NewInvalidAccessTokenError(e)
+		}
+		return nil, e
+	}
+	// gRPC has no DPoP scheme, DPoP-bound tokens are rejected.
+	if e := tokenauth.VerifyCertificateBinding(auth, peerCertificates(ctx)); e != nil {
+		return nil, e
+	}
+	if e := tokenauth.VerifyDPoPBinding(ctx, nil, nil, auth, token, false); e != nil {
+		return nil, e
+	}
+	if auth != nil {
+		if e := security.Set(mc, auth); e != nil {
+			return nil, e
+		}
+	}
+	return mc, nil
+}
+
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, v := range md.Get(MetadataKeyAuthorization) {
+		if len(v) > len(bearerPrefix) && strings.EqualFold(v[:len(bearerPrefix)], bearerPrefix) {
+			return strings.TrimSpace(v[len(bearerPrefix):])
+		}
+	}
+	return ""
+}
+
+func peerCertificates(ctx context.Context) []*x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		return info.State.PeerCertificates
+	}
+	return nil
+}
+
+/**********************
+	Access Control
+ **********************/
+
+func newAccessControlInterceptor(acl map[string]access.ControlFunc, defaultAccess access.ControlFunc) *Interceptor {
+	decide := func(ctx context.Context, method string) error {
+		cf, ok := acl[method]
+		if !ok {
+			cf = defaultAccess
+		}
+		granted, reason := cf(security.Get(ctx))
+		switch {
+		case granted:
+			return nil
+		case reason != nil:
+			return reason
+		default:
+			return security.NewAccessDeniedError("access denied")
+		}
+	}
+	return &Interceptor{
+		Order: InterceptorOrderAccessControl,
+		Unary: func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if e := decide(ctx, info.FullMethod); e != nil {
+				return nil, e
+			}
+			return handler(ctx, req)
+		},
+		Stream: func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if e := decide(ss.Context(), info.FullMethod); e != nil {
+				return e
+			}
+			return handler(srv, ss)
+		},
+	}
+}
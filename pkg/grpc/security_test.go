@@ -0,0 +1,165 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	lanaigrpc "github.com/cisco-open/go-lanai/pkg/grpc"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+/*************************
+	Tests
+ *************************/
+
+func TestAuthenticationInterceptor(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestUnboundToken(), "TestUnboundToken"),
+		test.GomegaSubTest(SubTestCertBoundTokenWithSameCert(), "TestCertBoundTokenWithSameCert"),
+		test.GomegaSubTest(SubTestCertBoundTokenWithDifferentCert(), "TestCertBoundTokenWithDifferentCert"),
+		test.GomegaSubTest(SubTestCertBoundTokenWithoutTLS(), "TestCertBoundTokenWithoutTLS"),
+		test.GomegaSubTest(SubTestDPoPBoundToken(), "TestDPoPBoundToken"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestUnboundToken() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		e := invokeWithBoundToken(ctx, oauth2.MapClaims{}, nil)
+		g.Expect(e).To(Succeed(), "unbound token should be accepted without client certificate")
+	}
+}
+
+func SubTestCertBoundTokenWithSameCert() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		cert := newTestCert(g)
+		e := invokeWithBoundToken(ctx, certBoundClaims(cert), cert)
+		g.Expect(e).To(Succeed(), "certificate-bound token should be accepted with the same client certificate")
+	}
+}
+
+func SubTestCertBoundTokenWithDifferentCert() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		e := invokeWithBoundToken(ctx, certBoundClaims(newTestCert(g)), newTestCert(g))
+		assertInvalidToken(g, e)
+	}
+}
+
+func SubTestCertBoundTokenWithoutTLS() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		e := invokeWithBoundToken(ctx, certBoundClaims(newTestCert(g)), nil)
+		assertInvalidToken(g, e)
+	}
+}
+
+func SubTestDPoPBoundToken() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		claims := oauth2.MapClaims{
+			oauth2.ClaimConfirmation: map[string]interface{}{oauth2.ClaimJwkThumbprint: "test-jkt"},
+		}
+		e := invokeWithBoundToken(ctx, claims, newTestCert(g))
+		assertInvalidToken(g, e)
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+type testBoundTokenAuthenticator struct {
+	claims oauth2.Claims
+}
+
+func (a testBoundTokenAuthenticator) Authenticate(_ context.Context, candidate security.Candidate) (security.Authentication, error) {
+	token := oauth2.NewDefaultAccessToken(candidate.Credentials().(string))
+	token.SetClaims(a.claims)
+	return oauth2.NewAuthentication(func(opt *oauth2.AuthOption) {
+		opt.Token = token
+	}), nil
+}
+
+// invokeWithBoundToken invokes unary authentication interceptor with a token carrying given claims.
+// If peerCert is not nil, the call is made as if the client presented it over mutual TLS.
+func invokeWithBoundToken(ctx context.Context, claims oauth2.Claims, peerCert *x509.Certificate) error {
+	interceptor := lanaigrpc.NewAuthenticationInterceptor(testBoundTokenAuthenticator{claims: claims})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(lanaigrpc.MetadataKeyAuthorization, "Bearer test-token"))
+	p := &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50051}}
+	if peerCert != nil {
+		p.AuthInfo = credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{peerCert}}}
+	}
+	ctx = peer.NewContext(ctx, p)
+	_, e := interceptor.Unary(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		if _, ok := security.Get(ctx).(oauth2.Authentication); !ok {
+			return nil, security.NewAuthenticationError("security context is not set")
+		}
+		return nil, nil
+	})
+	return e
+}
+
+func certBoundClaims(cert *x509.Certificate) oauth2.Claims {
+	return oauth2.MapClaims{
+		oauth2.ClaimConfirmation: map[string]interface{}{
+			oauth2.ClaimCertThumbprint: oauth2.CertificateThumbprint(cert),
+		},
+	}
+}
+
+func newTestCert(g *gomega.WithT) *x509.Certificate {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(e).To(Succeed(), "generating key should not fail")
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "test-client"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	raw, e := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, key.Public(), key)
+	g.Expect(e).To(Succeed(), "creating certificate should not fail")
+	cert, e := x509.ParseCertificate(raw)
+	g.Expect(e).To(Succeed(), "parsing certificate should not fail")
+	return cert
+}
+
+func assertInvalidToken(g *gomega.WithT, err error) {
+	g.Expect(err).To(HaveOccurred(), "bound token should be rejected")
+	var oauthErr *oauth2.OAuth2Error
+	g.Expect(errors.As(err, &oauthErr)).To(BeTrue(), "error should be OAuth2Error")
+	g.Expect(oauthErr.TranslateErrorCode()).To(Equal(oauth2.ErrorTranslationInvalidToken), "error code should be invalid_token")
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/certs"
+	"github.com/cisco-open/go-lanai/pkg/web"
+)
+
+// TLSCustomizer implements Customizer. It enables server TLS based on web.TLSProperties using certificate source from certs.Manager
+type TLSCustomizer struct {
+	props    web.TLSProperties
+	certsMgr certs.Manager
+}
+
+func NewTLSCustomizer(props ServerProperties, certsMgr certs.Manager) *TLSCustomizer {
+	return &TLSCustomizer{
+		props:    props.TLS,
+		certsMgr: certsMgr,
+	}
+}
+
+func (c *TLSCustomizer) Customize(ctx context.Context, r *Registrar) error {
+	if !c.props.Enabled {
+		return nil
+	}
+	if c.certsMgr == nil {
+		return fmt.Errorf("gRPC server TLS is enabled, but certificate manager is not available")
+	}
+	tlsCfg, e := web.NewServerTLSConfig(ctx, c.props, c.certsMgr)
+	if e != nil {
+		return fmt.Errorf("unable to initialize gRPC server TLS: %v", e)
+	}
+	logger.WithContext(ctx).Infof("gRPC server TLS enabled with client-auth [%s]", c.props.ClientAuth)
+	return r.SetServerTLSConfig(tlsCfg)
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/tracing"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+const tracingOpName = "grpc"
+
+// MetadataCarrier implements opentracing.TextMapReader and opentracing.TextMapWriter on top of gRPC metadata
+type MetadataCarrier metadata.MD
+
+func (c MetadataCarrier) Set(key, val string) {
+	key = strings.ToLower(key)
+	c[key] = append(c[key], val)
+}
+
+func (c MetadataCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, vals := range c {
+		for _, v := range vals {
+			if e := handler(k, v); e != nil {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+// SpanCode returns tracing.SpanOption that tags the span with gRPC status code of given error
+func SpanCode(err error) tracing.SpanOption {
+	code := status.Code(err)
+	return func(span opentracing.Span) {
+		span.SetTag("grpc.code", code.String())
+		if err != nil {
+			ext.Error.Set(span, true)
+		}
+	}
+}
+
+func newTracingInterceptor(tracer opentracing.Tracer) *Interceptor {
+	return &Interceptor{
+		Order: InterceptorOrderTracing,
+		Unary: func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			ctx = startServerSpan(ctx, tracer, info.FullMethod)
+			resp, e := handler(ctx, req)
+			tracing.WithTracer(tracer).WithOptions(SpanCode(e)).Finish(ctx)
+			return resp, e
+		},
+		Stream: func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			ctx := startServerSpan(ss.Context(), tracer, info.FullMethod)
+			e := handler(srv, &ContextServerStream{ServerStream: ss, Ctx: ctx})
+			tracing.WithTracer(tracer).WithOptions(SpanCode(e)).Finish(ctx)
+			return e
+		},
+	}
+}
+
+func startServerSpan(ctx context.Context, tracer opentracing.Tracer, method string) context.Context {
+	op := tracing.WithTracer(tracer).
+		WithOpName(tracingOpName+" "+method).
+		WithOptions(
+			tracing.SpanKind(ext.SpanKindRPCServerEnum),
+			tracing.SpanComponent(tracingOpName),
+			tracing.SpanTag("grpc.method", method),
+		)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if spanCtx, e := tracer.Extract(opentracing.TextMap, MetadataCarrier(md)); e == nil {
+			op = op.WithStartOptions(ext.RPCServerOption(spanCtx))
+		}
+	}
+	return op.NewSpanOrDescendant(ctx)
+}
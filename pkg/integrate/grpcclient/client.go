@@ -0,0 +1,119 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcclient
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/discovery"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	LoadBalancingRoundRobin = "round_robin"
+	LoadBalancingPickFirst  = "pick_first"
+)
+
+type Client interface {
+	// DialService creates a connection to the given service. Addresses are resolved via discovery.Client and
+	// load balanced according to ClientOption.LoadBalancingPolicy.
+	// Access token of current security context is forwarded to the service if no "authorization" metadata is set.
+	DialService(ctx context.Context, serviceName string, opts ...ClientOptions) (*grpc.ClientConn, error)
+	// Dial creates a connection to the given target, e.g. "localhost:9090" or "dns:///my-host:9090"
+	Dial(ctx context.Context, target string, opts ...ClientOptions) (*grpc.ClientConn, error)
+}
+
+type ClientOptions func(opt *ClientOption)
+
+type ClientOption struct {
+	SDClient discovery.Client
+	// Selector is used to filter instances during service discovery. Default: discovery.InstanceIsHealthy()
+	Selector discovery.InstanceMatcher
+	// LoadBalancingPolicy is the gRPC load balancing policy name. Default: "round_robin"
+	LoadBalancingPolicy string
+	// TLSConfig enables transport security. Connections are insecure if nil
+	TLSConfig          *tls.Config
+	UnaryInterceptors  []grpc.UnaryClientInterceptor
+	StreamInterceptors []grpc.StreamClientInterceptor
+	DialOptions        []grpc.DialOption
+}
+
+type client struct {
+	defaults ClientOption
+}
+
+func NewClient(opts ...ClientOptions) Client {
+	opt := ClientOption{
+		Selector:            discovery.InstanceIsHealthy(),
+		LoadBalancingPolicy: LoadBalancingRoundRobin,
+	}
+	for _, f := range opts {
+		f(&opt)
+	}
+	return &client{
+		defaults: opt,
+	}
+}
+
+func (c *client) DialService(ctx context.Context, serviceName string, opts ...ClientOptions) (*grpc.ClientConn, error) {
+	opts = append([]ClientOptions{func(opt *ClientOption) {
+		opt.UnaryInterceptors = append(opt.UnaryInterceptors, tokenPassthroughUnaryInterceptor())
+		opt.StreamInterceptors = append(opt.StreamInterceptors, tokenPassthroughStreamInterceptor())
+	}}, opts...)
+	opt := c.option(opts)
+	if opt.SDClient == nil {
+		return nil, fmt.Errorf("cannot dial gRPC service [%s]: service discovery client is not configured", serviceName)
+	}
+	dialOpts := append(dialOptions(&opt), grpc.WithResolvers(NewSDResolverBuilder(opt.SDClient, opt.Selector)))
+	return grpc.DialContext(ctx, fmt.Sprintf("%s:///%s", SDScheme, serviceName), dialOpts...)
+}
+
+func (c *client) Dial(ctx context.Context, target string, opts ...ClientOptions) (*grpc.ClientConn, error) {
+	opt := c.option(opts)
+	return grpc.DialContext(ctx, target, dialOptions(&opt)...)
+}
+
+// option returns a copy of default option with given options applied
+func (c *client) option(opts []ClientOptions) ClientOption {
+	opt := c.defaults
+	opt.UnaryInterceptors = append([]grpc.UnaryClientInterceptor{}, c.defaults.UnaryInterceptors...)
+	opt.StreamInterceptors = append([]grpc.StreamClientInterceptor{}, c.defaults.StreamInterceptors...)
+	opt.DialOptions = append([]grpc.DialOption{}, c.defaults.DialOptions...)
+	for _, f := range opts {
+		f(&opt)
+	}
+	return opt
+}
+
+func dialOptions(opt *ClientOption) []grpc.DialOption {
+	creds := insecure.NewCredentials()
+	if opt.TLSConfig != nil {
+		creds = credentials.NewTLS(opt.TLSConfig)
+	}
+	ret := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(opt.UnaryInterceptors...),
+		grpc.WithChainStreamInterceptor(opt.StreamInterceptors...),
+	}
+	if opt.LoadBalancingPolicy != "" {
+		ret = append(ret, grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingConfig":[{"%s":{}}]}`, opt.LoadBalancingPolicy)))
+	}
+	return append(ret, opt.DialOptions...)
+}
@@ -0,0 +1,194 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcclient_test
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/discovery"
+	"github.com/cisco-open/go-lanai/pkg/integrate/grpcclient"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/apptest"
+	"github.com/cisco-open/go-lanai/test/sdtest"
+	"github.com/cisco-open/go-lanai/test/sectest"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"go.uber.org/fx"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+/*************************
+	Setup
+ *************************/
+
+const (
+	TestServiceName = "test-service"
+	TestAccessToken = "test-access-token"
+)
+
+type testHealthServer struct {
+	healthpb.UnimplementedHealthServer
+	mtx    sync.Mutex
+	count  int
+	tokens []string
+}
+
+func (s *testHealthServer) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.count++
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		s.tokens = append(s.tokens, md.Get("authorization")...)
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+type testServer struct {
+	*grpc.Server
+	impl *testHealthServer
+	port int
+}
+
+func startTestServers(ctx context.Context, g *gomega.WithT, count int) []*testServer {
+	servers := make([]*testServer, count)
+	for i := range servers {
+		lis, e := net.Listen("tcp", "127.0.0.1:0")
+		g.Expect(e).To(Succeed(), "listening should not fail")
+		s := &testServer{
+			Server: grpc.NewServer(),
+			impl:   &testHealthServer{},
+			port:   lis.Addr().(*net.TCPAddr).Port,
+		}
+		healthpb.RegisterHealthServer(s.Server, s.impl)
+		go func() { _ = s.Serve(lis) }()
+		go func() {
+			<-ctx.Done()
+			s.Stop()
+		}()
+		servers[i] = s
+	}
+	return servers
+}
+
+func mockServerInstances(servers []*testServer) sdtest.InstanceMockOptions {
+	i := 0
+	return func(inst *discovery.Instance) {
+		inst.Meta[discovery.InstanceMetaKeyGrpcPort] = strconv.Itoa(servers[i%len(servers)].port)
+		i++
+	}
+}
+
+type testDI struct {
+	fx.In
+	Client   grpcclient.Client
+	SDClient *sdtest.ClientMock
+}
+
+/*************************
+	Tests
+ *************************/
+
+func TestGrpcClient(t *testing.T) {
+	var di testDI
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		apptest.WithModules(grpcclient.Module),
+		apptest.WithDI(&di),
+		apptest.WithFxOptions(
+			fx.Provide(sdtest.ProvideDiscoveryClient),
+		),
+		test.GomegaSubTest(SubTestLoadBalancing(&di), "TestLoadBalancing"),
+		test.GomegaSubTest(SubTestTokenPassthrough(&di), "TestTokenPassthrough"),
+		test.GomegaSubTest(SubTestNoInstances(&di), "TestNoInstances"),
+		test.GomegaSubTest(SubTestWithoutGrpcPort(&di), "TestWithoutGrpcPort"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestLoadBalancing(di *testDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		servers := startTestServers(ctx, g, 2)
+		di.SDClient.MockService(TestServiceName, 2, mockServerInstances(servers))
+
+		conn, e := di.Client.DialService(ctx, TestServiceName)
+		g.Expect(e).To(Succeed(), "dial service should not fail")
+		defer func() { _ = conn.Close() }()
+		client := healthpb.NewHealthClient(conn)
+		for i := 0; i < 10; i++ {
+			resp, e := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+			g.Expect(e).To(Succeed(), "call should not fail")
+			g.Expect(resp.Status).To(Equal(healthpb.HealthCheckResponse_SERVING), "response should be correct")
+		}
+		for _, s := range servers {
+			g.Expect(s.impl.count).To(BeNumerically(">", 0), "calls should be balanced across instances")
+		}
+	}
+}
+
+func SubTestTokenPassthrough(di *testDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		servers := startTestServers(ctx, g, 1)
+		di.SDClient.MockService(TestServiceName, 1, mockServerInstances(servers))
+
+		conn, e := di.Client.DialService(ctx, TestServiceName)
+		g.Expect(e).To(Succeed(), "dial service should not fail")
+		defer func() { _ = conn.Close() }()
+		secCtx := sectest.ContextWithSecurity(ctx, sectest.MockedAuthentication(func(d *sectest.SecurityDetailsMock) {
+			d.Username = "test-user"
+			d.AccessToken = TestAccessToken
+		}))
+		_, e = healthpb.NewHealthClient(conn).Check(secCtx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+		g.Expect(e).To(Succeed(), "call should not fail")
+		g.Expect(servers[0].impl.tokens).To(ConsistOf("Bearer "+TestAccessToken), "access token should be forwarded")
+	}
+}
+
+func SubTestNoInstances(di *testDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		di.SDClient.MockService(TestServiceName, 1, sdtest.BeCritical())
+
+		conn, e := di.Client.DialService(ctx, TestServiceName)
+		g.Expect(e).To(Succeed(), "dial service should not fail")
+		defer func() { _ = conn.Close() }()
+		_, e = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		g.Expect(e).To(HaveOccurred(), "call should fail without healthy instances")
+	}
+}
+
+func SubTestWithoutGrpcPort(di *testDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		servers := startTestServers(ctx, g, 1)
+		di.SDClient.MockService(TestServiceName, 1, func(inst *discovery.Instance) {
+			inst.Port = servers[0].port
+		})
+
+		conn, e := di.Client.DialService(ctx, TestServiceName)
+		g.Expect(e).To(Succeed(), "dial service should not fail")
+		defer func() { _ = conn.Close() }()
+		_, e = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		g.Expect(e).To(HaveOccurred(), "call should fail when instances don't register gRPC port")
+		g.Expect(servers[0].impl.count).To(BeZero(), "service port should not be used as gRPC port")
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcclient
+
+import (
+	"context"
+	"fmt"
+	lanaigrpc "github.com/cisco-open/go-lanai/pkg/grpc"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/tracing"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const tracingOpName = "remote-grpc"
+
+/**********************
+	Token Passthrough
+ **********************/
+
+func tokenPassthroughUnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(contextWithToken(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func tokenPassthroughStreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(contextWithToken(ctx), desc, cc, method, opts...)
+	}
+}
+
+func contextWithToken(ctx context.Context) context.Context {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(lanaigrpc.MetadataKeyAuthorization)) != 0 {
+		return ctx
+	}
+	auth, ok := security.Get(ctx).(oauth2.Authentication)
+	if !ok || !security.IsFullyAuthenticated(auth) || auth.AccessToken() == nil {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, lanaigrpc.MetadataKeyAuthorization, fmt.Sprintf("Bearer %s", auth.AccessToken().Value()))
+}
+
+/**********************
+	Tracing
+ **********************/
+
+// TracingUnaryInterceptor creates client span for each unary call and propagates it via outgoing metadata
+func TracingUnaryInterceptor(tracer opentracing.Tracer) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = startClientSpan(ctx, tracer, method)
+		e := invoker(ctx, method, req, reply, cc, opts...)
+		tracing.WithTracer(tracer).WithOptions(lanaigrpc.SpanCode(e)).Finish(ctx)
+		return e
+	}
+}
+
+// TracingStreamInterceptor creates client span for each stream and propagates it via outgoing metadata.
+// The span is finished once the stream is established.
+func TracingStreamInterceptor(tracer opentracing.Tracer) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx = startClientSpan(ctx, tracer, method)
+		cs, e := streamer(ctx, desc, cc, method, opts...)
+		tracing.WithTracer(tracer).WithOptions(lanaigrpc.SpanCode(e)).Finish(ctx)
+		return cs, e
+	}
+}
+
+func startClientSpan(ctx context.Context, tracer opentracing.Tracer, method string) context.Context {
+	ctx = tracing.WithTracer(tracer).
+		WithOpName(tracingOpName+" "+method).
+		WithOptions(
+			tracing.SpanKind(ext.SpanKindRPCClientEnum),
+			tracing.SpanComponent("grpc"),
+			tracing.SpanTag("grpc.method", method),
+		).
+		DescendantOrNoSpan(ctx)
+	span := tracing.SpanFromContext(ctx)
+	if span == nil {
+		return ctx
+	}
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	if e := tracer.Inject(span.Context(), opentracing.TextMap, lanaigrpc.MetadataCarrier(md)); e != nil {
+		return ctx
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
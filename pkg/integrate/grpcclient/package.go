@@ -0,0 +1,140 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcclient
+
+import (
+	"context"
+	"fmt"
+	appconfig "github.com/cisco-open/go-lanai/pkg/appconfig/init"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/certs"
+	"github.com/cisco-open/go-lanai/pkg/discovery"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/opentracing/opentracing-go"
+	"go.uber.org/fx"
+)
+
+var logger = log.New("gRPCClient")
+
+const (
+	FxGroup = "grpc-client"
+)
+
+var Module = &bootstrap.Module{
+	Name:       "grpc-client",
+	Precedence: bootstrap.GrpcClientPrecedence,
+	Options: []fx.Option{
+		appconfig.FxEmbeddedDefaults(defaultConfigFS),
+		fx.Provide(bindGrpcClientProperties),
+		fx.Provide(provideGrpcClient),
+		fx.Provide(tracingProvider()),
+	},
+}
+
+func Use() {
+	bootstrap.Register(Module)
+}
+
+// ClientCustomizer customizes default ClientOption of the Client provided by this module
+type ClientCustomizer interface {
+	Customize(opt *ClientOption)
+}
+
+type ClientCustomizerFunc func(opt *ClientOption)
+
+func (fn ClientCustomizerFunc) Customize(opt *ClientOption) {
+	fn(opt)
+}
+
+// FxClientCustomizers takes providers of ClientCustomizer and wrap them with FxGroup
+func FxClientCustomizers(providers ...interface{}) []fx.Annotated {
+	annotated := make([]fx.Annotated, len(providers))
+	for i, t := range providers {
+		annotated[i] = fx.Annotated{
+			Group:  FxGroup,
+			Target: t,
+		}
+	}
+	return annotated
+}
+
+type clientDI struct {
+	fx.In
+	AppCtx       *bootstrap.ApplicationContext
+	Properties   GrpcClientProperties
+	DiscClient   discovery.Client   `optional:"true"`
+	CertsManager certs.Manager      `optional:"true"`
+	Customizers  []ClientCustomizer `group:"grpc-client"`
+}
+
+func provideGrpcClient(di clientDI) (Client, error) {
+	options := []ClientOptions{func(opt *ClientOption) {
+		opt.SDClient = di.DiscClient
+		opt.LoadBalancingPolicy = di.Properties.LoadBalancing
+	}}
+	if di.Properties.TLS.Enabled {
+		tlsOpt, e := tlsOption(di.AppCtx, di.CertsManager, &di.Properties.TLS)
+		if e != nil {
+			return nil, e
+		}
+		options = append(options, tlsOpt)
+	}
+	for _, customizer := range di.Customizers {
+		options = append(options, customizer.Customize)
+	}
+	return NewClient(options...), nil
+}
+
+func tlsOption(ctx context.Context, certsMgr certs.Manager, props *TLSProperties) (ClientOptions, error) {
+	if certsMgr == nil {
+		return nil, fmt.Errorf("gRPC client TLS is enabled, but certificate manager is not available")
+	}
+	src, e := certsMgr.Source(ctx, certs.WithSourceProperties(&props.Certs))
+	if e != nil {
+		return nil, fmt.Errorf("unable to initialize gRPC client TLS: %v", e)
+	}
+	tlsCfg, e := src.TLSConfig(ctx)
+	if e != nil {
+		return nil, fmt.Errorf("unable to initialize gRPC client TLS: %v", e)
+	}
+	return func(opt *ClientOption) {
+		opt.TLSConfig = tlsCfg
+	}, nil
+}
+
+/**************************
+	Tracing
+***************************/
+
+type tracingDI struct {
+	fx.In
+	Tracer opentracing.Tracer `optional:"true"`
+}
+
+func tracingProvider() fx.Annotated {
+	return FxClientCustomizers(newTracingCustomizer)[0]
+}
+
+func newTracingCustomizer(di tracingDI) ClientCustomizer {
+	return ClientCustomizerFunc(func(opt *ClientOption) {
+		if di.Tracer == nil {
+			return
+		}
+		opt.UnaryInterceptors = append(opt.UnaryInterceptors, TracingUnaryInterceptor(di.Tracer))
+		opt.StreamInterceptors = append(opt.StreamInterceptors, TracingStreamInterceptor(di.Tracer))
+	})
+}
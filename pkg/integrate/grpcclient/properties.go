@@ -0,0 +1,57 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcclient
+
+import (
+	"embed"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/certs"
+	"github.com/pkg/errors"
+)
+
+const (
+	PropertiesPrefix = "integrate.grpc"
+)
+
+//go:embed defaults-integrate-grpc.yml
+var defaultConfigFS embed.FS
+
+type GrpcClientProperties struct {
+	// LoadBalancing is the gRPC load balancing policy used for service connections, e.g. "round_robin", "pick_first"
+	LoadBalancing string        `json:"load-balancing"`
+	TLS           TLSProperties `json:"tls"`
+}
+
+// TLSProperties configures client TLS. Root CAs and client certificate (for mTLS) are loaded from certs.Manager
+type TLSProperties struct {
+	Enabled bool                   `json:"enabled"`
+	Certs   certs.SourceProperties `json:"certs"`
+}
+
+func newGrpcClientProperties() *GrpcClientProperties {
+	return &GrpcClientProperties{
+		LoadBalancing: LoadBalancingRoundRobin,
+	}
+}
+
+func bindGrpcClientProperties(ctx *bootstrap.ApplicationContext) GrpcClientProperties {
+	props := newGrpcClientProperties()
+	if err := ctx.Config().Bind(props, PropertiesPrefix); err != nil {
+		panic(errors.Wrap(err, "failed to bind GrpcClientProperties"))
+	}
+	return *props
+}
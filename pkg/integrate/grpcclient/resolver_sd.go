@@ -0,0 +1,125 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcclient
+
+import (
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/discovery"
+	"google.golang.org/grpc/resolver"
+	"net"
+	"strconv"
+	"sync"
+)
+
+// SDScheme is the gRPC target scheme resolved by SDResolverBuilder, e.g. "discovery:///my-service"
+const SDScheme = "discovery"
+
+// SDResolverBuilder implements resolver.Builder. Target addresses are resolved from discovery.Instancer,
+// and updated whenever the instancer reports changes. Only instances registering gRPC port as
+// discovery.InstanceMetaKeyGrpcPort are resolved.
+type SDResolverBuilder struct {
+	sdClient discovery.Client
+	selector discovery.InstanceMatcher
+}
+
+func NewSDResolverBuilder(sdClient discovery.Client, selector discovery.InstanceMatcher) *SDResolverBuilder {
+	return &SDResolverBuilder{
+		sdClient: sdClient,
+		selector: selector,
+	}
+}
+
+func (b *SDResolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	instancer, e := b.sdClient.Instancer(target.Endpoint())
+	if e != nil {
+		return nil, fmt.Errorf("cannot resolve gRPC service [%s]: %v", target.Endpoint(), e)
+	}
+	r := &sdResolver{
+		instancer: instancer,
+		selector:  b.selector,
+		cc:        cc,
+	}
+	instancer.RegisterCallback(r, func(discovery.Instancer) {
+		r.resolve()
+	})
+	r.resolve()
+	return r, nil
+}
+
+func (b *SDResolverBuilder) Scheme() string {
+	return SDScheme
+}
+
+type sdResolver struct {
+	mtx       sync.Mutex
+	instancer discovery.Instancer
+	selector  discovery.InstanceMatcher
+	cc        resolver.ClientConn
+}
+
+func (r *sdResolver) ResolveNow(resolver.ResolveNowOptions) {
+	r.resolve()
+}
+
+func (r *sdResolver) Close() {
+	r.instancer.DeregisterCallback(r)
+}
+
+func (r *sdResolver) resolve() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	svc := r.instancer.Service()
+	if svc == nil {
+		r.cc.ReportError(fmt.Errorf("cannot find service [%s]", r.instancer.ServiceName()))
+		return
+	}
+	insts := svc.Instances(r.selector)
+	if len(insts) == 0 {
+		if svc.Err != nil {
+			r.cc.ReportError(fmt.Errorf("cannot find service [%s]: %v", r.instancer.ServiceName(), svc.Err))
+		} else {
+			r.cc.ReportError(fmt.Errorf("no available instances of service [%s]", r.instancer.ServiceName()))
+		}
+		return
+	}
+	addrs := make([]resolver.Address, 0, len(insts))
+	for _, inst := range insts {
+		port, ok := grpcPort(inst)
+		if !ok {
+			logger.Debugf("instance [%s] of service [%s] doesn't register gRPC port", inst.ID, r.instancer.ServiceName())
+			continue
+		}
+		addrs = append(addrs, resolver.Address{Addr: net.JoinHostPort(inst.Address, strconv.Itoa(port))})
+	}
+	if len(addrs) == 0 {
+		r.cc.ReportError(fmt.Errorf("no instances of service [%s] registered gRPC port", r.instancer.ServiceName()))
+		return
+	}
+	if e := r.cc.UpdateState(resolver.State{Addresses: addrs}); e != nil {
+		logger.Debugf("gRPC resolver state update of service [%s] rejected: %v", r.instancer.ServiceName(), e)
+	}
+}
+
+// grpcPort returns the gRPC port registered as discovery.InstanceMetaKeyGrpcPort.
+// The instance's port is not used, since it is the HTTP port of the service.
+func grpcPort(inst *discovery.Instance) (int, bool) {
+	port, e := strconv.Atoi(inst.Meta[discovery.InstanceMetaKeyGrpcPort])
+	if e != nil || port <= 0 {
+		return 0, false
+	}
+	return port, true
+}
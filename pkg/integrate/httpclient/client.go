@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"github.com/cisco-open/go-lanai/pkg/discovery"
 	"github.com/cisco-open/go-lanai/pkg/utils/order"
+	"net/url"
 	"time"
 )
 
@@ -42,6 +43,8 @@ type client struct {
 	before   []BeforeHook
 	after    []AfterHook
 	resolver TargetResolver
+	// target is the service name or host of base URL, see TargetName
+	target string
 }
 
 func NewClient(opts ...ClientOptions) Client {
@@ -91,6 +94,7 @@ func (c *client) WithService(service string, opts ...SDOptions) (Client, error)
 
 	cp := c.shallowCopy()
 	cp.resolver = targetResolver
+	cp.target = service
 	return cp.WithConfig(defaultServiceConfig()), nil
 }
 
@@ -102,6 +106,9 @@ func (c *client) WithBaseUrl(baseUrl string) (Client, error) {
 
 	cp := c.shallowCopy()
 	cp.resolver = endpointer
+	if u, e := url.Parse(baseUrl); e == nil {
+		cp.target = u.Host
+	}
 	return cp.WithConfig(defaultExtHostConfig()), nil
 }
 
@@ -217,12 +224,18 @@ func (c *client) executor(request *Request, resolver TargetResolver, dec DecodeR
 			return nil, e
 		}
 
+		ctx = context.WithValue(ctx, kCtxTargetName{}, c.target)
 		for _, hook := range c.before {
 			ctx = hook.Before(ctx, req)
 		}
 
 		resp, e := c.config.HTTPClient.Do(req.WithContext(ctx))
 		if e != nil {
+			for _, hook := range c.after {
+				if aware, ok := hook.(ErrorAwareAfterHook); ok {
+					ctx = aware.AfterError(ctx, e)
+				}
+			}
 			return nil, e
 		}
 		defer func() { _ = resp.Body.Close() }()
@@ -140,6 +140,22 @@ type ConfigurableAfterHook interface {
 	WithConfig(cfg *ClientConfig) AfterHook
 }
 
+// ErrorAwareAfterHook is an additional interface that AfterHook can implement.
+// AfterError is invoked instead of After when no HTTP response is returned, e.g. timeout, connection refused or DNS failure
+type ErrorAwareAfterHook interface {
+	AfterError(ctx context.Context, err error) context.Context
+}
+
+type kCtxTargetName struct{}
+
+// TargetName returns the name of the remote target of current HTTP exchange, i.e. the service name of clients created
+// via Client.WithService, or host of the base URL of clients created via Client.WithBaseUrl.
+// It's available to BeforeHook and AfterHook.
+func TargetName(ctx context.Context) string {
+	name, _ := ctx.Value(kCtxTargetName{}).(string)
+	return name
+}
+
 type TargetResolver interface {
 	Resolve(ctx context.Context, req *Request) (*url.URL, error)
 }
@@ -101,6 +101,14 @@ func (h orderedAfterHook) Order() int {
 	return h.order
 }
 
+// AfterError implements ErrorAwareAfterHook, if the wrapped hook supports it
+func (h orderedAfterHook) AfterError(ctx context.Context, err error) context.Context {
+	if aware, ok := h.AfterHook.(ErrorAwareAfterHook); ok {
+		return aware.AfterError(ctx, err)
+	}
+	return ctx
+}
+
 /****************************
 	Token Passthrough Hook
  ****************************/
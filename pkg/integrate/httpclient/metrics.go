@@ -0,0 +1,116 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httpclient
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/metrics"
+	"github.com/cisco-open/go-lanai/pkg/utils/order"
+	"go.uber.org/fx"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	MetricClientRequests = "http.client.requests"
+	TagMethod            = "method"
+	TagService           = "service"
+	TagStatus            = "status"
+	// StatusIOError is the status tag value of requests failed without response, e.g. timeout or connection refused
+	StatusIOError = "IO_ERROR"
+)
+
+type kCtxMetricsStart struct{}
+
+type kCtxMetricsRequest struct{}
+
+type metricsCustomizer struct {
+	registry metrics.Registry
+}
+
+func metricsProvider() fx.Annotated {
+	return FxClientCustomizers(newMetricsCustomizer)[0]
+}
+
+type metricsDI struct {
+	fx.In
+	Registry metrics.Registry `optional:"true"`
+}
+
+func newMetricsCustomizer(di metricsDI) ClientCustomizer {
+	return &metricsCustomizer{
+		registry: di.Registry,
+	}
+}
+
+// Customize records duration of each HTTP exchange, tagged by method, service, status code and outcome.
+// Service is the service name of clients created via Client.WithService, or host of base URL of clients created via
+// Client.WithBaseUrl. Outcome is "error" for 5xx responses and requests failed without response. The latter are tagged
+// with status StatusIOError.
+func (c *metricsCustomizer) Customize(opt *ClientOption) {
+	if c.registry == nil {
+		return
+	}
+	timer := c.registry.Timer(MetricClientRequests,
+		metrics.WithDescription("HTTP client requests"),
+		metrics.WithTags(TagMethod, TagService, TagStatus, metrics.TagOutcome),
+	)
+	opt.DefaultBeforeHooks = append(opt.DefaultBeforeHooks, startTimerHook())
+	opt.DefaultAfterHooks = append(opt.DefaultAfterHooks, AfterHookWithOrder(order.Lowest, recordTimerHook{timer: timer}))
+}
+
+func startTimerHook() BeforeHook {
+	fn := func(ctx context.Context, req *http.Request) context.Context {
+		ctx = context.WithValue(ctx, kCtxMetricsRequest{}, req)
+		return context.WithValue(ctx, kCtxMetricsStart{}, time.Now())
+	}
+	return BeforeHookWithOrder(order.Highest, BeforeHookFunc(fn))
+}
+
+// recordTimerHook implements AfterHook and ErrorAwareAfterHook
+type recordTimerHook struct {
+	timer metrics.Timer
+}
+
+func (h recordTimerHook) After(ctx context.Context, resp *http.Response) context.Context {
+	outcome := metrics.OutcomeSuccess
+	if resp.StatusCode >= http.StatusInternalServerError {
+		outcome = metrics.OutcomeError
+	}
+	h.record(ctx, strconv.Itoa(resp.StatusCode), outcome)
+	return ctx
+}
+
+func (h recordTimerHook) AfterError(ctx context.Context, _ error) context.Context {
+	h.record(ctx, StatusIOError, metrics.OutcomeError)
+	return ctx
+}
+
+func (h recordTimerHook) record(ctx context.Context, status, outcome string) {
+	start, ok := ctx.Value(kCtxMetricsStart{}).(time.Time)
+	req, _ := ctx.Value(kCtxMetricsRequest{}).(*http.Request)
+	if !ok || req == nil {
+		return
+	}
+	h.timer.Record(time.Since(start), metrics.Tags{
+		TagMethod:          req.Method,
+		TagService:         TargetName(ctx),
+		TagStatus:          status,
+		metrics.TagOutcome: outcome,
+	})
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httpclient_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/integrate/httpclient"
+	"github.com/cisco-open/go-lanai/pkg/metrics"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/apptest"
+	"github.com/cisco-open/go-lanai/test/sdtest"
+	"github.com/cisco-open/go-lanai/test/webtest"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/fx"
+	"net"
+	"net/http"
+	"testing"
+)
+
+/*************************
+	Test Setup
+ *************************/
+
+func NewTestMetricsRegistry() (metrics.Registry, *metrics.PrometheusRegistry) {
+	reg := metrics.NewPrometheusRegistry()
+	return reg, reg
+}
+
+/*************************
+	Tests
+ *************************/
+
+type TestMetricsDI struct {
+	fx.In
+	TestDI
+	Registry *metrics.PrometheusRegistry
+}
+
+func TestHttpClientMetrics(t *testing.T) {
+	var di TestMetricsDI
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		webtest.WithRealServer(),
+		sdtest.WithMockedSD(sdtest.DefinitionWithPrefix("mocks.sd")),
+		apptest.WithModules(httpclient.Module),
+		apptest.WithDI(&di),
+		apptest.WithFxOptions(
+			fx.Provide(NewMockedController, NewTestMetricsRegistry),
+			web.FxControllerProviders(ProvideWebController),
+		),
+		test.SubTestSetup(UpdateMockedSD(&di.TestDI)),
+		test.GomegaSubTest(SubTestMetricsWithResponse(&di), "TestMetricsWithResponse"),
+		test.GomegaSubTest(SubTestMetricsWithIOError(&di), "TestMetricsWithIOError"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestMetricsWithResponse(di *TestMetricsDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		client, e := di.HttpClient.WithService(SDServiceNameFullInfo)
+		g.Expect(e).To(Succeed(), "client with service name should be available")
+		performEchoTest(ctx, t, g, client)
+
+		AssertClientRequestsMetric(g, di.Registry, map[string]string{
+			httpclient.TagMethod:  http.MethodPost,
+			httpclient.TagService: SDServiceNameFullInfo,
+			httpclient.TagStatus:  "200",
+			metrics.TagOutcome:    metrics.OutcomeSuccess,
+		})
+	}
+}
+
+func SubTestMetricsWithIOError(di *TestMetricsDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		// find a port that nobody listens to
+		l, e := net.Listen("tcp", "127.0.0.1:0")
+		g.Expect(e).To(Succeed(), "listen on random port should succeed")
+		host := l.Addr().String()
+		_ = l.Close()
+
+		client, e := di.HttpClient.WithBaseUrl(fmt.Sprintf("http://%s/test", host))
+		g.Expect(e).To(Succeed(), "client with base URL should be available")
+		req := httpclient.NewRequest(TestPath, http.MethodPost, httpclient.WithBody(makeEchoRequestBody()))
+		_, e = client.Execute(ctx, req, httpclient.JsonBody(&EchoResponse{}))
+		g.Expect(e).To(HaveOccurred(), "execute request should fail")
+
+		AssertClientRequestsMetric(g, di.Registry, map[string]string{
+			httpclient.TagMethod:  http.MethodPost,
+			httpclient.TagService: host,
+			httpclient.TagStatus:  httpclient.StatusIOError,
+			metrics.TagOutcome:    metrics.OutcomeError,
+		})
+	}
+}
+
+/*************************
+	Helper
+ *************************/
+
+func AssertClientRequestsMetric(g *gomega.WithT, reg *metrics.PrometheusRegistry, expected map[string]string) {
+	families, e := reg.Gatherer().Gather()
+	g.Expect(e).To(Succeed(), "gather should not fail")
+	var found *dto.Metric
+	for _, f := range families {
+		if f.GetName() != "http_client_requests_seconds" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels[httpclient.TagService] == expected[httpclient.TagService] {
+				g.Expect(labels).To(Equal(expected), "metric labels should be correct")
+				found = m
+			}
+		}
+	}
+	g.Expect(found).ToNot(BeNil(), "client request metric should be recorded")
+	g.Expect(found.GetHistogram().GetSampleCount()).To(BeNumerically(">=", 1), "client request should be recorded")
+}
@@ -190,6 +190,33 @@ func (c *remoteAuthClient) SwitchTenant(ctx context.Context, opts ...AuthOptions
 	return c.handleResponse(resp, e)
 }
 
+func (c *remoteAuthClient) TokenExchange(ctx context.Context, opts ...AuthOptions) (*Result, error) {
+	opt := c.option(opts)
+
+	values := url.Values{
+		oauth2.ParameterSubjectToken:     {opt.AccessToken},
+		oauth2.ParameterSubjectTokenType: {oauth2.TokenTypeIdAccessToken},
+		oauth2.ParameterAudience:         opt.Audience,
+		oauth2.ClaimScope:                {strings.Join(opt.Scopes, " ")},
+	}
+	if opt.ActorToken != "" {
+		values[oauth2.ParameterActorToken] = []string{opt.ActorToken}
+		values[oauth2.ParameterActorTokenType] = []string{oauth2.TokenTypeIdAccessToken}
+	}
+	reqOpts := []httpclient.RequestOptions{
+		httpclient.WithParam(oauth2.ParameterGrantType, oauth2.GrantTypeTokenExchange),
+		c.withClientAuth(opt),
+		httpclient.WithUrlEncodedBody(WithNonEmptyURLValues(values)),
+	}
+
+	// prepare request
+	req := httpclient.NewRequest(c.switchCtxPath, http.MethodPost, reqOpts...)
+	// send request and parse response
+	body := oauth2.NewDefaultAccessToken("")
+	resp, e := c.client.Execute(ctx, req, httpclient.JsonBody(body))
+	return c.handleResponse(resp, e)
+}
+
 func (c *remoteAuthClient) option(opts []AuthOptions) *AuthOption {
 	opt := AuthOption{}
 	for _, fn := range opts {
@@ -26,7 +26,9 @@ type AuthOptions func(opt *AuthOption)
 
 type AuthOption struct {
 	Password         string   // Password is used by password login
-	AccessToken      string   // AccessToken is used by switch user/tenant
+	AccessToken      string   // AccessToken is used by switch user/tenant, and as subject token of token exchange
+	ActorToken       string   // ActorToken is used by token exchange for delegation
+	Audience         []string // Audience is used by token exchange to narrow down target services
 	Username         string   // Username is used by password login and switch user
 	UserId           string   // UserId is used by switch user
 	TenantId         string   // TenantId is used by password login and switch user/tenant
@@ -41,6 +43,9 @@ type AuthenticationClient interface {
 	ClientCredentials(ctx context.Context, opts ...AuthOptions) (*Result, error)
 	SwitchUser(ctx context.Context, opts ...AuthOptions) (*Result, error)
 	SwitchTenant(ctx context.Context, opts ...AuthOptions) (*Result, error)
+	// TokenExchange exchanges the given access token for a new token using OAuth2 Token Exchange (RFC 8693).
+	// The new token is on behalf of the same user. When actor token is provided, the new token represents delegation.
+	TokenExchange(ctx context.Context, opts ...AuthOptions) (*Result, error)
 }
 
 type Result struct {
@@ -130,6 +135,20 @@ func WithScopes(scopes ...string) AuthOptions {
 	}
 }
 
+// WithActorToken specify the actor token of token exchange. Resulting token would carry "act" claim
+func WithActorToken(actorToken string) AuthOptions {
+	return func(opt *AuthOption) {
+		opt.ActorToken = actorToken
+	}
+}
+
+// WithAudience specify the target services of token exchange
+func WithAudience(audience ...string) AuthOptions {
+	return func(opt *AuthOption) {
+		opt.Audience = audience
+	}
+}
+
 func WithClientAuth(clientID, secret string) AuthOptions {
 	return func(opt *AuthOption) {
 		opt.ClientID = clientID
@@ -32,6 +32,7 @@ const (
 	TestAltClientID        = `test-client-alt`
 	TestAltClientSecret    = `test-secret-alt`
 	TestCurrentAccessToken = `test-token`
+	TestActorAccessToken   = `test-actor-token`
 )
 
 /*************************
@@ -62,6 +63,7 @@ func TestWithMockedServer(t *testing.T) {
 		test.GomegaSubTest(SubTestPasswordLogin(&di), "PasswordLogin"),
 		test.GomegaSubTest(SubTestSwitchUser(&di), "SwitchUser"),
 		test.GomegaSubTest(SubTestSwitchTenant(&di), "SwitchTenant"),
+		test.GomegaSubTest(SubTestTokenExchange(&di), "TokenExchange"),
 	)
 }
 
@@ -279,6 +281,57 @@ func SubTestSwitchTenant(di *TestDI) test.GomegaSubTestFunc {
 	}
 }
 
+func SubTestTokenExchange(di *TestDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		test.RunTest(ctx, t,
+			test.GomegaSubTest(func(ctx context.Context, t *testing.T, g *WithT) {
+				// minimum options
+				rs, e := di.AuthClient.TokenExchange(ctx, seclient.WithAccessToken(TestCurrentAccessToken))
+				g.Expect(e).To(Succeed(), "token exchange should not fail")
+				req := AssertResult(g, rs, oauth2.GrantTypeTokenExchange, TestClientID, TestClientSecret)
+				AssertTokenRequestParams(g, req,
+					oauth2.ParameterSubjectToken, TestCurrentAccessToken,
+					oauth2.ParameterSubjectTokenType, oauth2.TokenTypeIdAccessToken,
+					oauth2.ParameterActorToken, nil, oauth2.ParameterAudience, nil)
+			}, "Impersonation"),
+
+			test.GomegaSubTest(func(ctx context.Context, t *testing.T, g *WithT) {
+				// with more options
+				rs, e := di.AuthClient.TokenExchange(ctx, seclient.WithAccessToken(TestCurrentAccessToken),
+					seclient.WithActorToken(TestActorAccessToken),
+					seclient.WithAudience("service-1", "service-2"),
+					seclient.WithClientAuth(TestAltClientID, TestAltClientSecret),
+					seclient.WithScopes(TestScope),
+				)
+				g.Expect(e).To(Succeed(), "token exchange should not fail")
+				req := AssertResult(g, rs, oauth2.GrantTypeTokenExchange, TestAltClientID, TestAltClientSecret)
+				g.Expect(req.Scopes).To(HaveKey(TestScope), "request's [%s] should be correct", "Scopes")
+				AssertTokenRequestParams(g, req,
+					oauth2.ParameterSubjectToken, TestCurrentAccessToken,
+					oauth2.ParameterSubjectTokenType, oauth2.TokenTypeIdAccessToken,
+					oauth2.ParameterActorToken, TestActorAccessToken,
+					oauth2.ParameterActorTokenType, oauth2.TokenTypeIdAccessToken,
+					oauth2.ParameterAudience, "service-1 service-2")
+			}, "Delegation"),
+
+			test.GomegaSubTest(func(ctx context.Context, t *testing.T, g *WithT) {
+				// current security
+				ctx = ContextWithSecurity(ctx)
+				rs, e := di.AuthClient.TokenExchange(ctx, seclient.WithCurrentSecurity(ctx))
+				g.Expect(e).To(Succeed(), "token exchange should not fail")
+				req := AssertResult(g, rs, oauth2.GrantTypeTokenExchange, TestClientID, TestClientSecret)
+				AssertTokenRequestParams(g, req, oauth2.ParameterSubjectToken, TestCurrentAccessToken)
+			}, "WithCurrentSecurity"),
+
+			test.GomegaSubTest(func(ctx context.Context, t *testing.T, g *WithT) {
+				// failure
+				_, e := di.AuthClient.TokenExchange(ctx, seclient.WithClientAuth(InvalidClientID, "whatever"))
+				g.Expect(e).To(HaveOccurred(), "token exchange should fail with invalid credentials")
+			}, "Failure"),
+		)
+	}
+}
+
 /*************************
 	Helpers
  *************************/
@@ -0,0 +1,89 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"go.uber.org/fx"
+)
+
+type logSinkDI struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Binder    Binder
+}
+
+// initializeLogSink creates producers for topics of "mq" loggers and installs log.MQPublisher once the binder is started.
+// Note: log entries of the producers themselves are also shipped, so "Kafka" logger level should not be too verbose.
+func initializeLogSink(di logSinkDI) error {
+	topics := log.MQTopics()
+	if len(topics) == 0 {
+		return nil
+	}
+	publisher := &logPublisher{producers: map[string]Producer{}}
+	for _, topic := range topics {
+		if _, ok := publisher.producers[topic]; ok {
+			continue
+		}
+		p, e := di.Binder.Produce(topic, RequireLocalAck())
+		if e != nil {
+			return fmt.Errorf("unable to create producer for mq logger topic [%s]: %v", topic, e)
+		}
+		publisher.producers[topic] = p
+	}
+	di.Lifecycle.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			log.SetMQPublisher(publisher)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			log.SetMQPublisher(nil)
+			return nil
+		},
+	})
+	return nil
+}
+
+// logPublisher implements log.MQPublisher
+type logPublisher struct {
+	producers map[string]Producer
+}
+
+func (p *logPublisher) Publish(ctx context.Context, topic string, entries []log.SinkEntry) error {
+	producer, ok := p.producers[topic]
+	if !ok {
+		return fmt.Errorf("producer for mq logger topic [%s] is not available", topic)
+	}
+	select {
+	case <-producer.ReadyCh():
+	default:
+		return log.ErrSinkNotReady
+	}
+	for i, entry := range entries {
+		if e := producer.Send(ctx, bytes.TrimRight(entry.Data, "\n"), WithEncoder(binaryEncoder{})); e != nil {
+			if i == 0 {
+				return e
+			}
+			// already sent messages should not be duplicated by retries
+			return log.PartialWriteError{Remaining: entries[i:], Err: e}
+		}
+	}
+	return nil
+}
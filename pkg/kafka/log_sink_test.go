@@ -0,0 +1,92 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+/*************************
+	Tests
+ *************************/
+
+func TestLogPublisher(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestPublishPartialFailure(), "TestPublishPartialFailure"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestPublishPartialFailure() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		producer := NewTestLogProducer(2)
+		publisher := &logPublisher{producers: map[string]Producer{"LOGS": producer}}
+		entries := []log.SinkEntry{{Data: []byte("entry 1\n")}, {Data: []byte("entry 2\n")}, {Data: []byte("entry 3\n")}}
+
+		e := publisher.Publish(ctx, "LOGS", entries)
+		var partial log.PartialWriteError
+		g.Expect(errors.As(e, &partial)).To(BeTrue(), "error should be partial write error")
+		g.Expect(partial.Remaining).To(Equal(entries[2:]), "remaining entries should be correct")
+		g.Expect(producer.sent).To(HaveLen(2), "messages before failure should be sent")
+
+		producer.failAt = -1
+		g.Expect(publisher.Publish(ctx, "LOGS", partial.Remaining)).To(Succeed(), "retry should not fail")
+		g.Expect(producer.sent).To(HaveLen(3), "remaining messages should be sent without duplicates")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+// TestLogProducer fails to send the message at index failAt
+type TestLogProducer struct {
+	failAt int
+	ready  chan struct{}
+	sent   []interface{}
+}
+
+func NewTestLogProducer(failAt int) *TestLogProducer {
+	ready := make(chan struct{})
+	close(ready)
+	return &TestLogProducer{failAt: failAt, ready: ready}
+}
+
+func (p *TestLogProducer) Topic() string {
+	return "LOGS"
+}
+
+func (p *TestLogProducer) Send(_ context.Context, message interface{}, _ ...MessageOptions) error {
+	if len(p.sent) == p.failAt {
+		return errors.New("broker unavailable")
+	}
+	p.sent = append(p.sent, message)
+	return nil
+}
+
+func (p *TestLogProducer) ReadyCh() <-chan struct{} {
+	return p.ready
+}
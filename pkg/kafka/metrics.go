@@ -0,0 +1,104 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/metrics"
+	"go.uber.org/fx"
+	"time"
+)
+
+const (
+	MetricProducerMessages = "kafka.producer.messages"
+	MetricConsumerMessages = "kafka.consumer.messages"
+	TagTopic               = "topic"
+)
+
+type kCtxMetricsStart struct{}
+
+type metricsDI struct {
+	fx.In
+	Registry metrics.Registry `optional:"true"`
+}
+
+func metricsProvider() fx.Annotated {
+	return fx.Annotated{
+		Group: FxGroup,
+		Target: func(di metricsDI) (ProducerMessageInterceptor, ConsumerDispatchInterceptor) {
+			if di.Registry != nil {
+				return newKafkaMetricsInterceptors(di.Registry)
+			}
+			return nil, nil
+		},
+	}
+}
+
+func newKafkaMetricsInterceptors(registry metrics.Registry) (ProducerMessageInterceptor, ConsumerDispatchInterceptor) {
+	return &kafkaProducerMetricsInterceptor{
+		timer: registry.Timer(MetricProducerMessages,
+			metrics.WithDescription("Kafka messages produced"),
+			metrics.WithTags(TagTopic, metrics.TagOutcome),
+		),
+	}, &kafkaConsumerMetricsInterceptor{
+		timer: registry.Timer(MetricConsumerMessages,
+			metrics.WithDescription("Kafka messages consumed"),
+			metrics.WithTags(TagTopic, metrics.TagOutcome),
+		),
+	}
+}
+
+// kafkaProducerMetricsInterceptor implements kafka.ProducerMessageInterceptor and kafka.ProducerMessageFinalizer
+type kafkaProducerMetricsInterceptor struct {
+	timer metrics.Timer
+}
+
+func (i kafkaProducerMetricsInterceptor) Intercept(msgCtx *MessageContext) (*MessageContext, error) {
+	msgCtx.Context = context.WithValue(msgCtx.Context, kCtxMetricsStart{}, time.Now())
+	return msgCtx, nil
+}
+
+func (i kafkaProducerMetricsInterceptor) Finalize(msgCtx *MessageContext, _ int32, _ int64, err error) (*MessageContext, error) {
+	recordMessageMetrics(i.timer, msgCtx, err)
+	return msgCtx, err
+}
+
+// kafkaConsumerMetricsInterceptor implements kafka.ConsumerDispatchInterceptor and kafka.ConsumerDispatchFinalizer
+type kafkaConsumerMetricsInterceptor struct {
+	timer metrics.Timer
+}
+
+func (i kafkaConsumerMetricsInterceptor) Intercept(msgCtx *MessageContext) (*MessageContext, error) {
+	msgCtx.Context = context.WithValue(msgCtx.Context, kCtxMetricsStart{}, time.Now())
+	return msgCtx, nil
+}
+
+func (i kafkaConsumerMetricsInterceptor) Finalize(msgCtx *MessageContext, err error) (*MessageContext, error) {
+	recordMessageMetrics(i.timer, msgCtx, err)
+	return msgCtx, err
+}
+
+func recordMessageMetrics(timer metrics.Timer, msgCtx *MessageContext, err error) {
+	start, ok := msgCtx.Value(kCtxMetricsStart{}).(time.Time)
+	if !ok {
+		return
+	}
+	timer.Record(time.Since(start), metrics.Tags{
+		TagTopic:           msgCtx.Topic,
+		metrics.TagOutcome: metrics.Outcome(err),
+	})
+}
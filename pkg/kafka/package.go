@@ -33,7 +33,9 @@ var Module = &bootstrap.Module{
 	Options: []fx.Option{
 		fx.Provide(BindKafkaProperties, ProvideKafkaBinder),
 		fx.Provide(tracingProvider()),
+		fx.Provide(metricsProvider()),
 		fx.Invoke(initialize),
+		fx.Invoke(initializeLogSink),
 	},
 }
 
@@ -22,6 +22,7 @@ import (
     "github.com/cisco-open/go-lanai/pkg/log/internal"
     "go.uber.org/zap"
     "go.uber.org/zap/zapcore"
+    "io"
     "os"
     "strings"
     "time"
@@ -55,6 +56,8 @@ type zapLoggerFactory struct {
 	effectiveValuers ContextValuers
 	extraValuers     ContextValuers
 	registry         map[string]*configurableZapLogger
+	// closers are writers created by current coreCreator, closed when configuration is refreshed
+	closers []io.Closer
 }
 
 func newZapLoggerFactory(properties *Properties) *zapLoggerFactory {
@@ -87,7 +90,7 @@ func (f *zapLoggerFactory) createLogger(name string) ContextualLogger {
 
 	ll := f.resolveEffectiveLevel(key)
 	leveler := zap.NewAtomicLevel()
-	l := newConfigurableZapLogger(name, f.coreCreator(leveler), ll, leveler, f.effectiveValuers)
+	l := newConfigurableZapLogger(name, f.newCore(key, leveler), ll, leveler, f.effectiveValuers)
 	f.registry[key] = l
 	return l
 }
@@ -149,10 +152,17 @@ func (f *zapLoggerFactory) refresh(properties *Properties) error {
 	f.rootLogLevel = rootLogLevel
 	f.logLevels = convertLevelsNameToKey(properties.Levels)
 	f.effectiveValuers = buildContextValuerFromConfig(properties)
+	f.properties = properties
 	var e error
+	prevClosers := f.closers
+	f.closers = nil
 	if f.coreCreator, e = f.buildZapCoreCreator(properties); e != nil {
+		closeAll(f.closers)
+		f.closers = prevClosers
 		return e
 	}
+	// previous writers may still be used by in-flight log calls, those entries are dropped once closed
+	defer closeAll(prevClosers)
 
 	// merge valuers, note: we don't delete extra valuers during refresh
 	for k, v := range f.extraValuers {
@@ -161,7 +171,7 @@ func (f *zapLoggerFactory) refresh(properties *Properties) error {
 
 	for key, l := range f.registry {
 		ll := f.resolveEffectiveLevel(key)
-		l.core = f.coreCreator(l.leveler)
+		l.core = f.newCore(key, l.leveler)
 		l.valuers = f.effectiveValuers
 		l.setMinLevel(ll)
 	}
@@ -202,12 +212,16 @@ func (f *zapLoggerFactory) buildZapCoreCreator(properties *Properties) (zapCoreC
 			},
 		}
 	}
+	redactor, e := newRedactor(properties.Redaction)
+	if e != nil {
+		return nil, e
+	}
 	encoders := make([]zapcore.Encoder, len(properties.Loggers))
 	syncers := make([]zapcore.WriteSyncer, len(properties.Loggers))
 	var i int
-	for _, loggerProps := range properties.Loggers {
+	for name, loggerProps := range properties.Loggers {
 		var e error
-		if syncers[i], e = f.newZapWriteSyncer(loggerProps); e != nil {
+		if syncers[i], e = f.newZapWriteSyncer(name, loggerProps); e != nil {
 			return nil, e
 		}
 		if encoders[i], e = f.newZapEncoder(loggerProps, syncers[i].(internal.TerminalAware).IsTerminal()); e != nil {
@@ -233,6 +247,8 @@ func (f *zapLoggerFactory) buildZapCoreCreator(properties *Properties) (zapCoreC
 			}
 			core = zapcore.NewTee(cores...)
 		}
+		// all cores share the same level, so redaction can be applied once
+		core = newRedactingCore(core, redactor)
 		if isTerm {
 			return internal.ZapTerminalCore{Core: core}
 		}
@@ -252,22 +268,41 @@ func (f *zapLoggerFactory) newZapEncoder(props *LoggerProperties, isTerm bool) (
 	return nil, fmt.Errorf("unsupported logger format: %v", props.Format)
 }
 
-func (f *zapLoggerFactory) newZapWriteSyncer(props *LoggerProperties) (zapcore.WriteSyncer, error) {
+func (f *zapLoggerFactory) newZapWriteSyncer(name string, props *LoggerProperties) (zapcore.WriteSyncer, error) {
 	switch props.Type {
 	case TypeConsole:
 		return internal.NewZapWriterWrapper(os.Stdout), nil
 	case TypeFile:
-		file, e := openOrCreateFile(props.Location)
+		file, e := newRotatingFile(props.Location, props.Rotation)
 		if e != nil {
 			return nil, e
 		}
-		return internal.NewZapWriterWrapper(file), nil
+		f.closers = append(f.closers, file)
+		return file, nil
 	case TypeHttp:
-		fallthrough
+		writer, e := newHttpSinkWriter(props.Location, props.Sink)
+		if e != nil {
+			return nil, e
+		}
+		return f.newAsyncSink(name, props.Sink, writer), nil
 	case TypeMQ:
-		fallthrough
+		if props.Location == "" {
+			return nil, fmt.Errorf("location is missing for mq logger")
+		}
+		return f.newAsyncSink(name, props.Sink, mqSinkWriter{topic: props.Location}), nil
 	default:
 		return nil, fmt.Errorf("unsupported logger type: %v", props.Type)
 	}
 }
 
+func (f *zapLoggerFactory) newAsyncSink(name string, props SinkProperties, writer SinkWriter) *asyncSink {
+	sink := newAsyncSink(name, props, writer)
+	f.closers = append(f.closers, sink)
+	return sink
+}
+
+func closeAll(closers []io.Closer) {
+	for _, c := range closers {
+		_ = c.Close()
+	}
+}
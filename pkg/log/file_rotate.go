@@ -0,0 +1,340 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	compressSuffix   = ".gz"
+	megabyte         = 1024 * 1024
+	// rotateRetryInterval is the minimum interval between rotation attempts after a failed one
+	rotateRetryInterval = time.Minute
+)
+
+var (
+	openFiles   = map[*rotatingFile]struct{}{}
+	openFileMtx sync.Mutex
+	// renameFile is used to rename current file to backup name. Replaced in tests to simulate failures
+	renameFile = os.Rename
+	// closeFile is used to close current file before rotation. Replaced in tests to simulate failures
+	closeFile = (*os.File).Close
+)
+
+// ReopenFiles closes and reopens all files of "file" loggers. This is useful when log files are rotated by external
+// tools like logrotate. Applications that need it should wire it to a signal themselves, e.g.
+//
+//	ch := make(chan os.Signal, 1)
+//	signal.Notify(ch, syscall.SIGHUP)
+//	go func() {
+//		for range ch {
+//			log.ReopenFiles()
+//		}
+//	}()
+func ReopenFiles() {
+	openFileMtx.Lock()
+	files := make([]*rotatingFile, 0, len(openFiles))
+	for f := range openFiles {
+		files = append(files, f)
+	}
+	openFileMtx.Unlock()
+	for _, f := range files {
+		if e := f.Reopen(); e != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "unable to reopen log file [%s]: %v\n", f.location, e)
+		}
+	}
+}
+
+// rotatingFile implements zapcore.WriteSyncer, internal.TerminalAware and io.Closer.
+// It writes to file at given location and rotates it based on RotationProperties.
+// Rotated files are named "<name>-<timestamp><ext>", optionally compressed and removed according to retention settings.
+type rotatingFile struct {
+	location   string
+	props      RotationProperties
+	mtx        sync.Mutex
+	file       *os.File
+	size       int64
+	nextDaily  time.Time
+	cleanupReq chan struct{}
+	closed     bool
+	// retryRotateAt is set when rotation failed. Rotation is not attempted again before this time,
+	// so writes are not slowed down by repeated failures and the failure is reported only once
+	retryRotateAt time.Time
+}
+
+func newRotatingFile(location string, props RotationProperties) (*rotatingFile, error) {
+	if location == "" {
+		return nil, fmt.Errorf("location is missing for file logger")
+	}
+	f := &rotatingFile{
+		location:   location,
+		props:      props,
+		cleanupReq: make(chan struct{}, 1),
+	}
+	if e := f.open(); e != nil {
+		return nil, e
+	}
+	go f.cleanupLoop()
+
+	openFileMtx.Lock()
+	openFiles[f] = struct{}{}
+	openFileMtx.Unlock()
+	return f, nil
+}
+
+// Write implements io.Writer
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	if f.shouldRotate(len(p)) {
+		if e := f.rotate(); e != nil {
+			return 0, e
+		}
+	}
+	n, e := f.file.Write(p)
+	f.size += int64(n)
+	return n, e
+}
+
+// Sync implements zapcore.WriteSyncer
+func (f *rotatingFile) Sync() error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if f.closed {
+		return nil
+	}
+	return f.file.Sync()
+}
+
+// IsTerminal implements internal.TerminalAware
+func (f *rotatingFile) IsTerminal() bool {
+	return false
+}
+
+// Reopen closes current file and opens the file at the same location. Any file renamed by external tools is left as-is.
+func (f *rotatingFile) Reopen() error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if f.closed {
+		return nil
+	}
+	_ = f.file.Close()
+	return f.open()
+}
+
+// Close implements io.Closer
+func (f *rotatingFile) Close() error {
+	openFileMtx.Lock()
+	delete(openFiles, f)
+	openFileMtx.Unlock()
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	close(f.cleanupReq)
+	return f.file.Close()
+}
+
+func (f *rotatingFile) shouldRotate(n int) bool {
+	if !f.retryRotateAt.IsZero() && time.Now().Before(f.retryRotateAt) {
+		return false
+	}
+	if f.props.MaxSize > 0 && f.size > 0 && f.size+int64(n) > int64(f.props.MaxSize)*megabyte {
+		return true
+	}
+	return f.props.Daily && !time.Now().Before(f.nextDaily)
+}
+
+// open opens or creates file at location. Caller should hold the lock
+func (f *rotatingFile) open() error {
+	file, e := openOrCreateFile(f.location)
+	if e != nil {
+		return e
+	}
+	f.file = file
+	f.size = 0
+	modTime := time.Now()
+	if stat, e := file.Stat(); e == nil {
+		f.size = stat.Size()
+		if f.size > 0 {
+			modTime = stat.ModTime()
+		}
+	}
+	f.nextDaily = nextMidnight(modTime)
+	return nil
+}
+
+// rotate renames current file to backup name and opens a new one. Caller should hold the lock
+func (f *rotatingFile) rotate() error {
+	if e := closeFile(f.file); e != nil {
+		return f.rotateFailed(e)
+	}
+	if e := renameFile(f.location, f.backupName(time.Now())); e != nil && !os.IsNotExist(e) {
+		return f.rotateFailed(e)
+	}
+	f.retryRotateAt = time.Time{}
+	if e := f.open(); e != nil {
+		return e
+	}
+	select {
+	case f.cleanupReq <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// rotateFailed reopens current file and postpones next rotation attempt, so the logger stays usable.
+// We cannot use logger here. Caller should hold the lock
+func (f *rotatingFile) rotateFailed(err error) error {
+	if f.retryRotateAt.IsZero() {
+		_, _ = fmt.Fprintf(os.Stderr, "unable to rotate log file [%s], retry in %v: %v\n", f.location, rotateRetryInterval, err)
+	}
+	f.retryRotateAt = time.Now().Add(rotateRetryInterval)
+	return f.open()
+}
+
+func (f *rotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := f.backupPattern()
+	return filepath.Join(dir, prefix+t.Format(backupTimeFormat)+ext)
+}
+
+func (f *rotatingFile) backupPattern() (dir, prefix, ext string) {
+	dir = filepath.Dir(f.location)
+	name := filepath.Base(f.location)
+	ext = filepath.Ext(name)
+	prefix = strings.TrimSuffix(name, ext) + "-"
+	return
+}
+
+// cleanupLoop compresses and removes rotated files in background, so logging goroutines are not blocked
+func (f *rotatingFile) cleanupLoop() {
+	for range f.cleanupReq {
+		if e := f.cleanup(); e != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "unable to clean up rotated log files of [%s]: %v\n", f.location, e)
+		}
+	}
+}
+
+type backupFile struct {
+	path string
+	time time.Time
+}
+
+func (f *rotatingFile) cleanup() error {
+	backups, e := f.listBackups()
+	if e != nil {
+		return e
+	}
+	// newest first
+	sort.Slice(backups, func(i, j int) bool { return backups[i].time.After(backups[j].time) })
+	cutoff := time.Now().Add(-time.Duration(f.props.MaxAge))
+	remaining := backups[:0]
+	for i, b := range backups {
+		if (f.props.MaxBackups > 0 && i >= f.props.MaxBackups) || (f.props.MaxAge > 0 && b.time.Before(cutoff)) {
+			if e := os.Remove(b.path); e != nil && !os.IsNotExist(e) {
+				return e
+			}
+			continue
+		}
+		remaining = append(remaining, b)
+	}
+	if !f.props.Compress {
+		return nil
+	}
+	for _, b := range remaining {
+		if strings.HasSuffix(b.path, compressSuffix) {
+			continue
+		}
+		if e := compressFile(b.path); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func (f *rotatingFile) listBackups() ([]backupFile, error) {
+	dir, prefix, ext := f.backupPattern()
+	entries, e := os.ReadDir(dir)
+	if e != nil {
+		return nil, e
+	}
+	var backups []backupFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		ts := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), compressSuffix), ext)
+		t, e := time.ParseInLocation(backupTimeFormat, ts, time.Local)
+		if e != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(dir, name), time: t})
+	}
+	return backups, nil
+}
+
+func compressFile(path string) (err error) {
+	src, e := os.Open(path)
+	if e != nil {
+		return e
+	}
+	defer func() { _ = src.Close() }()
+	dst, e := os.OpenFile(path+compressSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if e != nil {
+		return e
+	}
+	defer func() {
+		if err != nil {
+			_ = dst.Close()
+			_ = os.Remove(path + compressSuffix)
+		}
+	}()
+	gw := gzip.NewWriter(dst)
+	if _, e := io.Copy(gw, src); e != nil {
+		return e
+	}
+	if e := gw.Close(); e != nil {
+		return e
+	}
+	if e := dst.Close(); e != nil {
+		return e
+	}
+	_ = src.Close()
+	return os.Remove(path)
+}
+
+func nextMidnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,234 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+/*************************
+	Tests
+ *************************/
+
+func TestRotatingFile(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestRotateBySize(), "RotateBySize"),
+		test.GomegaSubTest(SubTestRotateDaily(), "RotateDaily"),
+		test.GomegaSubTest(SubTestRotationRetention(), "RotationRetention"),
+		test.GomegaSubTest(SubTestRotateConcurrently(), "RotateConcurrently"),
+		test.GomegaSubTest(SubTestRotateFailure(), "RotateFailure"),
+		test.GomegaSubTest(SubTestRotateCloseFailure(), "RotateCloseFailure"),
+		test.GomegaSubTest(SubTestReopenFiles(), "ReopenFiles"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestRotateBySize() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		location := filepath.Join(t.TempDir(), "test.log")
+		f, e := newRotatingFile(location, RotationProperties{MaxSize: 1})
+		g.Expect(e).To(Succeed(), "creating file should not fail")
+		defer func() { _ = f.Close() }()
+
+		chunk := bytes.Repeat([]byte("x"), 600*1024)
+		_, e = f.Write(chunk)
+		g.Expect(e).To(Succeed(), "write should not fail")
+		g.Expect(ListBackups(g, location)).To(BeEmpty(), "file should not be rotated before reaching max size")
+		_, e = f.Write(chunk)
+		g.Expect(e).To(Succeed(), "write should not fail")
+		g.Expect(ListBackups(g, location)).To(HaveLen(1), "file should be rotated when reaching max size")
+		AssertFileSize(g, location, len(chunk))
+	}
+}
+
+func SubTestRotateDaily() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		location := filepath.Join(t.TempDir(), "test.log")
+		f, e := newRotatingFile(location, RotationProperties{Daily: true})
+		g.Expect(e).To(Succeed(), "creating file should not fail")
+		defer func() { _ = f.Close() }()
+
+		_, _ = f.Write([]byte("day 1\n"))
+		g.Expect(f.nextDaily).To(BeTemporally(">", time.Now()), "next rotation should be in future")
+		// pretend midnight has passed
+		f.nextDaily = time.Now().Add(-time.Second)
+		_, _ = f.Write([]byte("day 2\n"))
+		g.Expect(ListBackups(g, location)).To(HaveLen(1), "file should be rotated after midnight")
+		g.Expect(os.ReadFile(location)).To(BeEquivalentTo("day 2\n"), "new file should have new entries")
+	}
+}
+
+func SubTestRotationRetention() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		location := filepath.Join(t.TempDir(), "test.log")
+		f, e := newRotatingFile(location, RotationProperties{MaxBackups: 2, Compress: true})
+		g.Expect(e).To(Succeed(), "creating file should not fail")
+		defer func() { _ = f.Close() }()
+
+		for i := 0; i < 4; i++ {
+			_, _ = f.Write([]byte("entry\n"))
+			f.mtx.Lock()
+			g.Expect(f.rotate()).To(Succeed(), "rotate should not fail")
+			f.mtx.Unlock()
+			// make sure backup names are unique
+			time.Sleep(2 * time.Millisecond)
+		}
+		g.Eventually(func() []string { return ListBackups(g, location) }).
+			Should(And(HaveLen(2), HaveEach(HaveSuffix(".log.gz"))), "only latest backups should be kept and compressed")
+	}
+}
+
+func SubTestRotateConcurrently() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		const goroutines = 8
+		const lines = 2000
+		location := filepath.Join(t.TempDir(), "test.log")
+		f, e := newRotatingFile(location, RotationProperties{MaxSize: 1})
+		g.Expect(e).To(Succeed(), "creating file should not fail")
+		defer func() { _ = f.Close() }()
+
+		line := []byte(strings.Repeat("x", 199) + "\n")
+		var wg sync.WaitGroup
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < lines; j++ {
+					_, _ = f.Write(line)
+				}
+			}()
+		}
+		wg.Wait()
+
+		backups := ListBackups(g, location)
+		g.Expect(len(backups)).To(BeNumerically(">=", 2), "file should be rotated")
+		var total int
+		for _, path := range append(backups, location) {
+			data, e := os.ReadFile(path)
+			g.Expect(e).To(Succeed(), "reading file should not fail")
+			g.Expect(len(data)%len(line)).To(BeZero(), "lines should not be interleaved")
+			g.Expect(len(data)).To(BeNumerically("<=", megabyte), "file should not exceed max size")
+			total += len(data) / len(line)
+		}
+		g.Expect(total).To(Equal(goroutines*lines), "no line should be lost")
+	}
+}
+
+func SubTestRotateFailure() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		var attempts int
+		renameFile = func(_, _ string) error {
+			attempts++
+			return os.ErrPermission
+		}
+		defer func() { renameFile = os.Rename }()
+
+		location := filepath.Join(t.TempDir(), "test.log")
+		f, e := newRotatingFile(location, RotationProperties{MaxSize: 1})
+		g.Expect(e).To(Succeed(), "creating file should not fail")
+		defer func() { _ = f.Close() }()
+
+		chunk := bytes.Repeat([]byte("x"), 600*1024)
+		for i := 0; i < 3; i++ {
+			_, e = f.Write(chunk)
+			g.Expect(e).To(Succeed(), "write should not fail when rotation fails")
+		}
+		g.Expect(attempts).To(Equal(1), "rotation should not be attempted again on next write")
+		AssertFileSize(g, location, 3*len(chunk))
+
+		// retry after backoff
+		renameFile = os.Rename
+		f.retryRotateAt = time.Now().Add(-time.Second)
+		_, e = f.Write(chunk)
+		g.Expect(e).To(Succeed(), "write should not fail")
+		g.Expect(ListBackups(g, location)).To(HaveLen(1), "file should be rotated after backoff")
+		AssertFileSize(g, location, len(chunk))
+	}
+}
+
+func SubTestRotateCloseFailure() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		var attempts int
+		closeFile = func(file *os.File) error {
+			attempts++
+			_ = file.Close()
+			return os.ErrInvalid
+		}
+		defer func() { closeFile = (*os.File).Close }()
+
+		location := filepath.Join(t.TempDir(), "test.log")
+		f, e := newRotatingFile(location, RotationProperties{MaxSize: 1})
+		g.Expect(e).To(Succeed(), "creating file should not fail")
+		defer func() { _ = f.Close() }()
+
+		chunk := bytes.Repeat([]byte("x"), 600*1024)
+		for i := 0; i < 3; i++ {
+			_, e = f.Write(chunk)
+			g.Expect(e).To(Succeed(), "write should not fail when closing file fails")
+		}
+		g.Expect(attempts).To(Equal(1), "rotation should not be attempted again on next write")
+		g.Expect(ListBackups(g, location)).To(BeEmpty(), "file should not be rotated")
+		AssertFileSize(g, location, 3*len(chunk))
+	}
+}
+
+func SubTestReopenFiles() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		location := filepath.Join(t.TempDir(), "test.log")
+		f, e := newRotatingFile(location, RotationProperties{})
+		g.Expect(e).To(Succeed(), "creating file should not fail")
+		defer func() { _ = f.Close() }()
+
+		// rotated by external tool
+		_, _ = f.Write([]byte("before\n"))
+		g.Expect(os.Rename(location, location+".1")).To(Succeed(), "rename should not fail")
+		ReopenFiles()
+		_, _ = f.Write([]byte("after\n"))
+
+		g.Expect(os.ReadFile(location+".1")).To(BeEquivalentTo("before\n"), "renamed file should have old entries")
+		g.Expect(os.ReadFile(location)).To(BeEquivalentTo("after\n"), "reopened file should have new entries")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func ListBackups(g *gomega.WithT, location string) []string {
+	matches, e := filepath.Glob(strings.TrimSuffix(location, ".log") + "-*")
+	g.Expect(e).To(Succeed(), "listing backups should not fail")
+	return matches
+}
+
+func AssertFileSize(g *gomega.WithT, path string, expected int) {
+	stat, e := os.Stat(path)
+	g.Expect(e).To(Succeed(), "file should exist")
+	g.Expect(stat.Size()).To(BeEquivalentTo(expected), "file should have correct size")
+}
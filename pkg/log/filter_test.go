@@ -0,0 +1,249 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"os"
+	"testing"
+)
+
+/*************************
+	Tests
+ *************************/
+
+func TestLogFilters(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.SubTestSetup(SubSetupClearLogOutput()),
+		test.GomegaSubTest(SubTestSampling(), "Sampling"),
+		test.GomegaSubTest(SubTestRateLimit(), "RateLimit"),
+		test.GomegaSubTest(SubTestSamplingWithRateLimit(), "SamplingWithRateLimit"),
+		test.GomegaSubTest(SubTestRedactKeys(), "RedactKeys"),
+		test.GomegaSubTest(SubTestRedactPatterns(), "RedactPatterns"),
+		test.GomegaSubTest(SubTestRedactStructTags(), "RedactStructTags"),
+		test.GomegaSubTest(SubTestRedactNilValues(), "RedactNilValues"),
+		test.GomegaSubTest(SubTestRedactUntaggedStructs(), "RedactUntaggedStructs"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestSampling() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		f := NewTestFilterFactory(g)
+		sampled := f.createLogger("TestLogger.Sampled.Child")
+		for i := 0; i < 10; i++ {
+			sampled.Info("same message")
+		}
+		// first 2, then 1 in 3 of the remaining 8
+		AssertEachJsonLogEntry(g, 4, NewExpectedLog(ExpectCaller(`(log/)?filter_test\.go:[0-9]+`), ExpectName("TestLogger.Sampled.Child"), ExpectLevel(LevelInfo), ExpectMsg("same message")))
+
+		// different messages are sampled separately, other loggers are not sampled
+		SubSetupClearLogOutput()(ctx, t)
+		sampled.Info("message 1")
+		sampled.Info("message 2")
+		other := f.createLogger("TestLogger.Other")
+		for i := 0; i < 10; i++ {
+			other.Info("same message")
+		}
+		g.Expect(CountLogEntries(g, LogOutputJson)).To(Equal(12), "log should contain correct number of entries")
+	}
+}
+
+func SubTestRateLimit() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		f := NewTestFilterFactory(g)
+		limited := f.createLogger("TestLogger.Limited")
+		for i := 0; i < 10; i++ {
+			limited.WithKV("index", i).Infof("message %d", i)
+		}
+		g.Expect(CountLogEntries(g, LogOutputJson)).To(Equal(5), "log should be rate limited")
+	}
+}
+
+func SubTestSamplingWithRateLimit() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		f := NewTestFilterFactory(g)
+		l := f.createLogger("TestLogger.SampledLimited")
+		for i := 0; i < 10; i++ {
+			l.Info("same message")
+		}
+		// sampler passes 4 (first 2, then 1 in 3 of the remaining 8), entries dropped by sampler don't count toward rate limit
+		g.Expect(CountLogEntries(g, LogOutputJson)).To(Equal(3), "log should be sampled then rate limited")
+	}
+}
+
+func SubTestRedactKeys() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		f := NewTestFilterFactory(g)
+		l := f.createLogger("TestLogger")
+		//nolint:staticcheck // string key is used by context-mappings
+		ctx = context.WithValue(ctx, "k-ctx-secret", "s3cret")
+		l.WithContext(ctx).Info("redact keys",
+			"authorization", "Bearer abc", "user", "test",
+			"header", map[string]interface{}{"Authorization": "Bearer abc", "Accept": "*/*"})
+
+		expect := NewExpectedLog(ExpectCaller(`(log/)?filter_test\.go:[0-9]+`), ExpectName("TestLogger"), ExpectLevel(LevelInfo), ExpectMsg("redact keys"),
+			ExpectFields("password", defaultRedactionMask, "authorization", defaultRedactionMask, "user", "test"))
+		AssertLastJsonLogEntry(g, expect)
+		AssertLastTextLogEntry(g, expect)
+		entry := DecodeLastJsonLogEntry(g)
+		g.Expect(entry["header"]).To(HaveKeyWithValue("Authorization", defaultRedactionMask), "nested map should be redacted")
+		g.Expect(entry["header"]).To(HaveKeyWithValue("Accept", "*/*"), "other nested values should be kept")
+	}
+}
+
+func SubTestRedactPatterns() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		f := NewTestFilterFactory(g)
+		l := f.createLogger("TestLogger")
+		l.Infof("calling /api?token=%s&page=1", "abc123")
+		expect := NewExpectedLog(ExpectCaller(`(log/)?filter_test\.go:[0-9]+`), ExpectName("TestLogger"), ExpectLevel(LevelInfo), ExpectMsg("calling /api?token=******&page=1"))
+		AssertLastJsonLogEntry(g, expect)
+		AssertLastTextLogEntry(g, expect)
+
+		l.Info("payment", "card", "1234-5678-9012-3456", "error", errors.New("invalid TOKEN=xyz"))
+		expect = NewExpectedLog(ExpectCaller(`(log/)?filter_test\.go:[0-9]+`), ExpectName("TestLogger"), ExpectLevel(LevelInfo), ExpectMsg("payment"),
+			ExpectFields("card", defaultRedactionMask, "error", "invalid TOKEN=******"))
+		AssertLastJsonLogEntry(g, expect)
+		AssertLastTextLogEntry(g, expect)
+	}
+}
+
+func SubTestRedactStructTags() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		type Credentials struct {
+			Username string `json:"username"`
+			Secret   string `json:"secret" log:"redact"`
+		}
+		f := NewTestFilterFactory(g)
+		l := f.createLogger("TestLogger")
+		l.WithKV("creds", &Credentials{Username: "user", Secret: "s3cret"}).Info("struct tags")
+		entry := DecodeLastJsonLogEntry(g)
+		g.Expect(entry["creds"]).To(HaveKeyWithValue("username", "user"), "untagged field should be kept")
+		g.Expect(entry["creds"]).To(HaveKeyWithValue("secret", defaultRedactionMask), "tagged field should be redacted")
+		text := string(ReadLastLogEntry(g, LogOutputText, 0))
+		g.Expect(text).ToNot(ContainSubstring("s3cret"), "text log should not contain redacted value")
+	}
+}
+
+func SubTestRedactUntaggedStructs() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		type Payment struct {
+			Card string `json:"card"`
+		}
+		type Profile struct {
+			Username string   `json:"username"`
+			Password string   `json:"password"`
+			Callback string   `json:"callback"`
+			Payment  *Payment `json:"payment"`
+		}
+		type Account struct {
+			Profile  Profile   `json:"profile"`
+			Payments []Payment `json:"payments"`
+		}
+		f := NewTestFilterFactory(g)
+		l := f.createLogger("TestLogger")
+		l.WithKV("account", &Account{
+			Profile: Profile{
+				Username: "user",
+				Password: "s3cret",
+				Callback: "/api?token=abc123",
+				Payment:  &Payment{Card: "1234-5678-9012-3456"},
+			},
+			Payments: []Payment{{Card: "1111-2222-3333-4444"}},
+		}).Info("untagged structs")
+
+		entry := DecodeLastJsonLogEntry(g)
+		g.Expect(entry["account"]).To(HaveKey("profile"), "nested struct should be logged")
+		profile := entry["account"].(map[string]interface{})["profile"]
+		g.Expect(profile).To(HaveKeyWithValue("username", "user"), "other fields should be kept")
+		g.Expect(profile).To(HaveKeyWithValue("password", defaultRedactionMask), "field with sensitive key should be redacted")
+		g.Expect(profile).To(HaveKeyWithValue("callback", "/api?token=******"), "field matching pattern should be redacted")
+		g.Expect(profile).To(HaveKeyWithValue("payment", HaveKeyWithValue("card", defaultRedactionMask)), "nested struct pointer should be redacted")
+		g.Expect(entry["account"]).To(HaveKeyWithValue("payments", ConsistOf(HaveKeyWithValue("card", defaultRedactionMask))), "structs in slice should be redacted")
+		text := string(ReadLastLogEntry(g, LogOutputText, 0))
+		g.Expect(text).ToNot(ContainSubstring("s3cret"), "text log should not contain redacted value")
+		g.Expect(text).ToNot(ContainSubstring("1234-5678"), "text log should not contain redacted value")
+
+		// cyclic references
+		type Node struct {
+			Password string `json:"password"`
+			Next     *Node  `json:"next"`
+		}
+		node := &Node{Password: "s3cret"}
+		node.Next = node
+		g.Expect(func() {
+			l.WithKV("node", node).Info("cyclic struct")
+		}).ToNot(Panic(), "logging cyclic struct should not panic")
+	}
+}
+
+func SubTestRedactNilValues() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		f := NewTestFilterFactory(g)
+		l := f.createLogger("TestLogger")
+		var err *nilPanicError
+		var stringer *nilPanicError
+		g.Expect(func() {
+			l.Info("nil values", "error", err, "value", fmt.Stringer(stringer))
+		}).ToNot(Panic(), "logging nil pointers should not panic")
+		entry := DecodeLastJsonLogEntry(g)
+		g.Expect(entry).To(HaveKeyWithValue(LogKeyMessage, "nil values"), "entry should be logged")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+// nilPanicError panics when its methods are invoked on nil pointer
+type nilPanicError struct {
+	msg string
+}
+
+func (e *nilPanicError) Error() string {
+	return e.msg
+}
+
+func (e *nilPanicError) String() string {
+	return e.msg
+}
+
+func NewTestFilterFactory(g *gomega.WithT) *zapLoggerFactory {
+	p := BindProperties(g, os.DirFS("testdata"), "filters.yml")
+	return newZapLoggerFactory(&p)
+}
+
+func CountLogEntries(g *gomega.WithT, logPath string) int {
+	return ForEachLogEntry(g, logPath, func([]byte) {})
+}
+
+func DecodeLastJsonLogEntry(g *gomega.WithT) map[string]interface{} {
+	var decoded map[string]interface{}
+	line := ReadLastLogEntry(g, LogOutputJson, 0)
+	g.Expect(json.Unmarshal(line, &decoded)).To(Succeed(), "JSON log should be valid JSON")
+	return decoded
+}
@@ -19,8 +19,22 @@ package log
 import (
 	"dario.cat/mergo"
 	"strings"
+	"sync"
+	"time"
 )
 
+var (
+	levelMtx sync.Mutex
+	reverts  = map[string]*levelRevert{}
+)
+
+// levelRevert records the configured level before a temporary change, and the timer that restores it
+type levelRevert struct {
+	prefix   string
+	original *LoggingLevel
+	timer    *time.Timer
+}
+
 // LevelConfig is a read-only carrier struct that stores LoggingLevel configuration of each logger
 type LevelConfig struct {
 	Name            string
@@ -31,11 +45,71 @@ type LevelConfig struct {
 // SetLevel set/unset logging level of all loggers with given prefix
 // function returns actual number of affected loggers
 func SetLevel(prefix string, logLevel *LoggingLevel) int {
+	levelMtx.Lock()
+	defer levelMtx.Unlock()
+	cancelRevert(loggerKey(prefix))
 	return factory.setLevel(prefix, logLevel)
 }
 
+// SetLevelWithTTL is similar to SetLevel, but the previously configured level is restored after given TTL.
+// Non-positive TTL is same as SetLevel.
+// If the same prefix is changed again before it's restored, the original level is kept and the timer is reset.
+func SetLevelWithTTL(prefix string, logLevel *LoggingLevel, ttl time.Duration) int {
+	if ttl <= 0 {
+		return SetLevel(prefix, logLevel)
+	}
+	levelMtx.Lock()
+	defer levelMtx.Unlock()
+	key := loggerKey(prefix)
+	r, ok := reverts[key]
+	if ok {
+		r.timer.Stop()
+	} else {
+		r = &levelRevert{prefix: prefix, original: configuredLevel(key)}
+		reverts[key] = r
+	}
+	r.timer = time.AfterFunc(ttl, func() { revertLevel(key, r) })
+	return factory.setLevel(prefix, logLevel)
+}
+
+// configuredLevel returns currently configured level of given key. nil if not configured
+func configuredLevel(key string) *LoggingLevel {
+	if isRootKey(key) {
+		lvl := factory.rootLogLevel
+		return &lvl
+	}
+	if lvl, ok := factory.logLevels[key]; ok {
+		return &lvl
+	}
+	return nil
+}
+
+func isRootKey(key string) bool {
+	return key == "" || key == keyLevelDefault || key == loggerKey(nameLevelDefault)
+}
+
+func cancelRevert(key string) {
+	if r, ok := reverts[key]; ok {
+		r.timer.Stop()
+		delete(reverts, key)
+	}
+}
+
+func revertLevel(key string, r *levelRevert) {
+	levelMtx.Lock()
+	defer levelMtx.Unlock()
+	// the revert might have been cancelled or replaced while the timer was firing
+	if current, ok := reverts[key]; !ok || current != r {
+		return
+	}
+	delete(reverts, key)
+	factory.setLevel(r.prefix, r.original)
+}
+
 // Levels logger level configuration, the returned map's key is the lower case of logger's name
 func Levels(prefix string) (ret map[string]*LevelConfig) {
+	levelMtx.Lock()
+	defer levelMtx.Unlock()
 	ret = map[string]*LevelConfig{}
 	prefixKey := loggerKey(prefix)
 
@@ -63,10 +137,11 @@ func Levels(prefix string) (ret map[string]*LevelConfig) {
 		}
 	}
 	if prefix == "" {
+		root := factory.rootLogLevel
 		ret[keyLevelDefault] = &LevelConfig{
 			Name:            nameLevelDefault,
-			EffectiveLevel:  &factory.rootLogLevel,
-			ConfiguredLevel: &factory.rootLogLevel,
+			EffectiveLevel:  &root,
+			ConfiguredLevel: &root,
 		}
 	}
 	return
@@ -85,5 +160,11 @@ func UpdateLoggingConfiguration(properties *Properties) error {
 	if err != nil {
 		return err
 	}
+	levelMtx.Lock()
+	defer levelMtx.Unlock()
+	// pending reverts are meaningless after configuration refresh
+	for k := range reverts {
+		cancelRevert(k)
+	}
 	return factory.refresh(mergedProperties)
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/onsi/gomega"
 	. "github.com/onsi/gomega"
 	"testing"
+	"time"
 )
 
 /*************************
@@ -34,6 +35,7 @@ func TestManageLogger(t *testing.T) {
 		test.SubTestSetup(SubSetupClearLogOutput()),
 		test.GomegaSubTest(SubTestManageGetLevel(), "Levels"),
 		test.GomegaSubTest(SubTestManageSetLevel(), "SetLevel"),
+		test.GomegaSubTest(SubTestManageSetLevelWithTTL(), "SetLevelWithTTL"),
 	)
 }
 
@@ -120,6 +122,52 @@ func SubTestManageSetLevel() test.GomegaSubTestFunc {
 	}
 }
 
+func SubTestManageSetLevelWithTTL() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		const LoggerKeyPrefix = `test-logger`
+		const LoggerNamePrefix = `TestLogger`
+		const LoggerName1 = `TestLogger.1`
+		const LoggerKey1 = `test-logger.1`
+		const TTL = 100 * time.Millisecond
+		var rs map[string]*LevelConfig
+
+		_ = New(LoggerName1)
+		lvl := LevelDebug
+		SetLevel("default", &lvl)
+		SetLevel(LoggerNamePrefix, &lvl)
+		SetLevel(LoggerName1, nil)
+
+		// temporary level on logger without configured level
+		lvl = LevelInfo
+		SetLevelWithTTL(LoggerName1, &lvl, TTL)
+		rs = Levels("")
+		AssertLevelConfigs(g, rs, LoggerKey1, LoggerName1, LevelInfo, LevelInfo)
+		// change again before expiry, original level should be kept
+		lvl = LevelWarn
+		SetLevelWithTTL(LoggerName1, &lvl, TTL)
+		AssertLevelConfigs(g, Levels(""), LoggerKey1, LoggerName1, LevelWarn, LevelWarn)
+		g.Eventually(func() map[string]*LevelConfig { return Levels("") }).WithTimeout(time.Second).
+			Should(HaveKeyWithValue(LoggerKey1, HaveField("ConfiguredLevel", BeNil())), "level should be reverted after TTL")
+		AssertLevelConfigs(g, Levels(""), LoggerKey1, LoggerName1, LevelDebug, -1)
+
+		// temporary level on configured prefix
+		lvl = LevelError
+		SetLevelWithTTL(LoggerNamePrefix, &lvl, TTL)
+		AssertLevelConfigs(g, Levels(""), LoggerKeyPrefix, LoggerNamePrefix, LevelError, LevelError)
+		g.Eventually(func() map[string]*LevelConfig { return Levels("") }).WithTimeout(time.Second).
+			Should(HaveKeyWithValue(LoggerKeyPrefix, HaveField("ConfiguredLevel", HaveValue(Equal(LevelDebug)))), "level should be reverted after TTL")
+
+		// permanent change cancels pending revert
+		lvl = LevelWarn
+		SetLevelWithTTL(LoggerNamePrefix, &lvl, TTL)
+		SetLevel(LoggerNamePrefix, &lvl)
+		time.Sleep(2 * TTL)
+		AssertLevelConfigs(g, Levels(""), LoggerKeyPrefix, LoggerNamePrefix, LevelWarn, LevelWarn)
+		lvl = LevelDebug
+		SetLevel(LoggerNamePrefix, &lvl)
+	}
+}
+
 /*************************
 	Helpers
  *************************/
@@ -16,7 +16,10 @@
 
 package log
 
-import "github.com/cisco-open/go-lanai/pkg/utils"
+import (
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"time"
+)
 
 const (
 	defaultTemplate = `{{pad -25 .time}} {{lvl 5 .}} [{{cap -20 .caller | pad 20 | blue}}] {{cap -12 .logger | pad 12 | green}}: {{.msg}} {{kv .}}`
@@ -34,15 +37,46 @@ var defaultFixedFields = utils.NewStringSet(
 // Properties contains logging settings
 // Note:
 //	1. "context-mappings" indicate how to map context key to log key, it's map[context-key]log-key
+//	2. "sampling" is keyed by logger name, settings apply to all loggers with the name as prefix
 type Properties struct {
-	Levels   map[string]LoggingLevel      `json:"levels"`
-	Loggers  map[string]*LoggerProperties `json:"loggers"`
-	Mappings map[string]string            `json:"context-mappings"`
+	Levels    map[string]LoggingLevel       `json:"levels"`
+	Loggers   map[string]*LoggerProperties  `json:"loggers"`
+	Mappings  map[string]string             `json:"context-mappings"`
+	Sampling  map[string]SamplingProperties `json:"sampling"`
+	Redaction RedactionProperties           `json:"redaction"`
+}
+
+// SamplingProperties limits the volume of a logger.
+// Within each interval, the first "first" entries with same level and message are logged, then 1 in "thereafter".
+// Regardless of the message, at most "rate-limit" entries of the logger are logged within each interval. Rate limit applies
+// after sampling, i.e. entries dropped by sampling are not counted.
+type SamplingProperties struct {
+	// Interval of sampling and rate limiting, default to 1s
+	Interval utils.Duration `json:"interval"`
+	// First number of entries with same level and message logged as-is within each interval. Zero disables sampling
+	First int `json:"first"`
+	// Thereafter every Nth entry with same level and message is logged after the first ones. Zero drops the rest
+	Thereafter int `json:"thereafter"`
+	// RateLimit max number of entries of the logger within each interval. Zero means no limit
+	RateLimit int `json:"rate-limit"`
+}
+
+// RedactionProperties masks sensitive values in all loggers, including values extracted with "context-mappings"
+type RedactionProperties struct {
+	// Keys of log KVs whose values are masked, case-insensitive. e.g. "password, authorization"
+	Keys utils.CommaSeparatedSlice `json:"keys"`
+	// Patterns regular expressions of sensitive data, matched substrings in messages and string values are masked.
+	// When the pattern has a capturing group, only the first group is masked. e.g. "(?i)token=([^&\s]+)"
+	Patterns []string `json:"patterns"`
+	// StructTags of struct fields whose values are masked, in format of "key" or "key:value". e.g. "log:redact"
+	StructTags utils.CommaSeparatedSlice `json:"struct-tags"`
+	// Mask replaces sensitive values, default to "******"
+	Mask string `json:"mask"`
 }
 
 // LoggerProperties individual logger setup
 // Note:
-//	1. we currently only support file and console type
+//	1. "location" is the file path when "type" is "file", URL when "type" is "http" and topic name when "type" is "mq"
 //  2. "location" is ignored when "type" is "console"
 // 	3. "template" and "fixed-keys" are ignored when "format" is not "text"
 //	4. "template" is "text/template" compliant template, with "." as log KVs and following added functions:
@@ -50,12 +84,96 @@ type Properties struct {
 //		- "{{level . 5}}" colored level string with fixed length
 //		- "{{coler .key}}" color code (red, green, yellow, gray, cyan) with pipeline support.
 //			e.g. "{{padding .msg 20 | red}}"
+//	5. "sink" is only used by "http" and "mq" types
+//	6. "rotation" is only used by "file" type
 type LoggerProperties struct {
 	Type      LoggerType                `json:"type"`
 	Format    Format                    `json:"format"`
 	Location  string                    `json:"location"`
 	Template  string                    `json:"template"`
 	FixedKeys utils.CommaSeparatedSlice `json:"fixed-keys"`
+	Sink      SinkProperties            `json:"sink"`
+	Rotation  RotationProperties        `json:"rotation"`
+}
+
+// RotationProperties settings of "file" loggers. When none is set, the file is never rotated.
+// To rotate log files by external tools like logrotate, applications can call ReopenFiles after the files are renamed.
+type RotationProperties struct {
+	// MaxSize in megabytes of the file before it's rotated. Zero means no limit
+	MaxSize int `json:"max-size"`
+	// Daily whether to rotate the file at local midnight
+	Daily bool `json:"daily"`
+	// MaxAge how long rotated files are kept, e.g. "168h". Zero means no limit
+	MaxAge utils.Duration `json:"max-age"`
+	// MaxBackups number of rotated files kept. Zero means no limit
+	MaxBackups int `json:"max-backups"`
+	// Compress whether to gzip rotated files
+	Compress bool `json:"compress"`
+}
+
+// SinkProperties settings of asynchronous loggers ("http" and "mq").
+// Log entries are buffered and shipped in batches by a background goroutine.
+type SinkProperties struct {
+	// BufferSize max number of log entries waiting to be shipped
+	BufferSize int `json:"buffer-size"`
+	// BatchSize max number of log entries shipped at once
+	BatchSize int `json:"batch-size"`
+	// FlushInterval max time a log entry waits in buffer before shipped
+	FlushInterval utils.Duration `json:"flush-interval"`
+	// Overflow what to do when buffer is full: "drop-newest", "drop-oldest" or "block"
+	Overflow OverflowPolicy `json:"overflow"`
+	// BlockTimeout how long the logging goroutine waits for buffer space with "block" policy, before dropping the entry
+	BlockTimeout utils.Duration `json:"block-timeout"`
+	// MaxRetries number of retries of a failed batch before it's dropped. Negative value disables retry
+	MaxRetries int `json:"max-retries"`
+	// RetryInterval initial wait time between retries, doubled after each retry
+	RetryInterval utils.Duration `json:"retry-interval"`
+	// Protocol of "http" logger: "json" (JSON array), "loki" (Loki push API) or "opensearch" (OpenSearch bulk API)
+	Protocol HttpProtocol `json:"protocol"`
+	// Compression of "http" logger request body: "gzip" or "none"
+	Compression string `json:"compression"`
+	// Timeout of "http" logger requests
+	Timeout utils.Duration `json:"timeout"`
+	// Headers additional headers of "http" logger requests, e.g. "Authorization"
+	Headers map[string]string `json:"headers"`
+	// Labels of the Loki stream, used with "loki" protocol
+	Labels map[string]string `json:"labels"`
+	// Index name, used with "opensearch" protocol
+	Index string `json:"index"`
+}
+
+func (p SinkProperties) withDefaults() SinkProperties {
+	if p.BufferSize <= 0 {
+		p.BufferSize = 8192
+	}
+	if p.BatchSize <= 0 {
+		p.BatchSize = 256
+	}
+	if p.FlushInterval <= 0 {
+		p.FlushInterval = utils.Duration(time.Second)
+	}
+	if len(p.Overflow) == 0 {
+		p.Overflow = OverflowDropNewest
+	}
+	if p.BlockTimeout <= 0 {
+		p.BlockTimeout = utils.Duration(100 * time.Millisecond)
+	}
+	if p.MaxRetries == 0 {
+		p.MaxRetries = 3
+	}
+	if p.RetryInterval <= 0 {
+		p.RetryInterval = utils.Duration(500 * time.Millisecond)
+	}
+	if len(p.Protocol) == 0 {
+		p.Protocol = ProtocolJson
+	}
+	if len(p.Compression) == 0 {
+		p.Compression = CompressionGzip
+	}
+	if p.Timeout <= 0 {
+		p.Timeout = utils.Duration(10 * time.Second)
+	}
+	return p
 }
 
 func newProperties() *Properties {
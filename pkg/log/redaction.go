@@ -0,0 +1,297 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+const (
+	defaultRedactionMask = "******"
+	// maxRedactionDepth is the maximum depth of nested maps, slices and structs to redact
+	maxRedactionDepth = 32
+)
+
+// redactor masks sensitive values based on RedactionProperties
+type redactor struct {
+	mask     string
+	keys     map[string]struct{}
+	patterns []*regexp.Regexp
+	tags     []structTag
+}
+
+type structTag struct {
+	key   string
+	value string
+}
+
+// newRedactor returns nil if no redaction is configured
+func newRedactor(props RedactionProperties) (*redactor, error) {
+	if len(props.Keys) == 0 && len(props.Patterns) == 0 && len(props.StructTags) == 0 {
+		return nil, nil
+	}
+	r := &redactor{
+		mask: props.Mask,
+		keys: map[string]struct{}{},
+	}
+	if len(r.mask) == 0 {
+		r.mask = defaultRedactionMask
+	}
+	for _, k := range props.Keys {
+		r.keys[strings.ToLower(strings.TrimSpace(k))] = struct{}{}
+	}
+	for _, p := range props.Patterns {
+		regex, e := regexp.Compile(p)
+		if e != nil {
+			return nil, fmt.Errorf("invalid redaction pattern [%s]: %v", p, e)
+		}
+		r.patterns = append(r.patterns, regex)
+	}
+	for _, t := range props.StructTags {
+		split := strings.SplitN(strings.TrimSpace(t), ":", 2)
+		tag := structTag{key: split[0]}
+		if len(split) > 1 {
+			tag.value = split[1]
+		}
+		r.tags = append(r.tags, tag)
+	}
+	return r, nil
+}
+
+// String masks substrings matching any pattern
+func (r *redactor) String(s string) string {
+	for _, regex := range r.patterns {
+		if regex.NumSubexp() == 0 {
+			s = regex.ReplaceAllLiteralString(s, r.mask)
+			continue
+		}
+		s = regex.ReplaceAllStringFunc(s, func(match string) string {
+			loc := regex.FindStringSubmatchIndex(match)
+			if len(loc) < 4 || loc[2] < 0 {
+				return r.mask
+			}
+			return match[:loc[2]] + r.mask + match[loc[3]:]
+		})
+	}
+	return s
+}
+
+// Fields returns redacted copy of given fields. The given slice is not modified
+func (r *redactor) Fields(fields []zapcore.Field) []zapcore.Field {
+	var ret []zapcore.Field
+	for i := range fields {
+		redacted, changed := r.field(fields[i])
+		if !changed {
+			if ret != nil {
+				ret = append(ret, fields[i])
+			}
+			continue
+		}
+		if ret == nil {
+			ret = make([]zapcore.Field, i, len(fields))
+			copy(ret, fields[:i])
+		}
+		ret = append(ret, redacted)
+	}
+	if ret == nil {
+		return fields
+	}
+	return ret
+}
+
+func (r *redactor) field(f zapcore.Field) (zapcore.Field, bool) {
+	if r.isSensitiveKey(f.Key) {
+		return zap.String(f.Key, r.mask), true
+	}
+	switch f.Type {
+	case zapcore.StringType:
+		if s := r.String(f.String); s != f.String {
+			return zap.String(f.Key, s), true
+		}
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			if msg, ok := safeString(err.Error); ok && r.String(msg) != msg {
+				return zap.String(f.Key, r.String(msg)), true
+			}
+		}
+	case zapcore.StringerType:
+		if stringer, ok := f.Interface.(fmt.Stringer); ok && stringer != nil {
+			if s, ok := safeString(stringer.String); ok && r.String(s) != s {
+				return zap.String(f.Key, r.String(s)), true
+			}
+		}
+	case zapcore.ReflectType:
+		if v, changed := r.value(reflect.ValueOf(f.Interface), 0); changed {
+			return zap.Any(f.Key, v), true
+		}
+	}
+	return f, false
+}
+
+// safeString invokes fn and recovers from panic, e.g. String() of nil pointer.
+// When it panics, the field is left as-is and zap encodes the panic the same way as without redaction.
+func safeString(fn func() string) (s string, ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	return fn(), true
+}
+
+func (r *redactor) isSensitiveKey(key string) bool {
+	_, ok := r.keys[strings.ToLower(key)]
+	return ok
+}
+
+// value redacts maps, slices and structs recursively. The returned value is a copy when changed.
+// Recursion stops at maxRedactionDepth to guard against cyclic references.
+func (r *redactor) value(rv reflect.Value, depth int) (interface{}, bool) {
+	if !rv.IsValid() || depth > maxRedactionDepth {
+		return nil, false
+	}
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil, false
+		}
+		return r.value(rv.Elem(), depth+1)
+	case reflect.String:
+		if s := r.String(rv.String()); s != rv.String() {
+			return s, true
+		}
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		ret := make(map[string]interface{}, rv.Len())
+		var changed bool
+		for iter := rv.MapRange(); iter.Next(); {
+			k := iter.Key().String()
+			switch v, ok := r.value(iter.Value(), depth+1); {
+			case r.isSensitiveKey(k):
+				ret[k], changed = r.mask, true
+			case ok:
+				ret[k], changed = v, true
+			default:
+				ret[k] = iter.Value().Interface()
+			}
+		}
+		if changed {
+			return ret, true
+		}
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return nil, false
+		}
+		ret := make([]interface{}, rv.Len())
+		var changed bool
+		for i := 0; i < rv.Len(); i++ {
+			if v, ok := r.value(rv.Index(i), depth+1); ok {
+				ret[i], changed = v, true
+			} else {
+				ret[i] = rv.Index(i).Interface()
+			}
+		}
+		if changed {
+			return ret, true
+		}
+	case reflect.Struct:
+		return r.structValue(rv, depth)
+	}
+	return nil, false
+}
+
+// structValue converts struct to map with JSON field names, masking tagged fields and fields with sensitive key,
+// and redacting field values recursively. The map is returned only when any field is changed
+func (r *redactor) structValue(rv reflect.Value, depth int) (map[string]interface{}, bool) {
+	t := rv.Type()
+	ret := make(map[string]interface{}, t.NumField())
+	var changed bool
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := sf.Name
+		if tag, ok := sf.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; len(n) != 0 {
+				name = n
+			}
+		}
+		if r.isTagged(sf) || r.isSensitiveKey(name) {
+			ret[name], changed = r.mask, true
+			continue
+		}
+		if v, ok := r.value(rv.Field(i), depth+1); ok {
+			ret[name], changed = v, true
+		} else {
+			ret[name] = rv.Field(i).Interface()
+		}
+	}
+	return ret, changed
+}
+
+func (r *redactor) isTagged(sf reflect.StructField) bool {
+	for _, tag := range r.tags {
+		v, ok := sf.Tag.Lookup(tag.key)
+		if ok && (len(tag.value) == 0 || v == tag.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactingCore is a zapcore.Core that masks sensitive data before entries are encoded
+type redactingCore struct {
+	zapcore.Core
+	redactor *redactor
+}
+
+func newRedactingCore(core zapcore.Core, redactor *redactor) zapcore.Core {
+	if redactor == nil {
+		return core
+	}
+	return &redactingCore{Core: core, redactor: redactor}
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{
+		Core:     c.Core.With(c.redactor.Fields(fields)),
+		redactor: c.redactor,
+	}
+}
+
+func (c *redactingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = c.redactor.String(ent.Message)
+	return c.Core.Write(ent, c.redactor.Fields(fields))
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/log/internal"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const defaultSamplingInterval = time.Second
+
+// resolveSampling finds SamplingProperties with the longest matching logger prefix
+func (f *zapLoggerFactory) resolveSampling(key string) (SamplingProperties, bool) {
+	var found SamplingProperties
+	var longest = -1
+	for k, v := range f.properties.Sampling {
+		prefix := loggerKey(k)
+		if len(prefix) > longest && (key == prefix || strings.HasPrefix(key, prefix+keySeparator)) {
+			found, longest = v, len(prefix)
+		}
+	}
+	return found, longest >= 0
+}
+
+// newCore creates zapcore.Core of the logger with given key, with sampling and rate limiting applied if configured
+func (f *zapLoggerFactory) newCore(key string, leveler zap.AtomicLevel) zapcore.Core {
+	core := f.coreCreator(leveler)
+	props, ok := f.resolveSampling(key)
+	if !ok {
+		return core
+	}
+	termAware, isTerm := core.(internal.TerminalAware)
+	isTerm = isTerm && termAware.IsTerminal()
+	interval := time.Duration(props.Interval)
+	if interval <= 0 {
+		interval = defaultSamplingInterval
+	}
+	// rate limiter goes inside the sampler, so entries dropped by the sampler don't consume the rate limit
+	if props.RateLimit > 0 {
+		core = newRateLimitedCore(core, interval, props.RateLimit)
+	}
+	if props.First > 0 {
+		core = zapcore.NewSamplerWithOptions(core, interval, props.First, props.Thereafter)
+	}
+	if isTerm {
+		return internal.ZapTerminalCore{Core: core}
+	}
+	return core
+}
+
+// rateLimitedCore is a zapcore.Core that drops entries exceeding the limit within each interval.
+// Counters are shared with cores derived via With, so the limit applies to the logger as a whole.
+type rateLimitedCore struct {
+	zapcore.Core
+	counter *rateCounter
+}
+
+type rateCounter struct {
+	interval int64
+	limit    int64
+	resetAt  atomic.Int64
+	count    atomic.Int64
+}
+
+func newRateLimitedCore(core zapcore.Core, interval time.Duration, limit int) zapcore.Core {
+	return &rateLimitedCore{
+		Core: core,
+		counter: &rateCounter{
+			interval: int64(interval),
+			limit:    int64(limit),
+		},
+	}
+}
+
+func (c *rateLimitedCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitedCore{
+		Core:    c.Core.With(fields),
+		counter: c.counter,
+	}
+}
+
+func (c *rateLimitedCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	if !c.counter.allow(ent.Time) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+func (c *rateCounter) allow(t time.Time) bool {
+	now := t.UnixNano()
+	resetAt := c.resetAt.Load()
+	if now >= resetAt && c.resetAt.CompareAndSwap(resetAt, now+c.interval) {
+		c.count.Store(0)
+	}
+	return c.count.Add(1) <= c.limit
+}
@@ -0,0 +1,354 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type OverflowPolicy string
+
+const (
+	// OverflowDropNewest drops the entry being logged when buffer is full
+	OverflowDropNewest OverflowPolicy = "drop-newest"
+	// OverflowDropOldest drops the oldest buffered entry to make room for the entry being logged
+	OverflowDropOldest OverflowPolicy = "drop-oldest"
+	// OverflowBlock waits for buffer space up to SinkProperties.BlockTimeout, then drops the entry being logged
+	OverflowBlock OverflowPolicy = "block"
+)
+
+const (
+	DropReasonOverflow = "overflow"
+	DropReasonFailure  = "failure"
+	DropReasonClosed   = "closed"
+)
+
+const (
+	syncTimeout     = 5 * time.Second
+	maxRetryBackoff = 30 * time.Second
+)
+
+// ErrSinkNotReady is returned by SinkWriter when the destination is not available yet, e.g. message broker is not connected.
+// Entries are kept in buffer and retried without counting as failure.
+var ErrSinkNotReady = errors.New("log sink is not ready")
+
+// nonRetryableError indicates the batch would fail again if retried, e.g. rejected by the destination
+type nonRetryableError struct {
+	error
+}
+
+func (e nonRetryableError) Unwrap() error {
+	return e.error
+}
+
+// PartialWriteError is returned by SinkWriter or MQPublisher when only part of the batch is written.
+// Remaining entries are retried and Rejected is the number of entries that would fail again if retried.
+// Other entries of the batch are considered as shipped, so they are not duplicated by retries.
+type PartialWriteError struct {
+	Remaining []SinkEntry
+	Rejected  int
+	Err       error
+}
+
+func (e PartialWriteError) Error() string {
+	return e.Err.Error()
+}
+
+func (e PartialWriteError) Unwrap() error {
+	return e.Err
+}
+
+// SinkEntry is an encoded log entry waiting to be shipped
+type SinkEntry struct {
+	Time time.Time
+	Data []byte
+}
+
+// SinkWriter ships a batch of log entries to a remote destination
+type SinkWriter interface {
+	WriteBatch(ctx context.Context, entries []SinkEntry) error
+}
+
+// SinkObserver receives statistics of asynchronous loggers, typically used for self-metrics.
+// Implementations must not log using this package and must not block.
+type SinkObserver interface {
+	// Sent is called when a batch of n entries is shipped
+	Sent(sink string, n int)
+	// Dropped is called when n entries are discarded, with one of the DropReasonXXX
+	Dropped(sink string, n int, reason string)
+	// Retried is called when a failed batch is retried
+	Retried(sink string)
+}
+
+var sinkObserver atomic.Value
+
+type observerHolder struct {
+	SinkObserver
+}
+
+// SetSinkObserver installs global SinkObserver of all asynchronous loggers. nil removes current one.
+func SetSinkObserver(observer SinkObserver) {
+	sinkObserver.Store(observerHolder{SinkObserver: observer})
+}
+
+func currentObserver() SinkObserver {
+	if h, ok := sinkObserver.Load().(observerHolder); ok && h.SinkObserver != nil {
+		return h.SinkObserver
+	}
+	return nil
+}
+
+// asyncSink implements zapcore.WriteSyncer and internal.TerminalAware.
+// Entries are buffered in a bounded channel and shipped by a single background goroutine,
+// so slow or failing SinkWriter never blocks logging goroutines beyond the configured overflow policy.
+type asyncSink struct {
+	name     string
+	props    SinkProperties
+	writer   SinkWriter
+	queue    chan SinkEntry
+	flushReq chan chan struct{}
+	done     chan struct{}
+	closeMtx sync.RWMutex
+	closed   bool
+	failing  bool
+}
+
+func newAsyncSink(name string, props SinkProperties, writer SinkWriter) *asyncSink {
+	props = props.withDefaults()
+	s := &asyncSink{
+		name:     name,
+		props:    props,
+		writer:   writer,
+		queue:    make(chan SinkEntry, props.BufferSize),
+		flushReq: make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Write implements io.Writer. Given bytes are copied, since zap reuses its buffer
+func (s *asyncSink) Write(p []byte) (int, error) {
+	s.closeMtx.RLock()
+	defer s.closeMtx.RUnlock()
+	if s.closed {
+		s.dropped(1, DropReasonClosed)
+		return len(p), nil
+	}
+
+	entry := SinkEntry{Time: time.Now(), Data: make([]byte, len(p))}
+	copy(entry.Data, p)
+	select {
+	case s.queue <- entry:
+		return len(p), nil
+	default:
+	}
+
+	switch s.props.Overflow {
+	case OverflowDropOldest:
+		for {
+			select {
+			case <-s.queue:
+				s.dropped(1, DropReasonOverflow)
+			default:
+			}
+			select {
+			case s.queue <- entry:
+				return len(p), nil
+			default:
+			}
+		}
+	case OverflowBlock:
+		timer := time.NewTimer(time.Duration(s.props.BlockTimeout))
+		defer timer.Stop()
+		select {
+		case s.queue <- entry:
+			return len(p), nil
+		case <-timer.C:
+		}
+	}
+	s.dropped(1, DropReasonOverflow)
+	return len(p), nil
+}
+
+// Sync implements zapcore.WriteSyncer. It waits for buffered entries to be shipped, up to a limited time
+func (s *asyncSink) Sync() error {
+	ack := make(chan struct{})
+	timer := time.NewTimer(syncTimeout)
+	defer timer.Stop()
+	select {
+	case s.flushReq <- ack:
+	case <-s.done:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("timeout while flushing log sink [%s]", s.name)
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("timeout while flushing log sink [%s]", s.name)
+	}
+}
+
+// IsTerminal implements internal.TerminalAware
+func (s *asyncSink) IsTerminal() bool {
+	return false
+}
+
+// Close stops accepting new entries. Buffered entries are shipped in background.
+func (s *asyncSink) Close() error {
+	s.closeMtx.Lock()
+	defer s.closeMtx.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	return nil
+}
+
+func (s *asyncSink) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(time.Duration(s.props.FlushInterval))
+	defer ticker.Stop()
+	batch := make([]SinkEntry, 0, s.props.BatchSize)
+	for {
+		select {
+		case entry, ok := <-s.queue:
+			if !ok {
+				s.ship(batch)
+				return
+			}
+			if batch = append(batch, entry); len(batch) >= s.props.BatchSize {
+				batch = s.ship(batch)
+			}
+		case <-ticker.C:
+			batch = s.ship(batch)
+		case ack := <-s.flushReq:
+			batch = s.drain(batch)
+			batch = s.ship(batch)
+			close(ack)
+		}
+	}
+}
+
+// drain moves currently buffered entries into batch, shipping full batches along the way
+func (s *asyncSink) drain(batch []SinkEntry) []SinkEntry {
+	for {
+		select {
+		case entry, ok := <-s.queue:
+			if !ok {
+				return batch
+			}
+			if batch = append(batch, entry); len(batch) >= s.props.BatchSize {
+				batch = s.ship(batch)
+			}
+		default:
+			return batch
+		}
+	}
+}
+
+// ship writes the batch with retries and returns an emptied batch for reuse
+func (s *asyncSink) ship(batch []SinkEntry) []SinkEntry {
+	if len(batch) == 0 {
+		return batch
+	}
+	backoff := time.Duration(s.props.RetryInterval)
+	for attempt := 0; ; {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.props.Timeout))
+		e := s.writer.WriteBatch(ctx, batch)
+		cancel()
+		var partial PartialWriteError
+		if errors.As(e, &partial) {
+			if batch = s.partiallyShipped(batch, partial); len(batch) == 0 {
+				return batch
+			}
+		}
+		switch {
+		case e == nil:
+			s.recovered()
+			if o := currentObserver(); o != nil {
+				o.Sent(s.name, len(batch))
+			}
+			return batch[:0]
+		case errors.Is(e, ErrSinkNotReady) && !s.isClosed():
+			// keep the entries until the destination is ready. New entries are subject to overflow policy meanwhile
+			time.Sleep(time.Duration(s.props.FlushInterval))
+			continue
+		case attempt >= s.props.MaxRetries || errors.As(e, &nonRetryableError{}):
+			s.failed(e)
+			s.dropped(len(batch), DropReasonFailure)
+			return batch[:0]
+		}
+		attempt++
+		if o := currentObserver(); o != nil {
+			o.Retried(s.name)
+		}
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+// partiallyShipped reports shipped and rejected entries, and returns the remaining entries, reusing the batch
+func (s *asyncSink) partiallyShipped(batch []SinkEntry, e PartialWriteError) []SinkEntry {
+	if o := currentObserver(); o != nil {
+		if sent := len(batch) - len(e.Remaining) - e.Rejected; sent > 0 {
+			o.Sent(s.name, sent)
+		}
+	}
+	if e.Rejected > 0 {
+		s.failed(e)
+		s.dropped(e.Rejected, DropReasonFailure)
+	}
+	return batch[:copy(batch, e.Remaining)]
+}
+
+func (s *asyncSink) isClosed() bool {
+	s.closeMtx.RLock()
+	defer s.closeMtx.RUnlock()
+	return s.closed
+}
+
+func (s *asyncSink) dropped(n int, reason string) {
+	if o := currentObserver(); o != nil {
+		o.Dropped(s.name, n, reason)
+	}
+}
+
+// failed reports the failure to stderr once until the sink recovers. We cannot use logger here.
+func (s *asyncSink) failed(e error) {
+	if !s.failing {
+		s.failing = true
+		_, _ = fmt.Fprintf(os.Stderr, "log sink [%s] failed, entries are dropped: %v\n", s.name, e)
+	}
+}
+
+func (s *asyncSink) recovered() {
+	if s.failing {
+		s.failing = false
+		_, _ = fmt.Fprintf(os.Stderr, "log sink [%s] recovered\n", s.name)
+	}
+}
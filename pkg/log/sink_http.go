@@ -0,0 +1,246 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+type HttpProtocol string
+
+const (
+	// ProtocolJson posts a JSON array of log entries
+	ProtocolJson HttpProtocol = "json"
+	// ProtocolLoki posts to Loki push API, e.g. "http://loki:3100/loki/api/v1/push"
+	ProtocolLoki HttpProtocol = "loki"
+	// ProtocolOpenSearch posts to OpenSearch bulk API, e.g. "http://opensearch:9200/_bulk"
+	ProtocolOpenSearch HttpProtocol = "opensearch"
+)
+
+const (
+	CompressionGzip = "gzip"
+	CompressionNone = "none"
+)
+
+// httpSinkWriter implements SinkWriter and ships log entries with HTTP POST
+type httpSinkWriter struct {
+	url    string
+	props  SinkProperties
+	client *http.Client
+	encode func(entries []SinkEntry) ([]byte, string, error)
+	// verify checks body of successful response, optional
+	verify func(entries []SinkEntry, body io.Reader) error
+}
+
+func newHttpSinkWriter(url string, props SinkProperties) (*httpSinkWriter, error) {
+	if url == "" {
+		return nil, fmt.Errorf("location is missing for http logger")
+	}
+	props = props.withDefaults()
+	w := &httpSinkWriter{
+		url:    url,
+		props:  props,
+		client: &http.Client{},
+	}
+	switch props.Protocol {
+	case ProtocolJson:
+		w.encode = encodeJsonArray
+	case ProtocolLoki:
+		w.encode = w.encodeLoki
+	case ProtocolOpenSearch:
+		if props.Index == "" {
+			return nil, fmt.Errorf("index is missing for http logger with opensearch protocol")
+		}
+		w.encode = w.encodeOpenSearchBulk
+		w.verify = verifyOpenSearchBulk
+	default:
+		return nil, fmt.Errorf("unsupported http logger protocol: %v", props.Protocol)
+	}
+	return w, nil
+}
+
+func (w *httpSinkWriter) WriteBatch(ctx context.Context, entries []SinkEntry) error {
+	body, contentType, e := w.encode(entries)
+	if e != nil {
+		return nonRetryableError{e}
+	}
+	var reader io.Reader = bytes.NewReader(body)
+	if w.props.Compression == CompressionGzip {
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		if _, e := gw.Write(body); e != nil {
+			return e
+		}
+		if e := gw.Close(); e != nil {
+			return e
+		}
+		reader = &buf
+	}
+
+	req, e := http.NewRequestWithContext(ctx, http.MethodPost, w.url, reader)
+	if e != nil {
+		return nonRetryableError{e}
+	}
+	req.Header.Set("Content-Type", contentType)
+	if w.props.Compression == CompressionGzip {
+		req.Header.Set("Content-Encoding", CompressionGzip)
+	}
+	for k, v := range w.props.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, e := w.client.Do(req)
+	if e != nil {
+		return e
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		if w.verify != nil {
+			return w.verify(entries, resp.Body)
+		}
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("log shipping failed with status %d", resp.StatusCode)
+	default:
+		return nonRetryableError{fmt.Errorf("log shipping rejected with status %d", resp.StatusCode)}
+	}
+}
+
+// encodeJsonArray encodes entries as JSON array. Entries not in JSON format are encoded as JSON string.
+func encodeJsonArray(entries []SinkEntry) ([]byte, string, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, entry := range entries {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		if e := writeJsonDoc(&buf, entry); e != nil {
+			return nil, "", e
+		}
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), "application/json", nil
+}
+
+func (w *httpSinkWriter) encodeLoki(entries []SinkEntry) ([]byte, string, error) {
+	type stream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+	s := stream{
+		Stream: w.props.Labels,
+		Values: make([][2]string, len(entries)),
+	}
+	if s.Stream == nil {
+		s.Stream = map[string]string{}
+	}
+	for i, entry := range entries {
+		s.Values[i] = [2]string{strconv.FormatInt(entry.Time.UnixNano(), 10), string(bytes.TrimSpace(entry.Data))}
+	}
+	data, e := json.Marshal(map[string]interface{}{"streams": []stream{s}})
+	return data, "application/json", e
+}
+
+func (w *httpSinkWriter) encodeOpenSearchBulk(entries []SinkEntry) ([]byte, string, error) {
+	action, e := json.Marshal(map[string]interface{}{"index": map[string]string{"_index": w.props.Index}})
+	if e != nil {
+		return nil, "", e
+	}
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		buf.Write(action)
+		buf.WriteByte('\n')
+		if e := writeJsonDoc(&buf, entry); e != nil {
+			return nil, "", e
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), "application/x-ndjson", nil
+}
+
+// verifyOpenSearchBulk checks per-item results of bulk API. Bulk API responds 200 even if some items failed.
+// Items failed with 429 or 5xx are retried, other failed items are rejected.
+// See https://opensearch.org/docs/latest/api-reference/document-apis/bulk/#response
+func verifyOpenSearchBulk(entries []SinkEntry, body io.Reader) error {
+	var resp struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int             `json:"status"`
+			Error  json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if e := json.NewDecoder(body).Decode(&resp); e != nil || !resp.Errors {
+		return nil
+	}
+	if len(resp.Items) != len(entries) {
+		return nonRetryableError{fmt.Errorf("log shipping failed with unexpected number of bulk items: %d of %d", len(resp.Items), len(entries))}
+	}
+	var retry []SinkEntry
+	var rejected int
+	var cause json.RawMessage
+	for i, item := range resp.Items {
+		for _, result := range item {
+			switch {
+			case result.Status >= 200 && result.Status < 300:
+			case result.Status == http.StatusTooManyRequests || result.Status >= 500:
+				retry = append(retry, entries[i])
+			default:
+				rejected++
+			}
+			if result.Status >= 300 && cause == nil {
+				cause = result.Error
+			}
+		}
+	}
+	if len(retry) == 0 && rejected == 0 {
+		return nil
+	}
+	return PartialWriteError{
+		Remaining: retry,
+		Rejected:  rejected,
+		Err:       fmt.Errorf("log shipping failed for %d of %d bulk items: %s", len(retry)+rejected, len(entries), cause),
+	}
+}
+
+// writeJsonDoc writes entry as JSON object. Entries not in JSON format are wrapped as message with timestamp
+func writeJsonDoc(buf *bytes.Buffer, entry SinkEntry) error {
+	data := bytes.TrimSpace(entry.Data)
+	if json.Valid(data) {
+		buf.Write(data)
+		return nil
+	}
+	doc, e := json.Marshal(map[string]interface{}{
+		LogKeyTimestamp: entry.Time.UTC(),
+		LogKeyMessage:   string(data),
+	})
+	if e != nil {
+		return e
+	}
+	buf.Write(doc)
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// MQPublisher publishes log entries of "mq" loggers to message queue topics.
+// Since this package cannot depend on messaging packages, the implementation is installed via SetMQPublisher
+// once the message broker is available. Until then, entries are kept in each logger's buffer.
+// PartialWriteError should be returned when only some entries are published, so they are not duplicated by retries.
+type MQPublisher interface {
+	Publish(ctx context.Context, topic string, entries []SinkEntry) error
+}
+
+var mqPublisher atomic.Value
+
+type mqPublisherHolder struct {
+	MQPublisher
+}
+
+// SetMQPublisher installs global MQPublisher used by all "mq" loggers. nil removes current one.
+func SetMQPublisher(publisher MQPublisher) {
+	mqPublisher.Store(mqPublisherHolder{MQPublisher: publisher})
+}
+
+// MQTopics returns topics of all configured "mq" loggers
+func MQTopics() (topics []string) {
+	levelMtx.Lock()
+	defer levelMtx.Unlock()
+	for _, props := range factory.properties.Loggers {
+		if props != nil && props.Type == TypeMQ && len(props.Location) != 0 {
+			topics = append(topics, props.Location)
+		}
+	}
+	return
+}
+
+// mqSinkWriter implements SinkWriter, delegating to global MQPublisher
+type mqSinkWriter struct {
+	topic string
+}
+
+func (w mqSinkWriter) WriteBatch(ctx context.Context, entries []SinkEntry) error {
+	h, ok := mqPublisher.Load().(mqPublisherHolder)
+	if !ok || h.MQPublisher == nil {
+		return ErrSinkNotReady
+	}
+	return h.Publish(ctx, w.topic, entries)
+}
@@ -0,0 +1,413 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+/*************************
+	Tests
+ *************************/
+
+func TestAsyncSinks(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestHttpSinkJson(), "HttpSinkJson"),
+		test.GomegaSubTest(SubTestHttpSinkLoki(), "HttpSinkLoki"),
+		test.GomegaSubTest(SubTestHttpSinkOpenSearch(), "HttpSinkOpenSearch"),
+		test.GomegaSubTest(SubTestHttpSinkRetry(), "HttpSinkRetry"),
+		test.GomegaSubTest(SubTestHttpSinkOpenSearchItemFailures(), "HttpSinkOpenSearchItemFailures"),
+		test.GomegaSubTest(SubTestSinkOverflow(), "SinkOverflow"),
+		test.GomegaSubTest(SubTestMQSink(), "MQSink"),
+		test.GomegaSubTest(SubTestMQSinkPartialFailure(), "MQSinkPartialFailure"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestHttpSinkJson() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		srv := NewTestHttpServer(http.StatusOK)
+		defer srv.Close()
+		sink := NewTestHttpSink(g, srv.URL, SinkProperties{Protocol: ProtocolJson})
+		defer func() { _ = sink.Close() }()
+
+		_, _ = sink.Write([]byte(`{"msg":"json entry"}` + "\n"))
+		_, _ = sink.Write([]byte("text entry\n"))
+		g.Expect(sink.Sync()).To(Succeed(), "Sync should not fail")
+
+		reqs := srv.Requests()
+		g.Expect(reqs).To(HaveLen(1), "entries should be shipped in one batch")
+		g.Expect(reqs[0].Header.Get("Content-Encoding")).To(Equal("gzip"), "request should be compressed")
+		var docs []map[string]interface{}
+		g.Expect(json.Unmarshal(reqs[0].Body, &docs)).To(Succeed(), "body should be JSON array")
+		g.Expect(docs).To(HaveLen(2), "body should have all entries")
+		g.Expect(docs[0]).To(HaveKeyWithValue(LogKeyMessage, "json entry"), "JSON entry should be kept as-is")
+		g.Expect(docs[1]).To(HaveKeyWithValue(LogKeyMessage, "text entry"), "text entry should be wrapped")
+		g.Expect(docs[1]).To(HaveKey(LogKeyTimestamp), "text entry should have timestamp")
+	}
+}
+
+func SubTestHttpSinkLoki() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		srv := NewTestHttpServer(http.StatusNoContent)
+		defer srv.Close()
+		sink := NewTestHttpSink(g, srv.URL, SinkProperties{
+			Protocol:    ProtocolLoki,
+			Compression: CompressionNone,
+			Labels:      map[string]string{"app": "test"},
+			Headers:     map[string]string{"X-Scope-OrgID": "tenant"},
+		})
+		defer func() { _ = sink.Close() }()
+
+		_, _ = sink.Write([]byte("text entry\n"))
+		g.Expect(sink.Sync()).To(Succeed(), "Sync should not fail")
+
+		reqs := srv.Requests()
+		g.Expect(reqs).To(HaveLen(1), "entries should be shipped")
+		g.Expect(reqs[0].Header.Get("Content-Encoding")).To(BeEmpty(), "request should not be compressed")
+		g.Expect(reqs[0].Header.Get("X-Scope-OrgID")).To(Equal("tenant"), "request should have custom headers")
+		var body struct {
+			Streams []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string       `json:"values"`
+			} `json:"streams"`
+		}
+		g.Expect(json.Unmarshal(reqs[0].Body, &body)).To(Succeed(), "body should be JSON")
+		g.Expect(body.Streams).To(HaveLen(1), "body should have one stream")
+		g.Expect(body.Streams[0].Stream).To(HaveKeyWithValue("app", "test"), "stream should have labels")
+		g.Expect(body.Streams[0].Values).To(HaveLen(1), "stream should have all entries")
+		g.Expect(body.Streams[0].Values[0][1]).To(Equal("text entry"), "entry should be correct")
+	}
+}
+
+func SubTestHttpSinkOpenSearch() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		srv := NewTestHttpServer(http.StatusOK)
+		defer srv.Close()
+		sink := NewTestHttpSink(g, srv.URL, SinkProperties{Protocol: ProtocolOpenSearch, Index: "logs"})
+		defer func() { _ = sink.Close() }()
+
+		_, _ = sink.Write([]byte(`{"msg":"entry 1"}` + "\n"))
+		_, _ = sink.Write([]byte(`{"msg":"entry 2"}` + "\n"))
+		g.Expect(sink.Sync()).To(Succeed(), "Sync should not fail")
+
+		reqs := srv.Requests()
+		g.Expect(reqs).To(HaveLen(1), "entries should be shipped")
+		g.Expect(reqs[0].Header.Get("Content-Type")).To(Equal("application/x-ndjson"), "request should have correct content type")
+		lines := strings.Split(strings.TrimSpace(string(reqs[0].Body)), "\n")
+		g.Expect(lines).To(HaveLen(4), "body should have action and document of each entry")
+		g.Expect(lines[0]).To(MatchJSON(`{"index":{"_index":"logs"}}`), "action should be correct")
+		g.Expect(lines[1]).To(MatchJSON(`{"msg":"entry 1"}`), "document should be correct")
+		g.Expect(lines[3]).To(MatchJSON(`{"msg":"entry 2"}`), "document should be correct")
+	}
+}
+
+func SubTestHttpSinkRetry() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		observer := NewTestSinkObserver()
+		SetSinkObserver(observer)
+		defer SetSinkObserver(nil)
+		srv := NewTestHttpServer(http.StatusServiceUnavailable, http.StatusOK)
+		defer srv.Close()
+		sink := NewTestHttpSink(g, srv.URL, SinkProperties{RetryInterval: utils.Duration(time.Millisecond)})
+		defer func() { _ = sink.Close() }()
+
+		_, _ = sink.Write([]byte(`{"msg":"entry"}` + "\n"))
+		g.Expect(sink.Sync()).To(Succeed(), "Sync should not fail")
+		g.Expect(srv.Requests()).To(HaveLen(2), "batch should be retried")
+		g.Expect(observer.Get("retried")).To(Equal(1), "retry should be observed")
+		g.Expect(observer.Get("sent")).To(Equal(1), "sent entries should be observed")
+
+		// rejected batch is not retried
+		srv.SetStatus(http.StatusBadRequest)
+		_, _ = sink.Write([]byte(`{"msg":"entry"}` + "\n"))
+		g.Expect(sink.Sync()).To(Succeed(), "Sync should not fail")
+		g.Expect(srv.Requests()).To(HaveLen(3), "rejected batch should not be retried")
+		g.Expect(observer.Get(DropReasonFailure)).To(Equal(1), "dropped entries should be observed")
+	}
+}
+
+func SubTestHttpSinkOpenSearchItemFailures() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		observer := NewTestSinkObserver()
+		SetSinkObserver(observer)
+		defer SetSinkObserver(nil)
+		srv := NewTestHttpServer(http.StatusOK)
+		srv.SetBodies(
+			`{"errors":true,"items":[{"index":{"status":201}},{"index":{"status":429,"error":{"type":"es_rejected_execution_exception"}}},{"index":{"status":400,"error":{"type":"mapper_parsing_exception"}}}]}`,
+			`{"errors":false,"items":[{"index":{"status":201}}]}`,
+		)
+		defer srv.Close()
+		sink := NewTestHttpSink(g, srv.URL, SinkProperties{Protocol: ProtocolOpenSearch, Index: "logs", RetryInterval: utils.Duration(time.Millisecond)})
+		defer func() { _ = sink.Close() }()
+
+		for i := 1; i <= 3; i++ {
+			_, _ = sink.Write([]byte(fmt.Sprintf(`{"msg":"entry %d"}`, i) + "\n"))
+		}
+		g.Expect(sink.Sync()).To(Succeed(), "Sync should not fail")
+
+		reqs := srv.Requests()
+		g.Expect(reqs).To(HaveLen(2), "failed items should be retried")
+		lines := strings.Split(strings.TrimSpace(string(reqs[1].Body)), "\n")
+		g.Expect(lines).To(HaveLen(2), "only failed items with retryable status should be retried")
+		g.Expect(lines[1]).To(MatchJSON(`{"msg":"entry 2"}`), "retried document should be correct")
+		g.Expect(observer.Get("sent")).To(Equal(2), "sent entries should be observed")
+		g.Expect(observer.Get("retried")).To(Equal(1), "retry should be observed")
+		g.Expect(observer.Get(DropReasonFailure)).To(Equal(1), "rejected entries should be observed")
+	}
+}
+
+func SubTestSinkOverflow() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		observer := NewTestSinkObserver()
+		SetSinkObserver(observer)
+		defer SetSinkObserver(nil)
+		writer := &TestBlockingWriter{release: make(chan struct{})}
+		sink := newAsyncSink("test", SinkProperties{BufferSize: 2, BatchSize: 1}, writer)
+		defer func() { _ = sink.Close() }()
+
+		// first entry is taken by the blocked writer, next two fill the buffer, the rest are dropped
+		start := time.Now()
+		for i := 0; i < 10; i++ {
+			_, _ = sink.Write([]byte("entry\n"))
+			if i == 0 {
+				g.Eventually(writer.Count).Should(Equal(1), "writer should receive first entry")
+			}
+		}
+		g.Expect(time.Since(start)).To(BeNumerically("<", time.Second), "logging should not be blocked")
+		g.Expect(observer.Get(DropReasonOverflow)).To(Equal(7), "overflow should be observed")
+
+		close(writer.release)
+		g.Expect(sink.Sync()).To(Succeed(), "Sync should not fail")
+		g.Expect(writer.Count()).To(Equal(3), "buffered entries should be shipped")
+	}
+}
+
+func SubTestMQSinkPartialFailure() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		publisher := &TestMQPublisher{failAfter: 2}
+		SetMQPublisher(publisher)
+		defer SetMQPublisher(nil)
+		sink := newAsyncSink("test-mq", SinkProperties{BatchSize: 4, RetryInterval: utils.Duration(time.Millisecond)}, mqSinkWriter{topic: "LOGS"})
+		defer func() { _ = sink.Close() }()
+
+		for i := 1; i <= 4; i++ {
+			_, _ = sink.Write([]byte(fmt.Sprintf("entry %d\n", i)))
+		}
+		g.Expect(sink.Sync()).To(Succeed(), "Sync should not fail")
+		g.Eventually(publisher.Entries).Should(HaveKeyWithValue("LOGS", HaveLen(4)), "all entries should be published")
+		g.Expect(publisher.Entries()["LOGS"]).To(Equal([][]byte{
+			[]byte("entry 1\n"), []byte("entry 2\n"), []byte("entry 3\n"), []byte("entry 4\n"),
+		}), "published entries should not be duplicated")
+	}
+}
+
+func SubTestMQSink() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		sink := newAsyncSink("test-mq", SinkProperties{FlushInterval: utils.Duration(10 * time.Millisecond)}, mqSinkWriter{topic: "LOGS"})
+		defer func() { _ = sink.Close() }()
+
+		// publisher is not available yet, entries are kept
+		_, _ = sink.Write([]byte("entry\n"))
+		time.Sleep(50 * time.Millisecond)
+
+		publisher := &TestMQPublisher{}
+		SetMQPublisher(publisher)
+		defer SetMQPublisher(nil)
+		g.Expect(sink.Sync()).To(Succeed(), "Sync should not fail")
+		g.Eventually(publisher.Entries).Should(HaveKeyWithValue("LOGS", HaveLen(1)), "entries should be published once publisher is available")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+type TestHttpRequest struct {
+	Header http.Header
+	Body   []byte
+}
+
+type TestHttpServer struct {
+	*httptest.Server
+	mtx      sync.Mutex
+	statuses []int
+	bodies   []string
+	requests []TestHttpRequest
+}
+
+// NewTestHttpServer responds with given statuses in order, the last one is repeated
+func NewTestHttpServer(statuses ...int) *TestHttpServer {
+	srv := &TestHttpServer{statuses: statuses}
+	srv.Server = httptest.NewServer(http.HandlerFunc(srv.handle))
+	return srv
+}
+
+func (s *TestHttpServer) handle(rw http.ResponseWriter, r *http.Request) {
+	var reader io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gr, e := gzip.NewReader(r.Body)
+		if e != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reader = gr
+	}
+	body, _ := io.ReadAll(reader)
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.requests = append(s.requests, TestHttpRequest{Header: r.Header, Body: body})
+	status := s.statuses[0]
+	if len(s.statuses) > 1 {
+		s.statuses = s.statuses[1:]
+	}
+	rw.WriteHeader(status)
+	if len(s.bodies) != 0 {
+		_, _ = rw.Write([]byte(s.bodies[0]))
+		s.bodies = s.bodies[1:]
+	}
+}
+
+// SetBodies sets response bodies in order, responses after that have empty body
+func (s *TestHttpServer) SetBodies(bodies ...string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.bodies = bodies
+}
+
+func (s *TestHttpServer) SetStatus(status int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.statuses = []int{status}
+}
+
+func (s *TestHttpServer) Requests() []TestHttpRequest {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([]TestHttpRequest{}, s.requests...)
+}
+
+func NewTestHttpSink(g *gomega.WithT, url string, props SinkProperties) *asyncSink {
+	writer, e := newHttpSinkWriter(url, props)
+	g.Expect(e).To(Succeed(), "creating http writer should not fail")
+	return newAsyncSink("test-http", props, writer)
+}
+
+type TestSinkObserver struct {
+	mtx    sync.Mutex
+	counts map[string]int
+}
+
+func NewTestSinkObserver() *TestSinkObserver {
+	return &TestSinkObserver{counts: map[string]int{}}
+}
+
+func (o *TestSinkObserver) Sent(_ string, n int) {
+	o.add("sent", n)
+}
+
+func (o *TestSinkObserver) Dropped(_ string, n int, reason string) {
+	o.add(reason, n)
+}
+
+func (o *TestSinkObserver) Retried(_ string) {
+	o.add("retried", 1)
+}
+
+func (o *TestSinkObserver) add(key string, n int) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.counts[key] += n
+}
+
+func (o *TestSinkObserver) Get(key string) int {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	return o.counts[key]
+}
+
+type TestBlockingWriter struct {
+	mtx     sync.Mutex
+	count   int
+	release chan struct{}
+}
+
+func (w *TestBlockingWriter) WriteBatch(_ context.Context, entries []SinkEntry) error {
+	w.mtx.Lock()
+	w.count += len(entries)
+	w.mtx.Unlock()
+	<-w.release
+	return nil
+}
+
+func (w *TestBlockingWriter) Count() int {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.count
+}
+
+// TestMQPublisher records published entries. When failAfter is set, the first Publish fails after publishing failAfter entries
+type TestMQPublisher struct {
+	mtx       sync.Mutex
+	entries   map[string][][]byte
+	failAfter int
+}
+
+func (p *TestMQPublisher) Publish(_ context.Context, topic string, entries []SinkEntry) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.entries == nil {
+		p.entries = map[string][][]byte{}
+	}
+	for i, entry := range entries {
+		if p.failAfter > 0 && i == p.failAfter {
+			p.failAfter = 0
+			return PartialWriteError{Remaining: entries[i:], Err: errors.New("broker unavailable")}
+		}
+		p.entries[topic] = append(p.entries[topic], bytes.Clone(entry.Data))
+	}
+	return nil
+}
+
+func (p *TestMQPublisher) Entries() map[string][][]byte {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	ret := map[string][][]byte{}
+	for k, v := range p.entries {
+		ret[k] = v
+	}
+	return ret
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"embed"
+	"github.com/cisco-open/go-lanai/pkg/actuator/prometheus"
+	appconfig "github.com/cisco-open/go-lanai/pkg/appconfig/init"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/metrics"
+	"github.com/cisco-open/go-lanai/pkg/scheduler"
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/fx"
+)
+
+//go:embed defaults-metrics.yml
+var defaultConfigFS embed.FS
+
+var logger = log.New("Metrics")
+
+var Module = &bootstrap.Module{
+	Name:       "metrics",
+	Precedence: bootstrap.MetricsPrecedence,
+	Options: []fx.Option{
+		appconfig.FxEmbeddedDefaults(defaultConfigFS),
+		fx.Provide(metrics.BindMetricsProperties),
+		fx.Provide(provideRegistry),
+		fx.Invoke(initialize),
+	},
+}
+
+// Use Allow service to include this module and the "prometheus" actuator endpoint in main()
+func Use() {
+	bootstrap.Register(Module)
+	prometheus.Register()
+}
+
+/**************************
+	Provide dependencies
+***************************/
+
+type registryOut struct {
+	fx.Out
+	Registry           metrics.Registry
+	PrometheusRegistry *metrics.PrometheusRegistry
+	Gatherer           promclient.Gatherer
+}
+
+func provideRegistry(props metrics.MetricsProperties) (ret registryOut) {
+	if !props.Enabled {
+		return
+	}
+	reg := metrics.NewPrometheusRegistry(func(opt *metrics.PrometheusRegistryOption) {
+		opt.Prefix = props.Prefix
+		opt.Tags = props.Tags
+		opt.Runtime = props.Runtime.Enabled
+	})
+	return registryOut{
+		Registry:           reg,
+		PrometheusRegistry: reg,
+		Gatherer:           reg.Gatherer(),
+	}
+}
+
+/**************************
+	Setup
+***************************/
+
+type initDI struct {
+	fx.In
+	Registry metrics.Registry `optional:"true"`
+}
+
+func initialize(di initDI) {
+	if di.Registry == nil {
+		logger.Infof("metrics is disabled")
+		return
+	}
+	scheduler.EnableMetrics(di.Registry)
+	log.SetSinkObserver(metrics.NewLogSinkObserver(di.Registry))
+}
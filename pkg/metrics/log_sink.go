@@ -0,0 +1,66 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/log"
+)
+
+const (
+	MetricLogSinkSent    = "log.sink.sent"
+	MetricLogSinkDropped = "log.sink.dropped"
+	MetricLogSinkRetries = "log.sink.retries"
+	TagLogSink           = "sink"
+	TagLogDropReason     = "reason"
+)
+
+// logSinkObserver implements log.SinkObserver
+type logSinkObserver struct {
+	sent    Counter
+	dropped Counter
+	retries Counter
+}
+
+// NewLogSinkObserver creates log.SinkObserver that records self-metrics of asynchronous loggers
+func NewLogSinkObserver(registry Registry) log.SinkObserver {
+	return &logSinkObserver{
+		sent: registry.Counter(MetricLogSinkSent,
+			WithDescription("Log entries shipped by asynchronous loggers"),
+			WithTags(TagLogSink),
+		),
+		dropped: registry.Counter(MetricLogSinkDropped,
+			WithDescription("Log entries dropped by asynchronous loggers"),
+			WithTags(TagLogSink, TagLogDropReason),
+		),
+		retries: registry.Counter(MetricLogSinkRetries,
+			WithDescription("Retried batches of asynchronous loggers"),
+			WithTags(TagLogSink),
+		),
+	}
+}
+
+func (o *logSinkObserver) Sent(sink string, n int) {
+	o.sent.Add(float64(n), Tags{TagLogSink: sink})
+}
+
+func (o *logSinkObserver) Dropped(sink string, n int, reason string) {
+	o.dropped.Add(float64(n), Tags{TagLogSink: sink, TagLogDropReason: reason})
+}
+
+func (o *logSinkObserver) Retried(sink string) {
+	o.retries.Inc(Tags{TagLogSink: sink})
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics provides a vendor-neutral API to record counters, gauges, histograms and timers with tags.
+// Metric names are dot separated, e.g. "http.server.requests", and converted to the backend's naming convention.
+// Instrumentation of other packages is installed automatically when a Registry is available in dependency injection.
+package metrics
+
+import (
+	"time"
+)
+
+const (
+	TagOutcome = "outcome"
+
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Tags are key-value pairs attached to a measurement. Only keys declared via WithTags are recorded, missing keys are recorded as empty
+type Tags map[string]string
+
+// Registry creates or retrieves metrics by name. Getting a metric with the same name returns the existing one,
+// as long as the type matches. Options are only applied when the metric is created.
+type Registry interface {
+	Counter(name string, opts ...MetricOptions) Counter
+	Gauge(name string, opts ...MetricOptions) Gauge
+	Histogram(name string, opts ...MetricOptions) Histogram
+	Timer(name string, opts ...MetricOptions) Timer
+}
+
+// Counter is a monotonically increasing value
+type Counter interface {
+	Inc(tags Tags)
+	Add(v float64, tags Tags)
+}
+
+// Gauge is a value that can go up and down
+type Gauge interface {
+	Set(v float64, tags Tags)
+	Add(v float64, tags Tags)
+}
+
+// Histogram samples observations into buckets
+type Histogram interface {
+	Observe(v float64, tags Tags)
+}
+
+// Timer is a Histogram of durations
+type Timer interface {
+	Record(d time.Duration, tags Tags)
+}
+
+type MetricOptions func(opt *MetricOption)
+
+type MetricOption struct {
+	Description string
+	TagKeys     []string
+	// Buckets of Histogram and Timer. Timer buckets are in seconds
+	Buckets []float64
+}
+
+// WithDescription set metric's description
+func WithDescription(desc string) MetricOptions {
+	return func(opt *MetricOption) {
+		opt.Description = desc
+	}
+}
+
+// WithTags declares tag keys of the metric
+func WithTags(keys ...string) MetricOptions {
+	return func(opt *MetricOption) {
+		opt.TagKeys = append(opt.TagKeys, keys...)
+	}
+}
+
+// WithBuckets set buckets of Histogram or Timer
+func WithBuckets(buckets ...float64) MetricOptions {
+	return func(opt *MetricOption) {
+		opt.Buckets = buckets
+	}
+}
+
+// Outcome returns OutcomeError if given err is not nil, otherwise OutcomeSuccess
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeSuccess
+}
@@ -0,0 +1,240 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"strings"
+	"sync"
+	"time"
+)
+
+type PrometheusRegistryOptions func(opt *PrometheusRegistryOption)
+
+type PrometheusRegistryOption struct {
+	// Prefix is prepended to all metric names
+	Prefix string
+	// Tags are common tags added to all metrics
+	Tags map[string]string
+	// Runtime enables Go runtime and process metrics
+	Runtime bool
+}
+
+// PrometheusRegistry implements Registry backed by a dedicated prometheus.Registry
+type PrometheusRegistry struct {
+	registry   *prometheus.Registry
+	registerer prometheus.Registerer
+	mtx        sync.Mutex
+	metrics    map[string]interface{}
+}
+
+func NewPrometheusRegistry(opts ...PrometheusRegistryOptions) *PrometheusRegistry {
+	opt := PrometheusRegistryOption{}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	reg := prometheus.NewRegistry()
+	var registerer prometheus.Registerer = reg
+	if len(opt.Tags) != 0 {
+		labels := prometheus.Labels{}
+		for k, v := range opt.Tags {
+			labels[sanitize(k)] = v
+		}
+		registerer = prometheus.WrapRegistererWith(labels, registerer)
+	}
+	if opt.Prefix != "" {
+		registerer = prometheus.WrapRegistererWithPrefix(sanitize(opt.Prefix)+"_", registerer)
+	}
+	if opt.Runtime {
+		registerer.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+	}
+	return &PrometheusRegistry{
+		registry:   reg,
+		registerer: registerer,
+		metrics:    map[string]interface{}{},
+	}
+}
+
+// Gatherer returns the prometheus.Gatherer to be exposed
+func (r *PrometheusRegistry) Gatherer() prometheus.Gatherer {
+	return r.registry
+}
+
+// Registerer returns the prometheus.Registerer for custom prometheus.Collector
+func (r *PrometheusRegistry) Registerer() prometheus.Registerer {
+	return r.registerer
+}
+
+func (r *PrometheusRegistry) Counter(name string, opts ...MetricOptions) Counter {
+	v := r.getOrCreate(name, opts, func(opt *MetricOption, labels []string) interface{} {
+		vec := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: sanitize(name) + "_total",
+			Help: opt.Description,
+		}, labels)
+		r.registerer.MustRegister(vec)
+		return &promCounter{vec: vec, keys: opt.TagKeys}
+	})
+	if ret, ok := v.(Counter); ok {
+		return ret
+	}
+	panic(fmt.Errorf("metric [%s] is already registered with a different type", name))
+}
+
+func (r *PrometheusRegistry) Gauge(name string, opts ...MetricOptions) Gauge {
+	v := r.getOrCreate(name, opts, func(opt *MetricOption, labels []string) interface{} {
+		vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: sanitize(name),
+			Help: opt.Description,
+		}, labels)
+		r.registerer.MustRegister(vec)
+		return &promGauge{vec: vec, keys: opt.TagKeys}
+	})
+	if ret, ok := v.(Gauge); ok {
+		return ret
+	}
+	panic(fmt.Errorf("metric [%s] is already registered with a different type", name))
+}
+
+func (r *PrometheusRegistry) Histogram(name string, opts ...MetricOptions) Histogram {
+	v := r.getOrCreate(name, opts, func(opt *MetricOption, labels []string) interface{} {
+		vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    sanitize(name),
+			Help:    opt.Description,
+			Buckets: opt.Buckets,
+		}, labels)
+		r.registerer.MustRegister(vec)
+		return &promHistogram{vec: vec, keys: opt.TagKeys}
+	})
+	if ret, ok := v.(*promHistogram); ok {
+		return ret
+	}
+	panic(fmt.Errorf("metric [%s] is already registered with a different type", name))
+}
+
+func (r *PrometheusRegistry) Timer(name string, opts ...MetricOptions) Timer {
+	v := r.getOrCreate(name, opts, func(opt *MetricOption, labels []string) interface{} {
+		vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    sanitize(name) + "_seconds",
+			Help:    opt.Description,
+			Buckets: opt.Buckets,
+		}, labels)
+		r.registerer.MustRegister(vec)
+		return &promTimer{promHistogram{vec: vec, keys: opt.TagKeys}}
+	})
+	if ret, ok := v.(*promTimer); ok {
+		return ret
+	}
+	panic(fmt.Errorf("metric [%s] is already registered with a different type", name))
+}
+
+func (r *PrometheusRegistry) getOrCreate(name string, opts []MetricOptions, factory func(opt *MetricOption, labels []string) interface{}) interface{} {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if v, ok := r.metrics[name]; ok {
+		return v
+	}
+	opt := MetricOption{}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	if opt.Description == "" {
+		opt.Description = name
+	}
+	labels := make([]string, len(opt.TagKeys))
+	for i, k := range opt.TagKeys {
+		labels[i] = sanitize(k)
+	}
+	v := factory(&opt, labels)
+	r.metrics[name] = v
+	return v
+}
+
+/**********************
+	Metrics
+ **********************/
+
+type promCounter struct {
+	vec  *prometheus.CounterVec
+	keys []string
+}
+
+func (c *promCounter) Inc(tags Tags) {
+	c.vec.WithLabelValues(labelValues(c.keys, tags)...).Inc()
+}
+
+func (c *promCounter) Add(v float64, tags Tags) {
+	c.vec.WithLabelValues(labelValues(c.keys, tags)...).Add(v)
+}
+
+type promGauge struct {
+	vec  *prometheus.GaugeVec
+	keys []string
+}
+
+func (g *promGauge) Set(v float64, tags Tags) {
+	g.vec.WithLabelValues(labelValues(g.keys, tags)...).Set(v)
+}
+
+func (g *promGauge) Add(v float64, tags Tags) {
+	g.vec.WithLabelValues(labelValues(g.keys, tags)...).Add(v)
+}
+
+type promHistogram struct {
+	vec  *prometheus.HistogramVec
+	keys []string
+}
+
+func (h *promHistogram) Observe(v float64, tags Tags) {
+	h.vec.WithLabelValues(labelValues(h.keys, tags)...).Observe(v)
+}
+
+type promTimer struct {
+	promHistogram
+}
+
+func (t *promTimer) Record(d time.Duration, tags Tags) {
+	t.Observe(d.Seconds(), tags)
+}
+
+/**********************
+	Helpers
+ **********************/
+
+func labelValues(keys []string, tags Tags) []string {
+	values := make([]string, len(keys))
+	for i, k := range keys {
+		values[i] = tags[k]
+	}
+	return values
+}
+
+// sanitize converts dot-separated names to prometheus names
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == ':':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics_test
+
+import (
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/metrics"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	"testing"
+	"time"
+)
+
+func TestPrometheusRegistry(t *testing.T) {
+	g := NewWithT(t)
+	reg := metrics.NewPrometheusRegistry(func(opt *metrics.PrometheusRegistryOption) {
+		opt.Prefix = "test"
+		opt.Tags = map[string]string{"application": "testapp"}
+	})
+
+	counter := reg.Counter("my.counter", metrics.WithTags("key"))
+	counter.Inc(metrics.Tags{"key": "value"})
+	counter.Add(2, metrics.Tags{"key": "value"})
+	g.Expect(reg.Counter("my.counter")).To(BeIdenticalTo(counter), "same counter should be returned")
+	reg.Gauge("my.gauge").Set(5, nil)
+	reg.Histogram("my.histogram", metrics.WithBuckets(1, 10)).Observe(3, nil)
+	reg.Timer("my.timer", metrics.WithTags(metrics.TagOutcome)).
+		Record(time.Second, metrics.Tags{metrics.TagOutcome: metrics.Outcome(nil)})
+
+	families, e := reg.Gatherer().Gather()
+	g.Expect(e).To(Succeed(), "gather should not fail")
+	byName := map[string]*dto.MetricFamily{}
+	for _, f := range families {
+		byName[f.GetName()] = f
+	}
+
+	f := byName["test_my_counter_total"]
+	g.Expect(f).ToNot(BeNil(), "counter should be registered")
+	g.Expect(f.GetType()).To(Equal(dto.MetricType_COUNTER))
+	g.Expect(f.GetMetric()[0].GetCounter().GetValue()).To(BeNumerically("==", 3))
+	assertLabels(g, f.GetMetric()[0], map[string]string{"application": "testapp", "key": "value"})
+
+	f = byName["test_my_gauge"]
+	g.Expect(f).ToNot(BeNil(), "gauge should be registered")
+	g.Expect(f.GetMetric()[0].GetGauge().GetValue()).To(BeNumerically("==", 5))
+
+	f = byName["test_my_histogram"]
+	g.Expect(f).ToNot(BeNil(), "histogram should be registered")
+	g.Expect(f.GetMetric()[0].GetHistogram().GetBucket()).To(HaveLen(2))
+
+	f = byName["test_my_timer_seconds"]
+	g.Expect(f).ToNot(BeNil(), "timer should be registered")
+	g.Expect(f.GetMetric()[0].GetHistogram().GetSampleSum()).To(BeNumerically("==", 1))
+	assertLabels(g, f.GetMetric()[0], map[string]string{"application": "testapp", metrics.TagOutcome: metrics.OutcomeSuccess})
+}
+
+func TestPrometheusRegistryTypeMismatch(t *testing.T) {
+	g := NewWithT(t)
+	reg := metrics.NewPrometheusRegistry()
+	reg.Counter("my.metric")
+	g.Expect(func() { reg.Gauge("my.metric") }).To(Panic(), "registering different type with same name should panic")
+	g.Expect(func() { reg.Timer("my.metric") }).To(Panic(), "registering different type with same name should panic")
+}
+
+func TestOutcome(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(metrics.Outcome(nil)).To(Equal(metrics.OutcomeSuccess))
+	g.Expect(metrics.Outcome(errors.New("oops"))).To(Equal(metrics.OutcomeError))
+}
+
+func assertLabels(g *WithT, m *dto.Metric, expected map[string]string) {
+	actual := map[string]string{}
+	for _, l := range m.GetLabel() {
+		actual[l.GetName()] = l.GetValue()
+	}
+	g.Expect(actual).To(Equal(expected), "labels should be correct")
+}
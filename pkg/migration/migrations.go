@@ -18,6 +18,8 @@ package migration
 
 import (
 	"context"
+	"embed"
+	"fmt"
 	"github.com/cisco-open/go-lanai/pkg/utils"
 	"gorm.io/gorm"
 	"io/fs"
@@ -55,6 +57,17 @@ func WithVersion(version string) *Migration {
 	}
 }
 
+// WithSQLFile returns a TagPreUpgrade migration step that executes the SQL file in the given embed.FS.
+// It's convenient for packages that ship their table definitions, e.g.
+//
+//	r.AddMigrations(migration.WithSQLFile("4.1.0.1", somepkg.MigrationFS, "migrations/create_tables.sql", db))
+func WithSQLFile(version string, fs embed.FS, filePath string, db *gorm.DB) *Migration {
+	return WithVersion(version).
+		WithTag(TagPreUpgrade).
+		WithFile(fs, filePath, db).
+		WithDesc(fmt.Sprintf("execute %s", filePath))
+}
+
 func (m *Migration) Dot(i int) *Migration {
 	m.Version = append(m.Version, i)
 	return m
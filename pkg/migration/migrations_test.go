@@ -0,0 +1,59 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package migration_test
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/migration"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+/*************************
+	Tests
+ *************************/
+
+func TestMigrationStep(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestWithSQLFile(), "WithSQLFile"),
+		test.GomegaSubTest(SubTestWithMissingSQLFile(), "WithMissingSQLFile"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestWithSQLFile() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		step := migration.WithSQLFile("4.1.0.1", TestStepsFS, "testdata/test.sql", nil)
+		g.Expect(step.Version.String()).To(Equal("4.1.0.1"), "step should have correct version")
+		g.Expect(step.Tags.Has(migration.TagPreUpgrade)).To(BeTrue(), "step should be tagged as pre-upgrade")
+		g.Expect(step.Description).To(ContainSubstring("testdata/test.sql"), "step should be described by file")
+		g.Expect(step.Func).ToNot(BeNil(), "step should have migration func")
+	}
+}
+
+func SubTestWithMissingSQLFile() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		g.Expect(func() {
+			migration.WithSQLFile("4.1.0.1", TestStepsFS, "testdata/missing.sql", nil)
+		}).To(Panic(), "missing file should fail early")
+	}
+}
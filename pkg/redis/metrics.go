@@ -0,0 +1,102 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/metrics"
+	goredis "github.com/go-redis/redis/v8"
+	"strconv"
+	"time"
+)
+
+const (
+	MetricCommands = "redis.commands"
+	TagCommand     = "command"
+	TagDB          = "db"
+)
+
+type kCtxMetricsStart struct{}
+
+// redisMetricsHook implements redis.Hook and redis.OptionsAwareHook
+type redisMetricsHook struct {
+	timer metrics.Timer
+	db    string
+}
+
+// NewRedisMetricsHook returns a redis.Hook that records duration of each command
+func NewRedisMetricsHook(registry metrics.Registry) *redisMetricsHook {
+	return &redisMetricsHook{
+		timer: registry.Timer(MetricCommands,
+			metrics.WithDescription("Redis commands"),
+			metrics.WithTags(TagCommand, TagDB, metrics.TagOutcome),
+		),
+	}
+}
+
+// WithClientOption implements redis.OptionsAwareHook
+func (h redisMetricsHook) WithClientOption(opts *goredis.UniversalOptions) goredis.Hook {
+	return &redisMetricsHook{
+		timer: h.timer,
+		db:    strconv.Itoa(opts.DB),
+	}
+}
+
+// BeforeProcess implements redis.Hook
+func (h redisMetricsHook) BeforeProcess(ctx context.Context, _ goredis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, kCtxMetricsStart{}, time.Now()), nil
+}
+
+// AfterProcess implements redis.Hook
+func (h redisMetricsHook) AfterProcess(ctx context.Context, cmd goredis.Cmder) error {
+	h.record(ctx, cmd.Name(), cmd.Err())
+	return nil
+}
+
+// BeforeProcessPipeline implements redis.Hook
+func (h redisMetricsHook) BeforeProcessPipeline(ctx context.Context, _ []goredis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, kCtxMetricsStart{}, time.Now()), nil
+}
+
+// AfterProcessPipeline implements redis.Hook
+func (h redisMetricsHook) AfterProcessPipeline(ctx context.Context, cmds []goredis.Cmder) error {
+	var err error
+	for _, v := range cmds {
+		if v.Err() != nil && !errors.Is(v.Err(), goredis.Nil) {
+			err = v.Err()
+			break
+		}
+	}
+	h.record(ctx, "pipeline", err)
+	return nil
+}
+
+func (h redisMetricsHook) record(ctx context.Context, cmd string, err error) {
+	start, ok := ctx.Value(kCtxMetricsStart{}).(time.Time)
+	if !ok {
+		return
+	}
+	if errors.Is(err, goredis.Nil) {
+		err = nil
+	}
+	h.timer.Record(time.Since(start), metrics.Tags{
+		TagCommand:         cmd,
+		TagDB:              h.db,
+		metrics.TagOutcome: metrics.Outcome(err),
+	})
+}
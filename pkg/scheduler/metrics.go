@@ -0,0 +1,67 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/metrics"
+	"time"
+)
+
+const (
+	MetricTasks = "scheduler.tasks"
+	TagTask     = "task"
+)
+
+type kCtxMetricsStart struct{}
+
+type metricsTaskHook struct {
+	timer metrics.Timer
+}
+
+func newMetricsTaskHook(registry metrics.Registry) *metricsTaskHook {
+	return &metricsTaskHook{
+		timer: registry.Timer(MetricTasks,
+			metrics.WithDescription("Scheduled task executions"),
+			metrics.WithTags(TagTask, metrics.TagOutcome),
+		),
+	}
+}
+
+func (h *metricsTaskHook) BeforeTrigger(ctx context.Context, _ string) context.Context {
+	return context.WithValue(ctx, kCtxMetricsStart{}, time.Now())
+}
+
+func (h *metricsTaskHook) AfterTrigger(ctx context.Context, id string, err error) {
+	start, ok := ctx.Value(kCtxMetricsStart{}).(time.Time)
+	if !ok {
+		return
+	}
+	h.timer.Record(time.Since(start), metrics.Tags{
+		TagTask:            taskName(id),
+		metrics.TagOutcome: metrics.Outcome(err),
+	})
+}
+
+// taskName strips the random UUID suffix from task ID to keep tag cardinality low. See newTask
+func taskName(id string) string {
+	const suffixLen = 37 // "-" + UUID
+	if len(id) <= suffixLen {
+		return "unnamed"
+	}
+	return id[:len(id)-suffixLen]
+}
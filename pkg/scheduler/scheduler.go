@@ -19,6 +19,7 @@ package scheduler
 import (
 	"fmt"
 	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/metrics"
 	"github.com/cisco-open/go-lanai/pkg/utils/order"
 	"github.com/opentracing/opentracing-go"
 	"time"
@@ -58,6 +59,13 @@ func EnableTracing(tracer opentracing.Tracer) {
 	}
 }
 
+// EnableMetrics add a default hook with provided metrics.Registry to record duration and outcome of each execution
+func EnableMetrics(registry metrics.Registry) {
+	if registry != nil {
+		AddDefaultHook(newMetricsTaskHook(registry))
+	}
+}
+
 /**************************
 	Options
  **************************/
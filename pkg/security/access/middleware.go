@@ -17,6 +17,8 @@
 package access
 
 import (
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/audit"
 	"github.com/gin-gonic/gin"
 )
 
@@ -50,6 +52,12 @@ func (ac *AccessControlMiddleware) ACHandlerFunc() gin.HandlerFunc {
 }
 
 func (ac *AccessControlMiddleware) handleError(c *gin.Context, err error) {
+	if audit.Enabled() {
+		data := audit.RequestData(c.Request)
+		data[audit.DataKeyError] = err.Error()
+		audit.Publish(c, audit.NewAuditEvent(security.AuditPrincipal(security.Get(c)), audit.TypeAuthorizationFailure, data))
+	}
+
 	// We add the error and let the error handling middleware to render it
 	_ = c.Error(err)
 	c.Abort()
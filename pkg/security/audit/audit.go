@@ -0,0 +1,162 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package audit records security related events, such as authentication success/failure, MFA, token issuance/revocation
+// and access denials, as structured AuditEvent.
+// Events are published via Publish and stored in a pluggable AuditEventRepository.
+// Publishing is a no-op until the audit module is enabled, see package "github.com/cisco-open/go-lanai/pkg/security/audit/init"
+package audit
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Event types published by go-lanai's security packages
+const (
+	TypeAuthenticationSuccess = "AUTHENTICATION_SUCCESS"
+	TypeAuthenticationFailure = "AUTHENTICATION_FAILURE"
+	TypeMFAChallenge          = "MFA_CHALLENGE"
+	TypeMFAFailure            = "MFA_FAILURE"
+	TypeTokenIssued           = "TOKEN_ISSUED"
+	TypeTokenRevoked          = "TOKEN_REVOKED"
+	TypeAuthorizationFailure  = "AUTHORIZATION_FAILURE"
+)
+
+// Common keys of AuditEvent.Data
+const (
+	DataKeyRemoteAddress  = "remoteAddress"
+	DataKeyMethod         = "method"
+	DataKeyPath           = "path"
+	DataKeyError          = "error"
+	DataKeyAuthentication = "authentication"
+	DataKeyClientId       = "clientId"
+	DataKeySessionId      = "sessionId"
+	DataKeyTokenType      = "tokenType"
+	DataKeyGrantType      = "grantType"
+)
+
+// AuditEvent is a structured security event
+type AuditEvent struct {
+	Timestamp time.Time              `json:"timestamp"`
+	Principal string                 `json:"principal"`
+	Type      string                 `json:"type"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// NewAuditEvent create an AuditEvent with current time
+func NewAuditEvent(principal, eventType string, data map[string]interface{}) *AuditEvent {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	return &AuditEvent{
+		Timestamp: time.Now().UTC(),
+		Principal: principal,
+		Type:      eventType,
+		Data:      data,
+	}
+}
+
+// RequestData extracts common data of given HTTP request, such as remote address, method and path
+func RequestData(r *http.Request) map[string]interface{} {
+	data := map[string]interface{}{}
+	if r == nil {
+		return data
+	}
+	data[DataKeyRemoteAddress] = r.RemoteAddr
+	data[DataKeyMethod] = r.Method
+	if r.URL != nil {
+		data[DataKeyPath] = r.URL.Path
+	}
+	return data
+}
+
+// Query is used to find AuditEvent. Zero value fields are ignored.
+type Query struct {
+	Principal string
+	Type      string
+	// After is exclusive
+	After time.Time
+	// Limit is the max number of events to return, most recent events first
+	Limit int
+}
+
+// Matches returns true if given event satisfies the query, Limit is not considered
+func (q Query) Matches(event *AuditEvent) bool {
+	switch {
+	case q.Principal != "" && q.Principal != event.Principal:
+		return false
+	case q.Type != "" && q.Type != event.Type:
+		return false
+	case !q.After.IsZero() && !event.Timestamp.After(q.After):
+		return false
+	default:
+		return true
+	}
+}
+
+// AuditEventRepository stores and finds AuditEvent
+type AuditEventRepository interface {
+	Add(ctx context.Context, event *AuditEvent) error
+	// Find returns events matching given Query, most recent events first
+	Find(ctx context.Context, query Query) ([]*AuditEvent, error)
+}
+
+// RepositoryFactory creates AuditEventRepository of a particular type. Type is matched against AuditProperties.Repository.
+// RepositoryFactory can be registered via fx group FxGroup.
+type RepositoryFactory interface {
+	Type() string
+	NewRepository(props AuditProperties) (AuditEventRepository, error)
+}
+
+// Publisher publishes AuditEvent
+type Publisher interface {
+	Publish(ctx context.Context, event *AuditEvent)
+}
+
+/*********************
+	Global Publisher
+ *********************/
+
+type publisherHolder struct {
+	Publisher
+}
+
+var globalPublisher atomic.Value
+
+func init() {
+	globalPublisher.Store(publisherHolder{})
+}
+
+// SetGlobalPublisher set the Publisher used by Publish. nil disable publishing.
+func SetGlobalPublisher(p Publisher) {
+	globalPublisher.Store(publisherHolder{Publisher: p})
+}
+
+// Enabled returns true if a global Publisher is set.
+// Callers can use it to avoid building events when audit is disabled.
+func Enabled() bool {
+	return globalPublisher.Load().(publisherHolder).Publisher != nil
+}
+
+// Publish publishes given event using global Publisher. This function is a no-op if no global Publisher is set.
+func Publish(ctx context.Context, event *AuditEvent) {
+	if h := globalPublisher.Load().(publisherHolder); h.Publisher != nil && event != nil {
+		h.Publish(ctx, event)
+	}
+}
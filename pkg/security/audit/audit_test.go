@@ -0,0 +1,81 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security/audit"
+	. "github.com/onsi/gomega"
+	"testing"
+	"time"
+)
+
+func TestInMemoryRepository(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	repo := audit.NewInMemoryAuditEventRepository(5)
+	start := time.Now().UTC()
+	for i := 0; i < 8; i++ {
+		event := audit.NewAuditEvent(fmt.Sprintf("user-%d", i%2), audit.TypeAuthenticationSuccess, nil)
+		event.Timestamp = start.Add(time.Duration(i) * time.Second)
+		g.Expect(repo.Add(ctx, event)).To(Succeed(), "Add should not fail")
+	}
+
+	events, e := repo.Find(ctx, audit.Query{})
+	g.Expect(e).To(Succeed(), "Find should not fail")
+	g.Expect(events).To(HaveLen(5), "only most recent events should be kept")
+	for i, event := range events {
+		g.Expect(event.Timestamp).To(Equal(start.Add(time.Duration(7-i)*time.Second)), "events should be in reverse order")
+	}
+
+	events, e = repo.Find(ctx, audit.Query{Principal: "user-1"})
+	g.Expect(e).To(Succeed(), "Find should not fail")
+	g.Expect(events).To(HaveLen(3), "events should be filtered by principal")
+
+	events, e = repo.Find(ctx, audit.Query{After: start.Add(5 * time.Second)})
+	g.Expect(e).To(Succeed(), "Find should not fail")
+	g.Expect(events).To(HaveLen(2), "events should be filtered by time")
+
+	events, e = repo.Find(ctx, audit.Query{Type: audit.TypeAuthenticationFailure})
+	g.Expect(e).To(Succeed(), "Find should not fail")
+	g.Expect(events).To(BeEmpty(), "events should be filtered by type")
+
+	events, e = repo.Find(ctx, audit.Query{Limit: 2})
+	g.Expect(e).To(Succeed(), "Find should not fail")
+	g.Expect(events).To(HaveLen(2), "events should be limited")
+}
+
+func TestGlobalPublisher(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	repo := audit.NewInMemoryAuditEventRepository(10)
+	defer audit.SetGlobalPublisher(nil)
+
+	g.Expect(audit.Enabled()).To(BeFalse(), "audit should be disabled by default")
+	audit.Publish(ctx, audit.NewAuditEvent("user", audit.TypeTokenIssued, nil))
+
+	audit.SetGlobalPublisher(audit.NewPublisher(repo, audit.TypeTokenRevoked))
+	g.Expect(audit.Enabled()).To(BeTrue(), "audit should be enabled")
+	audit.Publish(ctx, audit.NewAuditEvent("user", audit.TypeTokenIssued, nil))
+	audit.Publish(ctx, audit.NewAuditEvent("user", audit.TypeTokenRevoked, nil))
+
+	events, e := repo.Find(ctx, audit.Query{})
+	g.Expect(e).To(Succeed(), "Find should not fail")
+	g.Expect(events).To(HaveLen(1), "only events published after enabled and not excluded should be recorded")
+	g.Expect(events[0].Type).To(Equal(audit.TypeTokenIssued), "recorded event should be correct")
+}
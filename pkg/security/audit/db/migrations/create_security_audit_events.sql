@@ -0,0 +1,13 @@
+-- Table security_audit_events for security audit events
+CREATE TABLE IF NOT EXISTS security_audit_events
+(
+    id        UUID        NOT NULL,
+    timestamp timestamptz NOT NULL,
+    principal TEXT,
+    type      TEXT        NOT NULL,
+    data      JSONB,
+    CONSTRAINT "primary" PRIMARY KEY (id),
+    INDEX idx_security_audit_events_timestamp (timestamp),
+    INDEX idx_security_audit_events_principal (principal),
+    INDEX idx_security_audit_events_type (type)
+);
@@ -0,0 +1,63 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package dbaudit provides audit.AuditEventRepository backed by database, enabled with "security.audit.repository: db"
+package dbaudit
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/security/audit"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+var Module = &bootstrap.Module{
+	Name:       "security-audit-db",
+	Precedence: bootstrap.SecurityPrecedence,
+	Options: []fx.Option{
+		fx.Provide(FxProvider()),
+	},
+}
+
+func Use() {
+	bootstrap.Register(Module)
+}
+
+type factoryDI struct {
+	fx.In
+	DB *gorm.DB
+}
+
+func FxProvider() fx.Annotated {
+	return fx.Annotated{
+		Group: audit.FxGroup,
+		Target: func(di factoryDI) audit.RepositoryFactory {
+			return factory{db: di.DB}
+		},
+	}
+}
+
+type factory struct {
+	db *gorm.DB
+}
+
+func (f factory) Type() string {
+	return RepositoryType
+}
+
+func (f factory) NewRepository(_ audit.AuditProperties) (audit.AuditEventRepository, error) {
+	return NewGormAuditEventRepository(f.db), nil
+}
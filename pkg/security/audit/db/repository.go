@@ -0,0 +1,98 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dbaudit
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/data/types/pqx"
+	"github.com/cisco-open/go-lanai/pkg/security/audit"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	RepositoryType = "db"
+)
+
+// AuditEventModel is the table model used by GormAuditEventRepository.
+// The table "security_audit_events" is expected to be created by the application's migration, see Migration.
+type AuditEventModel struct {
+	ID        uuid.UUID    `gorm:"primaryKey;type:UUID;"`
+	Timestamp time.Time    `gorm:"column:timestamp;not null;index;"`
+	Principal string       `gorm:"column:principal;type:TEXT;index;"`
+	Type      string       `gorm:"column:type;type:TEXT;not null;index;"`
+	Data      pqx.JsonbMap `gorm:"column:data;type:JSONB;"`
+}
+
+func (AuditEventModel) TableName() string {
+	return "security_audit_events"
+}
+
+// GormAuditEventRepository implements audit.AuditEventRepository backed by the database configured via pkg/data
+type GormAuditEventRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAuditEventRepository(db *gorm.DB) *GormAuditEventRepository {
+	return &GormAuditEventRepository{db: db}
+}
+
+func (r *GormAuditEventRepository) Add(ctx context.Context, event *audit.AuditEvent) error {
+	model := AuditEventModel{
+		ID:        uuid.New(),
+		Timestamp: event.Timestamp,
+		Principal: event.Principal,
+		Type:      event.Type,
+		Data:      event.Data,
+	}
+	if rs := r.db.WithContext(ctx).Create(&model); rs.Error != nil {
+		return fmt.Errorf("unable to save audit event: %v", rs.Error)
+	}
+	return nil
+}
+
+func (r *GormAuditEventRepository) Find(ctx context.Context, query audit.Query) ([]*audit.AuditEvent, error) {
+	tx := r.db.WithContext(ctx).Order("timestamp DESC")
+	if query.Principal != "" {
+		tx = tx.Where("principal = ?", query.Principal)
+	}
+	if query.Type != "" {
+		tx = tx.Where("type = ?", query.Type)
+	}
+	if !query.After.IsZero() {
+		tx = tx.Where("timestamp > ?", query.After)
+	}
+	if query.Limit > 0 {
+		tx = tx.Limit(query.Limit)
+	}
+	var models []*AuditEventModel
+	if rs := tx.Find(&models); rs.Error != nil {
+		return nil, fmt.Errorf("unable to find audit events: %v", rs.Error)
+	}
+	events := make([]*audit.AuditEvent, len(models))
+	for i, m := range models {
+		events[i] = &audit.AuditEvent{
+			Timestamp: m.Timestamp,
+			Principal: m.Principal,
+			Type:      m.Type,
+			Data:      m.Data,
+		}
+	}
+	return events, nil
+}
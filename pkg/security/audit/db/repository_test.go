@@ -0,0 +1,49 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dbaudit
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm/schema"
+	"io/fs"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGormAuditEventRepository(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestMigrationMatchesModel(), "MigrationMatchesModel"),
+	)
+}
+
+func SubTestMigrationMatchesModel() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		sql, e := fs.ReadFile(MigrationFS, "migrations/create_security_audit_events.sql")
+		g.Expect(e).To(Succeed(), "migration SQL should be embedded")
+
+		s, e := schema.Parse(&AuditEventModel{}, &sync.Map{}, schema.NamingStrategy{})
+		g.Expect(e).To(Succeed(), "parsing model schema should not fail")
+		g.Expect(string(sql)).To(ContainSubstring(s.Table), "migration should create table [%s]", s.Table)
+		for _, name := range s.DBNames {
+			g.Expect(strings.Contains(string(sql), name+" ")).To(BeTrue(), "migration should have column [%s]", name)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package auditinit
+// Initialize security audit: AuditEventRepository selected by properties and the global audit.Publisher
+package auditinit
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/audit"
+	"go.uber.org/fx"
+)
+
+var Module = &bootstrap.Module{
+	Name:       "security-audit",
+	Precedence: security.MinSecurityPrecedence,
+	Options: []fx.Option{
+		fx.Provide(BindProperties, ProvideRepository),
+		fx.Provide(fx.Annotated{
+			Group:  audit.FxGroup,
+			Target: audit.NewMemoryRepositoryFactory,
+		}),
+		fx.Invoke(RegisterPublisher),
+	},
+}
+
+func Use() {
+	bootstrap.Register(Module)
+}
+
+// BindProperties create and bind audit.AuditProperties
+func BindProperties(appCfg bootstrap.ApplicationConfig) audit.AuditProperties {
+	props := audit.NewAuditProperties()
+	if e := appCfg.Bind(props, audit.PropertiesPrefix); e != nil {
+		panic(fmt.Errorf("failed to bind audit properties: %v", e))
+	}
+	return *props
+}
+
+type repoDI struct {
+	fx.In
+	Props     audit.AuditProperties
+	Factories []audit.RepositoryFactory `group:"security-audit"`
+}
+
+// ProvideRepository creates audit.AuditEventRepository using the audit.RepositoryFactory matching configured type
+func ProvideRepository(di repoDI) (audit.AuditEventRepository, error) {
+	for _, f := range di.Factories {
+		if f != nil && f.Type() == di.Props.Repository {
+			return f.NewRepository(di.Props)
+		}
+	}
+	return nil, fmt.Errorf(`unsupported audit repository type [%s]`, di.Props.Repository)
+}
+
+// RegisterPublisher installs global audit.Publisher during application lifecycle, if audit is enabled
+func RegisterPublisher(lc fx.Lifecycle, props audit.AuditProperties, repo audit.AuditEventRepository) {
+	if !props.Enabled {
+		return
+	}
+	publisher := audit.NewPublisher(repo, props.ExcludeTypes...)
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			audit.SetGlobalPublisher(publisher)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			audit.SetGlobalPublisher(nil)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package kafkaaudit provides audit.AuditEventRepository backed by Kafka, enabled with "security.audit.repository: kafka"
+package kafkaaudit
+
+import (
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/kafka"
+	"github.com/cisco-open/go-lanai/pkg/security/audit"
+	"go.uber.org/fx"
+)
+
+const (
+	PropertiesPrefix = "security.audit.kafka"
+	DefaultTopic     = "SECURITY_AUDIT_EVENTS"
+)
+
+var Module = &bootstrap.Module{
+	Name:       "security-audit-kafka",
+	Precedence: bootstrap.SecurityPrecedence,
+	Options: []fx.Option{
+		fx.Provide(BindProperties, FxProvider()),
+	},
+}
+
+func Use() {
+	bootstrap.Register(Module)
+}
+
+type KafkaAuditProperties struct {
+	Topic string `json:"topic"`
+}
+
+func BindProperties(appCfg bootstrap.ApplicationConfig) KafkaAuditProperties {
+	props := KafkaAuditProperties{
+		Topic: DefaultTopic,
+	}
+	if e := appCfg.Bind(&props, PropertiesPrefix); e != nil {
+		panic(fmt.Errorf("failed to bind kafka audit properties: %v", e))
+	}
+	return props
+}
+
+type factoryDI struct {
+	fx.In
+	Binder kafka.Binder
+	Props  KafkaAuditProperties
+}
+
+func FxProvider() fx.Annotated {
+	return fx.Annotated{
+		Group: audit.FxGroup,
+		Target: func(di factoryDI) audit.RepositoryFactory {
+			return factory{binder: di.Binder, props: di.Props}
+		},
+	}
+}
+
+type factory struct {
+	binder kafka.Binder
+	props  KafkaAuditProperties
+}
+
+func (f factory) Type() string {
+	return RepositoryType
+}
+
+func (f factory) NewRepository(props audit.AuditProperties) (audit.AuditEventRepository, error) {
+	producer, e := f.binder.Produce(f.props.Topic, kafka.RequireLocalAck())
+	if e != nil {
+		return nil, fmt.Errorf("unable to create producer of audit topic [%s]: %v", f.props.Topic, e)
+	}
+	return NewKafkaAuditEventRepository(producer, props.Memory.Capacity), nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaaudit
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/kafka"
+	"github.com/cisco-open/go-lanai/pkg/security/audit"
+)
+
+const (
+	RepositoryType = "kafka"
+)
+
+// KafkaAuditEventRepository implements audit.AuditEventRepository that sends events to a Kafka topic.
+// Kafka is not queryable, so events published by current instance are also kept in an in-memory ring buffer to serve Find.
+// Consumers of the topic are responsible for long term storage.
+type KafkaAuditEventRepository struct {
+	producer kafka.Producer
+	recent   *audit.InMemoryAuditEventRepository
+}
+
+func NewKafkaAuditEventRepository(producer kafka.Producer, capacity int) *KafkaAuditEventRepository {
+	return &KafkaAuditEventRepository{
+		producer: producer,
+		recent:   audit.NewInMemoryAuditEventRepository(capacity),
+	}
+}
+
+func (r *KafkaAuditEventRepository) Add(ctx context.Context, event *audit.AuditEvent) error {
+	_ = r.recent.Add(ctx, event)
+	if e := r.producer.Send(ctx, event, kafka.WithKey(event.Principal)); e != nil {
+		return fmt.Errorf("unable to send audit event to topic [%s]: %v", r.producer.Topic(), e)
+	}
+	return nil
+}
+
+func (r *KafkaAuditEventRepository) Find(ctx context.Context, query audit.Query) ([]*audit.AuditEvent, error) {
+	return r.recent.Find(ctx, query)
+}
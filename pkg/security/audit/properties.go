@@ -0,0 +1,51 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/utils"
+)
+
+const (
+	PropertiesPrefix      = "security.audit"
+	FxGroup               = "security-audit"
+	DefaultMemoryCapacity = 1000
+)
+
+type AuditProperties struct {
+	Enabled bool `json:"enabled"`
+	// Repository is the type of AuditEventRepository, e.g. "memory", "db" or "kafka"
+	Repository string `json:"repository"`
+	// ExcludeTypes are event types that should not be recorded
+	ExcludeTypes utils.CommaSeparatedSlice `json:"exclude-types"`
+	Memory       MemoryProperties          `json:"memory"`
+}
+
+type MemoryProperties struct {
+	// Capacity is the max number of events kept in memory
+	Capacity int `json:"capacity"`
+}
+
+func NewAuditProperties() *AuditProperties {
+	return &AuditProperties{
+		Enabled:    true,
+		Repository: RepositoryTypeMemory,
+		Memory: MemoryProperties{
+			Capacity: DefaultMemoryCapacity,
+		},
+	}
+}
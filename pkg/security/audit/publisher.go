@@ -0,0 +1,48 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+)
+
+var logger = log.New("SEC.Audit")
+
+// DefaultPublisher adds events to AuditEventRepository synchronously.
+// Repository errors are logged and not propagated, so auditing never interrupts the audited operation.
+type DefaultPublisher struct {
+	Repository   AuditEventRepository
+	ExcludeTypes utils.StringSet
+}
+
+func NewPublisher(repo AuditEventRepository, excludeTypes ...string) *DefaultPublisher {
+	return &DefaultPublisher{
+		Repository:   repo,
+		ExcludeTypes: utils.NewStringSet(excludeTypes...),
+	}
+}
+
+func (p *DefaultPublisher) Publish(ctx context.Context, event *AuditEvent) {
+	if p.ExcludeTypes.Has(event.Type) {
+		return
+	}
+	if e := p.Repository.Add(ctx, event); e != nil {
+		logger.WithContext(ctx).Warnf("unable to record audit event [%s] of principal [%s]: %v", event.Type, event.Principal, e)
+	}
+}
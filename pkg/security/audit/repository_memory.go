@@ -0,0 +1,92 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	RepositoryTypeMemory = "memory"
+)
+
+// InMemoryAuditEventRepository keeps most recent events in a fixed size ring buffer
+type InMemoryAuditEventRepository struct {
+	mtx    sync.RWMutex
+	events []*AuditEvent
+	// next is the index to write next event
+	next int
+	full bool
+}
+
+func NewInMemoryAuditEventRepository(capacity int) *InMemoryAuditEventRepository {
+	if capacity <= 0 {
+		capacity = DefaultMemoryCapacity
+	}
+	return &InMemoryAuditEventRepository{
+		events: make([]*AuditEvent, capacity),
+	}
+}
+
+func (r *InMemoryAuditEventRepository) Add(_ context.Context, event *AuditEvent) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.events[r.next] = event
+	r.next = (r.next + 1) % len(r.events)
+	if r.next == 0 {
+		r.full = true
+	}
+	return nil
+}
+
+func (r *InMemoryAuditEventRepository) Find(_ context.Context, query Query) ([]*AuditEvent, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	count := r.next
+	if r.full {
+		count = len(r.events)
+	}
+	found := make([]*AuditEvent, 0, 16)
+	// iterate from most recent
+	for i := 1; i <= count; i++ {
+		event := r.events[(r.next-i+len(r.events))%len(r.events)]
+		if !query.Matches(event) {
+			continue
+		}
+		found = append(found, event)
+		if query.Limit > 0 && len(found) >= query.Limit {
+			break
+		}
+	}
+	return found, nil
+}
+
+type memoryRepositoryFactory struct{}
+
+// NewMemoryRepositoryFactory returns RepositoryFactory of RepositoryTypeMemory
+func NewMemoryRepositoryFactory() RepositoryFactory {
+	return memoryRepositoryFactory{}
+}
+
+func (f memoryRepositoryFactory) Type() string {
+	return RepositoryTypeMemory
+}
+
+func (f memoryRepositoryFactory) NewRepository(props AuditProperties) (AuditEventRepository, error) {
+	return NewInMemoryAuditEventRepository(props.Memory.Capacity), nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security/audit"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/cisco-open/go-lanai/pkg/utils/order"
+	"net/http"
+)
+
+// auditHandler implements AuthenticationSuccessHandler and AuthenticationErrorHandler.
+// It publishes audit.AuditEvent of authentication success and failure, and is added to every WebSecurity.
+type auditHandler struct{}
+
+func (h auditHandler) Order() int {
+	return order.Highest
+}
+
+func (h auditHandler) HandleAuthenticationSuccess(c context.Context, r *http.Request, _ http.ResponseWriter, from, to Authentication) {
+	var eventType string
+	switch {
+	case !audit.Enabled():
+		return
+	case IsBeingAuthenticated(from, to):
+		eventType = audit.TypeAuthenticationSuccess
+	case to != nil && to.State() == StatePrincipalKnown:
+		// principal is known but further authentication is required, e.g. MFA is pending
+		eventType = audit.TypeMFAChallenge
+	default:
+		return
+	}
+	data := audit.RequestData(r)
+	data[audit.DataKeyAuthentication] = fmt.Sprintf("%T", to)
+	audit.Publish(c, audit.NewAuditEvent(AuditPrincipal(to), eventType, data))
+}
+
+func (h auditHandler) HandleAuthenticationError(c context.Context, r *http.Request, _ http.ResponseWriter, err error) {
+	if !audit.Enabled() {
+		return
+	}
+	eventType := audit.TypeAuthenticationFailure
+	current := Get(c)
+	if current != nil && current.State() == StatePrincipalKnown {
+		eventType = audit.TypeMFAFailure
+	}
+	principal := AuditPrincipal(current)
+	if candidate, ok := c.Value(auditCandidateCtxKey{}).(Candidate); ok && principal == "" && candidate.Principal() != nil {
+		principal = fmt.Sprint(candidate.Principal())
+	}
+	data := audit.RequestData(r)
+	data[audit.DataKeyError] = err.Error()
+	audit.Publish(c, audit.NewAuditEvent(principal, eventType, data))
+}
+
+type auditCandidateCtxKey struct{}
+
+// SetAuditCandidate records the Candidate being authenticated in given context, so that audit.AuditEvent of
+// authentication failure carries the attempted principal. It does nothing if the context is not mutable.
+// Authentication middlewares should invoke it before the Candidate is authenticated.
+func SetAuditCandidate(ctx context.Context, candidate Candidate) {
+	if mc := utils.FindMutableContext(ctx); mc != nil && candidate != nil {
+		mc.Set(auditCandidateCtxKey{}, candidate)
+	}
+}
+
+// AuditPrincipal returns principal name of given Authentication used in audit.AuditEvent.
+// Empty string is returned if the principal name cannot be determined
+func AuditPrincipal(auth Authentication) string {
+	if auth == nil || auth.State() == StateAnonymous {
+		return ""
+	}
+	username, e := GetUsername(auth)
+	if e != nil {
+		return ""
+	}
+	return username
+}
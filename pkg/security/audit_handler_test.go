@@ -0,0 +1,89 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/security/audit"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"testing"
+)
+
+type auditTestCandidate struct {
+	username string
+}
+
+func (c auditTestCandidate) Principal() interface{} {
+	return c.username
+}
+
+func (c auditTestCandidate) Credentials() interface{} {
+	return "secret"
+}
+
+func (c auditTestCandidate) Details() interface{} {
+	return nil
+}
+
+func TestAuditHandler(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestAuditFailureWithCandidate(), "TestFailureWithCandidate"),
+		test.GomegaSubTest(SubTestAuditFailureWithoutCandidate(), "TestFailureWithoutCandidate"),
+	)
+}
+
+func SubTestAuditFailureWithCandidate() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		repo := enableTestAudit(t)
+		ctx = utils.MakeMutableContext(ctx)
+		SetAuditCandidate(ctx, auditTestCandidate{username: "test-user"})
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/login", nil)
+		auditHandler{}.HandleAuthenticationError(ctx, req, nil, errors.New("bad credentials"))
+
+		events, e := repo.Find(ctx, audit.Query{})
+		g.Expect(e).To(Succeed(), "Find should not fail")
+		g.Expect(events).To(HaveLen(1), "authentication failure should be audited")
+		g.Expect(events[0].Type).To(Equal(audit.TypeAuthenticationFailure), "event should have correct type")
+		g.Expect(events[0].Principal).To(Equal("test-user"), "event should have attempted principal")
+	}
+}
+
+func SubTestAuditFailureWithoutCandidate() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		repo := enableTestAudit(t)
+		ctx = utils.MakeMutableContext(ctx)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/login", nil)
+		auditHandler{}.HandleAuthenticationError(ctx, req, nil, errors.New("bad credentials"))
+
+		events, e := repo.Find(ctx, audit.Query{})
+		g.Expect(e).To(Succeed(), "Find should not fail")
+		g.Expect(events).To(HaveLen(1), "authentication failure should be audited")
+		g.Expect(events[0].Principal).To(BeEmpty(), "event should not have principal")
+	}
+}
+
+func enableTestAudit(t *testing.T) *audit.InMemoryAuditEventRepository {
+	repo := audit.NewInMemoryAuditEventRepository(10)
+	audit.SetGlobalPublisher(audit.NewPublisher(repo))
+	t.Cleanup(func() { audit.SetGlobalPublisher(nil) })
+	return repo
+}
@@ -78,6 +78,7 @@ func (basic *BasicAuthMiddleware) HandlerFunc() gin.HandlerFunc {
 			Username: pair[0],
 			Password: pair[1],
 		}
+		security.SetAuditCandidate(ctx, &candidate)
 		// Search auth in the slice of allowed credentials
 		auth, err := basic.authenticator.Authenticate(ctx, &candidate)
 		if err != nil {
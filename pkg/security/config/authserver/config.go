@@ -27,13 +27,18 @@ import (
 	"github.com/cisco-open/go-lanai/pkg/security/idp"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/clientauth"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/grants"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/openid"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/par"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/registration"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/revoke"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/common"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/dpop"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/tokenauth"
 	"github.com/cisco-open/go-lanai/pkg/security/passwd"
+	"github.com/cisco-open/go-lanai/pkg/security/replay"
 	samlctx "github.com/cisco-open/go-lanai/pkg/security/saml"
 	"github.com/cisco-open/go-lanai/pkg/security/session"
 	"github.com/cisco-open/go-lanai/pkg/web"
@@ -43,10 +48,11 @@ import (
 )
 
 const (
-	OrderAuthorizeSecurityConfigurer  = 0
-	OrderLogoutSecurityConfigurer     = 50
-	OrderClientAuthSecurityConfigurer = 100
-	OrderTokenAuthSecurityConfigurer  = 200
+	OrderAuthorizeSecurityConfigurer    = 0
+	OrderLogoutSecurityConfigurer       = 50
+	OrderClientAuthSecurityConfigurer   = 100
+	OrderTokenAuthSecurityConfigurer    = 200
+	OrderRegistrationSecurityConfigurer = 300
 )
 
 type AuthorizationServerConfigurer func(*Configuration)
@@ -60,6 +66,7 @@ type configDI struct {
 	ServerProperties   web.ServerProperties
 	SessionProperties  security.SessionProperties
 	CryptoProperties   jwt.CryptoProperties
+	DPoPProperties     dpop.DPoPProperties
 	SessionStore       session.Store
 	TimeoutSupport     oauth2.TimeoutApplier `optional:"true"`
 	ApprovalStore      auth.ApprovalStore    `optional:"true"`
@@ -72,7 +79,7 @@ type authServerOut struct {
 }
 
 //goland:noinspection GoExportedFuncWithUnexportedType
-func ProvideAuthServerDI(di configDI) authServerOut {
+func ProvideAuthServerDI(di configDI) (authServerOut, error) {
 	config := Configuration{
 		appContext:         di.AppContext,
 		redisClientFactory: di.RedisClientFactory,
@@ -81,6 +88,7 @@ func ProvideAuthServerDI(di configDI) authServerOut {
 		serverProperties:   di.ServerProperties,
 		sessionProperties:  di.SessionProperties,
 		cryptoProperties:   di.CryptoProperties,
+		dpopProperties:     di.DPoPProperties,
 		Issuer:             newIssuer(&di.Properties.Issuer, &di.ServerProperties),
 		timeoutSupport:     di.TimeoutSupport,
 		ApprovalStore:      di.ApprovalStore,
@@ -101,20 +109,36 @@ func ProvideAuthServerDI(di configDI) authServerOut {
 				Location:  &url.URL{Path: di.Properties.Endpoints.Authorize, RawQuery: fmt.Sprintf("%s=%s", oauth2.ParameterGrantType, samlctx.GrantTypeSamlSSO)},
 				Condition: matcher.RequestWithForm(oauth2.ParameterGrantType, samlctx.GrantTypeSamlSSO),
 			},
-			SamlMetadata:    di.Properties.Endpoints.SamlMetadata,
-			TenantHierarchy: di.Properties.Endpoints.TenantHierarchy,
+			SamlMetadata:        di.Properties.Endpoints.SamlMetadata,
+			TenantHierarchy:     di.Properties.Endpoints.TenantHierarchy,
+			DeviceAuthorization: di.Properties.Endpoints.DeviceAuthorization,
+			DeviceVerification:  di.Properties.Endpoints.DeviceVerification,
+			Registration:        di.Properties.Endpoints.Registration,
+			PushedAuthorization: di.Properties.Endpoints.PushedAuthorization,
 		},
 		OpenIDSSOEnabled: true,
 	}
 	di.Configurer(&config)
+	if e := config.validate(); e != nil {
+		return authServerOut{}, e
+	}
+	if e := config.prepareReplayCaches(); e != nil {
+		return authServerOut{}, e
+	}
+	if di.Properties.Registration.Enabled {
+		if e := config.prepareClientRegistry(); e != nil {
+			return authServerOut{}, e
+		}
+	}
 	return authServerOut{
 		Config:                  &config,
 		CompatibilityCustomizer: compatibility.CompatibilityDiscoveryCustomizer{},
-	}
+	}, nil
 }
 
 type initDI struct {
 	fx.In
+	Lifecycle         fx.Lifecycle
 	Config            *Configuration
 	WebRegistrar      *web.Registrar
 	SecurityRegistrar security.Registrar
@@ -125,6 +149,9 @@ func ConfigureAuthorizationServer(di initDI) {
 	// Securities
 	di.SecurityRegistrar.Register(&ClientAuthEndpointsConfigurer{config: di.Config})
 	di.SecurityRegistrar.Register(&TokenAuthEndpointsConfigurer{config: di.Config})
+	if di.Config.properties.Registration.Enabled {
+		di.SecurityRegistrar.Register(&RegistrationEndpointConfigurer{config: di.Config})
+	}
 	for _, configuer := range di.Config.idpConfigurers {
 		di.SecurityRegistrar.Register(&AuthorizeEndpointConfigurer{config: di.Config, delegate: configuer})
 	}
@@ -136,8 +163,32 @@ func ConfigureAuthorizationServer(di initDI) {
 		di.Config.Endpoints.SamlSso.Location.Path,
 		di.Config.Endpoints.Approval,
 		di.Config.Endpoints.Logout,
+		di.Config.Endpoints.DeviceVerification,
 	)
 	registerEndpoints(di.WebRegistrar, di.Config)
+
+	// JWK rotation
+	if store, ok := di.Config.jwkStore().(*jwt.RotatingJwkStore); ok {
+		startJwkRotation(di.Lifecycle, di.Config.appContext, store)
+	}
+}
+
+func startJwkRotation(lc fx.Lifecycle, appCtx *bootstrap.ApplicationContext, store *jwt.RotatingJwkStore) {
+	var cancel context.CancelFunc
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(appCtx)
+			store.Start(ctx)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			if cancel != nil {
+				cancel()
+			}
+			return nil
+		},
+	})
 }
 
 /****************************
@@ -150,18 +201,22 @@ type ConditionalEndpoint struct {
 }
 
 type Endpoints struct {
-	Authorize       ConditionalEndpoint
-	Approval        string
-	Token           string
-	CheckToken      string
-	UserInfo        string
-	JwkSet          string
-	Logout          string
-	LoggedOut       string
-	Error           string
-	SamlSso         ConditionalEndpoint
-	SamlMetadata    string
-	TenantHierarchy string
+	Authorize           ConditionalEndpoint
+	Approval            string
+	Token               string
+	CheckToken          string
+	UserInfo            string
+	JwkSet              string
+	Logout              string
+	LoggedOut           string
+	Error               string
+	SamlSso             ConditionalEndpoint
+	SamlMetadata        string
+	TenantHierarchy     string
+	DeviceAuthorization string
+	DeviceVerification  string
+	Registration        string
+	PushedAuthorization string
 }
 
 type Configuration struct {
@@ -181,6 +236,20 @@ type Configuration struct {
 	OpenIDSSOEnabled      bool
 	SamlIdpSigningMethod  string
 	ApprovalStore         auth.ApprovalStore
+	DeviceCodeStore       auth.DeviceCodeStore
+	// DPoPReplayCache keeps used "jti" of DPoP proofs. Default to Redis
+	DPoPReplayCache replay.Cache
+	// ClientAssertionReplayCache keeps used "jti" of client assertions ("private_key_jwt") and request objects. Default to Redis
+	ClientAssertionReplayCache replay.Cache
+	// PushedAuthorizeRequestStore keeps authorize requests pushed by clients (RFC 9126). Default to Redis
+	PushedAuthorizeRequestStore auth.PushedAuthorizeRequestStore
+	// JwkStorage is used when JWK rotation is enabled ("security.jwt.rotation.enabled"). It should be shared by all replicas.
+	// It's required when rotation is enabled and JwkStore is not set. See jwkstorage package for implementations.
+	JwkStorage jwt.RotatingJwkStorage
+	// ClientRegistry is used by dynamic client registration. When registration is enabled, it also replaces ClientStore.
+	// If not set, ClientStore is used if it implements registration.ClientRegistry, otherwise startup fails.
+	// It should be shared by all replicas, see datastore.GormClientRegistry
+	ClientRegistry registration.ClientRegistry
 
 	// not directly configurable items
 	appContext                *bootstrap.ApplicationContext
@@ -190,6 +259,7 @@ type Configuration struct {
 	serverProperties          web.ServerProperties
 	sessionProperties         security.SessionProperties
 	cryptoProperties          jwt.CryptoProperties
+	dpopProperties            dpop.DPoPProperties
 	idpConfigurers            []IdpSecurityConfigurer
 	sharedContextDetailsStore security.ContextDetailsStore
 	sharedAuthRegistry        auth.AuthorizationRegistry
@@ -205,6 +275,8 @@ type Configuration struct {
 	sharedAuthHandler         auth.AuthorizeHandler
 	sharedAuthCodeStore       auth.AuthorizationCodeStore
 	sharedTokenAuthenticator  security.Authenticator
+	sharedDPoPVerifier        *dpop.Verifier
+	sharedClientJwkResolver   clientauth.JwkStoreResolver
 	timeoutSupport            oauth2.TimeoutApplier
 }
 
@@ -257,6 +329,8 @@ func (c *Configuration) tokenGranter() auth.TokenGranter {
 			grants.NewRefreshGranter(c.authorizationService(), c.tokenStore()),
 			grants.NewSwitchUserGranter(c.authorizationService(), c.tokenAuthenticator(), c.UserAccountStore),
 			grants.NewSwitchTenantGranter(c.authorizationService(), c.tokenAuthenticator(), c.UserAccountStore),
+			grants.NewDeviceCodeGranter(c.authorizationService(), c.deviceCodeStore()),
+			grants.NewTokenExchangeGranter(c.authorizationService(), c.tokenAuthenticator()),
 		}
 
 		// password granter is optional
@@ -335,13 +409,62 @@ func (c *Configuration) authorizationService() auth.AuthorizationService {
 	return c.sharedAuthService
 }
 
+// validate checks configured items after AuthorizationServerConfigurer is applied
+func (c *Configuration) validate() error {
+	if c.JwkStore == nil && c.cryptoProperties.Jwt.Rotation.Enabled && c.JwkStorage == nil {
+		return fmt.Errorf("JWK rotation is enabled, but JwkStorage is not configured for authorization server")
+	}
+	return nil
+}
+
+// prepareReplayCaches creates Redis replay caches that are not configured
+func (c *Configuration) prepareReplayCaches() error {
+	if c.DPoPReplayCache == nil {
+		client, e := c.redisClientFactory.New(c.appContext, func(opt *redis.ClientOption) {
+			opt.DbIndex = c.dpopProperties.ReplayCacheDbIndex
+		})
+		if e != nil {
+			return e
+		}
+		c.DPoPReplayCache = replay.NewRedisCache(client, dpop.ReplayCachePrefix)
+	}
+	if c.ClientAssertionReplayCache == nil {
+		client, e := c.redisClientFactory.New(c.appContext, func(opt *redis.ClientOption) {
+			opt.DbIndex = c.sessionProperties.DbIndex
+		})
+		if e != nil {
+			return e
+		}
+		c.ClientAssertionReplayCache = replay.NewRedisCache(client, clientauth.ReplayCachePrefix)
+	}
+	return nil
+}
+
 func (c *Configuration) jwkStore() jwt.JwkStore {
 	if c.JwkStore == nil {
-		c.JwkStore = jwt.NewFileJwkStore(c.cryptoProperties)
+		if c.cryptoProperties.Jwt.Rotation.Enabled {
+			c.JwkStore = c.rotatingJwkStore()
+		} else {
+			c.JwkStore = jwt.NewFileJwkStore(c.cryptoProperties)
+		}
 	}
 	return c.JwkStore
 }
 
+// rotatingJwkStore creates a jwt.RotatingJwkStore that generates and rotates keys in the shared JwkStorage
+func (c *Configuration) rotatingJwkStore() *jwt.RotatingJwkStore {
+	store, e := jwt.NewRotatingJwkStore(
+		jwt.RotatingJwkStoreWithProperties(c.cryptoProperties.Jwt),
+		func(opt *jwt.RotatingJwkStoreOption) {
+			opt.Storage = c.JwkStorage
+		},
+	)
+	if e != nil {
+		panic(e)
+	}
+	return store
+}
+
 func (c *Configuration) jwtEncoder() jwt.JwtEncoder {
 	if c.sharedJwtEncoder == nil {
 		c.sharedJwtEncoder = jwt.NewSignedJwtEncoder(jwt.SignWithJwkStore(c.jwkStore(), c.cryptoProperties.Jwt.KeyName))
@@ -374,10 +497,18 @@ func (c *Configuration) authorizeRequestProcessor() auth.AuthorizeRequestProcess
 		if c.OpenIDSSOEnabled {
 			p := openid.NewOpenIDAuthorizeRequestProcessor(func(opt *openid.ARPOption) {
 				opt.Issuer = c.Issuer
-				opt.JwtDecoder = c.jwtDecoder()
 			})
 			processors = append([]auth.ChainedAuthorizeRequestProcessor{p}, processors...)
 		}
+		// "request_uri" and "request" need to be resolved before any other processors
+		requestObjProcessor := par.NewRequestObjectProcessor(func(opt *par.ProcessorOption) {
+			opt.ClientStore = c.ClientStore
+			opt.RequestStore = c.pushedAuthorizeRequestStore()
+			opt.JwkStoreResolver = c.clientJwkStoreResolver()
+			opt.ReplayCache = c.ClientAssertionReplayCache
+			opt.Issuer = c.Issuer
+		})
+		processors = append([]auth.ChainedAuthorizeRequestProcessor{requestObjProcessor}, processors...)
 		c.sharedARProcessor = auth.NewAuthorizeRequestProcessor(processors...)
 	}
 	return c.sharedARProcessor
@@ -404,6 +535,66 @@ func (c *Configuration) authorizeCodeStore() auth.AuthorizationCodeStore {
 	return c.sharedAuthCodeStore
 }
 
+func (c *Configuration) deviceCodeStore() auth.DeviceCodeStore {
+	if c.DeviceCodeStore == nil {
+		c.DeviceCodeStore = auth.NewRedisDeviceCodeStore(c.appContext, c.redisClientFactory, c.sessionProperties.DbIndex)
+	}
+	return c.DeviceCodeStore
+}
+
+func (c *Configuration) pushedAuthorizeRequestStore() auth.PushedAuthorizeRequestStore {
+	if c.PushedAuthorizeRequestStore == nil {
+		c.PushedAuthorizeRequestStore = auth.NewRedisPushedAuthorizeRequestStore(c.appContext, c.redisClientFactory, c.sessionProperties.DbIndex)
+	}
+	return c.PushedAuthorizeRequestStore
+}
+
+func (c *Configuration) clientJwkStoreResolver() clientauth.JwkStoreResolver {
+	if c.sharedClientJwkResolver == nil {
+		c.sharedClientJwkResolver = clientauth.NewRemoteJwkStoreResolver()
+	}
+	return c.sharedClientJwkResolver
+}
+
+func (c *Configuration) dpopVerifier() *dpop.Verifier {
+	if c.sharedDPoPVerifier == nil {
+		c.sharedDPoPVerifier = dpop.NewVerifier(func(opt *dpop.VerifierOption) {
+			opt.ReplayCache = c.DPoPReplayCache
+			opt.BaseUrl = c.dpopProperties.BaseUrl
+			opt.TrustedProxies = c.serverProperties.TrustedProxies
+		})
+	}
+	return c.sharedDPoPVerifier
+}
+
+// prepareClientRegistry resolves ClientRegistry and make sure registered clients are visible via ClientStore
+func (c *Configuration) prepareClientRegistry() error {
+	if c.ClientRegistry == nil {
+		registry, ok := c.ClientStore.(registration.ClientRegistry)
+		if !ok {
+			return fmt.Errorf("client registration is enabled, but ClientRegistry is not configured for authorization server")
+		}
+		c.ClientRegistry = registry
+	}
+	c.ClientStore = c.ClientRegistry
+	return nil
+}
+
+func (c *Configuration) initialAccessPolicy() registration.InitialAccessPolicy {
+	props := c.properties.Registration
+	if props.Open {
+		return registration.OpenRegistration()
+	}
+	var policies []registration.InitialAccessPolicy
+	if len(props.InitialAccessTokens) != 0 {
+		policies = append(policies, registration.StaticInitialAccessTokens(props.InitialAccessTokens...))
+	}
+	if props.InitialAccessScope != "" {
+		policies = append(policies, registration.ScopedInitialAccessToken(c.tokenStore(), props.InitialAccessScope))
+	}
+	return registration.AnyInitialAccessPolicy(policies...)
+}
+
 func (c *Configuration) tokenAuthenticator() security.Authenticator {
 	if c.sharedTokenAuthenticator == nil {
 		c.sharedTokenAuthenticator = tokenauth.NewAuthenticator(func(opt *tokenauth.AuthenticatorOption) {
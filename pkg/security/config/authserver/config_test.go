@@ -0,0 +1,143 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package authserver
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/registration"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"github.com/cisco-open/go-lanai/pkg/security/replay"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/sectest"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+/*************************
+	Tests
+ *************************/
+
+func TestJwkRotationConfiguration(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestRotationWithoutStorage(), "RotationWithoutStorage"),
+		test.GomegaSubTest(SubTestRotationWithStorage(), "RotationWithStorage"),
+		test.GomegaSubTest(SubTestRotationWithJwkStore(), "RotationWithJwkStore"),
+	)
+}
+
+func TestClientRegistryConfiguration(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestRegistrationWithoutRegistry(), "RegistrationWithoutRegistry"),
+		test.GomegaSubTest(SubTestRegistrationWithRegistry(), "RegistrationWithRegistry"),
+		test.GomegaSubTest(SubTestRegistrationWithRegistryClientStore(), "RegistrationWithRegistryClientStore"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestRotationWithoutStorage() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		_, e := ProvideAuthServerDI(NewRotationTestDI(func(conf *Configuration) {}))
+		g.Expect(e).To(HaveOccurred(), "rotation without JwkStorage should fail")
+	}
+}
+
+func SubTestRotationWithStorage() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		out, e := ProvideAuthServerDI(NewRotationTestDI(func(conf *Configuration) {
+			conf.JwkStorage = jwt.NewInMemoryJwkStorage()
+		}))
+		g.Expect(e).To(Succeed(), "rotation with JwkStorage should not fail")
+		g.Expect(out.Config.jwkStore()).To(BeAssignableToTypeOf(&jwt.RotatingJwkStore{}), "JWK store should be rotating")
+	}
+}
+
+func SubTestRotationWithJwkStore() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		store := jwt.NewStaticJwkStore()
+		out, e := ProvideAuthServerDI(NewRotationTestDI(func(conf *Configuration) {
+			conf.JwkStore = store
+		}))
+		g.Expect(e).To(Succeed(), "rotation with JwkStore should not fail")
+		g.Expect(out.Config.jwkStore()).To(BeIdenticalTo(store), "configured JWK store should be used")
+	}
+}
+
+func SubTestRegistrationWithoutRegistry() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		_, e := ProvideAuthServerDI(NewRegistrationTestDI(func(conf *Configuration) {
+			conf.ClientStore = sectest.NewMockedClientStore()
+		}))
+		g.Expect(e).To(HaveOccurred(), "registration without ClientRegistry should fail")
+	}
+}
+
+func SubTestRegistrationWithRegistry() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		registry := registration.NewInMemoryClientRegistry(sectest.NewMockedClientStore())
+		out, e := ProvideAuthServerDI(NewRegistrationTestDI(func(conf *Configuration) {
+			conf.ClientRegistry = registry
+		}))
+		g.Expect(e).To(Succeed(), "registration with ClientRegistry should not fail")
+		g.Expect(out.Config.ClientStore).To(BeIdenticalTo(registry), "ClientStore should be the configured registry")
+	}
+}
+
+func SubTestRegistrationWithRegistryClientStore() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		registry := registration.NewInMemoryClientRegistry(nil)
+		out, e := ProvideAuthServerDI(NewRegistrationTestDI(func(conf *Configuration) {
+			conf.ClientStore = registry
+		}))
+		g.Expect(e).To(Succeed(), "registration with ClientStore implementing ClientRegistry should not fail")
+		g.Expect(out.Config.ClientRegistry).To(BeIdenticalTo(registry), "ClientStore should be used as registry")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func NewRotationTestDI(configurer AuthorizationServerConfigurer) configDI {
+	props := jwt.CryptoProperties{}
+	props.Jwt.KeyName = "test-key"
+	props.Jwt.Rotation.Enabled = true
+	return configDI{
+		CryptoProperties: props,
+		Configurer:       withInMemoryReplayCaches(configurer),
+	}
+}
+
+func NewRegistrationTestDI(configurer AuthorizationServerConfigurer) configDI {
+	props := AuthServerProperties{}
+	props.Registration.Enabled = true
+	return configDI{
+		Properties: props,
+		Configurer: withInMemoryReplayCaches(configurer),
+	}
+}
+
+func withInMemoryReplayCaches(configurer AuthorizationServerConfigurer) AuthorizationServerConfigurer {
+	return func(conf *Configuration) {
+		conf.DPoPReplayCache = replay.NewInMemoryCache()
+		conf.ClientAssertionReplayCache = replay.NewInMemoryCache()
+		configurer(conf)
+	}
+}
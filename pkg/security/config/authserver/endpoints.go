@@ -92,6 +92,11 @@ func prepareWellKnownEndpoint(config *Configuration) *misc.WellKnownEndpoint {
 		openid.OPMetadataUserInfoEndpoint:   config.Endpoints.UserInfo,
 		openid.OPMetadataJwkSetURI:          config.Endpoints.JwkSet,
 		openid.OPMetadataEndSessionEndpoint: config.Endpoints.Logout,
+		openid.OPMetadataDeviceAuthEndpoint: config.Endpoints.DeviceAuthorization,
+		openid.OPMetadataPAREndpoint:        config.Endpoints.PushedAuthorization,
+	}
+	if config.properties.Registration.Enabled {
+		extra[openid.OPMetadataRegEndpoint] = config.Endpoints.Registration
 	}
 	return misc.NewWellKnownEndpoint(config.Issuer, config.IdpManager, extra)
 }
@@ -20,6 +20,7 @@ import (
     "embed"
     appconfig "github.com/cisco-open/go-lanai/pkg/appconfig/init"
     "github.com/cisco-open/go-lanai/pkg/bootstrap"
+    "github.com/cisco-open/go-lanai/pkg/log"
     "github.com/cisco-open/go-lanai/pkg/security"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2/timeoutsupport"
     samlidp "github.com/cisco-open/go-lanai/pkg/security/saml/idp"
@@ -27,6 +28,8 @@ import (
     "go.uber.org/fx"
 )
 
+var logger = log.New("SEC.AuthServer")
+
 //go:embed defaults-authserver.yml
 var defaultConfigFS embed.FS
 
@@ -18,7 +18,10 @@ package authserver
 
 import (
 	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/utils"
 	"github.com/pkg/errors"
+	"time"
 )
 
 const (
@@ -27,9 +30,12 @@ const (
 
 //goland:noinspection GoNameStartsWithPackageName
 type AuthServerProperties struct {
-	Issuer            IssuerProperties    `json:"issuer"`
-	RedirectWhitelist []string            `json:"redirect-whitelist"`
-	Endpoints         EndpointsProperties `json:"endpoints"`
+	Issuer            IssuerProperties       `json:"issuer"`
+	RedirectWhitelist []string               `json:"redirect-whitelist"`
+	Endpoints         EndpointsProperties    `json:"endpoints"`
+	Device            DeviceProperties       `json:"device"`
+	Registration      RegistrationProperties `json:"registration"`
+	PAR               PARProperties          `json:"par"`
 }
 
 type IssuerProperties struct {
@@ -57,6 +63,45 @@ type EndpointsProperties struct {
 	UserInfo        string `json:"user-info"`
 	JwkSet          string `json:"jwk-set"`
 	SamlMetadata    string `json:"saml-metadata"`
+	// DeviceAuthorization and DeviceVerification are endpoints of OAuth2 Device Authorization Grant (RFC 8628)
+	DeviceAuthorization string `json:"device-authorization"`
+	DeviceVerification  string `json:"device-verification"`
+	// Registration is the endpoint of OAuth2 Dynamic Client Registration (RFC 7591 & RFC 7592)
+	Registration string `json:"registration"`
+	// PushedAuthorization is the endpoint of OAuth2 Pushed Authorization Requests (RFC 9126)
+	PushedAuthorization string `json:"pushed-authorization"`
+}
+
+type DeviceProperties struct {
+	// CodeValidity is the lifetime of device code and user code
+	CodeValidity utils.Duration `json:"code-validity"`
+	// PollingInterval is the minimum amount of time that clients should wait between polling token endpoint
+	PollingInterval utils.Duration `json:"polling-interval"`
+	// MaxVerificationAttempts is the max number of invalid user codes a user can enter within VerificationAttemptsWindow.
+	// Zero or negative value disables the throttling
+	MaxVerificationAttempts int `json:"max-verification-attempts"`
+	// VerificationAttemptsWindow is the period during which failed user code attempts are counted
+	VerificationAttemptsWindow utils.Duration `json:"verification-attempts-window"`
+}
+
+type RegistrationProperties struct {
+	Enabled bool `json:"enabled"`
+	// Open allows anyone to register clients without initial access token
+	Open bool `json:"open"`
+	// InitialAccessTokens are pre-shared tokens that allow client registration
+	InitialAccessTokens []string `json:"initial-access-tokens"`
+	// InitialAccessScope allows client registration with access tokens issued by this server having this scope
+	InitialAccessScope string `json:"initial-access-scope"`
+	// AllowedGrantTypes, AllowedScopes and DefaultScopes restrict metadata of registered clients
+	AllowedGrantTypes []string `json:"allowed-grant-types"`
+	AllowedScopes     []string `json:"allowed-scopes"`
+	DefaultScopes     []string `json:"default-scopes"`
+}
+
+type PARProperties struct {
+	// RequestValidity is the lifetime of "request_uri" issued by pushed authorization request endpoint.
+	// The "request_uri" is consumed once user is authenticated, so it should be long enough for user to log in.
+	RequestValidity utils.Duration `json:"request-validity"`
 }
 
 // NewAuthServerProperties create a SessionProperties with default values
@@ -71,17 +116,36 @@ func NewAuthServerProperties() *AuthServerProperties {
 		},
 		RedirectWhitelist: []string{},
 		Endpoints: EndpointsProperties{
-			Authorize:       "/v2/authorize",
-			Token:           "/v2/token",
-			Approval:        "/v2/approve",
-			CheckToken:      "/v2/check_token",
-			TenantHierarchy: "/v2/tenant_hierarchy",
-			Error:           "/error",
-			Logout:          "/v2/logout",
-			UserInfo:        "/v2/userinfo",
-			JwkSet:          "/v2/jwks",
-			SamlMetadata:    "/metadata",
-			LoggedOut:       "/",
+			Authorize:           "/v2/authorize",
+			Token:               "/v2/token",
+			Approval:            "/v2/approve",
+			CheckToken:          "/v2/check_token",
+			TenantHierarchy:     "/v2/tenant_hierarchy",
+			Error:               "/error",
+			Logout:              "/v2/logout",
+			UserInfo:            "/v2/userinfo",
+			JwkSet:              "/v2/jwks",
+			SamlMetadata:        "/metadata",
+			LoggedOut:           "/",
+			DeviceAuthorization: "/v2/device_authorization",
+			DeviceVerification:  "/v2/device",
+			Registration:        "/v2/register",
+			PushedAuthorization: "/v2/par",
+		},
+		Device: DeviceProperties{
+			CodeValidity:               utils.Duration(10 * time.Minute),
+			PollingInterval:            utils.Duration(5 * time.Second),
+			MaxVerificationAttempts:    5,
+			VerificationAttemptsWindow: utils.Duration(15 * time.Minute),
+		},
+		PAR: PARProperties{
+			RequestValidity: utils.Duration(5 * time.Minute),
+		},
+		Registration: RegistrationProperties{
+			InitialAccessTokens: []string{},
+			AllowedGrantTypes:   []string{oauth2.GrantTypeAuthCode, oauth2.GrantTypeRefresh, oauth2.GrantTypeClientCredentials},
+			AllowedScopes:       []string{oauth2.ScopeRead, oauth2.ScopeWrite, oauth2.ScopeOidc},
+			DefaultScopes:       []string{oauth2.ScopeRead},
 		},
 	}
 }
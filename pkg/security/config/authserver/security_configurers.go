@@ -25,7 +25,10 @@ import (
 	"github.com/cisco-open/go-lanai/pkg/security/logout"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/authorize"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/clientauth"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/device"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/openid"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/par"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/registration"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/revoke"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/token"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/tokenauth"
@@ -35,6 +38,7 @@ import (
 	"github.com/cisco-open/go-lanai/pkg/security/session"
 	"github.com/cisco-open/go-lanai/pkg/utils"
 	"github.com/cisco-open/go-lanai/pkg/web/matcher"
+	"time"
 )
 
 /***************************
@@ -74,10 +78,15 @@ func (c *ClientAuthEndpointsConfigurer) Configure(ws security.WebSecurity) {
 	ws.Route(matcher.RouteWithPattern(c.config.Endpoints.Token)).
 		Route(matcher.RouteWithPattern(c.config.Endpoints.CheckToken)).
 		Route(matcher.RouteWithPattern(fmt.Sprintf("%s/*", c.config.Endpoints.TenantHierarchy))).
+		Route(matcher.RouteWithPattern(c.config.Endpoints.DeviceAuthorization)).
+		Route(matcher.RouteWithPattern(c.config.Endpoints.PushedAuthorization)).
 		With(clientauth.New().
 			ClientStore(c.config.ClientStore).
 			ClientSecretEncoder(c.config.clientSecretEncoder()).
 			ErrorHandler(c.config.errorHandler()).
+			Issuer(c.config.Issuer).
+			JwkStoreResolver(c.config.clientJwkStoreResolver()).
+			ReplayCache(c.config.ClientAssertionReplayCache).
 			AllowForm(true), // AllowForm also implicitly enables Public Client
 		).
 		// uncomment following if we want CheckToken to not allow public client
@@ -87,7 +96,22 @@ func (c *ClientAuthEndpointsConfigurer) Configure(ws security.WebSecurity) {
 		//).
 		With(token.NewEndpoint().
 			Path(c.config.Endpoints.Token).
-			AddGranter(c.config.tokenGranter()),
+			AddGranter(c.config.tokenGranter()).
+			DPoPVerifier(c.config.dpopVerifier()),
+		).
+		With(device.NewAuthorizationEndpoint().
+			Path(c.config.Endpoints.DeviceAuthorization).
+			VerificationPath(c.config.Endpoints.DeviceVerification).
+			Issuer(c.config.Issuer).
+			DeviceCodeStore(c.config.deviceCodeStore()).
+			CodeValidity(time.Duration(c.config.properties.Device.CodeValidity)).
+			PollingInterval(time.Duration(c.config.properties.Device.PollingInterval)),
+		).
+		With(par.NewEndpoint().
+			Path(c.config.Endpoints.PushedAuthorization).
+			RequestProcessor(c.config.authorizeRequestProcessor()).
+			RequestStore(c.config.pushedAuthorizeRequestStore()).
+			RequestValidity(time.Duration(c.config.properties.PAR.RequestValidity)),
 		)
 }
 
@@ -113,6 +137,33 @@ func (c *TokenAuthEndpointsConfigurer) Configure(ws security.WebSecurity) {
 		With(errorhandling.New())
 }
 
+// RegistrationEndpointConfigurer implements security.Configurer and order.Ordered
+// responsible to configure dynamic client registration endpoints
+type RegistrationEndpointConfigurer struct {
+	config *Configuration
+}
+
+func (c *RegistrationEndpointConfigurer) Order() int {
+	return OrderRegistrationSecurityConfigurer
+}
+
+func (c *RegistrationEndpointConfigurer) Configure(ws security.WebSecurity) {
+	props := c.config.properties.Registration
+	ws.Route(matcher.RouteWithPattern(c.config.Endpoints.Registration)).
+		Route(matcher.RouteWithPattern(fmt.Sprintf("%s/*", c.config.Endpoints.Registration))).
+		With(registration.NewEndpoint().
+			Path(c.config.Endpoints.Registration).
+			Issuer(c.config.Issuer).
+			ClientRegistry(c.config.ClientRegistry).
+			InitialAccessPolicy(c.config.initialAccessPolicy()).
+			ClientSecretEncoder(c.config.clientSecretEncoder()).
+			AllowedGrantTypes(props.AllowedGrantTypes...).
+			AllowedScopes(props.AllowedScopes...).
+			DefaultScopes(props.DefaultScopes...).
+			ErrorHandler(c.config.errorHandler()),
+		)
+}
+
 // AuthorizeEndpointConfigurer implements security.Configurer and order.Ordered
 // responsible to configure "authorize" endpoint
 type AuthorizeEndpointConfigurer struct {
@@ -144,7 +195,13 @@ func (c *AuthorizeEndpointConfigurer) Configure(ws security.WebSecurity) {
 			SsoLocation(c.config.Endpoints.SamlSso.Location).
 			MetadataPath(c.config.Endpoints.SamlMetadata).
 			EnableSLO(c.config.Endpoints.Logout).
-			SigningMethod(c.config.SamlIdpSigningMethod))
+			SigningMethod(c.config.SamlIdpSigningMethod)).
+		Route(matcher.RouteWithPattern(c.config.Endpoints.DeviceVerification)).
+		With(device.NewVerificationEndpoint().
+			Path(c.config.Endpoints.DeviceVerification).
+			DeviceCodeStore(c.config.deviceCodeStore()).
+			MaxAttempts(c.config.properties.Device.MaxVerificationAttempts,
+				time.Duration(c.config.properties.Device.VerificationAttemptsWindow)))
 
 	c.delegate.Configure(ws, c.config)
 }
@@ -39,6 +39,9 @@ import (
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/authorize"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/clientauth"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/device"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/par"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/registration"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/token"
 	"github.com/cisco-open/go-lanai/pkg/security/passwd"
 	"github.com/cisco-open/go-lanai/pkg/security/request_cache"
@@ -171,7 +174,7 @@ func TestWithMockedServer(t *testing.T) {
 			passwdidp.Module, extsamlidp.Module, authorize.Module, samlidp.Module,
 			passwd.Module, formlogin.Module, logout.Module,
 			samlctx.Module, samlsp.Module,
-			basicauth.Module, clientauth.Module,
+			basicauth.Module, clientauth.Module, device.Module, registration.Module, par.Module,
 			token.Module, access.Module, errorhandling.Module,
 			request_cache.Module, csrf.Module, session.Module,
 			redis.Module, tenancy.Module,
@@ -203,6 +206,9 @@ func TestWithMockedServer(t *testing.T) {
 		test.GomegaSubTest(SubTestOAuth2AuthCodeWithTenantClient(di), "TestOAuth2AuthCodeWithTenantClient"),
 		test.GomegaSubTest(SubTestOAuth2PasswordGrant(di), "TestOAuth2PasswordGrant"),
 		test.GomegaSubTest(SubTestTenantClientCredential(di), "TestTenantClientCredential"),
+		test.GomegaSubTest(SubTestOAuth2DeviceCode(di), "TestOAuth2DeviceCode"),
+		test.GomegaSubTest(SubTestOAuth2PushedAuthorization(di), "TestOAuth2PushedAuthorization"),
+		test.GomegaSubTest(SubTestOAuth2TokenExchange(di), "TestOAuth2TokenExchange"),
 
 		//switch tenants
 		test.GomegaSubTest(SubTestOauth2SwitchTenantWithPerTenantPermission(di), "TestOauth2SwitchTenantWithPerTenantPermission"),
@@ -222,7 +228,7 @@ func TestWithMockedServerWithoutFinalizer(t *testing.T) {
 			passwdidp.Module, extsamlidp.Module, authorize.Module, samlidp.Module,
 			passwd.Module, formlogin.Module, logout.Module,
 			samlctx.Module, samlsp.Module,
-			basicauth.Module, clientauth.Module,
+			basicauth.Module, clientauth.Module, device.Module, registration.Module, par.Module,
 			token.Module, access.Module, errorhandling.Module,
 			request_cache.Module, csrf.Module, session.Module,
 			redis.Module,
@@ -560,6 +566,147 @@ func SubTestOAuth2PasswordGrant(di *intDI) test.GomegaSubTestFunc {
 	}
 }
 
+func SubTestOAuth2DeviceCode(di *intDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		// device authorization request
+		req := webtest.NewRequest(ctx, http.MethodPost, "/v2/device_authorization", strings.NewReader(url.Values{}.Encode()),
+			tokenReqOptions(), withDefaultClientAuth())
+		resp := webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusOK), "device authorization should have correct status code")
+		deviceCode, userCode := assertDeviceAuthorizationResponse(t, g, resp.Response)
+
+		// poll token endpoint before user verification, expect authorization_pending
+		req = webtest.NewRequest(ctx, http.MethodPost, "/v2/token", deviceCodeReqBody(deviceCode), tokenReqOptions(), withDefaultClientAuth())
+		resp = webtest.MustExec(ctx, req)
+		assertTokenErrorResponse(t, g, resp.Response, oauth2.ErrorTranslationAuthorizationPending)
+
+		// user approve on verification page
+		s, token, err := newSessionWithCsrfToken(di.SessionStore)
+		g.Expect(err).ToNot(HaveOccurred(), "session should be created")
+		fedAccount := di.Mocking.FederatedUsers.MapValues()["fed1"]
+		ctx, e := contextWithSamlAuth(ctx, di.FedAccountStore, fedAccount)
+		g.Expect(e).To(Succeed(), "SAML auth should be stored correctly")
+
+		verifyUri := fmt.Sprintf("http://%s/test/v2/device", testdata.IdpDomainExtSAML)
+		req = webtest.NewRequest(ctx, http.MethodPost, verifyUri, deviceVerifyReqBody(userCode, "true", token),
+			approvalReqOptions(), cookieOptions(s.Name(), s.GetID()))
+		resp = webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusOK), "device verification should have correct status code")
+
+		// poll again, expect token
+		req = webtest.NewRequest(ctx, http.MethodPost, "/v2/token", deviceCodeReqBody(deviceCode), tokenReqOptions(), withDefaultClientAuth())
+		resp = webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusOK), "token response should have correct status code")
+		assertTokenResponse(t, g, resp.Response, fedAccount.Username, true)
+
+		// device code is consumed
+		req = webtest.NewRequest(ctx, http.MethodPost, "/v2/token", deviceCodeReqBody(deviceCode), tokenReqOptions(), withDefaultClientAuth())
+		resp = webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusBadRequest), "reused device code should be rejected")
+	}
+}
+
+func SubTestOAuth2PushedAuthorization(di *intDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		// push authorization request
+		req := webtest.NewRequest(ctx, http.MethodPost, "/v2/par", parReqBody(TestClientID), tokenReqOptions(), withDefaultClientAuth())
+		resp := webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusCreated), "pushed authorization should have correct status code")
+		requestUri := assertPARResponse(t, g, resp.Response)
+
+		// push without client authentication
+		req = webtest.NewRequest(ctx, http.MethodPost, "/v2/par", parReqBody(TestClientID), tokenReqOptions())
+		resp = webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusUnauthorized), "pushed authorization without client auth should be rejected")
+
+		// authorize with request_uri
+		fedAccount := di.Mocking.FederatedUsers.MapValues()["fed1"]
+		ctx, e := contextWithSamlAuth(ctx, di.FedAccountStore, fedAccount)
+		g.Expect(e).To(Succeed(), "SAML auth should be stored correctly")
+		req = webtest.NewRequest(ctx, http.MethodGet, "/v2/authorize", nil, authorizeWithRequestUriOptions(TestClientID, requestUri))
+		resp = webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusFound), "authorize with request_uri should have correct status code")
+		assertAuthorizeResponse(t, g, resp.Response, false)
+
+		// token
+		code := extractAuthCode(resp.Response)
+		req = webtest.NewRequest(ctx, http.MethodPost, "/v2/token", authCodeReqBody(code, TestClientID, ""), tokenReqOptions())
+		resp = webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusOK), "token response should have correct status code")
+		assertTokenResponse(t, g, resp.Response, fedAccount.Username, true)
+
+		// request_uri not issued by the server
+		req = webtest.NewRequest(ctx, http.MethodGet, "/v2/authorize", nil, authorizeWithRequestUriOptions(TestClientID, "urn:ietf:params:oauth:request_uri:unknown"))
+		resp = webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusBadRequest), "authorize with unknown request_uri should be rejected without redirect")
+	}
+}
+
+func SubTestOAuth2TokenExchange(di *intDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		// subject token
+		req := webtest.NewRequest(ctx, http.MethodPost, "/v2/token", passwordGrantReqBody("", "regular", "regular"), tokenReqOptions(), withDefaultClientAuth())
+		resp := webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusOK), "subject token response should have correct status code")
+		subject := assertTokenResponse(t, g, resp.Response, "regular", false)
+
+		// actor token
+		req = webtest.NewRequest(ctx, http.MethodPost, "/v2/token", clientCredentialReqBody(""), tokenReqOptions(), withDefaultClientAuth())
+		resp = webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusOK), "actor token response should have correct status code")
+		body, e := io.ReadAll(resp.Response.Body)
+		g.Expect(e).To(Succeed(), "actor token response body should be readable")
+		actor := oauth2.NewDefaultAccessToken("")
+		g.Expect(json.Unmarshal(body, actor)).To(Succeed(), "actor token response should be valid JSON")
+
+		// impersonation
+		req = webtest.NewRequest(ctx, http.MethodPost, "/v2/token", tokenExchangeReqBody(subject.Value(), "", oauth2.LegacyResourceId), tokenReqOptions(), withDefaultClientAuth())
+		resp = webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusOK), "token exchange should have correct status code")
+		exchanged := assertTokenExchangeResponse(t, g, resp.Response)
+		auth, e := di.TokenReader.ReadAuthentication(ctx, exchanged.Value(), oauth2.TokenHintAccessToken)
+		g.Expect(e).To(Succeed(), "exchanged token should be valid")
+		g.Expect(auth.OAuth2Request().Extensions()).ToNot(HaveKey(oauth2.ClaimActor), "impersonation should not have actor")
+		assertUserAuth(t, g, auth, "id-regular", "id-tenant-1", utils.NewStringSet("id-tenant-1", "id-tenant-2"), "test-provider")
+
+		// delegation
+		req = webtest.NewRequest(ctx, http.MethodPost, "/v2/token", tokenExchangeReqBody(subject.Value(), actor.Value(), ""), tokenReqOptions(), withDefaultClientAuth())
+		resp = webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusOK), "token exchange should have correct status code")
+		exchanged = assertTokenExchangeResponse(t, g, resp.Response)
+		auth, e = di.TokenReader.ReadAuthentication(ctx, exchanged.Value(), oauth2.TokenHintAccessToken)
+		g.Expect(e).To(Succeed(), "exchanged token should be valid")
+		g.Expect(auth.OAuth2Request().Extensions()).To(HaveKeyWithValue(oauth2.ClaimActor, HaveKeyWithValue(oauth2.ClaimClientId, TestClientID)),
+			"delegation should have correct actor")
+
+		// nested delegation, prior actor is taken from subject token
+		req = webtest.NewRequest(ctx, http.MethodPost, "/v2/token", tokenExchangeReqBody(exchanged.Value(), actor.Value(), ""), tokenReqOptions(), withDefaultClientAuth())
+		resp = webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusOK), "token exchange should have correct status code")
+		nested := assertTokenExchangeResponse(t, g, resp.Response)
+		auth, e = di.TokenReader.ReadAuthentication(ctx, nested.Value(), oauth2.TokenHintAccessToken)
+		g.Expect(e).To(Succeed(), "exchanged token should be valid")
+		g.Expect(auth.OAuth2Request().Extensions()).To(HaveKeyWithValue(oauth2.ClaimActor,
+			HaveKeyWithValue(oauth2.ClaimActor, HaveKeyWithValue(oauth2.ClaimClientId, TestClientID))),
+			"nested delegation should have prior actor")
+
+		// actor cannot be injected by request parameters
+		injected, _ := io.ReadAll(tokenExchangeReqBody(subject.Value(), "", ""))
+		req = webtest.NewRequest(ctx, http.MethodPost, "/v2/token", strings.NewReader(string(injected)+"&act=injected"), tokenReqOptions(), withDefaultClientAuth())
+		resp = webtest.MustExec(ctx, req)
+		g.Expect(resp.Response.StatusCode).To(Equal(http.StatusOK), "token exchange should have correct status code")
+		exchanged = assertTokenExchangeResponse(t, g, resp.Response)
+		auth, e = di.TokenReader.ReadAuthentication(ctx, exchanged.Value(), oauth2.TokenHintAccessToken)
+		g.Expect(e).To(Succeed(), "exchanged token should be valid")
+		g.Expect(auth.OAuth2Request().Extensions()).ToNot(HaveKey(oauth2.ClaimActor), "actor should not be injected by request")
+
+		// invalid audience
+		req = webtest.NewRequest(ctx, http.MethodPost, "/v2/token", tokenExchangeReqBody(subject.Value(), "", "unknown-service"), tokenReqOptions(), withDefaultClientAuth())
+		resp = webtest.MustExec(ctx, req)
+		assertTokenErrorResponse(t, g, resp.Response, oauth2.ErrorTranslationInvalidTarget)
+	}
+}
+
 type ClientCredentialTestStruct struct {
 	name                    string
 	clientId                string
@@ -1051,6 +1198,17 @@ func authorizeReqOptions(clientId string) webtest.RequestOptions {
 	}
 }
 
+func authorizeWithRequestUriOptions(clientId, requestUri string) webtest.RequestOptions {
+	return func(req *http.Request) {
+		req.Host = testdata.IdpDomainExtSAML
+		req.URL.Host = testdata.IdpDomainExtSAML
+		values := url.Values{}
+		values.Set(oauth2.ParameterClientId, clientId)
+		values.Set(oauth2.ParameterRequestUri, requestUri)
+		req.URL.RawQuery = values.Encode()
+	}
+}
+
 func approvalReqOptions() webtest.RequestOptions {
 	return func(req *http.Request) {
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
@@ -1070,6 +1228,15 @@ func extractAuthCode(resp *http.Response) string {
 	return locUrl.Query().Get("code")
 }
 
+func parReqBody(clientId string) io.Reader {
+	values := url.Values{}
+	values.Set(oauth2.ParameterResponseType, "code")
+	values.Set(oauth2.ParameterClientId, clientId)
+	values.Set(oauth2.ParameterRedirectUri, "http://localhost/test/callback")
+	values.Set(oauth2.ParameterState, "test-state")
+	return strings.NewReader(values.Encode())
+}
+
 func authCodeReqBody(code string, clientId string, tenantId string) io.Reader {
 	values := url.Values{}
 	values.Set(oauth2.ParameterGrantType, oauth2.GrantTypeAuthCode)
@@ -1129,6 +1296,36 @@ func approveRequestBody(approval string, approvedScopes []string, csrfToken *csr
 	return strings.NewReader(values.Encode())
 }
 
+func deviceCodeReqBody(deviceCode string) io.Reader {
+	values := url.Values{}
+	values.Set(oauth2.ParameterGrantType, oauth2.GrantTypeDeviceCode)
+	values.Set(oauth2.ParameterDeviceCode, deviceCode)
+	return strings.NewReader(values.Encode())
+}
+
+func deviceVerifyReqBody(userCode string, approval string, csrfToken *csrf.Token) io.Reader {
+	values := url.Values{}
+	values.Set(oauth2.ParameterUserCode, userCode)
+	values.Set(oauth2.ParameterUserApproval, approval)
+	values.Set(csrfToken.ParameterName, csrfToken.Value)
+	return strings.NewReader(values.Encode())
+}
+
+func tokenExchangeReqBody(subjectToken string, actorToken string, audience string) io.Reader {
+	values := url.Values{}
+	values.Set(oauth2.ParameterGrantType, oauth2.GrantTypeTokenExchange)
+	values.Set(oauth2.ParameterSubjectToken, subjectToken)
+	values.Set(oauth2.ParameterSubjectTokenType, oauth2.TokenTypeIdAccessToken)
+	if actorToken != "" {
+		values.Set(oauth2.ParameterActorToken, actorToken)
+		values.Set(oauth2.ParameterActorTokenType, oauth2.TokenTypeIdAccessToken)
+	}
+	if audience != "" {
+		values.Set(oauth2.ParameterAudience, audience)
+	}
+	return strings.NewReader(values.Encode())
+}
+
 func requestNewAccessToken(refreshToken string) io.Reader {
 	values := url.Values{}
 	values.Set(oauth2.ParameterGrantType, oauth2.GrantTypeRefresh)
@@ -1157,6 +1354,58 @@ func assertTokenResponse(_ *testing.T, g *gomega.WithT, resp *http.Response, exp
 	return accessToken
 }
 
+func assertDeviceAuthorizationResponse(_ *testing.T, g *gomega.WithT, resp *http.Response) (deviceCode, userCode string) {
+	body, e := io.ReadAll(resp.Body)
+	g.Expect(e).To(Succeed(), `device authorization response body should be readable`)
+	g.Expect(body).To(HaveJsonPath("$.verification_uri"), "device authorization response should have verification_uri")
+	g.Expect(body).To(HaveJsonPath("$.verification_uri_complete"), "device authorization response should have verification_uri_complete")
+	g.Expect(body).To(HaveJsonPath("$.expires_in"), "device authorization response should have expires_in")
+	g.Expect(body).To(HaveJsonPath("$.interval"), "device authorization response should have interval")
+
+	var v struct {
+		DeviceCode string `json:"device_code"`
+		UserCode   string `json:"user_code"`
+	}
+	e = json.Unmarshal(body, &v)
+	g.Expect(e).ToNot(HaveOccurred())
+	g.Expect(v.DeviceCode).ToNot(BeEmpty(), "device authorization response should have device_code")
+	g.Expect(v.UserCode).ToNot(BeEmpty(), "device authorization response should have user_code")
+	return v.DeviceCode, v.UserCode
+}
+
+func assertPARResponse(_ *testing.T, g *gomega.WithT, resp *http.Response) (requestUri string) {
+	body, e := io.ReadAll(resp.Body)
+	g.Expect(e).To(Succeed(), `pushed authorization response body should be readable`)
+	g.Expect(body).To(HaveJsonPath("$.expires_in"), "pushed authorization response should have expires_in")
+
+	var v struct {
+		RequestUri string `json:"request_uri"`
+	}
+	e = json.Unmarshal(body, &v)
+	g.Expect(e).ToNot(HaveOccurred())
+	g.Expect(v.RequestUri).To(HavePrefix("urn:ietf:params:oauth:request_uri:"), "pushed authorization response should have request_uri")
+	return v.RequestUri
+}
+
+func assertTokenExchangeResponse(_ *testing.T, g *gomega.WithT, resp *http.Response) oauth2.AccessToken {
+	body, e := io.ReadAll(resp.Body)
+	g.Expect(e).To(Succeed(), `token exchange response body should be readable`)
+	g.Expect(body).To(HaveJsonPath("$.access_token"), "token exchange response should have access_token")
+	g.Expect(body).To(HaveJsonPathWithValue("$.issued_token_type", oauth2.TokenTypeIdAccessToken), "token exchange response should have correct issued_token_type")
+	g.Expect(body).NotTo(HaveJsonPath("$.refresh_token"), "token exchange response should not have refresh_token")
+	token := oauth2.NewDefaultAccessToken("")
+	e = json.Unmarshal(body, token)
+	g.Expect(e).To(Succeed(), "token exchange response should be a valid access token")
+	return token
+}
+
+func assertTokenErrorResponse(_ *testing.T, g *gomega.WithT, resp *http.Response, expectedError string) {
+	g.Expect(resp.StatusCode).To(Equal(http.StatusBadRequest), "token error response should have correct status code")
+	body, e := io.ReadAll(resp.Body)
+	g.Expect(e).To(Succeed(), `token error response body should be readable`)
+	g.Expect(body).To(HaveJsonPathWithValue("$.error", expectedError), "token error response should have correct error")
+}
+
 func assertAuthorizeResponse(t *testing.T, g *gomega.WithT, resp *http.Response, expectErr bool) {
 	g.Expect(resp.Header.Get("Set-Cookie")).To(Not(BeEmpty()), "authorize response should set cookie")
 	expected, _ := url.Parse(ExpectedAuthorizeCallback)
@@ -17,6 +17,7 @@
 package resserver
 
 import (
+	"fmt"
 	"github.com/cisco-open/go-lanai/pkg/bootstrap"
 	"github.com/cisco-open/go-lanai/pkg/discovery"
 	"github.com/cisco-open/go-lanai/pkg/redis"
@@ -24,8 +25,11 @@ import (
 	"github.com/cisco-open/go-lanai/pkg/security/config/compatibility"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/common"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/dpop"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
 	"github.com/cisco-open/go-lanai/pkg/security/oauth2/tokenauth"
+	"github.com/cisco-open/go-lanai/pkg/security/replay"
+	"github.com/cisco-open/go-lanai/pkg/web"
 	"go.uber.org/fx"
 )
 
@@ -36,6 +40,8 @@ type resServerConfigDI struct {
 	AppContext         *bootstrap.ApplicationContext
 	RedisClientFactory redis.ClientFactory
 	CryptoProperties   jwt.CryptoProperties
+	DPoPProperties     dpop.DPoPProperties
+	ServerProperties   web.ServerProperties
 	TimeoutSupport     oauth2.TimeoutApplier `optional:"true"`
 	Configurer         ResourceServerConfigurer
 }
@@ -48,10 +54,12 @@ type resServerOut struct {
 }
 
 //goland:noinspection GoExportedFuncWithUnexportedType,HttpUrlsUsage
-func ProvideResServerDI(di resServerConfigDI) resServerOut {
+func ProvideResServerDI(di resServerConfigDI) (resServerOut, error) {
 	config := Configuration{
 		appContext:         di.AppContext,
 		cryptoProperties:   di.CryptoProperties,
+		dpopProperties:     di.DPoPProperties,
+		serverProperties:   di.ServerProperties,
 		redisClientFactory: di.RedisClientFactory,
 		timeoutSupport:     di.TimeoutSupport,
 		RemoteEndpoints: RemoteEndpoints{
@@ -62,11 +70,17 @@ func ProvideResServerDI(di resServerConfigDI) resServerOut {
 		},
 	}
 	di.Configurer(&config)
+	if e := config.validate(); e != nil {
+		return resServerOut{}, e
+	}
+	if e := config.prepareReplayCaches(); e != nil {
+		return resServerOut{}, e
+	}
 	return resServerOut{
 		Config:                  &config,
 		TokenStore:              config.SharedTokenStoreReader(),
 		CompatibilityCustomizer: compatibility.CompatibilityDiscoveryCustomizer{},
-	}
+	}, nil
 }
 
 type resServerDI struct {
@@ -80,6 +94,7 @@ func ConfigureResourceServer(di resServerDI) {
 	// register token auth feature
 	configurer := tokenauth.NewTokenAuthConfigurer(func(opt *tokenauth.TokenAuthOption) {
 		opt.TokenStoreReader = di.Config.tokenStoreReader()
+		opt.DPoPVerifier = di.Config.dpopVerifier()
 	})
 	di.SecurityRegistrar.(security.FeatureRegistrar).RegisterFeature(tokenauth.FeatureId, configurer)
 }
@@ -100,15 +115,23 @@ type Configuration struct {
 	RemoteEndpoints  RemoteEndpoints
 	TokenStoreReader oauth2.TokenStoreReader
 	JwkStore         jwt.JwkStore
+	// DPoPReplayCache keeps used "jti" of DPoP proofs. Default to Redis
+	DPoPReplayCache replay.Cache
+	// JwkStorage is the storage shared with authorization server when JWK rotation is enabled ("security.jwt.rotation.enabled").
+	// It's required when rotation is enabled and JwkStore is not set. JWKs are loaded from the storage instead of key files
+	JwkStorage jwt.RotatingJwkStorage
 
 	// not directly configurable items
 	appContext                *bootstrap.ApplicationContext
 	redisClientFactory        redis.ClientFactory
 	cryptoProperties          jwt.CryptoProperties
+	dpopProperties            dpop.DPoPProperties
+	serverProperties          web.ServerProperties
 	sharedTokenAuthenticator  security.Authenticator
 	sharedErrorHandler        *tokenauth.OAuth2ErrorHandler
 	sharedContextDetailsStore security.ContextDetailsStore
 	sharedJwtDecoder          jwt.JwtDecoder
+	sharedDPoPVerifier        *dpop.Verifier
 	timeoutSupport            oauth2.TimeoutApplier
 }
 
@@ -140,13 +163,54 @@ func (c *Configuration) tokenStoreReader() oauth2.TokenStoreReader {
 	return c.TokenStoreReader
 }
 
+// validate checks configured items after ResourceServerConfigurer is applied
+func (c *Configuration) validate() error {
+	if c.JwkStore == nil && c.cryptoProperties.Jwt.Rotation.Enabled && c.JwkStorage == nil {
+		return fmt.Errorf("JWK rotation is enabled, but JwkStorage is not configured for resource server")
+	}
+	return nil
+}
+
+// prepareReplayCaches creates Redis replay caches that are not configured
+func (c *Configuration) prepareReplayCaches() error {
+	if c.DPoPReplayCache == nil {
+		client, e := c.redisClientFactory.New(c.appContext, func(opt *redis.ClientOption) {
+			opt.DbIndex = c.dpopProperties.ReplayCacheDbIndex
+		})
+		if e != nil {
+			return e
+		}
+		c.DPoPReplayCache = replay.NewRedisCache(client, dpop.ReplayCachePrefix)
+	}
+	return nil
+}
+
 func (c *Configuration) jwkStore() jwt.JwkStore {
 	if c.JwkStore == nil {
-		c.JwkStore = jwt.NewFileJwkStore(c.cryptoProperties)
+		if c.cryptoProperties.Jwt.Rotation.Enabled {
+			c.JwkStore = c.rotatingJwkStore()
+		} else {
+			c.JwkStore = jwt.NewFileJwkStore(c.cryptoProperties)
+		}
 	}
 	return c.JwkStore
 }
 
+// rotatingJwkStore creates a read-only jwt.RotatingJwkStore. Keys are generated and rotated by authorization server
+func (c *Configuration) rotatingJwkStore() *jwt.RotatingJwkStore {
+	store, e := jwt.NewRotatingJwkStore(
+		jwt.RotatingJwkStoreWithProperties(c.cryptoProperties.Jwt),
+		func(opt *jwt.RotatingJwkStoreOption) {
+			opt.Storage = c.JwkStorage
+			opt.ReadOnly = true
+		},
+	)
+	if e != nil {
+		panic(e)
+	}
+	return store
+}
+
 func (c *Configuration) jwtDecoder() jwt.JwtDecoder {
 	if c.sharedJwtDecoder == nil {
 		c.sharedJwtDecoder = jwt.NewSignedJwtDecoder(jwt.VerifyWithJwkStore(c.jwkStore(), c.cryptoProperties.Jwt.KeyName))
@@ -162,3 +226,14 @@ func (c *Configuration) tokenAuthenticator() security.Authenticator {
 	}
 	return c.sharedTokenAuthenticator
 }
+
+func (c *Configuration) dpopVerifier() *dpop.Verifier {
+	if c.sharedDPoPVerifier == nil {
+		c.sharedDPoPVerifier = dpop.NewVerifier(func(opt *dpop.VerifierOption) {
+			opt.ReplayCache = c.DPoPReplayCache
+			opt.BaseUrl = c.dpopProperties.BaseUrl
+			opt.TrustedProxies = c.serverProperties.TrustedProxies
+		})
+	}
+	return c.sharedDPoPVerifier
+}
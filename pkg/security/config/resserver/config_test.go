@@ -0,0 +1,90 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resserver
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"github.com/cisco-open/go-lanai/pkg/security/replay"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/sectest"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+/*************************
+	Tests
+ *************************/
+
+func TestJwkRotationConfiguration(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestRotationWithoutStorage(), "RotationWithoutStorage"),
+		test.GomegaSubTest(SubTestRotationWithStorage(), "RotationWithStorage"),
+		test.GomegaSubTest(SubTestRotationWithJwkStore(), "RotationWithJwkStore"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestRotationWithoutStorage() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		_, e := ProvideResServerDI(NewRotationTestDI(func(conf *Configuration) {}))
+		g.Expect(e).To(HaveOccurred(), "rotation without JwkStorage should fail")
+	}
+}
+
+func SubTestRotationWithStorage() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		out, e := ProvideResServerDI(NewRotationTestDI(func(conf *Configuration) {
+			conf.JwkStorage = jwt.NewInMemoryJwkStorage()
+		}))
+		g.Expect(e).To(Succeed(), "rotation with JwkStorage should not fail")
+		g.Expect(out.Config.jwkStore()).To(BeAssignableToTypeOf(&jwt.RotatingJwkStore{}), "JWK store should be rotating")
+	}
+}
+
+func SubTestRotationWithJwkStore() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		store := jwt.NewStaticJwkStore()
+		out, e := ProvideResServerDI(NewRotationTestDI(func(conf *Configuration) {
+			conf.JwkStore = store
+		}))
+		g.Expect(e).To(Succeed(), "rotation with JwkStore should not fail")
+		g.Expect(out.Config.jwkStore()).To(BeIdenticalTo(store), "configured JWK store should be used")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func NewRotationTestDI(configurer ResourceServerConfigurer) resServerConfigDI {
+	props := jwt.CryptoProperties{}
+	props.Jwt.KeyName = "test-key"
+	props.Jwt.Rotation.Enabled = true
+	return resServerConfigDI{
+		CryptoProperties: props,
+		Configurer: func(conf *Configuration) {
+			conf.TokenStoreReader = sectest.NewMockedTokenStoreReader(nil, nil)
+			conf.DPoPReplayCache = replay.NewInMemoryCache()
+			configurer(conf)
+		},
+	}
+}
@@ -21,6 +21,7 @@ import (
     appconfig "github.com/cisco-open/go-lanai/pkg/appconfig/init"
     "github.com/cisco-open/go-lanai/pkg/bootstrap"
     "github.com/cisco-open/go-lanai/pkg/security"
+    "github.com/cisco-open/go-lanai/pkg/security/oauth2/dpop"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2/timeoutsupport"
     "go.uber.org/fx"
@@ -36,6 +37,7 @@ var Module = &bootstrap.Module{
 	Options: []fx.Option{
 		appconfig.FxEmbeddedDefaults(defaultConfigFS),
 		fx.Provide(jwt.BindCryptoProperties),
+		fx.Provide(dpop.BindDPoPProperties),
 		fx.Provide(ProvideResServerDI),
 		fx.Invoke(ConfigureResourceServer),
 	},
@@ -70,6 +70,8 @@ const (
 	AuthMethodPassword       = "Password"
 	AuthMethodExternalSaml   = "ExtSAML"
 	AuthMethodExternalOpenID = "ExtOpenID"
+	AuthMethodWebAuthn       = "WebAuthn"
+	AuthMethodX509           = "X509"
 )
 
 const (
@@ -94,6 +96,7 @@ const (
 	MWOrderFormLogout
 	MWOrderFormAuth
 	MWOrderOAuth2TokenAuth
+	MWOrderX509Auth
 	// ... more MW goes here
 	MWOrderAccessControl     = LowestMiddlewareOrder - 200
 	MWOrderOAuth2Endpoints   = MWOrderAccessControl + 100
@@ -108,6 +111,8 @@ const (
 	FeatureOrderBasicAuth
 	FeatureOrderFormLogin
 	FeatureOrderSamlLogin
+	FeatureOrderOidcLogin
+	FeatureOrderWebAuthn
 	FeatureOrderSamlLogout
 	FeatureOrderLogout
 	FeatureOrderOAuth2TokenEndpoint
@@ -118,6 +123,7 @@ const (
 	FeatureOrderAccess
 	FeatureOrderSession
 	FeatureOrderRequestCache
+	FeatureOrderX509Auth
 	// ... more Feature goes here
 	FeatureOrderErrorHandling = order.Lowest - 200
 )
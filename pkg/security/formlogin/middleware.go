@@ -82,6 +82,7 @@ func (mw *FormAuthenticationMiddleware) LoginProcessHandlerFunc() gin.HandlerFun
 			Password: password[0],
 			EnforceMFA: passwd.MFAModeOptional,
 		}
+		security.SetAuditCandidate(ctx, &candidate)
 		// Authenticate
 		auth, err := mw.authenticator.Authenticate(ctx, &candidate)
 		if err != nil {
@@ -35,6 +35,12 @@ const (
 	LoginModelKeyMfaVerifyUrl       = "mfaVerifyUrl"
 	LoginModelKeyMfaRefreshUrl      = "mfaRefreshUrl"
 	LoginModelKeyMsxVersion         = "MSXVersion"
+
+	LoginModelKeyWebAuthnLoginOptionsUrl = "webauthnLoginOptionsUrl"
+	LoginModelKeyWebAuthnLoginUrl        = "webauthnLoginUrl"
+	LoginModelKeyWebAuthnMfaOptionsUrl   = "webauthnMfaOptionsUrl"
+	LoginModelKeyWebAuthnMfaUrl          = "webauthnMfaUrl"
+	LoginModelKeyWebAuthnCredentialParam = "webauthnCredentialParam"
 )
 
 type DefaultFormLoginController struct {
@@ -48,6 +54,12 @@ type DefaultFormLoginController struct {
 	mfaVerifyUrl  string
 	mfaRefreshUrl string
 	otpParam      string
+
+	webAuthnLoginOptionsUrl string
+	webAuthnLoginUrl        string
+	webAuthnMfaOptionsUrl   string
+	webAuthnMfaUrl          string
+	webAuthnCredentialParam string
 }
 
 type PageOptionsFunc func(*DefaultFormLoginPageOptions)
@@ -63,6 +75,13 @@ type DefaultFormLoginPageOptions struct {
 	OtpParam      string
 	MfaVerifyUrl  string
 	MfaRefreshUrl string
+
+	// WebAuthn URLs are optional. When set, login pages would offer passkey / security key sign-in
+	WebAuthnLoginOptionsUrl string
+	WebAuthnLoginUrl        string
+	WebAuthnMfaOptionsUrl   string
+	WebAuthnMfaUrl          string
+	WebAuthnCredentialParam string
 }
 
 func NewDefaultLoginFormController(options ...PageOptionsFunc) *DefaultFormLoginController {
@@ -82,6 +101,12 @@ func NewDefaultLoginFormController(options ...PageOptionsFunc) *DefaultFormLogin
 		mfaVerifyUrl:  opts.MfaVerifyUrl,
 		mfaRefreshUrl: opts.MfaRefreshUrl,
 		otpParam:      opts.OtpParam,
+
+		webAuthnLoginOptionsUrl: opts.WebAuthnLoginOptionsUrl,
+		webAuthnLoginUrl:        opts.WebAuthnLoginUrl,
+		webAuthnMfaOptionsUrl:   opts.WebAuthnMfaOptionsUrl,
+		webAuthnMfaUrl:          opts.WebAuthnMfaUrl,
+		webAuthnCredentialParam: opts.WebAuthnCredentialParam,
 	}
 }
 
@@ -107,6 +132,11 @@ func (c *DefaultFormLoginController) LoginForm(ctx context.Context, r *LoginRequ
 		LoginModelKeyLoginProcessUrl: c.loginProcessUrl,
 		LoginModelKeyMsxVersion:      c.msxVersion(),
 	}
+	if c.webAuthnLoginUrl != "" {
+		model[LoginModelKeyWebAuthnLoginOptionsUrl] = c.webAuthnLoginOptionsUrl
+		model[LoginModelKeyWebAuthnLoginUrl] = c.webAuthnLoginUrl
+		model[LoginModelKeyWebAuthnCredentialParam] = c.webAuthnCredentialParam
+	}
 
 	s := session.Get(ctx)
 	if s != nil {
@@ -138,6 +168,11 @@ func (c *DefaultFormLoginController) OtpVerificationForm(ctx context.Context, r
 		LoginModelKeyMfaRefreshUrl: c.mfaRefreshUrl,
 		LoginModelKeyMsxVersion:    c.msxVersion(),
 	}
+	if c.webAuthnMfaUrl != "" {
+		model[LoginModelKeyWebAuthnMfaOptionsUrl] = c.webAuthnMfaOptionsUrl
+		model[LoginModelKeyWebAuthnMfaUrl] = c.webAuthnMfaUrl
+		model[LoginModelKeyWebAuthnCredentialParam] = c.webAuthnCredentialParam
+	}
 
 	s := session.Get(ctx)
 	if s != nil {
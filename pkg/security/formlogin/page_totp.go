@@ -0,0 +1,168 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package formlogin
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/passwd"
+	"github.com/cisco-open/go-lanai/pkg/security/redirect"
+	"github.com/cisco-open/go-lanai/pkg/security/session"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"github.com/cisco-open/go-lanai/pkg/web/template"
+	htmltemplate "html/template"
+	"net/http"
+)
+
+const (
+	TOTPModelKeyEnrollUrl     = "totpEnrollUrl"
+	TOTPModelKeyEnrolled      = "totpEnrolled"
+	TOTPModelKeySecret        = "totpSecret"
+	TOTPModelKeyURI           = "totpUri"
+	TOTPModelKeyQRCode        = "totpQrCode"
+	TOTPModelKeyRecoveryCodes = "totpRecoveryCodes"
+)
+
+const (
+	TOTPActionConfirm    = "confirm"
+	TOTPActionRegenerate = "regenerate"
+	TOTPActionDisable    = "disable"
+)
+
+// DefaultTOTPEnrollmentController serves authenticator app enrollment page for authenticated users.
+// No page is served if Manager is nil, i.e. authenticator app is disabled
+type DefaultTOTPEnrollmentController struct {
+	manager   *passwd.TOTPAppManager
+	template  string
+	enrollUrl string
+}
+
+type TOTPEnrollmentPageOptionsFunc func(*TOTPEnrollmentPageOptions)
+
+type TOTPEnrollmentPageOptions struct {
+	Manager   *passwd.TOTPAppManager
+	Template  string
+	EnrollUrl string
+}
+
+func NewDefaultTOTPEnrollmentController(options ...TOTPEnrollmentPageOptionsFunc) *DefaultTOTPEnrollmentController {
+	opts := TOTPEnrollmentPageOptions{}
+	for _, f := range options {
+		f(&opts)
+	}
+	return &DefaultTOTPEnrollmentController{
+		manager:   opts.Manager,
+		template:  opts.Template,
+		enrollUrl: opts.EnrollUrl,
+	}
+}
+
+type TOTPEnrollmentRequest struct {
+	Error    bool   `form:"error"`
+	Action   string `form:"action"`
+	Passcode string `form:"passcode"`
+}
+
+func (c *DefaultTOTPEnrollmentController) Mappings() []web.Mapping {
+	if c.manager == nil {
+		return nil
+	}
+	return []web.Mapping{
+		template.New().Get(c.enrollUrl).HandlerFunc(c.EnrollmentForm).Build(),
+		template.New().Post(c.enrollUrl).HandlerFunc(c.ProcessEnrollment).Build(),
+	}
+}
+
+func (c *DefaultTOTPEnrollmentController) EnrollmentForm(ctx context.Context, r *TOTPEnrollmentRequest) (*template.ModelView, error) {
+	acct, e := c.currentAccount(ctx)
+	if e != nil {
+		return nil, e
+	}
+
+	model := template.Model{
+		TOTPModelKeyEnrollUrl: c.enrollUrl,
+		TOTPModelKeyEnrolled:  c.manager.IsEnrolled(ctx, acct.Username()),
+	}
+	if s := session.Get(ctx); s != nil {
+		if err, ok := s.Flash(redirect.FlashKeyPreviousError).(error); ok && r.Error {
+			model[template.ModelKeyError] = err
+		}
+	}
+
+	if enrolled, _ := model[TOTPModelKeyEnrolled].(bool); !enrolled {
+		provisioning, e := c.manager.BeginEnrollment(ctx, acct)
+		if e != nil {
+			return nil, e
+		}
+		model[TOTPModelKeySecret] = provisioning.Secret
+		model[TOTPModelKeyURI] = provisioning.URI
+		// QR code is generated by us as "data:" URL, which would otherwise be escaped by html/template
+		model[TOTPModelKeyQRCode] = htmltemplate.URL(provisioning.QRCode)
+	}
+	return &template.ModelView{
+		View:  c.template,
+		Model: model,
+	}, nil
+}
+
+func (c *DefaultTOTPEnrollmentController) ProcessEnrollment(ctx context.Context, r *TOTPEnrollmentRequest) (*template.ModelView, error) {
+	acct, e := c.currentAccount(ctx)
+	if e != nil {
+		return nil, e
+	}
+
+	var codes []string
+	switch r.Action {
+	case TOTPActionConfirm:
+		codes, e = c.manager.ConfirmEnrollment(ctx, acct, r.Passcode)
+	case TOTPActionRegenerate:
+		codes, e = c.manager.RegenerateRecoveryCodes(ctx, acct)
+	case TOTPActionDisable:
+		e = c.manager.Disable(ctx, acct, r.Passcode)
+	default:
+		e = errors.New("unsupported action")
+	}
+
+	switch {
+	case e != nil:
+		if s := session.Get(ctx); s != nil {
+			// flatten the error, so it can always be serialized in session
+			s.AddFlash(errors.New(e.Error()), redirect.FlashKeyPreviousError)
+		}
+		return template.RedirectView(c.enrollUrl+"?error=true", http.StatusFound, false), nil
+	case len(codes) == 0:
+		return template.RedirectView(c.enrollUrl, http.StatusFound, false), nil
+	}
+
+	return &template.ModelView{
+		View: c.template,
+		Model: template.Model{
+			TOTPModelKeyEnrollUrl:     c.enrollUrl,
+			TOTPModelKeyEnrolled:      true,
+			TOTPModelKeyRecoveryCodes: codes,
+		},
+	}, nil
+}
+
+func (c *DefaultTOTPEnrollmentController) currentAccount(ctx context.Context) (security.Account, error) {
+	auth := security.Get(ctx)
+	if acct, ok := auth.Principal().(security.Account); ok && security.IsFullyAuthenticated(auth) {
+		return acct, nil
+	}
+	return nil, security.NewInsufficientAuthError("authenticator app enrollment requires authenticated user")
+}
@@ -32,6 +32,7 @@ var logger = log.New("SEC.IDP")
 const (
 	InternalIdpForm = AuthenticationFlow("InternalIdpForm")
 	ExternalIdpSAML = AuthenticationFlow("ExternalIdpSAML")
+	ExternalIdpOIDC = AuthenticationFlow("ExternalIdpOIDC")
 	UnknownIdp      = AuthenticationFlow("UnKnown")
 )
 
@@ -50,6 +51,8 @@ func (f *AuthenticationFlow) UnmarshalText(data []byte) error {
 		*f = InternalIdpForm
 	case string(ExternalIdpSAML):
 		*f = ExternalIdpSAML
+	case string(ExternalIdpOIDC):
+		*f = ExternalIdpOIDC
 	default:
 		return fmt.Errorf("unrecognized authentication flow: %s", value)
 	}
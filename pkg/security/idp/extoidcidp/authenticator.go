@@ -0,0 +1,191 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package extoidcidp
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"time"
+)
+
+// IdTokenCandidate implements security.Candidate. Claims should be verified before authentication
+type IdTokenCandidate struct {
+	Provider   OpenIDIdentityProvider
+	IdToken    string
+	Claims     oauth2.MapClaims
+	DetailsMap map[string]interface{}
+}
+
+func (c *IdTokenCandidate) Principal() interface{} {
+	v, _ := c.Claims.Get(c.Provider.ExternalIdName()).(string)
+	return v
+}
+
+func (c *IdTokenCandidate) Credentials() interface{} {
+	return c.IdToken
+}
+
+func (c *IdTokenCandidate) Details() interface{} {
+	return c.DetailsMap
+}
+
+type OidcAuthentication interface {
+	security.Authentication
+	IdToken() string
+}
+
+type oidcAuthentication struct {
+	Account    security.Account
+	Perms      map[string]interface{}
+	DetailsMap map[string]interface{}
+	RawIdToken string
+}
+
+func (a *oidcAuthentication) Principal() interface{} {
+	return a.Account
+}
+
+func (a *oidcAuthentication) Permissions() security.Permissions {
+	return a.Perms
+}
+
+func (a *oidcAuthentication) State() security.AuthenticationState {
+	return security.StateAuthenticated
+}
+
+func (a *oidcAuthentication) Details() interface{} {
+	return a.DetailsMap
+}
+
+func (a *oidcAuthentication) IdToken() string {
+	return a.RawIdToken
+}
+
+// Authenticator implements security.Authenticator for IdTokenCandidate.
+// The ID token claims are passed to security.FederatedAccountStore as raw assertion for claim mapping
+type Authenticator struct {
+	accountStore security.FederatedAccountStore
+}
+
+func (a *Authenticator) Authenticate(ctx context.Context, candidate security.Candidate) (security.Authentication, error) {
+	idTokenCandidate, ok := candidate.(*IdTokenCandidate)
+	if !ok {
+		return nil, nil
+	}
+
+	p := idTokenCandidate.Provider
+	externalId, _ := idTokenCandidate.Principal().(string)
+	if externalId == "" {
+		return nil, security.NewInternalAuthenticationError(fmt.Sprintf("ID token doesn't have claim [%s]", p.ExternalIdName()))
+	}
+
+	user, err := a.accountStore.LoadAccountByExternalId(ctx, p.ExternalIdName(), externalId, p.ExternalIdpName(), p.GetAutoCreateUserDetails(), idTokenCandidate.Claims)
+	if err != nil {
+		return nil, security.NewInternalAuthenticationError(err)
+	}
+
+	if user.Disabled() {
+		return nil, security.NewAccountStatusError("Account Disabled")
+	}
+
+	permissions := map[string]interface{}{}
+	for _, p := range user.Permissions() {
+		permissions[p] = true
+	}
+
+	details := idTokenCandidate.DetailsMap
+	if details == nil {
+		details = make(map[string]interface{})
+	}
+	authTime := claimTime(idTokenCandidate.Claims, oauth2.ClaimAuthTime)
+	if authTime.IsZero() {
+		authTime = claimTime(idTokenCandidate.Claims, oauth2.ClaimIssueAt)
+	}
+	details[security.DetailsKeyAuthTime] = authTime
+	details[security.DetailsKeyAuthMethod] = security.AuthMethodExternalOpenID
+
+	return &oidcAuthentication{
+		Account:    user,
+		Perms:      permissions,
+		DetailsMap: details,
+		RawIdToken: idTokenCandidate.IdToken,
+	}, nil
+}
+
+// validateIdTokenClaims performs ID token validation required by OpenID Connect Core 1.0 Section 3.1.3.7
+func validateIdTokenClaims(claims oauth2.MapClaims, issuer, clientId, nonce string, now time.Time) error {
+	if iss, _ := claims.Get(oauth2.ClaimIssuer).(string); iss != issuer {
+		return fmt.Errorf("unexpected issuer [%s]", iss)
+	}
+
+	aud := claimStrings(claims, oauth2.ClaimAudience)
+	var audOk bool
+	for _, v := range aud {
+		audOk = audOk || v == clientId
+	}
+	if !audOk {
+		return fmt.Errorf("ID token is not issued to [%s]", clientId)
+	}
+	if azp, ok := claims.Get(oauth2.ClaimAuthorizedParty).(string); (len(aud) > 1 || ok) && azp != clientId {
+		return fmt.Errorf("unexpected authorized party [%s]", azp)
+	}
+
+	if exp := claimTime(claims, oauth2.ClaimExpire); exp.IsZero() || !now.Before(exp.Add(clockSkew)) {
+		return fmt.Errorf("ID token expired")
+	}
+
+	if v, _ := claims.Get(oauth2.ClaimNonce).(string); v != nonce {
+		return fmt.Errorf("ID token nonce mismatch")
+	}
+	return nil
+}
+
+func claimStrings(claims oauth2.MapClaims, name string) []string {
+	switch v := claims.Get(name).(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		ret := make([]string, 0, len(v))
+		for _, elem := range v {
+			if s, ok := elem.(string); ok {
+				ret = append(ret, s)
+			}
+		}
+		return ret
+	default:
+		return nil
+	}
+}
+
+func claimTime(claims oauth2.MapClaims, name string) time.Time {
+	switch v := claims.Get(name).(type) {
+	case time.Time:
+		return v
+	case int:
+		return time.Unix(int64(v), 0)
+	case int64:
+		return time.Unix(v, 0)
+	case float64:
+		return time.Unix(int64(v), 0)
+	default:
+		return time.Time{}
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package extoidcidp
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/test"
+	. "github.com/onsi/gomega"
+	"testing"
+	"time"
+)
+
+/*************************
+	Test Cases
+ *************************/
+
+func TestAuthenticator(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestAuthenticateSuccess(), "AuthenticateSuccess"),
+		test.GomegaSubTest(SubTestAuthenticateUnsupportedCandidate(), "UnsupportedCandidate"),
+		test.GomegaSubTest(SubTestAuthenticateMissingExternalId(), "MissingExternalId"),
+		test.GomegaSubTest(SubTestAuthenticateAccountStoreError(), "AccountStoreError"),
+		test.GomegaSubTest(SubTestAuthenticateDisabledAccount(), "DisabledAccount"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestAuthenticateSuccess() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		store := NewMockedFederatedAccountStore()
+		authenticator := &Authenticator{accountStore: store}
+		authTime := time.Now().Add(-time.Minute).Truncate(time.Second)
+		candidate := NewTestIdTokenCandidate(oauth2.MapClaims{
+			oauth2.ClaimSubject:  TestExternalId,
+			oauth2.ClaimAuthTime: authTime.Unix(),
+			oauth2.ClaimIssueAt:  time.Now().Unix(),
+		})
+
+		auth, e := authenticator.Authenticate(ctx, candidate)
+		g.Expect(e).To(Succeed(), "authentication should succeed")
+		g.Expect(auth.State()).To(Equal(security.StateAuthenticated))
+		g.Expect(auth.Permissions()).To(HaveKeyWithValue("TEST_PERMISSION", true))
+		g.Expect(auth.(OidcAuthentication).IdToken()).To(Equal("test-id-token"))
+		details := auth.Details().(map[string]interface{})
+		g.Expect(details).To(HaveKeyWithValue(security.DetailsKeyAuthTime, authTime), "auth time should be taken from auth_time claim")
+		g.Expect(details).To(HaveKeyWithValue(security.DetailsKeyAuthMethod, security.AuthMethodExternalOpenID))
+		g.Expect(store.assertion).To(Equal(candidate.Claims), "claims should be passed to account store as raw assertion")
+	}
+}
+
+func SubTestAuthenticateUnsupportedCandidate() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		authenticator := &Authenticator{accountStore: NewMockedFederatedAccountStore()}
+		auth, e := authenticator.Authenticate(ctx, &security.AnonymousCandidate{})
+		g.Expect(e).To(Succeed())
+		g.Expect(auth).To(BeNil(), "other candidates should be ignored")
+	}
+}
+
+func SubTestAuthenticateMissingExternalId() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		authenticator := &Authenticator{accountStore: NewMockedFederatedAccountStore()}
+		_, e := authenticator.Authenticate(ctx, NewTestIdTokenCandidate(oauth2.MapClaims{}))
+		g.Expect(e).To(HaveOccurred(), "ID token without external ID should fail")
+		g.Expect(errors.Is(e, security.ErrorSubTypeInternalError)).To(BeTrue())
+	}
+}
+
+func SubTestAuthenticateAccountStoreError() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		store := NewMockedFederatedAccountStore()
+		store.err = errors.New("oops")
+		authenticator := &Authenticator{accountStore: store}
+		_, e := authenticator.Authenticate(ctx, NewTestIdTokenCandidate(oauth2.MapClaims{oauth2.ClaimSubject: TestExternalId}))
+		g.Expect(e).To(HaveOccurred(), "account store error should fail authentication")
+		g.Expect(errors.Is(e, security.ErrorSubTypeInternalError)).To(BeTrue())
+	}
+}
+
+func SubTestAuthenticateDisabledAccount() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		store := NewMockedFederatedAccountStore()
+		store.account.AcctDetails.Disabled = true
+		authenticator := &Authenticator{accountStore: store}
+		_, e := authenticator.Authenticate(ctx, NewTestIdTokenCandidate(oauth2.MapClaims{oauth2.ClaimSubject: TestExternalId}))
+		g.Expect(e).To(HaveOccurred(), "disabled account should fail authentication")
+		var coded *security.CodedError
+		g.Expect(errors.As(e, &coded)).To(BeTrue())
+		g.Expect(coded.Code()).To(BeEquivalentTo(security.ErrorCodeAccountStatus))
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+// MockedFederatedAccountStore returns the same account for any external ID
+type MockedFederatedAccountStore struct {
+	account   *security.DefaultAccount
+	assertion interface{}
+	err       error
+}
+
+func NewMockedFederatedAccountStore() *MockedFederatedAccountStore {
+	return &MockedFederatedAccountStore{
+		account: security.NewUsernamePasswordAccount(&security.AcctDetails{
+			ID:          "test-user-id",
+			Username:    TestExternalId,
+			Permissions: []string{"TEST_PERMISSION"},
+		}),
+	}
+}
+
+func (s *MockedFederatedAccountStore) LoadAccountByExternalId(_ context.Context, _ string, _ string, _ string, _ security.AutoCreateUserDetails, rawAssertion interface{}) (security.Account, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.assertion = rawAssertion
+	return s.account, nil
+}
+
+func NewTestIdTokenCandidate(claims oauth2.MapClaims) *IdTokenCandidate {
+	return &IdTokenCandidate{
+		Provider: NewIdentityProvider(func(opt *OidcIdpDetails) {
+			opt.Domain = "localhost"
+			opt.Issuer = "http://localhost/test-op"
+			opt.ClientId = TestClientId
+			opt.ExternalIdName = oauth2.ClaimSubject
+		}),
+		IdToken: "test-id-token",
+		Claims:  claims,
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package extoidcidp
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/access"
+	"github.com/cisco-open/go-lanai/pkg/security/config/authserver"
+	"github.com/cisco-open/go-lanai/pkg/security/errorhandling"
+	"github.com/cisco-open/go-lanai/pkg/security/idp"
+	"github.com/cisco-open/go-lanai/pkg/security/redirect"
+	"github.com/cisco-open/go-lanai/pkg/security/session"
+	"github.com/cisco-open/go-lanai/pkg/web/matcher"
+)
+
+type Options func(opt *option)
+type option struct {
+	Properties *OidcAuthProperties
+}
+
+func WithProperties(props *OidcAuthProperties) Options {
+	return func(opt *option) {
+		opt.Properties = props
+	}
+}
+
+// OidcIdpSecurityConfigurer implements authserver.IdpSecurityConfigurer
+//
+//goland:noinspection GoNameStartsWithPackageName
+type OidcIdpSecurityConfigurer struct {
+	props *OidcAuthProperties
+}
+
+func NewOidcIdpSecurityConfigurer(opts ...Options) *OidcIdpSecurityConfigurer {
+	opt := option{
+		Properties: NewOidcAuthProperties(),
+	}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	return &OidcIdpSecurityConfigurer{
+		props: opt.Properties,
+	}
+}
+
+func (c *OidcIdpSecurityConfigurer) Configure(ws security.WebSecurity, config *authserver.Configuration) {
+	// For Authorize endpoint
+	condition := idp.RequestWithAuthenticationFlow(idp.ExternalIdpOIDC, config.IdpManager)
+	ws = ws.AndCondition(condition)
+
+	if !c.props.Enabled {
+		return
+	}
+
+	handler := redirect.NewRedirectWithURL(config.Endpoints.Error)
+	ws.
+		With(New().
+			Issuer(config.Issuer).
+			CallbackPath(c.props.Endpoints.Callback).
+			ErrorPath(config.Endpoints.Error),
+		).
+		With(session.New().SettingService(config.SessionSettingService)).
+		With(access.New().
+			Request(matcher.AnyRequest()).Authenticated(),
+		).
+		With(errorhandling.New().
+			AccessDeniedHandler(handler),
+		)
+}
+
+func (c *OidcIdpSecurityConfigurer) ConfigureLogout(_ security.WebSecurity, _ *authserver.Configuration) {
+	// RP-initiated logout with external OpenID providers is not supported. Local logout is handled by authserver.
+}
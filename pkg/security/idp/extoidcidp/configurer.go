@@ -0,0 +1,87 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package extoidcidp
+
+import (
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/access"
+	"github.com/cisco-open/go-lanai/pkg/security/errorhandling"
+	"github.com/cisco-open/go-lanai/pkg/security/idp"
+	"github.com/cisco-open/go-lanai/pkg/security/redirect"
+	"github.com/cisco-open/go-lanai/pkg/security/request_cache"
+	"github.com/cisco-open/go-lanai/pkg/utils/order"
+	"github.com/cisco-open/go-lanai/pkg/web/mapping"
+	"github.com/cisco-open/go-lanai/pkg/web/matcher"
+)
+
+type OidcLoginConfigurer struct {
+	idpManager    idp.IdentityProviderManager
+	accountStore  security.FederatedAccountStore
+	clientManager *ProviderClientManager
+}
+
+func newOidcLoginConfigurer(idpManager idp.IdentityProviderManager, accountStore security.FederatedAccountStore, clientManager *ProviderClientManager) *OidcLoginConfigurer {
+	return &OidcLoginConfigurer{
+		idpManager:    idpManager,
+		accountStore:  accountStore,
+		clientManager: clientManager,
+	}
+}
+
+func (c *OidcLoginConfigurer) Apply(feature security.Feature, ws security.WebSecurity) error {
+	f := feature.(*Feature)
+	if f.issuer == nil {
+		return fmt.Errorf("issuer is required for %v", FeatureId)
+	}
+
+	m := c.makeMiddleware(f, ws)
+
+	ws.Route(matcher.RouteWithPattern(f.callbackPath)).
+		Add(mapping.Get(f.callbackPath).
+			HandlerFunc(m.CallbackHandlerFunc()).
+			Name("oidc login callback"))
+
+	access.Configure(ws).
+		Request(matcher.RequestWithPattern(f.callbackPath)).WithOrder(order.Highest).PermitAll()
+
+	//authentication entry point
+	errorhandling.Configure(ws).
+		AuthenticationEntryPoint(request_cache.NewSaveRequestEntryPoint(m))
+	return nil
+}
+
+func (c *OidcLoginConfigurer) makeMiddleware(f *Feature, ws security.WebSecurity) *OidcLoginMiddleware {
+	if f.successHandler == nil {
+		f.successHandler = request_cache.NewSavedRequestAuthenticationSuccessHandler(
+			redirect.NewRedirectWithRelativePath("/", true), nil,
+		)
+	}
+	authenticator := &Authenticator{
+		accountStore: c.accountStore,
+	}
+	return NewLoginMiddleware(f.issuer, f.callbackPath, c.idpManager, c.clientManager,
+		c.effectiveSuccessHandler(f, ws), authenticator, f.errorPath)
+}
+
+func (c *OidcLoginConfigurer) effectiveSuccessHandler(f *Feature, ws security.WebSecurity) security.AuthenticationSuccessHandler {
+	if globalHandler, ok := ws.Shared(security.WSSharedKeyCompositeAuthSuccessHandler).(security.AuthenticationSuccessHandler); ok {
+		return security.NewAuthenticationSuccessHandler(globalHandler, f.successHandler)
+	} else {
+		return security.NewAuthenticationSuccessHandler(f.successHandler)
+	}
+}
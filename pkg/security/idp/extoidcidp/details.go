@@ -0,0 +1,133 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package extoidcidp
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/idp"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+)
+
+// OpenIDIdentityProvider is the external OpenID provider information required by the relying party login flow
+type OpenIDIdentityProvider interface {
+	idp.IdentityProvider
+	// Issuer is the OpenID provider's issuer identifier. Provider configuration is discovered from
+	// "<issuer>/.well-known/openid-configuration"
+	Issuer() string
+	ClientId() string
+	ClientSecret() string
+	Scopes() []string
+	// ExternalIdName is the ID token claim used as external ID, e.g. "sub", "email", "preferred_username"
+	ExternalIdName() string
+	ExternalIdpName() string
+	GetAutoCreateUserDetails() security.AutoCreateUserDetails
+}
+
+type OidcIdpAutoCreateUserDetails struct {
+	Enabled               bool
+	EmailWhiteList        []string
+	AttributeMapping      map[string]string
+	ElevatedUserRoleNames []string
+	RegularUserRoleNames  []string
+}
+
+func (a OidcIdpAutoCreateUserDetails) GetElevatedUserRoleNames() []string {
+	return a.ElevatedUserRoleNames
+}
+
+func (a OidcIdpAutoCreateUserDetails) GetRegularUserRoleNames() []string {
+	return a.RegularUserRoleNames
+}
+
+func (a OidcIdpAutoCreateUserDetails) IsEnabled() bool {
+	return a.Enabled
+}
+
+func (a OidcIdpAutoCreateUserDetails) GetEmailWhiteList() []string {
+	return a.EmailWhiteList
+}
+
+func (a OidcIdpAutoCreateUserDetails) GetAttributeMapping() map[string]string {
+	return a.AttributeMapping
+}
+
+type OidcIdpDetails struct {
+	Domain                string
+	Issuer                string
+	ClientId              string
+	ClientSecret          string
+	Scopes                []string
+	ExternalIdName        string
+	ExternalIdpName       string
+	AutoCreateUserDetails OidcIdpAutoCreateUserDetails
+}
+
+type OidcIdpOptions func(opt *OidcIdpDetails)
+
+// OidcIdentityProvider implements idp.IdentityProvider, idp.AuthenticationFlowAware and OpenIDIdentityProvider
+type OidcIdentityProvider struct {
+	OidcIdpDetails
+}
+
+func NewIdentityProvider(opts ...OidcIdpOptions) *OidcIdentityProvider {
+	opt := OidcIdpDetails{
+		Scopes:         []string{oauth2.ScopeOidc},
+		ExternalIdName: oauth2.ClaimSubject,
+	}
+	for _, f := range opts {
+		f(&opt)
+	}
+	return &OidcIdentityProvider{
+		OidcIdpDetails: opt,
+	}
+}
+
+func (p OidcIdentityProvider) AuthenticationFlow() idp.AuthenticationFlow {
+	return idp.ExternalIdpOIDC
+}
+
+func (p OidcIdentityProvider) Domain() string {
+	return p.OidcIdpDetails.Domain
+}
+
+func (p OidcIdentityProvider) Issuer() string {
+	return p.OidcIdpDetails.Issuer
+}
+
+func (p OidcIdentityProvider) ClientId() string {
+	return p.OidcIdpDetails.ClientId
+}
+
+func (p OidcIdentityProvider) ClientSecret() string {
+	return p.OidcIdpDetails.ClientSecret
+}
+
+func (p OidcIdentityProvider) Scopes() []string {
+	return p.OidcIdpDetails.Scopes
+}
+
+func (p OidcIdentityProvider) ExternalIdName() string {
+	return p.OidcIdpDetails.ExternalIdName
+}
+
+func (p OidcIdentityProvider) ExternalIdpName() string {
+	return p.OidcIdpDetails.ExternalIdpName
+}
+
+func (p OidcIdentityProvider) GetAutoCreateUserDetails() security.AutoCreateUserDetails {
+	return p.OidcIdpDetails.AutoCreateUserDetails
+}
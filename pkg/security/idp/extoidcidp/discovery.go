@@ -0,0 +1,209 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package extoidcidp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"golang.org/x/sync/singleflight"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+)
+
+// ProviderMetadata is the subset of OpenID Provider Metadata used by the relying party.
+// See https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type ProviderMetadata struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JwksUri               string   `json:"jwks_uri"`
+	UserInfoEndpoint      string   `json:"userinfo_endpoint,omitempty"`
+	CodeChallengeMethods  []string `json:"code_challenge_methods_supported,omitempty"`
+}
+
+// TokenResponse is the subset of token endpoint response used by the relying party
+type TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	IdToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// ProviderClient interacts with a single external OpenID provider.
+type ProviderClient struct {
+	Metadata   ProviderMetadata
+	httpClient *http.Client
+	decoder    jwt.JwtDecoder
+}
+
+// ExchangeCode redeem authorization code at the provider's token endpoint using "client_secret_basic" and PKCE code verifier
+func (c *ProviderClient) ExchangeCode(ctx context.Context, p OpenIDIdentityProvider, code, redirectUri, verifier string) (*TokenResponse, error) {
+	form := url.Values{}
+	form.Set(oauth2.ParameterGrantType, oauth2.GrantTypeAuthCode)
+	form.Set(oauth2.ParameterAuthCode, code)
+	form.Set(oauth2.ParameterRedirectUri, redirectUri)
+	form.Set(oauth2.ParameterCodeVerifier, verifier)
+	req, e := http.NewRequestWithContext(ctx, http.MethodPost, c.Metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if e != nil {
+		return nil, e
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.ClientId()), url.QueryEscape(p.ClientSecret()))
+
+	resp, e := c.httpClient.Do(req)
+	if e != nil {
+		return nil, e
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var token TokenResponse
+	if e := json.NewDecoder(resp.Body).Decode(&token); e != nil {
+		return nil, fmt.Errorf("unable to parse token response: %v", e)
+	}
+	switch {
+	case token.Error != "":
+		return nil, fmt.Errorf("token request failed: %s - %s", token.Error, token.ErrorDescription)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("token request failed with status %d", resp.StatusCode)
+	case token.IdToken == "":
+		return nil, fmt.Errorf("token response doesn't contain id_token")
+	}
+	return &token, nil
+}
+
+// DecodeIdToken verify ID token's signature with provider's JWKS. Claims are not validated
+func (c *ProviderClient) DecodeIdToken(ctx context.Context, idToken string) (oauth2.MapClaims, error) {
+	claims := oauth2.MapClaims{}
+	if e := c.decoder.DecodeWithClaims(ctx, idToken, &claims); e != nil {
+		return nil, e
+	}
+	return claims, nil
+}
+
+// ProviderClientManager discovers and caches ProviderClient per issuer
+type ProviderClientManager struct {
+	httpClient  *http.Client
+	mtx         sync.RWMutex
+	clients     map[string]*ProviderClient
+	discovering singleflight.Group
+}
+
+// NewProviderClientManager create ProviderClientManager using given http.Client.
+// When httpClient is nil, a client with default timeout is used.
+func NewProviderClientManager(httpClient *http.Client) *ProviderClientManager {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultHttpTimeout}
+	}
+	return &ProviderClientManager{
+		httpClient: httpClient,
+		clients:    map[string]*ProviderClient{},
+	}
+}
+
+// GetClient returns ProviderClient of given issuer. Provider configuration is discovered on first use.
+// Concurrent discoveries of same issuer are de-duplicated and don't block other issuers. Failed discoveries are not cached.
+func (m *ProviderClientManager) GetClient(ctx context.Context, issuer string) (*ProviderClient, error) {
+	m.mtx.RLock()
+	client, ok := m.clients[issuer]
+	m.mtx.RUnlock()
+	if ok {
+		return client, nil
+	}
+
+	v, e, _ := m.discovering.Do(issuer, func() (interface{}, error) {
+		metadata, e := m.discover(ctx, issuer)
+		if e != nil {
+			return nil, e
+		}
+		store := providerJwkStore{RemoteJwkStore: jwt.NewRemoteJwkStore(func(cfg *jwt.RemoteJwkConfig) {
+			cfg.HttpClient = m.httpClient
+			cfg.JwkSetURL = metadata.JwksUri
+		})}
+		client := &ProviderClient{
+			Metadata:   *metadata,
+			httpClient: m.httpClient,
+			decoder:    jwt.NewSignedJwtDecoder(jwt.VerifyWithJwkStore(store, "")),
+		}
+		m.mtx.Lock()
+		defer m.mtx.Unlock()
+		m.clients[issuer] = client
+		return client, nil
+	})
+	if e != nil {
+		return nil, e
+	}
+	return v.(*ProviderClient), nil
+}
+
+func (m *ProviderClientManager) discover(ctx context.Context, issuer string) (*ProviderMetadata, error) {
+	req, e := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(issuer, "/")+discoveryPath, nil)
+	if e != nil {
+		return nil, e
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, e := m.httpClient.Do(req)
+	if e != nil {
+		return nil, fmt.Errorf("unable to fetch OpenID provider configuration of [%s]: %v", issuer, e)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch OpenID provider configuration of [%s]: status %d", issuer, resp.StatusCode)
+	}
+
+	var metadata ProviderMetadata
+	if e := json.NewDecoder(resp.Body).Decode(&metadata); e != nil {
+		return nil, fmt.Errorf("invalid OpenID provider configuration of [%s]: %v", issuer, e)
+	}
+	switch {
+	case metadata.Issuer != issuer:
+		return nil, fmt.Errorf("OpenID provider configuration issuer mismatch: expected [%s] but got [%s]", issuer, metadata.Issuer)
+	case metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JwksUri == "":
+		return nil, fmt.Errorf("OpenID provider configuration of [%s] is missing required endpoints", issuer)
+	}
+	return &metadata, nil
+}
+
+// providerJwkStore wraps jwt.RemoteJwkStore to support ID tokens without "kid" header.
+// Per OpenID Connect Core 1.0 Section 10.1, "kid" is only required when the provider's JWK set has multiple keys.
+type providerJwkStore struct {
+	*jwt.RemoteJwkStore
+}
+
+func (s providerJwkStore) LoadByName(ctx context.Context, name string) (jwt.Jwk, error) {
+	if name != "" {
+		return s.RemoteJwkStore.LoadByName(ctx, name)
+	}
+	jwks, e := s.RemoteJwkStore.LoadAll(ctx)
+	switch {
+	case e != nil:
+		return nil, e
+	case len(jwks) != 1:
+		return nil, fmt.Errorf("ID token without kid requires exactly one key in provider's JWK set, but got %d", len(jwks))
+	}
+	return jwks[0], nil
+}
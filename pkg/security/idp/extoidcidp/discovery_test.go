@@ -0,0 +1,200 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package extoidcidp
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"github.com/cisco-open/go-lanai/test"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	TestKid          = "test-kid"
+	TestClientId     = "test-client"
+	TestClientSecret = "test-secret"
+	TestCode         = "test-code"
+	TestVerifier     = "test-verifier"
+	TestNonce        = "test-nonce"
+	TestExternalId   = "test-user"
+)
+
+/*************************
+	Test Cases
+ *************************/
+
+func TestProviderClient(t *testing.T) {
+	op := NewTestOpenIDProvider()
+	defer op.Close()
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestSuccessfulCodeExchange(op), "SuccessfulCodeExchange"),
+		test.GomegaSubTest(SubTestInvalidClaims(op), "InvalidClaims"),
+		test.GomegaSubTest(SubTestConcurrentDiscovery(op), "ConcurrentDiscovery"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestSuccessfulCodeExchange(op *TestOpenIDProvider) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		p := op.IdentityProvider()
+		client, e := NewProviderClientManager(nil).GetClient(ctx, p.Issuer())
+		g.Expect(e).To(Succeed(), "discovery should not fail")
+		g.Expect(client.Metadata.TokenEndpoint).To(Equal(op.URL+"/token"), "token endpoint should be discovered")
+
+		token, e := client.ExchangeCode(ctx, p, TestCode, "http://localhost/oidc/callback", TestVerifier)
+		g.Expect(e).To(Succeed(), "code exchange should not fail")
+		claims, e := client.DecodeIdToken(ctx, token.IdToken)
+		g.Expect(e).To(Succeed(), "ID token should be verified with remote JWKS")
+		e = validateIdTokenClaims(claims, p.Issuer(), TestClientId, TestNonce, time.Now())
+		g.Expect(e).To(Succeed(), "ID token claims should be valid")
+
+		candidate := &IdTokenCandidate{Provider: p, IdToken: token.IdToken, Claims: claims}
+		g.Expect(candidate.Principal()).To(Equal(TestExternalId), "principal should be mapped from ExternalIdName claim")
+	}
+}
+
+func SubTestInvalidClaims(op *TestOpenIDProvider) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		now := time.Now()
+		valid := op.claims(now)
+		var e error
+		e = validateIdTokenClaims(valid, op.URL, TestClientId, "another-nonce", now)
+		g.Expect(e).To(HaveOccurred(), "nonce mismatch should fail")
+		e = validateIdTokenClaims(valid, op.URL, "another-client", TestNonce, now)
+		g.Expect(e).To(HaveOccurred(), "audience mismatch should fail")
+		e = validateIdTokenClaims(valid, "http://another.issuer", TestClientId, TestNonce, now)
+		g.Expect(e).To(HaveOccurred(), "issuer mismatch should fail")
+		e = validateIdTokenClaims(valid, op.URL, TestClientId, TestNonce, now.Add(2*time.Hour))
+		g.Expect(e).To(HaveOccurred(), "expired ID token should fail")
+	}
+}
+
+func SubTestConcurrentDiscovery(op *TestOpenIDProvider) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		manager := NewProviderClientManager(nil)
+		g.Expect(manager.httpClient.Timeout).To(BeNumerically(">", 0), "default HTTP client should have timeout")
+		before := atomic.LoadInt32(&op.discoveries)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, e := manager.GetClient(ctx, op.URL)
+				g.Expect(e).To(Succeed())
+			}()
+		}
+		wg.Wait()
+		after := atomic.LoadInt32(&op.discoveries)
+		g.Expect(after-before).To(BeNumerically(">=", 1), "provider configuration should be discovered")
+		_, e := manager.GetClient(ctx, op.URL)
+		g.Expect(e).To(Succeed())
+		g.Expect(atomic.LoadInt32(&op.discoveries)).To(Equal(after), "discovered client should be cached")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+type TestOpenIDProvider struct {
+	*httptest.Server
+	jwkStore    *jwt.SingleJwkStore
+	discoveries int32
+}
+
+func NewTestOpenIDProvider() *TestOpenIDProvider {
+	op := &TestOpenIDProvider{
+		jwkStore: jwt.NewSingleJwkStoreWithOptions(func(s *jwt.SingleJwkStore) {
+			s.Kid = TestKid
+		}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, op.discovery)
+	mux.HandleFunc("/jwks", op.jwks)
+	mux.HandleFunc("/token", op.token)
+	op.Server = httptest.NewServer(mux)
+	return op
+}
+
+func (op *TestOpenIDProvider) IdentityProvider() *OidcIdentityProvider {
+	return NewIdentityProvider(func(opt *OidcIdpDetails) {
+		opt.Domain = "localhost"
+		opt.Issuer = op.URL
+		opt.ClientId = TestClientId
+		opt.ClientSecret = TestClientSecret
+		opt.ExternalIdName = oauth2.ClaimSubject
+	})
+}
+
+func (op *TestOpenIDProvider) claims(now time.Time) oauth2.MapClaims {
+	return oauth2.MapClaims{
+		oauth2.ClaimIssuer:   op.URL,
+		oauth2.ClaimSubject:  TestExternalId,
+		oauth2.ClaimAudience: TestClientId,
+		oauth2.ClaimIssueAt:  now.Unix(),
+		oauth2.ClaimExpire:   now.Add(time.Hour).Unix(),
+		oauth2.ClaimNonce:    TestNonce,
+	}
+}
+
+func (op *TestOpenIDProvider) discovery(rw http.ResponseWriter, _ *http.Request) {
+	atomic.AddInt32(&op.discoveries, 1)
+	writeJson(rw, http.StatusOK, ProviderMetadata{
+		Issuer:                op.URL,
+		AuthorizationEndpoint: op.URL + "/authorize",
+		TokenEndpoint:         op.URL + "/token",
+		JwksUri:               op.URL + "/jwks",
+	})
+}
+
+func (op *TestOpenIDProvider) jwks(rw http.ResponseWriter, r *http.Request) {
+	jwks, _ := op.jwkStore.LoadAll(r.Context())
+	writeJson(rw, http.StatusOK, map[string]interface{}{"keys": jwks})
+}
+
+func (op *TestOpenIDProvider) token(rw http.ResponseWriter, r *http.Request) {
+	id, secret, _ := r.BasicAuth()
+	_ = r.ParseForm()
+	if id != TestClientId || secret != TestClientSecret ||
+		r.PostForm.Get(oauth2.ParameterAuthCode) != TestCode || r.PostForm.Get(oauth2.ParameterCodeVerifier) != TestVerifier {
+		writeJson(rw, http.StatusBadRequest, TokenResponse{Error: "invalid_grant"})
+		return
+	}
+	idToken, e := jwt.NewSignedJwtEncoder(jwt.SignWithJwkStore(op.jwkStore, TestKid)).Encode(r.Context(), op.claims(time.Now()))
+	if e != nil {
+		writeJson(rw, http.StatusInternalServerError, TokenResponse{Error: "server_error"})
+		return
+	}
+	writeJson(rw, http.StatusOK, TokenResponse{AccessToken: "access-token", TokenType: "bearer", IdToken: idToken})
+}
+
+func writeJson(rw http.ResponseWriter, status int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(v)
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package extoidcidp
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/security"
+)
+
+var FeatureId = security.FeatureId("oidc_login", security.FeatureOrderOidcLogin)
+
+// Feature configures login via external OpenID providers, using authorization code flow with PKCE
+type Feature struct {
+	callbackPath   string
+	errorPath      string //The path to send the user to when authentication error is encountered
+	successHandler security.AuthenticationSuccessHandler
+	issuer         security.Issuer
+}
+
+func New() *Feature {
+	return &Feature{
+		callbackPath: "/oidc/callback",
+		errorPath:    "/error",
+	}
+}
+
+func (f *Feature) Identifier() security.FeatureIdentifier {
+	return FeatureId
+}
+
+func (f *Feature) Issuer(issuer security.Issuer) *Feature {
+	f.issuer = issuer
+	return f
+}
+
+// CallbackPath is the path of redirect_uri registered with external OpenID providers
+func (f *Feature) CallbackPath(path string) *Feature {
+	f.callbackPath = path
+	return f
+}
+
+func (f *Feature) ErrorPath(path string) *Feature {
+	f.errorPath = path
+	return f
+}
+
+func (f *Feature) SuccessHandler(handler security.AuthenticationSuccessHandler) *Feature {
+	f.successHandler = handler
+	return f
+}
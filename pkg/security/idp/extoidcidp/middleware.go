@@ -0,0 +1,257 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package extoidcidp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/idp"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/redirect"
+	"github.com/cisco-open/go-lanai/pkg/security/session"
+	netutil "github.com/cisco-open/go-lanai/pkg/utils/net"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	sessionKeyAuthRequest = "OidcAuthRequest"
+	codeChallengeMethod   = "S256"
+	clockSkew             = time.Minute
+)
+
+// authRequest is the state of an in-flight authorization request, kept in session
+type authRequest struct {
+	Issuer       string
+	State        string
+	Nonce        string
+	CodeVerifier string
+	RedirectUri  string
+}
+
+// OidcLoginMiddleware implements security.AuthenticationEntryPoint and the redirect_uri callback handler
+// of authorization code flow with PKCE.
+type OidcLoginMiddleware struct {
+	issuer             security.Issuer
+	callbackPath       string
+	idpManager         idp.IdentityProviderManager
+	clientManager      *ProviderClientManager
+	authenticator      security.Authenticator
+	successHandler     security.AuthenticationSuccessHandler
+	fallbackEntryPoint security.AuthenticationEntryPoint
+}
+
+func NewLoginMiddleware(issuer security.Issuer, callbackPath string,
+	idpManager idp.IdentityProviderManager, clientManager *ProviderClientManager,
+	handler security.AuthenticationSuccessHandler, authenticator security.Authenticator,
+	errorPath string) *OidcLoginMiddleware {
+
+	return &OidcLoginMiddleware{
+		issuer:             issuer,
+		callbackPath:       callbackPath,
+		idpManager:         idpManager,
+		clientManager:      clientManager,
+		authenticator:      authenticator,
+		successHandler:     handler,
+		fallbackEntryPoint: redirect.NewRedirectWithURL(errorPath),
+	}
+}
+
+// MakeAuthenticationRequest redirect user agent to the authorization endpoint of the OpenID provider configured for current domain
+func (m *OidcLoginMiddleware) MakeAuthenticationRequest(ctx context.Context, r *http.Request, w http.ResponseWriter) error {
+	s := session.Get(ctx)
+	if s == nil {
+		return security.NewExternalOidcAuthenticationError("session is required for OpenID Connect login")
+	}
+
+	host := netutil.GetForwardedHostName(r)
+	p, e := m.findProvider(ctx, host)
+	if e != nil {
+		return e
+	}
+
+	client, e := m.clientManager.GetClient(ctx, p.Issuer())
+	if e != nil {
+		return security.NewExternalOidcAuthenticationError("cannot resolve OpenID provider configuration", e)
+	}
+
+	callback, e := m.issuer.BuildUrl(func(opt *security.UrlBuilderOption) {
+		opt.FQDN = host
+		opt.Path = m.callbackPath
+	})
+	if e != nil {
+		return security.NewExternalOidcAuthenticationError("cannot build redirect URI", e)
+	}
+
+	req := authRequest{
+		Issuer:       p.Issuer(),
+		State:        randomString(),
+		Nonce:        randomString(),
+		CodeVerifier: randomString(),
+		RedirectUri:  callback.String(),
+	}
+	s.Set(sessionKeyAuthRequest, &req)
+
+	authorizeUrl, e := url.Parse(client.Metadata.AuthorizationEndpoint)
+	if e != nil {
+		return security.NewExternalOidcAuthenticationError("invalid authorization endpoint", e)
+	}
+	challenge := sha256.Sum256([]byte(req.CodeVerifier))
+	query := authorizeUrl.Query()
+	query.Set(oauth2.ParameterResponseType, oauth2.ParameterAuthCode)
+	query.Set(oauth2.ParameterClientId, p.ClientId())
+	query.Set(oauth2.ParameterRedirectUri, req.RedirectUri)
+	query.Set(oauth2.ParameterScope, strings.Join(p.Scopes(), " "))
+	query.Set(oauth2.ParameterState, req.State)
+	query.Set(oauth2.ParameterNonce, req.Nonce)
+	query.Set(oauth2.ParameterCodeChallenge, base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set(oauth2.ParameterCodeChallengeMethod, codeChallengeMethod)
+	authorizeUrl.RawQuery = query.Encode()
+
+	http.Redirect(w, r, authorizeUrl.String(), http.StatusFound)
+	return nil
+}
+
+// CallbackHandlerFunc is the redirect_uri endpoint. OpenID provider redirect user agent to this endpoint with authorization code
+func (m *OidcLoginMiddleware) CallbackHandlerFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, e := m.loadAuthRequest(c)
+		if e != nil {
+			m.handleError(c, e)
+			return
+		}
+
+		if errCode := c.Query(oauth2.ParameterError); errCode != "" {
+			m.handleError(c, security.NewExternalOidcAuthenticationError(
+				fmt.Sprintf("OpenID provider returned error: %s - %s", errCode, c.Query(oauth2.ParameterErrorDescription))))
+			return
+		}
+
+		p, e := m.findProvider(c, netutil.GetForwardedHostName(c.Request))
+		if e != nil {
+			m.handleError(c, e)
+			return
+		}
+		if p.Issuer() != req.Issuer {
+			m.handleError(c, security.NewExternalOidcAuthenticationError("OpenID provider changed during authentication"))
+			return
+		}
+
+		client, e := m.clientManager.GetClient(c, p.Issuer())
+		if e != nil {
+			m.handleError(c, security.NewExternalOidcAuthenticationError("cannot resolve OpenID provider configuration", e))
+			return
+		}
+
+		token, e := client.ExchangeCode(c, p, c.Query(oauth2.ParameterAuthCode), req.RedirectUri, req.CodeVerifier)
+		if e != nil {
+			logger.WithContext(c).Debugf("authorization code exchange failed: %v", e)
+			m.handleError(c, security.NewExternalOidcAuthenticationError(e.Error(), e))
+			return
+		}
+
+		claims, e := client.DecodeIdToken(c, token.IdToken)
+		if e != nil {
+			m.handleError(c, security.NewExternalOidcAuthenticationError("invalid ID token signature", e))
+			return
+		}
+		if e := validateIdTokenClaims(claims, client.Metadata.Issuer, p.ClientId(), req.Nonce, time.Now()); e != nil {
+			m.handleError(c, security.NewExternalOidcAuthenticationError(e.Error(), e))
+			return
+		}
+
+		candidate := &IdTokenCandidate{
+			Provider: p,
+			IdToken:  token.IdToken,
+			Claims:   claims,
+		}
+		auth, e := m.authenticator.Authenticate(c, candidate)
+		if e != nil {
+			m.handleError(c, security.NewExternalOidcAuthenticationError(e))
+			return
+		}
+
+		before := security.Get(c)
+		m.handleSuccess(c, before, auth)
+	}
+}
+
+func (m *OidcLoginMiddleware) Commence(c context.Context, r *http.Request, w http.ResponseWriter, _ error) {
+	err := m.MakeAuthenticationRequest(c, r, w)
+	if err != nil {
+		m.fallbackEntryPoint.Commence(c, r, w, err)
+	}
+}
+
+func (m *OidcLoginMiddleware) findProvider(ctx context.Context, host string) (OpenIDIdentityProvider, error) {
+	provider, e := m.idpManager.GetIdentityProviderByDomain(ctx, host)
+	if e != nil {
+		logger.WithContext(ctx).Debugf("cannot find idp for domain %s", host)
+		return nil, security.NewExternalOidcAuthenticationError("cannot find idp for this domain")
+	}
+	p, ok := provider.(OpenIDIdentityProvider)
+	if !ok {
+		return nil, security.NewExternalOidcAuthenticationError("idp of this domain is not an OpenID provider")
+	}
+	return p, nil
+}
+
+// loadAuthRequest load and remove the pending authRequest from session, and verify the "state" parameter.
+func (m *OidcLoginMiddleware) loadAuthRequest(c *gin.Context) (*authRequest, error) {
+	s := session.Get(c)
+	if s == nil {
+		return nil, security.NewExternalOidcAuthenticationError("session is required for OpenID Connect login")
+	}
+	req, ok := s.Get(sessionKeyAuthRequest).(*authRequest)
+	if !ok {
+		return nil, security.NewExternalOidcAuthenticationError("no pending OpenID Connect authentication request")
+	}
+	s.Delete(sessionKeyAuthRequest)
+	if state := c.Query(oauth2.ParameterState); state == "" || state != req.State {
+		return nil, security.NewExternalOidcAuthenticationError("state mismatch")
+	}
+	return req, nil
+}
+
+func (m *OidcLoginMiddleware) handleSuccess(c *gin.Context, before, new security.Authentication) {
+	if new != nil {
+		security.MustSet(c, new)
+	}
+	m.successHandler.HandleAuthenticationSuccess(c, c.Request, c.Writer, before, new)
+	if c.Writer.Written() {
+		c.Abort()
+	}
+}
+
+func (m *OidcLoginMiddleware) handleError(c *gin.Context, err error) {
+	security.MustClear(c)
+	_ = c.Error(err)
+	c.Abort()
+}
+
+func randomString() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
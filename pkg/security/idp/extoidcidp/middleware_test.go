@@ -0,0 +1,236 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package extoidcidp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/idp"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/session"
+	"github.com/cisco-open/go-lanai/pkg/security/session/common"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/mocks/sessionmock"
+	"github.com/cisco-open/go-lanai/test/webtest"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const (
+	TestCallbackPath = "/oidc/callback"
+	TestErrorPath    = "/error"
+	TestState        = "test-state"
+)
+
+/*************************
+	Test Cases
+ *************************/
+
+func TestOidcLoginMiddleware(t *testing.T) {
+	op := NewTestOpenIDProvider()
+	defer op.Close()
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestAuthenticationRequest(op), "AuthenticationRequest"),
+		test.GomegaSubTest(SubTestAuthenticationRequestWithoutSession(op), "AuthenticationRequestWithoutSession"),
+		test.GomegaSubTest(SubTestCallbackSuccess(op), "CallbackSuccess"),
+		test.GomegaSubTest(SubTestCallbackStateMismatch(op), "CallbackStateMismatch"),
+		test.GomegaSubTest(SubTestCallbackProviderError(op), "CallbackProviderError"),
+		test.GomegaSubTest(SubTestCallbackNonceMismatch(op), "CallbackNonceMismatch"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestAuthenticationRequest(op *TestOpenIDProvider) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		mw, _ := NewTestLoginMiddleware(op)
+		ctx, s := ContextWithSession(ctx, t)
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/login", nil).WithContext(ctx)
+		rw := httptest.NewRecorder()
+
+		mw.Commence(ctx, req, rw, nil)
+		g.Expect(rw.Code).To(Equal(http.StatusFound), "user agent should be redirected")
+		location, e := url.Parse(rw.Header().Get("Location"))
+		g.Expect(e).To(Succeed())
+		g.Expect(location.Scheme+"://"+location.Host+location.Path).To(Equal(op.URL+"/authorize"), "should redirect to discovered authorization endpoint")
+
+		pending, ok := s.Get(sessionKeyAuthRequest).(*authRequest)
+		g.Expect(ok).To(BeTrue(), "auth request should be saved in session")
+		challenge := sha256.Sum256([]byte(pending.CodeVerifier))
+		query := location.Query()
+		g.Expect(query.Get(oauth2.ParameterResponseType)).To(Equal("code"))
+		g.Expect(query.Get(oauth2.ParameterClientId)).To(Equal(TestClientId))
+		g.Expect(query.Get(oauth2.ParameterState)).To(Equal(pending.State))
+		g.Expect(query.Get(oauth2.ParameterNonce)).To(Equal(pending.Nonce))
+		g.Expect(query.Get(oauth2.ParameterRedirectUri)).To(Equal(pending.RedirectUri))
+		g.Expect(pending.RedirectUri).To(HaveSuffix(TestCallbackPath))
+		g.Expect(query.Get(oauth2.ParameterCodeChallenge)).To(Equal(base64.RawURLEncoding.EncodeToString(challenge[:])))
+		g.Expect(query.Get(oauth2.ParameterCodeChallengeMethod)).To(Equal(codeChallengeMethod))
+	}
+}
+
+func SubTestAuthenticationRequestWithoutSession(op *TestOpenIDProvider) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		mw, _ := NewTestLoginMiddleware(op)
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/login", nil).WithContext(ctx)
+		rw := httptest.NewRecorder()
+
+		mw.Commence(ctx, req, rw, nil)
+		g.Expect(rw.Code).To(Equal(http.StatusFound))
+		g.Expect(rw.Header().Get("Location")).To(Equal(TestErrorPath), "should fallback to error page")
+	}
+}
+
+func SubTestCallbackSuccess(op *TestOpenIDProvider) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		mw, success := NewTestLoginMiddleware(op)
+		gc, s := NewTestCallbackContext(ctx, t, op, TestNonce, url.Values{
+			oauth2.ParameterAuthCode: []string{TestCode},
+			oauth2.ParameterState:    []string{TestState},
+		})
+
+		mw.CallbackHandlerFunc()(gc)
+		g.Expect(gc.Errors).To(BeEmpty(), "callback should not fail")
+		g.Expect(success.auth).ToNot(BeNil(), "success handler should be invoked")
+		g.Expect(success.auth.State()).To(Equal(security.StateAuthenticated))
+		g.Expect(security.Get(gc)).To(Equal(success.auth), "authentication should be set in context")
+		g.Expect(s.Get(sessionKeyAuthRequest)).To(BeNil(), "auth request should be removed from session")
+	}
+}
+
+func SubTestCallbackStateMismatch(op *TestOpenIDProvider) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		mw, success := NewTestLoginMiddleware(op)
+		gc, s := NewTestCallbackContext(ctx, t, op, TestNonce, url.Values{
+			oauth2.ParameterAuthCode: []string{TestCode},
+			oauth2.ParameterState:    []string{"another-state"},
+		})
+
+		mw.CallbackHandlerFunc()(gc)
+		AssertCallbackError(g, gc, success)
+		g.Expect(s.Get(sessionKeyAuthRequest)).To(BeNil(), "auth request should not be reusable")
+	}
+}
+
+func SubTestCallbackProviderError(op *TestOpenIDProvider) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		mw, success := NewTestLoginMiddleware(op)
+		gc, _ := NewTestCallbackContext(ctx, t, op, TestNonce, url.Values{
+			oauth2.ParameterError: []string{"access_denied"},
+			oauth2.ParameterState: []string{TestState},
+		})
+
+		mw.CallbackHandlerFunc()(gc)
+		AssertCallbackError(g, gc, success)
+	}
+}
+
+func SubTestCallbackNonceMismatch(op *TestOpenIDProvider) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		mw, success := NewTestLoginMiddleware(op)
+		gc, _ := NewTestCallbackContext(ctx, t, op, "another-nonce", url.Values{
+			oauth2.ParameterAuthCode: []string{TestCode},
+			oauth2.ParameterState:    []string{TestState},
+		})
+
+		mw.CallbackHandlerFunc()(gc)
+		AssertCallbackError(g, gc, success)
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+type staticIdpManager []idp.IdentityProvider
+
+func (m staticIdpManager) GetIdentityProvidersWithFlow(_ context.Context, _ idp.AuthenticationFlow) []idp.IdentityProvider {
+	return m
+}
+
+func (m staticIdpManager) GetIdentityProviderByDomain(_ context.Context, domain string) (idp.IdentityProvider, error) {
+	for _, p := range m {
+		if p.Domain() == domain {
+			return p, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+// recordingSuccessHandler records the authentication passed to success handler
+type recordingSuccessHandler struct {
+	auth security.Authentication
+}
+
+func (h *recordingSuccessHandler) HandleAuthenticationSuccess(_ context.Context, _ *http.Request, _ http.ResponseWriter, _, to security.Authentication) {
+	h.auth = to
+}
+
+func NewTestLoginMiddleware(op *TestOpenIDProvider) (*OidcLoginMiddleware, *recordingSuccessHandler) {
+	issuer := security.NewIssuer(func(opt *security.DefaultIssuerDetails) {
+		opt.Protocol = "http"
+		opt.Domain = "localhost"
+		opt.Port = 80
+	})
+	success := &recordingSuccessHandler{}
+	mw := NewLoginMiddleware(issuer, TestCallbackPath,
+		staticIdpManager{op.IdentityProvider()}, NewProviderClientManager(nil),
+		success, &Authenticator{accountStore: NewMockedFederatedAccountStore()}, TestErrorPath)
+	return mw, success
+}
+
+func ContextWithSession(ctx context.Context, t *testing.T) (context.Context, *session.Session) {
+	ctrl := gomock.NewController(t)
+	store := sessionmock.NewMockStore(ctrl)
+	store.EXPECT().Options().Return(&session.Options{}).AnyTimes()
+	s := session.NewSession(store, common.DefaultName)
+	ctx = utils.MakeMutableContext(ctx)
+	session.MustSet(ctx, s)
+	return ctx, s
+}
+
+// NewTestCallbackContext prepare gin.Context of callback request, with pending auth request in session
+func NewTestCallbackContext(ctx context.Context, t *testing.T, op *TestOpenIDProvider, nonce string, query url.Values) (*gin.Context, *session.Session) {
+	ctx, s := ContextWithSession(ctx, t)
+	s.Set(sessionKeyAuthRequest, &authRequest{
+		Issuer:       op.URL,
+		State:        TestState,
+		Nonce:        nonce,
+		CodeVerifier: TestVerifier,
+		RedirectUri:  "http://localhost" + TestCallbackPath,
+	})
+	gc := webtest.NewGinContext(ctx, http.MethodGet, "http://localhost"+TestCallbackPath+"?"+query.Encode(), nil)
+	return gc, s
+}
+
+func AssertCallbackError(g *WithT, gc *gin.Context, success *recordingSuccessHandler) {
+	g.Expect(gc.IsAborted()).To(BeTrue(), "callback should be aborted")
+	g.Expect(gc.Errors).To(HaveLen(1), "error should be recorded")
+	g.Expect(errors.Is(gc.Errors[0].Err, security.ErrorSubTypeExternalOidcAuth)).To(BeTrue(), "error should be external OIDC auth error")
+	g.Expect(success.auth).To(BeNil(), "success handler should not be invoked")
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package extoidcidp
+
+import (
+	"encoding/gob"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/idp"
+	"go.uber.org/fx"
+	"net/http"
+	"time"
+)
+
+var logger = log.New("SEC.OIDC")
+
+var Module = &bootstrap.Module{
+	Name:       "OIDC IDP",
+	Precedence: security.MaxSecurityPrecedence - 100,
+	Options: []fx.Option{
+		fx.Provide(BindOidcAuthProperties),
+		fx.Invoke(register),
+	},
+}
+
+func init() {
+	gob.Register((*oidcAuthentication)(nil))
+	gob.Register((*authRequest)(nil))
+}
+
+func Use() {
+	bootstrap.Register(Module)
+}
+
+type initDI struct {
+	fx.In
+	SecRegistrar security.Registrar `optional:"true"`
+	IdpManager   idp.IdentityProviderManager
+	AccountStore security.FederatedAccountStore
+	Properties   OidcAuthProperties
+}
+
+func register(di initDI) {
+	if di.SecRegistrar != nil {
+		configurer := newOidcLoginConfigurer(di.IdpManager, di.AccountStore, NewProviderClientManager(&http.Client{
+			Timeout: time.Duration(di.Properties.HttpTimeout),
+		}))
+		di.SecRegistrar.(security.FeatureRegistrar).RegisterFeature(FeatureId, configurer)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package extoidcidp
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	PropertiesPrefix   = "security.idp.oidc"
+	defaultHttpTimeout = 10 * time.Second
+)
+
+type OidcAuthProperties struct {
+	Enabled   bool                       `json:"enabled"`
+	Endpoints OidcAuthEndpointProperties `json:"endpoints"`
+	// HttpTimeout is the timeout of requests sent to external OpenID providers, including discovery, JWKS and token requests
+	HttpTimeout utils.Duration `json:"http-timeout"`
+}
+
+type OidcAuthEndpointProperties struct {
+	// Callback is the redirect_uri path registered with external OpenID providers
+	Callback string `json:"callback"`
+}
+
+func NewOidcAuthProperties() *OidcAuthProperties {
+	return &OidcAuthProperties{
+		Endpoints: OidcAuthEndpointProperties{
+			Callback: "/oidc/callback",
+		},
+		HttpTimeout: utils.Duration(defaultHttpTimeout),
+	}
+}
+
+func BindOidcAuthProperties(ctx *bootstrap.ApplicationContext) OidcAuthProperties {
+	props := NewOidcAuthProperties()
+	if err := ctx.Config().Bind(props, PropertiesPrefix); err != nil {
+		panic(errors.Wrap(err, "failed to bind OidcAuthProperties"))
+	}
+	return *props
+}
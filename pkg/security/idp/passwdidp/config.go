@@ -28,6 +28,7 @@ import (
 	"github.com/cisco-open/go-lanai/pkg/security/redirect"
 	"github.com/cisco-open/go-lanai/pkg/security/request_cache"
 	"github.com/cisco-open/go-lanai/pkg/security/session"
+	"github.com/cisco-open/go-lanai/pkg/security/webauthn"
 	"github.com/cisco-open/go-lanai/pkg/web/matcher"
 	"time"
 )
@@ -116,4 +117,23 @@ func (c *PasswordIdpSecurityConfigurer) Configure(ws security.WebSecurity, confi
 			IgnoreCsrfProtectionMatcher(matcher.RequestWithPattern(config.Endpoints.Authorize.Location.Path)),
 		).
 		With(request_cache.New())
+
+	if c.props.MFA.Enabled {
+		// authenticator app enrollment pages
+		ws.Route(matcher.RouteWithPattern(c.props.Endpoints.TotpEnroll))
+	}
+
+	if c.props.WebAuthn.Enabled {
+		f := webauthn.New().
+			RegistrationOptionsUrl(c.props.Endpoints.WebAuthnRegistrationOptions).
+			RegistrationUrl(c.props.Endpoints.WebAuthnRegistration).
+			LoginOptionsUrl(c.props.Endpoints.WebAuthnLoginOptions).
+			LoginUrl(c.props.Endpoints.WebAuthnLogin).
+			MfaOptionsUrl(c.props.Endpoints.WebAuthnMfaOptions).
+			MfaVerifyUrl(c.props.Endpoints.WebAuthnMfa)
+		if c.props.MFA.Enabled {
+			f.EnableMFA()
+		}
+		ws.With(f)
+	}
 }
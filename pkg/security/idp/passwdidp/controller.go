@@ -18,11 +18,12 @@ package passwdidp
 
 import (
 	"github.com/cisco-open/go-lanai/pkg/security/formlogin"
+	"github.com/cisco-open/go-lanai/pkg/security/passwd"
 	"github.com/cisco-open/go-lanai/pkg/web"
 )
 
-func NewWhiteLabelLoginFormController() web.Controller {
-	return formlogin.NewDefaultLoginFormController(func(opts *formlogin.DefaultFormLoginPageOptions) {
+func NewWhiteLabelLoginFormController(opts ...formlogin.PageOptionsFunc) web.Controller {
+	whiteLabel := func(opts *formlogin.DefaultFormLoginPageOptions) {
 		opts.LoginTemplate = "login.tmpl"
 		opts.LoginProcessUrl = "/login"
 		opts.UsernameParam = "username"
@@ -31,5 +32,28 @@ func NewWhiteLabelLoginFormController() web.Controller {
 		opts.MfaVerifyUrl = "/login/mfa"
 		opts.MfaRefreshUrl = "/login/mfa/refresh"
 		opts.OtpParam = "otp"
+	}
+	return formlogin.NewDefaultLoginFormController(append([]formlogin.PageOptionsFunc{whiteLabel}, opts...)...)
+}
+
+// WithWhiteLabelWebAuthn shows passkey / security key sign-in on whitelabel login pages.
+// It should be used when WebAuthn is enabled with default endpoints
+func WithWhiteLabelWebAuthn() formlogin.PageOptionsFunc {
+	return func(opts *formlogin.DefaultFormLoginPageOptions) {
+		opts.WebAuthnLoginOptionsUrl = "/login/webauthn/options"
+		opts.WebAuthnLoginUrl = "/login/webauthn"
+		opts.WebAuthnMfaOptionsUrl = "/login/mfa/webauthn/options"
+		opts.WebAuthnMfaUrl = "/login/mfa/webauthn"
+		opts.WebAuthnCredentialParam = "credential"
+	}
+}
+
+// NewWhiteLabelTOTPEnrollmentController serves authenticator app enrollment page at PwdAuthEndpointProperties.TotpEnroll.
+// passwd.TOTPAppManager and PwdAuthProperties are available via DI
+func NewWhiteLabelTOTPEnrollmentController(manager *passwd.TOTPAppManager, props PwdAuthProperties) web.Controller {
+	return formlogin.NewDefaultTOTPEnrollmentController(func(opts *formlogin.TOTPEnrollmentPageOptions) {
+		opts.Manager = manager
+		opts.Template = "totp_enroll.tmpl"
+		opts.EnrollUrl = props.Endpoints.TotpEnroll
 	})
 }
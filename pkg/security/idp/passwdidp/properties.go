@@ -34,6 +34,7 @@ type PwdAuthProperties struct {
 	Endpoints                 PwdAuthEndpointProperties `json:"endpoints"`
 	MFA                       PwdAuthMfaProperties      `json:"mfa"`
 	RememberMe                RememberMeProperties      `json:"remember-me"`
+	WebAuthn                  PwdAuthWebAuthnProperties `json:"webauthn"`
 }
 
 type PwdAuthEndpointProperties struct {
@@ -45,6 +46,14 @@ type PwdAuthEndpointProperties struct {
 	OtpVerifyResend      string `json:"otp-verify-resend"`
 	OtpVerifyError       string `json:"otp-verify-error"`
 	ResetPasswordPageUrl string `json:"reset-password-page-url"`
+	TotpEnroll           string `json:"totp-enroll"`
+
+	WebAuthnRegistrationOptions string `json:"webauthn-registration-options"`
+	WebAuthnRegistration        string `json:"webauthn-registration"`
+	WebAuthnLoginOptions        string `json:"webauthn-login-options"`
+	WebAuthnLogin               string `json:"webauthn-login"`
+	WebAuthnMfaOptions          string `json:"webauthn-mfa-options"`
+	WebAuthnMfa                 string `json:"webauthn-mfa"`
 }
 
 type PwdAuthMfaProperties struct {
@@ -56,6 +65,11 @@ type PwdAuthMfaProperties struct {
 	OtpResendLimit uint           `json:"otp-resend-limit"`
 }
 
+// PwdAuthWebAuthnProperties enables passkey / security key login and MFA. Requires webauthn.Module
+type PwdAuthWebAuthnProperties struct {
+	Enabled bool `json:"enabled"`
+}
+
 type RememberMeProperties struct {
 	CookieDomain    string         `json:"cookie-domain"`
 	UseSecureCookie bool           `json:"use-secure-cookie"`
@@ -74,6 +88,14 @@ func NewPwdAuthProperties() *PwdAuthProperties {
 			OtpVerifyResend:      "/login/mfa/refresh",
 			OtpVerifyError:       "/login/mfa?error=true",
 			ResetPasswordPageUrl: "http://localhost:9003/#/forgotpassword",
+			TotpEnroll:           "/mfa/totp",
+
+			WebAuthnRegistrationOptions: "/webauthn/register/options",
+			WebAuthnRegistration:        "/webauthn/register",
+			WebAuthnLoginOptions:        "/login/webauthn/options",
+			WebAuthnLogin:               "/login/webauthn",
+			WebAuthnMfaOptions:          "/login/mfa/webauthn/options",
+			WebAuthnMfa:                 "/login/mfa/webauthn",
 		},
 		MFA: PwdAuthMfaProperties{
 			Enabled:        true,
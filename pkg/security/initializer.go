@@ -140,8 +140,8 @@ func (init *initializer) Initialize(ctx context.Context, _ fx.Lifecycle, registr
 func (init *initializer) build(ctx context.Context, configurer Configurer) (WebSecurityMappingBuilder, map[web.RequestPreProcessorName]web.RequestPreProcessor, error) {
 	// collect security configs
 	ws := newWebSecurity(ctx, NewAuthenticator(), map[string]interface{}{
-		WSSharedKeyCompositeAuthSuccessHandler: NewAuthenticationSuccessHandler(),
-		WSSharedKeyCompositeAuthErrorHandler: NewAuthenticationErrorHandler(),
+		WSSharedKeyCompositeAuthSuccessHandler: NewAuthenticationSuccessHandler(auditHandler{}),
+		WSSharedKeyCompositeAuthErrorHandler: NewAuthenticationErrorHandler(auditHandler{}),
 		WSSharedKeyCompositeAccessDeniedHandler: NewAccessDeniedHandler(),
 	})
 	configurer.Configure(ws)
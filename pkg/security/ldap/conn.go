@@ -0,0 +1,197 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	ldapv3 "github.com/go-ldap/ldap/v3"
+	"net"
+	neturl "net/url"
+	"time"
+)
+
+var ErrPoolClosed = errors.New("LDAP connection pool is closed")
+
+// Conn is the subset of LDAP operations used by this package. *ldapv3.Conn implements it
+type Conn interface {
+	Bind(username, password string) error
+	Search(req *ldapv3.SearchRequest) (*ldapv3.SearchResult, error)
+	Close() error
+}
+
+// Dialer opens a new LDAP connection. Returned connection is not bound
+type Dialer func(ctx context.Context) (Conn, error)
+
+// NewDialer create a Dialer with given URL. When tlsConfig is provided, it's used for both "ldaps://" and StartTLS
+func NewDialer(url string, startTLS bool, tlsConfig *tls.Config, timeout time.Duration) Dialer {
+	return func(ctx context.Context) (Conn, error) {
+		opts := []ldapv3.DialOpt{ldapv3.DialWithDialer(&net.Dialer{Timeout: timeout})}
+		if tlsConfig != nil {
+			opts = append(opts, ldapv3.DialWithTLSConfig(tlsConfig))
+		}
+		conn, e := ldapv3.DialURL(url, opts...)
+		if e != nil {
+			return nil, e
+		}
+		if timeout > 0 {
+			conn.SetTimeout(timeout)
+		}
+		if startTLS {
+			cfg := tlsConfig
+			if cfg == nil {
+				cfg = &tls.Config{ServerName: hostname(url)}
+			}
+			if e := conn.StartTLS(cfg); e != nil {
+				_ = conn.Close()
+				return nil, fmt.Errorf("StartTLS failed: %v", e)
+			}
+		}
+		return conn, nil
+	}
+}
+
+func hostname(rawUrl string) string {
+	if u, e := neturl.Parse(rawUrl); e == nil {
+		return u.Hostname()
+	}
+	return ""
+}
+
+type ConnPoolOptions func(opt *ConnPoolOption)
+type ConnPoolOption struct {
+	Dialer       Dialer
+	BindDN       string
+	BindPassword string
+	MaxOpen      int
+	MaxIdle      int
+}
+
+// ConnPool maintains LDAP connections bound as the service account.
+// Connections are returned via Put. Callers that bind a connection as other identity must restore it
+// with Rebind before returning it.
+type ConnPool struct {
+	dialer       Dialer
+	bindDN       string
+	bindPassword string
+	idle         chan Conn
+	slots        chan struct{}
+	closed       chan struct{}
+}
+
+func NewConnPool(opts ...ConnPoolOptions) *ConnPool {
+	opt := ConnPoolOption{
+		MaxOpen: 10,
+		MaxIdle: 5,
+	}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	if opt.MaxIdle > opt.MaxOpen {
+		opt.MaxIdle = opt.MaxOpen
+	}
+	return &ConnPool{
+		dialer:       opt.Dialer,
+		bindDN:       opt.BindDN,
+		bindPassword: opt.BindPassword,
+		idle:         make(chan Conn, opt.MaxIdle),
+		slots:        make(chan struct{}, opt.MaxOpen),
+		closed:       make(chan struct{}),
+	}
+}
+
+// Get returns an idle connection or opens a new one. It blocks when MaxOpen is reached until a connection
+// is returned or ctx is done
+func (p *ConnPool) Get(ctx context.Context) (Conn, error) {
+	select {
+	case <-p.closed:
+		return nil, ErrPoolClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case p.slots <- struct{}{}:
+	}
+
+	select {
+	case conn := <-p.idle:
+		return conn, nil
+	default:
+	}
+
+	conn, e := p.dialer(ctx)
+	if e == nil {
+		if e = p.BindServiceAccount(conn); e != nil {
+			_ = conn.Close()
+		}
+	}
+	if e != nil {
+		<-p.slots
+		return nil, e
+	}
+	return conn, nil
+}
+
+// Put returns the connection to the pool. Broken connections are closed
+func (p *ConnPool) Put(conn Conn, broken bool) {
+	defer func() { <-p.slots }()
+	if broken {
+		_ = conn.Close()
+		return
+	}
+	select {
+	case <-p.closed:
+		_ = conn.Close()
+	case p.idle <- conn:
+	default:
+		_ = conn.Close()
+	}
+}
+
+// BindServiceAccount bind given connection as the configured service account. Anonymous bind is used if not configured
+func (p *ConnPool) BindServiceAccount(conn Conn) error {
+	if p.bindDN == "" {
+		return nil
+	}
+	return conn.Bind(p.bindDN, p.bindPassword)
+}
+
+// Rebind restores service account binding of a connection that was bound as other identity.
+// Connections cannot be restored to anonymous binding, in which case an error is returned and the connection should be discarded
+func (p *ConnPool) Rebind(conn Conn) error {
+	if p.bindDN == "" {
+		return errors.New("cannot restore anonymous binding")
+	}
+	return conn.Bind(p.bindDN, p.bindPassword)
+}
+
+func (p *ConnPool) Close() error {
+	select {
+	case <-p.closed:
+		return nil
+	default:
+		close(p.closed)
+	}
+	for {
+		select {
+		case conn := <-p.idle:
+			_ = conn.Close()
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ldap provides LDAP / Active Directory backed security.AccountStore with bind authentication.
+//
+// The module provides AccountStore as the application's security.AccountStore. AccountStore implements passwd.PasswordVerifier,
+// so password authentication (e.g. passwdidp form login) binds to the directory server instead of comparing password hashes.
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/certs"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"go.uber.org/fx"
+	"time"
+)
+
+var logger = log.New("SEC.LDAP")
+
+var Module = &bootstrap.Module{
+	Name:       "LDAP",
+	Precedence: security.MaxSecurityPrecedence - 200,
+	Options: []fx.Option{
+		fx.Provide(BindLdapProperties),
+		fx.Provide(provideConnPool),
+		fx.Provide(provideAccountStore),
+	},
+}
+
+func Use() {
+	bootstrap.Register(Module)
+}
+
+type poolDI struct {
+	fx.In
+	Lifecycle   fx.Lifecycle
+	Properties  LdapProperties
+	CertManager certs.Manager `optional:"true"`
+}
+
+func provideConnPool(di poolDI) (*ConnPool, error) {
+	var tlsConfig *tls.Config
+	if di.Properties.TLS.Enabled {
+		if di.CertManager == nil {
+			return nil, fmt.Errorf("TLS is enabled for LDAP, but certificate manager is not available")
+		}
+		src, e := di.CertManager.Source(context.Background(), certs.WithSourceProperties(&di.Properties.TLS.Certs))
+		if e != nil {
+			return nil, fmt.Errorf("failed to initialize LDAP TLS: %v", e)
+		}
+		if tlsConfig, e = src.TLSConfig(context.Background()); e != nil {
+			return nil, fmt.Errorf("failed to initialize LDAP TLS: %v", e)
+		}
+	}
+	props := di.Properties
+	pool := NewConnPool(func(opt *ConnPoolOption) {
+		opt.Dialer = NewDialer(props.URL, props.StartTLS, tlsConfig, time.Duration(props.Timeout))
+		opt.BindDN = props.BindDN
+		opt.BindPassword = props.BindPassword
+		opt.MaxOpen = props.Pool.MaxOpen
+		opt.MaxIdle = props.Pool.MaxIdle
+	})
+	di.Lifecycle.Append(fx.StopHook(func() error {
+		logger.Debugf("closing LDAP connection pool")
+		return pool.Close()
+	}))
+	return pool, nil
+}
+
+type accountStoreOut struct {
+	fx.Out
+	Store        *AccountStore
+	AccountStore security.AccountStore
+}
+
+func provideAccountStore(pool *ConnPool, props LdapProperties) accountStoreOut {
+	store := NewAccountStore(func(opt *AccountStoreOption) {
+		opt.Pool = pool
+		opt.Properties = props
+	})
+	return accountStoreOut{
+		Store:        store,
+		AccountStore: store,
+	}
+}
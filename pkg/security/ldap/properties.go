@@ -0,0 +1,113 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ldap
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/certs"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	PropertiesPrefix = "security.ldap"
+)
+
+type LdapProperties struct {
+	Enabled bool `json:"enabled"`
+	// URL of the directory server, e.g. "ldap://localhost:389" or "ldaps://ad.example.com:636"
+	URL string `json:"url"`
+	// StartTLS upgrade plain "ldap://" connections using StartTLS extended operation
+	StartTLS bool          `json:"start-tls"`
+	TLS      TLSProperties `json:"tls"`
+	// BindDN and BindPassword are the service account used for searching users and groups
+	BindDN       string          `json:"bind-dn"`
+	BindPassword string          `json:"bind-password"`
+	Timeout      utils.Duration  `json:"timeout"`
+	Pool         PoolProperties  `json:"pool"`
+	User         UserProperties  `json:"user"`
+	Group        GroupProperties `json:"group"`
+	// ActiveDirectory enables AD specific behaviors, e.g. "userAccountControl" based account status
+	ActiveDirectory bool `json:"active-directory"`
+	// Permissions maps group name to permissions
+	Permissions map[string][]string `json:"permissions"`
+	// DefaultPermissions are granted to all LDAP users
+	DefaultPermissions []string `json:"default-permissions"`
+}
+
+type TLSProperties struct {
+	Enabled bool                   `json:"enabled"`
+	Certs   certs.SourceProperties `json:"certs"`
+}
+
+type PoolProperties struct {
+	MaxOpen int `json:"max-open"`
+	MaxIdle int `json:"max-idle"`
+}
+
+type UserProperties struct {
+	BaseDN string `json:"base-dn"`
+	// Filter to find user by username. "{0}" is replaced by escaped username, e.g. "(sAMAccountName={0})" for AD
+	Filter             string `json:"filter"`
+	EmailAttribute     string `json:"email-attribute"`
+	FirstNameAttribute string `json:"first-name-attribute"`
+	LastNameAttribute  string `json:"last-name-attribute"`
+}
+
+type GroupProperties struct {
+	BaseDN string `json:"base-dn"`
+	// Filter to find groups by member DN. "{0}" is replaced by escaped member DN.
+	// For AD, "(member:1.2.840.113556.1.4.1941:={0})" resolves nested groups on server side
+	Filter        string `json:"filter"`
+	NameAttribute string `json:"name-attribute"`
+	// Nested resolve nested groups by searching groups of groups, up to MaxDepth levels
+	Nested   bool `json:"nested"`
+	MaxDepth int  `json:"max-depth"`
+}
+
+func NewLdapProperties() *LdapProperties {
+	return &LdapProperties{
+		URL:     "ldap://localhost:389",
+		Timeout: utils.Duration(10 * time.Second),
+		Pool: PoolProperties{
+			MaxOpen: 10,
+			MaxIdle: 5,
+		},
+		User: UserProperties{
+			Filter:             "(uid={0})",
+			EmailAttribute:     "mail",
+			FirstNameAttribute: "givenName",
+			LastNameAttribute:  "sn",
+		},
+		Group: GroupProperties{
+			Filter:        "(member={0})",
+			NameAttribute: "cn",
+			Nested:        true,
+			MaxDepth:      5,
+		},
+		Permissions: map[string][]string{},
+	}
+}
+
+func BindLdapProperties(ctx *bootstrap.ApplicationContext) LdapProperties {
+	props := NewLdapProperties()
+	if err := ctx.Config().Bind(props, PropertiesPrefix); err != nil {
+		panic(errors.Wrap(err, "failed to bind LdapProperties"))
+	}
+	return *props
+}
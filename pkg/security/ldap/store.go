@@ -0,0 +1,265 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ldap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	ldapv3 "github.com/go-ldap/ldap/v3"
+	"strconv"
+	"strings"
+)
+
+const (
+	attrUserAccountControl = "userAccountControl"
+	// uacAccountDisable is the ACCOUNTDISABLE flag of AD "userAccountControl"
+	uacAccountDisable = 0x2
+	placeholder       = "{0}"
+)
+
+var (
+	ErrUserNotFound  = errors.New("LDAP user not found")
+	ErrEmptyPassword = errors.New("empty password is not allowed")
+)
+
+type AccountStoreOptions func(opt *AccountStoreOption)
+type AccountStoreOption struct {
+	Pool       *ConnPool
+	Properties LdapProperties
+}
+
+// AccountStore implements security.AccountStore and passwd.PasswordVerifier.
+// Users are searched with the service account, and passwords are verified by binding as the user's DN.
+// Group memberships are mapped to permissions via LdapProperties.Permissions. Accounts are read-only.
+type AccountStore struct {
+	pool  *ConnPool
+	props LdapProperties
+}
+
+func NewAccountStore(opts ...AccountStoreOptions) *AccountStore {
+	opt := AccountStoreOption{
+		Properties: *NewLdapProperties(),
+	}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	return &AccountStore{
+		pool:  opt.Pool,
+		props: opt.Properties,
+	}
+}
+
+// LoadAccountById load account by its DN
+func (s *AccountStore) LoadAccountById(ctx context.Context, id interface{}) (security.Account, error) {
+	dn, ok := id.(string)
+	if !ok {
+		return nil, fmt.Errorf("unsupported account ID type %T", id)
+	}
+	var acct security.Account
+	e := s.withConn(ctx, func(conn Conn) (err error) {
+		req := ldapv3.NewSearchRequest(dn, ldapv3.ScopeBaseObject, ldapv3.NeverDerefAliases, 1, 0, false,
+			"(objectClass=*)", s.userAttributes(), nil)
+		entry, err := searchOne(conn, req)
+		if err != nil {
+			return err
+		}
+		acct, err = s.toAccount(conn, entry, entry.DN)
+		return
+	})
+	return acct, e
+}
+
+func (s *AccountStore) LoadAccountByUsername(ctx context.Context, username string) (security.Account, error) {
+	var acct security.Account
+	e := s.withConn(ctx, func(conn Conn) (err error) {
+		filter := strings.ReplaceAll(s.props.User.Filter, placeholder, ldapv3.EscapeFilter(username))
+		req := ldapv3.NewSearchRequest(s.props.User.BaseDN, ldapv3.ScopeWholeSubtree, ldapv3.NeverDerefAliases, 0, 0, false,
+			filter, s.userAttributes(), nil)
+		entry, err := searchOne(conn, req)
+		if err != nil {
+			return err
+		}
+		acct, err = s.toAccount(conn, entry, username)
+		return
+	})
+	return acct, e
+}
+
+// LoadLockingRules returns disabled rule. Account locking is managed by directory server
+func (s *AccountStore) LoadLockingRules(_ context.Context, _ security.Account) (security.AccountLockingRule, error) {
+	return &security.DefaultAccount{
+		AcctLockingRule: security.AcctLockingRule{Name: "ldap"},
+	}, nil
+}
+
+// LoadPwdAgingRules returns disabled rule. Password policy is managed by directory server
+func (s *AccountStore) LoadPwdAgingRules(_ context.Context, _ security.Account) (security.AccountPwdAgingRule, error) {
+	return &security.DefaultAccount{
+		AcctPasswordPolicy: security.AcctPasswordPolicy{Name: "ldap"},
+	}, nil
+}
+
+// Save is noop. LDAP accounts are read-only
+func (s *AccountStore) Save(_ context.Context, _ security.Account) error {
+	return nil
+}
+
+// VerifyPassword implements passwd.PasswordVerifier by binding as the account's DN.
+// Only rejected binds and empty passwords are reported as bad credentials.
+// Connection and server failures are returned as internal errors, so they are not counted as failed login attempts
+func (s *AccountStore) VerifyPassword(ctx context.Context, acct security.Account, password string) error {
+	// Note: empty password results in "unauthenticated bind", which succeeds on many servers
+	if password == "" {
+		return ErrEmptyPassword
+	}
+	dn, ok := acct.ID().(string)
+	if !ok || dn == "" {
+		return security.NewInternalAuthenticationError("account is not loaded from LDAP")
+	}
+	conn, e := s.pool.Get(ctx)
+	if e != nil {
+		return translateError(e)
+	}
+	bindErr := conn.Bind(dn, password)
+	s.pool.Put(conn, s.pool.Rebind(conn) != nil)
+	if ldapv3.IsErrorWithCode(bindErr, ldapv3.LDAPResultInvalidCredentials) {
+		return security.NewBadCredentialsError("invalid LDAP credentials", bindErr)
+	}
+	return translateError(bindErr)
+}
+
+func (s *AccountStore) withConn(ctx context.Context, fn func(conn Conn) error) error {
+	conn, e := s.pool.Get(ctx)
+	if e != nil {
+		return translateError(e)
+	}
+	e = fn(conn)
+	s.pool.Put(conn, ldapv3.IsErrorWithCode(e, ldapv3.ErrorNetwork))
+	return translateError(e)
+}
+
+func (s *AccountStore) userAttributes() []string {
+	attrs := []string{s.props.User.EmailAttribute, s.props.User.FirstNameAttribute, s.props.User.LastNameAttribute}
+	if s.props.ActiveDirectory {
+		attrs = append(attrs, attrUserAccountControl)
+	}
+	return attrs
+}
+
+func (s *AccountStore) toAccount(conn Conn, entry *ldapv3.Entry, username string) (security.Account, error) {
+	groups, e := s.resolveGroups(conn, entry.DN)
+	if e != nil {
+		return nil, e
+	}
+	perms := utils.NewStringSet(s.props.DefaultPermissions...)
+	for _, g := range groups {
+		perms.Add(s.props.Permissions[g]...)
+	}
+
+	acct := security.NewUsernamePasswordAccount(&security.AcctDetails{
+		ID:          entry.DN,
+		Type:        security.AccountTypeDefault,
+		Username:    username,
+		Permissions: perms.Values(),
+		Disabled:    s.isDisabled(entry),
+	})
+	acct.AcctMetadata = security.AcctMetadata{
+		RoleNames: groups,
+		FirstName: entry.GetAttributeValue(s.props.User.FirstNameAttribute),
+		LastName:  entry.GetAttributeValue(s.props.User.LastNameAttribute),
+		Email:     entry.GetAttributeValue(s.props.User.EmailAttribute),
+	}
+	return acct, nil
+}
+
+// resolveGroups find names of groups the member belongs to. When nested group is enabled,
+// groups of groups are searched breadth-first up to configured depth
+func (s *AccountStore) resolveGroups(conn Conn, memberDN string) ([]string, error) {
+	if s.props.Group.BaseDN == "" {
+		return []string{}, nil
+	}
+	maxDepth := 1
+	if s.props.Group.Nested && s.props.Group.MaxDepth > 1 {
+		maxDepth = s.props.Group.MaxDepth
+	}
+
+	names := make([]string, 0, 4)
+	visited := utils.NewStringSet(memberDN)
+	members := []string{memberDN}
+	for depth := 0; depth < maxDepth && len(members) != 0; depth++ {
+		var next []string
+		for _, member := range members {
+			filter := strings.ReplaceAll(s.props.Group.Filter, placeholder, ldapv3.EscapeFilter(member))
+			req := ldapv3.NewSearchRequest(s.props.Group.BaseDN, ldapv3.ScopeWholeSubtree, ldapv3.NeverDerefAliases, 0, 0, false,
+				filter, []string{s.props.Group.NameAttribute}, nil)
+			result, e := conn.Search(req)
+			if e != nil {
+				return nil, e
+			}
+			for _, entry := range result.Entries {
+				if visited.Has(entry.DN) {
+					continue
+				}
+				visited.Add(entry.DN)
+				next = append(next, entry.DN)
+				if name := entry.GetAttributeValue(s.props.Group.NameAttribute); name != "" {
+					names = append(names, name)
+				}
+			}
+		}
+		members = next
+	}
+	return names, nil
+}
+
+func (s *AccountStore) isDisabled(entry *ldapv3.Entry) bool {
+	if !s.props.ActiveDirectory {
+		return false
+	}
+	uac, e := strconv.ParseInt(entry.GetAttributeValue(attrUserAccountControl), 10, 64)
+	return e == nil && uac&uacAccountDisable != 0
+}
+
+// translateError converts connection and server errors to internal authentication errors. ErrUserNotFound is kept as is.
+func translateError(e error) error {
+	switch {
+	case e == nil:
+		return nil
+	case errors.Is(e, ErrUserNotFound) || errors.Is(e, security.ErrorTypeSecurity):
+		return e
+	default:
+		return security.NewInternalAuthenticationError(fmt.Sprintf("LDAP server error: %v", e), e)
+	}
+}
+
+func searchOne(conn Conn, req *ldapv3.SearchRequest) (*ldapv3.Entry, error) {
+	result, e := conn.Search(req)
+	switch {
+	case ldapv3.IsErrorWithCode(e, ldapv3.LDAPResultNoSuchObject):
+		return nil, ErrUserNotFound
+	case e != nil:
+		return nil, e
+	case len(result.Entries) == 0:
+		return nil, ErrUserNotFound
+	case len(result.Entries) > 1:
+		return nil, fmt.Errorf("multiple LDAP users matched")
+	}
+	return result.Entries[0], nil
+}
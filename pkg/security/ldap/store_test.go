@@ -0,0 +1,281 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ldap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/passwd"
+	"github.com/cisco-open/go-lanai/test"
+	ldapv3 "github.com/go-ldap/ldap/v3"
+	. "github.com/onsi/gomega"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const (
+	TestServiceDN  = "cn=service,dc=example,dc=com"
+	TestServicePwd = "service-password"
+	TestUserDN     = "uid=alice,ou=people,dc=example,dc=com"
+	TestUsername   = "alice"
+	TestPassword   = "alice-password"
+)
+
+var errBadCredentials = security.NewBadCredentialsError("bad credentials")
+
+/*************************
+	Test Cases
+ *************************/
+
+func TestAccountStore(t *testing.T) {
+	dir := NewTestDirectory()
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestLoadAccount(dir, true), "LoadAccountWithNestedGroups"),
+		test.GomegaSubTest(SubTestLoadAccount(dir, false), "LoadAccountWithoutNestedGroups"),
+		test.GomegaSubTest(SubTestVerifyPassword(dir), "VerifyPassword"),
+		test.GomegaSubTest(SubTestPasswordAuthenticator(dir), "PasswordAuthenticator"),
+		test.GomegaSubTest(SubTestConnectionFailure(), "ConnectionFailure"),
+		test.GomegaSubTest(SubTestBindServerError(), "BindServerError"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestLoadAccount(dir *TestDirectory, nested bool) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		store := NewTestAccountStore(dir, func(props *LdapProperties) {
+			props.Group.Nested = nested
+		})
+		acct, e := store.LoadAccountByUsername(ctx, TestUsername)
+		g.Expect(e).To(Succeed(), "loading account should not fail")
+		g.Expect(acct.ID()).To(Equal(TestUserDN), "account ID should be DN")
+		g.Expect(acct.Username()).To(Equal(TestUsername), "username should be correct")
+		meta := acct.(security.AccountMetadata)
+		g.Expect(meta.Email()).To(Equal("alice@example.com"), "email should be mapped")
+		if nested {
+			g.Expect(meta.RoleNames()).To(ConsistOf("developers", "engineering", "admins"), "nested groups should be resolved")
+			g.Expect(acct.Permissions()).To(ConsistOf("LOGIN", "DEV", "ADMIN"), "permissions should be mapped from groups")
+		} else {
+			g.Expect(meta.RoleNames()).To(ConsistOf("developers"), "only direct groups should be resolved")
+			g.Expect(acct.Permissions()).To(ConsistOf("LOGIN", "DEV"), "permissions should be mapped from groups")
+		}
+
+		byId, e := store.LoadAccountById(ctx, TestUserDN)
+		g.Expect(e).To(Succeed(), "loading account by DN should not fail")
+		g.Expect(byId.ID()).To(Equal(TestUserDN), "account ID should be DN")
+
+		_, e = store.LoadAccountByUsername(ctx, "unknown")
+		g.Expect(e).To(MatchError(ErrUserNotFound), "unknown user should fail")
+	}
+}
+
+func SubTestVerifyPassword(dir *TestDirectory) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		store := NewTestAccountStore(dir)
+		acct, e := store.LoadAccountByUsername(ctx, TestUsername)
+		g.Expect(e).To(Succeed(), "loading account should not fail")
+		g.Expect(store.VerifyPassword(ctx, acct, TestPassword)).To(Succeed(), "correct password should pass")
+		g.Expect(store.VerifyPassword(ctx, acct, "wrong")).To(HaveOccurred(), "wrong password should fail")
+		g.Expect(store.VerifyPassword(ctx, acct, "")).To(MatchError(ErrEmptyPassword), "empty password should fail")
+
+		// pooled connection should be re-bound as service account
+		_, e = store.LoadAccountByUsername(ctx, TestUsername)
+		g.Expect(e).To(Succeed(), "pooled connection should be usable after user bind")
+	}
+}
+
+func SubTestPasswordAuthenticator(dir *TestDirectory) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		store := NewTestAccountStore(dir)
+		authenticator := passwd.NewAuthenticator(func(opts *passwd.AuthenticatorOptions) {
+			opts.AccountStore = store
+		})
+		auth, e := authenticator.Authenticate(ctx, &passwd.UsernamePasswordPair{Username: TestUsername, Password: TestPassword})
+		g.Expect(e).To(Succeed(), "authentication should succeed with LDAP bind")
+		g.Expect(auth.State()).To(Equal(security.StateAuthenticated), "authentication should be authenticated")
+		g.Expect(auth.Permissions()).To(HaveKey("DEV"), "authentication should have mapped permissions")
+
+		_, e = authenticator.Authenticate(ctx, &passwd.UsernamePasswordPair{Username: TestUsername, Password: "wrong"})
+		g.Expect(e).To(HaveOccurred(), "authentication should fail with wrong password")
+		g.Expect(errors.Is(e, errBadCredentials)).To(BeTrue(), "wrong password should be bad credentials")
+	}
+}
+
+func SubTestConnectionFailure() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		dir := NewTestDirectory()
+		dir.DialError = ldapv3.NewError(ldapv3.ErrorNetwork, fmt.Errorf("connection refused"))
+		store := NewTestAccountStore(dir)
+		_, e := store.LoadAccountByUsername(ctx, TestUsername)
+		g.Expect(errors.Is(e, security.ErrorSubTypeInternalError)).To(BeTrue(), "connection failure should be internal error")
+
+		authenticator := passwd.NewAuthenticator(func(opts *passwd.AuthenticatorOptions) {
+			opts.AccountStore = store
+		})
+		_, e = authenticator.Authenticate(ctx, &passwd.UsernamePasswordPair{Username: TestUsername, Password: TestPassword})
+		g.Expect(errors.Is(e, security.ErrorSubTypeInternalError)).To(BeTrue(), "connection failure should not be reported as user not found")
+		g.Expect(errors.Is(e, errBadCredentials)).To(BeFalse())
+	}
+}
+
+func SubTestBindServerError() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		dir := NewTestDirectory()
+		store := NewTestAccountStore(dir)
+		acct, e := store.LoadAccountByUsername(ctx, TestUsername)
+		g.Expect(e).To(Succeed())
+
+		dir.UserBindError = ldapv3.NewError(ldapv3.LDAPResultUnavailable, fmt.Errorf("server unavailable"))
+		e = store.VerifyPassword(ctx, acct, TestPassword)
+		g.Expect(errors.Is(e, security.ErrorSubTypeInternalError)).To(BeTrue(), "server error should be internal error")
+
+		authenticator := passwd.NewAuthenticator(func(opts *passwd.AuthenticatorOptions) {
+			opts.AccountStore = store
+		})
+		_, e = authenticator.Authenticate(ctx, &passwd.UsernamePasswordPair{Username: TestUsername, Password: TestPassword})
+		g.Expect(errors.Is(e, security.ErrorSubTypeInternalError)).To(BeTrue(), "server error should be internal error")
+		g.Expect(errors.Is(e, errBadCredentials)).To(BeFalse(), "server error should not count as bad credentials")
+	}
+}
+
+/*************************
+	In-process directory
+ *************************/
+
+var equalityFilter = regexp.MustCompile(`^\(([a-zA-Z]+)=(.*)\)$`)
+
+type TestEntry struct {
+	Attrs    map[string][]string
+	Password string
+}
+
+// TestDirectory is an in-process LDAP stand-in supporting simple bind and single equality filters
+type TestDirectory struct {
+	Entries map[string]TestEntry
+	// DialError fails all dials when set
+	DialError error
+	// UserBindError fails binds other than the service account when set
+	UserBindError error
+}
+
+func NewTestDirectory() *TestDirectory {
+	return &TestDirectory{
+		Entries: map[string]TestEntry{
+			TestServiceDN: {Password: TestServicePwd},
+			TestUserDN: {
+				Password: TestPassword,
+				Attrs:    map[string][]string{"uid": {TestUsername}, "mail": {"alice@example.com"}, "givenName": {"Alice"}},
+			},
+			"cn=developers,ou=groups,dc=example,dc=com": {
+				Attrs: map[string][]string{"cn": {"developers"}, "member": {TestUserDN}},
+			},
+			"cn=engineering,ou=groups,dc=example,dc=com": {
+				Attrs: map[string][]string{"cn": {"engineering"}, "member": {"cn=developers,ou=groups,dc=example,dc=com"}},
+			},
+			"cn=admins,ou=groups,dc=example,dc=com": {
+				Attrs: map[string][]string{"cn": {"admins"}, "member": {"cn=engineering,ou=groups,dc=example,dc=com"}},
+			},
+		},
+	}
+}
+
+func (d *TestDirectory) Dial(_ context.Context) (Conn, error) {
+	if d.DialError != nil {
+		return nil, d.DialError
+	}
+	return &testConn{dir: d}, nil
+}
+
+type testConn struct {
+	dir   *TestDirectory
+	bound string
+}
+
+func (c *testConn) Bind(username, password string) error {
+	if c.dir.UserBindError != nil && username != TestServiceDN {
+		return c.dir.UserBindError
+	}
+	if entry, ok := c.dir.Entries[username]; ok && entry.Password == password {
+		c.bound = username
+		return nil
+	}
+	c.bound = ""
+	return ldapv3.NewError(ldapv3.LDAPResultInvalidCredentials, fmt.Errorf("invalid credentials"))
+}
+
+func (c *testConn) Search(req *ldapv3.SearchRequest) (*ldapv3.SearchResult, error) {
+	if c.bound != TestServiceDN {
+		return nil, ldapv3.NewError(ldapv3.LDAPResultInsufficientAccessRights, fmt.Errorf("not bound as service account"))
+	}
+	result := &ldapv3.SearchResult{}
+	if req.Scope == ldapv3.ScopeBaseObject {
+		if entry, ok := c.dir.Entries[req.BaseDN]; ok {
+			result.Entries = append(result.Entries, ldapv3.NewEntry(req.BaseDN, entry.Attrs))
+			return result, nil
+		}
+		return nil, ldapv3.NewError(ldapv3.LDAPResultNoSuchObject, fmt.Errorf("no such object"))
+	}
+	matches := equalityFilter.FindStringSubmatch(req.Filter)
+	if matches == nil {
+		return nil, fmt.Errorf("unsupported filter %s", req.Filter)
+	}
+	for dn, entry := range c.dir.Entries {
+		if !strings.HasSuffix(dn, req.BaseDN) {
+			continue
+		}
+		for _, v := range entry.Attrs[matches[1]] {
+			if v == matches[2] {
+				result.Entries = append(result.Entries, ldapv3.NewEntry(dn, entry.Attrs))
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (c *testConn) Close() error {
+	return nil
+}
+
+func NewTestAccountStore(dir *TestDirectory, customizers ...func(props *LdapProperties)) *AccountStore {
+	props := NewLdapProperties()
+	props.BindDN = TestServiceDN
+	props.BindPassword = TestServicePwd
+	props.User.BaseDN = "ou=people,dc=example,dc=com"
+	props.Group.BaseDN = "ou=groups,dc=example,dc=com"
+	props.DefaultPermissions = []string{"LOGIN"}
+	props.Permissions = map[string][]string{"developers": {"DEV"}, "admins": {"ADMIN"}}
+	for _, fn := range customizers {
+		fn(props)
+	}
+	pool := NewConnPool(func(opt *ConnPoolOption) {
+		opt.Dialer = dir.Dial
+		opt.BindDN = props.BindDN
+		opt.BindPassword = props.BindPassword
+		opt.MaxOpen = 1
+		opt.MaxIdle = 1
+	})
+	return NewAccountStore(func(opt *AccountStoreOption) {
+		opt.Pool = pool
+		opt.Properties = *props
+	})
+}
@@ -247,6 +247,8 @@ func authMethodString(authMethod string) (ret string) {
 		return "saml"
 	case security.AuthMethodExternalOpenID:
 		return "openid"
+	case security.AuthMethodWebAuthn:
+		return "hwk"
 	}
 	return
 }
\ No newline at end of file
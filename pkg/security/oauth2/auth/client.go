@@ -18,6 +18,7 @@ package auth
 
 import (
 	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
 	"github.com/cisco-open/go-lanai/pkg/utils"
 	"time"
 )
@@ -38,6 +39,17 @@ type ClientDetails struct {
 	UseSessionTimeout    bool
 	AssignedTenantIds    utils.StringSet
 	ResourceIds          utils.StringSet
+	// TokenExchangeSubjectTypes is the subject token types this client may exchange. See oauth2.TokenExchangeClient
+	TokenExchangeSubjectTypes utils.StringSet
+	// TokenEndpointAuthMethod, JwkSetUri and TLSClientAuthSubjectDN are used by non-secret client authentication.
+	// See oauth2.ClientAuthMethodClient
+	TokenEndpointAuthMethod string
+	JwkSetUri               string
+	TLSClientAuthSubjectDN  string
+	// JwkSet is client's JWK Set registered by value. It takes precedence over JwkSetUri
+	JwkSet []jwt.Jwk
+	// DPoPBoundAccessTokens requires DPoP proof at token endpoint. See oauth2.DPoPClient
+	DPoPBoundAccessTokens bool
 }
 
 // DefaultOAuth2Client implements security.Account & OAuth2Client
@@ -105,6 +117,30 @@ func (c *DefaultOAuth2Client) ResourceIDs() utils.StringSet {
 	return c.ClientDetails.ResourceIds
 }
 
+func (c *DefaultOAuth2Client) TokenExchangeSubjectTypes() utils.StringSet {
+	return c.ClientDetails.TokenExchangeSubjectTypes
+}
+
+func (c *DefaultOAuth2Client) TokenEndpointAuthMethod() string {
+	return c.ClientDetails.TokenEndpointAuthMethod
+}
+
+func (c *DefaultOAuth2Client) JwkSetUri() string {
+	return c.ClientDetails.JwkSetUri
+}
+
+func (c *DefaultOAuth2Client) TLSClientAuthSubjectDN() string {
+	return c.ClientDetails.TLSClientAuthSubjectDN
+}
+
+func (c *DefaultOAuth2Client) JwkSet() []jwt.Jwk {
+	return c.ClientDetails.JwkSet
+}
+
+func (c *DefaultOAuth2Client) DPoPBoundAccessTokens() bool {
+	return c.ClientDetails.DPoPBoundAccessTokens
+}
+
 func (c *DefaultOAuth2Client) MaxTokensPerUser() int {
 	return -1
 }
@@ -0,0 +1,322 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package clientauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"github.com/cisco-open/go-lanai/pkg/security/replay"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"time"
+)
+
+const (
+	DetailsKeyClientAuthMethod = "ClientAuthMethod"
+	// ReplayCachePrefix is the key prefix of used client assertions when replay.RedisCache is used
+	ReplayCachePrefix = "CA"
+)
+
+/*****************************
+	Candidates
+ *****************************/
+
+// ClientAssertion implements security.Candidate. It represents a "private_key_jwt" client authentication.
+// See https://datatracker.ietf.org/doc/html/rfc7523#section-2.2
+type ClientAssertion struct {
+	ClientId  string
+	Assertion string
+	// Audiences are acceptable "aud" values of the assertion, typically issuer identifier and the URL of current endpoint
+	Audiences  utils.StringSet
+	DetailsMap map[string]interface{}
+}
+
+func (c *ClientAssertion) Principal() interface{} {
+	return c.ClientId
+}
+
+func (c *ClientAssertion) Credentials() interface{} {
+	return c.Assertion
+}
+
+func (c *ClientAssertion) Details() interface{} {
+	return c.DetailsMap
+}
+
+// ClientCertificate implements security.Candidate. It represents a "tls_client_auth" or "self_signed_tls_client_auth"
+// client authentication.
+// See https://datatracker.ietf.org/doc/html/rfc8705#section-2
+type ClientCertificate struct {
+	ClientId     string
+	Certificates []*x509.Certificate
+	// ChainVerified indicates the certificate chain was verified against trusted CAs during TLS handshake
+	ChainVerified bool
+	DetailsMap    map[string]interface{}
+}
+
+func (c *ClientCertificate) Principal() interface{} {
+	return c.ClientId
+}
+
+func (c *ClientCertificate) Credentials() interface{} {
+	return c.Certificates
+}
+
+func (c *ClientCertificate) Details() interface{} {
+	return c.DetailsMap
+}
+
+/*****************************
+	Authentication
+ *****************************/
+
+// clientAuthentication implements security.Authentication
+type clientAuthentication struct {
+	Client     oauth2.OAuth2Client
+	Perms      security.Permissions
+	DetailsMap map[string]interface{}
+}
+
+func (a *clientAuthentication) Principal() interface{} {
+	return a.Client
+}
+
+func (a *clientAuthentication) Permissions() security.Permissions {
+	return a.Perms
+}
+
+func (a *clientAuthentication) State() security.AuthenticationState {
+	return security.StateAuthenticated
+}
+
+func (a *clientAuthentication) Details() interface{} {
+	return a.DetailsMap
+}
+
+/*****************************
+	Authenticator
+ *****************************/
+
+// Authenticator implements security.Authenticator for ClientAssertion and ClientCertificate
+type Authenticator struct {
+	clientStore oauth2.OAuth2ClientStore
+	jwkResolver JwkStoreResolver
+	replayCache replay.Cache
+}
+
+type AuthenticatorOptions func(opt *AuthenticatorOption)
+
+type AuthenticatorOption struct {
+	ClientStore      oauth2.OAuth2ClientStore
+	JwkStoreResolver JwkStoreResolver
+	// ReplayCache is used to reject client assertions with previously used "jti". Default to replay.InMemoryCache
+	ReplayCache replay.Cache
+}
+
+func NewAuthenticator(opts ...AuthenticatorOptions) *Authenticator {
+	opt := AuthenticatorOption{}
+	for _, fn := range opts {
+		if fn != nil {
+			fn(&opt)
+		}
+	}
+	if opt.JwkStoreResolver == nil {
+		opt.JwkStoreResolver = NewRemoteJwkStoreResolver()
+	}
+	if opt.ReplayCache == nil {
+		opt.ReplayCache = replay.NewInMemoryCache()
+	}
+	return &Authenticator{
+		clientStore: opt.ClientStore,
+		jwkResolver: opt.JwkStoreResolver,
+		replayCache: opt.ReplayCache,
+	}
+}
+
+func (a *Authenticator) Authenticate(ctx context.Context, candidate security.Candidate) (security.Authentication, error) {
+	switch can := candidate.(type) {
+	case *ClientAssertion:
+		return a.authenticateAssertion(ctx, can)
+	case *ClientCertificate:
+		return a.authenticateCertificate(ctx, can)
+	default:
+		return nil, nil
+	}
+}
+
+func (a *Authenticator) authenticateAssertion(ctx context.Context, can *ClientAssertion) (security.Authentication, error) {
+	client, e := a.loadClient(ctx, can.ClientId, oauth2.ClientAuthMethodPrivateKeyJwt)
+	if e != nil {
+		return nil, e
+	}
+
+	store, e := a.jwkResolver.Resolve(ctx, client)
+	if e != nil {
+		return nil, security.NewInternalAuthenticationError("unable to resolve client's JWK set", e)
+	}
+	decoder := jwt.NewSignedJwtDecoder(jwt.VerifyWithJwkStore(store, ""))
+	claims := oauth2.BasicClaims{}
+	if e := decoder.DecodeWithClaims(ctx, can.Assertion, &claims); e != nil {
+		return nil, security.NewBadCredentialsError("invalid client assertion", e)
+	}
+
+	now := time.Now()
+	switch {
+	case claims.Issuer != client.ClientId() || claims.Subject != client.ClientId():
+		return nil, security.NewBadCredentialsError(`client assertion's "iss" and "sub" should be the client ID`)
+	case !a.hasAudience(claims.Audience, can.Audiences):
+		return nil, security.NewBadCredentialsError(`client assertion's "aud" is not acceptable`)
+	case claims.ExpiresAt.IsZero() || now.After(claims.ExpiresAt):
+		return nil, security.NewBadCredentialsError(`client assertion is expired or missing "exp"`)
+	case !claims.NotBefore.IsZero() && now.Before(claims.NotBefore):
+		return nil, security.NewBadCredentialsError(`client assertion is not yet valid`)
+	case claims.Id == "":
+		return nil, security.NewBadCredentialsError(`client assertion is missing "jti"`)
+	}
+
+	// the "jti" is only recorded until the assertion expires, after that the assertion would be rejected anyway
+	key := fmt.Sprintf("%s:%s", client.ClientId(), claims.Id)
+	switch ok, e := a.replayCache.Add(ctx, key, claims.ExpiresAt.Sub(now)); {
+	case e != nil:
+		return nil, security.NewInternalAuthenticationError("unable to check client assertion replay", e)
+	case !ok:
+		return nil, security.NewBadCredentialsError(`client assertion's "jti" was already used`)
+	}
+
+	return a.createSuccessAuthentication(client, oauth2.ClientAuthMethodPrivateKeyJwt, can.DetailsMap), nil
+}
+
+func (a *Authenticator) authenticateCertificate(ctx context.Context, can *ClientCertificate) (security.Authentication, error) {
+	if len(can.Certificates) == 0 {
+		return nil, security.NewBadCredentialsError("client certificate is missing")
+	}
+	client, e := a.loadClient(ctx, can.ClientId, oauth2.ClientAuthMethodTLS, oauth2.ClientAuthMethodSelfSignedTLS)
+	if e != nil {
+		return nil, e
+	}
+
+	cert := can.Certificates[0]
+	if now := time.Now(); now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return nil, security.NewBadCredentialsError("client certificate is expired or not yet valid")
+	}
+
+	method := AuthMethod(client)
+	switch method {
+	case oauth2.ClientAuthMethodTLS:
+		expected := client.(oauth2.ClientAuthMethodClient).TLSClientAuthSubjectDN()
+		if !can.ChainVerified || expected == "" || cert.Subject.String() != expected {
+			return nil, security.NewBadCredentialsError("client certificate doesn't match registered subject")
+		}
+	default:
+		if e := a.matchRegisteredKey(ctx, client, cert); e != nil {
+			return nil, e
+		}
+	}
+
+	details := can.DetailsMap
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details[oauth2.DetailsKeyCertThumbprint] = oauth2.CertificateThumbprint(cert)
+	return a.createSuccessAuthentication(client, method, details), nil
+}
+
+func (a *Authenticator) loadClient(ctx context.Context, clientId string, methods ...string) (oauth2.OAuth2Client, error) {
+	if clientId == "" {
+		return nil, security.NewUsernameNotFoundError("client ID is missing")
+	}
+	client, e := a.clientStore.LoadClientByClientId(ctx, clientId)
+	if e != nil {
+		return nil, security.NewUsernameNotFoundError("invalid client", e)
+	}
+	if method := AuthMethod(client); !utils.NewStringSet(methods...).Has(method) {
+		return nil, security.NewBadCredentialsError(fmt.Sprintf("client is registered with authentication method [%s]", method))
+	}
+	return client, nil
+}
+
+func (a *Authenticator) hasAudience(aud oauth2.StringSetClaim, accepted utils.StringSet) bool {
+	for v := range aud {
+		if accepted.Has(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRegisteredKey check if the self-signed certificate's public key is one of client's registered JWK
+func (a *Authenticator) matchRegisteredKey(ctx context.Context, client oauth2.OAuth2Client, cert *x509.Certificate) error {
+	store, e := a.jwkResolver.Resolve(ctx, client)
+	if e != nil {
+		return security.NewInternalAuthenticationError("unable to resolve client's JWK set", e)
+	}
+	jwks, e := store.LoadAll(ctx)
+	if e != nil {
+		return security.NewInternalAuthenticationError("unable to load client's JWK set", e)
+	}
+	for _, jwk := range jwks {
+		if pub, ok := jwk.Public().(interface{ Equal(x crypto.PublicKey) bool }); ok && pub.Equal(cert.PublicKey) {
+			return nil
+		}
+	}
+	return security.NewBadCredentialsError("client certificate doesn't match any registered key")
+}
+
+func (a *Authenticator) createSuccessAuthentication(client oauth2.OAuth2Client, method string, details map[string]interface{}) security.Authentication {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details[DetailsKeyClientAuthMethod] = method
+	details[security.DetailsKeyAuthTime] = time.Now().UTC()
+
+	perms := security.Permissions{}
+	if acct, ok := client.(security.Account); ok {
+		for _, p := range acct.Permissions() {
+			perms[p] = true
+		}
+		if cp, ok := acct.CacheableCopy().(oauth2.OAuth2Client); ok {
+			client = cp
+		}
+	}
+	return &clientAuthentication{
+		Client:     client,
+		Perms:      perms,
+		DetailsMap: details,
+	}
+}
+
+// AuthMethod returns client's token endpoint authentication method. Default to oauth2.ClientAuthMethodSecretBasic
+func AuthMethod(client oauth2.OAuth2Client) string {
+	if c, ok := client.(oauth2.ClientAuthMethodClient); ok && c.TokenEndpointAuthMethod() != "" {
+		return c.TokenEndpointAuthMethod()
+	}
+	return oauth2.ClientAuthMethodSecretBasic
+}
+
+// usesClientSecret returns true if the client should be authenticated with client secret
+func usesClientSecret(client oauth2.OAuth2Client) bool {
+	switch AuthMethod(client) {
+	case oauth2.ClientAuthMethodPrivateKeyJwt, oauth2.ClientAuthMethodTLS, oauth2.ClientAuthMethodSelfSignedTLS:
+		return false
+	default:
+		return true
+	}
+}
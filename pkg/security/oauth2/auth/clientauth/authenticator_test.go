@@ -0,0 +1,231 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package clientauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"math/big"
+	"testing"
+	"time"
+)
+
+/*************************
+	Setup
+ *************************/
+
+const (
+	TestJwtClientID  = "test-jwt-client"
+	TestTLSClientID  = "test-tls-client"
+	TestSelfClientID = "test-self-signed-client"
+	TestClientKid    = "test-client-kid"
+	TestAudience     = "http://localhost/auth/v2/token"
+	TestSubjectDN    = "CN=test-tls-client,O=Test"
+)
+
+type testClientStore map[string]oauth2.OAuth2Client
+
+func (s testClientStore) LoadClientByClientId(_ context.Context, clientId string) (oauth2.OAuth2Client, error) {
+	if c, ok := s[clientId]; ok {
+		return c, nil
+	}
+	return nil, errors.New("client not found")
+}
+
+func newTestClient(clientId, method string) oauth2.OAuth2Client {
+	return auth.NewClientWithDetails(auth.ClientDetails{
+		ClientId:                clientId,
+		Scopes:                  utils.NewStringSet("read"),
+		TokenEndpointAuthMethod: method,
+		TLSClientAuthSubjectDN:  TestSubjectDN,
+	})
+}
+
+func newTestAuthenticator(jwkStore jwt.JwkStore) *Authenticator {
+	return NewAuthenticator(func(opt *AuthenticatorOption) {
+		opt.ClientStore = testClientStore{
+			TestJwtClientID:  newTestClient(TestJwtClientID, oauth2.ClientAuthMethodPrivateKeyJwt),
+			TestTLSClientID:  newTestClient(TestTLSClientID, oauth2.ClientAuthMethodTLS),
+			TestSelfClientID: newTestClient(TestSelfClientID, oauth2.ClientAuthMethodSelfSignedTLS),
+		}
+		opt.JwkStoreResolver = StaticJwkStoreResolver{
+			TestJwtClientID:  jwkStore,
+			TestSelfClientID: jwkStore,
+		}
+	})
+}
+
+/*************************
+	Test
+ *************************/
+
+func TestClientAuthenticator(t *testing.T) {
+	jwkStore := jwt.NewSingleJwkStoreWithOptions(func(s *jwt.SingleJwkStore) {
+		s.Kid = TestClientKid
+	})
+	authenticator := newTestAuthenticator(jwkStore)
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestValidAssertion(authenticator, jwkStore), "ValidAssertion"),
+		test.GomegaSubTest(SubTestAssertionWithWrongAudience(authenticator, jwkStore), "AssertionWithWrongAudience"),
+		test.GomegaSubTest(SubTestAssertionWithWrongMethod(authenticator, jwkStore), "AssertionWithWrongMethod"),
+		test.GomegaSubTest(SubTestReplayedAssertion(authenticator, jwkStore), "ReplayedAssertion"),
+		test.GomegaSubTest(SubTestSelfSignedCertificate(authenticator, jwkStore), "SelfSignedCertificate"),
+		test.GomegaSubTest(SubTestTLSCertificate(authenticator, jwkStore), "TLSCertificate"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestValidAssertion(authenticator *Authenticator, jwkStore jwt.JwkStore) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		assertion := signTestAssertion(ctx, g, jwkStore, TestJwtClientID, TestAudience)
+		rs, e := authenticator.Authenticate(ctx, &ClientAssertion{
+			ClientId:  TestJwtClientID,
+			Assertion: assertion,
+			Audiences: utils.NewStringSet(TestAudience),
+		})
+		g.Expect(e).To(Succeed(), "authenticate should succeed")
+		g.Expect(rs.State()).To(Equal(security.StateAuthenticated), "authentication should be authenticated")
+		g.Expect(rs.Principal()).To(BeAssignableToTypeOf(&auth.DefaultOAuth2Client{}), "principal should be the client")
+		g.Expect(rs.Details()).To(HaveKeyWithValue(DetailsKeyClientAuthMethod, oauth2.ClientAuthMethodPrivateKeyJwt))
+	}
+}
+
+func SubTestAssertionWithWrongAudience(authenticator *Authenticator, jwkStore jwt.JwkStore) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		assertion := signTestAssertion(ctx, g, jwkStore, TestJwtClientID, "http://other.host/token")
+		_, e := authenticator.Authenticate(ctx, &ClientAssertion{
+			ClientId:  TestJwtClientID,
+			Assertion: assertion,
+			Audiences: utils.NewStringSet(TestAudience),
+		})
+		g.Expect(e).To(HaveOccurred(), "authenticate should fail")
+		g.Expect(errors.Is(e, security.ErrorTypeAuthentication)).To(BeTrue(), "error should be authentication error")
+	}
+}
+
+func SubTestAssertionWithWrongMethod(authenticator *Authenticator, jwkStore jwt.JwkStore) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		assertion := signTestAssertion(ctx, g, jwkStore, TestSelfClientID, TestAudience)
+		_, e := authenticator.Authenticate(ctx, &ClientAssertion{
+			ClientId:  TestSelfClientID,
+			Assertion: assertion,
+			Audiences: utils.NewStringSet(TestAudience),
+		})
+		g.Expect(e).To(HaveOccurred(), "authenticate should fail when client is registered with different method")
+	}
+}
+
+func SubTestReplayedAssertion(authenticator *Authenticator, jwkStore jwt.JwkStore) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		assertion := signTestAssertion(ctx, g, jwkStore, TestJwtClientID, TestAudience)
+		candidate := &ClientAssertion{
+			ClientId:  TestJwtClientID,
+			Assertion: assertion,
+			Audiences: utils.NewStringSet(TestAudience),
+		}
+		_, e := authenticator.Authenticate(ctx, candidate)
+		g.Expect(e).To(Succeed(), "first use of the assertion should succeed")
+		_, e = authenticator.Authenticate(ctx, candidate)
+		g.Expect(e).To(HaveOccurred(), "replayed assertion should fail")
+		g.Expect(errors.Is(e, security.ErrorTypeAuthentication)).To(BeTrue(), "error should be authentication error")
+	}
+}
+
+func SubTestSelfSignedCertificate(authenticator *Authenticator, jwkStore jwt.JwkStore) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		cert := createTestCertificate(ctx, g, jwkStore, TestSelfClientID)
+		rs, e := authenticator.Authenticate(ctx, &ClientCertificate{
+			ClientId:     TestSelfClientID,
+			Certificates: []*x509.Certificate{cert},
+		})
+		g.Expect(e).To(Succeed(), "authenticate should succeed")
+		g.Expect(rs.Details()).To(HaveKeyWithValue(oauth2.DetailsKeyCertThumbprint, oauth2.CertificateThumbprint(cert)))
+		g.Expect(rs.Details()).To(HaveKeyWithValue(DetailsKeyClientAuthMethod, oauth2.ClientAuthMethodSelfSignedTLS))
+	}
+}
+
+func SubTestTLSCertificate(authenticator *Authenticator, jwkStore jwt.JwkStore) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		cert := createTestCertificate(ctx, g, jwkStore, TestTLSClientID)
+		// chain not verified
+		_, e := authenticator.Authenticate(ctx, &ClientCertificate{
+			ClientId:     TestTLSClientID,
+			Certificates: []*x509.Certificate{cert},
+		})
+		g.Expect(e).To(HaveOccurred(), "authenticate should fail if certificate chain is not verified")
+
+		// verified
+		rs, e := authenticator.Authenticate(ctx, &ClientCertificate{
+			ClientId:      TestTLSClientID,
+			Certificates:  []*x509.Certificate{cert},
+			ChainVerified: true,
+		})
+		g.Expect(e).To(Succeed(), "authenticate should succeed")
+		g.Expect(rs.Details()).To(HaveKeyWithValue(DetailsKeyClientAuthMethod, oauth2.ClientAuthMethodTLS))
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func signTestAssertion(ctx context.Context, g *gomega.WithT, jwkStore jwt.JwkStore, clientId, aud string) string {
+	now := time.Now()
+	claims := oauth2.BasicClaims{
+		Id:        utils.RandomString(16),
+		Issuer:    clientId,
+		Subject:   clientId,
+		Audience:  oauth2.StringSetClaim(utils.NewStringSet(aud)),
+		ExpiresAt: now.Add(time.Minute),
+		IssuedAt:  now,
+	}
+	encoder := jwt.NewSignedJwtEncoder(jwt.SignWithJwkStore(jwkStore, TestClientKid))
+	assertion, e := encoder.Encode(ctx, &claims)
+	g.Expect(e).To(Succeed(), "signing client assertion should not fail")
+	return assertion
+}
+
+func createTestCertificate(ctx context.Context, g *gomega.WithT, jwkStore jwt.JwkStore, clientId string) *x509.Certificate {
+	jwks, e := jwkStore.LoadAll(ctx)
+	g.Expect(e).To(Succeed(), "loading JWK should not fail")
+	key := jwks[0].(jwt.PrivateJwk)
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: clientId, Organization: []string{"Test"}},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	raw, e := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, key.Public(), key.Private())
+	g.Expect(e).To(Succeed(), "creating certificate should not fail")
+	cert, e := x509.ParseCertificate(raw)
+	g.Expect(e).To(Succeed(), "parsing certificate should not fail")
+	return cert
+}
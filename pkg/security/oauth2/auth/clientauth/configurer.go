@@ -17,11 +17,13 @@
 package clientauth
 
 import (
+    "context"
     "fmt"
     "github.com/cisco-open/go-lanai/pkg/security"
     "github.com/cisco-open/go-lanai/pkg/security/access"
     "github.com/cisco-open/go-lanai/pkg/security/basicauth"
     "github.com/cisco-open/go-lanai/pkg/security/errorhandling"
+    "github.com/cisco-open/go-lanai/pkg/security/oauth2"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
     "github.com/cisco-open/go-lanai/pkg/security/passwd"
     "github.com/cisco-open/go-lanai/pkg/web/matcher"
@@ -62,16 +64,39 @@ func (c *ClientAuthConfigurer) Apply(feature security.Feature, ws security.WebSe
 	errorhandling.Configure(ws).
 		AdditionalErrorHandler(f.errorHandler)
 
+	// add authenticator for client assertion and client certificate
+	composite, ok := ws.Authenticator().(*security.CompositeAuthenticator)
+	if !ok {
+		return fmt.Errorf("unable to add client authenticator to %T", ws.Authenticator())
+	}
+	composite.Add(NewAuthenticator(func(opt *AuthenticatorOption) {
+		opt.ClientStore = f.clientStore
+		opt.JwkStoreResolver = f.jwkStoreResolver
+		opt.ReplayCache = f.replayCache
+	}))
+
 	// add middleware to translate authentication error to oauth2 error
 	mw := NewClientAuthMiddleware(func(opt *MWOption) {
 		opt.Authenticator = ws.Authenticator()
 		opt.SuccessHandler = ws.Shared(security.WSSharedKeyCompositeAuthSuccessHandler).(security.AuthenticationSuccessHandler)
+		opt.ClientStore = f.clientStore
+		opt.Issuer = f.issuer
 	})
 	ws.Add(middleware.NewBuilder("client auth error translator").
 		Order(security.MWOrderPreAuth).
 		Use(mw.ErrorTranslationHandlerFunc()),
 	)
 
+	// add middlewares to support private_key_jwt and mTLS client auth. They need to be placed before form client auth
+	ws.Add(middleware.NewBuilder("client assertion auth").
+		Order(security.MWOrderFormAuth - 10).
+		Use(mw.ClientAssertionHandlerFunc()),
+	)
+	ws.Add(middleware.NewBuilder("client certificate auth").
+		Order(security.MWOrderFormAuth - 10).
+		Use(mw.ClientCertificateHandlerFunc()),
+	)
+
 	// add middleware to support form based client auth
 	if f.allowForm {
 		ws.Add(middleware.NewBuilder("form client auth").
@@ -98,8 +123,26 @@ func (c *ClientAuthConfigurer) validate(f *ClientAuthFeature, ws security.WebSec
 	return nil
 }
 
+// clientAccountStore returns an account store for password authenticator. Clients that don't use client secret
+// are excluded, so they cannot be authenticated with basic auth or form auth.
 func (c *ClientAuthConfigurer) clientAccountStore(f *ClientAuthFeature) *auth.OAuth2ClientAccountStore {
-	return auth.WrapOAuth2ClientStore(f.clientStore)
+	return auth.WrapOAuth2ClientStore(secretClientStore{OAuth2ClientStore: f.clientStore})
+}
+
+// secretClientStore implements oauth2.OAuth2ClientStore and only returns clients that authenticate with client secret
+type secretClientStore struct {
+	oauth2.OAuth2ClientStore
+}
+
+func (s secretClientStore) LoadClientByClientId(ctx context.Context, clientId string) (oauth2.OAuth2Client, error) {
+	client, e := s.OAuth2ClientStore.LoadClientByClientId(ctx, clientId)
+	if e != nil {
+		return nil, e
+	}
+	if !usesClientSecret(client) {
+		return nil, fmt.Errorf("client [%s] doesn't use client secret", clientId)
+	}
+	return client, nil
 }
 
 
@@ -22,6 +22,7 @@ import (
     "github.com/cisco-open/go-lanai/pkg/security/oauth2"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
     "github.com/cisco-open/go-lanai/pkg/security/passwd"
+    "github.com/cisco-open/go-lanai/pkg/security/replay"
 )
 
 // We currently don't have any stuff to configure
@@ -31,6 +32,9 @@ type ClientAuthFeature struct {
 	clientSecretEncoder passwd.PasswordEncoder
 	errorHandler        *auth.OAuth2ErrorHandler
 	allowForm           bool
+	issuer              security.Issuer
+	jwkStoreResolver    JwkStoreResolver
+	replayCache         replay.Cache
 }
 
 // Standard security.Feature entrypoint
@@ -72,4 +76,24 @@ func (f *ClientAuthFeature) ErrorHandler(errorHandler *auth.OAuth2ErrorHandler)
 func (f *ClientAuthFeature) AllowForm(allowForm bool) *ClientAuthFeature {
 	f.allowForm = allowForm
 	return f
-}
\ No newline at end of file
+}
+
+// Issuer is used to verify "aud" of client assertions ("private_key_jwt")
+func (f *ClientAuthFeature) Issuer(issuer security.Issuer) *ClientAuthFeature {
+	f.issuer = issuer
+	return f
+}
+
+// JwkStoreResolver is used to verify client assertions ("private_key_jwt") and self-signed certificates
+// ("self_signed_tls_client_auth"). Default to RemoteJwkStoreResolver
+func (f *ClientAuthFeature) JwkStoreResolver(resolver JwkStoreResolver) *ClientAuthFeature {
+	f.jwkStoreResolver = resolver
+	return f
+}
+
+// ReplayCache is used to reject replayed client assertions ("private_key_jwt"). It should be shared by all instances.
+// Default to replay.InMemoryCache
+func (f *ClientAuthFeature) ReplayCache(cache replay.Cache) *ClientAuthFeature {
+	f.replayCache = cache
+	return f
+}
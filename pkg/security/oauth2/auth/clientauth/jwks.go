@@ -0,0 +1,130 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package clientauth
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"sync"
+)
+
+// JwkStoreResolver resolves jwt.JwkStore of given client.
+// The resolved store is used to verify "private_key_jwt" client assertions and "self_signed_tls_client_auth" certificates
+type JwkStoreResolver interface {
+	Resolve(ctx context.Context, client oauth2.OAuth2Client) (jwt.JwkStore, error)
+}
+
+// JwkSetClient is an optional interface of oauth2.OAuth2Client, implemented by clients registered with JWK Set by value
+type JwkSetClient interface {
+	JwkSet() []jwt.Jwk
+}
+
+// RemoteJwkStoreResolver implements JwkStoreResolver.
+// It loads JWKs from client's JWK Set URI (see oauth2.ClientAuthMethodClient) and keeps one jwt.RemoteJwkStore per URI.
+// If the client has JWK Set registered by value (see JwkSetClient), the registered JWKs are used instead.
+type RemoteJwkStoreResolver struct {
+	mtx    sync.Mutex
+	stores map[string]jwt.JwkStore
+	opts   []jwt.RemoteJwkOptions
+}
+
+func NewRemoteJwkStoreResolver(opts ...jwt.RemoteJwkOptions) *RemoteJwkStoreResolver {
+	return &RemoteJwkStoreResolver{
+		stores: map[string]jwt.JwkStore{},
+		opts:   opts,
+	}
+}
+
+func (r *RemoteJwkStoreResolver) Resolve(_ context.Context, client oauth2.OAuth2Client) (jwt.JwkStore, error) {
+	if c, ok := client.(JwkSetClient); ok && len(c.JwkSet()) != 0 {
+		return singleKeyFallbackJwkStore{JwkStore: jwkSetStore(c.JwkSet())}, nil
+	}
+	c, ok := client.(oauth2.ClientAuthMethodClient)
+	if !ok || c.JwkSetUri() == "" {
+		return nil, fmt.Errorf("client [%s] doesn't have JWK Set URI", client.ClientId())
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	uri := c.JwkSetUri()
+	if store, ok := r.stores[uri]; ok {
+		return store, nil
+	}
+	opts := append([]jwt.RemoteJwkOptions{func(cfg *jwt.RemoteJwkConfig) {
+		cfg.JwkSetURL = uri
+	}}, r.opts...)
+	store := singleKeyFallbackJwkStore{JwkStore: jwt.NewRemoteJwkStore(opts...)}
+	r.stores[uri] = store
+	return store, nil
+}
+
+// StaticJwkStoreResolver implements JwkStoreResolver with pre-configured JwkStore per client ID. Useful for testing.
+type StaticJwkStoreResolver map[string]jwt.JwkStore
+
+func (r StaticJwkStoreResolver) Resolve(_ context.Context, client oauth2.OAuth2Client) (jwt.JwkStore, error) {
+	store, ok := r[client.ClientId()]
+	if !ok {
+		return nil, fmt.Errorf("client [%s] doesn't have JWK Set", client.ClientId())
+	}
+	return singleKeyFallbackJwkStore{JwkStore: store}, nil
+}
+
+// singleKeyFallbackJwkStore wraps a jwt.JwkStore. When JWT doesn't specify "kid", it uses the only JWK available.
+type singleKeyFallbackJwkStore struct {
+	jwt.JwkStore
+}
+
+func (s singleKeyFallbackJwkStore) LoadByName(ctx context.Context, name string) (jwt.Jwk, error) {
+	if name != "" {
+		return s.JwkStore.LoadByName(ctx, name)
+	}
+	jwks, e := s.JwkStore.LoadAll(ctx)
+	switch {
+	case e != nil:
+		return nil, e
+	case len(jwks) != 1:
+		return nil, fmt.Errorf(`"kid" is required when client has %d JWKs`, len(jwks))
+	}
+	return jwks[0], nil
+}
+
+// jwkSetStore implements jwt.JwkStore with fixed set of JWKs
+type jwkSetStore []jwt.Jwk
+
+func (s jwkSetStore) LoadByKid(_ context.Context, kid string) (jwt.Jwk, error) {
+	for _, jwk := range s {
+		if jwk.Id() == kid {
+			return jwk, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot find JWK with kid [%s]", kid)
+}
+
+func (s jwkSetStore) LoadByName(_ context.Context, name string) (jwt.Jwk, error) {
+	for _, jwk := range s {
+		if jwk.Name() == name {
+			return jwk, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot find JWK with name [%s]", name)
+}
+
+func (s jwkSetStore) LoadAll(_ context.Context, _ ...string) ([]jwt.Jwk, error) {
+	return s, nil
+}
@@ -18,18 +18,25 @@ package clientauth
 
 import (
     "context"
+    "encoding/base64"
+    "encoding/json"
     "errors"
     "github.com/cisco-open/go-lanai/pkg/security"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2"
     "github.com/cisco-open/go-lanai/pkg/security/passwd"
+    "github.com/cisco-open/go-lanai/pkg/utils"
     "github.com/cisco-open/go-lanai/pkg/web"
     "github.com/gin-gonic/gin"
     "net/http"
+    "net/url"
+    "strings"
 )
 
 type Middleware struct {
 	authenticator  security.Authenticator
 	successHandler security.AuthenticationSuccessHandler
+	clientStore    oauth2.OAuth2ClientStore
+	issuer         security.Issuer
 }
 
 type MWOptions func(*MWOption)
@@ -37,6 +44,10 @@ type MWOptions func(*MWOption)
 type MWOption struct {
 	Authenticator  security.Authenticator
 	SuccessHandler security.AuthenticationSuccessHandler
+	// ClientStore is required by certificate based client authentication
+	ClientStore oauth2.OAuth2ClientStore
+	// Issuer is used to determine acceptable audiences of client assertions
+	Issuer security.Issuer
 }
 
 func NewClientAuthMiddleware(opts...MWOptions) *Middleware {
@@ -50,6 +61,8 @@ func NewClientAuthMiddleware(opts...MWOptions) *Middleware {
 	return &Middleware{
 		authenticator:  opt.Authenticator,
 		successHandler: opt.SuccessHandler,
+		clientStore:    opt.ClientStore,
+		issuer:         opt.Issuer,
 	}
 }
 
@@ -60,7 +73,8 @@ func (mw *Middleware) ClientAuthFormHandlerFunc() http.HandlerFunc {
 		}
 
 		_, hasClientId := r.Form[oauth2.ParameterClientId]
-		if !hasClientId {
+		_, hasAssertion := r.PostForm[oauth2.ParameterClientAssertionType]
+		if !hasClientId || hasAssertion {
 			return
 		}
 
@@ -74,6 +88,9 @@ func (mw *Middleware) ClientAuthFormHandlerFunc() http.HandlerFunc {
 		switch {
 		case ok && passwd.IsSamePrincipal(clientId, currentAuth):
 			return
+		case isAuthenticatedClient(clientId, before):
+			// already authenticated by client certificate
+			return
 		case ok:
 			mw.handleError(r.Context(), oauth2.NewInvalidClientError("client_id parameter and Authorization header doesn't match"))
 		}
@@ -94,6 +111,94 @@ func (mw *Middleware) ClientAuthFormHandlerFunc() http.HandlerFunc {
 	}
 }
 
+// ClientAssertionHandlerFunc authenticates client using "private_key_jwt" method.
+// See https://datatracker.ietf.org/doc/html/rfc7523#section-2.2
+func (mw *Middleware) ClientAssertionHandlerFunc() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		if e := r.ParseForm(); e != nil {
+			return
+		}
+
+		assertionType, ok := r.PostForm[oauth2.ParameterClientAssertionType]
+		if !ok {
+			return
+		}
+
+		before := security.Get(r.Context())
+		assertion := r.PostForm.Get(oauth2.ParameterClientAssertion)
+		switch {
+		case len(assertionType) != 1 || assertionType[0] != oauth2.ClientAssertionTypeJwtBearer:
+			mw.handleError(r.Context(), oauth2.NewInvalidClientError("unsupported client_assertion_type"))
+			return
+		case assertion == "":
+			mw.handleError(r.Context(), oauth2.NewInvalidClientError("client_assertion is missing"))
+			return
+		case before.State() >= security.StateAuthenticated:
+			mw.handleError(r.Context(), oauth2.NewInvalidClientError("client used more than one authentication method"))
+			return
+		}
+
+		clientId := r.PostForm.Get(oauth2.ParameterClientId)
+		if clientId == "" {
+			clientId = unverifiedSubject(assertion)
+		}
+		candidate := ClientAssertion{
+			ClientId:   clientId,
+			Assertion:  assertion,
+			Audiences:  mw.acceptableAudiences(r),
+			DetailsMap: map[string]interface{}{},
+		}
+		auth, err := mw.authenticator.Authenticate(r.Context(), &candidate)
+		if err != nil {
+			mw.handleError(r.Context(), err)
+			return
+		}
+		mw.handleSuccess(r.Context(), r, rw, before, auth)
+	}
+}
+
+// ClientCertificateHandlerFunc authenticates client using "tls_client_auth" or "self_signed_tls_client_auth" method.
+// The handler only applies to clients registered with one of those methods, and requires "client_id" parameter.
+// See https://datatracker.ietf.org/doc/html/rfc8705#section-2
+func (mw *Middleware) ClientCertificateHandlerFunc() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 || mw.clientStore == nil {
+			return
+		}
+		if e := r.ParseForm(); e != nil {
+			return
+		}
+
+		clientId := r.Form.Get(oauth2.ParameterClientId)
+		if clientId == "" {
+			return
+		}
+		if client, e := mw.clientStore.LoadClientByClientId(r.Context(), clientId); e != nil || usesClientSecret(client) {
+			// let other client authentication to handle
+			return
+		}
+
+		before := security.Get(r.Context())
+		if before.State() >= security.StateAuthenticated {
+			mw.handleError(r.Context(), oauth2.NewInvalidClientError("client used more than one authentication method"))
+			return
+		}
+
+		candidate := ClientCertificate{
+			ClientId:      clientId,
+			Certificates:  r.TLS.PeerCertificates,
+			ChainVerified: len(r.TLS.VerifiedChains) != 0,
+			DetailsMap:    map[string]interface{}{},
+		}
+		auth, err := mw.authenticator.Authenticate(r.Context(), &candidate)
+		if err != nil {
+			mw.handleError(r.Context(), err)
+			return
+		}
+		mw.handleSuccess(r.Context(), r, rw, before, auth)
+	}
+}
+
 func (mw *Middleware) ErrorTranslationHandlerFunc() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
@@ -122,4 +227,47 @@ func (mw *Middleware) handleError(c context.Context, err error) {
 	security.MustClear(gc)
 	_ = gc.Error(err)
 	gc.Abort()
-}
\ No newline at end of file
+}
+
+// acceptableAudiences returns issuer identifier and URL of current endpoint
+func (mw *Middleware) acceptableAudiences(r *http.Request) utils.StringSet {
+	aud := utils.NewStringSet()
+	endpoint := url.URL{Scheme: "http", Host: r.Host, Path: r.URL.Path}
+	if r.TLS != nil {
+		endpoint.Scheme = "https"
+	}
+	if mw.issuer != nil {
+		id := mw.issuer.Identifier()
+		aud.Add(id)
+		if u, e := url.Parse(id); e == nil {
+			endpoint.Scheme = u.Scheme
+			endpoint.Host = u.Host
+		}
+	}
+	aud.Add(endpoint.String())
+	return aud
+}
+
+func isAuthenticatedClient(clientId string, auth security.Authentication) bool {
+	ca, ok := auth.(*clientAuthentication)
+	return ok && ca.Client.ClientId() == clientId
+}
+
+// unverifiedSubject extract "sub" claim from JWT without verification. The value is only used to look up client.
+func unverifiedSubject(jwtValue string) string {
+	parts := strings.Split(jwtValue, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, e := base64.RawURLEncoding.DecodeString(parts[1])
+	if e != nil {
+		return ""
+	}
+	var claims struct {
+		Subject string `json:"sub"`
+	}
+	if e := json.Unmarshal(payload, &claims); e != nil {
+		return ""
+	}
+	return claims.Subject
+}
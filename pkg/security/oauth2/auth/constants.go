@@ -22,6 +22,8 @@ const (
 	TokenEnhancerOrderBasicClaims
 	TokenEnhancerOrderDetailsClaims
 	TokenEnhancerOrderResourceIdClaims
+	TokenEnhancerOrderTokenExchangeClaims
+	TokenEnhancerOrderConfirmationClaims
 	TokenEnhancerOrderTokenDetails
 	TokenEnhancerOrderRefreshToken
 	//TokenEnhancerOrder
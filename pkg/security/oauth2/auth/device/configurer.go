@@ -0,0 +1,132 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/web/mapping"
+	"github.com/cisco-open/go-lanai/pkg/web/matcher"
+	"github.com/cisco-open/go-lanai/pkg/web/middleware"
+	"net/http"
+)
+
+var (
+	AuthorizationFeatureId = security.FeatureId("OAuth2DeviceAuthorization", security.FeatureOrderOAuth2TokenEndpoint)
+	VerificationFeatureId  = security.FeatureId("OAuth2DeviceVerification", security.FeatureOrderOAuth2AuthorizeEndpoint)
+)
+
+/*********************************
+	Device Authorization Endpoint
+ *********************************/
+
+type AuthorizationEndpointConfigurer struct{}
+
+func newDeviceAuthorizationEndpointConfigurer() *AuthorizationEndpointConfigurer {
+	return &AuthorizationEndpointConfigurer{}
+}
+
+func (c *AuthorizationEndpointConfigurer) Apply(feature security.Feature, ws security.WebSecurity) (err error) {
+	// Verify
+	f := feature.(*AuthorizationFeature)
+	if err := c.validate(f); err != nil {
+		return err
+	}
+
+	// prepare middlewares
+	mw := NewDeviceEndpointMiddleware(func(opt *DeviceMWOption) {
+		opt.DeviceCodeStore = f.deviceCodeStore
+		opt.Issuer = f.issuer
+		opt.VerificationPath = f.verificationPath
+		opt.CodeValidity = f.validity
+		opt.PollingInterval = f.interval
+	})
+
+	// install middlewares
+	authMapping := middleware.NewBuilder("device authorization endpoint").
+		ApplyTo(matcher.RouteWithPattern(f.path, http.MethodPost)).
+		Order(security.MWOrderOAuth2Endpoints).
+		Use(mw.DeviceAuthorizationHandlerFunc())
+
+	ws.Add(authMapping)
+
+	// add dummy handler
+	ws.Add(mapping.Post(f.path).HandlerFunc(security.NoopHandlerFunc()))
+	return nil
+}
+
+func (c *AuthorizationEndpointConfigurer) validate(f *AuthorizationFeature) error {
+	switch {
+	case f.path == "":
+		return fmt.Errorf("device authorization endpoint path is not set")
+	case f.verificationPath == "":
+		return fmt.Errorf("device verification path is not set")
+	case f.issuer == nil:
+		return fmt.Errorf("issuer is not set")
+	case f.deviceCodeStore == nil:
+		return fmt.Errorf("device code store is not set")
+	}
+	return nil
+}
+
+/*********************************
+	User Verification Endpoint
+ *********************************/
+
+type VerificationEndpointConfigurer struct{}
+
+func newDeviceVerificationEndpointConfigurer() *VerificationEndpointConfigurer {
+	return &VerificationEndpointConfigurer{}
+}
+
+func (c *VerificationEndpointConfigurer) Apply(feature security.Feature, ws security.WebSecurity) (err error) {
+	// Verify
+	f := feature.(*VerificationFeature)
+	if err := c.validate(f); err != nil {
+		return err
+	}
+
+	mw := NewDeviceEndpointMiddleware(func(opt *DeviceMWOption) {
+		opt.DeviceCodeStore = f.deviceCodeStore
+		opt.VerificationPath = f.path
+		opt.VerificationTmpl = f.verificationTmpl
+		opt.MaxVerificationAttempts = f.maxAttempts
+		opt.VerificationAttemptsWindow = f.attemptsWindow
+	})
+
+	// install verification page
+	routeMatcher := matcher.RouteWithPattern(f.path, http.MethodGet, http.MethodPost)
+	epGet := mapping.Get(f.path).Name("device verification GET").
+		HandlerFunc(mw.VerificationHandlerFunc())
+	epPost := mapping.Post(f.path).Name("device verification POST").
+		HandlerFunc(mw.VerificationHandlerFunc())
+
+	ws.Route(routeMatcher).Add(epGet, epPost)
+	return nil
+}
+
+func (c *VerificationEndpointConfigurer) validate(f *VerificationFeature) error {
+	switch {
+	case f.path == "":
+		return fmt.Errorf("device verification path is not set")
+	case f.verificationTmpl == "":
+		return fmt.Errorf("device verification template is not set")
+	case f.deviceCodeStore == nil:
+		return fmt.Errorf("device code store is not set")
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+	"time"
+)
+
+/*********************************
+	Device Authorization Endpoint
+ *********************************/
+
+// AuthorizationFeature configures device authorization endpoint of OAuth2 Device Authorization Grant.
+// The endpoint requires client authentication.
+// See https://datatracker.ietf.org/doc/html/rfc8628#section-3.1
+type AuthorizationFeature struct {
+	path             string
+	verificationPath string
+	issuer           security.Issuer
+	deviceCodeStore  auth.DeviceCodeStore
+	validity         time.Duration
+	interval         time.Duration
+}
+
+func (f *AuthorizationFeature) Identifier() security.FeatureIdentifier {
+	return AuthorizationFeatureId
+}
+
+// ConfigureAuthorization is standard security.Feature entrypoint
+func ConfigureAuthorization(ws security.WebSecurity) *AuthorizationFeature {
+	feature := NewAuthorizationEndpoint()
+	if fc, ok := ws.(security.FeatureModifier); ok {
+		return fc.Enable(feature).(*AuthorizationFeature)
+	}
+	panic(fmt.Errorf("unable to configure oauth2 authserver: provided WebSecurity [%T] doesn't support FeatureModifier", ws))
+}
+
+// NewAuthorizationEndpoint is standard security.Feature entrypoint, DSL style. Used with security.WebSecurity
+func NewAuthorizationEndpoint() *AuthorizationFeature {
+	return &AuthorizationFeature{
+		validity: 10 * time.Minute,
+		interval: 5 * time.Second,
+	}
+}
+
+/** Setters **/
+
+func (f *AuthorizationFeature) Path(path string) *AuthorizationFeature {
+	f.path = path
+	return f
+}
+
+// VerificationPath is the path of user verification page, used to build "verification_uri" of the response
+func (f *AuthorizationFeature) VerificationPath(path string) *AuthorizationFeature {
+	f.verificationPath = path
+	return f
+}
+
+func (f *AuthorizationFeature) Issuer(issuer security.Issuer) *AuthorizationFeature {
+	f.issuer = issuer
+	return f
+}
+
+func (f *AuthorizationFeature) DeviceCodeStore(store auth.DeviceCodeStore) *AuthorizationFeature {
+	f.deviceCodeStore = store
+	return f
+}
+
+// CodeValidity is the lifetime of device code and user code
+func (f *AuthorizationFeature) CodeValidity(validity time.Duration) *AuthorizationFeature {
+	f.validity = validity
+	return f
+}
+
+// PollingInterval is the minimum amount of time that the client should wait between polling requests
+func (f *AuthorizationFeature) PollingInterval(interval time.Duration) *AuthorizationFeature {
+	f.interval = interval
+	return f
+}
+
+/*********************************
+	User Verification Endpoint
+ *********************************/
+
+// VerificationFeature configures end-user verification page of OAuth2 Device Authorization Grant.
+// The page requires user authentication.
+// See https://datatracker.ietf.org/doc/html/rfc8628#section-3.3
+type VerificationFeature struct {
+	path             string
+	verificationTmpl string
+	deviceCodeStore  auth.DeviceCodeStore
+	maxAttempts      int
+	attemptsWindow   time.Duration
+}
+
+func (f *VerificationFeature) Identifier() security.FeatureIdentifier {
+	return VerificationFeatureId
+}
+
+// ConfigureVerification is standard security.Feature entrypoint
+func ConfigureVerification(ws security.WebSecurity) *VerificationFeature {
+	feature := NewVerificationEndpoint()
+	if fc, ok := ws.(security.FeatureModifier); ok {
+		return fc.Enable(feature).(*VerificationFeature)
+	}
+	panic(fmt.Errorf("unable to configure oauth2 authserver: provided WebSecurity [%T] doesn't support FeatureModifier", ws))
+}
+
+// NewVerificationEndpoint is standard security.Feature entrypoint, DSL style. Used with security.WebSecurity
+func NewVerificationEndpoint() *VerificationFeature {
+	return &VerificationFeature{
+		verificationTmpl: "device_verify.tmpl",
+		maxAttempts:      5,
+		attemptsWindow:   15 * time.Minute,
+	}
+}
+
+/** Setters **/
+
+func (f *VerificationFeature) Path(path string) *VerificationFeature {
+	f.path = path
+	return f
+}
+
+func (f *VerificationFeature) VerificationTemplate(tmpl string) *VerificationFeature {
+	f.verificationTmpl = tmpl
+	return f
+}
+
+func (f *VerificationFeature) DeviceCodeStore(store auth.DeviceCodeStore) *VerificationFeature {
+	f.deviceCodeStore = store
+	return f
+}
+
+// MaxAttempts is the max number of invalid user codes a user can enter within given window.
+// Zero or negative value disables the throttling
+func (f *VerificationFeature) MaxAttempts(max int, window time.Duration) *VerificationFeature {
+	f.maxAttempts = max
+	f.attemptsWindow = window
+	return f
+}
@@ -0,0 +1,302 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+	"github.com/cisco-open/go-lanai/pkg/web/template"
+	"github.com/gin-gonic/gin"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	ModelKeyVerificationUrl = "VerificationUrl"
+	ModelKeyUserCode        = "UserCode"
+	ModelKeyDeviceRequest   = "DeviceRequest"
+	ModelKeyDeviceStatus    = "DeviceStatus"
+)
+
+var (
+	errInvalidUserCode  = errors.New("the code you entered is invalid or expired")
+	errTooManyUserCodes = errors.New("too many invalid codes were entered, please try again later")
+)
+
+/***********************
+	Device Endpoints
+ ***********************/
+
+// DeviceEndpointMiddleware implements device authorization endpoint and user verification page
+// of OAuth2 Device Authorization Grant.
+// See https://datatracker.ietf.org/doc/html/rfc8628
+type DeviceEndpointMiddleware struct {
+	deviceCodeStore  auth.DeviceCodeStore
+	issuer           security.Issuer
+	verificationPath string
+	verificationTmpl string
+	validity         time.Duration
+	interval         time.Duration
+	maxAttempts      int
+	attemptsWindow   time.Duration
+}
+
+type DeviceMWOptions func(*DeviceMWOption)
+
+type DeviceMWOption struct {
+	DeviceCodeStore  auth.DeviceCodeStore
+	Issuer           security.Issuer
+	VerificationPath string
+	VerificationTmpl string
+	CodeValidity     time.Duration
+	PollingInterval  time.Duration
+	// MaxVerificationAttempts is max number of invalid user codes a user can submit within VerificationAttemptsWindow.
+	// Zero or negative value disables the throttling
+	MaxVerificationAttempts    int
+	VerificationAttemptsWindow time.Duration
+}
+
+func NewDeviceEndpointMiddleware(opts ...DeviceMWOptions) *DeviceEndpointMiddleware {
+	opt := DeviceMWOption{
+		VerificationTmpl:           "device_verify.tmpl",
+		CodeValidity:               10 * time.Minute,
+		PollingInterval:            5 * time.Second,
+		MaxVerificationAttempts:    5,
+		VerificationAttemptsWindow: 15 * time.Minute,
+	}
+	for _, fn := range opts {
+		if fn != nil {
+			fn(&opt)
+		}
+	}
+	return &DeviceEndpointMiddleware{
+		deviceCodeStore:  opt.DeviceCodeStore,
+		issuer:           opt.Issuer,
+		verificationPath: opt.VerificationPath,
+		verificationTmpl: opt.VerificationTmpl,
+		validity:         opt.CodeValidity,
+		interval:         opt.PollingInterval,
+		maxAttempts:      opt.MaxVerificationAttempts,
+		attemptsWindow:   opt.VerificationAttemptsWindow,
+	}
+}
+
+// DeviceAuthorizationHandlerFunc handles device authorization request.
+// See https://datatracker.ietf.org/doc/html/rfc8628#section-3.1
+func (mw *DeviceEndpointMiddleware) DeviceAuthorizationHandlerFunc() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// first we double check if client is authenticated
+		client := auth.RetrieveAuthenticatedClient(ctx)
+		if client == nil {
+			mw.handleError(ctx, oauth2.NewClientNotFoundError("invalid client"))
+			return
+		}
+
+		// parse request, device authorization request has same format as token request without grant type
+		request, e := auth.ParseTokenRequest(ctx.Request)
+		if e != nil {
+			mw.handleError(ctx, oauth2.NewInvalidTokenRequestError("invalid device authorization request", e))
+			return
+		}
+		if request.ClientId != "" && request.ClientId != client.ClientId() {
+			mw.handleError(ctx, oauth2.NewInvalidTokenRequestError("given client Domain does not match authenticated client"))
+			return
+		}
+		request.GrantType = oauth2.GrantTypeDeviceCode
+		delete(request.Extensions, oauth2.ParameterClientSecret)
+
+		// check grant and scopes
+		if e := auth.ValidateGrant(ctx, client, request.GrantType); e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+		if len(request.Scopes) == 0 {
+			request.Scopes = client.Scopes()
+		}
+		if e := auth.ValidateAllScopes(ctx, client, request.Scopes); e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+
+		// generate and save device authorization. The request is not approved until user verification
+		oauthRequest := request.OAuth2Request(client).NewOAuth2Request(func(opt *oauth2.RequestDetails) {
+			opt.Approved = false
+		})
+		da, e := mw.deviceCodeStore.GenerateDeviceAuthorization(ctx, oauthRequest, mw.validity, mw.interval)
+		if e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+
+		verificationUri, e := mw.verificationUri(ctx)
+		if e != nil {
+			mw.handleError(ctx, oauth2.NewInternalError("unable to resolve verification URI", e))
+			return
+		}
+		complete := *verificationUri
+		complete.RawQuery = url.Values{oauth2.ParameterUserCode: []string{da.UserCode}}.Encode()
+
+		logger.WithContext(ctx).Debugf("device authorization issued for client [%s] with user_code=%s", client.ClientId(), da.UserCode)
+		mw.handleSuccess(ctx, map[string]interface{}{
+			oauth2.JsonFieldDeviceCode:              da.DeviceCode,
+			oauth2.JsonFieldUserCode:                da.UserCode,
+			oauth2.JsonFieldVerificationUri:         verificationUri.String(),
+			oauth2.JsonFieldVerificationUriComplete: complete.String(),
+			oauth2.JsonFieldExpiresIn:               da.ExpiresIn(),
+			oauth2.JsonFieldInterval:                int(da.Interval.Seconds()),
+		})
+	}
+}
+
+// VerificationHandlerFunc handles user interaction of device authorization. It supports:
+//   - GET without user_code: render a page for user to enter user code
+//   - GET/POST with user_code: render a page for user to approve or deny the authorization
+//   - POST with user_code and user_oauth_approval: approve or deny the authorization
+//
+// See https://datatracker.ietf.org/doc/html/rfc8628#section-3.3
+func (mw *DeviceEndpointMiddleware) VerificationHandlerFunc() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user := security.Get(ctx)
+		if user.State() < security.StateAuthenticated {
+			mw.handleError(ctx, oauth2.NewInternalError("device verification page is called without user authentication"))
+			return
+		}
+
+		_ = ctx.Request.ParseForm()
+		userCode := ctx.Request.Form.Get(oauth2.ParameterUserCode)
+		model := template.Model{
+			ModelKeyVerificationUrl: mw.verificationPath,
+			ModelKeyUserCode:        userCode,
+		}
+		if userCode == "" {
+			mw.render(ctx, model)
+			return
+		}
+
+		// user codes are short, we throttle invalid submissions per user to prevent brute-forcing
+		username, _ := security.GetUsername(user)
+		if e := mw.checkAttempts(ctx, username); e != nil {
+			model[template.ModelKeyError] = e
+			mw.render(ctx, model)
+			return
+		}
+
+		da, e := mw.deviceCodeStore.LoadDeviceAuthorizationByUserCode(ctx, userCode)
+		if e != nil || da.Expired() || da.Status != auth.DeviceAuthorizationPending {
+			mw.recordFailedAttempt(ctx, username)
+			model[template.ModelKeyError] = errInvalidUserCode
+			mw.render(ctx, model)
+			return
+		}
+		model[ModelKeyUserCode] = da.UserCode
+		model[ModelKeyDeviceRequest] = da.Request
+
+		approval, ok := ctx.Request.PostForm[oauth2.ParameterUserApproval]
+		if ctx.Request.Method != http.MethodPost || !ok || len(approval) == 0 {
+			mw.render(ctx, model)
+			return
+		}
+
+		if approved, _ := strconv.ParseBool(approval[len(approval)-1]); approved {
+			da.Status = auth.DeviceAuthorizationApproved
+			da.UserAuth = auth.ConvertToOAuthUserAuthentication(user)
+			da.Request = da.Request.NewOAuth2Request(func(opt *oauth2.RequestDetails) {
+				opt.Approved = true
+			})
+		} else {
+			da.Status = auth.DeviceAuthorizationDenied
+		}
+		if e := mw.deviceCodeStore.SaveDeviceAuthorization(ctx, da); e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+		model[ModelKeyDeviceStatus] = da.Status
+		mw.render(ctx, model)
+	}
+}
+
+func (mw *DeviceEndpointMiddleware) handleSuccess(c *gin.Context, v interface{}) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.JSON(http.StatusOK, v)
+	c.Abort()
+}
+
+func (mw *DeviceEndpointMiddleware) handleError(c *gin.Context, err error) {
+	if !errors.Is(err, oauth2.ErrorTypeOAuth2) {
+		err = oauth2.NewInvalidTokenRequestError(err)
+	}
+
+	_ = c.Error(err)
+	c.Abort()
+}
+
+func (mw *DeviceEndpointMiddleware) checkAttempts(c *gin.Context, username string) error {
+	if mw.maxAttempts <= 0 {
+		return nil
+	}
+	count, e := mw.deviceCodeStore.UserCodeFailures(c, username)
+	switch {
+	case e != nil:
+		// we don't want to allow unlimited attempts when the store is not available
+		logger.WithContext(c).Warnf("unable to check user code attempts: %v", e)
+		return errTooManyUserCodes
+	case count >= mw.maxAttempts:
+		return errTooManyUserCodes
+	}
+	return nil
+}
+
+func (mw *DeviceEndpointMiddleware) recordFailedAttempt(c *gin.Context, username string) {
+	if mw.maxAttempts <= 0 {
+		return
+	}
+	if _, e := mw.deviceCodeStore.RecordUserCodeFailure(c, username, mw.attemptsWindow); e != nil {
+		logger.WithContext(c).Warnf("unable to record invalid user code attempt: %v", e)
+	}
+}
+
+func (mw *DeviceEndpointMiddleware) render(c *gin.Context, model template.Model) {
+	mv := template.ModelView{
+		View:  mw.verificationTmpl,
+		Model: model,
+	}
+	_ = template.TemplateEncodeResponseFunc(c, c.Writer, &mv)
+	c.Abort()
+}
+
+// verificationUri build absolute verification URI on the same domain as the device authorization request
+func (mw *DeviceEndpointMiddleware) verificationUri(c *gin.Context) (*url.URL, error) {
+	host := c.Request.Host
+	if h, _, e := net.SplitHostPort(host); e == nil {
+		host = h
+	}
+	uri, e := mw.issuer.BuildUrl(func(opt *security.UrlBuilderOption) {
+		opt.FQDN = host
+		opt.Path = mw.verificationPath
+	})
+	if e != nil {
+		return nil, fmt.Errorf("invalid verification path [%s]: %v", mw.verificationPath, e)
+	}
+	return uri, nil
+}
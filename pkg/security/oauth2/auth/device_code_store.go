@@ -0,0 +1,335 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/redis"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	goredis "github.com/go-redis/redis/v8"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDeviceCodeLength = 40
+	defaultUserCodeLength   = 8
+	deviceCodePrefix        = "DC"
+	devicePollingPrefix     = "DCP"
+	userCodePrefix          = "UC"
+	userCodeFailurePrefix   = "UCF"
+	// userCodeCharset is the base-20 charset recommended by RFC 8628 section 6.1. It contains no vowels to avoid
+	// generating real words and is case-insensitive
+	userCodeCharset utils.RandomCharset = "BCDFGHJKLMNPQRSTVWXZ"
+)
+
+var (
+	// deviceCodeRetention is how long an expired device code is kept, so that polling clients would get
+	// "expired_token" instead of "invalid_grant"
+	deviceCodeRetention = 5 * time.Minute
+)
+
+type DeviceAuthorizationStatus string
+
+const (
+	DeviceAuthorizationPending  DeviceAuthorizationStatus = "pending"
+	DeviceAuthorizationApproved DeviceAuthorizationStatus = "approved"
+	DeviceAuthorizationDenied   DeviceAuthorizationStatus = "denied"
+)
+
+// DeviceAuthorization is the server side state of a single OAuth2 Device Authorization Grant flow.
+// See https://datatracker.ietf.org/doc/html/rfc8628
+type DeviceAuthorization struct {
+	DeviceCode string                    `json:"deviceCode"`
+	UserCode   string                    `json:"userCode"`
+	Status     DeviceAuthorizationStatus `json:"status"`
+	Request    oauth2.OAuth2Request      `json:"request"`
+	UserAuth   oauth2.UserAuthentication `json:"userAuth"`
+	ExpireAt   time.Time                 `json:"expireAt"`
+	Interval   time.Duration             `json:"interval"`
+	LastPolled time.Time                 `json:"lastPolled"`
+}
+
+func (da *DeviceAuthorization) Expired() bool {
+	return !da.ExpireAt.IsZero() && time.Now().After(da.ExpireAt)
+}
+
+// ExpiresIn returns remaining validity in seconds
+func (da *DeviceAuthorization) ExpiresIn() int {
+	if da.Expired() {
+		return 0
+	}
+	return int(time.Until(da.ExpireAt).Seconds())
+}
+
+/**********************
+	Abstraction
+ **********************/
+
+// DeviceCodeStore persists DeviceAuthorization between the device authorization endpoint, the user verification page
+// and the token endpoint.
+type DeviceCodeStore interface {
+	// GenerateDeviceAuthorization creates and saves a pending DeviceAuthorization with newly generated device code and user code.
+	GenerateDeviceAuthorization(ctx context.Context, request oauth2.OAuth2Request, validity time.Duration, interval time.Duration) (*DeviceAuthorization, error)
+	// LoadDeviceAuthorization load DeviceAuthorization by device code
+	LoadDeviceAuthorization(ctx context.Context, deviceCode string) (*DeviceAuthorization, error)
+	// LoadDeviceAuthorizationByUserCode load DeviceAuthorization by user code. User code is case-insensitive and
+	// any non-alphabetic characters are ignored
+	LoadDeviceAuthorizationByUserCode(ctx context.Context, userCode string) (*DeviceAuthorization, error)
+	// SaveDeviceAuthorization updates an existing DeviceAuthorization. The expiry time should not be changed
+	SaveDeviceAuthorization(ctx context.Context, da *DeviceAuthorization) error
+	// SaveDevicePolling updates only LastPolled and Interval of given DeviceAuthorization.
+	// It never overwrites Status or other fields, which could be changed concurrently by the user verification page.
+	SaveDevicePolling(ctx context.Context, da *DeviceAuthorization) error
+	// RemoveDeviceAuthorization removes given DeviceAuthorization. Both device code and user code become invalid
+	RemoveDeviceAuthorization(ctx context.Context, da *DeviceAuthorization) error
+	// ConsumeDeviceAuthorization atomically removes given DeviceAuthorization before it's redeemed.
+	// Only one of concurrent callers succeeds, others get an invalid grant error.
+	ConsumeDeviceAuthorization(ctx context.Context, da *DeviceAuthorization) error
+	// UserCodeFailures returns number of failed user code submissions of given subject in current window
+	UserCodeFailures(ctx context.Context, subject string) (int, error)
+	// RecordUserCodeFailure increments number of failed user code submissions of given subject.
+	// The window starts at the first failure and lasts for given duration
+	RecordUserCodeFailure(ctx context.Context, subject string, window time.Duration) (int, error)
+}
+
+/**********************
+	Redis Impl
+ **********************/
+
+// RedisDeviceCodeStore store DeviceAuthorization in Redis
+type RedisDeviceCodeStore struct {
+	redisClient redis.Client
+}
+
+func NewRedisDeviceCodeStore(ctx context.Context, cf redis.ClientFactory, dbIndex int) *RedisDeviceCodeStore {
+	client, e := cf.New(ctx, func(opt *redis.ClientOption) {
+		opt.DbIndex = dbIndex
+	})
+	if e != nil {
+		panic(e)
+	}
+
+	return &RedisDeviceCodeStore{
+		redisClient: client,
+	}
+}
+
+func (s *RedisDeviceCodeStore) GenerateDeviceAuthorization(ctx context.Context, request oauth2.OAuth2Request, validity time.Duration, interval time.Duration) (*DeviceAuthorization, error) {
+	userCode, e := s.newUserCode(ctx)
+	if e != nil {
+		return nil, oauth2.NewInternalError(e)
+	}
+
+	da := &DeviceAuthorization{
+		DeviceCode: utils.RandomStringWithCharset(defaultDeviceCodeLength, utils.CharsetAlphanumeric),
+		UserCode:   userCode,
+		Status:     DeviceAuthorizationPending,
+		Request:    request,
+		ExpireAt:   time.Now().Add(validity),
+		Interval:   interval,
+	}
+	if e := s.save(ctx, da); e != nil {
+		return nil, oauth2.NewInternalError(e)
+	}
+	return da, nil
+}
+
+func (s *RedisDeviceCodeStore) LoadDeviceAuthorization(ctx context.Context, deviceCode string) (*DeviceAuthorization, error) {
+	cmd := s.redisClient.Get(ctx, s.deviceCodeRedisKey(deviceCode))
+	if cmd.Err() != nil {
+		return nil, oauth2.NewInvalidGrantError(fmt.Sprintf("device code [%s] is not valid", deviceCode))
+	}
+
+	toLoad := DeviceAuthorization{
+		Request:  oauth2.NewOAuth2Request(),
+		UserAuth: oauth2.NewUserAuthentication(),
+	}
+	if e := json.Unmarshal([]byte(cmd.Val()), &toLoad); e != nil {
+		return nil, oauth2.NewInvalidGrantError(fmt.Sprintf("device code [%s] is not valid", deviceCode), e)
+	}
+
+	// polling state is stored separately, see SaveDevicePolling
+	pollCmd := s.redisClient.Get(ctx, s.devicePollingRedisKey(deviceCode))
+	switch {
+	case errors.Is(pollCmd.Err(), goredis.Nil):
+	case pollCmd.Err() != nil:
+		return nil, oauth2.NewInternalError(pollCmd.Err())
+	default:
+		var polling devicePolling
+		if e := json.Unmarshal([]byte(pollCmd.Val()), &polling); e != nil {
+			return nil, oauth2.NewInternalError(e)
+		}
+		toLoad.LastPolled = polling.LastPolled
+		toLoad.Interval = polling.Interval
+	}
+	return &toLoad, nil
+}
+
+func (s *RedisDeviceCodeStore) LoadDeviceAuthorizationByUserCode(ctx context.Context, userCode string) (*DeviceAuthorization, error) {
+	cmd := s.redisClient.Get(ctx, s.userCodeRedisKey(userCode))
+	if cmd.Err() != nil {
+		return nil, oauth2.NewInvalidGrantError(fmt.Sprintf("user code [%s] is not valid", userCode))
+	}
+	return s.LoadDeviceAuthorization(ctx, cmd.Val())
+}
+
+func (s *RedisDeviceCodeStore) SaveDeviceAuthorization(ctx context.Context, da *DeviceAuthorization) error {
+	if e := s.save(ctx, da); e != nil {
+		return oauth2.NewInternalError(e)
+	}
+	return nil
+}
+
+func (s *RedisDeviceCodeStore) SaveDevicePolling(ctx context.Context, da *DeviceAuthorization) error {
+	ttl := time.Until(da.ExpireAt) + deviceCodeRetention
+	if ttl <= 0 {
+		return oauth2.NewInternalError(fmt.Sprintf("device code [%s] already expired", da.DeviceCode))
+	}
+	toSave, e := json.Marshal(devicePolling{LastPolled: da.LastPolled, Interval: da.Interval})
+	if e != nil {
+		return oauth2.NewInternalError(e)
+	}
+	if cmd := s.redisClient.Set(ctx, s.devicePollingRedisKey(da.DeviceCode), toSave, ttl); cmd.Err() != nil {
+		return oauth2.NewInternalError(cmd.Err())
+	}
+	return nil
+}
+
+func (s *RedisDeviceCodeStore) RemoveDeviceAuthorization(ctx context.Context, da *DeviceAuthorization) error {
+	cmd := s.redisClient.Del(ctx, s.deviceCodeRedisKey(da.DeviceCode), s.userCodeRedisKey(da.UserCode), s.devicePollingRedisKey(da.DeviceCode))
+	if cmd.Err() != nil {
+		return oauth2.NewInternalError(cmd.Err())
+	}
+	return nil
+}
+
+func (s *RedisDeviceCodeStore) ConsumeDeviceAuthorization(ctx context.Context, da *DeviceAuthorization) error {
+	// DEL is atomic, only one caller would see the key deleted
+	cmd := s.redisClient.Del(ctx, s.deviceCodeRedisKey(da.DeviceCode))
+	switch {
+	case cmd.Err() != nil:
+		return oauth2.NewInternalError(cmd.Err())
+	case cmd.Val() == 0:
+		return oauth2.NewInvalidGrantError(fmt.Sprintf("device code [%s] is already used", da.DeviceCode))
+	}
+	if cmd := s.redisClient.Del(ctx, s.userCodeRedisKey(da.UserCode), s.devicePollingRedisKey(da.DeviceCode)); cmd.Err() != nil {
+		logger.WithContext(ctx).Warnf("user code was not removed: %v", cmd.Err())
+	}
+	return nil
+}
+
+func (s *RedisDeviceCodeStore) UserCodeFailures(ctx context.Context, subject string) (int, error) {
+	cmd := s.redisClient.Get(ctx, s.userCodeFailureRedisKey(subject))
+	switch {
+	case errors.Is(cmd.Err(), goredis.Nil):
+		return 0, nil
+	case cmd.Err() != nil:
+		return 0, cmd.Err()
+	}
+	return cmd.Int()
+}
+
+func (s *RedisDeviceCodeStore) RecordUserCodeFailure(ctx context.Context, subject string, window time.Duration) (int, error) {
+	key := s.userCodeFailureRedisKey(subject)
+	cmd := s.redisClient.Incr(ctx, key)
+	if cmd.Err() != nil {
+		return 0, cmd.Err()
+	}
+	if cmd.Val() == 1 {
+		if e := s.redisClient.Expire(ctx, key, window).Err(); e != nil {
+			return 0, e
+		}
+	}
+	return int(cmd.Val()), nil
+}
+
+/**********************
+	Helpers
+ **********************/
+
+// devicePolling is the polling state of a DeviceAuthorization, stored separately from the DeviceAuthorization
+type devicePolling struct {
+	LastPolled time.Time     `json:"lastPolled"`
+	Interval   time.Duration `json:"interval"`
+}
+
+func (s *RedisDeviceCodeStore) save(ctx context.Context, da *DeviceAuthorization) error {
+	ttl := time.Until(da.ExpireAt) + deviceCodeRetention
+	if ttl <= 0 {
+		return fmt.Errorf("device code [%s] already expired", da.DeviceCode)
+	}
+
+	toSave, e := json.Marshal(da)
+	if e != nil {
+		return e
+	}
+
+	if cmd := s.redisClient.Set(ctx, s.deviceCodeRedisKey(da.DeviceCode), toSave, ttl); cmd.Err() != nil {
+		return cmd.Err()
+	}
+	if cmd := s.redisClient.Set(ctx, s.userCodeRedisKey(da.UserCode), da.DeviceCode, ttl); cmd.Err() != nil {
+		return cmd.Err()
+	}
+	return nil
+}
+
+// newUserCode generate a user code that is not currently in use, formatted as "XXXX-XXXX"
+func (s *RedisDeviceCodeStore) newUserCode(ctx context.Context) (string, error) {
+	for i := 0; i < 5; i++ {
+		code := utils.RandomStringWithCharset(defaultUserCodeLength, userCodeCharset)
+		code = code[:defaultUserCodeLength/2] + "-" + code[defaultUserCodeLength/2:]
+		if cmd := s.redisClient.Exists(ctx, s.userCodeRedisKey(code)); cmd.Err() == nil && cmd.Val() == 0 {
+			return code, nil
+		}
+	}
+	return "", fmt.Errorf("unable to generate unique user code")
+}
+
+func (s *RedisDeviceCodeStore) deviceCodeRedisKey(code string) string {
+	return fmt.Sprintf("%s:%s", deviceCodePrefix, code)
+}
+
+func (s *RedisDeviceCodeStore) devicePollingRedisKey(code string) string {
+	return fmt.Sprintf("%s:%s", devicePollingPrefix, code)
+}
+
+func (s *RedisDeviceCodeStore) userCodeFailureRedisKey(subject string) string {
+	return fmt.Sprintf("%s:%s", userCodeFailurePrefix, subject)
+}
+
+func (s *RedisDeviceCodeStore) userCodeRedisKey(code string) string {
+	return fmt.Sprintf("%s:%s", userCodePrefix, NormalizeUserCode(code))
+}
+
+// NormalizeUserCode converts user entered user code into its canonical form: upper case without separators
+func NormalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z':
+			return r
+		default:
+			return -1
+		}
+	}, code)
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/redis"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/apptest"
+	"github.com/cisco-open/go-lanai/test/embedded"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"go.uber.org/fx"
+	"testing"
+	"time"
+)
+
+/*************************
+	Setup Test
+ *************************/
+
+type DeviceCodeStoreTestDI struct {
+	fx.In
+	ClientFactory redis.ClientFactory
+}
+
+/*************************
+	Tests
+ *************************/
+
+func TestRedisDeviceCodeStore(t *testing.T) {
+	di := &DeviceCodeStoreTestDI{}
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		embedded.WithRedis(),
+		apptest.WithModules(redis.Module),
+		apptest.WithDI(di),
+		test.GomegaSubTest(SubTestPollingWithConcurrentApproval(di), "TestPollingWithConcurrentApproval"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestPollingWithConcurrentApproval(di *DeviceCodeStoreTestDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		store := NewRedisDeviceCodeStore(ctx, di.ClientFactory, 0)
+		da, e := store.GenerateDeviceAuthorization(ctx, oauth2.NewOAuth2Request(func(opt *oauth2.RequestDetails) {
+			opt.ClientId = "test-client"
+		}), time.Minute, 5*time.Second)
+		g.Expect(e).To(Succeed(), "generating device authorization should not fail")
+
+		// token endpoint loads the device authorization before user approves it
+		polling, e := store.LoadDeviceAuthorization(ctx, da.DeviceCode)
+		g.Expect(e).To(Succeed(), "loading device authorization should not fail")
+
+		// user approves it on verification page
+		approving, e := store.LoadDeviceAuthorizationByUserCode(ctx, da.UserCode)
+		g.Expect(e).To(Succeed(), "loading device authorization by user code should not fail")
+		approving.Status = DeviceAuthorizationApproved
+		g.Expect(store.SaveDeviceAuthorization(ctx, approving)).To(Succeed(), "saving approval should not fail")
+
+		// token endpoint records polling with stale copy
+		polling.LastPolled = time.Now().Truncate(time.Second)
+		polling.Interval = 10 * time.Second
+		g.Expect(store.SaveDevicePolling(ctx, polling)).To(Succeed(), "saving polling should not fail")
+
+		loaded, e := store.LoadDeviceAuthorization(ctx, da.DeviceCode)
+		g.Expect(e).To(Succeed(), "loading device authorization should not fail")
+		g.Expect(loaded.Status).To(Equal(DeviceAuthorizationApproved), "approval should not be overwritten by polling")
+		g.Expect(loaded.LastPolled.Equal(polling.LastPolled)).To(BeTrue(), "last polled time should be updated")
+		g.Expect(loaded.Interval).To(Equal(10*time.Second), "polling interval should be updated")
+
+		// polling state is removed together with device code
+		g.Expect(store.ConsumeDeviceAuthorization(ctx, loaded)).To(Succeed(), "consuming device authorization should not fail")
+		_, e = store.LoadDeviceAuthorization(ctx, da.DeviceCode)
+		g.Expect(e).To(HaveOccurred(), "consumed device code should not be loaded")
+		exists, e := store.redisClient.Exists(ctx, store.devicePollingRedisKey(da.DeviceCode)).Result()
+		g.Expect(e).To(Succeed(), "checking polling key should not fail")
+		g.Expect(exists).To(BeZero(), "polling state should be removed")
+	}
+}
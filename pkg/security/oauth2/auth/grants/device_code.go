@@ -0,0 +1,153 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grants
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"time"
+)
+
+const (
+	// slowDownIncrement is how much the polling interval is increased each time the client polls too fast.
+	// See https://datatracker.ietf.org/doc/html/rfc8628#section-3.5
+	slowDownIncrement = 5 * time.Second
+)
+
+var (
+	deviceCodeIgnoreParams = utils.NewStringSet(
+		oauth2.ParameterClientSecret,
+		oauth2.ParameterDeviceCode,
+	)
+)
+
+// DeviceCodeGranter implements auth.TokenGranter
+// It's the polling part of OAuth2 Device Authorization Grant.
+// See https://datatracker.ietf.org/doc/html/rfc8628#section-3.4
+type DeviceCodeGranter struct {
+	authService     auth.AuthorizationService
+	deviceCodeStore auth.DeviceCodeStore
+}
+
+func NewDeviceCodeGranter(authService auth.AuthorizationService, deviceCodeStore auth.DeviceCodeStore) *DeviceCodeGranter {
+	if authService == nil {
+		panic(fmt.Errorf("cannot create DeviceCodeGranter without auth service"))
+	}
+
+	if deviceCodeStore == nil {
+		panic(fmt.Errorf("cannot create DeviceCodeGranter without device code store"))
+	}
+
+	return &DeviceCodeGranter{
+		authService:     authService,
+		deviceCodeStore: deviceCodeStore,
+	}
+}
+
+func (g *DeviceCodeGranter) Grant(ctx context.Context, request *auth.TokenRequest) (oauth2.AccessToken, error) {
+	if oauth2.GrantTypeDeviceCode != request.GrantType {
+		return nil, nil
+	}
+
+	client := auth.RetrieveAuthenticatedClient(ctx)
+
+	// common check
+	if e := auth.ValidateGrant(ctx, client, request.GrantType); e != nil {
+		return nil, e
+	}
+
+	// load device authorization using device code
+	code, ok := request.Parameters[oauth2.ParameterDeviceCode]
+	if !ok || code == "" {
+		return nil, oauth2.NewInvalidTokenRequestError(fmt.Sprintf("missing required parameter %s", oauth2.ParameterDeviceCode))
+	}
+
+	da, e := g.deviceCodeStore.LoadDeviceAuthorization(ctx, code)
+	if e != nil {
+		return nil, e
+	}
+
+	// check client ID
+	if da.Request.ClientId() != client.ClientId() {
+		return nil, oauth2.NewInvalidGrantError("client ID mismatch")
+	}
+
+	if da.Expired() {
+		_ = g.deviceCodeStore.RemoveDeviceAuthorization(ctx, da)
+		return nil, oauth2.NewExpiredTokenError("device code expired")
+	}
+
+	// polling rate and status
+	if e := g.checkPolling(ctx, da); e != nil {
+		return nil, e
+	}
+
+	// approved, device code is consumed. Only one of concurrent polling requests could redeem it
+	if e := g.deviceCodeStore.ConsumeDeviceAuthorization(ctx, da); e != nil {
+		return nil, e
+	}
+
+	oauthRequest, e := mergedOAuth2Request(da.Request, request, deviceCodeIgnoreParams)
+	if e != nil {
+		return nil, e
+	}
+
+	oauth, e := g.authService.CreateAuthentication(ctx, oauthRequest, da.UserAuth)
+	if e != nil {
+		return nil, oauth2.NewInvalidGrantError(e)
+	}
+
+	// create token
+	token, e := g.authService.CreateAccessToken(ctx, oauth)
+	if e != nil {
+		return nil, oauth2.NewInvalidGrantError(e)
+	}
+	return token, nil
+}
+
+// checkPolling enforces polling interval and translate status of DeviceAuthorization into errors.
+// returns nil if the device authorization is approved.
+func (g *DeviceCodeGranter) checkPolling(ctx context.Context, da *auth.DeviceAuthorization) error {
+	now := time.Now()
+	tooFast := !da.LastPolled.IsZero() && now.Sub(da.LastPolled) < da.Interval
+	da.LastPolled = now
+	if tooFast {
+		da.Interval = da.Interval + slowDownIncrement
+	}
+
+	switch da.Status {
+	case auth.DeviceAuthorizationApproved:
+		if !da.Request.Approved() || da.UserAuth == nil {
+			return oauth2.NewInvalidGrantError("original device authorization request is invalid")
+		}
+		return nil
+	case auth.DeviceAuthorizationDenied:
+		_ = g.deviceCodeStore.RemoveDeviceAuthorization(ctx, da)
+		return oauth2.NewAccessRejectedError("user denied the authorization request")
+	}
+
+	if e := g.deviceCodeStore.SaveDevicePolling(ctx, da); e != nil {
+		return e
+	}
+	if tooFast {
+		return oauth2.NewSlowDownError(fmt.Sprintf("polling interval is %d seconds", int(da.Interval.Seconds())))
+	}
+	return oauth2.NewAuthorizationPendingError("user has not yet completed the authorization")
+}
@@ -25,9 +25,13 @@ import (
 )
 
 var (
+	// refreshIgnoreParams are not carried over from refresh request when scope is reduced.
+	// Note: refreshed tokens are always bound to the original DPoP key, so "dpop_jkt" is kept from the original request.
+	// See https://datatracker.ietf.org/doc/html/rfc9449#section-5
 	refreshIgnoreParams = utils.NewStringSet(
 		oauth2.ParameterClientSecret,
 		oauth2.ParameterRefreshToken,
+		auth.ExtKeyDPoPJwkThumbprint,
 	)
 )
 
@@ -0,0 +1,280 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grants
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/claims"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/tokenauth"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"strings"
+)
+
+var (
+	tokenExchangeIgnoreParams = utils.NewStringSet(
+		oauth2.ParameterClientSecret,
+		oauth2.ParameterSubjectToken,
+		oauth2.ParameterSubjectTokenType,
+		oauth2.ParameterActorToken,
+		oauth2.ParameterActorTokenType,
+		oauth2.ParameterAudience,
+		oauth2.ParameterTenantId,
+		oauth2.ParameterTenantExternalId,
+		oauth2.ClaimActor,
+	)
+	tokenExchangeSupportedTypes = utils.NewStringSet(
+		oauth2.TokenTypeIdAccessToken,
+		oauth2.TokenTypeIdJwt,
+	)
+)
+
+// TokenExchangeGranter implements auth.TokenGranter
+// It exchanges a valid access token (subject token) for a new access token issued to the requesting client,
+// with same or narrower scopes and audience. When actor token is provided, the new token carries "act" claim (delegation).
+// Otherwise, the new token represents the subject directly (impersonation).
+// See https://datatracker.ietf.org/doc/html/rfc8693
+type TokenExchangeGranter struct {
+	authService   auth.AuthorizationService
+	authenticator security.Authenticator
+}
+
+func NewTokenExchangeGranter(authService auth.AuthorizationService, authenticator security.Authenticator) *TokenExchangeGranter {
+	if authService == nil {
+		panic(fmt.Errorf("cannot create TokenExchangeGranter without authorization service"))
+	}
+
+	if authenticator == nil {
+		panic(fmt.Errorf("cannot create TokenExchangeGranter without token authenticator"))
+	}
+
+	return &TokenExchangeGranter{
+		authService:   authService,
+		authenticator: authenticator,
+	}
+}
+
+func (g *TokenExchangeGranter) Grant(ctx context.Context, request *auth.TokenRequest) (oauth2.AccessToken, error) {
+	if oauth2.GrantTypeTokenExchange != request.GrantType {
+		return nil, nil
+	}
+
+	client := auth.RetrieveAuthenticatedClient(ctx)
+
+	// common check
+	if e := auth.ValidateGrant(ctx, client, request.GrantType); e != nil {
+		return nil, e
+	}
+
+	// additional request params check and exchange policy check
+	subjectTokenType, _ := request.Extensions[oauth2.ParameterSubjectTokenType].(string)
+	if e := g.validateRequest(ctx, client, request, subjectTokenType); e != nil {
+		return nil, e
+	}
+
+	// authenticate subject token
+	subjectToken, _ := request.Extensions[oauth2.ParameterSubjectToken].(string)
+	stored, e := g.authenticateToken(ctx, subjectToken, oauth2.ParameterSubjectToken)
+	if e != nil {
+		return nil, e
+	}
+	if stored.UserAuthentication() == nil || stored.UserAuthentication().State() < security.StateAuthenticated {
+		return nil, oauth2.NewInvalidGrantError("subject token is not associated with a valid user")
+	}
+
+	// authenticate actor token if provided
+	actor, e := g.resolveActor(ctx, request, stored)
+	if e != nil {
+		return nil, e
+	}
+
+	// create new request
+	req, e := g.exchangeRequest(ctx, client, stored, actor, request)
+	if e != nil {
+		return nil, e
+	}
+
+	oauth, e := g.authService.SwitchAuthentication(ctx, req, stored.UserAuthentication(), stored)
+	if e != nil {
+		return nil, oauth2.NewInvalidGrantError(e)
+	}
+
+	// create token
+	token, e := g.authService.CreateAccessToken(ctx, oauth)
+	if e != nil {
+		return nil, oauth2.NewInvalidGrantError(e)
+	}
+	return token, nil
+}
+
+func (g *TokenExchangeGranter) validateRequest(_ context.Context, client oauth2.OAuth2Client, request *auth.TokenRequest, subjectTokenType string) error {
+	if v, _ := request.Extensions[oauth2.ParameterSubjectToken].(string); strings.TrimSpace(v) == "" {
+		return oauth2.NewInvalidTokenRequestError(fmt.Sprintf("[%s] is missing", oauth2.ParameterSubjectToken))
+	}
+
+	if strings.TrimSpace(subjectTokenType) == "" {
+		return oauth2.NewInvalidTokenRequestError(fmt.Sprintf("[%s] is missing", oauth2.ParameterSubjectTokenType))
+	}
+
+	if !tokenExchangeSupportedTypes.Has(subjectTokenType) {
+		return oauth2.NewInvalidTokenRequestError(fmt.Sprintf("unsupported %s [%s]", oauth2.ParameterSubjectTokenType, subjectTokenType))
+	}
+
+	if v, ok := request.Extensions[oauth2.ParameterRequestedTokenType].(string); ok && v != "" && v != oauth2.TokenTypeIdAccessToken {
+		return oauth2.NewInvalidTokenRequestError(fmt.Sprintf("unsupported %s [%s]", oauth2.ParameterRequestedTokenType, v))
+	}
+
+	// exchange policy: client need to be explicitly allowed to exchange given subject token type
+	tec, ok := client.(oauth2.TokenExchangeClient)
+	if !ok || !tec.TokenExchangeSubjectTypes().Has(subjectTokenType) {
+		return oauth2.NewUnauthorizedClientError(fmt.Sprintf("client is not allowed to exchange token of type [%s]", subjectTokenType))
+	}
+	return nil
+}
+
+func (g *TokenExchangeGranter) authenticateToken(ctx context.Context, tokenValue string, param string) (oauth2.Authentication, error) {
+	candidate := tokenauth.BearerToken{
+		Token:      tokenValue,
+		DetailsMap: map[string]interface{}{},
+	}
+
+	auth, e := g.authenticator.Authenticate(ctx, &candidate)
+	if e != nil {
+		return nil, oauth2.NewInvalidGrantError(fmt.Sprintf("invalid %s", param), e)
+	}
+	oauth, ok := auth.(oauth2.Authentication)
+	if !ok || oauth.State() < security.StateAuthenticated {
+		return nil, oauth2.NewInvalidGrantError(fmt.Sprintf("invalid %s", param))
+	}
+	return oauth, nil
+}
+
+// resolveActor returns the "act" claim value if actor token is present. Returns nil if actor token is not provided.
+// If subject token already carries "act" claim, it's nested as the prior actor. The prior actor is always taken from
+// the validated subject token's claims, so delegation chain cannot be altered by the request.
+// See https://datatracker.ietf.org/doc/html/rfc8693#section-4.1
+func (g *TokenExchangeGranter) resolveActor(ctx context.Context, request *auth.TokenRequest, stored oauth2.Authentication) (map[string]interface{}, error) {
+	actorToken, _ := request.Extensions[oauth2.ParameterActorToken].(string)
+	actorTokenType, _ := request.Extensions[oauth2.ParameterActorTokenType].(string)
+	switch {
+	case actorToken == "" && actorTokenType == "":
+		return nil, nil
+	case actorToken == "":
+		return nil, oauth2.NewInvalidTokenRequestError(fmt.Sprintf("[%s] is missing", oauth2.ParameterActorToken))
+	case actorTokenType == "":
+		return nil, oauth2.NewInvalidTokenRequestError(fmt.Sprintf("[%s] is missing", oauth2.ParameterActorTokenType))
+	case !tokenExchangeSupportedTypes.Has(actorTokenType):
+		return nil, oauth2.NewInvalidTokenRequestError(fmt.Sprintf("unsupported %s [%s]", oauth2.ParameterActorTokenType, actorTokenType))
+	}
+
+	actorAuth, e := g.authenticateToken(ctx, actorToken, oauth2.ParameterActorToken)
+	if e != nil {
+		return nil, e
+	}
+
+	actorClientId := actorAuth.OAuth2Request().ClientId()
+	act := map[string]interface{}{
+		oauth2.ClaimSubject:  actorClientId,
+		oauth2.ClaimClientId: actorClientId,
+	}
+	if user := actorAuth.UserAuthentication(); user != nil {
+		if username, e := security.GetUsername(user); e == nil && username != "" {
+			act[oauth2.ClaimSubject] = username
+		}
+	}
+
+	if prior := subjectActor(stored); len(prior) != 0 {
+		act[oauth2.ClaimActor] = prior
+	}
+	return act, nil
+}
+
+// subjectActor returns the "act" claim of the subject token, including any nested prior actors
+func subjectActor(stored oauth2.Authentication) map[string]interface{} {
+	container, ok := stored.AccessToken().(oauth2.ClaimsContainer)
+	if !ok || container.Claims() == nil {
+		return nil
+	}
+	act, _ := container.Claims().Get(oauth2.ClaimActor).(map[string]interface{})
+	return act
+}
+
+// exchangeRequest creates a new request on behalf of requesting client. Scopes and audience can only be narrowed down.
+func (g *TokenExchangeGranter) exchangeRequest(ctx context.Context, client oauth2.OAuth2Client,
+	stored oauth2.Authentication, actor map[string]interface{}, request *auth.TokenRequest) (oauth2.OAuth2Request, error) {
+
+	// scopes
+	src := stored.OAuth2Request()
+	scopes := request.Scopes
+	if len(scopes) == 0 {
+		scopes = src.Scopes().Copy()
+		for scope := range scopes {
+			if !client.Scopes().Has(scope) {
+				scopes.Remove(scope)
+			}
+		}
+	} else if ok, invalid := auth.IsSubSet(ctx, src.Scopes(), scopes); !ok {
+		return nil, oauth2.NewInvalidScopeError(fmt.Sprintf("scope [%s] is not granted to subject token", invalid))
+	}
+	if e := auth.ValidateAllScopes(ctx, client, scopes); e != nil {
+		return nil, e
+	}
+
+	// audience
+	var audience []string
+	if v, _ := request.Extensions[oauth2.ParameterAudience].(string); strings.TrimSpace(v) != "" {
+		audience = strings.Fields(v)
+		allowed := claims.LegacyAudience(ctx, &claims.FactoryOption{Source: stored})
+		for _, aud := range audience {
+			if !allowed.Has(aud) {
+				return nil, oauth2.NewInvalidTargetError(fmt.Sprintf("audience [%s] is not an audience of the subject token", aud))
+			}
+		}
+	}
+
+	return oauth2.NewOAuth2Request(func(opt *oauth2.RequestDetails) {
+		for k, v := range request.Parameters {
+			if tokenExchangeIgnoreParams.Has(k) {
+				continue
+			}
+			opt.Parameters[k] = v
+		}
+		for k, v := range request.Extensions {
+			if tokenExchangeIgnoreParams.Has(k) {
+				continue
+			}
+			opt.Extensions[k] = v
+		}
+		// tenant of subject token is preserved
+		if td, ok := stored.Details().(security.TenantDetails); ok && td.TenantId() != "" {
+			opt.Parameters[oauth2.ParameterTenantId] = td.TenantId()
+		}
+		if len(audience) != 0 {
+			opt.Extensions[auth.ExtKeyTokenExchangeAudience] = audience
+		}
+		if actor != nil {
+			opt.Extensions[auth.ExtKeyTokenExchangeActor] = actor
+		}
+		opt.ClientId = client.ClientId()
+		opt.Scopes = scopes
+		opt.GrantType = oauth2.GrantTypeTokenExchange
+		opt.Approved = true
+	}), nil
+}
@@ -80,6 +80,7 @@ func SubTestOpenIDConfigWithExtra(di *WellKnownDI) test.GomegaSubTestFunc {
 			openid.OPMetadataUserInfoEndpoint:   "/userinfo",
 			openid.OPMetadataJwkSetURI:          "/jwks",
 			openid.OPMetadataEndSessionEndpoint: "/logout",
+			openid.OPMetadataDeviceAuthEndpoint: "/device_authorization",
 		})
 
 		resp, e = endpoint.OpenIDConfig(ctx, req)
@@ -89,6 +90,7 @@ func SubTestOpenIDConfigWithExtra(di *WellKnownDI) test.GomegaSubTestFunc {
 			ExpectClaim(openid.OPMetadataTokenEndpoint, FullURL("/token")),
 			ExpectClaim(openid.OPMetadataUserInfoEndpoint, FullURL("/userinfo")),
 			ExpectClaim(openid.OPMetadataJwkSetURI, FullURL("/jwks")),
+			ExpectClaim(openid.OPMetadataDeviceAuthEndpoint, FullURL("/device_authorization")),
 		)
 	}
 }
@@ -108,13 +110,19 @@ func FullURL(path string) string {
 func AssertOpenIDConfigClaims(g *gomega.WithT, claims oauth2.Claims, expectExtra ...ExpectedClaimsOption) {
 	expectOpts := []ExpectedClaimsOption{
 		ExpectClaim(openid.OPMetadataIssuer, "http://"+IssuerDomain+IssuerPath),
-		ExpectClaim(openid.OPMetadataGrantTypes, HaveLen(8)),
+		ExpectClaim(openid.OPMetadataGrantTypes, HaveLen(10)),
 		ExpectClaim(openid.OPMetadataScopes, HaveLen(9)),
 		ExpectClaim(openid.OPMetadataResponseTypes, HaveKey("code")),
 		ExpectClaim(openid.OPMetadataACRValues, HaveKey(Or(Equal(ACRValue(1)), Equal(ACRValue(2)), Equal(ACRValue(3))))),
 		ExpectClaim(openid.OPMetadataSubjectTypes, HaveKey("public")),
 		ExpectClaim(openid.OPMetadataIdTokenJwsAlg, HaveKey("RS256")),
 		ExpectClaim(openid.OPMetadataClaims, Not(BeEmpty())),
+		ExpectClaim(openid.OPMetadataClientAuthMethod, SatisfyAll(
+			HaveKey(oauth2.ClientAuthMethodSecretBasic), HaveKey(oauth2.ClientAuthMethodPrivateKeyJwt),
+			HaveKey(oauth2.ClientAuthMethodTLS), HaveKey(oauth2.ClientAuthMethodSelfSignedTLS),
+		)),
+		ExpectClaim(openid.OPMetadataAuthJwsAlg, SatisfyAll(HaveKey("RS256"), HaveKey("ES256"), Not(HaveKey("none")))),
+		ExpectClaim(openid.OPMetadataTLSCertBoundTokens, BeTrue()),
 	}
 	expectOpts = append(expectOpts, expectExtra...)
 	AssertClaims(g, claims, NewExpectedClaims(expectOpts...))
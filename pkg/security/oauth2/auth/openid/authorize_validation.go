@@ -26,9 +26,7 @@ import (
     "github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
     "github.com/cisco-open/go-lanai/pkg/utils"
     "github.com/cisco-open/go-lanai/pkg/web"
-    "io"
     "net/http"
-    "net/url"
     "strings"
     "time"
 )
@@ -43,17 +41,18 @@ var (
 
 // OpenIDAuthorizeRequestProcessor implements ChainedAuthorizeRequestProcessor and order.Ordered
 // it validate auth request against standard oauth2 specs
+// Note: "request" and "request_uri" should be resolved by par.RequestObjectProcessor before this processor.
+// Unresolved request objects are rejected.
 //goland:noinspection GoNameStartsWithPackageName
 type OpenIDAuthorizeRequestProcessor struct {
-	issuer             security.Issuer
-	jwtDecoder         jwt.JwtDecoder
-	fallbackJwtDecoder jwt.JwtDecoder
+	issuer security.Issuer
 }
 
 type ARPOptions func(opt *ARPOption)
 
 type ARPOption struct {
-	Issuer     security.Issuer
+	Issuer security.Issuer
+	// Deprecated: request objects are verified with client's keys by par.RequestObjectProcessor. This field is ignored
 	JwtDecoder jwt.JwtDecoder
 }
 
@@ -63,9 +62,7 @@ func NewOpenIDAuthorizeRequestProcessor(opts ...ARPOptions) *OpenIDAuthorizeRequ
 		f(&opt)
 	}
 	return &OpenIDAuthorizeRequestProcessor{
-		issuer:             opt.Issuer,
-		jwtDecoder:         opt.JwtDecoder,
-		fallbackJwtDecoder: jwt.NewPlaintextJwtDecoder(),
+		issuer: opt.Issuer,
 	}
 }
 
@@ -79,9 +76,8 @@ func (p *OpenIDAuthorizeRequestProcessor) Process(ctx context.Context, request *
 		return nil, e
 	}
 
-	// attempt to decode from request object
-	if request, err = p.decodeRequestObject(ctx, request); err != nil {
-		return
+	if e := p.validateRequestObject(ctx, request); e != nil {
+		return nil, e
 	}
 
 	// continue with the chain
@@ -117,46 +113,15 @@ func (p *OpenIDAuthorizeRequestProcessor) Process(ctx context.Context, request *
 	return request, nil
 }
 
-func (p *OpenIDAuthorizeRequestProcessor) decodeRequestObject(ctx context.Context, request *auth.AuthorizeRequest) (*auth.AuthorizeRequest, error) {
-	reqUri, uriOk := request.Parameters[oauth2.ParameterRequestUri]
-	reqObj, objOk := request.Parameters[oauth2.ParameterRequestObj]
-	switch {
-	case !uriOk && !objOk:
-		return request, nil
-	case uriOk && objOk:
-		return nil, oauth2.NewInvalidAuthorizeRequestError(fmt.Errorf("%s and %s are exclusive", oauth2.ParameterRequestUri, oauth2.ParameterRequestObj))
-	case uriOk:
-		if strings.HasPrefix(strings.ToLower(reqUri), "https:") {
-			return nil, oauth2.NewInvalidAuthorizeRequestError(fmt.Errorf("%s must use https", oauth2.ParameterRequestUri))
-		}
-		bytes, e := httpGet(ctx, reqUri)
-		if e != nil {
-			return nil, oauth2.NewInvalidAuthorizeRequestError(fmt.Errorf("unable to fetch request object from %s: %v", oauth2.ParameterRequestUri, e))
-		}
-		reqObj = string(bytes)
-	}
-
-	// decode JWT using configured decoder, fallback to plaintext decoder
-	claims, e := p.jwtDecoder.Decode(ctx, reqObj)
-	if e != nil {
-		if claims, e = p.fallbackJwtDecoder.Decode(ctx, reqObj); e != nil {
-			return nil, oauth2.NewInvalidAuthorizeRequestError(fmt.Errorf("invalid request object: %v", e))
-		}
+// validateRequestObject rejects "request" and "request_uri" that were not resolved by par.RequestObjectProcessor.
+// Request objects must not be silently ignored, because their parameters take precedence over the request parameters.
+func (p *OpenIDAuthorizeRequestProcessor) validateRequestObject(_ context.Context, request *auth.AuthorizeRequest) error {
+	_, uriOk := request.Parameters[oauth2.ParameterRequestUri]
+	_, objOk := request.Parameters[oauth2.ParameterRequestObj]
+	if uriOk || objOk {
+		return oauth2.NewInvalidAuthorizeRequestError(fmt.Errorf("%s and %s are not supported", oauth2.ParameterRequestUri, oauth2.ParameterRequestObj))
 	}
-
-	//nolint:contextcheck
-	decoded, e := claimsToAuthRequest(request.Context(), claims)
-	if e != nil {
-		return nil, oauth2.NewInvalidAuthorizeRequestError(fmt.Errorf("invalid request object: %v", e))
-	}
-
-	switch {
-	case !request.ResponseTypes.Equals(decoded.ResponseTypes):
-		return nil, oauth2.NewInvalidAuthorizeRequestError(fmt.Errorf("invalid request object - inconsistant response type"))
-	case !decoded.Scopes.Has(oauth2.ScopeOidc):
-		return nil, oauth2.NewInvalidAuthorizeRequestError(fmt.Errorf("invalid request object - missing 'openid' scope"))
-	}
-	return decoded, nil
+	return nil
 }
 
 func (p *OpenIDAuthorizeRequestProcessor) validateResponseTypes(ctx context.Context, request *auth.AuthorizeRequest) error {
@@ -325,29 +290,3 @@ func getHttpRequest(ctx context.Context) *http.Request {
 	}
 	return nil
 }
-
-func httpGet(ctx context.Context, urlStr string) ([]byte, error) {
-	parsed, e := url.Parse(urlStr)
-	if e != nil {
-		return nil, e
-	}
-	req, e := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), http.NoBody)
-	if e != nil {
-		return nil, e
-	}
-	resp, e := http.DefaultClient.Do(req)
-	if e != nil {
-		return nil, e
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return nil, fmt.Errorf("non-2XX status code")
-	}
-
-	return io.ReadAll(resp.Body)
-}
-
-func claimsToAuthRequest(ctx context.Context, claims oauth2.Claims) (*auth.AuthorizeRequest, error) {
-	return auth.ParseAuthorizeRequestWithKVs(ctx, claims.Values())
-}
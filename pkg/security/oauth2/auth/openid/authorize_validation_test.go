@@ -17,7 +17,6 @@ import (
     . "github.com/onsi/gomega"
     "go.uber.org/fx"
     "net/http"
-    "net/http/httptest"
     "strings"
     "testing"
     "time"
@@ -27,10 +26,9 @@ import (
 	Setup Test
  *************************/
 
-func ProvideOpenIDAuthorizeRequestProcessor(issuer security.Issuer, decoder jwt.JwtDecoder) *OpenIDAuthorizeRequestProcessor {
+func ProvideOpenIDAuthorizeRequestProcessor(issuer security.Issuer) *OpenIDAuthorizeRequestProcessor {
 	return NewOpenIDAuthorizeRequestProcessor(func(opt *ARPOption) {
 		opt.Issuer = issuer
-		opt.JwtDecoder = decoder
 	})
 }
 
@@ -64,8 +62,7 @@ func TestOpenIDAuthorizeRequestProcessor(t *testing.T) {
 		test.GomegaSubTest(SubTestProcessWithACR(&di), "ProcessWithClaimsRequest"),
 		test.GomegaSubTest(SubTestProcessWithMaxAge(&di), "ProcessWithMaxAge"),
 		test.GomegaSubTest(SubTestProcessWithPrompt(&di), "ProcessWithPrompt"),
-		test.GomegaSubTest(SubTestProcessWithRequestObject(&di), "ProcessWithRequestObject"),
-		test.GomegaSubTest(SubTestProcessWithRequestUri(&di), "ProcessWithRequestUri"),
+		test.GomegaSubTest(SubTestProcessWithUnresolvedRequestObject(&di), "ProcessWithUnresolvedRequestObject"),
 	)
 }
 
@@ -282,20 +279,19 @@ func SubTestProcessWithPrompt(di *ARProcessorDI) test.GomegaSubTestFunc {
 	}
 }
 
-func SubTestProcessWithRequestObject(di *ARProcessorDI) test.GomegaSubTestFunc {
+// SubTestProcessWithUnresolvedRequestObject request objects should be resolved by par.RequestObjectProcessor.
+// Any remaining "request" or "request_uri" should be rejected instead of being ignored
+func SubTestProcessWithUnresolvedRequestObject(di *ARProcessorDI) test.GomegaSubTestFunc {
 	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
 		var req *auth.AuthorizeRequest
 		type reqObjCond struct {
-			success bool
-			obj     string
-			uri     string
+			obj string
+			uri string
 		}
 		reqObj := []reqObjCond{
-			{obj: NewRequestObjectJwt(di.JwtEncoder), success: true},
-			{obj: NewRequestObjectJwt(di.JwtEncoder), uri: "http://localhost:0/authorize/request", success: false},
-			{obj: NewRequestObjectJwt(di.JwtEncoder, ARResponseTypes("token")), success: false},
-			{obj: NewRequestObjectJwt(di.JwtEncoder, ARScopes("read", "write")), success: false},
-			{obj: "malformed", success: false},
+			{obj: NewRequestObjectJwt(di.JwtEncoder)},
+			{uri: "https://localhost/authorize/request"},
+			{uri: auth.RequestUriPrefixPAR + "test"},
 		}
 		for _, cond := range reqObj {
 			req = auth.NewAuthorizeRequest(func(req *auth.AuthorizeRequest) {
@@ -308,40 +304,7 @@ func SubTestProcessWithRequestObject(di *ARProcessorDI) test.GomegaSubTestFunc {
 					req.Parameters[oauth2.ParameterRequestUri] = cond.uri
 				}
 			}).WithContext(ctx)
-			AssertProcessor(ctx, g, di, req, cond.success, fmt.Sprintf("req obj [%s], req uri [%s]", cond.obj, cond.uri))
-		}
-	}
-}
-
-func SubTestProcessWithRequestUri(di *ARProcessorDI) test.GomegaSubTestFunc {
-	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
-		var req *auth.AuthorizeRequest
-		// setup an authorize request CDN server
-		const path = `/authorize/request`
-		handler := &RequestUriHandler{Path: path}
-		server := httptest.NewServer(handler)
-		defer server.Close()
-		// do tests
-		type reqObjCond struct {
-			success bool
-			obj     string
-			uri     string
-		}
-		reqObj := []reqObjCond{
-			{uri: server.URL + path, obj: NewRequestObjectJwt(di.JwtEncoder), success: true},
-			{uri: server.URL + "/not/exists", obj: NewRequestObjectJwt(di.JwtEncoder), success: false},
-			{uri: server.URL + path, obj: NewRequestObjectJwt(di.JwtEncoder, ARResponseTypes("token")), success: false},
-			{uri: server.URL + path, obj: NewRequestObjectJwt(di.JwtEncoder, ARScopes("read", "write")), success: false},
-			{uri: server.URL + path, obj: "malformed", success: false},
-		}
-		for _, cond := range reqObj {
-			req = auth.NewAuthorizeRequest(func(req *auth.AuthorizeRequest) {
-				req.Scopes.Add(oauth2.ScopeOidc)
-				req.ResponseTypes.Add("code")
-				req.Parameters[oauth2.ParameterRequestUri] = cond.uri
-			}).WithContext(ctx)
-			handler.JWTValue = cond.obj
-			AssertProcessor(ctx, g, di, req, cond.success, fmt.Sprintf("req uri [%s], jwt [%s]", cond.uri, cond.obj))
+			AssertProcessor(ctx, g, di, req, false, fmt.Sprintf("req obj [%s], req uri [%s]", cond.obj, cond.uri))
 		}
 	}
 }
@@ -413,20 +376,6 @@ func NewRequestObjectJwt(encoder jwt.JwtEncoder, opts ...func(req *auth.Authoriz
 	return str
 }
 
-type RequestUriHandler struct {
-	Path     string
-	JWTValue string
-}
-
-func (h *RequestUriHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	if req.URL.Path != h.Path {
-		rw.WriteHeader(http.StatusNotFound)
-		return
-	}
-	rw.WriteHeader(http.StatusOK)
-	_, _ = rw.Write([]byte(h.JWTValue))
-}
-
 func ARClientID(value string) func(req *auth.AuthorizeRequest) {
 	return func(req *auth.AuthorizeRequest) {
 		req.ClientId = value
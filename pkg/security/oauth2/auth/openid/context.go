@@ -50,12 +50,14 @@ var (
 		oauth2.GrantTypePassword,
 		oauth2.GrantTypeSwitchUser,
 		oauth2.GrantTypeSwitchTenant,
+		oauth2.GrantTypeDeviceCode,
 	)
 	SupportedDisplayMode  = utils.NewStringSet(DisplayPage, PromptTouch)
 	FullIdTokenGrantTypes = utils.NewStringSet(
 		oauth2.GrantTypePassword,
 		oauth2.GrantTypeSwitchUser,
 		oauth2.GrantTypeSwitchTenant,
+		oauth2.GrantTypeDeviceCode,
 	)
 )
 
@@ -99,6 +101,11 @@ const (
 	OPMetadataPolicyUri             = "op_policy_uri"
 	OPMetadataTosUri                = "op_tos_uri"
 	OPMetadataEndSessionEndpoint    = "end_session_endpoint"
+	OPMetadataDeviceAuthEndpoint    = "device_authorization_endpoint"
+	OPMetadataPAREndpoint           = "pushed_authorization_request_endpoint"
+	OPMetadataRequirePAR            = "require_pushed_authorization_requests"
+	OPMetadataTLSCertBoundTokens    = "tls_client_certificate_bound_access_tokens"
+	OPMetadataDPoPSigningAlgs       = "dpop_signing_alg_values_supported"
 )
 
 // OPMetadata leverage claims implementations
@@ -32,6 +32,7 @@ var (
 			oauth2.GrantTypeClientCredentials, oauth2.GrantTypePassword,
 			oauth2.GrantTypeAuthCode, oauth2.GrantTypeImplicit, oauth2.GrantTypeRefresh,
 			oauth2.GrantTypeSwitchUser, oauth2.GrantTypeSwitchTenant, oauth2.GrantTypeSamlSSO,
+			oauth2.GrantTypeDeviceCode, oauth2.GrantTypeTokenExchange,
 		),
 		OPMetadataScopes: opMetaFixedSet(
 			oauth2.ScopeRead, oauth2.ScopeWrite, oauth2.ScopeTokenDetails, oauth2.ScopeTenantHierarchy,
@@ -60,11 +61,11 @@ var (
 		OPMetadataUserInfoJwsAlg:        opMetaFixedSet("RS256"),
 		OPMetadataUserInfoJweAlg:        claims.Unsupported(),
 		OPMetadataUserInfoJweEnc:        claims.Unsupported(),
-		OPMetadataRequestJwsAlg:         claims.Unsupported(),
+		OPMetadataRequestJwsAlg:         opMetaFixedSet("RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "PS256", "PS384", "PS512", "EdDSA"),
 		OPMetadataRequestJweAlg:         claims.Unsupported(),
 		OPMetadataRequestJweEnc:         claims.Unsupported(),
-		OPMetadataClientAuthMethod:      opMetaFixedSet("client_secret_basic", "client_secret_post"),
-		OPMetadataAuthJwsAlg:            claims.Unsupported(),
+		OPMetadataClientAuthMethod:      opMetaFixedSet(oauth2.ClientAuthMethodSecretBasic, oauth2.ClientAuthMethodSecretPost, oauth2.ClientAuthMethodPrivateKeyJwt, oauth2.ClientAuthMethodTLS, oauth2.ClientAuthMethodSelfSignedTLS),
+		OPMetadataAuthJwsAlg:            opMetaFixedSet("RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "PS256", "PS384", "PS512", "EdDSA"),
 		OPMetadataDisplayValues:         opMetaFixedSet("page", "touch"),
 		OPMetadataClaimTypes:            opMetaFixedSet("normal"),
 		OPMetadataServiceDocs:           claims.Unsupported(),
@@ -72,10 +73,15 @@ var (
 		OPMetadataUILocales:             opMetaFixedSet("en-CA", "en-US"),
 		OPMetadataClaimsParams:          opMetaFixedBool(true),
 		OPMetadataRequestParams:         opMetaFixedBool(true),
-		OPMetadataRequestUriParams:      claims.Unsupported(),
+		OPMetadataRequestUriParams:      opMetaFixedBool(true),
 		OPMetadataRequiresRequestUriReg: claims.Unsupported(),
 		OPMetadataPolicyUri:             claims.Unsupported(),
 		OPMetadataTosUri:                claims.Unsupported(),
 		OPMetadataEndSessionEndpoint:    opMetaEndpoint(OPMetadataEndSessionEndpoint),
+		OPMetadataDeviceAuthEndpoint:    opMetaEndpoint(OPMetadataDeviceAuthEndpoint),
+		OPMetadataPAREndpoint:           opMetaEndpoint(OPMetadataPAREndpoint),
+		OPMetadataRequirePAR:            opMetaFixedBool(false),
+		OPMetadataTLSCertBoundTokens:    opMetaFixedBool(true),
+		OPMetadataDPoPSigningAlgs:       opMetaFixedSet("RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "PS256", "PS384", "PS512", "EdDSA"),
 	}
 )
@@ -0,0 +1,77 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package par
+
+import (
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/web/mapping"
+	"github.com/cisco-open/go-lanai/pkg/web/matcher"
+	"github.com/cisco-open/go-lanai/pkg/web/middleware"
+	"net/http"
+)
+
+var (
+	FeatureId = security.FeatureId("OAuth2PushedAuthorization", security.FeatureOrderOAuth2TokenEndpoint)
+)
+
+type PAREndpointConfigurer struct{}
+
+func newPAREndpointConfigurer() *PAREndpointConfigurer {
+	return &PAREndpointConfigurer{}
+}
+
+func (c *PAREndpointConfigurer) Apply(feature security.Feature, ws security.WebSecurity) (err error) {
+	// Verify
+	f := feature.(*PushedAuthorizationFeature)
+	if err := c.validate(f); err != nil {
+		return err
+	}
+
+	// prepare middlewares
+	mw := NewPAREndpointMiddleware(func(opt *PARMWOption) {
+		opt.RequestProcessor = f.requestProcessor
+		opt.RequestStore = f.requestStore
+		opt.RequestValidity = f.validity
+	})
+
+	// install middlewares
+	parMapping := middleware.NewBuilder("pushed authorization request endpoint").
+		ApplyTo(matcher.RouteWithPattern(f.path, http.MethodPost)).
+		Order(security.MWOrderOAuth2Endpoints).
+		Use(mw.PushedAuthorizationHandlerFunc())
+
+	ws.Add(parMapping)
+
+	// add dummy handler
+	ws.Add(mapping.Post(f.path).HandlerFunc(security.NoopHandlerFunc()))
+	return nil
+}
+
+func (c *PAREndpointConfigurer) validate(f *PushedAuthorizationFeature) error {
+	switch {
+	case f.path == "":
+		return fmt.Errorf("pushed authorization request endpoint path is not set")
+	case f.requestProcessor == nil:
+		return fmt.Errorf("authorize request processor is not set")
+	case f.requestStore == nil:
+		return fmt.Errorf("pushed authorization request store is not set")
+	case f.validity <= 0:
+		return fmt.Errorf("invalid request_uri validity [%v]", f.validity)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package par
+
+import (
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+	"time"
+)
+
+// PushedAuthorizationFeature configures pushed authorization request endpoint.
+// The endpoint requires client authentication.
+// See https://datatracker.ietf.org/doc/html/rfc9126
+type PushedAuthorizationFeature struct {
+	path             string
+	requestProcessor auth.AuthorizeRequestProcessor
+	requestStore     auth.PushedAuthorizeRequestStore
+	validity         time.Duration
+}
+
+func (f *PushedAuthorizationFeature) Identifier() security.FeatureIdentifier {
+	return FeatureId
+}
+
+// Configure is standard security.Feature entrypoint
+func Configure(ws security.WebSecurity) *PushedAuthorizationFeature {
+	feature := NewEndpoint()
+	if fc, ok := ws.(security.FeatureModifier); ok {
+		return fc.Enable(feature).(*PushedAuthorizationFeature)
+	}
+	panic(fmt.Errorf("unable to configure oauth2 authserver: provided WebSecurity [%T] doesn't support FeatureModifier", ws))
+}
+
+// NewEndpoint is standard security.Feature entrypoint, DSL style. Used with security.WebSecurity
+func NewEndpoint() *PushedAuthorizationFeature {
+	return &PushedAuthorizationFeature{
+		validity: 5 * time.Minute,
+	}
+}
+
+/** Setters **/
+
+func (f *PushedAuthorizationFeature) Path(path string) *PushedAuthorizationFeature {
+	f.path = path
+	return f
+}
+
+// RequestProcessor is used to validate pushed requests. It should be the same processor used by authorize endpoint
+func (f *PushedAuthorizationFeature) RequestProcessor(processor auth.AuthorizeRequestProcessor) *PushedAuthorizationFeature {
+	f.requestProcessor = processor
+	return f
+}
+
+func (f *PushedAuthorizationFeature) RequestStore(store auth.PushedAuthorizeRequestStore) *PushedAuthorizationFeature {
+	f.requestStore = store
+	return f
+}
+
+// RequestValidity is the lifetime of issued "request_uri"
+func (f *PushedAuthorizationFeature) RequestValidity(validity time.Duration) *PushedAuthorizationFeature {
+	f.validity = validity
+	return f
+}
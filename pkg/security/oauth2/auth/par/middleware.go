@@ -0,0 +1,134 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package par
+
+import (
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+var (
+	// clientAuthParams are parameters used by client authentication, they are not part of the authorize request
+	clientAuthParams = []string{
+		oauth2.ParameterClientSecret, oauth2.ParameterClientAssertion, oauth2.ParameterClientAssertionType,
+	}
+)
+
+// PAREndpointMiddleware implements pushed authorization request endpoint.
+// See https://datatracker.ietf.org/doc/html/rfc9126#section-2
+type PAREndpointMiddleware struct {
+	requestProcessor auth.AuthorizeRequestProcessor
+	requestStore     auth.PushedAuthorizeRequestStore
+	validity         time.Duration
+}
+
+type PARMWOptions func(*PARMWOption)
+
+type PARMWOption struct {
+	// RequestProcessor validates pushed requests the same way as authorize endpoint does
+	RequestProcessor auth.AuthorizeRequestProcessor
+	RequestStore     auth.PushedAuthorizeRequestStore
+	RequestValidity  time.Duration
+}
+
+func NewPAREndpointMiddleware(opts ...PARMWOptions) *PAREndpointMiddleware {
+	opt := PARMWOption{
+		RequestValidity: 5 * time.Minute,
+	}
+	for _, fn := range opts {
+		if fn != nil {
+			fn(&opt)
+		}
+	}
+	return &PAREndpointMiddleware{
+		requestProcessor: opt.RequestProcessor,
+		requestStore:     opt.RequestStore,
+		validity:         opt.RequestValidity,
+	}
+}
+
+// PushedAuthorizationHandlerFunc handles pushed authorization request.
+// See https://datatracker.ietf.org/doc/html/rfc9126#section-2.1
+func (mw *PAREndpointMiddleware) PushedAuthorizationHandlerFunc() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// first we double check if client is authenticated
+		client := auth.RetrieveAuthenticatedClient(ctx)
+		if client == nil {
+			mw.handleError(ctx, oauth2.NewClientNotFoundError("invalid client"))
+			return
+		}
+
+		request, e := auth.ParseAuthorizeRequest(ctx.Request)
+		if e != nil {
+			mw.handleError(ctx, oauth2.NewInvalidAuthorizeRequestError("invalid pushed authorization request", e))
+			return
+		}
+		if _, ok := request.Parameters[oauth2.ParameterRequestUri]; ok {
+			mw.handleError(ctx, oauth2.NewInvalidAuthorizeRequestError("request_uri is not allowed in pushed authorization request"))
+			return
+		}
+		if request.ClientId != "" && request.ClientId != client.ClientId() {
+			mw.handleError(ctx, oauth2.NewInvalidAuthorizeRequestError("given client ID does not match authenticated client"))
+			return
+		}
+		request.ClientId = client.ClientId()
+		request.Parameters[oauth2.ParameterClientId] = client.ClientId()
+		for _, k := range clientAuthParams {
+			delete(request.Parameters, k)
+			delete(request.Extensions, k)
+		}
+
+		// validate the request as if it's sent to authorize endpoint. Request object (JAR) is resolved by the processor
+		processed, e := mw.requestProcessor.Process(ctx, request)
+		if e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+
+		pushed, e := mw.requestStore.SavePushedAuthorizeRequest(ctx, client.ClientId(), processed.Parameters, mw.validity)
+		if e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+
+		logger.WithContext(ctx).Debugf("pushed authorization request saved for client [%s]", client.ClientId())
+		mw.handleSuccess(ctx, map[string]interface{}{
+			oauth2.JsonFieldRequestUri: pushed.RequestUri,
+			oauth2.JsonFieldExpiresIn:  pushed.ExpiresIn(),
+		})
+	}
+}
+
+func (mw *PAREndpointMiddleware) handleSuccess(c *gin.Context, v interface{}) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.JSON(http.StatusCreated, v)
+	c.Abort()
+}
+
+func (mw *PAREndpointMiddleware) handleError(c *gin.Context, err error) {
+	if !errors.Is(err, oauth2.ErrorTypeOAuth2) {
+		err = oauth2.NewInvalidAuthorizeRequestError(err)
+	}
+
+	_ = c.Error(err)
+	c.Abort()
+}
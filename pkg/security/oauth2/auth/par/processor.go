@@ -0,0 +1,305 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package par
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/clientauth"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"github.com/cisco-open/go-lanai/pkg/security/replay"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"strconv"
+	"time"
+)
+
+const (
+	prefixUsedRequestObject = "request-object"
+)
+
+var (
+	// registeredClaims are JWT claims of request object that are not authorize request parameters
+	registeredClaims = utils.NewStringSet(
+		oauth2.ClaimIssuer, oauth2.ClaimAudience, oauth2.ClaimExpire,
+		oauth2.ClaimIssueAt, oauth2.ClaimNotBefore, oauth2.ClaimJwtId,
+	)
+)
+
+// RequestObjectProcessor implements auth.ChainedAuthorizeRequestProcessor.
+// It resolves the actual authorize request before the rest of the chain from either:
+//   - "request_uri" issued by pushed authorization request endpoint. See https://datatracker.ietf.org/doc/html/rfc9126
+//   - signed "request" object, verified with client's JWKs. See https://datatracker.ietf.org/doc/html/rfc9101
+//
+// Only parameters from the pushed request or request object are used, except "client_id" which must match.
+// "response_type" and "openid" scope, if present in the request, must also match the resolved request.
+//
+// Both "request_uri" and request object are one-time use. They are consumed once the request is processed on behalf of
+// an authenticated user (or client at the PAR endpoint). Before that, the authorize endpoint may be revisited with the
+// same parameters after login.
+type RequestObjectProcessor struct {
+	clientStore  oauth2.OAuth2ClientStore
+	requestStore auth.PushedAuthorizeRequestStore
+	jwkResolver  clientauth.JwkStoreResolver
+	replayCache  replay.Cache
+	issuer       security.Issuer
+}
+
+type ProcessorOptions func(opt *ProcessorOption)
+
+type ProcessorOption struct {
+	ClientStore oauth2.OAuth2ClientStore
+	// RequestStore is required to support "request_uri". When not set, "request_uri" is rejected
+	RequestStore auth.PushedAuthorizeRequestStore
+	// JwkStoreResolver resolves client's JWKs to verify request objects. Default to clientauth.RemoteJwkStoreResolver
+	JwkStoreResolver clientauth.JwkStoreResolver
+	// ReplayCache is used to reject used request objects. Default to replay.InMemoryCache
+	ReplayCache replay.Cache
+	// Issuer is used to verify "aud" of request objects
+	Issuer security.Issuer
+}
+
+func NewRequestObjectProcessor(opts ...ProcessorOptions) *RequestObjectProcessor {
+	opt := ProcessorOption{}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	if opt.JwkStoreResolver == nil {
+		opt.JwkStoreResolver = clientauth.NewRemoteJwkStoreResolver()
+	}
+	if opt.ReplayCache == nil {
+		opt.ReplayCache = replay.NewInMemoryCache()
+	}
+	return &RequestObjectProcessor{
+		clientStore:  opt.ClientStore,
+		requestStore: opt.RequestStore,
+		jwkResolver:  opt.JwkStoreResolver,
+		replayCache:  opt.ReplayCache,
+		issuer:       opt.Issuer,
+	}
+}
+
+// consumeFunc marks resolved "request_uri" or request object as used
+type consumeFunc func(ctx context.Context) error
+
+func (p *RequestObjectProcessor) Process(ctx context.Context, request *auth.AuthorizeRequest, chain auth.AuthorizeRequestProcessChain) (validated *auth.AuthorizeRequest, err error) {
+	reqUri, uriOk := request.Parameters[oauth2.ParameterRequestUri]
+	reqObj, objOk := request.Parameters[oauth2.ParameterRequestObj]
+	var resolved *auth.AuthorizeRequest
+	var consume consumeFunc
+	switch {
+	case !uriOk && !objOk:
+		return chain.Next(ctx, request)
+	case uriOk && objOk:
+		return nil, oauth2.NewInvalidAuthorizeRequestError(fmt.Sprintf("%s and %s are exclusive", oauth2.ParameterRequestUri, oauth2.ParameterRequestObj))
+	case uriOk:
+		resolved, consume, err = p.resolvePushedRequest(ctx, request, reqUri)
+	default:
+		resolved, consume, err = p.resolveRequestObject(ctx, request, reqObj)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if e := p.validateResolved(request, resolved); e != nil {
+		return nil, e
+	}
+
+	if validated, err = chain.Next(ctx, resolved); err != nil {
+		return nil, err
+	}
+	// Note: processors down the chain may clear the authentication, e.g. "prompt=login" or "max_age".
+	// In such case, user would come back with same request after login
+	if security.IsFullyAuthenticated(security.Get(ctx)) {
+		if e := consume(ctx); e != nil {
+			return nil, e
+		}
+	}
+	return validated, nil
+}
+
+func (p *RequestObjectProcessor) resolvePushedRequest(ctx context.Context, request *auth.AuthorizeRequest, reqUri string) (*auth.AuthorizeRequest, consumeFunc, error) {
+	if p.requestStore == nil || !auth.IsPushedRequestUri(reqUri) {
+		return nil, nil, oauth2.NewInvalidRequestUriError(fmt.Sprintf("%s is only supported via pushed authorization request", oauth2.ParameterRequestUri))
+	}
+
+	pushed, e := p.requestStore.LoadPushedAuthorizeRequest(ctx, reqUri)
+	if e != nil {
+		return nil, nil, e
+	}
+	if pushed.ClientId != request.ClientId {
+		return nil, nil, oauth2.NewInvalidRequestUriError(fmt.Sprintf("%s was not issued to the client", oauth2.ParameterRequestUri))
+	}
+
+	values := make(map[string]interface{}, len(pushed.Parameters))
+	for k, v := range pushed.Parameters {
+		values[k] = v
+	}
+	//nolint:contextcheck
+	resolved, e := auth.ParseAuthorizeRequestWithKVs(request.Context(), values)
+	if e != nil {
+		return nil, nil, e
+	}
+	return resolved, func(ctx context.Context) error {
+		return p.requestStore.ConsumePushedAuthorizeRequest(ctx, reqUri)
+	}, nil
+}
+
+func (p *RequestObjectProcessor) resolveRequestObject(ctx context.Context, request *auth.AuthorizeRequest, reqObj string) (*auth.AuthorizeRequest, consumeFunc, error) {
+	client, e := auth.LoadAndValidateClientId(ctx, request.ClientId, p.clientStore)
+	if e != nil {
+		return nil, nil, e
+	}
+
+	store, e := p.jwkResolver.Resolve(ctx, client)
+	if e != nil {
+		return nil, nil, oauth2.NewInvalidRequestObjectError("unable to resolve client's JWK set", e)
+	}
+	decoder := jwt.NewSignedJwtDecoder(jwt.VerifyWithJwkStore(store, ""))
+	claims, e := decoder.Decode(ctx, reqObj)
+	if e != nil {
+		return nil, nil, oauth2.NewInvalidRequestObjectError("invalid request object", e)
+	}
+
+	values := claims.Values()
+	if e := p.validateClaims(client, values); e != nil {
+		return nil, nil, e
+	}
+	consume := p.requestObjectConsumer(client, reqObj, values)
+	for k, v := range values {
+		if registeredClaims.Has(k) {
+			delete(values, k)
+			continue
+		}
+		values[k] = stringValue(v)
+	}
+	//nolint:contextcheck
+	resolved, e := auth.ParseAuthorizeRequestWithKVs(request.Context(), values)
+	if e != nil {
+		return nil, nil, e
+	}
+	return resolved, consume, nil
+}
+
+// requestObjectConsumer records "jti" of the request object (or its hash if "jti" is absent) until it expires.
+func (p *RequestObjectProcessor) requestObjectConsumer(client oauth2.OAuth2Client, reqObj string, values map[string]interface{}) consumeFunc {
+	id, ok := values[oauth2.ClaimJwtId].(string)
+	if !ok || id == "" {
+		hash := sha256.Sum256([]byte(reqObj))
+		id = hex.EncodeToString(hash[:])
+	}
+	key := fmt.Sprintf("%s:%s:%s", prefixUsedRequestObject, client.ClientId(), id)
+	exp := claimTime(values[oauth2.ClaimExpire])
+	return func(ctx context.Context) error {
+		switch ok, e := p.replayCache.Add(ctx, key, time.Until(exp)); {
+		case e != nil:
+			return oauth2.NewInternalError("unable to check request object replay", e)
+		case !ok:
+			return oauth2.NewInvalidRequestObjectError("request object was already used")
+		}
+		return nil
+	}
+}
+
+// validateResolved checks "response_type" and "openid" scope of the original request against the resolved request.
+// See https://openid.net/specs/openid-connect-core-1_0.html#RequestObject
+func (p *RequestObjectProcessor) validateResolved(request *auth.AuthorizeRequest, resolved *auth.AuthorizeRequest) error {
+	switch {
+	case len(request.ResponseTypes) != 0 && !request.ResponseTypes.Equals(resolved.ResponseTypes):
+		return oauth2.NewInvalidAuthorizeRequestError(`inconsistent "response_type" in request and resolved request`)
+	case request.Scopes.Has(oauth2.ScopeOidc) && !resolved.Scopes.Has(oauth2.ScopeOidc):
+		return oauth2.NewInvalidAuthorizeRequestError(`resolved request is missing "openid" scope`)
+	}
+	return nil
+}
+
+func (p *RequestObjectProcessor) validateClaims(client oauth2.OAuth2Client, values map[string]interface{}) error {
+	if clientId, ok := values[oauth2.ParameterClientId]; !ok || clientId != client.ClientId() {
+		return oauth2.NewInvalidRequestObjectError(`request object's "client_id" should match the client`)
+	}
+	if iss, ok := values[oauth2.ClaimIssuer]; ok && iss != client.ClientId() {
+		return oauth2.NewInvalidRequestObjectError(`request object's "iss" should be the client ID`)
+	}
+	if p.issuer != nil && !hasAudience(values[oauth2.ClaimAudience], p.issuer.Identifier()) {
+		return oauth2.NewInvalidRequestObjectError(`request object's "aud" should be the issuer identifier`)
+	}
+	// expired request objects are rejected by decoder, here we make sure it would expire
+	if exp := claimTime(values[oauth2.ClaimExpire]); exp.IsZero() || time.Now().After(exp) {
+		return oauth2.NewInvalidRequestObjectError(`request object is expired or missing "exp"`)
+	}
+	return nil
+}
+
+/*********************
+	Helpers
+ *********************/
+
+func hasAudience(aud interface{}, expected string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == expected
+	case []interface{}:
+		for _, item := range v {
+			if item == expected {
+				return true
+			}
+		}
+	case []string:
+		for _, item := range v {
+			if item == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func claimTime(v interface{}) time.Time {
+	switch t := v.(type) {
+	case float64:
+		return time.Unix(int64(t), 0)
+	case int:
+		return time.Unix(int64(t), 0)
+	case int64:
+		return time.Unix(t, 0)
+	case json.Number:
+		if n, e := t.Int64(); e == nil {
+			return time.Unix(n, 0)
+		}
+	case time.Time:
+		return t
+	}
+	return time.Time{}
+}
+
+// stringValue converts claim value to authorize request parameter value. Non-string values (e.g. "claims" or "max_age")
+// are converted to their JSON representation
+func stringValue(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
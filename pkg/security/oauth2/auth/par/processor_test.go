@@ -0,0 +1,374 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package par
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/clientauth"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/sectest"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"testing"
+	"time"
+)
+
+/*************************
+	Setup
+ *************************/
+
+const (
+	TestClientID    = "test-client"
+	TestOtherClient = "test-other-client"
+	TestClientKid   = "test-client-kid"
+	TestRedirectUri = "http://localhost/test/callback"
+)
+
+type testClientStore map[string]oauth2.OAuth2Client
+
+func (s testClientStore) LoadClientByClientId(_ context.Context, clientId string) (oauth2.OAuth2Client, error) {
+	if c, ok := s[clientId]; ok {
+		return c, nil
+	}
+	return nil, errors.New("client not found")
+}
+
+type testRequestStore map[string]*auth.PushedAuthorizeRequest
+
+func (s testRequestStore) SavePushedAuthorizeRequest(_ context.Context, clientId string, params map[string]string, validity time.Duration) (*auth.PushedAuthorizeRequest, error) {
+	req := &auth.PushedAuthorizeRequest{
+		RequestUri: auth.RequestUriPrefixPAR + utils.RandomString(16),
+		ClientId:   clientId,
+		Parameters: params,
+		ExpireAt:   time.Now().Add(validity),
+	}
+	s[req.RequestUri] = req
+	return req, nil
+}
+
+func (s testRequestStore) LoadPushedAuthorizeRequest(_ context.Context, requestUri string) (*auth.PushedAuthorizeRequest, error) {
+	if req, ok := s[requestUri]; ok && !req.Expired() {
+		return req, nil
+	}
+	return nil, oauth2.NewInvalidRequestUriError("request_uri is invalid or expired")
+}
+
+func (s testRequestStore) ConsumePushedAuthorizeRequest(_ context.Context, requestUri string) error {
+	if _, ok := s[requestUri]; !ok {
+		return oauth2.NewInvalidRequestUriError("request_uri was already used")
+	}
+	delete(s, requestUri)
+	return nil
+}
+
+func newTestIssuer() security.Issuer {
+	return security.NewIssuer(func(details *security.DefaultIssuerDetails) {
+		details.Protocol = "http"
+		details.Domain = "localhost"
+		details.Port = 8080
+		details.ContextPath = "/auth"
+		details.IncludePort = true
+	})
+}
+
+func newTestProcessor(jwkStore jwt.JwkStore, reqStore auth.PushedAuthorizeRequestStore) auth.AuthorizeRequestProcessor {
+	p := NewRequestObjectProcessor(func(opt *ProcessorOption) {
+		opt.ClientStore = testClientStore{
+			TestClientID: auth.NewClientWithDetails(auth.ClientDetails{
+				ClientId:     TestClientID,
+				Scopes:       utils.NewStringSet("read", "write"),
+				RedirectUris: utils.NewStringSet(TestRedirectUri),
+			}),
+		}
+		opt.RequestStore = reqStore
+		opt.JwkStoreResolver = clientauth.StaticJwkStoreResolver{
+			TestClientID: jwkStore,
+		}
+		opt.Issuer = newTestIssuer()
+	})
+	return auth.NewAuthorizeRequestProcessor(p)
+}
+
+/*************************
+	Test
+ *************************/
+
+func TestRequestObjectProcessor(t *testing.T) {
+	jwkStore := jwt.NewSingleJwkStoreWithOptions(func(s *jwt.SingleJwkStore) {
+		s.Kid = TestClientKid
+	})
+	reqStore := testRequestStore{}
+	processor := newTestProcessor(jwkStore, reqStore)
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestPlainRequest(processor), "PlainRequest"),
+		test.GomegaSubTest(SubTestSignedRequestObject(processor, jwkStore), "SignedRequestObject"),
+		test.GomegaSubTest(SubTestRequestObjectWithWrongKey(processor), "RequestObjectWithWrongKey"),
+		test.GomegaSubTest(SubTestRequestObjectWithWrongClaims(processor, jwkStore), "RequestObjectWithWrongClaims"),
+		test.GomegaSubTest(SubTestRequestObjectWithoutExp(processor, jwkStore), "RequestObjectWithoutExp"),
+		test.GomegaSubTest(SubTestInconsistentRequestObject(processor, jwkStore), "InconsistentRequestObject"),
+		test.GomegaSubTest(SubTestRequestObjectReplay(processor, jwkStore), "RequestObjectReplay"),
+		test.GomegaSubTest(SubTestPushedRequestUri(processor, reqStore), "PushedRequestUri"),
+		test.GomegaSubTest(SubTestInvalidRequestUri(processor, reqStore), "InvalidRequestUri"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestPlainRequest(processor auth.AuthorizeRequestProcessor) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		req := newTestAuthorizeRequest(ctx, g, map[string]interface{}{
+			oauth2.ParameterResponseType: "code",
+			oauth2.ParameterRedirectUri:  TestRedirectUri,
+			oauth2.ParameterScope:        "read",
+		})
+		rs, e := processor.Process(ctx, req)
+		g.Expect(e).To(Succeed(), "processing plain request should not fail")
+		g.Expect(rs).To(BeIdenticalTo(req), "plain request should not be changed")
+	}
+}
+
+func SubTestSignedRequestObject(processor auth.AuthorizeRequestProcessor, jwkStore jwt.JwkStore) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		reqObj := signTestRequestObject(ctx, g, jwkStore, oauth2.MapClaims{
+			oauth2.ClaimIssuer:           TestClientID,
+			oauth2.ClaimAudience:         newTestIssuer().Identifier(),
+			oauth2.ClaimExpire:           time.Now().Add(time.Minute).Unix(),
+			oauth2.ParameterClientId:     TestClientID,
+			oauth2.ParameterResponseType: "code",
+			oauth2.ParameterRedirectUri:  TestRedirectUri,
+			oauth2.ParameterScope:        "read write",
+			oauth2.ParameterState:        "test-state",
+			"max_age":                    300,
+		})
+		req := newTestAuthorizeRequest(ctx, g, map[string]interface{}{
+			oauth2.ParameterResponseType: "code",
+			oauth2.ParameterScope:        "read",
+			oauth2.ParameterRequestObj:   reqObj,
+		})
+		rs, e := processor.Process(ctx, req)
+		g.Expect(e).To(Succeed(), "processing request object should not fail")
+		g.Expect(rs.ClientId).To(Equal(TestClientID))
+		g.Expect(rs.RedirectUri).To(Equal(TestRedirectUri))
+		g.Expect(rs.State).To(Equal("test-state"))
+		g.Expect(rs.Scopes).To(Equal(utils.NewStringSet("read", "write")), "scopes should be from request object")
+		g.Expect(rs.Parameters).To(HaveKeyWithValue("max_age", "300"))
+		g.Expect(rs.Parameters).ToNot(HaveKey(oauth2.ClaimIssuer), "registered claims should be removed")
+		g.Expect(rs.Parameters).ToNot(HaveKey(oauth2.ClaimAudience), "registered claims should be removed")
+		g.Expect(rs.Parameters).ToNot(HaveKey(oauth2.ParameterRequestObj), "request object should not be carried over")
+	}
+}
+
+func SubTestRequestObjectWithWrongKey(processor auth.AuthorizeRequestProcessor) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		otherStore := jwt.NewSingleJwkStoreWithOptions(func(s *jwt.SingleJwkStore) {
+			s.Kid = TestClientKid
+		})
+		reqObj := signTestRequestObject(ctx, g, otherStore, oauth2.MapClaims{
+			oauth2.ClaimAudience:         newTestIssuer().Identifier(),
+			oauth2.ParameterClientId:     TestClientID,
+			oauth2.ParameterResponseType: "code",
+		})
+		req := newTestAuthorizeRequest(ctx, g, map[string]interface{}{
+			oauth2.ParameterRequestObj: reqObj,
+		})
+		_, e := processor.Process(ctx, req)
+		assertOAuth2Error(g, e, oauth2.ErrorTranslationInvalidRequestObj, "request object signed by other key should be rejected")
+	}
+}
+
+func SubTestRequestObjectWithWrongClaims(processor auth.AuthorizeRequestProcessor, jwkStore jwt.JwkStore) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		cases := []oauth2.MapClaims{
+			// wrong client_id
+			{oauth2.ClaimAudience: newTestIssuer().Identifier(), oauth2.ParameterClientId: TestOtherClient},
+			// wrong issuer
+			{oauth2.ClaimAudience: newTestIssuer().Identifier(), oauth2.ParameterClientId: TestClientID, oauth2.ClaimIssuer: TestOtherClient},
+			// wrong audience
+			{oauth2.ClaimAudience: "http://other.host/auth", oauth2.ParameterClientId: TestClientID},
+		}
+		for _, claims := range cases {
+			reqObj := signTestRequestObject(ctx, g, jwkStore, claims)
+			req := newTestAuthorizeRequest(ctx, g, map[string]interface{}{
+				oauth2.ParameterRequestObj: reqObj,
+			})
+			_, e := processor.Process(ctx, req)
+			g.Expect(e).To(HaveOccurred(), "request object with invalid claims should be rejected: %v", claims)
+		}
+	}
+}
+
+func SubTestRequestObjectWithoutExp(processor auth.AuthorizeRequestProcessor, jwkStore jwt.JwkStore) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		reqObj := signTestRequestObject(ctx, g, jwkStore, oauth2.MapClaims{
+			oauth2.ClaimAudience:         newTestIssuer().Identifier(),
+			oauth2.ParameterClientId:     TestClientID,
+			oauth2.ParameterResponseType: "code",
+		})
+		_, e := processor.Process(ctx, newTestAuthorizeRequest(ctx, g, map[string]interface{}{
+			oauth2.ParameterRequestObj: reqObj,
+		}))
+		assertOAuth2Error(g, e, oauth2.ErrorTranslationInvalidRequestObj, "request object without exp should be rejected")
+	}
+}
+
+func SubTestInconsistentRequestObject(processor auth.AuthorizeRequestProcessor, jwkStore jwt.JwkStore) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		reqObj := signTestRequestObject(ctx, g, jwkStore, oauth2.MapClaims{
+			oauth2.ClaimAudience:         newTestIssuer().Identifier(),
+			oauth2.ClaimExpire:           time.Now().Add(time.Minute).Unix(),
+			oauth2.ParameterClientId:     TestClientID,
+			oauth2.ParameterResponseType: "code",
+			oauth2.ParameterScope:        "read",
+		})
+		_, e := processor.Process(ctx, newTestAuthorizeRequest(ctx, g, map[string]interface{}{
+			oauth2.ParameterResponseType: "token",
+			oauth2.ParameterRequestObj:   reqObj,
+		}))
+		assertOAuth2Error(g, e, oauth2.ErrorTranslationInvalidRequest, "inconsistent response_type should be rejected")
+
+		_, e = processor.Process(ctx, newTestAuthorizeRequest(ctx, g, map[string]interface{}{
+			oauth2.ParameterResponseType: "code",
+			oauth2.ParameterScope:        "openid",
+			oauth2.ParameterRequestObj:   reqObj,
+		}))
+		assertOAuth2Error(g, e, oauth2.ErrorTranslationInvalidRequest, "request object without openid scope should be rejected")
+	}
+}
+
+func SubTestRequestObjectReplay(processor auth.AuthorizeRequestProcessor, jwkStore jwt.JwkStore) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		reqObj := signTestRequestObject(ctx, g, jwkStore, oauth2.MapClaims{
+			oauth2.ClaimAudience:         newTestIssuer().Identifier(),
+			oauth2.ClaimExpire:           time.Now().Add(time.Minute).Unix(),
+			oauth2.ClaimJwtId:            utils.RandomString(16),
+			oauth2.ParameterClientId:     TestClientID,
+			oauth2.ParameterResponseType: "code",
+			oauth2.ParameterRedirectUri:  TestRedirectUri,
+		})
+		_, e := processor.Process(ctx, newTestAuthorizeRequest(ctx, g, map[string]interface{}{
+			oauth2.ParameterRequestObj: reqObj,
+		}))
+		g.Expect(e).To(Succeed(), "request object should be reusable before user is authenticated")
+
+		authCtx := sectest.ContextWithSecurity(ctx, sectest.MockedAuthentication())
+		_, e = processor.Process(authCtx, newTestAuthorizeRequest(authCtx, g, map[string]interface{}{
+			oauth2.ParameterRequestObj: reqObj,
+		}))
+		g.Expect(e).To(Succeed(), "processing request object with authenticated user should not fail")
+		_, e = processor.Process(authCtx, newTestAuthorizeRequest(authCtx, g, map[string]interface{}{
+			oauth2.ParameterRequestObj: reqObj,
+		}))
+		assertOAuth2Error(g, e, oauth2.ErrorTranslationInvalidRequestObj, "replayed request object should be rejected")
+	}
+}
+
+func SubTestPushedRequestUri(processor auth.AuthorizeRequestProcessor, reqStore auth.PushedAuthorizeRequestStore) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		pushed, e := reqStore.SavePushedAuthorizeRequest(ctx, TestClientID, map[string]string{
+			oauth2.ParameterClientId:     TestClientID,
+			oauth2.ParameterResponseType: "code",
+			oauth2.ParameterRedirectUri:  TestRedirectUri,
+			oauth2.ParameterScope:        "write",
+		}, time.Minute)
+		g.Expect(e).To(Succeed(), "saving pushed request should not fail")
+
+		req := newTestAuthorizeRequest(ctx, g, map[string]interface{}{
+			oauth2.ParameterRequestUri: pushed.RequestUri,
+		})
+		rs, e := processor.Process(ctx, req)
+		g.Expect(e).To(Succeed(), "processing request_uri should not fail")
+		g.Expect(rs.RedirectUri).To(Equal(TestRedirectUri))
+		g.Expect(rs.Scopes).To(Equal(utils.NewStringSet("write")), "scopes should be from pushed request")
+		g.Expect(rs.Parameters).ToNot(HaveKey(oauth2.ParameterRequestUri))
+
+		// request_uri can be used again before user is authenticated, e.g. revisited after login
+		_, e = processor.Process(ctx, newTestAuthorizeRequest(ctx, g, map[string]interface{}{
+			oauth2.ParameterRequestUri: pushed.RequestUri,
+		}))
+		g.Expect(e).To(Succeed(), "request_uri should be reusable before user is authenticated")
+
+		// request_uri is consumed once used by authenticated user
+		authCtx := sectest.ContextWithSecurity(ctx, sectest.MockedAuthentication())
+		_, e = processor.Process(authCtx, newTestAuthorizeRequest(authCtx, g, map[string]interface{}{
+			oauth2.ParameterRequestUri: pushed.RequestUri,
+		}))
+		g.Expect(e).To(Succeed(), "processing request_uri with authenticated user should not fail")
+		_, e = processor.Process(authCtx, newTestAuthorizeRequest(authCtx, g, map[string]interface{}{
+			oauth2.ParameterRequestUri: pushed.RequestUri,
+		}))
+		assertOAuth2Error(g, e, oauth2.ErrorTranslationInvalidRequestURI, "request_uri should not be used twice")
+	}
+}
+
+func SubTestInvalidRequestUri(processor auth.AuthorizeRequestProcessor, reqStore auth.PushedAuthorizeRequestStore) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		// not issued by PAR endpoint
+		_, e := processor.Process(ctx, newTestAuthorizeRequest(ctx, g, map[string]interface{}{
+			oauth2.ParameterRequestUri: "https://client.example.org/request.jwt",
+		}))
+		assertOAuth2Error(g, e, oauth2.ErrorTranslationInvalidRequestURI, "non-PAR request_uri should be rejected")
+
+		// unknown
+		_, e = processor.Process(ctx, newTestAuthorizeRequest(ctx, g, map[string]interface{}{
+			oauth2.ParameterRequestUri: auth.RequestUriPrefixPAR + "unknown",
+		}))
+		assertOAuth2Error(g, e, oauth2.ErrorTranslationInvalidRequestURI, "unknown request_uri should be rejected")
+
+		// issued to other client
+		pushed, e := reqStore.SavePushedAuthorizeRequest(ctx, TestOtherClient, map[string]string{
+			oauth2.ParameterClientId: TestOtherClient,
+		}, time.Minute)
+		g.Expect(e).To(Succeed(), "saving pushed request should not fail")
+		_, e = processor.Process(ctx, newTestAuthorizeRequest(ctx, g, map[string]interface{}{
+			oauth2.ParameterRequestUri: pushed.RequestUri,
+		}))
+		assertOAuth2Error(g, e, oauth2.ErrorTranslationInvalidRequestURI, "request_uri of other client should be rejected")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func assertOAuth2Error(g *gomega.WithT, e error, expectedCode string, msg string) {
+	g.Expect(e).To(HaveOccurred(), msg)
+	var translator oauth2.OAuth2ErrorTranslator
+	g.Expect(errors.As(e, &translator)).To(BeTrue(), "error should be OAuth2 error")
+	g.Expect(translator.TranslateErrorCode()).To(Equal(expectedCode), msg)
+}
+
+func newTestAuthorizeRequest(ctx context.Context, g *gomega.WithT, values map[string]interface{}) *auth.AuthorizeRequest {
+	values[oauth2.ParameterClientId] = TestClientID
+	req, e := auth.ParseAuthorizeRequestWithKVs(ctx, values)
+	g.Expect(e).To(Succeed(), "creating authorize request should not fail")
+	return req
+}
+
+func signTestRequestObject(ctx context.Context, g *gomega.WithT, jwkStore jwt.JwkStore, claims oauth2.MapClaims) string {
+	encoder := jwt.NewSignedJwtEncoder(jwt.SignWithJwkStore(jwkStore, TestClientKid))
+	reqObj, e := encoder.Encode(ctx, claims)
+	g.Expect(e).To(Succeed(), "signing request object should not fail")
+	return reqObj
+}
@@ -0,0 +1,153 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/redis"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"strings"
+	"time"
+)
+
+const (
+	// RequestUriPrefixPAR is the URN prefix of "request_uri" issued by pushed authorization request endpoint
+	// See https://datatracker.ietf.org/doc/html/rfc9126#section-2.2
+	RequestUriPrefixPAR = "urn:ietf:params:oauth:request_uri:"
+
+	defaultRequestUriLength = 32
+	pushedRequestPrefix     = "PAR"
+)
+
+// PushedAuthorizeRequest is the authorization request parameters pushed by client, referenced by RequestUri.
+// See https://datatracker.ietf.org/doc/html/rfc9126
+type PushedAuthorizeRequest struct {
+	RequestUri string            `json:"requestUri"`
+	ClientId   string            `json:"clientId"`
+	Parameters map[string]string `json:"parameters"`
+	ExpireAt   time.Time         `json:"expireAt"`
+}
+
+func (r *PushedAuthorizeRequest) Expired() bool {
+	return !r.ExpireAt.IsZero() && time.Now().After(r.ExpireAt)
+}
+
+// ExpiresIn returns remaining validity in seconds
+func (r *PushedAuthorizeRequest) ExpiresIn() int {
+	if r.Expired() {
+		return 0
+	}
+	return int(time.Until(r.ExpireAt).Seconds())
+}
+
+// IsPushedRequestUri returns true if given "request_uri" is issued by pushed authorization request endpoint
+func IsPushedRequestUri(requestUri string) bool {
+	return strings.HasPrefix(requestUri, RequestUriPrefixPAR)
+}
+
+/**********************
+	Abstraction
+ **********************/
+
+// PushedAuthorizeRequestStore persists PushedAuthorizeRequest between pushed authorization request endpoint and
+// authorize endpoint.
+// The request_uri is invalid after it's consumed or expired.
+type PushedAuthorizeRequestStore interface {
+	// SavePushedAuthorizeRequest generates a new "request_uri" and saves given parameters with given validity
+	SavePushedAuthorizeRequest(ctx context.Context, clientId string, params map[string]string, validity time.Duration) (*PushedAuthorizeRequest, error)
+	// LoadPushedAuthorizeRequest load PushedAuthorizeRequest by "request_uri"
+	LoadPushedAuthorizeRequest(ctx context.Context, requestUri string) (*PushedAuthorizeRequest, error)
+	// ConsumePushedAuthorizeRequest atomically removes PushedAuthorizeRequest by "request_uri".
+	// It returns error if the "request_uri" was already consumed, e.g. by a concurrent request
+	ConsumePushedAuthorizeRequest(ctx context.Context, requestUri string) error
+}
+
+/**********************
+	Redis Impl
+ **********************/
+
+// RedisPushedAuthorizeRequestStore store PushedAuthorizeRequest in Redis
+type RedisPushedAuthorizeRequestStore struct {
+	redisClient redis.Client
+}
+
+func NewRedisPushedAuthorizeRequestStore(ctx context.Context, cf redis.ClientFactory, dbIndex int) *RedisPushedAuthorizeRequestStore {
+	client, e := cf.New(ctx, func(opt *redis.ClientOption) {
+		opt.DbIndex = dbIndex
+	})
+	if e != nil {
+		panic(e)
+	}
+
+	return &RedisPushedAuthorizeRequestStore{
+		redisClient: client,
+	}
+}
+
+func (s *RedisPushedAuthorizeRequestStore) SavePushedAuthorizeRequest(ctx context.Context, clientId string, params map[string]string, validity time.Duration) (*PushedAuthorizeRequest, error) {
+	if validity <= 0 {
+		return nil, oauth2.NewInternalError("invalid pushed authorization request validity")
+	}
+	par := &PushedAuthorizeRequest{
+		RequestUri: RequestUriPrefixPAR + utils.RandomStringWithCharset(defaultRequestUriLength, utils.CharsetAlphanumeric),
+		ClientId:   clientId,
+		Parameters: params,
+		ExpireAt:   time.Now().Add(validity),
+	}
+
+	toSave, e := json.Marshal(par)
+	if e != nil {
+		return nil, oauth2.NewInternalError(e)
+	}
+	if cmd := s.redisClient.Set(ctx, s.redisKey(par.RequestUri), toSave, validity); cmd.Err() != nil {
+		return nil, oauth2.NewInternalError(cmd.Err())
+	}
+	return par, nil
+}
+
+func (s *RedisPushedAuthorizeRequestStore) LoadPushedAuthorizeRequest(ctx context.Context, requestUri string) (*PushedAuthorizeRequest, error) {
+	if !IsPushedRequestUri(requestUri) {
+		return nil, oauth2.NewInvalidRequestUriError(fmt.Sprintf("request_uri [%s] is not valid", requestUri))
+	}
+	cmd := s.redisClient.Get(ctx, s.redisKey(requestUri))
+	if cmd.Err() != nil {
+		return nil, oauth2.NewInvalidRequestUriError(fmt.Sprintf("request_uri [%s] is not valid or expired", requestUri))
+	}
+
+	toLoad := PushedAuthorizeRequest{}
+	if e := json.Unmarshal([]byte(cmd.Val()), &toLoad); e != nil || toLoad.Expired() {
+		return nil, oauth2.NewInvalidRequestUriError(fmt.Sprintf("request_uri [%s] is not valid or expired", requestUri))
+	}
+	return &toLoad, nil
+}
+
+func (s *RedisPushedAuthorizeRequestStore) ConsumePushedAuthorizeRequest(ctx context.Context, requestUri string) error {
+	switch n, e := s.redisClient.Del(ctx, s.redisKey(requestUri)).Result(); {
+	case e != nil:
+		return oauth2.NewInternalError(e)
+	case n == 0:
+		return oauth2.NewInvalidRequestUriError(fmt.Sprintf("request_uri [%s] was already used or expired", requestUri))
+	}
+	return nil
+}
+
+func (s *RedisPushedAuthorizeRequestStore) redisKey(requestUri string) string {
+	return fmt.Sprintf("%s:%s", pushedRequestPrefix, strings.TrimPrefix(requestUri, RequestUriPrefixPAR))
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registration
+
+import (
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/errorhandling"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+	"github.com/cisco-open/go-lanai/pkg/web/mapping"
+	"github.com/cisco-open/go-lanai/pkg/web/matcher"
+	"net/http"
+)
+
+var (
+	FeatureId = security.FeatureId("OAuth2ClientRegistration", security.FeatureOrderOAuth2TokenEndpoint)
+)
+
+type RegistrationEndpointConfigurer struct{}
+
+func newRegistrationEndpointConfigurer() *RegistrationEndpointConfigurer {
+	return &RegistrationEndpointConfigurer{}
+}
+
+func (c *RegistrationEndpointConfigurer) Apply(feature security.Feature, ws security.WebSecurity) (err error) {
+	// Verify
+	f := feature.(*RegistrationFeature)
+	if err := c.validate(f); err != nil {
+		return err
+	}
+
+	// configure other dependent features
+	errorhandling.Configure(ws).
+		AdditionalErrorHandler(f.errorHandler)
+
+	mw := NewRegistrationMiddleware(func(opt *RegistrationMWOption) {
+		opt.Path = f.path
+		opt.Issuer = f.issuer
+		opt.ClientRegistry = f.registry
+		opt.InitialAccessPolicy = f.policy
+		opt.ClientSecretEncoder = f.secretEncoder
+		opt.Validator = &MetadataValidator{
+			AllowedGrantTypes: f.grantTypes,
+			AllowedScopes:     f.scopes,
+			DefaultScopes:     f.defaultScopes,
+		}
+	})
+
+	// install endpoints
+	clientPath := fmt.Sprintf("%s/:%s", f.path, PathParamClientId)
+	routeMatcher := matcher.RouteWithPattern(f.path, http.MethodPost).
+		Or(matcher.RouteWithPattern(fmt.Sprintf("%s/*", f.path), http.MethodGet, http.MethodPut, http.MethodDelete))
+	epRegister := mapping.Post(f.path).Name("client registration").
+		HandlerFunc(mw.RegisterHandlerFunc())
+	epRead := mapping.Get(clientPath).Name("client configuration GET").
+		HandlerFunc(mw.ReadHandlerFunc())
+	epUpdate := mapping.Put(clientPath).Name("client configuration PUT").
+		HandlerFunc(mw.UpdateHandlerFunc())
+	epDelete := mapping.Delete(clientPath).Name("client configuration DELETE").
+		HandlerFunc(mw.DeleteHandlerFunc())
+
+	ws.Route(routeMatcher).Add(epRegister, epRead, epUpdate, epDelete)
+	return nil
+}
+
+func (c *RegistrationEndpointConfigurer) validate(f *RegistrationFeature) error {
+	switch {
+	case f.path == "":
+		return fmt.Errorf("client registration endpoint path is not set")
+	case f.issuer == nil:
+		return fmt.Errorf("issuer is not set")
+	case f.registry == nil:
+		return fmt.Errorf("client registry is not set")
+	case f.secretEncoder == nil:
+		return fmt.Errorf("client secret encoder is not set")
+	}
+	if f.errorHandler == nil {
+		f.errorHandler = auth.NewOAuth2ErrorHandler()
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package datastore provides registration.ClientRegistry backed by pkg/data
+package datastore
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/data"
+	"github.com/cisco-open/go-lanai/pkg/data/repo"
+	"github.com/cisco-open/go-lanai/pkg/data/types/pqx"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/registration"
+	"time"
+)
+
+// ClientRecord is the persisted model of registration.Registration
+type ClientRecord struct {
+	ClientId              string         `gorm:"primaryKey;type:varchar(64)"`
+	ClientSecret          string         `gorm:"type:varchar(256)"`
+	IssuedAt              time.Time      `gorm:"not null"`
+	RegistrationTokenHash string         `gorm:"type:varchar(64);not null"`
+	Metadata              ClientMetadata `gorm:"not null"`
+	UpdatedAt             time.Time
+}
+
+func (ClientRecord) TableName() string {
+	return "oauth2_clients"
+}
+
+// ClientMetadata stores registration.ClientMetadata as JSONB
+type ClientMetadata registration.ClientMetadata
+
+// Value implements driver.Valuer
+func (m ClientMetadata) Value() (driver.Value, error) {
+	return pqx.JsonbValue(m)
+}
+
+// Scan implements sql.Scanner
+func (m *ClientMetadata) Scan(src interface{}) error {
+	return pqx.JsonbScan(src, m)
+}
+
+func (m ClientMetadata) GormDataType() string {
+	return "jsonb"
+}
+
+// GormClientRegistry implements registration.ClientRegistry and oauth2.OAuth2ClientStore using repo.CrudRepository.
+// Statically configured clients can be served by the optional delegate oauth2.OAuth2ClientStore
+type GormClientRegistry struct {
+	repo     repo.CrudRepository
+	delegate oauth2.OAuth2ClientStore
+}
+
+type RegistryOptions func(opt *RegistryOption)
+
+type RegistryOption struct {
+	Delegate oauth2.OAuth2ClientStore
+}
+
+func NewGormClientRegistry(factory repo.Factory, opts ...RegistryOptions) *GormClientRegistry {
+	opt := RegistryOption{}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	return &GormClientRegistry{
+		repo:     factory.NewCRUD(&ClientRecord{}),
+		delegate: opt.Delegate,
+	}
+}
+
+func (r *GormClientRegistry) LoadClientByClientId(ctx context.Context, clientId string) (oauth2.OAuth2Client, error) {
+	reg, e := r.LoadRegistration(ctx, clientId)
+	switch {
+	case e == nil:
+	case r.delegate != nil && isClientNotFound(e):
+		// only fall back to statically configured clients when the client is not registered.
+		// Other errors (e.g. DB unavailable) should not be masked by the delegate's "not found"
+		return r.delegate.LoadClientByClientId(ctx, clientId)
+	default:
+		return nil, e
+	}
+	client, e := reg.Client()
+	if e != nil {
+		return nil, oauth2.NewInternalError("invalid client registration", e)
+	}
+	return client, nil
+}
+
+func (r *GormClientRegistry) LoadRegistration(ctx context.Context, clientId string) (*registration.Registration, error) {
+	var record ClientRecord
+	// FindById treats non-UUID string as raw SQL condition, so query by column instead
+	switch e := r.repo.FindOneBy(ctx, &record, map[string]interface{}{"client_id": clientId}); {
+	case errors.Is(e, data.ErrorRecordNotFound):
+		return nil, oauth2.NewClientNotFoundError("client not found")
+	case e != nil:
+		return nil, oauth2.NewInternalError("unable to load client registration", e)
+	}
+	return &registration.Registration{
+		ClientId:              record.ClientId,
+		ClientSecret:          record.ClientSecret,
+		IssuedAt:              record.IssuedAt,
+		RegistrationTokenHash: record.RegistrationTokenHash,
+		Metadata:              registration.ClientMetadata(record.Metadata),
+	}, nil
+}
+
+func (r *GormClientRegistry) SaveRegistration(ctx context.Context, reg *registration.Registration) error {
+	record := ClientRecord{
+		ClientId:              reg.ClientId,
+		ClientSecret:          reg.ClientSecret,
+		IssuedAt:              reg.IssuedAt,
+		RegistrationTokenHash: reg.RegistrationTokenHash,
+		Metadata:              ClientMetadata(reg.Metadata),
+	}
+	return r.repo.Save(ctx, &record)
+}
+
+func (r *GormClientRegistry) DeleteRegistration(ctx context.Context, clientId string) error {
+	return r.repo.Delete(ctx, &ClientRecord{ClientId: clientId})
+}
+
+func isClientNotFound(err error) bool {
+	var oauthErr *oauth2.OAuth2Error
+	return errors.As(err, &oauthErr) && oauthErr.Code() == oauth2.ErrorCodeClientNotFound
+}
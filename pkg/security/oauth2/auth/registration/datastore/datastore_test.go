@@ -0,0 +1,218 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package datastore
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/data/repo"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth/registration"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/apptest"
+	"github.com/cisco-open/go-lanai/test/dbtest"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"go.uber.org/fx"
+	"gorm.io/gorm/schema"
+	"io/fs"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+/*************************
+	Setup
+ *************************/
+
+const (
+	TestRegisteredClientID = "test-registered-client"
+	TestStaticClientID     = "test-static-client"
+)
+
+//func TestMain(m *testing.M) {
+//	suitetest.RunTests(m,
+//		dbtest.EnableDBRecordMode(),
+//	)
+//}
+
+type testDI struct {
+	fx.In
+	dbtest.DI
+	Factory repo.Factory
+}
+
+func SetupTestPrepareTables(di *testDI) test.SetupFunc {
+	return dbtest.PrepareData(&di.DI,
+		dbtest.SetupUsingSQLFile(MigrationFS, "migrations/create_oauth2_clients.sql"),
+		dbtest.SetupTruncateTables(ClientRecord{}.TableName()),
+	)
+}
+
+type staticClientStore map[string]oauth2.OAuth2Client
+
+func (s staticClientStore) LoadClientByClientId(_ context.Context, clientId string) (oauth2.OAuth2Client, error) {
+	if c, ok := s[clientId]; ok {
+		return c, nil
+	}
+	return nil, oauth2.NewClientNotFoundError("client not found")
+}
+
+func NewTestRegistry(di *testDI) *GormClientRegistry {
+	return NewGormClientRegistry(di.Factory, func(opt *RegistryOption) {
+		opt.Delegate = staticClientStore{
+			TestStaticClientID: auth.NewClientWithDetails(auth.ClientDetails{ClientId: TestStaticClientID}),
+		}
+	})
+}
+
+/*************************
+	Test
+ *************************/
+
+func TestGormClientRegistry(t *testing.T) {
+	di := &testDI{}
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		dbtest.WithDBPlayback("testdb"),
+		apptest.WithModules(repo.Module),
+		apptest.WithDI(di),
+		test.SubTestSetup(SetupTestPrepareTables(di)),
+		test.GomegaSubTest(SubTestSaveAndLoad(di), "SaveAndLoad"),
+		test.GomegaSubTest(SubTestUpdate(di), "Update"),
+		test.GomegaSubTest(SubTestFallbackToDelegate(di), "FallbackToDelegate"),
+		test.GomegaSubTest(SubTestDBError(di), "DBError"),
+		test.GomegaSubTest(SubTestDelete(di), "Delete"),
+	)
+}
+
+func TestMigration(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestMigrationMatchesModel(), "MigrationMatchesModel"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestSaveAndLoad(di *testDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		registry := NewTestRegistry(di)
+		reg := NewTestRegistration()
+		g.Expect(registry.SaveRegistration(ctx, reg)).To(Succeed(), "save should not fail")
+
+		loaded, e := registry.LoadRegistration(ctx, TestRegisteredClientID)
+		g.Expect(e).To(Succeed(), "load registration should not fail")
+		g.Expect(loaded.IssuedAt).To(BeTemporally("==", reg.IssuedAt), "issued at should be correct")
+		g.Expect(loaded.RegistrationTokenHash).To(Equal(reg.RegistrationTokenHash), "token hash should be correct")
+		g.Expect(loaded.Metadata.RedirectUris).To(Equal(reg.Metadata.RedirectUris), "metadata should be correct")
+
+		client, e := registry.LoadClientByClientId(ctx, TestRegisteredClientID)
+		g.Expect(e).To(Succeed(), "load client should not fail")
+		g.Expect(client.ClientId()).To(Equal(TestRegisteredClientID), "client ID should be correct")
+		g.Expect(client.Scopes()).To(Equal(utils.NewStringSet("read", "write")), "client scopes should be correct")
+	}
+}
+
+func SubTestUpdate(di *testDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		registry := NewTestRegistry(di)
+		g.Expect(registry.SaveRegistration(ctx, NewTestRegistration())).To(Succeed(), "save should not fail")
+
+		reg := NewTestRegistration()
+		reg.RegistrationTokenHash = "updated-token-hash"
+		reg.Metadata.Scope = "read"
+		g.Expect(registry.SaveRegistration(ctx, reg)).To(Succeed(), "save existing registration should not fail")
+
+		loaded, e := registry.LoadRegistration(ctx, TestRegisteredClientID)
+		g.Expect(e).To(Succeed(), "load registration should not fail")
+		g.Expect(loaded.RegistrationTokenHash).To(Equal("updated-token-hash"), "token hash should be updated")
+		g.Expect(loaded.Metadata.Scope).To(Equal("read"), "metadata should be updated")
+	}
+}
+
+func SubTestFallbackToDelegate(di *testDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		registry := NewTestRegistry(di)
+		client, e := registry.LoadClientByClientId(ctx, TestStaticClientID)
+		g.Expect(e).To(Succeed(), "static client should be loaded from delegate")
+		g.Expect(client.ClientId()).To(Equal(TestStaticClientID), "client ID should be correct")
+
+		_, e = registry.LoadClientByClientId(ctx, "unknown-client")
+		g.Expect(e).To(HaveOccurred(), "unknown client should fail")
+		g.Expect(isClientNotFound(e)).To(BeTrue(), "error should be client not found")
+	}
+}
+
+func SubTestDBError(di *testDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		registry := NewTestRegistry(di)
+		// DB operations fail with cancelled context
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, e := registry.LoadClientByClientId(ctx, TestStaticClientID)
+		g.Expect(e).To(HaveOccurred(), "DB error should not fall back to delegate")
+		g.Expect(isClientNotFound(e)).To(BeFalse(), "DB error should not be reported as client not found")
+		g.Expect(errors.Is(e, oauth2.ErrorTypeOAuth2)).To(BeTrue(), "DB error should be translated to OAuth2 error")
+	}
+}
+
+func SubTestDelete(di *testDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		registry := NewTestRegistry(di)
+		g.Expect(registry.SaveRegistration(ctx, NewTestRegistration())).To(Succeed(), "save should not fail")
+		g.Expect(registry.DeleteRegistration(ctx, TestRegisteredClientID)).To(Succeed(), "delete should not fail")
+		_, e := registry.LoadRegistration(ctx, TestRegisteredClientID)
+		g.Expect(isClientNotFound(e)).To(BeTrue(), "deleted registration should not be found")
+	}
+}
+
+func SubTestMigrationMatchesModel() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		sql, e := fs.ReadFile(MigrationFS, "migrations/create_oauth2_clients.sql")
+		g.Expect(e).To(Succeed(), "migration SQL should be embedded")
+
+		s, e := schema.Parse(&ClientRecord{}, &sync.Map{}, schema.NamingStrategy{})
+		g.Expect(e).To(Succeed(), "parsing model schema should not fail")
+		g.Expect(string(sql)).To(ContainSubstring(s.Table), "migration should create table [%s]", s.Table)
+		for _, name := range s.DBNames {
+			g.Expect(strings.Contains(string(sql), name+" ")).To(BeTrue(), "migration should have column [%s]", name)
+		}
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func NewTestRegistration() *registration.Registration {
+	return &registration.Registration{
+		ClientId:              TestRegisteredClientID,
+		IssuedAt:              time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		RegistrationTokenHash: "test-token-hash",
+		Metadata: registration.ClientMetadata{
+			RedirectUris:            []string{"https://client.example.com/callback"},
+			TokenEndpointAuthMethod: oauth2.ClientAuthMethodSecretBasic,
+			GrantTypes:              []string{oauth2.GrantTypeAuthCode},
+			Scope:                   "read write",
+		},
+	}
+}
@@ -0,0 +1,11 @@
+-- Table oauth2_clients for dynamically registered OAuth2 clients
+CREATE TABLE IF NOT EXISTS oauth2_clients
+(
+    client_id               varchar(64)  NOT NULL,
+    client_secret           varchar(256),
+    issued_at               timestamptz  NOT NULL,
+    registration_token_hash varchar(64)  NOT NULL,
+    metadata                jsonb        NOT NULL,
+    updated_at              timestamptz,
+    CONSTRAINT "primary" PRIMARY KEY (client_id)
+);
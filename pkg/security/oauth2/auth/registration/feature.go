@@ -0,0 +1,117 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registration
+
+import (
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+	"github.com/cisco-open/go-lanai/pkg/security/passwd"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+)
+
+// RegistrationFeature configures client registration endpoint and client configuration endpoints.
+// See https://datatracker.ietf.org/doc/html/rfc7591 and https://datatracker.ietf.org/doc/html/rfc7592
+type RegistrationFeature struct {
+	path          string
+	issuer        security.Issuer
+	registry      ClientRegistry
+	policy        InitialAccessPolicy
+	secretEncoder passwd.PasswordEncoder
+	grantTypes    utils.StringSet
+	scopes        utils.StringSet
+	defaultScopes utils.StringSet
+	errorHandler  *auth.OAuth2ErrorHandler
+}
+
+func (f *RegistrationFeature) Identifier() security.FeatureIdentifier {
+	return FeatureId
+}
+
+// Configure is standard security.Feature entrypoint
+func Configure(ws security.WebSecurity) *RegistrationFeature {
+	feature := NewEndpoint()
+	if fc, ok := ws.(security.FeatureModifier); ok {
+		return fc.Enable(feature).(*RegistrationFeature)
+	}
+	panic(fmt.Errorf("unable to configure oauth2 authserver: provided WebSecurity [%T] doesn't support FeatureModifier", ws))
+}
+
+// NewEndpoint is standard security.Feature entrypoint, DSL style. Used with security.WebSecurity
+func NewEndpoint() *RegistrationFeature {
+	return &RegistrationFeature{
+		secretEncoder: passwd.NewNoopPasswordEncoder(),
+		grantTypes: utils.NewStringSet(
+			oauth2.GrantTypeAuthCode, oauth2.GrantTypeRefresh, oauth2.GrantTypeClientCredentials,
+		),
+		scopes:        utils.NewStringSet(oauth2.ScopeRead, oauth2.ScopeWrite, oauth2.ScopeOidc),
+		defaultScopes: utils.NewStringSet(oauth2.ScopeRead),
+		errorHandler:  auth.NewOAuth2ErrorHandler(),
+	}
+}
+
+/** Setters **/
+
+func (f *RegistrationFeature) Path(path string) *RegistrationFeature {
+	f.path = path
+	return f
+}
+
+// Issuer is used to build "registration_client_uri" of the response
+func (f *RegistrationFeature) Issuer(issuer security.Issuer) *RegistrationFeature {
+	f.issuer = issuer
+	return f
+}
+
+func (f *RegistrationFeature) ClientRegistry(registry ClientRegistry) *RegistrationFeature {
+	f.registry = registry
+	return f
+}
+
+// InitialAccessPolicy decides who can register new clients. Registration is rejected if not set
+func (f *RegistrationFeature) InitialAccessPolicy(policy InitialAccessPolicy) *RegistrationFeature {
+	f.policy = policy
+	return f
+}
+
+// ClientSecretEncoder should be same as the one used by client authentication
+func (f *RegistrationFeature) ClientSecretEncoder(encoder passwd.PasswordEncoder) *RegistrationFeature {
+	f.secretEncoder = encoder
+	return f
+}
+
+func (f *RegistrationFeature) AllowedGrantTypes(grantTypes ...string) *RegistrationFeature {
+	f.grantTypes = utils.NewStringSet(grantTypes...)
+	return f
+}
+
+func (f *RegistrationFeature) AllowedScopes(scopes ...string) *RegistrationFeature {
+	f.scopes = utils.NewStringSet(scopes...)
+	return f
+}
+
+// DefaultScopes is used when registration request doesn't specify "scope"
+func (f *RegistrationFeature) DefaultScopes(scopes ...string) *RegistrationFeature {
+	f.defaultScopes = utils.NewStringSet(scopes...)
+	return f
+}
+
+func (f *RegistrationFeature) ErrorHandler(errorHandler *auth.OAuth2ErrorHandler) *RegistrationFeature {
+	f.errorHandler = errorHandler
+	return f
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"strings"
+	"time"
+)
+
+/*****************************
+	Abstractions
+ *****************************/
+
+// ClientRegistry persists dynamically registered clients.
+// Implementations are also oauth2.OAuth2ClientStore, so registered clients are available to the authorization server.
+type ClientRegistry interface {
+	oauth2.OAuth2ClientStore
+	// LoadRegistration returns the registration of given client ID. oauth2.NewClientNotFoundError should be returned if not found
+	LoadRegistration(ctx context.Context, clientId string) (*Registration, error)
+	// SaveRegistration creates or updates the given registration
+	SaveRegistration(ctx context.Context, reg *Registration) error
+	// DeleteRegistration removes the registration of given client ID
+	DeleteRegistration(ctx context.Context, clientId string) error
+}
+
+/*****************************
+	Metadata
+ *****************************/
+
+// ClientMetadata is the client metadata used in registration requests and responses.
+// See https://datatracker.ietf.org/doc/html/rfc7591#section-2
+type ClientMetadata struct {
+	RedirectUris            []string `json:"redirect_uris,omitempty"`
+	TokenEndpointAuthMethod string   `json:"token_endpoint_auth_method,omitempty"`
+	GrantTypes              []string `json:"grant_types,omitempty"`
+	ResponseTypes           []string `json:"response_types,omitempty"`
+	ClientName              string   `json:"client_name,omitempty"`
+	ClientUri               string   `json:"client_uri,omitempty"`
+	Scope                   string   `json:"scope,omitempty"`
+	Contacts                []string `json:"contacts,omitempty"`
+	JwksUri                 string   `json:"jwks_uri,omitempty"`
+	Jwks                    *JwkSet  `json:"jwks,omitempty"`
+	// TLSClientAuthSubjectDN is used by "tls_client_auth". See https://datatracker.ietf.org/doc/html/rfc8705#section-2.1.2
+	TLSClientAuthSubjectDN string `json:"tls_client_auth_subject_dn,omitempty"`
+	// DPoPBoundAccessTokens see https://datatracker.ietf.org/doc/html/rfc9449#section-5.2
+	DPoPBoundAccessTokens bool `json:"dpop_bound_access_tokens,omitempty"`
+}
+
+// JwkSet is the JSON Web Key Set document registered by value
+type JwkSet struct {
+	Keys []json.RawMessage `json:"keys"`
+}
+
+// Registration is the persisted state of a dynamically registered client
+type Registration struct {
+	ClientId string
+	// ClientSecret is encoded with the authorization server's client secret encoder. Empty for non-secret clients
+	ClientSecret string
+	IssuedAt     time.Time
+	// RegistrationTokenHash is the hash of registration access token. See HashRegistrationToken
+	RegistrationTokenHash string
+	Metadata              ClientMetadata
+}
+
+// Client converts the registration to oauth2.OAuth2Client
+func (r *Registration) Client() (*auth.DefaultOAuth2Client, error) {
+	jwks, e := r.Metadata.ParseJwks()
+	if e != nil {
+		return nil, e
+	}
+	scopes := utils.NewStringSet(strings.Fields(r.Metadata.Scope)...)
+	return auth.NewClientWithDetails(auth.ClientDetails{
+		ClientId:                r.ClientId,
+		Secret:                  r.ClientSecret,
+		GrantTypes:              utils.NewStringSet(r.Metadata.GrantTypes...),
+		RedirectUris:            utils.NewStringSet(r.Metadata.RedirectUris...),
+		Scopes:                  scopes,
+		AutoApproveScopes:       utils.NewStringSet(),
+		AssignedTenantIds:       utils.NewStringSet(),
+		ResourceIds:             utils.NewStringSet(),
+		TokenEndpointAuthMethod: r.Metadata.TokenEndpointAuthMethod,
+		JwkSetUri:               r.Metadata.JwksUri,
+		JwkSet:                  jwks,
+		TLSClientAuthSubjectDN:  r.Metadata.TLSClientAuthSubjectDN,
+		DPoPBoundAccessTokens:   r.Metadata.DPoPBoundAccessTokens,
+	}), nil
+}
+
+// ParseJwks parses JWKs registered by value
+func (m *ClientMetadata) ParseJwks() ([]jwt.Jwk, error) {
+	if m.Jwks == nil {
+		return nil, nil
+	}
+	jwks := make([]jwt.Jwk, len(m.Jwks.Keys))
+	for i, raw := range m.Jwks.Keys {
+		jwk, e := jwt.ParseJwk(raw)
+		if e != nil {
+			return nil, fmt.Errorf("invalid JWK at index %d: %v", i, e)
+		}
+		jwks[i] = jwk
+	}
+	return jwks, nil
+}
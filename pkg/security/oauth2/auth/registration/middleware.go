@@ -0,0 +1,361 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registration
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/passwd"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+)
+
+const (
+	PathParamClientId = "client_id"
+)
+
+// JSON fields of client information response
+// See https://datatracker.ietf.org/doc/html/rfc7591#section-3.2.1 and https://datatracker.ietf.org/doc/html/rfc7592#section-3
+const (
+	JsonFieldClientId                = "client_id"
+	JsonFieldClientSecret            = "client_secret"
+	JsonFieldClientIdIssuedAt        = "client_id_issued_at"
+	JsonFieldClientSecretExpiresAt   = "client_secret_expires_at"
+	JsonFieldRegistrationAccessToken = "registration_access_token"
+	JsonFieldRegistrationClientUri   = "registration_client_uri"
+)
+
+// RegistrationMiddleware implements client registration endpoint and client configuration endpoint
+// See https://datatracker.ietf.org/doc/html/rfc7591#section-3 and https://datatracker.ietf.org/doc/html/rfc7592#section-2
+type RegistrationMiddleware struct {
+	path          string
+	issuer        security.Issuer
+	registry      ClientRegistry
+	policy        InitialAccessPolicy
+	secretEncoder passwd.PasswordEncoder
+	validator     *MetadataValidator
+}
+
+type RegistrationMWOptions func(*RegistrationMWOption)
+
+type RegistrationMWOption struct {
+	Path                string
+	Issuer              security.Issuer
+	ClientRegistry      ClientRegistry
+	InitialAccessPolicy InitialAccessPolicy
+	ClientSecretEncoder passwd.PasswordEncoder
+	Validator           *MetadataValidator
+}
+
+func NewRegistrationMiddleware(opts ...RegistrationMWOptions) *RegistrationMiddleware {
+	opt := RegistrationMWOption{
+		ClientSecretEncoder: passwd.NewNoopPasswordEncoder(),
+	}
+	for _, fn := range opts {
+		if fn != nil {
+			fn(&opt)
+		}
+	}
+	return &RegistrationMiddleware{
+		path:          opt.Path,
+		issuer:        opt.Issuer,
+		registry:      opt.ClientRegistry,
+		policy:        opt.InitialAccessPolicy,
+		secretEncoder: opt.ClientSecretEncoder,
+		validator:     opt.Validator,
+	}
+}
+
+// RegisterHandlerFunc handles client registration request.
+// See https://datatracker.ietf.org/doc/html/rfc7591#section-3.1
+func (mw *RegistrationMiddleware) RegisterHandlerFunc() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if mw.policy == nil {
+			mw.handleError(ctx, oauth2.NewInvalidAccessTokenError("client registration is not allowed"))
+			return
+		}
+		if e := mw.policy.Allow(ctx, extractBearerToken(ctx.Request)); e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+
+		var metadata ClientMetadata
+		if e := mw.parseMetadata(ctx, &metadata, &metadata); e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+
+		reg := Registration{
+			ClientId: uuid.New().String(),
+			IssuedAt: time.Now().UTC(),
+			Metadata: metadata,
+		}
+		secret, e := mw.assignSecret(&reg)
+		if e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+		token, e := randomString()
+		if e != nil {
+			mw.handleError(ctx, oauth2.NewInternalError("unable to generate registration access token", e))
+			return
+		}
+		reg.RegistrationTokenHash = HashRegistrationToken(token)
+
+		if e := mw.registry.SaveRegistration(ctx, &reg); e != nil {
+			mw.handleError(ctx, oauth2.NewInternalError("unable to save client registration", e))
+			return
+		}
+
+		resp, e := mw.clientInformation(ctx, &reg)
+		if e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+		if secret != "" {
+			resp[JsonFieldClientSecret] = secret
+		}
+		resp[JsonFieldRegistrationAccessToken] = token
+		logger.WithContext(ctx).Infof("client [%s] is registered", reg.ClientId)
+		mw.handleSuccess(ctx, http.StatusCreated, resp)
+	}
+}
+
+// ReadHandlerFunc handles client read request.
+// See https://datatracker.ietf.org/doc/html/rfc7592#section-2.1
+func (mw *RegistrationMiddleware) ReadHandlerFunc() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		reg, e := mw.loadAuthorizedRegistration(ctx)
+		if e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+		resp, e := mw.clientInformation(ctx, reg)
+		if e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+		mw.handleSuccess(ctx, http.StatusOK, resp)
+	}
+}
+
+// UpdateHandlerFunc handles client update request. The request replaces all metadata of the client.
+// See https://datatracker.ietf.org/doc/html/rfc7592#section-2.2
+func (mw *RegistrationMiddleware) UpdateHandlerFunc() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		reg, e := mw.loadAuthorizedRegistration(ctx)
+		if e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+
+		var req struct {
+			ClientMetadata
+			ClientId     string `json:"client_id"`
+			ClientSecret string `json:"client_secret,omitempty"`
+		}
+		if e := mw.parseMetadata(ctx, &req, &req.ClientMetadata); e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+		if req.ClientId != reg.ClientId {
+			mw.handleError(ctx, oauth2.NewInvalidClientMetadataError("client_id doesn't match"))
+			return
+		}
+		if req.ClientSecret != "" && !mw.secretEncoder.Matches(req.ClientSecret, reg.ClientSecret) {
+			mw.handleError(ctx, oauth2.NewInvalidClientMetadataError("client_secret doesn't match"))
+			return
+		}
+
+		reg.Metadata = req.ClientMetadata
+		secret, e := mw.assignSecret(reg)
+		if e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+		if e := mw.registry.SaveRegistration(ctx, reg); e != nil {
+			mw.handleError(ctx, oauth2.NewInternalError("unable to save client registration", e))
+			return
+		}
+
+		resp, e := mw.clientInformation(ctx, reg)
+		if e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+		if secret != "" {
+			resp[JsonFieldClientSecret] = secret
+		}
+		logger.WithContext(ctx).Infof("client [%s] is updated", reg.ClientId)
+		mw.handleSuccess(ctx, http.StatusOK, resp)
+	}
+}
+
+// DeleteHandlerFunc handles client delete request.
+// See https://datatracker.ietf.org/doc/html/rfc7592#section-2.3
+func (mw *RegistrationMiddleware) DeleteHandlerFunc() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		reg, e := mw.loadAuthorizedRegistration(ctx)
+		if e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+		if e := mw.registry.DeleteRegistration(ctx, reg.ClientId); e != nil {
+			mw.handleError(ctx, oauth2.NewInternalError("unable to delete client registration", e))
+			return
+		}
+		logger.WithContext(ctx).Infof("client [%s] is deleted", reg.ClientId)
+		ctx.Header("Cache-Control", "no-store")
+		ctx.Header("Pragma", "no-cache")
+		ctx.Status(http.StatusNoContent)
+		ctx.Writer.WriteHeaderNow()
+		ctx.Abort()
+	}
+}
+
+// HashRegistrationToken returns the hex encoded SHA-256 hash of given registration access token.
+// Only hashes are persisted
+func HashRegistrationToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+/***********************
+	Helpers
+ ***********************/
+
+// parseMetadata decodes request body into "v" and validates "m", which should be the metadata of "v"
+func (mw *RegistrationMiddleware) parseMetadata(ctx *gin.Context, v interface{}, m *ClientMetadata) error {
+	if e := json.NewDecoder(ctx.Request.Body).Decode(v); e != nil {
+		return oauth2.NewInvalidClientMetadataError("unable to parse client metadata", e)
+	}
+	return mw.validator.Validate(m)
+}
+
+// loadAuthorizedRegistration loads registration of path param "client_id" and verify the registration access token
+func (mw *RegistrationMiddleware) loadAuthorizedRegistration(ctx *gin.Context) (*Registration, error) {
+	token := extractBearerToken(ctx.Request)
+	if token == "" {
+		return nil, oauth2.NewInvalidAccessTokenError("registration access token is required")
+	}
+	reg, e := mw.registry.LoadRegistration(ctx, ctx.Param(PathParamClientId))
+	if e != nil {
+		// we don't disclose whether the client exists
+		return nil, oauth2.NewInvalidAccessTokenError("invalid registration access token")
+	}
+	hash := HashRegistrationToken(token)
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(reg.RegistrationTokenHash)) != 1 {
+		return nil, oauth2.NewInvalidAccessTokenError("invalid registration access token")
+	}
+	return reg, nil
+}
+
+// assignSecret generates a new secret if the client requires one and doesn't have one yet. Returns the plain secret if generated
+func (mw *RegistrationMiddleware) assignSecret(reg *Registration) (string, error) {
+	switch reg.Metadata.TokenEndpointAuthMethod {
+	case oauth2.ClientAuthMethodSecretBasic, oauth2.ClientAuthMethodSecretPost:
+	default:
+		reg.ClientSecret = ""
+		return "", nil
+	}
+	if reg.ClientSecret != "" {
+		return "", nil
+	}
+	secret, e := randomString()
+	if e != nil {
+		return "", oauth2.NewInternalError("unable to generate client secret", e)
+	}
+	reg.ClientSecret = mw.secretEncoder.Encode(secret)
+	return secret, nil
+}
+
+func (mw *RegistrationMiddleware) clientInformation(ctx *gin.Context, reg *Registration) (map[string]interface{}, error) {
+	data, e := json.Marshal(reg.Metadata)
+	if e != nil {
+		return nil, oauth2.NewInternalError("unable to serialize client metadata", e)
+	}
+	resp := map[string]interface{}{}
+	if e := json.Unmarshal(data, &resp); e != nil {
+		return nil, oauth2.NewInternalError("unable to serialize client metadata", e)
+	}
+
+	uri, e := mw.registrationClientUri(ctx, reg.ClientId)
+	if e != nil {
+		return nil, oauth2.NewInternalError("unable to resolve registration client URI", e)
+	}
+	resp[JsonFieldClientId] = reg.ClientId
+	resp[JsonFieldClientIdIssuedAt] = reg.IssuedAt.Unix()
+	if reg.ClientSecret != "" {
+		resp[JsonFieldClientSecretExpiresAt] = 0
+	}
+	resp[JsonFieldRegistrationClientUri] = uri.String()
+	return resp, nil
+}
+
+// registrationClientUri build absolute client configuration URI on the same domain as the request
+func (mw *RegistrationMiddleware) registrationClientUri(c *gin.Context, clientId string) (*url.URL, error) {
+	host := c.Request.Host
+	if h, _, e := net.SplitHostPort(host); e == nil {
+		host = h
+	}
+	p := path.Join(mw.path, url.PathEscape(clientId))
+	uri, e := mw.issuer.BuildUrl(func(opt *security.UrlBuilderOption) {
+		opt.FQDN = host
+		opt.Path = p
+	})
+	if e != nil {
+		return nil, fmt.Errorf("invalid registration path [%s]: %v", p, e)
+	}
+	return uri, nil
+}
+
+func (mw *RegistrationMiddleware) handleSuccess(c *gin.Context, status int, v interface{}) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.JSON(status, v)
+	c.Abort()
+}
+
+func (mw *RegistrationMiddleware) handleError(c *gin.Context, err error) {
+	if !errors.Is(err, oauth2.ErrorTypeOAuth2) {
+		err = oauth2.NewInvalidClientMetadataError(err)
+	}
+
+	_ = c.Error(err)
+	c.Abort()
+}
+
+func randomString() (string, error) {
+	buf := make([]byte, 32)
+	if _, e := rand.Read(buf); e != nil {
+		return "", e
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/gin-gonic/gin"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+/*************************
+	Setup
+ *************************/
+
+const (
+	TestPath         = "/v2/register"
+	TestInitialToken = "test-initial-access-token"
+)
+
+func newTestMiddleware(registry ClientRegistry) *RegistrationMiddleware {
+	return NewRegistrationMiddleware(func(opt *RegistrationMWOption) {
+		opt.Path = TestPath
+		opt.Issuer = security.NewIssuer(func(opt *security.DefaultIssuerDetails) {
+			opt.Protocol = "http"
+			opt.Domain = "localhost"
+			opt.Port = 8900
+			opt.ContextPath = "/auth"
+			opt.IncludePort = true
+		})
+		opt.ClientRegistry = registry
+		opt.InitialAccessPolicy = StaticInitialAccessTokens(TestInitialToken)
+		opt.Validator = &MetadataValidator{
+			AllowedGrantTypes: utils.NewStringSet(oauth2.GrantTypeAuthCode, oauth2.GrantTypeClientCredentials),
+			AllowedScopes:     utils.NewStringSet(oauth2.ScopeRead, oauth2.ScopeWrite),
+			DefaultScopes:     utils.NewStringSet(oauth2.ScopeRead),
+		}
+	})
+}
+
+func invoke(handler gin.HandlerFunc, method, clientId, token, body string) (*httptest.ResponseRecorder, *gin.Context) {
+	rw := httptest.NewRecorder()
+	gc, _ := gin.CreateTestContext(rw)
+	gc.Request = httptest.NewRequest(method, "http://localhost:8900/auth"+TestPath, strings.NewReader(body))
+	gc.Request.Host = "localhost:8900"
+	if token != "" {
+		gc.Request.Header.Set("Authorization", "Bearer "+token)
+	}
+	if clientId != "" {
+		gc.Params = gin.Params{{Key: PathParamClientId, Value: clientId}}
+	}
+	handler(gc)
+	return rw, gc
+}
+
+/*************************
+	Tests
+ *************************/
+
+func TestRegistrationEndpoints(t *testing.T) {
+	registry := NewInMemoryClientRegistry(nil)
+	mw := newTestMiddleware(registry)
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestRegistrationLifecycle(mw, registry), "RegistrationLifecycle"),
+		test.GomegaSubTest(SubTestRegistrationWithoutInitialToken(mw), "RegistrationWithoutInitialToken"),
+		test.GomegaSubTest(SubTestRegistrationWithInvalidMetadata(mw), "RegistrationWithInvalidMetadata"),
+		test.GomegaSubTest(SubTestRegistrationWithNativeRedirectUris(mw), "RegistrationWithNativeRedirectUris"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestRegistrationLifecycle(mw *RegistrationMiddleware, registry ClientRegistry) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		// register
+		body := `{"redirect_uris":["https://client.example.org/callback"],"client_name":"Test"}`
+		rw, gc := invoke(mw.RegisterHandlerFunc(), http.MethodPost, "", TestInitialToken, body)
+		g.Expect(gc.Errors).To(BeEmpty(), "registration should not fail")
+		g.Expect(rw.Code).To(Equal(http.StatusCreated), "registration should return 201")
+		var resp map[string]interface{}
+		g.Expect(json.Unmarshal(rw.Body.Bytes(), &resp)).To(Succeed())
+		g.Expect(resp).To(HaveKeyWithValue(JsonFieldClientSecretExpiresAt, BeEquivalentTo(0)))
+		g.Expect(resp).To(HaveKeyWithValue("grant_types", ConsistOf(oauth2.GrantTypeAuthCode)))
+		g.Expect(resp).To(HaveKeyWithValue("scope", oauth2.ScopeRead))
+		clientId := resp[JsonFieldClientId].(string)
+		token := resp[JsonFieldRegistrationAccessToken].(string)
+		g.Expect(resp).To(HaveKeyWithValue(JsonFieldRegistrationClientUri, "http://localhost:8900/auth/v2/register/"+clientId))
+		g.Expect(resp).To(HaveKeyWithValue(JsonFieldClientSecret, Not(BeEmpty())))
+
+		client, e := registry.LoadClientByClientId(ctx, clientId)
+		g.Expect(e).To(Succeed(), "registered client should be loadable")
+		g.Expect(client.Secret()).To(Equal(resp[JsonFieldClientSecret]))
+
+		// read
+		_, gc = invoke(mw.ReadHandlerFunc(), http.MethodGet, clientId, "wrong-token", "")
+		g.Expect(gc.Errors.Last()).ToNot(BeNil(), "read with wrong token should fail")
+		g.Expect(errors.Is(gc.Errors.Last().Err, oauth2.ErrorTypeOAuth2)).To(BeTrue())
+		rw, gc = invoke(mw.ReadHandlerFunc(), http.MethodGet, clientId, token, "")
+		g.Expect(gc.Errors).To(BeEmpty(), "read should not fail")
+		g.Expect(rw.Code).To(Equal(http.StatusOK))
+
+		// update
+		body = `{"client_id":"` + clientId + `","redirect_uris":["https://client.example.org/cb2"],"scope":"read write"}`
+		rw, gc = invoke(mw.UpdateHandlerFunc(), http.MethodPut, clientId, token, body)
+		g.Expect(gc.Errors).To(BeEmpty(), "update should not fail")
+		g.Expect(rw.Code).To(Equal(http.StatusOK))
+		client, e = registry.LoadClientByClientId(ctx, clientId)
+		g.Expect(e).To(Succeed())
+		g.Expect(client.Scopes().Values()).To(ConsistOf(oauth2.ScopeRead, oauth2.ScopeWrite))
+		g.Expect(client.RedirectUris().Values()).To(ConsistOf("https://client.example.org/cb2"))
+
+		// delete
+		rw, gc = invoke(mw.DeleteHandlerFunc(), http.MethodDelete, clientId, token, "")
+		g.Expect(gc.Errors).To(BeEmpty(), "delete should not fail")
+		g.Expect(rw.Code).To(Equal(http.StatusNoContent))
+		_, e = registry.LoadClientByClientId(ctx, clientId)
+		g.Expect(e).To(HaveOccurred(), "deleted client should not be loadable")
+	}
+}
+
+func SubTestRegistrationWithoutInitialToken(mw *RegistrationMiddleware) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		body := `{"redirect_uris":["https://client.example.org/callback"]}`
+		_, gc := invoke(mw.RegisterHandlerFunc(), http.MethodPost, "", "", body)
+		g.Expect(gc.Errors.Last()).ToNot(BeNil(), "registration without initial access token should fail")
+		g.Expect(errors.Is(gc.Errors.Last().Err, oauth2.ErrorSubTypeOAuth2Res)).To(BeTrue())
+	}
+}
+
+func SubTestRegistrationWithInvalidMetadata(mw *RegistrationMiddleware) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		bodies := []string{
+			`{"redirect_uris":["/relative/callback"]}`,
+			`{"redirect_uris":["https://client.example.org/callback#frag"]}`,
+			`{"redirect_uris":["javascript:alert(document.cookie)"]}`,
+			`{"redirect_uris":["data:text/html,<script>alert(1)</script>"]}`,
+			`{"redirect_uris":["http://client.example.org/callback"]}`,
+			`{"redirect_uris":["https:///callback"]}`,
+			`{"grant_types":["authorization_code"]}`,
+			`{"grant_types":["password"]}`,
+			`{"grant_types":["client_credentials"],"scope":"admin"}`,
+			`{"grant_types":["client_credentials"],"token_endpoint_auth_method":"private_key_jwt"}`,
+			`{"grant_types":["client_credentials"],"token_endpoint_auth_method":"private_key_jwt","jwks_uri":"https://client.example.org/jwks","jwks":{"keys":[{}]}}`,
+		}
+		for _, body := range bodies {
+			_, gc := invoke(mw.RegisterHandlerFunc(), http.MethodPost, "", TestInitialToken, body)
+			g.Expect(gc.Errors.Last()).ToNot(BeNil(), "registration with %s should fail", body)
+			g.Expect(errors.Is(gc.Errors.Last().Err, oauth2.ErrorSubTypeOAuth2ClientAuth)).To(BeTrue(), "error of %s should be client metadata error", body)
+		}
+	}
+}
+
+func SubTestRegistrationWithNativeRedirectUris(mw *RegistrationMiddleware) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		uris := []string{
+			"http://localhost:8080/callback",
+			"http://127.0.0.1:51004/callback",
+			"http://[::1]/callback",
+			"com.example.app:/oauth2redirect",
+		}
+		for _, uri := range uris {
+			body := `{"redirect_uris":["` + uri + `"]}`
+			rw, gc := invoke(mw.RegisterHandlerFunc(), http.MethodPost, "", TestInitialToken, body)
+			g.Expect(gc.Errors).To(BeEmpty(), "registration with redirect URI [%s] should not fail", uri)
+			g.Expect(rw.Code).To(Equal(http.StatusCreated), "registration with redirect URI [%s] should return 201", uri)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registration
+
+import (
+	"context"
+	"crypto/subtle"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"net/http"
+	"strings"
+)
+
+const bearerTokenPrefix = "Bearer "
+
+// InitialAccessPolicy decides whether a client registration request is allowed.
+// See https://datatracker.ietf.org/doc/html/rfc7591#section-3
+type InitialAccessPolicy interface {
+	// Allow returns error if the registration request is not allowed.
+	// "token" is the bearer token of the request, may be empty
+	Allow(ctx context.Context, token string) error
+}
+
+// InitialAccessPolicyFunc convert a function to InitialAccessPolicy
+type InitialAccessPolicyFunc func(ctx context.Context, token string) error
+
+func (fn InitialAccessPolicyFunc) Allow(ctx context.Context, token string) error {
+	return fn(ctx, token)
+}
+
+// OpenRegistration allows anyone to register clients
+func OpenRegistration() InitialAccessPolicy {
+	return InitialAccessPolicyFunc(func(_ context.Context, _ string) error {
+		return nil
+	})
+}
+
+// StaticInitialAccessTokens allows registration requests bearing one of given pre-shared tokens
+func StaticInitialAccessTokens(tokens ...string) InitialAccessPolicy {
+	return InitialAccessPolicyFunc(func(_ context.Context, token string) error {
+		if token == "" {
+			return oauth2.NewInvalidAccessTokenError("initial access token is required")
+		}
+		for _, t := range tokens {
+			if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+				return nil
+			}
+		}
+		return oauth2.NewInvalidAccessTokenError("invalid initial access token")
+	})
+}
+
+// ScopedInitialAccessToken allows registration requests bearing a valid access token issued by this authorization server,
+// with given scope
+func ScopedInitialAccessToken(reader oauth2.TokenStoreReader, scope string) InitialAccessPolicy {
+	return InitialAccessPolicyFunc(func(ctx context.Context, token string) error {
+		if token == "" {
+			return oauth2.NewInvalidAccessTokenError("initial access token is required")
+		}
+		oauth, e := reader.ReadAuthentication(ctx, token, oauth2.TokenHintAccessToken)
+		if e != nil {
+			return oauth2.NewInvalidAccessTokenError("invalid initial access token", e)
+		}
+		if oauth.OAuth2Request() == nil || !oauth.OAuth2Request().Scopes().Has(scope) {
+			return oauth2.NewInsufficientScopeError("initial access token doesn't have required scope")
+		}
+		return nil
+	})
+}
+
+// AnyInitialAccessPolicy allows registration requests allowed by any of given policies
+func AnyInitialAccessPolicy(policies ...InitialAccessPolicy) InitialAccessPolicy {
+	return InitialAccessPolicyFunc(func(ctx context.Context, token string) (err error) {
+		for _, p := range policies {
+			if err = p.Allow(ctx, token); err == nil {
+				return nil
+			}
+		}
+		if err == nil {
+			err = oauth2.NewInvalidAccessTokenError("client registration is not allowed")
+		}
+		return
+	})
+}
+
+func extractBearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) < len(bearerTokenPrefix) || !strings.EqualFold(header[:len(bearerTokenPrefix)], bearerTokenPrefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(bearerTokenPrefix):])
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registration
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"sync"
+)
+
+// InMemoryClientRegistry implements ClientRegistry. Registered clients are lost on restart.
+// It optionally delegates to another oauth2.OAuth2ClientStore for statically configured clients.
+// It's intended for tests only, and has to be set explicitly as ClientRegistry of authorization server
+type InMemoryClientRegistry struct {
+	mtx      sync.RWMutex
+	regs     map[string]Registration
+	delegate oauth2.OAuth2ClientStore
+}
+
+func NewInMemoryClientRegistry(delegate oauth2.OAuth2ClientStore) *InMemoryClientRegistry {
+	return &InMemoryClientRegistry{
+		regs:     map[string]Registration{},
+		delegate: delegate,
+	}
+}
+
+func (r *InMemoryClientRegistry) LoadClientByClientId(ctx context.Context, clientId string) (oauth2.OAuth2Client, error) {
+	reg, e := r.LoadRegistration(ctx, clientId)
+	switch {
+	case e == nil:
+		return clientOf(reg)
+	case r.delegate != nil:
+		return r.delegate.LoadClientByClientId(ctx, clientId)
+	default:
+		return nil, e
+	}
+}
+
+func (r *InMemoryClientRegistry) LoadRegistration(_ context.Context, clientId string) (*Registration, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	reg, ok := r.regs[clientId]
+	if !ok {
+		return nil, oauth2.NewClientNotFoundError("client not found")
+	}
+	return &reg, nil
+}
+
+func (r *InMemoryClientRegistry) SaveRegistration(_ context.Context, reg *Registration) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.regs[reg.ClientId] = *reg
+	return nil
+}
+
+func (r *InMemoryClientRegistry) DeleteRegistration(_ context.Context, clientId string) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	delete(r.regs, clientId)
+	return nil
+}
+
+func clientOf(reg *Registration) (oauth2.OAuth2Client, error) {
+	client, e := reg.Client()
+	if e != nil {
+		return nil, oauth2.NewInternalError("invalid client registration", e)
+	}
+	return client, nil
+}
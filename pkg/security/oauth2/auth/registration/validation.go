@@ -0,0 +1,175 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registration
+
+import (
+	"encoding/json"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"net"
+	"net/url"
+	"strings"
+)
+
+const (
+	ResponseTypeCode = "code"
+)
+
+// MetadataValidator validates and normalizes client metadata of registration and update requests
+type MetadataValidator struct {
+	AllowedGrantTypes utils.StringSet
+	AllowedScopes     utils.StringSet
+	DefaultScopes     utils.StringSet
+}
+
+// Validate checks given metadata and populates default values.
+// Error returned is either oauth2.NewInvalidClientMetadataError or oauth2.NewInvalidClientRedirectUriError
+func (v *MetadataValidator) Validate(m *ClientMetadata) error {
+	// grant types
+	if len(m.GrantTypes) == 0 {
+		m.GrantTypes = []string{oauth2.GrantTypeAuthCode}
+	}
+	for _, grant := range m.GrantTypes {
+		if !v.AllowedGrantTypes.Has(grant) {
+			return oauth2.NewInvalidClientMetadataError("grant type [" + grant + "] is not allowed")
+		}
+	}
+	grants := utils.NewStringSet(m.GrantTypes...)
+
+	// response types
+	if len(m.ResponseTypes) == 0 && grants.Has(oauth2.GrantTypeAuthCode) {
+		m.ResponseTypes = []string{ResponseTypeCode}
+	}
+	for _, rt := range m.ResponseTypes {
+		if rt != ResponseTypeCode || !grants.Has(oauth2.GrantTypeAuthCode) {
+			return oauth2.NewInvalidClientMetadataError("response type [" + rt + "] is not consistent with grant types")
+		}
+	}
+
+	// redirect URIs
+	if err := v.validateRedirectUris(m, grants); err != nil {
+		return err
+	}
+
+	// scopes
+	if len(strings.TrimSpace(m.Scope)) == 0 {
+		m.Scope = strings.Join(v.DefaultScopes.Values(), " ")
+	}
+	for _, scope := range strings.Fields(m.Scope) {
+		if !v.AllowedScopes.Has(scope) {
+			return oauth2.NewInvalidClientMetadataError("scope [" + scope + "] is not allowed")
+		}
+	}
+
+	// authentication method and keys
+	return v.validateAuthMethod(m)
+}
+
+func (v *MetadataValidator) validateRedirectUris(m *ClientMetadata, grants utils.StringSet) error {
+	if len(m.RedirectUris) == 0 && grants.Has(oauth2.GrantTypeAuthCode) {
+		return oauth2.NewInvalidClientRedirectUriError("redirect_uris is required for authorization_code grant")
+	}
+	for _, uri := range m.RedirectUris {
+		parsed, e := url.Parse(uri)
+		switch {
+		case e != nil:
+			return oauth2.NewInvalidClientRedirectUriError("invalid redirect URI ["+uri+"]", e)
+		case !parsed.IsAbs():
+			return oauth2.NewInvalidClientRedirectUriError("redirect URI [" + uri + "] is not absolute")
+		case parsed.Fragment != "" || strings.Contains(uri, "#"):
+			return oauth2.NewInvalidClientRedirectUriError("redirect URI [" + uri + "] should not contain fragment")
+		case !isAllowedRedirectUri(parsed):
+			return oauth2.NewInvalidClientRedirectUriError("redirect URI [" + uri + "] should use https, loopback http or private-use URI scheme")
+		}
+	}
+	return nil
+}
+
+// isAllowedRedirectUri only allows redirect URIs that cannot be abused to run scripts or leak authorization code:
+//   - "https" with a host
+//   - "http" with loopback host. See https://datatracker.ietf.org/doc/html/rfc8252#section-7.3
+//   - private-use URI scheme in reverse domain name notation. See https://datatracker.ietf.org/doc/html/rfc8252#section-7.1
+func isAllowedRedirectUri(uri *url.URL) bool {
+	switch scheme := strings.ToLower(uri.Scheme); scheme {
+	case "https":
+		return uri.Hostname() != ""
+	case "http":
+		return isLoopbackHost(uri.Hostname())
+	default:
+		return strings.Contains(scheme, ".")
+	}
+}
+
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (v *MetadataValidator) validateAuthMethod(m *ClientMetadata) error {
+	if m.TokenEndpointAuthMethod == "" {
+		m.TokenEndpointAuthMethod = oauth2.ClientAuthMethodSecretBasic
+	}
+	hasJwks := m.Jwks != nil && len(m.Jwks.Keys) != 0
+	if m.JwksUri != "" && hasJwks {
+		return oauth2.NewInvalidClientMetadataError("jwks_uri and jwks cannot be both present")
+	}
+	if m.JwksUri != "" {
+		if parsed, e := url.Parse(m.JwksUri); e != nil || parsed.Scheme != "https" {
+			return oauth2.NewInvalidClientMetadataError("jwks_uri should be a valid https URL")
+		}
+	}
+	if hasJwks {
+		if e := validateJwks(m.Jwks); e != nil {
+			return e
+		}
+	}
+
+	switch m.TokenEndpointAuthMethod {
+	case oauth2.ClientAuthMethodSecretBasic, oauth2.ClientAuthMethodSecretPost, oauth2.ClientAuthMethodNone:
+	case oauth2.ClientAuthMethodPrivateKeyJwt, oauth2.ClientAuthMethodSelfSignedTLS:
+		if m.JwksUri == "" && !hasJwks {
+			return oauth2.NewInvalidClientMetadataError(m.TokenEndpointAuthMethod + " requires jwks_uri or jwks")
+		}
+	case oauth2.ClientAuthMethodTLS:
+		if m.TLSClientAuthSubjectDN == "" {
+			return oauth2.NewInvalidClientMetadataError("tls_client_auth requires tls_client_auth_subject_dn")
+		}
+	default:
+		return oauth2.NewInvalidClientMetadataError("unsupported token_endpoint_auth_method [" + m.TokenEndpointAuthMethod + "]")
+	}
+	return nil
+}
+
+func validateJwks(jwks *JwkSet) error {
+	m := ClientMetadata{Jwks: jwks}
+	if _, e := m.ParseJwks(); e != nil {
+		return oauth2.NewInvalidClientMetadataError("invalid jwks", e)
+	}
+	for _, raw := range jwks.Keys {
+		var fields map[string]interface{}
+		if e := json.Unmarshal(raw, &fields); e != nil {
+			return oauth2.NewInvalidClientMetadataError("invalid jwks", e)
+		}
+		if _, ok := fields["d"]; ok {
+			return oauth2.NewInvalidClientMetadataError("jwks should only contain public keys")
+		}
+	}
+	return nil
+}
@@ -19,6 +19,8 @@ package revoke
 import (
     "context"
     "fmt"
+    "github.com/cisco-open/go-lanai/pkg/security"
+    "github.com/cisco-open/go-lanai/pkg/security/audit"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
     "github.com/cisco-open/go-lanai/pkg/security/session"
@@ -70,6 +72,9 @@ func (r DefaultAccessRevoker) RevokeWithSessionId(ctx context.Context, sessionId
 	if e := r.authRegistry.RevokeSessionAccess(ctx, sessionId, true); e != nil {
 		return e
 	}
+	auditRevocation(ctx, security.AuditPrincipal(security.Get(ctx)), map[string]interface{}{
+		audit.DataKeySessionId: sessionId,
+	})
 	return
 }
 
@@ -83,11 +88,18 @@ func (r DefaultAccessRevoker) RevokeWithUsername(ctx context.Context, username s
 	if e := r.authRegistry.RevokeUserAccess(ctx, username, revokeRefreshToken); e != nil {
 		return e
 	}
+	auditRevocation(ctx, username, nil)
 	return
 }
 
 func (r DefaultAccessRevoker) RevokeWithClientId(ctx context.Context, clientId string, revokeRefreshToken bool) error {
-	return r.authRegistry.RevokeClientAccess(ctx, clientId, true)
+	if e := r.authRegistry.RevokeClientAccess(ctx, clientId, true); e != nil {
+		return e
+	}
+	auditRevocation(ctx, security.AuditPrincipal(security.Get(ctx)), map[string]interface{}{
+		audit.DataKeyClientId: clientId,
+	})
+	return nil
 }
 
 func (r DefaultAccessRevoker) RevokeWithTokenValue(ctx context.Context, tokenValue string, hint auth.RevokerTokenHint) error {
@@ -97,14 +109,28 @@ func (r DefaultAccessRevoker) RevokeWithTokenValue(ctx context.Context, tokenVal
 		if e != nil {
 			return e
 		}
-		return r.authRegistry.RevokeAccessToken(ctx, token)
+		if e := r.authRegistry.RevokeAccessToken(ctx, token); e != nil {
+			return e
+		}
 	case auth.RevokerHintRefreshToken:
 		token, e := r.tokenStoreReader.ReadRefreshToken(ctx, tokenValue)
 		if e != nil {
 			return e
 		}
-		return r.authRegistry.RevokeRefreshToken(ctx, token)
+		if e := r.authRegistry.RevokeRefreshToken(ctx, token); e != nil {
+			return e
+		}
 	default:
 		return fmt.Errorf("unsupported revoker token hint")
 	}
+	auditRevocation(ctx, security.AuditPrincipal(security.Get(ctx)), map[string]interface{}{
+		audit.DataKeyTokenType: string(hint),
+	})
+	return nil
+}
+
+func auditRevocation(ctx context.Context, principal string, data map[string]interface{}) {
+	if audit.Enabled() {
+		audit.Publish(ctx, audit.NewAuditEvent(principal, audit.TypeTokenRevoked, data))
+	}
 }
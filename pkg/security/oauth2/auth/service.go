@@ -20,6 +20,7 @@ import (
     "context"
     "fmt"
     "github.com/cisco-open/go-lanai/pkg/security"
+    "github.com/cisco-open/go-lanai/pkg/security/audit"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2/common"
     "github.com/cisco-open/go-lanai/pkg/tenancy"
@@ -78,6 +79,8 @@ func NewDefaultAuthorizationService(opts ...DASOptions) *DefaultAuthorizationSer
 			&basicEnhancer,
 			&LegacyTokenEnhancer{},
 			&ResourceIdTokenEnhancer{},
+			&TokenExchangeTokenEnhancer{},
+			&ConfirmationTokenEnhancer{},
 			&DetailsTokenEnhancer{},
 			&refreshTokenEnhancer,
 		},
@@ -161,6 +164,7 @@ func (s *DefaultAuthorizationService) CreateAccessToken(c context.Context, oauth
 	if e != nil {
 		return nil, e
 	}
+	auditTokenIssued(c, oauth)
 	return s.postTokenEnhancer.Enhance(c, saved, oauth)
 }
 
@@ -183,10 +187,22 @@ func (s *DefaultAuthorizationService) RefreshAccessToken(c context.Context, oaut
 	if e != nil {
 		return nil, e
 	}
-
+	auditTokenIssued(c, oauth)
 	return s.postTokenEnhancer.Enhance(c, saved, oauth)
 }
 
+func auditTokenIssued(ctx context.Context, oauth oauth2.Authentication) {
+	if !audit.Enabled() {
+		return
+	}
+	data := map[string]interface{}{}
+	if req := oauth.OAuth2Request(); req != nil {
+		data[audit.DataKeyClientId] = req.ClientId()
+		data[audit.DataKeyGrantType] = req.GrantType()
+	}
+	audit.Publish(ctx, audit.NewAuditEvent(security.AuditPrincipal(oauth.UserAuthentication()), audit.TypeTokenIssued, data))
+}
+
 /*
 ***************************
 
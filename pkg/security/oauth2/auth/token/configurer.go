@@ -20,6 +20,7 @@ import (
     "fmt"
     "github.com/cisco-open/go-lanai/pkg/security"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+    "github.com/cisco-open/go-lanai/pkg/security/oauth2/dpop"
     "github.com/cisco-open/go-lanai/pkg/web/mapping"
     "github.com/cisco-open/go-lanai/pkg/web/matcher"
     "github.com/cisco-open/go-lanai/pkg/web/middleware"
@@ -49,6 +50,7 @@ func (c *TokenEndpointConfigurer) Apply(feature security.Feature, ws security.We
 	// prepare middlewares
 	tokenMw := NewTokenEndpointMiddleware(func(opts *TokenEndpointOptions) {
 		opts.Granter = auth.NewCompositeTokenGranter(f.granters...)
+		opts.DPoPVerifier = f.dpopVerifier
 	})
 
 	// install middlewares
@@ -73,6 +75,10 @@ func (c *TokenEndpointConfigurer) validate(f *TokenFeature, ws security.WebSecur
 	if f.granters == nil || len(f.granters) == 0 {
 		return fmt.Errorf("token granters is not set")
 	}
+
+	if f.dpopVerifier == nil {
+		f.dpopVerifier = dpop.NewVerifier()
+	}
 	return nil
 }
 
@@ -20,6 +20,7 @@ import (
     "fmt"
     "github.com/cisco-open/go-lanai/pkg/security"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+    "github.com/cisco-open/go-lanai/pkg/security/oauth2/dpop"
 )
 
 // We currently don't have any stuff to configure
@@ -27,6 +28,7 @@ import (
 type TokenFeature struct {
 	path string
 	granters []auth.TokenGranter
+	dpopVerifier *dpop.Verifier
 }
 
 // Standard security.Feature entrypoint
@@ -62,4 +64,10 @@ func (f *TokenFeature) AddGranter(granter auth.TokenGranter) *TokenFeature {
 	}
 
 	return f
-}
\ No newline at end of file
+}
+
+// DPoPVerifier set the verifier of DPoP proofs. See oauth2.DPoPClient
+func (f *TokenFeature) DPoPVerifier(verifier *dpop.Verifier) *TokenFeature {
+	f.dpopVerifier = verifier
+	return f
+}
@@ -20,6 +20,7 @@ import (
     "errors"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2/auth"
+    "github.com/cisco-open/go-lanai/pkg/security/oauth2/dpop"
     "github.com/gin-gonic/gin"
 )
 
@@ -29,7 +30,8 @@ import (
 
 //goland:noinspection GoNameStartsWithPackageName
 type TokenEndpointMiddleware struct {
-	granter     auth.TokenGranter
+	granter      auth.TokenGranter
+	dpopVerifier *dpop.Verifier
 }
 
 //goland:noinspection GoNameStartsWithPackageName
@@ -37,7 +39,8 @@ type TokenEndpointOptionsFunc func(*TokenEndpointOptions)
 
 //goland:noinspection GoNameStartsWithPackageName
 type TokenEndpointOptions struct {
-	Granter     *auth.CompositeTokenGranter
+	Granter      *auth.CompositeTokenGranter
+	DPoPVerifier *dpop.Verifier
 }
 
 func NewTokenEndpointMiddleware(optionFuncs...TokenEndpointOptionsFunc) *TokenEndpointMiddleware {
@@ -50,7 +53,8 @@ func NewTokenEndpointMiddleware(optionFuncs...TokenEndpointOptionsFunc) *TokenEn
 		}
 	}
 	return &TokenEndpointMiddleware{
-		granter: opts.Granter,
+		granter:      opts.Granter,
+		dpopVerifier: opts.DPoPVerifier,
 	}
 }
 
@@ -89,6 +93,12 @@ func (mw *TokenEndpointMiddleware) TokenHandlerFunc() gin.HandlerFunc {
 			return
 		}
 
+		// check DPoP proof
+		if e := mw.verifyDPoPProof(ctx, client, tokenRequest); e != nil {
+			mw.handleError(ctx, e)
+			return
+		}
+
 		token, e := mw.granter.Grant(ctx, tokenRequest)
 		if e != nil {
 			mw.handleError(ctx, e)
@@ -99,6 +109,26 @@ func (mw *TokenEndpointMiddleware) TokenHandlerFunc() gin.HandlerFunc {
 	}
 }
 
+// verifyDPoPProof requires and verifies DPoP proof if the client opted in DPoP-bound access tokens.
+// The JWK thumbprint of the proof is added to token request extensions, so the issued tokens can be bound to it.
+// See https://datatracker.ietf.org/doc/html/rfc9449#section-5
+func (mw *TokenEndpointMiddleware) verifyDPoPProof(ctx *gin.Context, client oauth2.OAuth2Client, request *auth.TokenRequest) error {
+	// never trust the value from request parameters
+	delete(request.Extensions, auth.ExtKeyDPoPJwkThumbprint)
+	if c, ok := client.(oauth2.DPoPClient); !ok || !c.DPoPBoundAccessTokens() {
+		return nil
+	}
+	if mw.dpopVerifier == nil {
+		return oauth2.NewInternalError("DPoP verifier is not configured")
+	}
+	proof, e := mw.dpopVerifier.Verify(ctx, ctx.Request, "")
+	if e != nil {
+		return oauth2.NewInvalidDPoPProofError(e.Error(), e)
+	}
+	request.Extensions[auth.ExtKeyDPoPJwkThumbprint] = proof.JwkThumbprint
+	return nil
+}
+
 func (mw *TokenEndpointMiddleware) handleSuccess(c *gin.Context, v interface{}) {
 	c.Header("Cache-Control", "no-store")
 	c.Header("Pragma", "no-cache")
@@ -107,10 +137,31 @@ func (mw *TokenEndpointMiddleware) handleSuccess(c *gin.Context, v interface{})
 }
 
 func (mw *TokenEndpointMiddleware) handleError(c *gin.Context, err error) {
-	if errors.Is(err, oauth2.ErrorTypeOAuth2) {
+	if errors.Is(err, oauth2.ErrorTypeOAuth2) && !isPassThroughError(err) {
 		err = oauth2.NewInvalidGrantError(err)
 	}
 
 	_ = c.Error(err)
 	c.Abort()
-}
\ No newline at end of file
+}
+
+// isPassThroughError returns true if given error is defined by grant specific specs and should be returned to clients as-is:
+// 	- polling responses of device authorization grant. See https://datatracker.ietf.org/doc/html/rfc8628#section-3.5
+// 	- "invalid_target" of token exchange grant. See https://datatracker.ietf.org/doc/html/rfc8693#section-2.2.2
+// 	- "invalid_dpop_proof". See https://datatracker.ietf.org/doc/html/rfc9449#section-5
+func isPassThroughError(err error) bool {
+	var oauthErr *oauth2.OAuth2Error
+	if !errors.As(err, &oauthErr) {
+		return false
+	}
+	switch oauthErr.Code() {
+	case oauth2.ErrorCodeAuthorizationPending, oauth2.ErrorCodeSlowDown, oauth2.ErrorCodeExpiredToken, oauth2.ErrorCodeAccessRejected:
+		return true
+	case oauth2.ErrorCodeInvalidTarget:
+		return true
+	case oauth2.ErrorCodeInvalidDPoPProof:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package token
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/dpop"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/sectest"
+	"github.com/gin-gonic/gin"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+/*************************
+	Tests
+ *************************/
+
+func TestTokenEndpointErrorTranslation(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestGrantErrorTranslation(), "GrantErrors"),
+		test.GomegaSubTest(SubTestNonOAuth2ErrorTranslation(), "NonOAuth2Errors"),
+		test.GomegaSubTest(SubTestDevicePollingErrorTranslation(), "DevicePollingErrors"),
+		test.GomegaSubTest(SubTestTokenExchangeErrorTranslation(), "TokenExchangeErrors"),
+		test.GomegaSubTest(SubTestTokenEndpointInvalidDPoPProof(), "TokenEndpointInvalidDPoPProof"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestGrantErrorTranslation() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		// OAuth2 errors returned by existing grants are translated into invalid_grant
+		errs := []error{
+			oauth2.NewInvalidScopeError("invalid scope"),
+			oauth2.NewInvalidTokenRequestError("invalid request"),
+			oauth2.NewUnauthorizedClientError("client is not allowed"),
+			oauth2.NewInvalidGrantError("invalid grant"),
+		}
+		for _, err := range errs {
+			translated := HandleError(err)
+			AssertOAuth2Error(g, translated, oauth2.ErrorTranslationInvalidGrant)
+			g.Expect(translated.Error()).To(Equal(err.Error()), "translated error should keep original description")
+		}
+	}
+}
+
+func SubTestNonOAuth2ErrorTranslation() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		// non-OAuth2 errors are left to the error handler
+		errs := []error{
+			security.NewBadCredentialsError("bad credentials"),
+			security.NewInternalError("internal"),
+			errors.New("generic"),
+		}
+		for _, err := range errs {
+			translated := HandleError(err)
+			g.Expect(translated).To(BeIdenticalTo(err), "non-OAuth2 error should not be translated")
+		}
+	}
+}
+
+func SubTestDevicePollingErrorTranslation() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		errs := map[string]error{
+			oauth2.ErrorTranslationAuthorizationPending: oauth2.NewAuthorizationPendingError("pending"),
+			oauth2.ErrorTranslationSlowDown:             oauth2.NewSlowDownError("slow down"),
+			oauth2.ErrorTranslationExpiredToken:         oauth2.NewExpiredTokenError("expired"),
+			oauth2.ErrorTranslationAccessDenied:         oauth2.NewAccessRejectedError("denied"),
+		}
+		for code, err := range errs {
+			translated := HandleError(err)
+			g.Expect(translated).To(BeIdenticalTo(err), "device polling error [%s] should not be translated", code)
+			AssertOAuth2Error(g, translated, code)
+		}
+	}
+}
+
+func SubTestTokenExchangeErrorTranslation() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		err := oauth2.NewInvalidTargetError("invalid audience")
+		translated := HandleError(err)
+		g.Expect(translated).To(BeIdenticalTo(err), "token exchange error should not be translated")
+		AssertOAuth2Error(g, translated, oauth2.ErrorTranslationInvalidTarget)
+	}
+}
+
+func SubTestTokenEndpointInvalidDPoPProof() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		mw := NewTokenEndpointMiddleware(func(opt *TokenEndpointOptions) {
+			opt.DPoPVerifier = dpop.NewVerifier()
+		})
+		client := sectest.MockedClient{MockedClientProperties: sectest.MockedClientProperties{
+			ClientID:   "test-client",
+			GrantTypes: utils.CommaSeparatedSlice{oauth2.GrantTypeClientCredentials},
+			DPoPBound:  true,
+		}}
+		form := url.Values{}
+		form.Set(oauth2.ParameterGrantType, oauth2.GrantTypeClientCredentials)
+		req := httptest.NewRequest(http.MethodPost, "/v2/token", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("DPoP", "not-a-valid-proof")
+
+		gc, _ := gin.CreateTestContext(httptest.NewRecorder())
+		gc.Request = req
+		gc.Set(oauth2.CtxKeyAuthenticatedClient, client)
+		mw.TokenHandlerFunc()(gc)
+
+		g.Expect(gc.IsAborted()).To(BeTrue(), "token endpoint should abort with invalid DPoP proof")
+		g.Expect(gc.Errors.Last()).To(Not(BeNil()), "token endpoint should report error")
+		AssertOAuth2Error(g, gc.Errors.Last().Err, oauth2.ErrorTranslationInvalidDPoPProof)
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func HandleError(err error) error {
+	gc, _ := gin.CreateTestContext(httptest.NewRecorder())
+	mw := NewTokenEndpointMiddleware()
+	mw.handleError(gc, err)
+	if gc.Errors.Last() == nil {
+		return nil
+	}
+	return gc.Errors.Last().Err
+}
+
+func AssertOAuth2Error(g *gomega.WithT, err error, expectedCode string) {
+	var oauthErr *oauth2.OAuth2Error
+	g.Expect(errors.As(err, &oauthErr)).To(BeTrue(), "error should be OAuth2 error")
+	g.Expect(oauthErr.TranslateErrorCode()).To(Equal(expectedCode), "error should have correct error code")
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+)
+
+const (
+	// ExtKeyDPoPJwkThumbprint is the request extension holding the JWK thumbprint of verified DPoP proof
+	ExtKeyDPoPJwkThumbprint = "dpop_jkt"
+)
+
+/*****************************
+	Confirmation Enhancer
+ *****************************/
+
+// confirmationClaims implements Claims and wraps any existing claims with "cnf" claim
+type confirmationClaims struct {
+	oauth2.FieldClaimsMapper
+	oauth2.Claims
+	Confirmation map[string]interface{} `claim:"cnf"`
+}
+
+func (c *confirmationClaims) MarshalJSON() ([]byte, error) {
+	return c.FieldClaimsMapper.DoMarshalJSON(c)
+}
+
+func (c *confirmationClaims) UnmarshalJSON(bytes []byte) error {
+	return c.FieldClaimsMapper.DoUnmarshalJSON(c, bytes)
+}
+
+func (c *confirmationClaims) Get(claim string) interface{} {
+	return c.FieldClaimsMapper.Get(c, claim)
+}
+
+func (c *confirmationClaims) Has(claim string) bool {
+	return c.FieldClaimsMapper.Has(c, claim)
+}
+
+func (c *confirmationClaims) Set(claim string, value interface{}) {
+	c.FieldClaimsMapper.Set(c, claim, value)
+}
+
+func (c *confirmationClaims) Values() map[string]interface{} {
+	return c.FieldClaimsMapper.Values(c)
+}
+
+// ConfirmationTokenEnhancer implements order.Ordered and TokenEnhancer
+// ConfirmationTokenEnhancer binds access token by adding "cnf" claim:
+//   - "x5t#S256" member, when the client is authenticated with mutual TLS at token endpoint.
+//     See https://datatracker.ietf.org/doc/html/rfc8705#section-3
+//   - "jkt" member, when the token request is made with DPoP proof. Such token's type is oauth2.TokenTypeDPoP.
+//     See https://datatracker.ietf.org/doc/html/rfc9449#section-6
+type ConfirmationTokenEnhancer struct{}
+
+func (te *ConfirmationTokenEnhancer) Order() int {
+	return TokenEnhancerOrderConfirmationClaims
+}
+
+func (te *ConfirmationTokenEnhancer) Enhance(ctx context.Context, token oauth2.AccessToken, oauth oauth2.Authentication) (oauth2.AccessToken, error) {
+	cnf := map[string]interface{}{}
+	if details, ok := security.Get(ctx).Details().(map[string]interface{}); ok {
+		if thumbprint, ok := details[oauth2.DetailsKeyCertThumbprint].(string); ok && thumbprint != "" {
+			cnf[oauth2.ClaimCertThumbprint] = thumbprint
+		}
+	}
+	jkt, _ := oauth.OAuth2Request().Extensions()[ExtKeyDPoPJwkThumbprint].(string)
+	if jkt != "" {
+		cnf[oauth2.ClaimJwkThumbprint] = jkt
+	}
+	if len(cnf) == 0 {
+		return token, nil
+	}
+
+	t, ok := token.(*oauth2.DefaultAccessToken)
+	if !ok {
+		return nil, oauth2.NewInternalError(errTmplUnsupportedToken, t)
+	}
+
+	if t.Claims() == nil {
+		return nil, oauth2.NewInternalError("ConfirmationTokenEnhancer need to be placed after BasicClaimsEnhancer")
+	}
+
+	if jkt != "" {
+		t.SetType(oauth2.TokenTypeDPoP)
+	}
+	t.SetClaims(&confirmationClaims{
+		Claims:       t.Claims(),
+		Confirmation: cnf,
+	})
+	return t, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+)
+
+const (
+	ExtKeyTokenExchangeActor    = oauth2.ClaimActor
+	ExtKeyTokenExchangeAudience = oauth2.ParameterAudience
+)
+
+/*****************************
+	Token Exchange Enhancer
+ *****************************/
+
+// tokenExchangeClaims implements Claims and wraps any existing claims with "act" claim
+type tokenExchangeClaims struct {
+	oauth2.FieldClaimsMapper
+	oauth2.Claims
+	Actor map[string]interface{} `claim:"act"`
+}
+
+func (c *tokenExchangeClaims) MarshalJSON() ([]byte, error) {
+	return c.FieldClaimsMapper.DoMarshalJSON(c)
+}
+
+func (c *tokenExchangeClaims) UnmarshalJSON(bytes []byte) error {
+	return c.FieldClaimsMapper.DoUnmarshalJSON(c, bytes)
+}
+
+func (c *tokenExchangeClaims) Get(claim string) interface{} {
+	return c.FieldClaimsMapper.Get(c, claim)
+}
+
+func (c *tokenExchangeClaims) Has(claim string) bool {
+	return c.FieldClaimsMapper.Has(c, claim)
+}
+
+func (c *tokenExchangeClaims) Set(claim string, value interface{}) {
+	c.FieldClaimsMapper.Set(c, claim, value)
+}
+
+func (c *tokenExchangeClaims) Values() map[string]interface{} {
+	return c.FieldClaimsMapper.Values(c)
+}
+
+// TokenExchangeTokenEnhancer implements order.Ordered and TokenEnhancer
+// TokenExchangeTokenEnhancer adds "act" claim, narrows down "aud" claim and "issued_token_type" response field
+// for tokens issued by token exchange grant.
+// See https://datatracker.ietf.org/doc/html/rfc8693
+type TokenExchangeTokenEnhancer struct{}
+
+func (te *TokenExchangeTokenEnhancer) Order() int {
+	return TokenEnhancerOrderTokenExchangeClaims
+}
+
+func (te *TokenExchangeTokenEnhancer) Enhance(_ context.Context, token oauth2.AccessToken, oauth oauth2.Authentication) (oauth2.AccessToken, error) {
+	request := oauth.OAuth2Request()
+	if request == nil || request.GrantType() != oauth2.GrantTypeTokenExchange {
+		return token, nil
+	}
+
+	t, ok := token.(*oauth2.DefaultAccessToken)
+	if !ok {
+		return nil, oauth2.NewInternalError(errTmplUnsupportedToken, t)
+	}
+
+	if t.Claims() == nil {
+		return nil, oauth2.NewInternalError("TokenExchangeTokenEnhancer need to be placed after BasicClaimsEnhancer")
+	}
+
+	if aud, ok := request.Extensions()[ExtKeyTokenExchangeAudience].([]string); ok && len(aud) != 0 {
+		t.Claims().Set(oauth2.ClaimAudience, utils.NewStringSet(aud...))
+	}
+
+	if act, ok := request.Extensions()[ExtKeyTokenExchangeActor].(map[string]interface{}); ok && len(act) != 0 {
+		t.SetClaims(&tokenExchangeClaims{
+			Claims: t.Claims(),
+			Actor:  act,
+		})
+	}
+
+	t.PutDetails(oauth2.JsonFieldIssuedTokenType, oauth2.TokenTypeIdAccessToken)
+	return t, nil
+}
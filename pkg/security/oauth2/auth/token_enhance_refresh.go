@@ -30,6 +30,7 @@ var (
 		oauth2.GrantTypeImplicit,
 		oauth2.GrantTypeRefresh,
 		oauth2.GrantTypeSwitchTenant, // Need this to create a new refresh token when switching tenants
+		oauth2.GrantTypeDeviceCode,
 		//oauth2.GrantTypePassword, // this is for dev purpose, shouldn't be allowed
 	)
 )
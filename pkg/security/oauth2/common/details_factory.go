@@ -303,6 +303,10 @@ func (f *ContextDetailsFactory) createKVDetails(_ context.Context, facts *facts)
 	}
 	if srcKV, ok := facts.source.Details().(security.KeyValueDetails); ok {
 		for k, v := range srcKV.Values() {
+			// request extensions of the current request take precedence
+			if _, exists := ret[k]; exists && k == oauth2.DetailsKeyRequestExt {
+				continue
+			}
 			ret[k] = v
 		}
 	}
@@ -23,6 +23,8 @@ type ExtendedClaims struct {
 	oauth2.FieldClaimsMapper
 	oauth2.BasicClaims
 	oauth2.Claims
+	Confirmation map[string]interface{} `claim:"cnf"`
+	Actor        map[string]interface{} `claim:"act"`
 }
 
 func NewExtendedClaims(claims ...oauth2.Claims) *ExtendedClaims {
@@ -27,6 +27,28 @@ const (
 	JsonFieldIDTokenValue      = "id_token"
 )
 
+// JSON fields of Device Authorization Response
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.2
+const (
+	JsonFieldDeviceCode              = "device_code"
+	JsonFieldUserCode                = "user_code"
+	JsonFieldVerificationUri         = "verification_uri"
+	JsonFieldVerificationUriComplete = "verification_uri_complete"
+	JsonFieldInterval                = "interval"
+)
+
+// JSON fields of Pushed Authorization Response
+// https://datatracker.ietf.org/doc/html/rfc9126#section-2.2
+const (
+	JsonFieldRequestUri = "request_uri"
+)
+
+// JSON fields of Token Exchange Response
+// https://datatracker.ietf.org/doc/html/rfc8693#section-2.2.1
+const (
+	JsonFieldIssuedTokenType = "issued_token_type"
+)
+
 const (
 	ParameterClientId            = "client_id"
 	ParameterClientSecret        = "client_secret"
@@ -58,6 +80,17 @@ const (
 	ParameterACR                 = "acr_values"
 	ParameterPrompt              = "prompt"
 	ParameterClaims              = "claims"
+	ParameterDeviceCode          = "device_code"
+	ParameterUserCode            = "user_code"
+	ParameterSubjectToken        = "subject_token"
+	ParameterSubjectTokenType    = "subject_token_type"
+	ParameterActorToken          = "actor_token"
+	ParameterActorTokenType      = "actor_token_type"
+	ParameterRequestedTokenType  = "requested_token_type"
+	ParameterAudience            = "audience"
+	ParameterResource            = "resource"
+	ParameterClientAssertion     = "client_assertion"
+	ParameterClientAssertionType = "client_assertion_type"
 	//Parameter = ""
 )
 
@@ -75,6 +108,34 @@ const (
 	GrantTypeSwitchUser        = "urn:cisco:nfv:oauth:grant-type:switch-user"
 	GrantTypeSwitchTenant      = "urn:cisco:nfv:oauth:grant-type:switch-tenant"
 	GrantTypeSamlSSO           = "urn:ietf:params:oauth:grant-type:saml2-bearer"
+	GrantTypeDeviceCode        = "urn:ietf:params:oauth:grant-type:device_code"
+	GrantTypeTokenExchange     = "urn:ietf:params:oauth:grant-type:token-exchange"
+)
+
+// Token Type Identifiers used by Token Exchange
+// https://datatracker.ietf.org/doc/html/rfc8693#section-3
+const (
+	TokenTypeIdAccessToken  = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeIdRefreshToken = "urn:ietf:params:oauth:token-type:refresh_token"
+	TokenTypeIdIdToken      = "urn:ietf:params:oauth:token-type:id_token"
+	TokenTypeIdJwt          = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// Client Assertion Types
+// https://datatracker.ietf.org/doc/html/rfc7523#section-2.2
+const (
+	ClientAssertionTypeJwtBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+// Client Authentication Methods used at token endpoint
+// https://www.iana.org/assignments/oauth-parameters/oauth-parameters.xhtml#token-endpoint-auth-method
+const (
+	ClientAuthMethodSecretBasic   = "client_secret_basic"
+	ClientAuthMethodSecretPost    = "client_secret_post"
+	ClientAuthMethodPrivateKeyJwt = "private_key_jwt"
+	ClientAuthMethodTLS           = "tls_client_auth"
+	ClientAuthMethodSelfSignedTLS = "self_signed_tls_client_auth"
+	ClientAuthMethodNone          = "none"
 )
 
 const (
@@ -107,8 +168,9 @@ const (
 )
 
 const (
-	DetailsKeyRequestExt    = "kOAuth2Ext"
-	DetailsKeyRequestParams = "kOAuth2Params"
+	DetailsKeyRequestExt     = "kOAuth2Ext"
+	DetailsKeyRequestParams  = "kOAuth2Params"
+	DetailsKeyCertThumbprint = "kCertThumbprint"
 )
 
 const (
@@ -125,6 +187,28 @@ const (
 	ClaimJwtId     = "jti"
 	//Claim = ""
 
+	/**
+	 * Token Exchange
+	 * https://datatracker.ietf.org/doc/html/rfc8693#section-4
+	 */
+	ClaimActor = "act"
+	//Claim = ""
+
+	/**
+	 * Certificate-Bound Access Tokens
+	 * https://datatracker.ietf.org/doc/html/rfc8705#section-3.1
+	 */
+	ClaimConfirmation   = "cnf"
+	ClaimCertThumbprint = "x5t#S256"
+	//Claim = ""
+
+	/**
+	 * DPoP-Bound Access Tokens
+	 * https://datatracker.ietf.org/doc/html/rfc9449#section-6.1
+	 */
+	ClaimJwkThumbprint = "jkt"
+	//Claim = ""
+
 	/**
 	 * ID TOKEN
 	 * https://openid.net/specs/openid-connect-core-1_0.html#IDToken
@@ -42,6 +42,36 @@ type OAuth2Client interface {
 	ResourceIDs() utils.StringSet
 }
 
+// TokenExchangeClient is an optional interface of OAuth2Client.
+// It declares which subject token types the client is allowed to exchange using Token Exchange grant.
+// Clients that don't implement this interface are not allowed to exchange any tokens.
+// See https://datatracker.ietf.org/doc/html/rfc8693
+type TokenExchangeClient interface {
+	TokenExchangeSubjectTypes() utils.StringSet
+}
+
+// ClientAuthMethodClient is an optional interface of OAuth2Client.
+// It declares how the client authenticates itself at token endpoint.
+// Clients that don't implement this interface, or returns empty TokenEndpointAuthMethod, authenticate with client secret.
+// See https://datatracker.ietf.org/doc/html/rfc7591#section-2
+type ClientAuthMethodClient interface {
+	// TokenEndpointAuthMethod is one of ClientAuthMethodSecretBasic, ClientAuthMethodPrivateKeyJwt, ClientAuthMethodTLS, etc.
+	TokenEndpointAuthMethod() string
+	// JwkSetUri is the URL of client's JWK Set. It's used to verify client assertion for "private_key_jwt"
+	// and to verify certificate for "self_signed_tls_client_auth"
+	JwkSetUri() string
+	// TLSClientAuthSubjectDN is the expected subject distinguished name of client's certificate for "tls_client_auth"
+	TLSClientAuthSubjectDN() string
+}
+
+// DPoPClient is an optional interface of OAuth2Client.
+// Clients returning true from DPoPBoundAccessTokens are required to present DPoP proof at token endpoint,
+// and their access tokens are bound to the proof key.
+// See https://datatracker.ietf.org/doc/html/rfc9449#section-5.2
+type DPoPClient interface {
+	DPoPBoundAccessTokens() bool
+}
+
 /***********************************
 	Store
  ***********************************/
@@ -0,0 +1,59 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package oauth2
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+)
+
+// CertificateThumbprint returns base64url-encoded SHA-256 thumbprint of the DER encoding of given X.509 certificate.
+// The value is used as "x5t#S256" confirmation method of certificate-bound access tokens.
+// See https://datatracker.ietf.org/doc/html/rfc8705#section-3.1
+func CertificateThumbprint(cert *x509.Certificate) string {
+	if cert == nil {
+		return ""
+	}
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// ConfirmationThumbprint extracts "x5t#S256" confirmation method from given claims. Returns empty string if not found.
+func ConfirmationThumbprint(claims Claims) string {
+	return confirmationMember(claims, ClaimCertThumbprint)
+}
+
+// ConfirmationJwkThumbprint extracts "jkt" confirmation method from given claims. Returns empty string if not found.
+// See https://datatracker.ietf.org/doc/html/rfc9449#section-6.1
+func ConfirmationJwkThumbprint(claims Claims) string {
+	return confirmationMember(claims, ClaimJwkThumbprint)
+}
+
+func confirmationMember(claims Claims, member string) string {
+	if claims == nil || !claims.Has(ClaimConfirmation) {
+		return ""
+	}
+	switch cnf := claims.Get(ClaimConfirmation).(type) {
+	case map[string]interface{}:
+		v, _ := cnf[member].(string)
+		return v
+	case map[string]string:
+		return cnf[member]
+	}
+	return ""
+}
@@ -31,6 +31,7 @@ const(
 	TokenTypeBearer = "bearer"
 	TokenTypeMac    = "mac"
 	TokenTypeBasic  = "basic"
+	TokenTypeDPoP   = "DPoP"
 )
 
 func (t TokenType) HttpHeader() string {
@@ -39,6 +40,8 @@ func (t TokenType) HttpHeader() string {
 		return "MAC"
 	case TokenTypeBasic:
 		return "Basic"
+	case strings.ToLower(TokenTypeDPoP):
+		return TokenTypeDPoP
 	default:
 		return "Bearer"
 	}
@@ -203,6 +206,11 @@ func (t *DefaultAccessToken) SetRefreshToken(v RefreshToken) *DefaultAccessToken
 	return t
 }
 
+func (t *DefaultAccessToken) SetType(v TokenType) *DefaultAccessToken {
+	t.tokenType = v
+	return t
+}
+
 func (t *DefaultAccessToken) SetScopes(scopes utils.StringSet) *DefaultAccessToken {
 	t.scopes = scopes.Copy()
 	return t
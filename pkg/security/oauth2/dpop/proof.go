@@ -0,0 +1,308 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dpop
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"github.com/cisco-open/go-lanai/pkg/security/replay"
+	netutil "github.com/cisco-open/go-lanai/pkg/utils/net"
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderDPoP           = "DPoP"
+	JwtTypeDPoP          = "dpop+jwt"
+	JwtHeaderJwk         = "jwk"
+	ClaimHttpMethod      = "htm"
+	ClaimHttpUri         = "htu"
+	ClaimAccessTokenHash = "ath"
+	// ReplayCachePrefix is the key prefix of used proofs when replay.RedisCache is used
+	ReplayCachePrefix = "DPOP"
+)
+
+const (
+	defaultMaxAge = 5 * time.Minute
+	defaultLeeway = 30 * time.Second
+)
+
+var (
+	ErrProofMissing  = errors.New("DPoP proof is missing")
+	ErrProofReplayed = errors.New("DPoP proof is replayed")
+)
+
+// Proof is a verified DPoP proof JWT.
+// See https://datatracker.ietf.org/doc/html/rfc9449#section-4.2
+type Proof struct {
+	// JwkThumbprint is the RFC 7638 thumbprint of the public key in proof's "jwk" header, used as "cnf.jkt"
+	JwkThumbprint string
+	Jwk           jwt.Jwk
+	Id            string
+	IssuedAt      time.Time
+}
+
+/*********************
+	Verifier
+ *********************/
+
+type VerifierOptions func(opt *VerifierOption)
+type VerifierOption struct {
+	// ReplayCache is used to reject proofs with used "jti". Default to replay.InMemoryCache
+	ReplayCache replay.Cache
+	// MaxAge is the maximum accepted age of proof, based on "iat"
+	MaxAge time.Duration
+	// Leeway is the tolerated clock skew
+	Leeway  time.Duration
+	Methods []gojwt.SigningMethod
+	// BaseUrl is the externally visible scheme and host (e.g. "https://api.example.com"), used to verify "htu".
+	// When set, request's Host and forwarding headers are ignored
+	BaseUrl string
+	// TrustedProxies are IPs or CIDRs of reverse proxies, typically web.ServerProperties.TrustedProxies.
+	// "X-Forwarded-Proto" and "X-Forwarded-Host" are only honored when the request comes from one of them
+	TrustedProxies []string
+}
+
+// Verifier verifies DPoP proof of an HTTP request.
+// See https://datatracker.ietf.org/doc/html/rfc9449#section-4.3
+type Verifier struct {
+	replayCache    replay.Cache
+	maxAge         time.Duration
+	leeway         time.Duration
+	parser         *gojwt.Parser
+	baseUrl        *url.URL
+	trustedProxies []*net.IPNet
+}
+
+func NewVerifier(opts ...VerifierOptions) *Verifier {
+	opt := VerifierOption{
+		MaxAge:  defaultMaxAge,
+		Leeway:  defaultLeeway,
+		Methods: jwt.AsymmetricSigningMethods,
+	}
+	for _, fn := range opts {
+		if fn != nil {
+			fn(&opt)
+		}
+	}
+	if opt.ReplayCache == nil {
+		opt.ReplayCache = replay.NewInMemoryCache()
+	}
+	trustedProxies, e := netutil.ParseIPNets(opt.TrustedProxies)
+	if e != nil {
+		panic(fmt.Errorf("invalid DPoP trusted proxies: %v", e))
+	}
+	methods := make([]string, len(opt.Methods))
+	for i := range opt.Methods {
+		methods[i] = opt.Methods[i].Alg()
+	}
+	v := &Verifier{
+		replayCache:    opt.ReplayCache,
+		maxAge:         opt.MaxAge,
+		leeway:         opt.Leeway,
+		parser:         gojwt.NewParser(gojwt.WithoutClaimsValidation(), gojwt.WithValidMethods(methods)),
+		trustedProxies: trustedProxies,
+	}
+	if opt.BaseUrl != "" {
+		baseUrl, e := url.Parse(opt.BaseUrl)
+		if e != nil || baseUrl.Scheme == "" || baseUrl.Host == "" {
+			panic(fmt.Errorf("invalid DPoP base URL [%s]", opt.BaseUrl))
+		}
+		v.baseUrl = baseUrl
+	}
+	return v
+}
+
+// Verify verifies the DPoP proof in given request's "DPoP" header.
+// When accessToken is not empty, the proof's "ath" claim is also verified.
+// ErrProofMissing is returned if the request doesn't have DPoP header
+func (v *Verifier) Verify(ctx context.Context, r *http.Request, accessToken string) (*Proof, error) {
+	values := r.Header.Values(HeaderDPoP)
+	switch {
+	case len(values) == 0:
+		return nil, ErrProofMissing
+	case len(values) > 1:
+		return nil, errors.New("multiple DPoP proofs are not allowed")
+	}
+
+	proof := Proof{}
+	claims := gojwt.MapClaims{}
+	if _, e := v.parser.ParseWithClaims(values[0], &claims, v.keyFunc(&proof)); e != nil {
+		return nil, fmt.Errorf("invalid DPoP proof: %v", e)
+	}
+
+	jti, _ := claims["jti"].(string)
+	htm, _ := claims[ClaimHttpMethod].(string)
+	htu, _ := claims[ClaimHttpUri].(string)
+	iat, ok := claims["iat"].(float64)
+	switch {
+	case jti == "" || htm == "" || htu == "" || !ok:
+		return nil, errors.New(`DPoP proof is missing required claims`)
+	case htm != r.Method:
+		return nil, errors.New(`DPoP proof's "htm" doesn't match request method`)
+	case !matchUri(htu, v.RequestURL(r)):
+		return nil, errors.New(`DPoP proof's "htu" doesn't match request URL`)
+	}
+
+	proof.Id = jti
+	proof.IssuedAt = time.Unix(int64(iat), 0)
+	now := time.Now()
+	if proof.IssuedAt.After(now.Add(v.leeway)) || proof.IssuedAt.Before(now.Add(-v.maxAge-v.leeway)) {
+		return nil, errors.New(`DPoP proof's "iat" is not within acceptable window`)
+	}
+
+	if accessToken != "" {
+		ath, _ := claims[ClaimAccessTokenHash].(string)
+		if ath != AccessTokenHash(accessToken) {
+			return nil, errors.New(`DPoP proof's "ath" doesn't match access token`)
+		}
+	}
+
+	key := proof.JwkThumbprint + ":" + proof.Id
+	if fresh, e := v.replayCache.Add(ctx, key, v.maxAge+2*v.leeway); e != nil {
+		return nil, e
+	} else if !fresh {
+		return nil, ErrProofReplayed
+	}
+	return &proof, nil
+}
+
+// RequestURL reconstructs the externally visible request URL without query and fragment.
+// Forwarding headers are only honored if the request is from a trusted proxy
+func (v *Verifier) RequestURL(r *http.Request) string {
+	if v.baseUrl != nil {
+		return v.baseUrl.Scheme + "://" + v.baseUrl.Host + r.URL.Path
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	host := r.Host
+	if !v.fromTrustedProxy(r) {
+		return scheme + "://" + host + r.URL.Path
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+	}
+	if fwd := r.Header.Get("X-Forwarded-Host"); fwd != "" {
+		host = strings.TrimSpace(strings.Split(fwd, ",")[0])
+	}
+	return scheme + "://" + host + r.URL.Path
+}
+
+func (v *Verifier) fromTrustedProxy(r *http.Request) bool {
+	if len(v.trustedProxies) == 0 {
+		return false
+	}
+	host, _, e := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+	if e != nil {
+		host = strings.TrimSpace(r.RemoteAddr)
+	}
+	return netutil.IPNetsContain(v.trustedProxies, net.ParseIP(host))
+}
+
+func (v *Verifier) keyFunc(proof *Proof) gojwt.Keyfunc {
+	return func(unverified *gojwt.Token) (interface{}, error) {
+		if typ, _ := unverified.Header[jwt.JwtHeaderType].(string); typ != JwtTypeDPoP {
+			return nil, fmt.Errorf(`"typ" should be "%s"`, JwtTypeDPoP)
+		}
+		header, ok := unverified.Header[JwtHeaderJwk].(map[string]interface{})
+		if !ok {
+			return nil, errors.New(`"jwk" header is missing`)
+		}
+		if _, ok := header["d"]; ok {
+			return nil, errors.New(`"jwk" header should not contain private key`)
+		}
+		thumbprint, e := JwkThumbprint(header)
+		if e != nil {
+			return nil, e
+		}
+		data, e := json.Marshal(header)
+		if e != nil {
+			return nil, e
+		}
+		jwk, e := jwt.ParseJwk(data)
+		if e != nil {
+			return nil, e
+		}
+		proof.Jwk = jwk
+		proof.JwkThumbprint = thumbprint
+		return jwk.Public(), nil
+	}
+}
+
+/*********************
+	Helpers
+ *********************/
+
+// JwkThumbprint computes RFC 7638 JWK thumbprint using SHA-256 of given JWK in JSON object form.
+// See https://datatracker.ietf.org/doc/html/rfc7638#section-3
+func JwkThumbprint(jwk map[string]interface{}) (string, error) {
+	var members []string
+	switch kty, _ := jwk["kty"].(string); kty {
+	case "RSA":
+		members = []string{"e", "kty", "n"}
+	case "EC":
+		members = []string{"crv", "kty", "x", "y"}
+	case "OKP":
+		members = []string{"crv", "kty", "x"}
+	default:
+		return "", fmt.Errorf(`unsupported JWK "kty" [%s]`, kty)
+	}
+	required := make(map[string]interface{}, len(members))
+	for _, m := range members {
+		v, ok := jwk[m].(string)
+		if !ok || v == "" {
+			return "", fmt.Errorf(`JWK is missing "%s"`, m)
+		}
+		required[m] = v
+	}
+	// json.Marshal sorts map keys lexicographically and doesn't add whitespaces
+	data, e := json.Marshal(required)
+	if e != nil {
+		return "", e
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// AccessTokenHash computes the "ath" value of given access token
+func AccessTokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// matchUri compares "htu" with request URL, ignoring query, fragment and case of scheme and host
+func matchUri(htu string, reqUrl string) bool {
+	expected, e1 := url.Parse(htu)
+	actual, e2 := url.Parse(reqUrl)
+	if e1 != nil || e2 != nil {
+		return false
+	}
+	return strings.EqualFold(expected.Scheme, actual.Scheme) &&
+		strings.EqualFold(expected.Host, actual.Host) &&
+		strings.TrimSuffix(expected.Path, "/") == strings.TrimSuffix(actual.Path, "/")
+}
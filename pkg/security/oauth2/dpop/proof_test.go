@@ -0,0 +1,254 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dpop
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/cisco-open/go-lanai/test"
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+/*************************
+	Setup
+ *************************/
+
+const (
+	TestUrl         = "https://api.example.com/resource"
+	TestAccessToken = "test-access-token"
+)
+
+type testReplayCache map[string]struct{}
+
+func (c testReplayCache) Add(_ context.Context, key string, _ time.Duration) (bool, error) {
+	if _, ok := c[key]; ok {
+		return false, nil
+	}
+	c[key] = struct{}{}
+	return true, nil
+}
+
+type proofClaims struct {
+	Method string
+	Url    string
+	Issued time.Time
+	ATHash string
+}
+
+/*************************
+	Test
+ *************************/
+
+func TestJwkThumbprint(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestRFC7638Thumbprint(), "RFC7638Example"),
+	)
+}
+
+func TestVerifier(t *testing.T) {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if e != nil {
+		t.Fatalf("unable to generate key: %v", e)
+	}
+	verifier := NewVerifier(func(opt *VerifierOption) {
+		opt.ReplayCache = testReplayCache{}
+	})
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestValidProof(verifier, key), "ValidProof"),
+		test.GomegaSubTest(SubTestReplayedProof(verifier, key), "ReplayedProof"),
+		test.GomegaSubTest(SubTestMismatchedProof(verifier, key), "MismatchedProof"),
+		test.GomegaSubTest(SubTestMissingProof(verifier), "MissingProof"),
+		test.GomegaSubTest(SubTestForwardedRequest(key), "ForwardedRequest"),
+		test.GomegaSubTest(SubTestDefaultReplayCache(key), "DefaultReplayCache"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestRFC7638Thumbprint() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		// https://datatracker.ietf.org/doc/html/rfc7638#section-3.1
+		jwk := map[string]interface{}{
+			"kty": "RSA",
+			"n": "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMs" +
+				"tn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajr" +
+				"n1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+			"e":   "AQAB",
+			"alg": "RS256",
+			"kid": "2011-04-29",
+		}
+		thumbprint, e := JwkThumbprint(jwk)
+		g.Expect(e).To(Succeed(), "thumbprint should not fail")
+		g.Expect(thumbprint).To(Equal("NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"), "thumbprint should be correct")
+	}
+}
+
+func SubTestValidProof(verifier *Verifier, key *ecdsa.PrivateKey) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		// without access token
+		req := newTestRequest(g, key, http.MethodPost, proofClaims{Method: http.MethodPost, Url: TestUrl})
+		proof, e := verifier.Verify(ctx, req, "")
+		g.Expect(e).To(Succeed(), "proof should be valid")
+		g.Expect(proof.JwkThumbprint).To(Equal(testThumbprint(g, key)), "thumbprint should be correct")
+
+		// with access token
+		req = newTestRequest(g, key, http.MethodGet, proofClaims{
+			Method: http.MethodGet, Url: TestUrl + "?query=ignored", ATHash: AccessTokenHash(TestAccessToken),
+		})
+		_, e = verifier.Verify(ctx, req, TestAccessToken)
+		g.Expect(e).To(Succeed(), "proof with access token hash should be valid")
+	}
+}
+
+func SubTestReplayedProof(verifier *Verifier, key *ecdsa.PrivateKey) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		req := newTestRequest(g, key, http.MethodPost, proofClaims{Method: http.MethodPost, Url: TestUrl})
+		_, e := verifier.Verify(ctx, req, "")
+		g.Expect(e).To(Succeed(), "first use should be valid")
+		_, e = verifier.Verify(ctx, req, "")
+		g.Expect(e).To(MatchError(ErrProofReplayed), "second use should fail")
+	}
+}
+
+func SubTestMismatchedProof(verifier *Verifier, key *ecdsa.PrivateKey) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		req := newTestRequest(g, key, http.MethodPost, proofClaims{Method: http.MethodGet, Url: TestUrl})
+		_, e := verifier.Verify(ctx, req, "")
+		g.Expect(e).To(HaveOccurred(), "mismatched htm should fail")
+
+		req = newTestRequest(g, key, http.MethodPost, proofClaims{Method: http.MethodPost, Url: "https://other.example.com/resource"})
+		_, e = verifier.Verify(ctx, req, "")
+		g.Expect(e).To(HaveOccurred(), "mismatched htu should fail")
+
+		req = newTestRequest(g, key, http.MethodPost, proofClaims{Method: http.MethodPost, Url: TestUrl, Issued: time.Now().Add(-time.Hour)})
+		_, e = verifier.Verify(ctx, req, "")
+		g.Expect(e).To(HaveOccurred(), "stale iat should fail")
+
+		req = newTestRequest(g, key, http.MethodGet, proofClaims{Method: http.MethodGet, Url: TestUrl, ATHash: AccessTokenHash("other")})
+		_, e = verifier.Verify(ctx, req, TestAccessToken)
+		g.Expect(e).To(HaveOccurred(), "mismatched ath should fail")
+	}
+}
+
+func SubTestMissingProof(verifier *Verifier) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		req := httptest.NewRequest(http.MethodPost, TestUrl, nil)
+		_, e := verifier.Verify(ctx, req, "")
+		g.Expect(e).To(MatchError(ErrProofMissing), "missing proof should fail")
+	}
+}
+
+func SubTestForwardedRequest(key *ecdsa.PrivateKey) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		const internalUrl = "http://internal:8080/resource"
+		newForwardedRequest := func() *http.Request {
+			req := newTestRequest(g, key, http.MethodPost, proofClaims{Method: http.MethodPost, Url: TestUrl})
+			req.Host = "internal:8080"
+			req.URL, _ = url.Parse(internalUrl)
+			req.Header.Set("X-Forwarded-Proto", "https")
+			req.Header.Set("X-Forwarded-Host", "api.example.com")
+			return req
+		}
+
+		// forwarding headers from untrusted source are ignored
+		_, e := NewVerifier().Verify(ctx, newForwardedRequest(), "")
+		g.Expect(e).To(HaveOccurred(), "forwarding headers from untrusted source should be ignored")
+
+		// trusted proxy. httptest requests are from 192.0.2.1
+		verifier := NewVerifier(func(opt *VerifierOption) {
+			opt.TrustedProxies = []string{"192.0.2.0/24"}
+		})
+		_, e = verifier.Verify(ctx, newForwardedRequest(), "")
+		g.Expect(e).To(Succeed(), "forwarding headers from trusted proxy should be honored")
+
+		// configured base URL
+		verifier = NewVerifier(func(opt *VerifierOption) {
+			opt.BaseUrl = "https://api.example.com"
+		})
+		req := newForwardedRequest()
+		req.Header.Del("X-Forwarded-Host")
+		_, e = verifier.Verify(ctx, req, "")
+		g.Expect(e).To(Succeed(), "configured base URL should be used")
+	}
+}
+
+func SubTestDefaultReplayCache(key *ecdsa.PrivateKey) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		verifier := NewVerifier()
+		req := newTestRequest(g, key, http.MethodPost, proofClaims{Method: http.MethodPost, Url: TestUrl})
+		_, e := verifier.Verify(ctx, req, "")
+		g.Expect(e).To(Succeed(), "first use should be valid")
+		_, e = verifier.Verify(ctx, req, "")
+		g.Expect(e).To(MatchError(ErrProofReplayed), "replay should be rejected by default")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func testJwkHeader(g *gomega.WithT, key *ecdsa.PrivateKey) map[string]interface{} {
+	data, e := json.Marshal(jwt.NewJwk("", "", key.Public()))
+	g.Expect(e).To(Succeed(), "marshalling JWK should not fail")
+	var header map[string]interface{}
+	g.Expect(json.Unmarshal(data, &header)).To(Succeed(), "unmarshalling JWK should not fail")
+	return header
+}
+
+func testThumbprint(g *gomega.WithT, key *ecdsa.PrivateKey) string {
+	thumbprint, e := JwkThumbprint(testJwkHeader(g, key))
+	g.Expect(e).To(Succeed(), "thumbprint should not fail")
+	return thumbprint
+}
+
+func newTestRequest(g *gomega.WithT, key *ecdsa.PrivateKey, method string, pc proofClaims) *http.Request {
+	if pc.Issued.IsZero() {
+		pc.Issued = time.Now()
+	}
+	claims := gojwt.MapClaims{
+		"jti":           utils.RandomString(16),
+		ClaimHttpMethod: pc.Method,
+		ClaimHttpUri:    pc.Url,
+		"iat":           pc.Issued.Unix(),
+	}
+	if pc.ATHash != "" {
+		claims[ClaimAccessTokenHash] = pc.ATHash
+	}
+	token := gojwt.NewWithClaims(gojwt.SigningMethodES256, claims)
+	token.Header[jwt.JwtHeaderType] = JwtTypeDPoP
+	token.Header[JwtHeaderJwk] = testJwkHeader(g, key)
+	proof, e := token.SignedString(key)
+	g.Expect(e).To(Succeed(), "signing proof should not fail")
+
+	req := httptest.NewRequest(method, TestUrl, nil)
+	req.Header.Set(HeaderDPoP, proof)
+	return req
+}
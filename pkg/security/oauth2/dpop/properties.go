@@ -0,0 +1,49 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dpop
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/pkg/errors"
+)
+
+const PropertiesPrefix = "security.dpop"
+
+type DPoPProperties struct {
+	// ReplayCacheDbIndex is the Redis DB index used to keep used proofs
+	ReplayCacheDbIndex int `json:"replay-cache-db-index"`
+	// BaseUrl is the externally visible scheme and host of this service, used to verify proof's "htu".
+	// When set, request's Host and forwarding headers are ignored.
+	// Otherwise, forwarding headers are only honored from "server.trusted-proxies"
+	BaseUrl string `json:"base-url"`
+}
+
+// NewDPoPProperties create a DPoPProperties with default values
+func NewDPoPProperties() *DPoPProperties {
+	return &DPoPProperties{
+		ReplayCacheDbIndex: 13,
+	}
+}
+
+// BindDPoPProperties create and bind DPoPProperties
+func BindDPoPProperties(ctx *bootstrap.ApplicationContext) DPoPProperties {
+	props := NewDPoPProperties()
+	if err := ctx.Config().Bind(props, PropertiesPrefix); err != nil {
+		panic(errors.Wrap(err, "failed to bind DPoPProperties"))
+	}
+	return *props
+}
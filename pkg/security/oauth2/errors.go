@@ -48,6 +48,8 @@ const (
 	_ = ErrorSubTypeCodeOAuth2ClientAuth + iota
 	ErrorCodeClientNotFound
 	ErrorCodeInvalidClient
+	ErrorCodeInvalidClientMetadata
+	ErrorCodeInvalidClientRedirectUri
 )
 
 // ErrorSubTypeCodeOAuth2Authorize
@@ -58,6 +60,8 @@ const (
 	ErrorCodeInvalidRedirectUri
 	ErrorCodeAccessRejected
 	ErrorCodeOpenIDExt
+	ErrorCodeInvalidRequestUri
+	ErrorCodeInvalidRequestObject
 )
 
 // ErrorSubTypeCodeOAuth2Grant
@@ -70,6 +74,11 @@ const (
 	ErrorCodeInvalidScope
 	ErrorCodeUnsupportedTokenType
 	ErrorCodeGeneric
+	ErrorCodeAuthorizationPending
+	ErrorCodeSlowDown
+	ErrorCodeExpiredToken
+	ErrorCodeInvalidTarget
+	ErrorCodeInvalidDPoPProof
 )
 
 // ErrorSubTypeCodeOAuth2Res
@@ -78,6 +87,7 @@ const (
 	ErrorCodeInvalidAccessToken
 	ErrorCodeInsufficientScope
 	ErrorCodeResourceServerGeneral // this should only be used for error deserialization
+	ErrorCodeInvalidResourceDPoPProof
 )
 
 // ErrorTypes, can be used in errors.Is
@@ -130,6 +140,21 @@ const (
 	ErrorTranslationRequestUnsupported      = "request_not_supported"
 	ErrorTranslationRequestURIUnsupported   = "request_uri_not_supported"
 	ErrorTranslationRegistrationUnsupported = "registration_not_supported"
+
+	// https://datatracker.ietf.org/doc/html/rfc8628#section-3.5
+	ErrorTranslationAuthorizationPending = "authorization_pending"
+	ErrorTranslationSlowDown             = "slow_down"
+	ErrorTranslationExpiredToken         = "expired_token"
+
+	// https://datatracker.ietf.org/doc/html/rfc8693#section-2.2.2
+	ErrorTranslationInvalidTarget = "invalid_target"
+
+	// https://datatracker.ietf.org/doc/html/rfc7591#section-3.2.2
+	ErrorTranslationInvalidClientMetadata = "invalid_client_metadata"
+	ErrorTranslationInvalidClientRedirect = "invalid_redirect_uri"
+
+	// https://datatracker.ietf.org/doc/html/rfc9449#section-12.2
+	ErrorTranslationInvalidDPoPProof = "invalid_dpop_proof"
 	//ErrorTranslation = ""
 )
 
@@ -293,6 +318,18 @@ func NewClientNotFoundError(value interface{}, causes ...interface{}) error {
 		causes...)
 }
 
+func NewInvalidClientMetadataError(value interface{}, causes ...interface{}) error {
+	return NewOAuth2Error(ErrorCodeInvalidClientMetadata, value,
+		ErrorTranslationInvalidClientMetadata, http.StatusBadRequest,
+		causes...)
+}
+
+func NewInvalidClientRedirectUriError(value interface{}, causes ...interface{}) error {
+	return NewOAuth2Error(ErrorCodeInvalidClientRedirectUri, value,
+		ErrorTranslationInvalidClientRedirect, http.StatusBadRequest,
+		causes...)
+}
+
 func NewUnauthorizedClientError(value interface{}, causes ...interface{}) error {
 	return NewOAuth2Error(ErrorCodeUnauthorizedClient, value,
 		ErrorTranslationUnauthorizedClient, http.StatusBadRequest,
@@ -323,6 +360,36 @@ func NewGenericError(value interface{}, causes ...interface{}) error {
 		causes...)
 }
 
+func NewAuthorizationPendingError(value interface{}, causes ...interface{}) error {
+	return NewOAuth2Error(ErrorCodeAuthorizationPending, value,
+		ErrorTranslationAuthorizationPending, http.StatusBadRequest,
+		causes...)
+}
+
+func NewSlowDownError(value interface{}, causes ...interface{}) error {
+	return NewOAuth2Error(ErrorCodeSlowDown, value,
+		ErrorTranslationSlowDown, http.StatusBadRequest,
+		causes...)
+}
+
+func NewExpiredTokenError(value interface{}, causes ...interface{}) error {
+	return NewOAuth2Error(ErrorCodeExpiredToken, value,
+		ErrorTranslationExpiredToken, http.StatusBadRequest,
+		causes...)
+}
+
+func NewInvalidTargetError(value interface{}, causes ...interface{}) error {
+	return NewOAuth2Error(ErrorCodeInvalidTarget, value,
+		ErrorTranslationInvalidTarget, http.StatusBadRequest,
+		causes...)
+}
+
+func NewInvalidDPoPProofError(value interface{}, causes ...interface{}) error {
+	return NewOAuth2Error(ErrorCodeInvalidDPoPProof, value,
+		ErrorTranslationInvalidDPoPProof, http.StatusBadRequest,
+		causes...)
+}
+
 func NewInvalidAuthorizeRequestError(value interface{}, causes ...interface{}) error {
 	return NewOAuth2Error(ErrorCodeInvalidAuthorizeRequest, value,
 		ErrorTranslationInvalidRequest, http.StatusBadRequest,
@@ -347,6 +414,18 @@ func NewAccessRejectedError(value interface{}, causes ...interface{}) error {
 		causes...)
 }
 
+func NewInvalidRequestUriError(value interface{}, causes ...interface{}) error {
+	return NewOAuth2Error(ErrorCodeInvalidRequestUri, value,
+		ErrorTranslationInvalidRequestURI, http.StatusBadRequest,
+		causes...)
+}
+
+func NewInvalidRequestObjectError(value interface{}, causes ...interface{}) error {
+	return NewOAuth2Error(ErrorCodeInvalidRequestObject, value,
+		ErrorTranslationInvalidRequestObj, http.StatusBadRequest,
+		causes...)
+}
+
 /* OAuth2Res family */
 
 func NewInvalidAccessTokenError(value interface{}, causes ...interface{}) error {
@@ -360,3 +439,9 @@ func NewInsufficientScopeError(value interface{}, causes ...interface{}) error {
 		ErrorTranslationInsufficientScope, http.StatusForbidden,
 		causes...)
 }
+
+func NewInvalidResourceDPoPProofError(value interface{}, causes ...interface{}) error {
+	return NewOAuth2Error(ErrorCodeInvalidResourceDPoPProof, value,
+		ErrorTranslationInvalidDPoPProof, http.StatusUnauthorized,
+		causes...)
+}
@@ -0,0 +1,509 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package jwt
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+	"sort"
+	"sync"
+	"time"
+)
+
+const maxUnknownKids = 1024
+
+var (
+	// ErrJwkRecordExists is returned by RotatingJwkStorage.CreateRecord when a record of same name and generation exists
+	ErrJwkRecordExists = errors.New("JWK record already exists")
+)
+
+// JwkRecord is the persisted form of a key managed by RotatingJwkStore
+type JwkRecord struct {
+	Name string `json:"name"`
+	// Generation increases by one on each rotation. Name and Generation uniquely identify a record
+	Generation int    `json:"generation"`
+	Kid        string `json:"kid"`
+	// PrivateKey is PKCS #8, ASN.1 DER encoded private key
+	PrivateKey []byte    `json:"private_key"`
+	CreatedAt  time.Time `json:"created_at"`
+	// ActivateAt is when the key starts to be used for signing. Before that, the key is only published for verification
+	ActivateAt time.Time `json:"activate_at"`
+}
+
+// RotatingJwkStorage persists JwkRecord, so all auth server replicas share same keys.
+type RotatingJwkStorage interface {
+	// LoadRecords returns all records of given name
+	LoadRecords(ctx context.Context, name string) ([]*JwkRecord, error)
+	// CreateRecord saves a new record. ErrJwkRecordExists should be returned if same name and generation already exists
+	CreateRecord(ctx context.Context, record *JwkRecord) error
+	// DeleteRecord removes the record
+	DeleteRecord(ctx context.Context, record *JwkRecord) error
+}
+
+type RotatingJwkStoreOptions func(opt *RotatingJwkStoreOption)
+
+type RotatingJwkStoreOption struct {
+	Storage RotatingJwkStorage
+	// Names of keys that are rotated in background. Other names are rotated once they are requested.
+	Names []string
+	// SigningMethod of generated keys. Only asymmetric methods are supported
+	SigningMethod jwt.SigningMethod
+	// Interval is how long each key is used for signing
+	Interval time.Duration
+	// PublishAhead is how long a new key is published in JWKS before it's used for signing
+	PublishAhead time.Duration
+	// MaxTokenValidity is the longest lifetime of tokens signed by the keys, including refresh tokens.
+	// Tokens signed by a retired key cannot be verified once the key is deleted, so Retention cannot be shorter.
+	MaxTokenValidity time.Duration
+	// Retention is how long a retired key is kept for verification. Default to MaxTokenValidity
+	Retention time.Duration
+	// RefreshInterval is how often keys are reloaded from storage and rotated if necessary
+	RefreshInterval time.Duration
+	// MinRefreshInterval limits how often keys are reloaded on-demand when an unknown "kid" is requested
+	MinRefreshInterval time.Duration
+	// ReadOnly store never generates nor deletes keys. Useful for resource servers sharing same storage
+	ReadOnly bool
+}
+
+// RotatingJwkStore implements JwkStore and JwkRotator.
+// Keys are generated on a schedule and persisted in RotatingJwkStorage. Each key goes through following phases:
+//   - published: included in JWKS, but not used for signing until "ActivateAt"
+//   - active: the newest activated key, used for signing
+//   - retired: superseded by a newer active key, kept for verification until "Retention" has passed
+type RotatingJwkStore struct {
+	RotatingJwkStoreOption
+	mtx      sync.RWMutex
+	names    map[string]struct{}
+	cache    map[string][]*rotatingJwk
+	loadedAt time.Time
+	// unknownKids is the negative cache of requested kids that are not found after refresh
+	unknownKids map[string]time.Time
+	onDemandAt  time.Time
+	refreshing  singleflight.Group
+}
+
+type rotatingJwk struct {
+	record *JwkRecord
+	jwk    PrivateJwk
+}
+
+func NewRotatingJwkStore(opts ...RotatingJwkStoreOptions) (*RotatingJwkStore, error) {
+	store := RotatingJwkStore{
+		RotatingJwkStoreOption: RotatingJwkStoreOption{
+			SigningMethod:      jwt.SigningMethodRS256,
+			Interval:           24 * time.Hour,
+			PublishAhead:       time.Hour,
+			MaxTokenValidity:   48 * time.Hour,
+			RefreshInterval:    time.Minute,
+			MinRefreshInterval: 5 * time.Second,
+		},
+		names:       map[string]struct{}{},
+		cache:       map[string][]*rotatingJwk{},
+		unknownKids: map[string]time.Time{},
+	}
+	for _, fn := range opts {
+		fn(&store.RotatingJwkStoreOption)
+	}
+	if store.Retention <= 0 {
+		store.Retention = store.MaxTokenValidity
+	}
+	switch {
+	case store.Storage == nil:
+		return nil, fmt.Errorf("JWK storage is required")
+	case store.Interval <= store.PublishAhead:
+		return nil, fmt.Errorf("JWK rotation interval [%v] should be longer than publish-ahead [%v]", store.Interval, store.PublishAhead)
+	case store.RefreshInterval <= 0:
+		return nil, fmt.Errorf("invalid JWK refresh interval [%v]", store.RefreshInterval)
+	case store.Retention < store.MaxTokenValidity:
+		return nil, fmt.Errorf("JWK retention [%v] should not be shorter than max token validity [%v]", store.Retention, store.MaxTokenValidity)
+	}
+	switch store.SigningMethod.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA, *jwt.SigningMethodEd25519:
+	default:
+		return nil, fmt.Errorf("unsupported signing method [%s] for JWK rotation", store.SigningMethod.Alg())
+	}
+	for _, name := range store.Names {
+		store.names[name] = struct{}{}
+	}
+	return &store, nil
+}
+
+func (s *RotatingJwkStore) LoadByKid(ctx context.Context, kid string) (Jwk, error) {
+	if jwk := s.findByKid(kid); jwk != nil {
+		return jwk, nil
+	}
+	if s.isKnownUnknown(kid) {
+		return nil, fmt.Errorf("cannot find JWK with kid [%s]", kid)
+	}
+	// the key might be just published by another replica.
+	// on-demand refreshes are rate limited and de-duplicated, so tokens with random "kid" cannot flood the storage
+	if e := s.refreshOnDemand(ctx); e != nil {
+		return nil, e
+	}
+	if jwk := s.findByKid(kid); jwk != nil {
+		return jwk, nil
+	}
+	s.markUnknown(kid)
+	return nil, fmt.Errorf("cannot find JWK with kid [%s]", kid)
+}
+
+func (s *RotatingJwkStore) LoadByName(ctx context.Context, name string) (Jwk, error) {
+	if jwk := s.findActive(name, time.Now()); jwk != nil {
+		return jwk, nil
+	}
+	s.mtx.Lock()
+	s.names[name] = struct{}{}
+	s.mtx.Unlock()
+	if e := s.Refresh(ctx); e != nil {
+		return nil, e
+	}
+	if jwk := s.findActive(name, time.Now()); jwk != nil {
+		return jwk, nil
+	}
+	return nil, fmt.Errorf("cannot find JWK with name [%s]", name)
+}
+
+func (s *RotatingJwkStore) LoadAll(_ context.Context, names ...string) ([]Jwk, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if len(names) == 0 {
+		names = make([]string, 0, len(s.cache))
+		for name := range s.cache {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	jwks := make([]Jwk, 0, len(names)*3)
+	for _, name := range names {
+		for _, k := range s.cache[name] {
+			jwks = append(jwks, k.jwk)
+		}
+	}
+	return jwks, nil
+}
+
+// Rotate generates a new key of given name. The new key is published immediately and used for signing after PublishAhead
+func (s *RotatingJwkStore) Rotate(ctx context.Context, name string) error {
+	if s.ReadOnly {
+		return fmt.Errorf("cannot rotate JWK [%s] with read-only store", name)
+	}
+	records, e := s.Storage.LoadRecords(ctx, name)
+	if e != nil {
+		return e
+	}
+	if e := s.generate(ctx, name, records, time.Now().Add(s.PublishAhead)); e != nil {
+		return e
+	}
+	return s.Refresh(ctx)
+}
+
+// Refresh reloads keys from storage, generate new keys and delete expired keys if necessary
+func (s *RotatingJwkStore) Refresh(ctx context.Context) error {
+	s.mtx.RLock()
+	names := make([]string, 0, len(s.names))
+	for name := range s.names {
+		names = append(names, name)
+	}
+	s.mtx.RUnlock()
+
+	cache := map[string][]*rotatingJwk{}
+	for _, name := range names {
+		keys, e := s.refresh(ctx, name)
+		if e != nil {
+			return e
+		}
+		cache[name] = keys
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.cache = cache
+	s.loadedAt = time.Now()
+	s.unknownKids = map[string]time.Time{}
+	return nil
+}
+
+// Start refreshes keys every RefreshInterval in background until given context is cancelled
+func (s *RotatingJwkStore) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.RefreshInterval)
+		defer ticker.Stop()
+		for {
+			if e := s.Refresh(ctx); e != nil {
+				logger.WithContext(ctx).Warnf("unable to refresh JWKs: %v", e)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+/*********************
+	Helpers
+ *********************/
+
+func (s *RotatingJwkStore) refresh(ctx context.Context, name string) ([]*rotatingJwk, error) {
+	records, e := s.Storage.LoadRecords(ctx, name)
+	if e != nil {
+		return nil, fmt.Errorf("unable to load JWK [%s]: %v", name, e)
+	}
+	if !s.ReadOnly {
+		now := time.Now()
+		latest := latestRecord(records)
+		var generate bool
+		var activateAt time.Time
+		switch {
+		case latest == nil:
+			// bootstrap, the first key is used immediately
+			generate, activateAt = true, now
+		case !now.Before(latest.ActivateAt.Add(s.Interval - s.PublishAhead)):
+			generate, activateAt = true, now.Add(s.PublishAhead)
+		}
+		if generate {
+			if e := s.generate(ctx, name, records, activateAt); e != nil {
+				return nil, e
+			}
+			if records, e = s.Storage.LoadRecords(ctx, name); e != nil {
+				return nil, fmt.Errorf("unable to load JWK [%s]: %v", name, e)
+			}
+		}
+		records = s.prune(ctx, records, now)
+	}
+
+	keys := make([]*rotatingJwk, 0, len(records))
+	for _, r := range records {
+		priv, e := x509.ParsePKCS8PrivateKey(r.PrivateKey)
+		if e != nil {
+			logger.WithContext(ctx).Warnf("ignored JWK [%s] due to error: %v", r.Kid, e)
+			continue
+		}
+		keys = append(keys, &rotatingJwk{
+			record: r,
+			jwk:    NewPrivateJwk(r.Kid, r.Name, priv),
+		})
+	}
+	return keys, nil
+}
+
+func (s *RotatingJwkStore) generate(ctx context.Context, name string, records []*JwkRecord, activateAt time.Time) error {
+	generation := 1
+	if latest := latestRecord(records); latest != nil {
+		generation = latest.Generation + 1
+	}
+	priv, e := generateCompatiblePrivateKey(s.SigningMethod)
+	if e != nil {
+		return fmt.Errorf("unable to generate JWK [%s]: %v", name, e)
+	}
+	der, e := x509.MarshalPKCS8PrivateKey(priv)
+	if e != nil {
+		return fmt.Errorf("unable to encode JWK [%s]: %v", name, e)
+	}
+	record := JwkRecord{
+		Name:       name,
+		Generation: generation,
+		Kid:        uuid.New().String(),
+		PrivateKey: der,
+		CreatedAt:  time.Now().UTC(),
+		ActivateAt: activateAt.UTC(),
+	}
+	switch e := s.Storage.CreateRecord(ctx, &record); {
+	case errors.Is(e, ErrJwkRecordExists):
+		// another replica rotated the key at the same time
+		logger.WithContext(ctx).Debugf("JWK [%s] generation %d is created by another instance", name, generation)
+	case e != nil:
+		return fmt.Errorf("unable to save JWK [%s]: %v", name, e)
+	default:
+		logger.WithContext(ctx).Infof("JWK [%s] generation %d is generated with kid=%s, active at %v", name, generation, record.Kid, record.ActivateAt)
+	}
+	return nil
+}
+
+// prune deletes keys that retired before "Retention", returns remaining records sorted by generation
+func (s *RotatingJwkStore) prune(ctx context.Context, records []*JwkRecord, now time.Time) []*JwkRecord {
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Generation < records[j].Generation })
+	remaining := make([]*JwkRecord, 0, len(records))
+	for i, r := range records {
+		// a key retires when the next key becomes active
+		if i+1 < len(records) && now.After(records[i+1].ActivateAt.Add(s.Retention)) {
+			if e := s.Storage.DeleteRecord(ctx, r); e != nil {
+				logger.WithContext(ctx).Warnf("unable to delete expired JWK [%s]: %v", r.Kid, e)
+			} else {
+				continue
+			}
+		}
+		remaining = append(remaining, r)
+	}
+	return remaining
+}
+
+// refreshOnDemand refreshes keys unless they were refreshed within MinRefreshInterval.
+// Concurrent callers share the same refresh
+func (s *RotatingJwkStore) refreshOnDemand(ctx context.Context) error {
+	_, e, _ := s.refreshing.Do("refresh", func() (interface{}, error) {
+		s.mtx.Lock()
+		if time.Since(s.onDemandAt) < s.MinRefreshInterval {
+			s.mtx.Unlock()
+			return nil, nil
+		}
+		s.onDemandAt = time.Now()
+		s.mtx.Unlock()
+		return nil, s.Refresh(ctx)
+	})
+	return e
+}
+
+func (s *RotatingJwkStore) isKnownUnknown(kid string) bool {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	exp, ok := s.unknownKids[kid]
+	return ok && time.Now().Before(exp)
+}
+
+// markUnknown adds the kid to negative cache until next scheduled refresh. The cache is bounded to avoid memory exhaustion
+func (s *RotatingJwkStore) markUnknown(kid string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if len(s.unknownKids) >= maxUnknownKids {
+		s.unknownKids = map[string]time.Time{}
+	}
+	s.unknownKids[kid] = time.Now().Add(s.RefreshInterval)
+}
+
+func (s *RotatingJwkStore) findByKid(kid string) Jwk {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	for _, keys := range s.cache {
+		for _, k := range keys {
+			if k.record.Kid == kid {
+				return k.jwk
+			}
+		}
+	}
+	return nil
+}
+
+// findActive returns the newest activated key. nil if the cache is stale or no key found
+func (s *RotatingJwkStore) findActive(name string, now time.Time) Jwk {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if now.Sub(s.loadedAt) > s.RefreshInterval*2 {
+		return nil
+	}
+	var active *rotatingJwk
+	for _, k := range s.cache[name] {
+		if !k.record.ActivateAt.After(now) && (active == nil || k.record.Generation > active.record.Generation) {
+			active = k
+		}
+	}
+	if active == nil {
+		return nil
+	}
+	return active.jwk
+}
+
+func latestRecord(records []*JwkRecord) (latest *JwkRecord) {
+	for _, r := range records {
+		if latest == nil || r.Generation > latest.Generation {
+			latest = r
+		}
+	}
+	return
+}
+
+/*********************
+	In-Memory Storage
+ *********************/
+
+// InMemoryJwkStorage implements RotatingJwkStorage. Keys are not shared between instances and lost on restart.
+// It's intended for tests only, and has to be set explicitly as JwkStorage of authorization and resource servers.
+type InMemoryJwkStorage struct {
+	mtx     sync.Mutex
+	records map[string]map[int]*JwkRecord
+}
+
+func NewInMemoryJwkStorage() *InMemoryJwkStorage {
+	return &InMemoryJwkStorage{
+		records: map[string]map[int]*JwkRecord{},
+	}
+}
+
+func (s *InMemoryJwkStorage) LoadRecords(_ context.Context, name string) ([]*JwkRecord, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	records := make([]*JwkRecord, 0, len(s.records[name]))
+	for _, r := range s.records[name] {
+		cp := *r
+		records = append(records, &cp)
+	}
+	return records, nil
+}
+
+func (s *InMemoryJwkStorage) CreateRecord(_ context.Context, record *JwkRecord) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.records[record.Name] == nil {
+		s.records[record.Name] = map[int]*JwkRecord{}
+	}
+	if _, ok := s.records[record.Name][record.Generation]; ok {
+		return ErrJwkRecordExists
+	}
+	cp := *record
+	s.records[record.Name][record.Generation] = &cp
+	return nil
+}
+
+func (s *InMemoryJwkStorage) DeleteRecord(_ context.Context, record *JwkRecord) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.records[record.Name], record.Generation)
+	return nil
+}
+
+// RotatingJwkStoreWithProperties configures RotatingJwkStore using JwkRotationProperties
+func RotatingJwkStoreWithProperties(props JwtProperties) RotatingJwkStoreOptions {
+	return func(opt *RotatingJwkStoreOption) {
+		if props.KeyName != "" {
+			opt.Names = append(opt.Names, props.KeyName)
+		}
+		if method := jwt.GetSigningMethod(props.Rotation.SigningMethod); method != nil {
+			opt.SigningMethod = method
+		}
+		if props.Rotation.Interval > 0 {
+			opt.Interval = time.Duration(props.Rotation.Interval)
+		}
+		if props.Rotation.PublishAhead > 0 {
+			opt.PublishAhead = time.Duration(props.Rotation.PublishAhead)
+		}
+		if props.Rotation.MaxTokenValidity > 0 {
+			opt.MaxTokenValidity = time.Duration(props.Rotation.MaxTokenValidity)
+		}
+		if props.Rotation.Retention > 0 {
+			opt.Retention = time.Duration(props.Rotation.Retention)
+		}
+		if props.Rotation.RefreshInterval > 0 {
+			opt.RefreshInterval = time.Duration(props.Rotation.RefreshInterval)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/*************************
+	Test Setup
+ *************************/
+
+const TestRotatingKeyName = "test-rotating"
+
+func newTestRotatingJwkStore(g *gomega.WithT, storage RotatingJwkStorage, readOnly bool) *RotatingJwkStore {
+	store, e := NewRotatingJwkStore(func(opt *RotatingJwkStoreOption) {
+		opt.Storage = storage
+		opt.Names = []string{TestRotatingKeyName}
+		opt.SigningMethod = jwt.SigningMethodES256
+		opt.Interval = time.Hour
+		opt.PublishAhead = time.Minute
+		opt.MaxTokenValidity = time.Hour
+		opt.Retention = 2 * time.Hour
+		opt.ReadOnly = readOnly
+	})
+	g.Expect(e).To(Succeed(), "store should be created")
+	return store
+}
+
+// shiftActivation moves activation time of given generation
+func shiftActivation(storage *InMemoryJwkStorage, generation int, d time.Duration) {
+	storage.mtx.Lock()
+	defer storage.mtx.Unlock()
+	r := storage.records[TestRotatingKeyName][generation]
+	r.ActivateAt = r.ActivateAt.Add(d)
+}
+
+// countingJwkStorage counts how many times records are loaded
+type countingJwkStorage struct {
+	*InMemoryJwkStorage
+	loads int32
+}
+
+func (s *countingJwkStorage) LoadRecords(ctx context.Context, name string) ([]*JwkRecord, error) {
+	atomic.AddInt32(&s.loads, 1)
+	return s.InMemoryJwkStorage.LoadRecords(ctx, name)
+}
+
+/*************************
+	Test Cases
+ *************************/
+
+func TestRotatingJwkStore(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestRotationLifecycle(), "RotationLifecycle"),
+		test.GomegaSubTest(SubTestSharedStorage(), "SharedStorage"),
+		test.GomegaSubTest(SubTestUnknownKid(), "UnknownKid"),
+		test.GomegaSubTest(SubTestRetentionValidation(), "RetentionValidation"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestRotationLifecycle() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		storage := NewInMemoryJwkStorage()
+		store := newTestRotatingJwkStore(g, storage, false)
+
+		// bootstrap
+		first, e := store.LoadByName(ctx, TestRotatingKeyName)
+		g.Expect(e).To(Succeed(), "first key should be generated")
+		_, ok := first.(PrivateJwk)
+		g.Expect(ok).To(BeTrue(), "signing key should have private key")
+
+		// rotate: new key is published but not used for signing
+		g.Expect(store.Rotate(ctx, TestRotatingKeyName)).To(Succeed())
+		jwks, e := store.LoadAll(ctx)
+		g.Expect(e).To(Succeed())
+		g.Expect(jwks).To(HaveLen(2), "new key should be published")
+		current, e := store.LoadByName(ctx, TestRotatingKeyName)
+		g.Expect(e).To(Succeed())
+		g.Expect(current.Id()).To(Equal(first.Id()), "new key should not be used before activation")
+
+		// activation
+		shiftActivation(storage, 2, -2*time.Minute)
+		g.Expect(store.Refresh(ctx)).To(Succeed())
+		current, e = store.LoadByName(ctx, TestRotatingKeyName)
+		g.Expect(e).To(Succeed())
+		g.Expect(current.Id()).ToNot(Equal(first.Id()), "new key should be used after activation")
+		retired, e := store.LoadByKid(ctx, first.Id())
+		g.Expect(e).To(Succeed(), "retired key should be available for verification")
+		g.Expect(retired.Id()).To(Equal(first.Id()))
+
+		// retention passed, old key should be removed and a new key should be scheduled
+		shiftActivation(storage, 2, -3*time.Hour)
+		g.Expect(store.Refresh(ctx)).To(Succeed())
+		_, e = store.LoadByKid(ctx, first.Id())
+		g.Expect(e).To(HaveOccurred(), "expired key should be removed")
+		jwks, e = store.LoadAll(ctx)
+		g.Expect(e).To(Succeed())
+		g.Expect(jwks).To(HaveLen(2), "active key and next published key should be available")
+	}
+}
+
+func SubTestSharedStorage() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		storage := NewInMemoryJwkStorage()
+		store := newTestRotatingJwkStore(g, storage, false)
+		replica := newTestRotatingJwkStore(g, storage, true)
+
+		_, e := replica.LoadByName(ctx, TestRotatingKeyName)
+		g.Expect(e).To(HaveOccurred(), "read-only store should not generate keys")
+
+		jwk, e := store.LoadByName(ctx, TestRotatingKeyName)
+		g.Expect(e).To(Succeed())
+		shared, e := replica.LoadByKid(ctx, jwk.Id())
+		g.Expect(e).To(Succeed(), "key should be shared via storage")
+		g.Expect(shared.Public()).To(Equal(jwk.Public()))
+		g.Expect(replica.Rotate(ctx, TestRotatingKeyName)).ToNot(Succeed(), "read-only store cannot rotate")
+	}
+}
+
+func SubTestUnknownKid() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		storage := &countingJwkStorage{InMemoryJwkStorage: NewInMemoryJwkStorage()}
+		store := newTestRotatingJwkStore(g, storage, false)
+		_, e := store.LoadByName(ctx, TestRotatingKeyName)
+		g.Expect(e).To(Succeed())
+
+		before := atomic.LoadInt32(&storage.loads)
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, e := store.LoadByKid(ctx, fmt.Sprintf("unknown-%d", i))
+				g.Expect(e).To(HaveOccurred(), "unknown kid should not be found")
+			}(i)
+		}
+		wg.Wait()
+		g.Expect(atomic.LoadInt32(&storage.loads)-before).To(BeNumerically("<=", 1),
+			"on-demand refresh should be rate limited")
+
+		// negative cache
+		before = atomic.LoadInt32(&storage.loads)
+		_, e = store.LoadByKid(ctx, "unknown-0")
+		g.Expect(e).To(HaveOccurred())
+		g.Expect(atomic.LoadInt32(&storage.loads)).To(Equal(before), "known unknown kid should not trigger refresh")
+	}
+}
+
+func SubTestRetentionValidation() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		store, e := NewRotatingJwkStore(func(opt *RotatingJwkStoreOption) {
+			opt.Storage = NewInMemoryJwkStorage()
+			opt.MaxTokenValidity = 72 * time.Hour
+		})
+		g.Expect(e).To(Succeed())
+		g.Expect(store.Retention).To(Equal(72*time.Hour), "retention should default to max token validity")
+
+		_, e = NewRotatingJwkStore(func(opt *RotatingJwkStoreOption) {
+			opt.Storage = NewInMemoryJwkStorage()
+			opt.MaxTokenValidity = 72 * time.Hour
+			opt.Retention = 24 * time.Hour
+		})
+		g.Expect(e).To(HaveOccurred(), "retention shorter than max token validity should be rejected")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package jwkstorage
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/data"
+	"github.com/cisco-open/go-lanai/pkg/data/repo"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"time"
+)
+
+// JwkRecordModel is the persisted model of jwt.JwkRecord
+type JwkRecordModel struct {
+	Name       string    `gorm:"primaryKey;type:varchar(128)"`
+	Generation int       `gorm:"primaryKey"`
+	Kid        string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	PrivateKey []byte    `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+	ActivateAt time.Time `gorm:"not null"`
+}
+
+func (JwkRecordModel) TableName() string {
+	return "security_jwk_records"
+}
+
+// GormStorage implements jwt.RotatingJwkStorage using repo.CrudRepository.
+// Note: private keys are stored as-is. Use column encryption or database-level encryption if necessary.
+type GormStorage struct {
+	repo repo.CrudRepository
+}
+
+func NewGormStorage(factory repo.Factory) *GormStorage {
+	return &GormStorage{
+		repo: factory.NewCRUD(&JwkRecordModel{}),
+	}
+}
+
+func (s *GormStorage) LoadRecords(ctx context.Context, name string) ([]*jwt.JwkRecord, error) {
+	var models []*JwkRecordModel
+	if e := s.repo.FindAllBy(ctx, &models, map[string]interface{}{"name": name}); e != nil {
+		return nil, e
+	}
+	records := make([]*jwt.JwkRecord, len(models))
+	for i, m := range models {
+		records[i] = &jwt.JwkRecord{
+			Name:       m.Name,
+			Generation: m.Generation,
+			Kid:        m.Kid,
+			PrivateKey: m.PrivateKey,
+			CreatedAt:  m.CreatedAt,
+			ActivateAt: m.ActivateAt,
+		}
+	}
+	return records, nil
+}
+
+func (s *GormStorage) CreateRecord(ctx context.Context, record *jwt.JwkRecord) error {
+	model := JwkRecordModel{
+		Name:       record.Name,
+		Generation: record.Generation,
+		Kid:        record.Kid,
+		PrivateKey: record.PrivateKey,
+		CreatedAt:  record.CreatedAt,
+		ActivateAt: record.ActivateAt,
+	}
+	switch e := s.repo.Create(ctx, &model); {
+	case errors.Is(e, data.ErrorDuplicateKey):
+		return jwt.ErrJwkRecordExists
+	default:
+		return e
+	}
+}
+
+func (s *GormStorage) DeleteRecord(ctx context.Context, record *jwt.JwkRecord) error {
+	return s.repo.Delete(ctx, &JwkRecordModel{Name: record.Name, Generation: record.Generation})
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package jwkstorage
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/data/repo"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/apptest"
+	"github.com/cisco-open/go-lanai/test/dbtest"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"go.uber.org/fx"
+	"testing"
+	"time"
+)
+
+/*************************
+	Test Setup
+ *************************/
+
+//func TestMain(m *testing.M) {
+//	suitetest.RunTests(m,
+//		dbtest.EnableDBRecordMode(),
+//	)
+//}
+
+//go:embed testdata/*.sql
+var testDataFS embed.FS
+
+type gormDI struct {
+	fx.In
+	dbtest.DI
+	Factory repo.Factory
+}
+
+func SetupGormTestPrepareTables(di *gormDI) test.SetupFunc {
+	return dbtest.PrepareData(&di.DI,
+		dbtest.SetupUsingSQLFile(testDataFS, "testdata/tables.sql"),
+		dbtest.SetupTruncateTables(JwkRecordModel{}.TableName()),
+	)
+}
+
+/*************************
+	Test Cases
+ *************************/
+
+func TestGormStorage(t *testing.T) {
+	di := &gormDI{}
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		dbtest.WithDBPlayback("testdb"),
+		apptest.WithModules(repo.Module),
+		apptest.WithDI(di),
+		test.SubTestSetup(SetupGormTestPrepareTables(di)),
+		test.GomegaSubTest(SubTestGormCreateAndLoad(di), "CreateAndLoad"),
+		test.GomegaSubTest(SubTestGormDuplicateGeneration(di), "DuplicateGeneration"),
+		test.GomegaSubTest(SubTestGormDelete(di), "Delete"),
+		test.GomegaSubTest(SubTestGormDBError(di), "DBError"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestGormCreateAndLoad(di *gormDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		storage := NewGormStorage(di.Factory)
+		now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		record := newTestJwkRecord(1)
+		record.CreatedAt = now
+		record.ActivateAt = now.Add(time.Minute)
+		g.Expect(storage.CreateRecord(ctx, record)).To(Succeed())
+
+		records, e := storage.LoadRecords(ctx, TestJwkName)
+		g.Expect(e).To(Succeed())
+		g.Expect(records).To(HaveLen(1))
+		g.Expect(records[0]).To(Equal(record), "loaded record should be same as created")
+
+		records, e = storage.LoadRecords(ctx, "another-key")
+		g.Expect(e).To(Succeed())
+		g.Expect(records).To(BeEmpty(), "records of other names should not be loaded")
+	}
+}
+
+func SubTestGormDuplicateGeneration(di *gormDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		storage := NewGormStorage(di.Factory)
+		g.Expect(storage.CreateRecord(ctx, newTestJwkRecord(1))).To(Succeed())
+		e := storage.CreateRecord(ctx, newTestJwkRecord(1))
+		g.Expect(e).To(Equal(jwt.ErrJwkRecordExists), "duplicate key should be translated")
+	}
+}
+
+func SubTestGormDelete(di *gormDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		storage := NewGormStorage(di.Factory)
+		g.Expect(storage.CreateRecord(ctx, newTestJwkRecord(1))).To(Succeed())
+		g.Expect(storage.CreateRecord(ctx, newTestJwkRecord(2))).To(Succeed())
+		g.Expect(storage.DeleteRecord(ctx, newTestJwkRecord(1))).To(Succeed())
+		records, e := storage.LoadRecords(ctx, TestJwkName)
+		g.Expect(e).To(Succeed())
+		g.Expect(records).To(HaveLen(1))
+		g.Expect(records[0].Generation).To(Equal(2), "only deleted generation should be removed")
+	}
+}
+
+func SubTestGormDBError(di *gormDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		storage := NewGormStorage(di.Factory)
+		// DB operations fail with cancelled context
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, e := storage.LoadRecords(ctx, TestJwkName)
+		g.Expect(e).To(HaveOccurred())
+		e = storage.CreateRecord(ctx, newTestJwkRecord(1))
+		g.Expect(e).To(HaveOccurred())
+		g.Expect(errors.Is(e, jwt.ErrJwkRecordExists)).To(BeFalse(), "DB errors should not be reported as conflict")
+	}
+}
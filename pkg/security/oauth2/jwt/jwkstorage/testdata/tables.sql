@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS security_jwk_records
+(
+    name        varchar(128) NOT NULL,
+    generation  int          NOT NULL,
+    kid         varchar(64)  NOT NULL,
+    private_key bytea        NOT NULL,
+    created_at  timestamptz  NOT NULL,
+    activate_at timestamptz  NOT NULL,
+    CONSTRAINT "primary" PRIMARY KEY (name, generation),
+    CONSTRAINT idx_security_jwk_records_kid UNIQUE (kid)
+);
@@ -0,0 +1,171 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package jwkstorage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"github.com/cisco-open/go-lanai/pkg/vault"
+	"net/url"
+	"strings"
+)
+
+const (
+	vaultFieldRecords  = "records"
+	vaultMaxCasRetries = 3
+)
+
+type VaultStorageOptions func(opt *VaultStorageOption)
+
+type VaultStorageOption struct {
+	// Mount is the mount path of KV version 2 secret engine
+	Mount string
+	// Prefix is the path prefix of secrets under Mount
+	Prefix string
+}
+
+// VaultStorage implements jwt.RotatingJwkStorage using Vault KV version 2 secret engine.
+// All records of same name are saved in a single secret, and check-and-set is used to prevent concurrent modification.
+type VaultStorage struct {
+	client *vault.Client
+	mount  string
+	prefix string
+}
+
+func NewVaultStorage(client *vault.Client, opts ...VaultStorageOptions) *VaultStorage {
+	opt := VaultStorageOption{
+		Mount:  "secret",
+		Prefix: "jwks",
+	}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	return &VaultStorage{
+		client: client,
+		mount:  strings.Trim(opt.Mount, "/"),
+		prefix: strings.Trim(opt.Prefix, "/"),
+	}
+}
+
+func (s *VaultStorage) LoadRecords(ctx context.Context, name string) ([]*jwt.JwkRecord, error) {
+	records, _, e := s.load(ctx, name)
+	return records, e
+}
+
+func (s *VaultStorage) CreateRecord(ctx context.Context, record *jwt.JwkRecord) error {
+	return s.update(ctx, record.Name, func(records []*jwt.JwkRecord) ([]*jwt.JwkRecord, error) {
+		for _, r := range records {
+			if r.Generation == record.Generation {
+				return nil, jwt.ErrJwkRecordExists
+			}
+		}
+		return append(records, record), nil
+	})
+}
+
+func (s *VaultStorage) DeleteRecord(ctx context.Context, record *jwt.JwkRecord) error {
+	return s.update(ctx, record.Name, func(records []*jwt.JwkRecord) ([]*jwt.JwkRecord, error) {
+		remaining := make([]*jwt.JwkRecord, 0, len(records))
+		for _, r := range records {
+			if r.Generation != record.Generation {
+				remaining = append(remaining, r)
+			}
+		}
+		if len(remaining) == len(records) {
+			return nil, nil
+		}
+		return remaining, nil
+	})
+}
+
+// update applies given function on current records and saves the result using check-and-set.
+// When the write fails, the secret is reloaded: a changed version means another instance modified the secret
+// concurrently, and the update is re-applied on the new records. nil records from the function means no change.
+func (s *VaultStorage) update(ctx context.Context, name string, fn func([]*jwt.JwkRecord) ([]*jwt.JwkRecord, error)) error {
+	records, version, e := s.load(ctx, name)
+	if e != nil {
+		return e
+	}
+	for i := 0; ; i++ {
+		updated, e := fn(records)
+		if e != nil || updated == nil {
+			return e
+		}
+		saveErr := s.save(ctx, name, updated, version)
+		if saveErr == nil {
+			return nil
+		}
+		var current int
+		if records, current, e = s.load(ctx, name); e != nil || current == version || i+1 >= vaultMaxCasRetries {
+			return saveErr
+		}
+		version = current
+	}
+}
+
+func (s *VaultStorage) path(name string) string {
+	return fmt.Sprintf("%s/data/%s/%s", s.mount, s.prefix, url.PathEscape(name))
+}
+
+// load returns records and current version of the secret. Version is 0 if the secret doesn't exist
+func (s *VaultStorage) load(ctx context.Context, name string) ([]*jwt.JwkRecord, int, error) {
+	secret, e := s.client.Logical(ctx).Read(s.path(name))
+	if e != nil {
+		return nil, 0, fmt.Errorf("unable to read JWKs from vault: %v", e)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, 0, nil
+	}
+
+	var version int
+	if meta, ok := secret.Data["metadata"].(map[string]interface{}); ok {
+		if v, ok := meta["version"].(json.Number); ok {
+			n, _ := v.Int64()
+			version = int(n)
+		}
+	}
+	data, ok := secret.Data["data"].(map[string]interface{})
+	if !ok {
+		// deleted or destroyed
+		return nil, version, nil
+	}
+	raw, _ := data[vaultFieldRecords].(string)
+	var records []*jwt.JwkRecord
+	if raw != "" {
+		if e := json.Unmarshal([]byte(raw), &records); e != nil {
+			return nil, 0, fmt.Errorf("invalid JWKs in vault: %v", e)
+		}
+	}
+	return records, version, nil
+}
+
+func (s *VaultStorage) save(ctx context.Context, name string, records []*jwt.JwkRecord, version int) error {
+	raw, e := json.Marshal(records)
+	if e != nil {
+		return e
+	}
+	body := map[string]interface{}{
+		"options": map[string]interface{}{"cas": version},
+		"data":    map[string]interface{}{vaultFieldRecords: string(raw)},
+	}
+	if _, e := s.client.Logical(ctx).Write(s.path(name), body); e != nil {
+		return fmt.Errorf("unable to write JWKs to vault: %v", e)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package jwkstorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/jwt"
+	"github.com/cisco-open/go-lanai/pkg/vault"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+/*************************
+	Test Setup
+ *************************/
+
+const TestJwkName = "test-key"
+
+// mockedKvServer mocks Vault KV version 2 secret engine with check-and-set support
+type mockedKvServer struct {
+	mtx      sync.Mutex
+	versions map[string]int
+	data     map[string]interface{}
+	// beforeWrite is invoked once before next write, to simulate concurrent modification
+	beforeWrite func(s *mockedKvServer, path string)
+	failWrite   bool
+}
+
+func newMockedKvServer() *mockedKvServer {
+	return &mockedKvServer{
+		versions: map[string]int{},
+		data:     map[string]interface{}{},
+	}
+}
+
+func (s *mockedKvServer) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := s.versions[path]; !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = rw.Write([]byte(`{"errors":[]}`))
+			return
+		}
+		writeJson(rw, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     s.data[path],
+				"metadata": map[string]interface{}{"version": s.versions[path]},
+			},
+		})
+	case http.MethodPut, http.MethodPost:
+		if fn := s.beforeWrite; fn != nil {
+			s.beforeWrite = nil
+			fn(s, path)
+		}
+		var body struct {
+			Options struct {
+				Cas int `json:"cas"`
+			} `json:"options"`
+			Data interface{} `json:"data"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case s.failWrite:
+			writeJson(rw, http.StatusInternalServerError, map[string]interface{}{"errors": []string{"internal error"}})
+		case body.Options.Cas != s.versions[path]:
+			writeJson(rw, http.StatusBadRequest, map[string]interface{}{
+				"errors": []string{"check-and-set parameter did not match the current version"},
+			})
+		default:
+			s.set(path, body.Data)
+			writeJson(rw, http.StatusOK, map[string]interface{}{
+				"data": map[string]interface{}{"version": s.versions[path]},
+			})
+		}
+	default:
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *mockedKvServer) set(path string, data interface{}) {
+	s.versions[path]++
+	s.data[path] = data
+}
+
+// setRecords writes records directly, as if another instance modified the secret
+func (s *mockedKvServer) setRecords(path string, records ...*jwt.JwkRecord) {
+	raw, _ := json.Marshal(records)
+	s.set(path, map[string]interface{}{vaultFieldRecords: string(raw)})
+}
+
+func writeJson(rw http.ResponseWriter, status int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(v)
+}
+
+func newTestVaultStorage(t *testing.T, g *gomega.WithT) (*VaultStorage, *mockedKvServer) {
+	server := newMockedKvServer()
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	client, e := vault.New(func(cfg *vault.ClientConfig) error {
+		cfg.Address = ts.URL
+		cfg.MaxRetries = 0
+		cfg.ClientAuth = vault.TokenClientAuthentication("test-token")
+		return nil
+	})
+	g.Expect(e).To(Succeed(), "vault client should be created")
+	return NewVaultStorage(client), server
+}
+
+func newTestJwkRecord(gen int) *jwt.JwkRecord {
+	return &jwt.JwkRecord{
+		Name:       TestJwkName,
+		Generation: gen,
+		Kid:        fmt.Sprintf("kid-%d", gen),
+		PrivateKey: []byte("test-private-key"),
+	}
+}
+
+/*************************
+	Test Cases
+ *************************/
+
+func TestVaultStorage(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestVaultCreateAndLoad(), "CreateAndLoad"),
+		test.GomegaSubTest(SubTestVaultConcurrentModification(), "ConcurrentModification"),
+		test.GomegaSubTest(SubTestVaultConcurrentSameGeneration(), "ConcurrentSameGeneration"),
+		test.GomegaSubTest(SubTestVaultWriteFailure(), "WriteFailure"),
+		test.GomegaSubTest(SubTestVaultDelete(), "Delete"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestVaultCreateAndLoad() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		storage, _ := newTestVaultStorage(t, g)
+		records, e := storage.LoadRecords(ctx, TestJwkName)
+		g.Expect(e).To(Succeed(), "loading non-existing secret should not fail")
+		g.Expect(records).To(BeEmpty())
+
+		g.Expect(storage.CreateRecord(ctx, newTestJwkRecord(1))).To(Succeed())
+		g.Expect(storage.CreateRecord(ctx, newTestJwkRecord(2))).To(Succeed())
+		e = storage.CreateRecord(ctx, newTestJwkRecord(2))
+		g.Expect(errors.Is(e, jwt.ErrJwkRecordExists)).To(BeTrue(), "duplicated generation should be rejected")
+
+		records, e = storage.LoadRecords(ctx, TestJwkName)
+		g.Expect(e).To(Succeed())
+		g.Expect(records).To(HaveLen(2))
+		g.Expect(records[1].Kid).To(Equal("kid-2"))
+		g.Expect(records[1].PrivateKey).To(Equal([]byte("test-private-key")))
+	}
+}
+
+func SubTestVaultConcurrentModification() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		storage, server := newTestVaultStorage(t, g)
+		g.Expect(storage.CreateRecord(ctx, newTestJwkRecord(1))).To(Succeed())
+		server.beforeWrite = func(s *mockedKvServer, path string) {
+			// another instance deleted generation 1 and created generation 2
+			s.setRecords(path, newTestJwkRecord(2))
+		}
+		g.Expect(storage.CreateRecord(ctx, newTestJwkRecord(3))).To(Succeed(), "create should be retried with new version")
+
+		records, e := storage.LoadRecords(ctx, TestJwkName)
+		g.Expect(e).To(Succeed())
+		g.Expect(records).To(HaveLen(2), "concurrent modification should not be overwritten")
+		g.Expect(records[0].Generation).To(Equal(2))
+		g.Expect(records[1].Generation).To(Equal(3))
+	}
+}
+
+func SubTestVaultConcurrentSameGeneration() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		storage, server := newTestVaultStorage(t, g)
+		server.beforeWrite = func(s *mockedKvServer, path string) {
+			s.setRecords(path, newTestJwkRecord(1))
+		}
+		e := storage.CreateRecord(ctx, newTestJwkRecord(1))
+		g.Expect(errors.Is(e, jwt.ErrJwkRecordExists)).To(BeTrue(), "generation created by another instance should be reported")
+	}
+}
+
+func SubTestVaultWriteFailure() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		storage, server := newTestVaultStorage(t, g)
+		server.failWrite = true
+		e := storage.CreateRecord(ctx, newTestJwkRecord(1))
+		g.Expect(e).To(HaveOccurred(), "write failure should be returned")
+		g.Expect(errors.Is(e, jwt.ErrJwkRecordExists)).To(BeFalse(), "write failure should not be reported as conflict")
+	}
+}
+
+func SubTestVaultDelete() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		storage, server := newTestVaultStorage(t, g)
+		g.Expect(storage.CreateRecord(ctx, newTestJwkRecord(1))).To(Succeed())
+		g.Expect(storage.CreateRecord(ctx, newTestJwkRecord(2))).To(Succeed())
+		server.beforeWrite = func(s *mockedKvServer, path string) {
+			s.setRecords(path, newTestJwkRecord(1), newTestJwkRecord(2), newTestJwkRecord(3))
+		}
+		g.Expect(storage.DeleteRecord(ctx, newTestJwkRecord(1))).To(Succeed())
+		g.Expect(storage.DeleteRecord(ctx, newTestJwkRecord(5))).To(Succeed(), "deleting non-existing record should succeed")
+
+		records, e := storage.LoadRecords(ctx, TestJwkName)
+		g.Expect(e).To(Succeed())
+		g.Expect(records).To(HaveLen(2))
+		g.Expect(records[0].Generation).To(Equal(2))
+		g.Expect(records[1].Generation).To(Equal(3))
+	}
+}
@@ -19,8 +19,10 @@ package jwt
 import (
 	"github.com/cisco-open/go-lanai/pkg/bootstrap"
 	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/utils"
 	"github.com/pkg/errors"
 	"strings"
+	"time"
 )
 
 var logger = log.New("OAuth2.JWT")
@@ -43,7 +45,24 @@ type CryptoProperties struct {
 }
 
 type JwtProperties struct {
-	KeyName string `json:"key-name"`
+	KeyName  string                `json:"key-name"`
+	Rotation JwkRotationProperties `json:"rotation"`
+}
+
+// JwkRotationProperties configures RotatingJwkStore. When enabled, "key-name" is used as the name of generated keys
+type JwkRotationProperties struct {
+	Enabled       bool   `json:"enabled"`
+	SigningMethod string `json:"signing-method"`
+	// Interval is how long each key is used for signing
+	Interval utils.Duration `json:"interval"`
+	// PublishAhead is how long a new key is published before it's used for signing
+	PublishAhead utils.Duration `json:"publish-ahead"`
+	// MaxTokenValidity is the longest lifetime of tokens signed by rotated keys, including refresh tokens
+	MaxTokenValidity utils.Duration `json:"max-token-validity"`
+	// Retention is how long a retired key is kept for verification. Default to MaxTokenValidity, and cannot be shorter
+	Retention utils.Duration `json:"retention"`
+	// RefreshInterval is how often keys are reloaded from shared storage
+	RefreshInterval utils.Duration `json:"refresh-interval"`
 }
 
 type CryptoKeyProperties struct {
@@ -61,6 +80,15 @@ func (p CryptoKeyProperties) Format() KeyFormatType {
 func NewCryptoProperties() *CryptoProperties {
 	return &CryptoProperties {
 		Keys: map[string]CryptoKeyProperties{},
+		Jwt: JwtProperties{
+			Rotation: JwkRotationProperties{
+				SigningMethod:    "RS256",
+				Interval:         utils.Duration(24 * time.Hour),
+				PublishAhead:     utils.Duration(time.Hour),
+				MaxTokenValidity: utils.Duration(48 * time.Hour),
+				RefreshInterval:  utils.Duration(time.Minute),
+			},
+		},
 	}
 }
 
@@ -0,0 +1,83 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenauth
+
+import (
+	"context"
+	"crypto/x509"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2/dpop"
+	"net/http"
+)
+
+/****************************
+	Token Binding
+ ****************************/
+
+// VerifyCertificateBinding enforces certificate-bound access token: if the token has "cnf" claim with "x5t#S256" member,
+// the first of given peer certificates must match the thumbprint.
+// See https://datatracker.ietf.org/doc/html/rfc8705#section-3
+func VerifyCertificateBinding(auth security.Authentication, peerCerts []*x509.Certificate) error {
+	expected := oauth2.ConfirmationThumbprint(tokenClaims(auth))
+	if expected == "" {
+		return nil
+	}
+	if len(peerCerts) == 0 || oauth2.CertificateThumbprint(peerCerts[0]) != expected {
+		return oauth2.NewInvalidAccessTokenError("access token is bound to a different client certificate")
+	}
+	return nil
+}
+
+// VerifyDPoPBinding enforces DPoP-bound access token: if the token has "cnf" claim with "jkt" member,
+// the token must be presented with "DPoP" scheme along with a valid DPoP proof signed by the same key.
+// "verifier" and "r" are only used when "isDPoP" is true.
+// See https://datatracker.ietf.org/doc/html/rfc9449#section-7
+func VerifyDPoPBinding(ctx context.Context, verifier *dpop.Verifier, r *http.Request, auth security.Authentication, tokenValue string, isDPoP bool) error {
+	expected := oauth2.ConfirmationJwkThumbprint(tokenClaims(auth))
+	switch {
+	case expected == "" && !isDPoP:
+		return nil
+	case expected == "":
+		return oauth2.NewInvalidAccessTokenError("access token is not DPoP-bound")
+	case !isDPoP:
+		return oauth2.NewInvalidAccessTokenError("DPoP-bound access token should be presented with DPoP scheme")
+	case verifier == nil:
+		return oauth2.NewInternalError("DPoP verifier is not configured")
+	}
+
+	proof, e := verifier.Verify(ctx, r, tokenValue)
+	if e != nil {
+		return oauth2.NewInvalidResourceDPoPProofError(e.Error(), e)
+	}
+	if proof.JwkThumbprint != expected {
+		return oauth2.NewInvalidAccessTokenError("access token is bound to a different DPoP key")
+	}
+	return nil
+}
+
+func tokenClaims(auth security.Authentication) oauth2.Claims {
+	oauth, ok := auth.(oauth2.Authentication)
+	if !ok {
+		return nil
+	}
+	container, ok := oauth.AccessToken().(oauth2.ClaimsContainer)
+	if !ok {
+		return nil
+	}
+	return container.Claims()
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"math/big"
+	"testing"
+	"time"
+)
+
+/*************************
+	Test
+ *************************/
+
+func TestCertificateBoundToken(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestUnboundToken(), "UnboundToken"),
+		test.GomegaSubTest(SubTestBoundTokenWithSameCert(), "BoundTokenWithSameCert"),
+		test.GomegaSubTest(SubTestBoundTokenWithDifferentCert(), "BoundTokenWithDifferentCert"),
+		test.GomegaSubTest(SubTestBoundTokenWithoutTLS(), "BoundTokenWithoutTLS"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestUnboundToken() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		e := VerifyCertificateBinding(NewBindingTestAuth(nil), nil)
+		g.Expect(e).To(Succeed(), "token without cnf claim should be accepted without client certificate")
+	}
+}
+
+func SubTestBoundTokenWithSameCert() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		cert := NewBindingTestCert(g)
+		e := VerifyCertificateBinding(NewBindingTestAuth(cert), []*x509.Certificate{cert})
+		g.Expect(e).To(Succeed(), "bound token should be accepted with the same client certificate")
+	}
+}
+
+func SubTestBoundTokenWithDifferentCert() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		peerCerts := []*x509.Certificate{NewBindingTestCert(g)}
+		e := VerifyCertificateBinding(NewBindingTestAuth(NewBindingTestCert(g)), peerCerts)
+		AssertInvalidAccessToken(g, e)
+	}
+}
+
+func SubTestBoundTokenWithoutTLS() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		e := VerifyCertificateBinding(NewBindingTestAuth(NewBindingTestCert(g)), nil)
+		AssertInvalidAccessToken(g, e)
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func NewBindingTestAuth(boundCert *x509.Certificate) oauth2.Authentication {
+	token := oauth2.NewDefaultAccessToken("test-token")
+	claims := oauth2.MapClaims{}
+	if boundCert != nil {
+		claims[oauth2.ClaimConfirmation] = map[string]interface{}{
+			oauth2.ClaimCertThumbprint: oauth2.CertificateThumbprint(boundCert),
+		}
+	}
+	token.SetClaims(claims)
+	return oauth2.NewAuthentication(func(opt *oauth2.AuthOption) {
+		opt.Token = token
+	})
+}
+
+func NewBindingTestCert(g *gomega.WithT) *x509.Certificate {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(e).To(Succeed(), "generating key should not fail")
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "test-client"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	raw, e := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, key.Public(), key)
+	g.Expect(e).To(Succeed(), "creating certificate should not fail")
+	cert, e := x509.ParseCertificate(raw)
+	g.Expect(e).To(Succeed(), "parsing certificate should not fail")
+	return cert
+}
+
+func AssertInvalidAccessToken(g *gomega.WithT, err error) {
+	g.Expect(err).To(HaveOccurred(), "certificate binding should fail")
+	g.Expect(errors.Is(err, oauth2.ErrorTypeOAuth2)).To(BeTrue(), "error should be OAuth2 error")
+	var oauthErr *oauth2.OAuth2Error
+	g.Expect(errors.As(err, &oauthErr)).To(BeTrue(), "error should be OAuth2Error")
+	g.Expect(oauthErr.TranslateErrorCode()).To(Equal(oauth2.ErrorTranslationInvalidToken), "error code should be invalid_token")
+}
@@ -21,6 +21,7 @@ import (
     "github.com/cisco-open/go-lanai/pkg/security"
     "github.com/cisco-open/go-lanai/pkg/security/errorhandling"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2"
+    "github.com/cisco-open/go-lanai/pkg/security/oauth2/dpop"
     "github.com/cisco-open/go-lanai/pkg/web/middleware"
 )
 
@@ -31,6 +32,7 @@ var (
 //goland:noinspection GoNameStartsWithPackageName
 type TokenAuthConfigurer struct {
 	tokenStoreReader oauth2.TokenStoreReader
+	dpopVerifier     *dpop.Verifier
 }
 
 //goland:noinspection GoNameStartsWithPackageName
@@ -39,6 +41,8 @@ type TokenAuthOptions func(opt *TokenAuthOption)
 //goland:noinspection GoNameStartsWithPackageName
 type TokenAuthOption struct {
 	TokenStoreReader oauth2.TokenStoreReader
+	// DPoPVerifier verifies DPoP proofs of DPoP-bound access tokens. Default verifier without replay check is used if not set
+	DPoPVerifier *dpop.Verifier
 }
 
 func NewTokenAuthConfigurer(opts ...TokenAuthOptions) *TokenAuthConfigurer {
@@ -46,8 +50,12 @@ func NewTokenAuthConfigurer(opts ...TokenAuthOptions) *TokenAuthConfigurer {
 	for _, f := range opts {
 		f(&opt)
 	}
+	if opt.DPoPVerifier == nil {
+		opt.DPoPVerifier = dpop.NewVerifier()
+	}
 	return &TokenAuthConfigurer{
 		tokenStoreReader: opt.TokenStoreReader,
+		dpopVerifier:     opt.DPoPVerifier,
 	}
 }
 
@@ -78,6 +86,7 @@ func (c *TokenAuthConfigurer) Apply(feature security.Feature, ws security.WebSec
 		opt.Authenticator = ws.Authenticator()
 		opt.SuccessHandler = successHandler
 		opt.PostBodyEnabled = f.postBodyEnabled
+		opt.DPoPVerifier = c.dpopVerifier
 	})
 
 	// install middlewares
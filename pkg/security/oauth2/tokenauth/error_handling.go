@@ -50,7 +50,11 @@ func writeOAuth2Error(c context.Context, r *http.Request, rw http.ResponseWriter
 	challenge := ""
 	sc := err.TranslateStatusCode()
 	if sc == http.StatusUnauthorized || sc == http.StatusForbidden {
-		challenge = fmt.Sprintf("%s %s", "Bearer", err.Error())
+		scheme := "Bearer"
+		if err.TranslateErrorCode() == oauth2.ErrorTranslationInvalidDPoPProof {
+			scheme = "DPoP"
+		}
+		challenge = fmt.Sprintf("%s %s", scheme, err.Error())
 	}
 	writeAdditionalHeader(c, r, rw, challenge)
 	security.WriteError(c, r, rw, sc, err)
@@ -17,15 +17,18 @@
 package tokenauth
 
 import (
+    "crypto/x509"
     "errors"
     "github.com/cisco-open/go-lanai/pkg/security"
     "github.com/cisco-open/go-lanai/pkg/security/oauth2"
+    "github.com/cisco-open/go-lanai/pkg/security/oauth2/dpop"
     "github.com/gin-gonic/gin"
     "strings"
 )
 
 const (
 	bearerTokenPrefix = "Bearer "
+	dpopTokenPrefix   = "DPoP "
 )
 
 /****************************
@@ -37,6 +40,7 @@ type TokenAuthMiddleware struct {
 	authenticator   security.Authenticator
 	successHandler  security.AuthenticationSuccessHandler
 	postBodyEnabled bool
+	dpopVerifier    *dpop.Verifier
 }
 
 //goland:noinspection GoNameStartsWithPackageName
@@ -47,6 +51,7 @@ type TokenAuthMWOption struct {
 	Authenticator   security.Authenticator
 	SuccessHandler  security.AuthenticationSuccessHandler
 	PostBodyEnabled bool
+	DPoPVerifier    *dpop.Verifier
 }
 
 func NewTokenAuthMiddleware(opts ...TokenAuthMWOptions) *TokenAuthMiddleware {
@@ -60,6 +65,7 @@ func NewTokenAuthMiddleware(opts ...TokenAuthMWOptions) *TokenAuthMiddleware {
 		authenticator:   opt.Authenticator,
 		successHandler:  opt.SuccessHandler,
 		postBodyEnabled: opt.PostBodyEnabled,
+		dpopVerifier:    opt.DPoPVerifier,
 	}
 }
 
@@ -71,7 +77,7 @@ func (mw *TokenAuthMiddleware) AuthenticateHandlerFunc() gin.HandlerFunc {
 		security.MustClear(ctx)
 
 		// grab bearer token and create candidate
-		tokenValue, e := mw.extractAccessToken(ctx)
+		tokenValue, isDPoP, e := mw.extractAccessToken(ctx)
 		if e != nil {
 			mw.handleError(ctx, e)
 			return
@@ -90,6 +96,18 @@ func (mw *TokenAuthMiddleware) AuthenticateHandlerFunc() gin.HandlerFunc {
 			mw.handleError(ctx, err)
 			return
 		}
+		var peerCerts []*x509.Certificate
+		if ctx.Request.TLS != nil {
+			peerCerts = ctx.Request.TLS.PeerCertificates
+		}
+		if err := VerifyCertificateBinding(auth, peerCerts); err != nil {
+			mw.handleError(ctx, err)
+			return
+		}
+		if err := VerifyDPoPBinding(ctx, mw.dpopVerifier, ctx.Request, auth, tokenValue, isDPoP); err != nil {
+			mw.handleError(ctx, err)
+			return
+		}
 		mw.handleSuccess(ctx, before, auth)
 	}
 }
@@ -103,7 +121,7 @@ func (mw *TokenAuthMiddleware) handleSuccess(c *gin.Context, before, new securit
 	// we don't explicitly write any thing on success
 }
 
-func (mw *TokenAuthMiddleware) extractAccessToken(ctx *gin.Context) (ret string, err error) {
+func (mw *TokenAuthMiddleware) extractAccessToken(ctx *gin.Context) (ret string, isDPoP bool, err error) {
 	header := ctx.GetHeader("Authorization")
 	if header == "" {
 		if mw.postBodyEnabled {
@@ -111,11 +129,14 @@ func (mw *TokenAuthMiddleware) extractAccessToken(ctx *gin.Context) (ret string,
 		}
 		return
 	}
-	if !strings.HasPrefix(strings.ToUpper(header), strings.ToUpper(bearerTokenPrefix)) {
-		return "", oauth2.NewInvalidAccessTokenError("missing bearer token")
+	switch upper := strings.ToUpper(header); {
+	case strings.HasPrefix(upper, strings.ToUpper(bearerTokenPrefix)):
+		return header[len(bearerTokenPrefix):], false, nil
+	case strings.HasPrefix(upper, strings.ToUpper(dpopTokenPrefix)):
+		return header[len(dpopTokenPrefix):], true, nil
+	default:
+		return "", false, oauth2.NewInvalidAccessTokenError("missing bearer token")
 	}
-
-	return header[len(bearerTokenPrefix):], nil
 }
 
 func (mw *TokenAuthMiddleware) handleError(c *gin.Context, err error) {
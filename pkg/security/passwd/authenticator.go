@@ -26,6 +26,13 @@ import (
     "time"
 )
 
+// PasswordVerifier verifies password against an external credential system, e.g. LDAP bind.
+// When the security.AccountStore implements PasswordVerifier, Authenticator uses it instead of PasswordEncoder.
+// Errors of security.ErrorSubTypeInternalError (e.g. directory server unavailable) are not treated as bad credentials
+type PasswordVerifier interface {
+	VerifyPassword(ctx context.Context, acct security.Account, password string) error
+}
+
 /******************************
 	security.Authenticator
 ******************************/
@@ -48,6 +55,8 @@ type AuthenticatorOptions struct {
 	MFAEventListeners []MFAEventListenerFunc
 	Checkers          []AuthenticationDecisionMaker
 	PostProcessors    []PostAuthenticationProcessor
+	// MFAFactorVerifiers are additional factors accepted by MFA verification, besides OTP
+	MFAFactorVerifiers []MFAFactorVerifier
 }
 
 func NewAuthenticator(optionFuncs...AuthenticatorOptionsFunc) *Authenticator {
@@ -91,7 +100,11 @@ func (a *Authenticator) Authenticate(ctx context.Context, candidate security.Can
 
 	// Search user in the slice of allowed credentials
 	user, e := a.accountStore.LoadAccountByUsername(ctx, upp.Username)
-	if e != nil {
+	switch {
+	case e != nil && errors.Is(e, security.ErrorSubTypeInternalError):
+		err = e
+		return
+	case e != nil:
 		err = security.NewUsernameNotFoundError(MessageUserNotFound, e)
 		return
 	}
@@ -103,10 +116,8 @@ func (a *Authenticator) Authenticate(ctx context.Context, candidate security.Can
 	}
 
 	// Check password
-	if password, ok := user.Credentials().(string);
-		!ok || upp.Username != user.Username() || !a.passwdEncoder.Matches(upp.Password, password) {
-
-		err = security.NewBadCredentialsError(MessageBadCredential)
+	if e := a.verifyPassword(ctx, upp, user); e != nil {
+		err = e
 		return
 	}
 
@@ -170,6 +181,26 @@ func (a *Authenticator) CreateSuccessAuthentication(candidate *UsernamePasswordP
 	return &auth, nil
 }
 
+func (a *Authenticator) verifyPassword(ctx context.Context, upp *UsernamePasswordPair, user security.Account) error {
+	if upp.Username != user.Username() {
+		return security.NewBadCredentialsError(MessageBadCredential)
+	}
+	if verifier, ok := a.accountStore.(PasswordVerifier); ok {
+		switch e := verifier.VerifyPassword(ctx, user, upp.Password); {
+		case e == nil:
+			return nil
+		case errors.Is(e, security.ErrorSubTypeInternalError):
+			return e
+		default:
+			return security.NewBadCredentialsError(MessageBadCredential, e)
+		}
+	}
+	if password, ok := user.Credentials().(string); !ok || !a.passwdEncoder.Matches(upp.Password, password) {
+		return security.NewBadCredentialsError(MessageBadCredential)
+	}
+	return nil
+}
+
 func (a *Authenticator) translate(err error) error {
 
 	switch {
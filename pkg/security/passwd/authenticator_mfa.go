@@ -34,6 +34,7 @@ type MfaVerifyAuthenticator struct {
 	mfaEventListeners []MFAEventListenerFunc
 	checkers 		  []AuthenticationDecisionMaker
 	postProcessors	  []PostAuthenticationProcessor
+	factorVerifiers   []MFAFactorVerifier
 }
 
 func NewMFAVerifyAuthenticator(optionFuncs...AuthenticatorOptionsFunc) *MfaVerifyAuthenticator {
@@ -49,6 +50,7 @@ func NewMFAVerifyAuthenticator(optionFuncs...AuthenticatorOptionsFunc) *MfaVerif
 		mfaEventListeners: options.MFAEventListeners,
 		checkers: 		   options.Checkers,
 		postProcessors:    options.PostProcessors,
+		factorVerifiers:   options.MFAFactorVerifiers,
 	}
 }
 
@@ -77,15 +79,22 @@ func (a *MfaVerifyAuthenticator) Authenticate(ctx context.Context, candidate sec
 		return
 	}
 
-	// Check OTP
+	// Check additional factors (e.g. authenticator app) first, then OTP
 	id := verify.CurrentAuth.OTPIdentifier()
-	switch otp, more, e := a.otpStore.Verify(id, verify.OTP); {
+	switch verified, e := a.verifyAdditionalFactors(ctx, user, verify.OTP); {
 	case e != nil:
-		broadcastMFAEvent(MFAEventVerificationFailure, otp, user, a.mfaEventListeners...)
-		err = a.translate(e, more)
+		err = a.translate(e, true)
 		return
+	case verified:
+		if otp, e := a.otpStore.Get(id); e == nil {
+			broadcastMFAEvent(MFAEventVerificationSuccess, otp, user, a.mfaEventListeners...)
+			_ = a.otpStore.Delete(id)
+		}
 	default:
-		broadcastMFAEvent(MFAEventVerificationSuccess, otp, user, a.mfaEventListeners...)
+		if e := a.verifyOtp(id, verify.OTP, user); e != nil {
+			err = e
+			return
+		}
 	}
 
 	newAuth, e := a.CreateSuccessAuthentication(verify, user)
@@ -128,6 +137,26 @@ func (a *MfaVerifyAuthenticator) CreateSuccessAuthentication(candidate *MFAOtpVe
 	return &auth, nil
 }
 
+func (a *MfaVerifyAuthenticator) verifyOtp(id, passcode string, user security.Account) error {
+	switch otp, more, e := a.otpStore.Verify(id, passcode); {
+	case e != nil:
+		broadcastMFAEvent(MFAEventVerificationFailure, otp, user, a.mfaEventListeners...)
+		return a.translate(e, more)
+	default:
+		broadcastMFAEvent(MFAEventVerificationSuccess, otp, user, a.mfaEventListeners...)
+		return nil
+	}
+}
+
+func (a *MfaVerifyAuthenticator) verifyAdditionalFactors(ctx context.Context, user security.Account, passcode string) (bool, error) {
+	for _, verifier := range a.factorVerifiers {
+		if ok, e := verifier.VerifyFactor(ctx, user, passcode); e != nil || ok {
+			return ok, e
+		}
+	}
+	return false, nil
+}
+
 func (a *MfaVerifyAuthenticator) translate(err error, more bool) error {
 	if more {
 		return security.NewBadCredentialsError(MessageInvalidPasscode, err)
@@ -31,6 +31,7 @@ type builderDefaults struct {
 	accountStore    security.AccountStore
 	passwordEncoder PasswordEncoder
 	redisClient     redis.Client
+	mfaFactors      []MFAFactorVerifier
 }
 
 // AuthenticatorBuilder implements security.AuthenticatorBuilder
@@ -151,6 +152,7 @@ func (b *AuthenticatorBuilder) mfaOptions(f *PasswordAuthFeature) (Authenticator
 			return order.OrderedFirstCompare(f.mfaEventListeners[i], f.mfaEventListeners[j])
 		})
 		opts.MFAEventListeners = f.mfaEventListeners
+		opts.MFAFactorVerifiers = append(append([]MFAFactorVerifier{}, f.mfaFactors...), b.defaults.mfaFactors...)
 		opts.Checkers = decisionMakers
 		opts.PostProcessors = processors
 	}, nil
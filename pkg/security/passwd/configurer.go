@@ -31,13 +31,15 @@ type PasswordAuthConfigurer struct {
 	accountStore security.AccountStore
 	passwordEncoder PasswordEncoder
 	redisClient redis.Client
+	totpApp     *TOTPAppManager
 }
 
-func newPasswordAuthConfigurer(store security.AccountStore, encoder PasswordEncoder, redisClient redis.Client) *PasswordAuthConfigurer {
+func newPasswordAuthConfigurer(store security.AccountStore, encoder PasswordEncoder, redisClient redis.Client, totpApp *TOTPAppManager) *PasswordAuthConfigurer {
 	return &PasswordAuthConfigurer {
 		accountStore:    store,
 		passwordEncoder: encoder,
 		redisClient:     redisClient,
+		totpApp:         totpApp,
 	}
 }
 
@@ -55,6 +57,9 @@ func (pac *PasswordAuthConfigurer) Apply(feature security.Feature, ws security.W
 		passwordEncoder: pac.passwordEncoder,
 		redisClient: pac.redisClient,
 	}
+	if pac.totpApp != nil {
+		defaults.mfaFactors = []MFAFactorVerifier{pac.totpApp}
+	}
 	authenticator, err := NewAuthenticatorBuilder(f, defaults).Build(ctx)
 	if err != nil {
 		return err
@@ -29,6 +29,7 @@ type PasswordAuthFeature struct {
 	// MFA support
 	mfaEnabled        bool
 	mfaEventListeners []MFAEventListenerFunc
+	mfaFactors        []MFAFactorVerifier
 	otpTTL            time.Duration
 	otpVerifyLimit    uint
 	otpRefreshLimit   uint
@@ -74,6 +75,12 @@ func (f *PasswordAuthFeature) MFAEventListeners(handlers ...MFAEventListenerFunc
 	return f
 }
 
+// MFAFactors adds additional factors accepted during MFA verification, e.g. TOTPAppManager
+func (f *PasswordAuthFeature) MFAFactors(verifiers ...MFAFactorVerifier) *PasswordAuthFeature {
+	f.mfaFactors = append(f.mfaFactors, verifiers...)
+	return f
+}
+
 func (f *PasswordAuthFeature) OtpTTL(ttl time.Duration) *PasswordAuthFeature {
 	f.otpTTL = ttl
 	return f
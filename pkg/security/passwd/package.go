@@ -21,6 +21,7 @@ import (
 	"github.com/cisco-open/go-lanai/pkg/log"
 	"github.com/cisco-open/go-lanai/pkg/redis"
 	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/replay"
 	"go.uber.org/fx"
 )
 
@@ -30,6 +31,7 @@ var Module = &bootstrap.Module{
 	Name: "passwd authenticator",
 	Precedence: security.MinSecurityPrecedence + 30,
 	Options: []fx.Option{
+		fx.Provide(provideTOTPAppManager),
 		fx.Invoke(register),
 	},
 }
@@ -44,11 +46,39 @@ type initDI struct {
 	AccountStore    security.AccountStore `optional:"true"`
 	PasswordEncoder PasswordEncoder       `optional:"true"`
 	Redis           redis.Client          `optional:"true"`
+	TOTPApp         *TOTPAppManager       `optional:"true"`
 }
 
 func register(di initDI) {
 	if di.SecRegistrar != nil {
-		configurer := newPasswordAuthConfigurer(di.AccountStore, di.PasswordEncoder, di.Redis)
+		configurer := newPasswordAuthConfigurer(di.AccountStore, di.PasswordEncoder, di.Redis, di.TOTPApp)
 		di.SecRegistrar.(security.FeatureRegistrar).RegisterFeature(PasswordAuthenticatorFeatureId, configurer)
 	}
-}
\ No newline at end of file
+}
+
+type totpAppDI struct {
+	fx.In
+	AppCtx      *bootstrap.ApplicationContext
+	Store       TOTPEnrollmentStore `optional:"true"`
+	ReplayCache TOTPReplayCache     `optional:"true"`
+	Redis       redis.Client        `optional:"true"`
+}
+
+// provideTOTPAppManager provides nil manager if no persistent TOTPEnrollmentStore is available, i.e. authenticator app is disabled.
+// See totpstore package for implementations
+func provideTOTPAppManager(di totpAppDI) (*TOTPAppManager, error) {
+	if di.Store == nil {
+		logger.Infof("Authenticator app is disabled: TOTPEnrollmentStore is not provided")
+		return nil, nil
+	}
+	return NewTOTPAppManager(func(opt *TOTPAppOption) {
+		if name := di.AppCtx.Name(); name != "" {
+			opt.Issuer = name
+		}
+		opt.Store = di.Store
+		opt.ReplayCache = di.ReplayCache
+		if opt.ReplayCache == nil && di.Redis != nil {
+			opt.ReplayCache = replay.NewRedisCache(di.Redis, TOTPReplayCachePrefix)
+		}
+	})
+}
@@ -0,0 +1,415 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package passwd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/replay"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"image/png"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TOTPReplayCachePrefix is the key prefix of used time steps when replay.RedisCache is used
+	TOTPReplayCachePrefix = "TOTP-USED"
+)
+
+var (
+	ErrTOTPNotEnrolled     = errors.New("authenticator app is not enrolled")
+	ErrTOTPAlreadyEnrolled = errors.New("authenticator app is already enrolled")
+	ErrTOTPInvalidPasscode = errors.New("invalid authenticator app passcode")
+)
+
+// MFAFactorVerifier verifies passcode of additional MFA factors other than the out-of-band OTP generated by OTPManager,
+// e.g. authenticator app or recovery codes.
+// It returns true if the passcode is accepted. Returning false with nil error means the passcode is not applicable.
+type MFAFactorVerifier interface {
+	VerifyFactor(ctx context.Context, account security.Account, passcode string) (bool, error)
+}
+
+// TOTPEnrollment is the per-user authenticator app (RFC 6238) secret and hashed recovery codes
+type TOTPEnrollment struct {
+	Username      string
+	Secret        string
+	Confirmed     bool
+	CreatedAt     time.Time
+	RecoveryCodes []string
+}
+
+// TOTPEnrollmentStore persists TOTPEnrollment. LoadEnrollment should return ErrTOTPNotEnrolled if not found.
+// Enrollments must survive restarts and be shared by all instances, see totpstore package for implementations.
+type TOTPEnrollmentStore interface {
+	LoadEnrollment(ctx context.Context, username string) (*TOTPEnrollment, error)
+	SaveEnrollment(ctx context.Context, enrollment *TOTPEnrollment) error
+	DeleteEnrollment(ctx context.Context, username string) error
+	// ConsumeRecoveryCode atomically removes the recovery code hash of given user.
+	// It returns false if the code doesn't exist, e.g. it was already used by a concurrent request
+	ConsumeRecoveryCode(ctx context.Context, username string, codeHash string) (bool, error)
+}
+
+// TOTPReplayCache remembers used TOTP time steps, so the same passcode cannot be used twice.
+// It's a distinct type so that applications can provide it via DI without affecting other replay.Cache users
+type TOTPReplayCache interface {
+	replay.Cache
+}
+
+// TOTPProvisioning is the information for users to add the secret to authenticator apps
+type TOTPProvisioning struct {
+	Secret string
+	// URI is the "otpauth://" key URI
+	URI string
+	// QRCode is the URI encoded as PNG image in "data:" URL format
+	QRCode string
+}
+
+type TOTPAppOptions func(opt *TOTPAppOption)
+type TOTPAppOption struct {
+	Issuer            string
+	Store             TOTPEnrollmentStore
+	ReplayCache       TOTPReplayCache
+	Period            time.Duration
+	Skew              uint
+	Digits            otp.Digits
+	SecretSize        uint
+	RecoveryCodeCount int
+}
+
+// TOTPAppManager manages authenticator app enrollment and verifies its passcodes and recovery codes.
+// TOTPAppManager implements MFAFactorVerifier
+type TOTPAppManager struct {
+	issuer            string
+	store             TOTPEnrollmentStore
+	replayCache       TOTPReplayCache
+	period            uint
+	skew              uint
+	digits            otp.Digits
+	secretSize        uint
+	recoveryCodeCount int
+}
+
+// NewTOTPAppManager creates TOTPAppManager. TOTPAppOption.Store is required
+func NewTOTPAppManager(opts ...TOTPAppOptions) (*TOTPAppManager, error) {
+	opt := TOTPAppOption{
+		Issuer:            "go-lanai",
+		Period:            30 * time.Second,
+		Skew:              1,
+		Digits:            otp.DigitsSix,
+		SecretSize:        20,
+		RecoveryCodeCount: 10,
+	}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	if opt.Store == nil {
+		return nil, errors.New("TOTPEnrollmentStore is required for authenticator app")
+	}
+	if opt.ReplayCache == nil {
+		opt.ReplayCache = replay.NewInMemoryCache()
+	}
+	return &TOTPAppManager{
+		issuer:            opt.Issuer,
+		store:             opt.Store,
+		replayCache:       opt.ReplayCache,
+		period:            uint(opt.Period.Round(time.Second).Seconds()),
+		skew:              opt.Skew,
+		digits:            opt.Digits,
+		secretSize:        opt.SecretSize,
+		recoveryCodeCount: opt.RecoveryCodeCount,
+	}, nil
+}
+
+// BeginEnrollment generates a secret for the account. The enrollment is pending until ConfirmEnrollment.
+// Secret of existing pending enrollment is reused, so reloading the provisioning page doesn't invalidate scanned QR code.
+// Confirmed enrollment need to be removed via Unenroll or Disable before enrolling again
+func (m *TOTPAppManager) BeginEnrollment(ctx context.Context, account security.Account) (*TOTPProvisioning, error) {
+	opts := totp.GenerateOpts{
+		Issuer:      m.issuer,
+		AccountName: account.Username(),
+		Period:      m.period,
+		SecretSize:  m.secretSize,
+		Digits:      m.digits,
+		Algorithm:   otp.AlgorithmSHA1,
+	}
+	switch existing, e := m.store.LoadEnrollment(ctx, account.Username()); {
+	case e != nil && !errors.Is(e, ErrTOTPNotEnrolled):
+		return nil, e
+	case e == nil && existing.Confirmed:
+		return nil, ErrTOTPAlreadyEnrolled
+	case e == nil:
+		if opts.Secret, e = b32NoPadding.DecodeString(existing.Secret); e != nil {
+			return nil, e
+		}
+	}
+
+	key, e := totp.Generate(opts)
+	if e != nil {
+		return nil, e
+	}
+	qr, e := m.qrCode(key)
+	if e != nil {
+		return nil, e
+	}
+
+	if opts.Secret == nil {
+		enrollment := &TOTPEnrollment{
+			Username:  account.Username(),
+			Secret:    key.Secret(),
+			CreatedAt: time.Now().UTC(),
+		}
+		if e := m.store.SaveEnrollment(ctx, enrollment); e != nil {
+			return nil, e
+		}
+	}
+	return &TOTPProvisioning{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: qr,
+	}, nil
+}
+
+// ConfirmEnrollment activates pending enrollment with a passcode generated by the authenticator app.
+// Returns plain text recovery codes, which are only available at this moment
+func (m *TOTPAppManager) ConfirmEnrollment(ctx context.Context, account security.Account, passcode string) ([]string, error) {
+	enrollment, e := m.store.LoadEnrollment(ctx, account.Username())
+	switch {
+	case e != nil:
+		return nil, e
+	case enrollment.Confirmed:
+		return nil, ErrTOTPAlreadyEnrolled
+	}
+
+	if ok, e := m.verifyPasscode(ctx, enrollment, passcode); e != nil {
+		return nil, e
+	} else if !ok {
+		return nil, ErrTOTPInvalidPasscode
+	}
+
+	codes, hashes, e := m.generateRecoveryCodes()
+	if e != nil {
+		return nil, e
+	}
+	enrollment.Confirmed = true
+	enrollment.RecoveryCodes = hashes
+	if e := m.store.SaveEnrollment(ctx, enrollment); e != nil {
+		return nil, e
+	}
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes of confirmed enrollment
+func (m *TOTPAppManager) RegenerateRecoveryCodes(ctx context.Context, account security.Account) ([]string, error) {
+	enrollment, e := m.loadConfirmed(ctx, account.Username())
+	if e != nil {
+		return nil, e
+	}
+	codes, hashes, e := m.generateRecoveryCodes()
+	if e != nil {
+		return nil, e
+	}
+	enrollment.RecoveryCodes = hashes
+	if e := m.store.SaveEnrollment(ctx, enrollment); e != nil {
+		return nil, e
+	}
+	return codes, nil
+}
+
+// Unenroll removes enrollment without verification, e.g. for administrative reset
+func (m *TOTPAppManager) Unenroll(ctx context.Context, account security.Account) error {
+	return m.store.DeleteEnrollment(ctx, account.Username())
+}
+
+// Disable removes confirmed enrollment on behalf of the user.
+// The passcode of the authenticator app or an unused recovery code is required
+func (m *TOTPAppManager) Disable(ctx context.Context, account security.Account, passcode string) error {
+	if _, e := m.loadConfirmed(ctx, account.Username()); e != nil {
+		return e
+	}
+	switch ok, e := m.VerifyFactor(ctx, account, passcode); {
+	case e != nil:
+		return e
+	case !ok:
+		return ErrTOTPInvalidPasscode
+	}
+	return m.store.DeleteEnrollment(ctx, account.Username())
+}
+
+func (m *TOTPAppManager) IsEnrolled(ctx context.Context, username string) bool {
+	_, e := m.loadConfirmed(ctx, username)
+	return e == nil
+}
+
+// VerifyFactor implements MFAFactorVerifier. The passcode is accepted if it's either a valid authenticator app passcode
+// or an unused recovery code. Used recovery code is consumed atomically, so it cannot be used by concurrent requests.
+func (m *TOTPAppManager) VerifyFactor(ctx context.Context, account security.Account, passcode string) (bool, error) {
+	enrollment, e := m.loadConfirmed(ctx, account.Username())
+	if e != nil {
+		return false, nil
+	}
+
+	if ok, e := m.verifyPasscode(ctx, enrollment, passcode); e != nil || ok {
+		return ok, e
+	}
+	return m.store.ConsumeRecoveryCode(ctx, enrollment.Username, hashRecoveryCode(passcode))
+}
+
+func (m *TOTPAppManager) loadConfirmed(ctx context.Context, username string) (*TOTPEnrollment, error) {
+	enrollment, e := m.store.LoadEnrollment(ctx, username)
+	switch {
+	case e != nil:
+		return nil, e
+	case !enrollment.Confirmed:
+		return nil, ErrTOTPNotEnrolled
+	}
+	return enrollment, nil
+}
+
+// verifyPasscode checks passcode against time steps within the drift window, and rejects time steps already used
+func (m *TOTPAppManager) verifyPasscode(ctx context.Context, enrollment *TOTPEnrollment, passcode string) (bool, error) {
+	passcode = strings.TrimSpace(passcode)
+	if len(passcode) != m.digits.Length() {
+		return false, nil
+	}
+	now := time.Now()
+	step := int64(m.period)
+	for i := 0; i <= int(m.skew)*2; i++ {
+		// 0, -1, +1, -2, +2 ...
+		offset := int64((i + 1) / 2)
+		if i%2 == 1 {
+			offset = -offset
+		}
+		t := now.Add(time.Duration(offset*step) * time.Second)
+		expected, e := totp.GenerateCodeCustom(enrollment.Secret, t, totp.ValidateOpts{
+			Period:    m.period,
+			Digits:    m.digits,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if e != nil {
+			return false, e
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(passcode)) != 1 {
+			continue
+		}
+		counter := t.Unix() / step
+		ttl := time.Duration(step*int64(2*m.skew+1)) * time.Second
+		return m.replayCache.Add(ctx, fmt.Sprintf("%s:%d", enrollment.Username, counter), ttl)
+	}
+	return false, nil
+}
+
+func (m *TOTPAppManager) generateRecoveryCodes() (codes []string, hashes []string, err error) {
+	codes = make([]string, m.recoveryCodeCount)
+	hashes = make([]string, m.recoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, 5)
+		if _, err = rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		v := strings.ToLower(b32NoPadding.EncodeToString(raw))
+		codes[i] = v[:4] + "-" + v[4:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return
+}
+
+func (m *TOTPAppManager) qrCode(key *otp.Key) (string, error) {
+	img, e := key.Image(256, 256)
+	if e != nil {
+		return "", e
+	}
+	var buf bytes.Buffer
+	if e := png.Encode(&buf, img); e != nil {
+		return "", e
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
+
+/*****************************
+	Common Implements
+ *****************************/
+
+// InMemoryTOTPEnrollmentStore implements TOTPEnrollmentStore.
+// Enrollments are lost on restart and not shared between instances, so it's for testing only
+type InMemoryTOTPEnrollmentStore struct {
+	mtx         sync.Mutex
+	enrollments map[string]*TOTPEnrollment
+}
+
+func NewInMemoryTOTPEnrollmentStore() *InMemoryTOTPEnrollmentStore {
+	return &InMemoryTOTPEnrollmentStore{enrollments: map[string]*TOTPEnrollment{}}
+}
+
+func (s *InMemoryTOTPEnrollmentStore) LoadEnrollment(_ context.Context, username string) (*TOTPEnrollment, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if v, ok := s.enrollments[username]; ok {
+		cp := *v
+		cp.RecoveryCodes = append([]string{}, v.RecoveryCodes...)
+		return &cp, nil
+	}
+	return nil, ErrTOTPNotEnrolled
+}
+
+func (s *InMemoryTOTPEnrollmentStore) SaveEnrollment(_ context.Context, enrollment *TOTPEnrollment) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	cp := *enrollment
+	cp.RecoveryCodes = append([]string{}, enrollment.RecoveryCodes...)
+	s.enrollments[enrollment.Username] = &cp
+	return nil
+}
+
+func (s *InMemoryTOTPEnrollmentStore) DeleteEnrollment(_ context.Context, username string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.enrollments, username)
+	return nil
+}
+
+func (s *InMemoryTOTPEnrollmentStore) ConsumeRecoveryCode(_ context.Context, username string, codeHash string) (bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	v, ok := s.enrollments[username]
+	if !ok {
+		return false, nil
+	}
+	for i, hash := range v.RecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(codeHash)) == 1 {
+			v.RecoveryCodes = append(v.RecoveryCodes[:i:i], v.RecoveryCodes[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
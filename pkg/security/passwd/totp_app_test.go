@@ -0,0 +1,245 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package passwd_test
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/passwd"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/sectest"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"github.com/pquerna/otp/totp"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/*************************
+	Test
+ *************************/
+
+func TestTOTPApp(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestTOTPEnrollment(), "Enrollment"),
+		test.GomegaSubTest(SubTestTOTPReplay(), "Replay"),
+		test.GomegaSubTest(SubTestTOTPRecoveryCode(), "RecoveryCode"),
+		test.GomegaSubTest(SubTestTOTPConcurrentRecoveryCode(), "ConcurrentRecoveryCode"),
+		test.GomegaSubTest(SubTestTOTPUnenroll(), "Unenroll"),
+		test.GomegaSubTest(SubTestTOTPDisable(), "Disable"),
+		test.GomegaSubTest(SubTestTOTPStoreRequired(), "StoreRequired"),
+		test.GomegaSubTest(SubTestMFAWithTOTPApp(), "MFAWithTOTPApp"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestTOTPEnrollment() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		manager := NewTestTOTPAppManager(g)
+		acct := NewAccount(TestUser, TestUserPassword)
+		p1, e := manager.BeginEnrollment(ctx, acct)
+		g.Expect(e).To(Succeed(), "begin enrollment should not fail")
+		g.Expect(p1.URI).To(HavePrefix("otpauth://totp/"), "provisioning URI should be correct")
+		g.Expect(p1.QRCode).To(HavePrefix("data:image/png;base64,"), "QR code should be correct")
+		g.Expect(manager.IsEnrolled(ctx, TestUser)).To(BeFalse(), "pending enrollment should not be enrolled")
+
+		p2, e := manager.BeginEnrollment(ctx, acct)
+		g.Expect(e).To(Succeed(), "begin enrollment again should not fail")
+		g.Expect(p2.Secret).To(Equal(p1.Secret), "pending secret should be reused")
+
+		_, e = manager.ConfirmEnrollment(ctx, acct, "000000x")
+		g.Expect(e).To(HaveOccurred(), "confirm with wrong passcode should fail")
+
+		codes, e := manager.ConfirmEnrollment(ctx, acct, MustTOTPCode(g, p1.Secret, time.Now()))
+		g.Expect(e).To(Succeed(), "confirm enrollment should not fail")
+		g.Expect(codes).To(HaveLen(10), "recovery codes should be returned")
+		g.Expect(manager.IsEnrolled(ctx, TestUser)).To(BeTrue(), "confirmed enrollment should be enrolled")
+
+		_, e = manager.BeginEnrollment(ctx, acct)
+		g.Expect(e).To(MatchError(passwd.ErrTOTPAlreadyEnrolled), "begin enrollment should fail when already enrolled")
+	}
+}
+
+func SubTestTOTPReplay() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		manager := NewTestTOTPAppManager(g)
+		acct := NewAccount(TestUser, TestUserPassword)
+		secret, _ := MustEnrollTOTP(ctx, g, manager, acct)
+
+		// previous step is within the drift window and not yet used
+		ok, e := manager.VerifyFactor(ctx, acct, MustTOTPCode(g, secret, time.Now().Add(-30*time.Second)))
+		g.Expect(e).To(Succeed(), "verifying drifted passcode should not fail")
+		g.Expect(ok).To(BeTrue(), "drifted passcode within skew should be accepted")
+
+		ok, e = manager.VerifyFactor(ctx, acct, MustTOTPCode(g, secret, time.Now().Add(-30*time.Second)))
+		g.Expect(e).To(Succeed(), "verifying replayed passcode should not fail")
+		g.Expect(ok).To(BeFalse(), "replayed passcode should not be accepted")
+
+		ok, _ = manager.VerifyFactor(ctx, acct, MustTOTPCode(g, secret, time.Now().Add(-5*time.Minute)))
+		g.Expect(ok).To(BeFalse(), "passcode outside of skew should not be accepted")
+	}
+}
+
+func SubTestTOTPRecoveryCode() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		manager := NewTestTOTPAppManager(g)
+		acct := NewAccount(TestUser, TestUserPassword)
+		_, codes := MustEnrollTOTP(ctx, g, manager, acct)
+
+		ok, e := manager.VerifyFactor(ctx, acct, codes[0])
+		g.Expect(e).To(Succeed(), "verifying recovery code should not fail")
+		g.Expect(ok).To(BeTrue(), "recovery code should be accepted")
+
+		ok, _ = manager.VerifyFactor(ctx, acct, codes[0])
+		g.Expect(ok).To(BeFalse(), "recovery code should not be accepted twice")
+
+		regenerated, e := manager.RegenerateRecoveryCodes(ctx, acct)
+		g.Expect(e).To(Succeed(), "regenerating recovery codes should not fail")
+		ok, _ = manager.VerifyFactor(ctx, acct, codes[1])
+		g.Expect(ok).To(BeFalse(), "old recovery code should not be accepted after regeneration")
+		ok, _ = manager.VerifyFactor(ctx, acct, regenerated[1])
+		g.Expect(ok).To(BeTrue(), "new recovery code should be accepted")
+	}
+}
+
+func SubTestTOTPConcurrentRecoveryCode() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		manager := NewTestTOTPAppManager(g)
+		acct := NewAccount(TestUser, TestUserPassword)
+		_, codes := MustEnrollTOTP(ctx, g, manager, acct)
+
+		var accepted int32
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if ok, e := manager.VerifyFactor(ctx, acct, codes[0]); e == nil && ok {
+					atomic.AddInt32(&accepted, 1)
+				}
+			}()
+		}
+		wg.Wait()
+		g.Expect(atomic.LoadInt32(&accepted)).To(BeEquivalentTo(1), "recovery code should be accepted only once by concurrent requests")
+	}
+}
+
+func SubTestTOTPUnenroll() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		manager := NewTestTOTPAppManager(g)
+		acct := NewAccount(TestUser, TestUserPassword)
+		secret, _ := MustEnrollTOTP(ctx, g, manager, acct)
+		g.Expect(manager.Unenroll(ctx, acct)).To(Succeed(), "unenroll should not fail")
+		g.Expect(manager.IsEnrolled(ctx, TestUser)).To(BeFalse(), "unenrolled account should not be enrolled")
+		ok, e := manager.VerifyFactor(ctx, acct, MustTOTPCode(g, secret, time.Now()))
+		g.Expect(e).To(Succeed(), "verifying factor of unenrolled account should not fail")
+		g.Expect(ok).To(BeFalse(), "verifying factor of unenrolled account should not be accepted")
+	}
+}
+
+func SubTestTOTPDisable() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		manager := NewTestTOTPAppManager(g)
+		acct := NewAccount(TestUser, TestUserPassword)
+		secret, _ := MustEnrollTOTP(ctx, g, manager, acct)
+
+		e := manager.Disable(ctx, acct, "")
+		g.Expect(e).To(MatchError(passwd.ErrTOTPInvalidPasscode), "disable without passcode should fail")
+		e = manager.Disable(ctx, acct, "000000")
+		g.Expect(e).To(MatchError(passwd.ErrTOTPInvalidPasscode), "disable with wrong passcode should fail")
+		g.Expect(manager.IsEnrolled(ctx, TestUser)).To(BeTrue(), "failed disable should keep enrollment")
+
+		g.Expect(manager.Disable(ctx, acct, MustTOTPCode(g, secret, time.Now()))).To(Succeed(), "disable with passcode should not fail")
+		g.Expect(manager.IsEnrolled(ctx, TestUser)).To(BeFalse(), "disabled account should not be enrolled")
+
+		// recovery code is also accepted
+		another := NewTestTOTPAppManager(g)
+		_, codes := MustEnrollTOTP(ctx, g, another, acct)
+		g.Expect(another.Disable(ctx, acct, codes[0])).To(Succeed(), "disable with recovery code should not fail")
+		g.Expect(another.IsEnrolled(ctx, TestUser)).To(BeFalse(), "disabled account should not be enrolled")
+	}
+}
+
+func SubTestTOTPStoreRequired() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		_, e := passwd.NewTOTPAppManager()
+		g.Expect(e).To(HaveOccurred(), "manager without store should not be created")
+	}
+}
+
+func SubTestMFAWithTOTPApp() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		manager := NewTestTOTPAppManager(g)
+		acct := NewAccount(TestUser, TestUserPassword)
+		secret, _ := MustEnrollTOTP(ctx, g, manager, acct)
+
+		store := sectest.NewMockedAccountStore([]*sectest.MockedAccountProperties{
+			{UserId: acct.ID().(string), Username: acct.Username()},
+		}, func(security.Account) security.Account { return acct })
+		authn, e := passwd.NewAuthenticatorBuilder(passwd.New().
+			AccountStore(store).MFA(true).MFAFactors(manager),
+		).Build(ctx)
+		g.Expect(e).To(Succeed(), "building authenticator should not fail")
+
+		pending, e := authn.Authenticate(ctx, &passwd.UsernamePasswordPair{
+			Username:   TestUser,
+			Password:   TestUserPassword,
+			EnforceMFA: passwd.MFAModeMust,
+		})
+		g.Expect(e).To(Succeed(), "password step should not fail")
+		g.Expect(pending.State()).To(Equal(security.StatePrincipalKnown), "password step should require MFA")
+
+		auth, e := authn.Authenticate(ctx, &passwd.MFAOtpVerification{
+			CurrentAuth: pending.(passwd.UsernamePasswordAuthentication),
+			OTP:         MustTOTPCode(g, secret, time.Now().Add(-30*time.Second)),
+		})
+		g.Expect(e).To(Succeed(), "MFA with authenticator app passcode should not fail")
+		g.Expect(auth.State()).To(Equal(security.StateAuthenticated), "MFA with authenticator app should be authenticated")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func NewTestTOTPAppManager(g *gomega.WithT) *passwd.TOTPAppManager {
+	manager, e := passwd.NewTOTPAppManager(func(opt *passwd.TOTPAppOption) {
+		opt.Store = passwd.NewInMemoryTOTPEnrollmentStore()
+	})
+	g.Expect(e).To(Succeed(), "creating manager should not fail")
+	return manager
+}
+
+func MustEnrollTOTP(ctx context.Context, g *gomega.WithT, manager *passwd.TOTPAppManager, acct security.Account) (string, []string) {
+	p, e := manager.BeginEnrollment(ctx, acct)
+	g.Expect(e).To(Succeed(), "begin enrollment should not fail")
+	// confirm with next time step, so subsequent verification of current or previous step is not a replay
+	codes, e := manager.ConfirmEnrollment(ctx, acct, MustTOTPCode(g, p.Secret, time.Now().Add(30*time.Second)))
+	g.Expect(e).To(Succeed(), "confirm enrollment should not fail")
+	return p.Secret, codes
+}
+
+func MustTOTPCode(g *gomega.WithT, secret string, t time.Time) string {
+	code, e := totp.GenerateCode(secret, t)
+	g.Expect(e).To(Succeed(), "generating TOTP code should not fail")
+	return code
+}
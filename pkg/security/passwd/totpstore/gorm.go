@@ -0,0 +1,160 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package totpstore
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/data"
+	"github.com/cisco-open/go-lanai/pkg/data/repo"
+	"github.com/cisco-open/go-lanai/pkg/security/passwd"
+	"time"
+)
+
+// TOTPEnrollmentModel is the persisted model of passwd.TOTPEnrollment, without recovery codes
+type TOTPEnrollmentModel struct {
+	Username  string    `gorm:"primaryKey;type:varchar(255)"`
+	Secret    string    `gorm:"type:varchar(128);not null"`
+	Confirmed bool      `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (TOTPEnrollmentModel) TableName() string {
+	return "security_totp_enrollments"
+}
+
+// TOTPRecoveryCodeModel is a hashed recovery code. Each code is a row, so it can be consumed with a single DELETE
+type TOTPRecoveryCodeModel struct {
+	Username string `gorm:"primaryKey;type:varchar(255)"`
+	CodeHash string `gorm:"primaryKey;type:varchar(64)"`
+}
+
+func (TOTPRecoveryCodeModel) TableName() string {
+	return "security_totp_recovery_codes"
+}
+
+// GormStore implements passwd.TOTPEnrollmentStore using repo.CrudRepository.
+// Note: TOTP secrets are stored as-is. Use column encryption or database-level encryption if necessary.
+type GormStore struct {
+	enrollments repo.CrudRepository
+	codes       repo.CrudRepository
+}
+
+func NewGormStore(factory repo.Factory) *GormStore {
+	return &GormStore{
+		enrollments: factory.NewCRUD(&TOTPEnrollmentModel{}),
+		codes:       factory.NewCRUD(&TOTPRecoveryCodeModel{}),
+	}
+}
+
+func (s *GormStore) LoadEnrollment(ctx context.Context, username string) (*passwd.TOTPEnrollment, error) {
+	var model TOTPEnrollmentModel
+	switch e := s.enrollments.FindOneBy(ctx, &model, map[string]interface{}{"username": username}); {
+	case errors.Is(e, data.ErrorRecordNotFound):
+		return nil, passwd.ErrTOTPNotEnrolled
+	case e != nil:
+		return nil, e
+	}
+	hashes, e := s.loadCodeHashes(ctx, username)
+	if e != nil {
+		return nil, e
+	}
+	return &passwd.TOTPEnrollment{
+		Username:      model.Username,
+		Secret:        model.Secret,
+		Confirmed:     model.Confirmed,
+		CreatedAt:     model.CreatedAt,
+		RecoveryCodes: hashes,
+	}, nil
+}
+
+// SaveEnrollment upserts the enrollment and replaces its recovery codes.
+// Removed codes are deleted before new codes are added, so replaced codes are never valid after a partial failure.
+func (s *GormStore) SaveEnrollment(ctx context.Context, enrollment *passwd.TOTPEnrollment) error {
+	model := TOTPEnrollmentModel{
+		Username:  enrollment.Username,
+		Secret:    enrollment.Secret,
+		Confirmed: enrollment.Confirmed,
+		CreatedAt: enrollment.CreatedAt,
+	}
+	if e := s.enrollments.Save(ctx, &model); e != nil {
+		return e
+	}
+
+	existing, e := s.loadCodeHashes(ctx, enrollment.Username)
+	if e != nil {
+		return e
+	}
+	wanted := make(map[string]struct{}, len(enrollment.RecoveryCodes))
+	for _, hash := range enrollment.RecoveryCodes {
+		wanted[hash] = struct{}{}
+	}
+	for _, hash := range existing {
+		if _, ok := wanted[hash]; ok {
+			delete(wanted, hash)
+			continue
+		}
+		if e := s.codes.DeleteBy(ctx, codeCondition(enrollment.Username, hash)); e != nil {
+			return e
+		}
+	}
+	for _, hash := range enrollment.RecoveryCodes {
+		if _, ok := wanted[hash]; !ok {
+			continue
+		}
+		code := TOTPRecoveryCodeModel{Username: enrollment.Username, CodeHash: hash}
+		if e := s.codes.Create(ctx, &code); e != nil && !errors.Is(e, data.ErrorDuplicateKey) {
+			return e
+		}
+	}
+	return nil
+}
+
+func (s *GormStore) DeleteEnrollment(ctx context.Context, username string) error {
+	condition := map[string]interface{}{"username": username}
+	if e := s.codes.DeleteBy(ctx, condition); e != nil {
+		return e
+	}
+	return s.enrollments.DeleteBy(ctx, condition)
+}
+
+// ConsumeRecoveryCode deletes the code row. Only one of concurrent requests would see the row affected.
+func (s *GormStore) ConsumeRecoveryCode(ctx context.Context, username string, codeHash string) (bool, error) {
+	switch e := s.codes.DeleteBy(ctx, codeCondition(username, codeHash), repo.ErrorOnZeroRows()); {
+	case errors.Is(e, data.ErrorRecordNotFound):
+		return false, nil
+	case e != nil:
+		return false, e
+	}
+	return true, nil
+}
+
+func (s *GormStore) loadCodeHashes(ctx context.Context, username string) ([]string, error) {
+	var models []*TOTPRecoveryCodeModel
+	if e := s.codes.FindAllBy(ctx, &models, map[string]interface{}{"username": username}); e != nil {
+		return nil, e
+	}
+	hashes := make([]string, len(models))
+	for i, m := range models {
+		hashes[i] = m.CodeHash
+	}
+	return hashes, nil
+}
+
+func codeCondition(username, codeHash string) map[string]interface{} {
+	return map[string]interface{}{"username": username, "code_hash": codeHash}
+}
@@ -0,0 +1,172 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package totpstore
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/data/repo"
+	"github.com/cisco-open/go-lanai/pkg/security/passwd"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/apptest"
+	"github.com/cisco-open/go-lanai/test/dbtest"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"go.uber.org/fx"
+	"testing"
+	"time"
+)
+
+/*************************
+	Test Setup
+ *************************/
+
+//func TestMain(m *testing.M) {
+//	suitetest.RunTests(m,
+//		dbtest.EnableDBRecordMode(),
+//	)
+//}
+
+const (
+	TestUsername = "test-user"
+	TestSecret   = "JBSWY3DPEHPK3PXP"
+)
+
+type gormDI struct {
+	fx.In
+	dbtest.DI
+	Factory repo.Factory
+}
+
+func SetupGormTestPrepareTables(di *gormDI) test.SetupFunc {
+	return dbtest.PrepareData(&di.DI,
+		dbtest.SetupUsingSQLFile(MigrationFS, "migrations/create_totp_enrollments.sql"),
+		dbtest.SetupTruncateTables(TOTPEnrollmentModel{}.TableName(), TOTPRecoveryCodeModel{}.TableName()),
+	)
+}
+
+func newTestEnrollment(codes ...string) *passwd.TOTPEnrollment {
+	return &passwd.TOTPEnrollment{
+		Username:      TestUsername,
+		Secret:        TestSecret,
+		Confirmed:     true,
+		CreatedAt:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		RecoveryCodes: codes,
+	}
+}
+
+/*************************
+	Test Cases
+ *************************/
+
+func TestGormStore(t *testing.T) {
+	di := &gormDI{}
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		dbtest.WithDBPlayback("testdb"),
+		apptest.WithModules(repo.Module),
+		apptest.WithDI(di),
+		test.SubTestSetup(SetupGormTestPrepareTables(di)),
+		test.GomegaSubTest(SubTestGormSaveAndLoad(di), "SaveAndLoad"),
+		test.GomegaSubTest(SubTestGormReplaceCodes(di), "ReplaceCodes"),
+		test.GomegaSubTest(SubTestGormConsumeRecoveryCode(di), "ConsumeRecoveryCode"),
+		test.GomegaSubTest(SubTestGormDelete(di), "Delete"),
+		test.GomegaSubTest(SubTestGormDBError(di), "DBError"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestGormSaveAndLoad(di *gormDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		store := NewGormStore(di.Factory)
+		_, e := store.LoadEnrollment(ctx, TestUsername)
+		g.Expect(e).To(Equal(passwd.ErrTOTPNotEnrolled), "missing enrollment should be translated")
+
+		enrollment := newTestEnrollment("hash-1", "hash-2")
+		g.Expect(store.SaveEnrollment(ctx, enrollment)).To(Succeed())
+		loaded, e := store.LoadEnrollment(ctx, TestUsername)
+		g.Expect(e).To(Succeed())
+		g.Expect(loaded.Secret).To(Equal(TestSecret))
+		g.Expect(loaded.Confirmed).To(BeTrue())
+		g.Expect(loaded.CreatedAt).To(Equal(enrollment.CreatedAt))
+		g.Expect(loaded.RecoveryCodes).To(ConsistOf("hash-1", "hash-2"))
+	}
+}
+
+func SubTestGormReplaceCodes(di *gormDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		store := NewGormStore(di.Factory)
+		g.Expect(store.SaveEnrollment(ctx, newTestEnrollment("hash-1", "hash-2"))).To(Succeed())
+		g.Expect(store.SaveEnrollment(ctx, newTestEnrollment("hash-2", "hash-3"))).To(Succeed())
+		loaded, e := store.LoadEnrollment(ctx, TestUsername)
+		g.Expect(e).To(Succeed())
+		g.Expect(loaded.RecoveryCodes).To(ConsistOf("hash-2", "hash-3"), "old codes should be replaced")
+		ok, e := store.ConsumeRecoveryCode(ctx, TestUsername, "hash-1")
+		g.Expect(e).To(Succeed())
+		g.Expect(ok).To(BeFalse(), "replaced code should not be valid")
+	}
+}
+
+func SubTestGormConsumeRecoveryCode(di *gormDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		store := NewGormStore(di.Factory)
+		g.Expect(store.SaveEnrollment(ctx, newTestEnrollment("hash-1", "hash-2"))).To(Succeed())
+
+		ok, e := store.ConsumeRecoveryCode(ctx, TestUsername, "hash-1")
+		g.Expect(e).To(Succeed())
+		g.Expect(ok).To(BeTrue(), "code should be consumed")
+		ok, e = store.ConsumeRecoveryCode(ctx, TestUsername, "hash-1")
+		g.Expect(e).To(Succeed())
+		g.Expect(ok).To(BeFalse(), "code should be consumed only once")
+
+		ok, e = store.ConsumeRecoveryCode(ctx, TestUsername, "unknown")
+		g.Expect(e).To(Succeed())
+		g.Expect(ok).To(BeFalse(), "unknown code should not be consumed")
+		loaded, e := store.LoadEnrollment(ctx, TestUsername)
+		g.Expect(e).To(Succeed())
+		g.Expect(loaded.RecoveryCodes).To(ConsistOf("hash-2"))
+	}
+}
+
+func SubTestGormDelete(di *gormDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		store := NewGormStore(di.Factory)
+		g.Expect(store.SaveEnrollment(ctx, newTestEnrollment("hash-1"))).To(Succeed())
+		g.Expect(store.DeleteEnrollment(ctx, TestUsername)).To(Succeed())
+		_, e := store.LoadEnrollment(ctx, TestUsername)
+		g.Expect(e).To(Equal(passwd.ErrTOTPNotEnrolled))
+		ok, e := store.ConsumeRecoveryCode(ctx, TestUsername, "hash-1")
+		g.Expect(e).To(Succeed())
+		g.Expect(ok).To(BeFalse(), "recovery codes should be deleted")
+	}
+}
+
+func SubTestGormDBError(di *gormDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		store := NewGormStore(di.Factory)
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, e := store.LoadEnrollment(ctx, TestUsername)
+		g.Expect(e).To(HaveOccurred())
+		g.Expect(errors.Is(e, passwd.ErrTOTPNotEnrolled)).To(BeFalse(), "DB errors should not be reported as not enrolled")
+		_, e = store.ConsumeRecoveryCode(ctx, TestUsername, "hash-1")
+		g.Expect(e).To(HaveOccurred(), "DB errors should not be reported as unknown code")
+	}
+}
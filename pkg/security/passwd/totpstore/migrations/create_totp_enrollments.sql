@@ -0,0 +1,17 @@
+-- Table security_totp_enrollments for authenticator app enrollments
+CREATE TABLE IF NOT EXISTS security_totp_enrollments
+(
+    username   varchar(255) NOT NULL,
+    secret     varchar(128) NOT NULL,
+    confirmed  boolean      NOT NULL DEFAULT false,
+    created_at timestamptz  NOT NULL,
+    CONSTRAINT "primary" PRIMARY KEY (username)
+);
+
+-- Table security_totp_recovery_codes for hashed recovery codes, one row per code
+CREATE TABLE IF NOT EXISTS security_totp_recovery_codes
+(
+    username  varchar(255) NOT NULL,
+    code_hash varchar(64)  NOT NULL,
+    CONSTRAINT "primary" PRIMARY KEY (username, code_hash)
+);
@@ -0,0 +1,43 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package totpstore provides persistent passwd.TOTPEnrollmentStore implementations.
+// Authenticator app is only enabled when a passwd.TOTPEnrollmentStore is available via DI, e.g. with Use()
+package totpstore
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/data/repo"
+	"github.com/cisco-open/go-lanai/pkg/security/passwd"
+	"go.uber.org/fx"
+)
+
+var Module = &bootstrap.Module{
+	Name:       "totp enrollment store",
+	Precedence: bootstrap.SecurityPrecedence,
+	Options: []fx.Option{
+		fx.Provide(provideGormStore),
+	},
+}
+
+// Use provides GormStore as passwd.TOTPEnrollmentStore. Tables can be created with Migration
+func Use() {
+	bootstrap.Register(Module)
+}
+
+func provideGormStore(factory repo.Factory) passwd.TOTPEnrollmentStore {
+	return NewGormStore(factory)
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package replay keeps track of one-time values that were already used, e.g. "jti" of client assertions and DPoP proofs
+// or time steps of TOTP passcodes, so that they are rejected when presented again.
+package replay
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/redis"
+	"sync"
+	"time"
+)
+
+const (
+	// sweepInterval is the minimum interval between removing expired keys from InMemoryCache
+	sweepInterval = time.Minute
+)
+
+// Cache keeps track of used keys
+type Cache interface {
+	// Add records the key for given duration. It returns false if the key was already recorded and not expired.
+	Add(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// RedisCache implements Cache using Redis "SET NX", so used keys are shared among all instances
+type RedisCache struct {
+	client redis.Client
+	prefix string
+}
+
+// NewRedisCache creates RedisCache. Keys are stored as "<prefix>:<key>", so features sharing the same DB don't collide
+func NewRedisCache(client redis.Client, prefix string) *RedisCache {
+	return &RedisCache{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (c *RedisCache) Add(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, fmt.Sprintf("%s:%s", c.prefix, key), time.Now().Unix(), ttl).Result()
+}
+
+// InMemoryCache implements Cache in memory. It's only suitable for single instance deployment and tests.
+// Expired keys are removed at most once per minute, so adding a key doesn't scan all recorded keys
+type InMemoryCache struct {
+	mtx       sync.Mutex
+	used      map[string]time.Time
+	nextSweep time.Time
+}
+
+func NewInMemoryCache() *InMemoryCache {
+	return &InMemoryCache{
+		used:      map[string]time.Time{},
+		nextSweep: time.Now().Add(sweepInterval),
+	}
+}
+
+func (c *InMemoryCache) Add(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	now := time.Now()
+	if !now.Before(c.nextSweep) {
+		c.sweep(now)
+	}
+	if exp, ok := c.used[key]; ok && now.Before(exp) {
+		return false, nil
+	}
+	c.used[key] = now.Add(ttl)
+	return true, nil
+}
+
+// sweep removes expired keys. Caller should hold the lock
+func (c *InMemoryCache) sweep(now time.Time) {
+	for k, exp := range c.used {
+		if !now.Before(exp) {
+			delete(c.used, k)
+		}
+	}
+	c.nextSweep = now.Add(sweepInterval)
+}
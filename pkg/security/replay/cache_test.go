@@ -0,0 +1,133 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/redis"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/apptest"
+	"github.com/cisco-open/go-lanai/test/embedded"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"go.uber.org/fx"
+	"testing"
+	"time"
+)
+
+/*************************
+	Setup Test
+ *************************/
+
+type CacheTestDI struct {
+	fx.In
+	ClientFactory redis.ClientFactory
+}
+
+/*************************
+	Tests
+ *************************/
+
+func TestInMemoryCache(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestReplay(func() Cache { return NewInMemoryCache() }), "TestReplay"),
+		test.GomegaSubTest(SubTestInMemorySweep(), "TestSweep"),
+	)
+}
+
+func TestRedisCache(t *testing.T) {
+	di := &CacheTestDI{}
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		embedded.WithRedis(),
+		apptest.WithModules(redis.Module),
+		apptest.WithDI(di),
+		test.GomegaSubTest(SubTestReplay(func() Cache { return NewTestRedisCache(di, "TEST") }), "TestReplay"),
+		test.GomegaSubTest(SubTestRedisPrefix(di), "TestPrefix"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestReplay(newCache func() Cache) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		cache := newCache()
+		ok, e := cache.Add(ctx, "test-key", time.Minute)
+		g.Expect(e).To(Succeed(), "first use should not fail")
+		g.Expect(ok).To(BeTrue(), "first use should be accepted")
+		ok, e = cache.Add(ctx, "test-key", time.Minute)
+		g.Expect(e).To(Succeed(), "replay should not fail")
+		g.Expect(ok).To(BeFalse(), "replay should be rejected")
+		ok, e = cache.Add(ctx, "another-key", time.Minute)
+		g.Expect(e).To(Succeed(), "another key should not fail")
+		g.Expect(ok).To(BeTrue(), "another key should be accepted")
+	}
+}
+
+func SubTestInMemorySweep() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		cache := NewInMemoryCache()
+		ok, _ := cache.Add(ctx, "short-key", time.Nanosecond)
+		g.Expect(ok).To(BeTrue(), "short-lived key should be accepted")
+		time.Sleep(time.Millisecond)
+		ok, _ = cache.Add(ctx, "short-key", time.Nanosecond)
+		g.Expect(ok).To(BeTrue(), "expired key should be accepted again before sweep")
+
+		cache = NewInMemoryCache()
+		_, _ = cache.Add(ctx, "expired-key", time.Nanosecond)
+		_, _ = cache.Add(ctx, "valid-key", time.Hour)
+		time.Sleep(time.Millisecond)
+		_, _ = cache.Add(ctx, "new-key", time.Hour)
+		g.Expect(cache.used).To(HaveLen(3), "expired keys should not be removed before sweep interval")
+
+		cache.nextSweep = time.Now()
+		_, _ = cache.Add(ctx, "another-key", time.Hour)
+		g.Expect(cache.used).To(HaveLen(3), "expired keys should be removed after sweep interval")
+		g.Expect(cache.used).NotTo(HaveKey("expired-key"), "expired key should be removed")
+		g.Expect(cache.nextSweep).To(BeTemporally(">", time.Now()), "next sweep should be scheduled")
+	}
+}
+
+func SubTestRedisPrefix(di *CacheTestDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		first := NewTestRedisCache(di, "FIRST")
+		second := NewTestRedisCache(di, "SECOND")
+		ok, e := first.Add(ctx, "shared-key", time.Minute)
+		g.Expect(e).To(Succeed(), "first cache should not fail")
+		g.Expect(ok).To(BeTrue(), "first cache should accept the key")
+		ok, e = second.Add(ctx, "shared-key", time.Minute)
+		g.Expect(e).To(Succeed(), "second cache should not fail")
+		g.Expect(ok).To(BeTrue(), "caches with different prefixes should not collide")
+		exists, e := first.client.Exists(ctx, "FIRST:shared-key").Result()
+		g.Expect(e).To(Succeed(), "checking key should not fail")
+		g.Expect(exists).To(BeEquivalentTo(1), "key should be prefixed")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func NewTestRedisCache(di *CacheTestDI, prefix string) *RedisCache {
+	client, e := di.ClientFactory.New(context.Background())
+	if e != nil {
+		panic(e)
+	}
+	return NewRedisCache(client, prefix)
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webauthn
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/passwd"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"time"
+)
+
+const (
+	MessageInvalidAssertion         = "Security Key Verification Failed"
+	MessageAccountStatus            = "Inactive Account"
+	MessageUserVerificationRequired = "Security Key User Verification Required"
+)
+
+/************************
+	security.Candidate
+************************/
+
+// AssertionCandidate is the security.Candidate for passwordless login.
+// Username is empty when discoverable credential is used
+type AssertionCandidate struct {
+	Username   string
+	Session    webauthn.SessionData
+	Response   *protocol.ParsedCredentialAssertionData
+	DetailsMap map[string]interface{}
+}
+
+func (c *AssertionCandidate) Principal() interface{} {
+	return c.Username
+}
+
+func (c *AssertionCandidate) Credentials() interface{} {
+	return c.Response
+}
+
+func (c *AssertionCandidate) Details() interface{} {
+	return c.DetailsMap
+}
+
+// MFAAssertionCandidate is the security.Candidate for using WebAuthn as second factor of passwd MFA
+type MFAAssertionCandidate struct {
+	CurrentAuth passwd.UsernamePasswordAuthentication
+	Session     webauthn.SessionData
+	Response    *protocol.ParsedCredentialAssertionData
+	DetailsMap  map[string]interface{}
+}
+
+func (c *MFAAssertionCandidate) Principal() interface{} {
+	return c.CurrentAuth.Principal()
+}
+
+func (c *MFAAssertionCandidate) Credentials() interface{} {
+	return c.Response
+}
+
+func (c *MFAAssertionCandidate) Details() interface{} {
+	return c.DetailsMap
+}
+
+/******************************
+	security.Authentication
+******************************/
+
+type Authentication interface {
+	security.Authentication
+	CredentialId() []byte
+}
+
+// webAuthnAuthentication implements Authentication
+// Note: all fields should not be used directly. It's exported only because gob only deal with exported field
+type webAuthnAuthentication struct {
+	Acct       security.Account
+	Perms      map[string]interface{}
+	DetailsMap map[string]interface{}
+	CredId     []byte
+}
+
+func (auth *webAuthnAuthentication) Principal() interface{} {
+	return auth.Acct
+}
+
+func (auth *webAuthnAuthentication) Permissions() security.Permissions {
+	return auth.Perms
+}
+
+func (auth *webAuthnAuthentication) State() security.AuthenticationState {
+	return security.StateAuthenticated
+}
+
+func (auth *webAuthnAuthentication) Details() interface{} {
+	return auth.DetailsMap
+}
+
+func (auth *webAuthnAuthentication) CredentialId() []byte {
+	return auth.CredId
+}
+
+/************************
+	Authenticator
+************************/
+
+// Authenticator implements security.Authenticator and verifies AssertionCandidate and MFAAssertionCandidate
+type Authenticator struct {
+	accountStore security.AccountStore
+	manager      *CeremonyManager
+}
+
+func NewAuthenticator(accountStore security.AccountStore, manager *CeremonyManager) *Authenticator {
+	return &Authenticator{
+		accountStore: accountStore,
+		manager:      manager,
+	}
+}
+
+func (a *Authenticator) Authenticate(ctx context.Context, candidate security.Candidate) (security.Authentication, error) {
+	switch can := candidate.(type) {
+	case *AssertionCandidate:
+		return a.authenticate(ctx, can)
+	case *MFAAssertionCandidate:
+		return a.authenticateMFA(ctx, can)
+	default:
+		return nil, nil
+	}
+}
+
+func (a *Authenticator) authenticate(ctx context.Context, can *AssertionCandidate) (security.Authentication, error) {
+	cred, e := a.manager.FinishLogin(ctx, can.Username, can.Session, can.Response)
+	if e != nil {
+		return nil, security.NewBadCredentialsError(MessageInvalidAssertion, e)
+	}
+
+	acct, e := a.accountStore.LoadAccountByUsername(ctx, cred.Username)
+	switch {
+	case e != nil:
+		return nil, security.NewUsernameNotFoundError(MessageInvalidAssertion, e)
+	case acct.Disabled() || acct.Locked():
+		return nil, security.NewAccountStatusError(MessageAccountStatus)
+	case acct.UseMFA() && !cred.Flags.UserVerified:
+		// possession of the key alone is a single factor. Accounts requiring MFA need user verification (PIN or biometric)
+		return nil, security.NewAuthenticationError(MessageUserVerificationRequired)
+	}
+
+	details := map[string]interface{}{
+		security.DetailsKeyAuthTime:   time.Now().UTC(),
+		security.DetailsKeyAuthMethod: security.AuthMethodWebAuthn,
+	}
+	// user verification (PIN or biometric) on top of possession of the key is considered multi-factor
+	if cred.Flags.UserVerified {
+		details[security.DetailsKeyMFAApplied] = true
+	}
+	return a.createAuthentication(acct, cred, details), nil
+}
+
+func (a *Authenticator) authenticateMFA(ctx context.Context, can *MFAAssertionCandidate) (security.Authentication, error) {
+	if can.CurrentAuth == nil || !can.CurrentAuth.IsMFAPending() {
+		return nil, security.NewAccessDeniedError("MFA is not in progress")
+	}
+
+	acct, e := a.accountStore.LoadAccountByUsername(ctx, can.CurrentAuth.Username())
+	if e != nil {
+		return nil, security.NewUsernameNotFoundError(passwd.MessageInvalidAccountStatus, e)
+	}
+
+	cred, e := a.manager.FinishLogin(ctx, acct.Username(), can.Session, can.Response)
+	if e != nil {
+		return nil, security.NewBadCredentialsError(MessageInvalidAssertion, e)
+	}
+
+	details, ok := can.CurrentAuth.Details().(map[string]interface{})
+	if details == nil || !ok {
+		details = map[string]interface{}{}
+	}
+	details[security.DetailsKeyAuthTime] = time.Now().UTC()
+	details[security.DetailsKeyMFAApplied] = true
+	return a.createAuthentication(acct, cred, details), nil
+}
+
+func (a *Authenticator) createAuthentication(acct security.Account, cred *Credential, details map[string]interface{}) *webAuthnAuthentication {
+	permissions := map[string]interface{}{}
+	for _, p := range acct.Permissions() {
+		permissions[p] = true
+	}
+	return &webAuthnAuthentication{
+		Acct:       acct,
+		Perms:      permissions,
+		DetailsMap: details,
+		CredId:     cred.ID,
+	}
+}
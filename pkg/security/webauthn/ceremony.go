@@ -0,0 +1,396 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webauthn
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"io"
+	"os"
+	"time"
+)
+
+type ManagerOptions func(opt *ManagerOption)
+type ManagerOption struct {
+	Properties *WebAuthnProperties
+	Store      CredentialStore
+	// AttestationRoots are trusted attestation root certificates, in addition to WebAuthnProperties' TrustedRoots
+	AttestationRoots []*x509.Certificate
+}
+
+func WithProperties(props *WebAuthnProperties) ManagerOptions {
+	return func(opt *ManagerOption) {
+		opt.Properties = props
+	}
+}
+
+func WithCredentialStore(store CredentialStore) ManagerOptions {
+	return func(opt *ManagerOption) {
+		opt.Store = store
+	}
+}
+
+func WithAttestationRoots(roots ...*x509.Certificate) ManagerOptions {
+	return func(opt *ManagerOption) {
+		opt.AttestationRoots = append(opt.AttestationRoots, roots...)
+	}
+}
+
+// CeremonyManager performs WebAuthn registration and assertion ceremonies on behalf of accounts,
+// using CredentialStore to look up and persist credentials.
+// The returned webauthn.SessionData need to be kept by caller (e.g. in HTTP session) between "begin" and "finish" calls
+type CeremonyManager struct {
+	webauthn       *webauthn.WebAuthn
+	store          CredentialStore
+	requireAttest  bool
+	allowedAAGUIDs []uuid.UUID
+	attestRoots    *x509.CertPool
+	// decoyKey derives decoy credential IDs of users without credentials
+	decoyKey []byte
+}
+
+// NewCeremonyManager creates CeremonyManager. CredentialStore is required, see WithCredentialStore
+func NewCeremonyManager(opts ...ManagerOptions) (*CeremonyManager, error) {
+	opt := ManagerOption{
+		Properties: NewWebAuthnProperties(),
+	}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	if opt.Store == nil {
+		return nil, fmt.Errorf("CredentialStore is required for WebAuthn")
+	}
+	props := opt.Properties
+
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    time.Duration(props.Timeout),
+		TimeoutUVD: time.Duration(props.Timeout),
+	}
+	w, e := webauthn.New(&webauthn.Config{
+		RPID:                  props.RPID,
+		RPDisplayName:         props.RPDisplayName,
+		RPOrigins:             props.RPOrigins,
+		AttestationPreference: protocol.ConveyancePreference(props.Attestation.Conveyance),
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			AuthenticatorAttachment: protocol.AuthenticatorAttachment(props.AuthenticatorAttachment),
+			ResidentKey:             protocol.ResidentKeyRequirement(props.ResidentKey),
+			RequireResidentKey:      protocol.ResidentKeyNotRequired(),
+			UserVerification:        protocol.UserVerificationRequirement(props.UserVerification),
+		},
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+	if e != nil {
+		return nil, e
+	}
+	if props.ResidentKey == string(protocol.ResidentKeyRequirementRequired) {
+		w.Config.AuthenticatorSelection.RequireResidentKey = protocol.ResidentKeyRequired()
+	}
+
+	aaguids := make([]uuid.UUID, len(props.Attestation.AllowedAAGUIDs))
+	for i, v := range props.Attestation.AllowedAAGUIDs {
+		if aaguids[i], e = uuid.Parse(v); e != nil {
+			return nil, fmt.Errorf("invalid AAGUID [%s]: %v", v, e)
+		}
+	}
+	roots, e := loadAttestationRoots(props.Attestation.TrustedRoots, opt.AttestationRoots)
+	switch {
+	case e != nil:
+		return nil, e
+	case len(aaguids) != 0 && roots == nil:
+		return nil, fmt.Errorf("trusted attestation roots are required to enforce allowed AAGUIDs")
+	}
+	decoyKey := make([]byte, 32)
+	if _, e := rand.Read(decoyKey); e != nil {
+		return nil, e
+	}
+	return &CeremonyManager{
+		webauthn:       w,
+		store:          opt.Store,
+		requireAttest:  props.Attestation.Required,
+		allowedAAGUIDs: aaguids,
+		attestRoots:    roots,
+		decoyKey:       decoyKey,
+	}, nil
+}
+
+// loadAttestationRoots returns nil if no root is configured
+func loadAttestationRoots(paths []string, certs []*x509.Certificate) (*x509.CertPool, error) {
+	if len(paths) == 0 && len(certs) == 0 {
+		return nil, nil
+	}
+	pool := x509.NewCertPool()
+	for _, path := range paths {
+		data, e := os.ReadFile(path)
+		if e != nil {
+			return nil, fmt.Errorf("unable to read attestation roots [%s]: %v", path, e)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no valid certificate found in attestation roots [%s]", path)
+		}
+	}
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+	return pool, nil
+}
+
+func (m *CeremonyManager) CredentialStore() CredentialStore {
+	return m.store
+}
+
+// BeginRegistration starts registration ceremony for given account. Existing credentials of the account are excluded.
+func (m *CeremonyManager) BeginRegistration(ctx context.Context, acct security.Account) (*protocol.CredentialCreation, *webauthn.SessionData, error) {
+	user, e := m.loadUser(ctx, acct.Username())
+	if e != nil {
+		return nil, nil, e
+	}
+	if meta, ok := acct.(security.AccountMetadata); ok {
+		if name := fmt.Sprintf("%s %s", meta.FirstName(), meta.LastName()); len(name) > 1 {
+			user.displayName = name
+		}
+	}
+	exclusions := make([]protocol.CredentialDescriptor, len(user.credentials))
+	for i := range user.credentials {
+		exclusions[i] = user.credentials[i].Descriptor()
+	}
+	return m.webauthn.BeginRegistration(user, webauthn.WithExclusions(exclusions))
+}
+
+// FinishRegistration verifies the attestation response read from given body, applies attestation policy and save the new credential.
+func (m *CeremonyManager) FinishRegistration(ctx context.Context, acct security.Account, session webauthn.SessionData, body io.Reader, name string) (*Credential, error) {
+	parsed, e := protocol.ParseCredentialCreationResponseBody(body)
+	if e != nil {
+		return nil, e
+	}
+	user, e := m.loadUser(ctx, acct.Username())
+	if e != nil {
+		return nil, e
+	}
+	data, e := m.webauthn.CreateCredential(user, session, parsed)
+	if e != nil {
+		return nil, e
+	}
+	if e := m.checkAttestation(parsed, data); e != nil {
+		return nil, e
+	}
+	if existing, e := m.store.LoadCredentialById(ctx, data.ID); e == nil && existing != nil {
+		return nil, protocol.ErrBadRequest.WithDetails("credential is already registered")
+	}
+
+	now := time.Now().UTC()
+	cred := &Credential{
+		Credential: *data,
+		Username:   acct.Username(),
+		Name:       name,
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+	if e := m.store.SaveCredential(ctx, cred); e != nil {
+		return nil, e
+	}
+	return cred, nil
+}
+
+// BeginLogin starts assertion ceremony. When username is empty, the ceremony is for discoverable credentials (passkeys).
+// Users without credentials (including unknown usernames) get a stable decoy credential, so the options don't reveal
+// whether the username exists. The decoy credential always fails FinishLogin
+func (m *CeremonyManager) BeginLogin(ctx context.Context, username string) (*protocol.CredentialAssertion, *webauthn.SessionData, error) {
+	if username == "" {
+		return m.webauthn.BeginDiscoverableLogin()
+	}
+	user, e := m.loadUser(ctx, username)
+	if e != nil {
+		return nil, nil, e
+	}
+	if len(user.credentials) == 0 {
+		user.credentials = []*Credential{m.decoyCredential(username)}
+	}
+	return m.webauthn.BeginLogin(user)
+}
+
+// FinishLogin verifies the assertion and returns the verified credential with updated signature counter.
+// username should be the same value used in BeginLogin
+func (m *CeremonyManager) FinishLogin(ctx context.Context, username string, session webauthn.SessionData, parsed *protocol.ParsedCredentialAssertionData) (*Credential, error) {
+	var verified *webauthn.Credential
+	var user *webAuthnUser
+	var e error
+	if username == "" {
+		verified, e = m.webauthn.ValidateDiscoverableLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+			cred, e := m.store.LoadCredentialById(ctx, rawID)
+			if e != nil {
+				return nil, e
+			}
+			if user, e = m.loadUser(ctx, cred.Username); e != nil {
+				return nil, e
+			}
+			if !bytes.Equal(user.WebAuthnID(), userHandle) {
+				return nil, fmt.Errorf("user handle mismatch")
+			}
+			return user, nil
+		}, session, parsed)
+	} else {
+		if user, e = m.loadUser(ctx, username); e == nil {
+			verified, e = m.webauthn.ValidateLogin(user, session, parsed)
+		}
+	}
+	if e != nil {
+		return nil, e
+	}
+	if verified.Authenticator.CloneWarning {
+		return nil, protocol.ErrBadRequest.WithDetails("signature counter indicates the authenticator may be cloned")
+	}
+
+	var cred *Credential
+	for _, c := range user.credentials {
+		if bytes.Equal(c.ID, verified.ID) {
+			cred = c
+		}
+	}
+	if cred == nil {
+		return nil, ErrCredentialNotFound
+	}
+	cred.Authenticator = verified.Authenticator
+	cred.Flags = verified.Flags
+	cred.LastUsedAt = time.Now().UTC()
+	if e := m.store.SaveCredential(ctx, cred); e != nil {
+		return nil, e
+	}
+	return cred, nil
+}
+
+func (m *CeremonyManager) loadUser(ctx context.Context, username string) (*webAuthnUser, error) {
+	creds, e := m.store.LoadCredentials(ctx, username)
+	if e != nil {
+		return nil, e
+	}
+	return &webAuthnUser{
+		username:    username,
+		displayName: username,
+		credentials: creds,
+	}, nil
+}
+
+func (m *CeremonyManager) decoyCredential(username string) *Credential {
+	mac := hmac.New(sha256.New, m.decoyKey)
+	mac.Write([]byte(username))
+	return &Credential{
+		Credential: webauthn.Credential{ID: mac.Sum(nil)},
+		Username:   username,
+	}
+}
+
+func (m *CeremonyManager) checkAttestation(parsed *protocol.ParsedCredentialCreationData, cred *webauthn.Credential) error {
+	if m.requireAttest && (cred.AttestationType == "" || cred.AttestationType == "none") {
+		return protocol.ErrAttestation.WithDetails("attestation statement is required")
+	}
+	if len(m.allowedAAGUIDs) == 0 {
+		return nil
+	}
+	// AAGUID is reported by the authenticator, it can only be trusted when the attestation chains to a trusted root
+	if e := m.verifyAttestationChain(parsed.Response.AttestationObject.AttStatement); e != nil {
+		return e
+	}
+	aaguid, e := uuid.FromBytes(cred.Authenticator.AAGUID)
+	if e != nil {
+		return protocol.ErrAttestation.WithDetails("invalid authenticator AAGUID")
+	}
+	for _, allowed := range m.allowedAAGUIDs {
+		if allowed == aaguid {
+			return nil
+		}
+	}
+	return protocol.ErrAttestation.WithDetails(fmt.Sprintf("authenticator model [%v] is not allowed", aaguid))
+}
+
+// verifyAttestationChain verifies "x5c" of the attestation statement against trusted roots.
+// The signature of attestation statement is verified by the attestation format during registration
+func (m *CeremonyManager) verifyAttestationChain(stmt map[string]interface{}) error {
+	x5c, _ := stmt["x5c"].([]interface{})
+	if len(x5c) == 0 {
+		return protocol.ErrAttestation.WithDetails("attestation certificate is required")
+	}
+	certs := make([]*x509.Certificate, len(x5c))
+	for i, raw := range x5c {
+		der, ok := raw.([]byte)
+		if !ok {
+			return protocol.ErrAttestation.WithDetails("invalid attestation certificate")
+		}
+		var e error
+		if certs[i], e = x509.ParseCertificate(der); e != nil {
+			return protocol.ErrAttestation.WithDetails(fmt.Sprintf("invalid attestation certificate: %v", e))
+		}
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, e := certs[0].Verify(x509.VerifyOptions{
+		Roots:         m.attestRoots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if e != nil {
+		return protocol.ErrAttestation.WithDetails(fmt.Sprintf("untrusted attestation certificate: %v", e))
+	}
+	return nil
+}
+
+// webAuthnUser implements webauthn.User.
+// The user handle is derived from username, so it's stable and doesn't reveal the username to authenticators
+type webAuthnUser struct {
+	username    string
+	displayName string
+	credentials []*Credential
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	hash := sha256.Sum256([]byte(u.username))
+	return hash[:]
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.username
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.displayName
+}
+
+func (u *webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	ret := make([]webauthn.Credential, len(u.credentials))
+	for i := range u.credentials {
+		ret[i] = u.credentials[i].Credential
+	}
+	return ret
+}
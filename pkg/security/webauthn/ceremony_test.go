@@ -0,0 +1,498 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webauthn
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/sectest"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+	"math/big"
+	"testing"
+	"time"
+)
+
+const (
+	TestOrigin   = "http://localhost:8900"
+	TestUsername = "alice"
+	TestAAGUID   = "2fc0579f-8113-47ea-b116-bb5a8db9202a"
+)
+
+/*************************
+	Test Cases
+ *************************/
+
+func TestCeremonies(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestRegisterAndLogin(), "RegisterAndLogin"),
+		test.GomegaSubTest(SubTestClonedAuthenticator(), "ClonedAuthenticator"),
+		test.GomegaSubTest(SubTestAttestationPolicy(), "AttestationPolicy"),
+		test.GomegaSubTest(SubTestAuthenticator(), "Authenticator"),
+		test.GomegaSubTest(SubTestUserVerificationRequired(), "UserVerificationRequired"),
+		test.GomegaSubTest(SubTestLoginOptionsWithoutCredentials(), "LoginOptionsWithoutCredentials"),
+		test.GomegaSubTest(SubTestManagerWithoutStore(), "ManagerWithoutStore"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestRegisterAndLogin() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		manager := NewTestCeremonyManager(g)
+		key := NewVirtualAuthenticator(g)
+		cred := RegisterCredential(ctx, g, manager, key)
+		g.Expect(cred.Username).To(Equal(TestUsername))
+		g.Expect(cred.Name).To(Equal("my key"))
+		g.Expect(cred.ID).To(Equal(key.credId))
+
+		// existing credentials are excluded from new registration
+		creation, _, e := manager.BeginRegistration(ctx, NewTestAccount())
+		g.Expect(e).To(Succeed())
+		g.Expect(creation.Response.CredentialExcludeList).To(HaveLen(1))
+
+		// discoverable
+		verified := Login(ctx, g, manager, key, "")
+		g.Expect(verified.Username).To(Equal(TestUsername))
+		g.Expect(verified.Authenticator.SignCount).To(BeEquivalentTo(1))
+
+		// with username
+		verified = Login(ctx, g, manager, key, TestUsername)
+		g.Expect(verified.Authenticator.SignCount).To(BeEquivalentTo(2))
+		stored, e := manager.CredentialStore().LoadCredentialById(ctx, key.credId)
+		g.Expect(e).To(Succeed())
+		g.Expect(stored.Authenticator.SignCount).To(BeEquivalentTo(2))
+	}
+}
+
+func SubTestClonedAuthenticator() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		manager := NewTestCeremonyManager(g)
+		key := NewVirtualAuthenticator(g)
+		RegisterCredential(ctx, g, manager, key)
+		Login(ctx, g, manager, key, TestUsername)
+
+		key.counter = 0
+		_, data, e := manager.BeginLogin(ctx, TestUsername)
+		g.Expect(e).To(Succeed())
+		_, e = manager.FinishLogin(ctx, TestUsername, *data, key.Assert(g, data.Challenge))
+		g.Expect(e).To(HaveOccurred())
+	}
+}
+
+func SubTestManagerWithoutStore() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		props := NewWebAuthnProperties()
+		props.RPOrigins = []string{TestOrigin}
+		_, e := NewCeremonyManager(WithProperties(props))
+		g.Expect(e).To(HaveOccurred(), "manager without CredentialStore should fail")
+		_, e = provideCeremonyManager(managerDI{Properties: *props})
+		g.Expect(e).To(HaveOccurred(), "module without CredentialStore should fail")
+	}
+}
+
+func SubTestAttestationPolicy() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		ca := NewTestAttestationCA(g)
+		// attestation required
+		manager := NewTestCeremonyManager(g, func(props *WebAuthnProperties) {
+			props.Attestation.Required = true
+		})
+		_, e := TryRegisterCredential(ctx, g, manager, NewVirtualAuthenticator(g))
+		g.Expect(e).To(HaveOccurred())
+
+		// AAGUID allow-list cannot be enforced without trusted roots
+		_, e = NewCeremonyManager(WithProperties(&WebAuthnProperties{
+			RPID: "localhost", RPDisplayName: "localhost", RPOrigins: []string{TestOrigin},
+			Attestation: AttestationProperties{AllowedAAGUIDs: []string{TestAAGUID}},
+		}), WithCredentialStore(NewInMemoryCredentialStore()))
+		g.Expect(e).To(HaveOccurred(), "allowed AAGUIDs without trusted roots should be rejected")
+
+		// AAGUID not allowed
+		manager = NewTestAttestationManager(g, uuid.NewString(), ca.cert)
+		_, e = TryRegisterCredential(ctx, g, manager, NewVirtualAuthenticator(g).WithAttestation(g, ca))
+		g.Expect(e).To(HaveOccurred())
+
+		// AAGUID allowed, but without attestation certificate
+		manager = NewTestAttestationManager(g, TestAAGUID, ca.cert)
+		_, e = TryRegisterCredential(ctx, g, manager, NewVirtualAuthenticator(g))
+		g.Expect(e).To(HaveOccurred(), "self-reported AAGUID should not be trusted")
+
+		// AAGUID allowed, but attestation certificate is issued by untrusted CA
+		_, e = TryRegisterCredential(ctx, g, manager, NewVirtualAuthenticator(g).WithAttestation(g, NewTestAttestationCA(g)))
+		g.Expect(e).To(HaveOccurred(), "untrusted attestation should be rejected")
+
+		// AAGUID allowed
+		_, e = TryRegisterCredential(ctx, g, manager, NewVirtualAuthenticator(g).WithAttestation(g, ca))
+		g.Expect(e).To(Succeed())
+	}
+}
+
+func SubTestAuthenticator() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		manager := NewTestCeremonyManager(g)
+		key := NewVirtualAuthenticator(g)
+		RegisterCredential(ctx, g, manager, key)
+		accountStore := sectest.NewMockedAccountStore([]*sectest.MockedAccountProperties{
+			{UserId: "alice-id", Username: TestUsername, Perms: []string{"TEST_PERMISSION"}},
+		})
+		authenticator := NewAuthenticator(accountStore, manager)
+
+		_, data, e := manager.BeginLogin(ctx, "")
+		g.Expect(e).To(Succeed())
+		auth, e := authenticator.Authenticate(ctx, &AssertionCandidate{
+			Session:  *data,
+			Response: key.Assert(g, data.Challenge),
+		})
+		g.Expect(e).To(Succeed())
+		g.Expect(auth.State()).To(Equal(security.StateAuthenticated))
+		g.Expect(auth.Permissions()).To(HaveKey("TEST_PERMISSION"))
+		details := auth.Details().(map[string]interface{})
+		g.Expect(details).To(HaveKeyWithValue(security.DetailsKeyAuthMethod, security.AuthMethodWebAuthn))
+		g.Expect(details).To(HaveKeyWithValue(security.DetailsKeyMFAApplied, true))
+
+		// replayed assertion
+		_, e = authenticator.Authenticate(ctx, &AssertionCandidate{
+			Session:  *data,
+			Response: key.Assert(g, "replayed"),
+		})
+		g.Expect(e).To(HaveOccurred())
+		g.Expect(e).To(BeAssignableToTypeOf(&security.CodedError{}))
+	}
+}
+
+func SubTestUserVerificationRequired() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		manager := NewTestCeremonyManager(g)
+		key := NewVirtualAuthenticator(g)
+		key.noUV = true
+		RegisterCredential(ctx, g, manager, key)
+		authenticator := NewAuthenticator(mfaAccountStore{}, manager)
+
+		_, data, e := manager.BeginLogin(ctx, "")
+		g.Expect(e).To(Succeed())
+		_, e = authenticator.Authenticate(ctx, &AssertionCandidate{
+			Session:  *data,
+			Response: key.Assert(g, data.Challenge),
+		})
+		g.Expect(e).To(HaveOccurred(), "MFA account should not login without user verification")
+
+		key.noUV = false
+		_, data, e = manager.BeginLogin(ctx, "")
+		g.Expect(e).To(Succeed())
+		auth, e := authenticator.Authenticate(ctx, &AssertionCandidate{
+			Session:  *data,
+			Response: key.Assert(g, data.Challenge),
+		})
+		g.Expect(e).To(Succeed(), "MFA account should login with user verification")
+		g.Expect(auth.Details()).To(HaveKeyWithValue(security.DetailsKeyMFAApplied, true))
+	}
+}
+
+func SubTestLoginOptionsWithoutCredentials() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *WithT) {
+		manager := NewTestCeremonyManager(g)
+		key := NewVirtualAuthenticator(g)
+		RegisterCredential(ctx, g, manager, key)
+
+		known, _, e := manager.BeginLogin(ctx, TestUsername)
+		g.Expect(e).To(Succeed())
+		unknown, data, e := manager.BeginLogin(ctx, "unknown")
+		g.Expect(e).To(Succeed(), "unknown username should not be revealed")
+		g.Expect(unknown.Response.AllowedCredentials).To(HaveLen(len(known.Response.AllowedCredentials)))
+		again, _, e := manager.BeginLogin(ctx, "unknown")
+		g.Expect(e).To(Succeed())
+		g.Expect(again.Response.AllowedCredentials[0].CredentialID).
+			To(Equal(unknown.Response.AllowedCredentials[0].CredentialID), "decoy credential should be stable")
+		another, _, e := manager.BeginLogin(ctx, "another")
+		g.Expect(e).To(Succeed())
+		g.Expect(another.Response.AllowedCredentials[0].CredentialID).
+			ToNot(Equal(unknown.Response.AllowedCredentials[0].CredentialID), "decoy credential should differ per user")
+
+		_, e = manager.FinishLogin(ctx, "unknown", *data, key.Assert(g, data.Challenge))
+		g.Expect(e).To(HaveOccurred(), "decoy credential should not be usable")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+// mfaAccountStore returns account with MFA enabled
+type mfaAccountStore struct {
+	security.AccountStore
+}
+
+func (mfaAccountStore) LoadAccountByUsername(_ context.Context, username string) (security.Account, error) {
+	return security.NewUsernamePasswordAccount(&security.AcctDetails{
+		ID:       "alice-id",
+		Username: username,
+		UseMFA:   true,
+	}), nil
+}
+
+type TestAttestationCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func NewTestAttestationCA(g *WithT) *TestAttestationCA {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(e).To(Succeed())
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Attestation Root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, e := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	g.Expect(e).To(Succeed())
+	cert, e := x509.ParseCertificate(der)
+	g.Expect(e).To(Succeed())
+	return &TestAttestationCA{cert: cert, key: key}
+}
+
+func NewTestAttestationManager(g *WithT, aaguid string, roots ...*x509.Certificate) *CeremonyManager {
+	props := NewWebAuthnProperties()
+	props.RPOrigins = []string{TestOrigin}
+	props.Attestation.AllowedAAGUIDs = []string{aaguid}
+	manager, e := NewCeremonyManager(WithProperties(props), WithAttestationRoots(roots...), WithCredentialStore(NewInMemoryCredentialStore()))
+	g.Expect(e).To(Succeed())
+	return manager
+}
+
+func NewTestAccount() security.Account {
+	return security.NewUsernamePasswordAccount(&security.AcctDetails{
+		ID:       "alice-id",
+		Username: TestUsername,
+	})
+}
+
+func NewTestCeremonyManager(g *WithT, customizers ...func(props *WebAuthnProperties)) *CeremonyManager {
+	props := NewWebAuthnProperties()
+	props.RPOrigins = []string{TestOrigin}
+	for _, fn := range customizers {
+		fn(props)
+	}
+	manager, e := NewCeremonyManager(WithProperties(props), WithCredentialStore(NewInMemoryCredentialStore()))
+	g.Expect(e).To(Succeed())
+	return manager
+}
+
+func RegisterCredential(ctx context.Context, g *WithT, manager *CeremonyManager, key *VirtualAuthenticator) *Credential {
+	cred, e := TryRegisterCredential(ctx, g, manager, key)
+	g.Expect(e).To(Succeed())
+	return cred
+}
+
+func TryRegisterCredential(ctx context.Context, g *WithT, manager *CeremonyManager, key *VirtualAuthenticator) (*Credential, error) {
+	acct := NewTestAccount()
+	creation, data, e := manager.BeginRegistration(ctx, acct)
+	g.Expect(e).To(Succeed())
+	g.Expect(creation.Response.RelyingParty.ID).To(Equal("localhost"))
+	return manager.FinishRegistration(ctx, acct, *data, key.Attest(g, data.Challenge), "my key")
+}
+
+func Login(ctx context.Context, g *WithT, manager *CeremonyManager, key *VirtualAuthenticator, username string) *Credential {
+	_, data, e := manager.BeginLogin(ctx, username)
+	g.Expect(e).To(Succeed())
+	cred, e := manager.FinishLogin(ctx, username, *data, key.Assert(g, data.Challenge))
+	g.Expect(e).To(Succeed())
+	return cred
+}
+
+// VirtualAuthenticator is a software authenticator with ES256 key, "none" attestation and user verification.
+// "packed" attestation is used when attestation key is set
+type VirtualAuthenticator struct {
+	key     *ecdsa.PrivateKey
+	credId  []byte
+	counter uint32
+	noUV    bool
+	attKey  *ecdsa.PrivateKey
+	attCert []byte
+}
+
+func NewVirtualAuthenticator(g *WithT) *VirtualAuthenticator {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(e).To(Succeed())
+	credId := make([]byte, 16)
+	_, _ = rand.Read(credId)
+	return &VirtualAuthenticator{key: key, credId: credId}
+}
+
+// WithAttestation issues attestation certificate with given CA
+func (a *VirtualAuthenticator) WithAttestation(g *WithT, ca *TestAttestationCA) *VirtualAuthenticator {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(e).To(Succeed())
+	aaguid := uuid.MustParse(TestAAGUID)
+	ext, e := asn1.Marshal(aaguid[:])
+	g.Expect(e).To(Succeed())
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject: pkix.Name{
+			Country:            []string{"US"},
+			Organization:       []string{"Test Vendor"},
+			OrganizationalUnit: []string{"Authenticator Attestation"},
+			CommonName:         "Test Authenticator",
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		ExtraExtensions:       []pkix.Extension{{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 45724, 1, 1, 4}, Value: ext}},
+	}
+	a.attCert, e = x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	g.Expect(e).To(Succeed())
+	a.attKey = key
+	return a
+}
+
+func (a *VirtualAuthenticator) Attest(g *WithT, challenge string) *bytes.Reader {
+	clientData := a.clientData(g, "webauthn.create", challenge)
+	aaguid := uuid.MustParse(TestAAGUID)
+	authData := a.authData(0x45) // UP, UV, AT
+	authData = append(authData, aaguid[:]...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credId)))
+	authData = append(authData, a.credId...)
+	authData = append(authData, a.coseKey()...)
+
+	attObj := []byte{0xa3}
+	attObj = append(attObj, cborText("fmt")...)
+	if a.attKey == nil {
+		attObj = append(attObj, cborText("none")...)
+		attObj = append(attObj, cborText("attStmt")...)
+		attObj = append(attObj, 0xa0)
+	} else {
+		// packed attestation: {"alg": -7, "sig": sig, "x5c": [cert]}
+		hash := sha256.Sum256(clientData)
+		digest := sha256.Sum256(append(append([]byte{}, authData...), hash[:]...))
+		sig, e := ecdsa.SignASN1(rand.Reader, a.attKey, digest[:])
+		g.Expect(e).To(Succeed())
+		attObj = append(attObj, cborText("packed")...)
+		attObj = append(attObj, cborText("attStmt")...)
+		attObj = append(attObj, 0xa3)
+		attObj = append(attObj, cborText("alg")...)
+		attObj = append(attObj, 0x26)
+		attObj = append(attObj, cborText("sig")...)
+		attObj = append(attObj, cborBytes(sig)...)
+		attObj = append(attObj, cborText("x5c")...)
+		attObj = append(attObj, 0x81)
+		attObj = append(attObj, cborBytes(a.attCert)...)
+	}
+	attObj = append(attObj, cborText("authData")...)
+	attObj = append(attObj, cborBytes(authData)...)
+
+	return a.response(g, map[string]interface{}{
+		"clientDataJSON":    b64(clientData),
+		"attestationObject": b64(attObj),
+	})
+}
+
+func (a *VirtualAuthenticator) Assert(g *WithT, challenge string) *protocol.ParsedCredentialAssertionData {
+	a.counter++
+	clientData := a.clientData(g, "webauthn.get", challenge)
+	flags := byte(0x05) // UP, UV
+	if a.noUV {
+		flags = 0x01
+	}
+	authData := a.authData(flags)
+	hash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), hash[:]...))
+	sig, e := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	g.Expect(e).To(Succeed())
+
+	userHandle := sha256.Sum256([]byte(TestUsername))
+	parsed, e := protocol.ParseCredentialRequestResponseBody(a.response(g, map[string]interface{}{
+		"clientDataJSON":    b64(clientData),
+		"authenticatorData": b64(authData),
+		"signature":         b64(sig),
+		"userHandle":        b64(userHandle[:]),
+	}))
+	g.Expect(e).To(Succeed())
+	return parsed
+}
+
+func (a *VirtualAuthenticator) clientData(g *WithT, typ, challenge string) []byte {
+	data, e := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    TestOrigin,
+	})
+	g.Expect(e).To(Succeed())
+	return data
+}
+
+func (a *VirtualAuthenticator) authData(flags byte) []byte {
+	rpIdHash := sha256.Sum256([]byte("localhost"))
+	data := append(rpIdHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, a.counter)
+}
+
+func (a *VirtualAuthenticator) coseKey() []byte {
+	// {1: 2 (EC2), 3: -7 (ES256), -1: 1 (P-256), -2: x, -3: y}
+	key := []byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21}
+	key = append(key, cborBytes(a.key.X.FillBytes(make([]byte, 32)))...)
+	key = append(key, 0x22)
+	return append(key, cborBytes(a.key.Y.FillBytes(make([]byte, 32)))...)
+}
+
+func (a *VirtualAuthenticator) response(g *WithT, resp map[string]interface{}) *bytes.Reader {
+	data, e := json.Marshal(map[string]interface{}{
+		"id":       b64(a.credId),
+		"rawId":    b64(a.credId),
+		"type":     "public-key",
+		"response": resp,
+	})
+	g.Expect(e).To(Succeed())
+	return bytes.NewReader(data)
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func cborText(v string) []byte {
+	return append([]byte{0x60 | byte(len(v))}, v...)
+}
+
+func cborBytes(v []byte) []byte {
+	switch {
+	case len(v) < 24:
+		return append([]byte{0x40 | byte(len(v))}, v...)
+	case len(v) < 256:
+		return append([]byte{0x58, byte(len(v))}, v...)
+	default:
+		return append([]byte{0x59, byte(len(v) >> 8), byte(len(v))}, v...)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webauthn
+
+import (
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/access"
+	"github.com/cisco-open/go-lanai/pkg/security/csrf"
+	"github.com/cisco-open/go-lanai/pkg/security/passwd"
+	"github.com/cisco-open/go-lanai/pkg/security/redirect"
+	"github.com/cisco-open/go-lanai/pkg/security/request_cache"
+	"github.com/cisco-open/go-lanai/pkg/utils/order"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"github.com/cisco-open/go-lanai/pkg/web/mapping"
+	"github.com/cisco-open/go-lanai/pkg/web/matcher"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type WebAuthnConfigurer struct {
+	accountStore security.AccountStore
+	manager      *CeremonyManager
+}
+
+func newWebAuthnConfigurer(accountStore security.AccountStore, manager *CeremonyManager) *WebAuthnConfigurer {
+	return &WebAuthnConfigurer{
+		accountStore: accountStore,
+		manager:      manager,
+	}
+}
+
+func (c *WebAuthnConfigurer) Apply(feature security.Feature, ws security.WebSecurity) error {
+	f := feature.(*Feature)
+	if c.accountStore == nil {
+		return fmt.Errorf("security.AccountStore is required for %v", FeatureId)
+	}
+	if f.successHandler == nil {
+		f.successHandler = request_cache.NewSavedRequestAuthenticationSuccessHandler(
+			redirect.NewRedirectWithRelativePath("/", true),
+			func(_, to security.Authentication) bool {
+				return security.IsFullyAuthenticated(to)
+			})
+	}
+
+	mw := NewWebAuthnMiddleware(func(opts *MWOptions) {
+		opts.Manager = c.manager
+		opts.AccountStore = c.accountStore
+		opts.Authenticator = NewAuthenticator(c.accountStore, c.manager)
+		opts.SuccessHandler = c.effectiveSuccessHandler(f, ws)
+		opts.UsernameParam = f.usernameParam
+		opts.CredentialParam = f.credentialParam
+	})
+
+	// registration
+	c.addEndpoint(ws, f.registrationOptionsUrl, mw.RegistrationOptionsHandlerFunc(), "webauthn registration options")
+	c.addEndpoint(ws, f.registrationUrl, mw.RegistrationHandlerFunc(), "webauthn registration")
+	access.Configure(ws).
+		Request(c.requestMatcher(f.registrationOptionsUrl, f.registrationUrl)).WithOrder(order.Highest).
+		Authenticated()
+
+	// passwordless login
+	c.addEndpoint(ws, f.loginOptionsUrl, mw.LoginOptionsHandlerFunc(), "webauthn login options")
+	c.addEndpoint(ws, f.loginUrl, mw.LoginHandlerFunc(), "webauthn login")
+	access.Configure(ws).
+		Request(c.requestMatcher(f.loginOptionsUrl, f.loginUrl)).WithOrder(order.Highest).
+		PermitAll()
+
+	csrfMatcher := c.requestMatcher(f.registrationOptionsUrl, f.registrationUrl, f.loginOptionsUrl, f.loginUrl)
+
+	// second factor
+	if f.mfaEnabled {
+		c.addEndpoint(ws, f.mfaOptionsUrl, mw.MfaOptionsHandlerFunc(), "webauthn mfa options")
+		c.addEndpoint(ws, f.mfaVerifyUrl, mw.MfaVerifyHandlerFunc(), "webauthn mfa verify")
+		access.Configure(ws).
+			Request(c.requestMatcher(f.mfaOptionsUrl, f.mfaVerifyUrl)).WithOrder(order.Highest).
+			HasPermissions(passwd.SpecialPermissionMFAPending, passwd.SpecialPermissionOtpId)
+		csrfMatcher = csrfMatcher.Or(c.requestMatcher(f.mfaOptionsUrl, f.mfaVerifyUrl))
+	}
+
+	csrf.Configure(ws).AddCsrfProtectionMatcher(csrfMatcher)
+	return nil
+}
+
+func (c *WebAuthnConfigurer) addEndpoint(ws security.WebSecurity, url string, handler gin.HandlerFunc, name string) {
+	ws.Route(matcher.RouteWithURL(url, http.MethodPost)).
+		Add(mapping.Post(url).HandlerFunc(handler).Name(name))
+}
+
+func (c *WebAuthnConfigurer) requestMatcher(urls ...string) web.RequestMatcher {
+	var m web.RequestMatcher
+	for _, url := range urls {
+		if m == nil {
+			m = matcher.RequestWithURL(url, http.MethodPost)
+		} else {
+			m = m.Or(matcher.RequestWithURL(url, http.MethodPost))
+		}
+	}
+	return m
+}
+
+func (c *WebAuthnConfigurer) effectiveSuccessHandler(f *Feature, ws security.WebSecurity) security.AuthenticationSuccessHandler {
+	if globalHandler, ok := ws.Shared(security.WSSharedKeyCompositeAuthSuccessHandler).(security.AuthenticationSuccessHandler); ok {
+		return security.NewAuthenticationSuccessHandler(globalHandler, f.successHandler)
+	} else {
+		return security.NewAuthenticationSuccessHandler(f.successHandler)
+	}
+}
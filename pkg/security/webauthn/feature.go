@@ -0,0 +1,118 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webauthn
+
+import (
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+)
+
+var FeatureId = security.FeatureId("WebAuthn", security.FeatureOrderWebAuthn)
+
+// Feature configures WebAuthn credential registration, passwordless login and WebAuthn as passwd MFA second factor.
+// Options and registration endpoints exchange JSON and expect CSRF token in header;
+// login and MFA endpoints expect form post with the assertion JSON as a form parameter
+type Feature struct {
+	successHandler         security.AuthenticationSuccessHandler
+	registrationOptionsUrl string
+	registrationUrl        string
+	loginOptionsUrl        string
+	loginUrl               string
+	usernameParam          string
+	credentialParam        string
+
+	mfaEnabled    bool
+	mfaOptionsUrl string
+	mfaVerifyUrl  string
+}
+
+func (f *Feature) Identifier() security.FeatureIdentifier {
+	return FeatureId
+}
+
+func (f *Feature) RegistrationOptionsUrl(url string) *Feature {
+	f.registrationOptionsUrl = url
+	return f
+}
+
+func (f *Feature) RegistrationUrl(url string) *Feature {
+	f.registrationUrl = url
+	return f
+}
+
+func (f *Feature) LoginOptionsUrl(url string) *Feature {
+	f.loginOptionsUrl = url
+	return f
+}
+
+func (f *Feature) LoginUrl(url string) *Feature {
+	f.loginUrl = url
+	return f
+}
+
+func (f *Feature) UsernameParameter(param string) *Feature {
+	f.usernameParam = param
+	return f
+}
+
+func (f *Feature) CredentialParameter(param string) *Feature {
+	f.credentialParam = param
+	return f
+}
+
+func (f *Feature) SuccessHandler(handler security.AuthenticationSuccessHandler) *Feature {
+	f.successHandler = handler
+	return f
+}
+
+// EnableMFA allows WebAuthn assertion as second factor of passwd MFA
+func (f *Feature) EnableMFA() *Feature {
+	f.mfaEnabled = true
+	return f
+}
+
+func (f *Feature) MfaOptionsUrl(url string) *Feature {
+	f.mfaOptionsUrl = url
+	return f
+}
+
+func (f *Feature) MfaVerifyUrl(url string) *Feature {
+	f.mfaVerifyUrl = url
+	return f
+}
+
+func Configure(ws security.WebSecurity) *Feature {
+	feature := New()
+	if fc, ok := ws.(security.FeatureModifier); ok {
+		return fc.Enable(feature).(*Feature)
+	}
+	panic(fmt.Errorf("unable to configure webauthn: provided WebSecurity [%T] doesn't support FeatureModifier", ws))
+}
+
+// New is Standard security.Feature entrypoint, DSL style. Used with security.WebSecurity
+func New() *Feature {
+	return &Feature{
+		registrationOptionsUrl: "/webauthn/register/options",
+		registrationUrl:        "/webauthn/register",
+		loginOptionsUrl:        "/login/webauthn/options",
+		loginUrl:               "/login/webauthn",
+		usernameParam:          "username",
+		credentialParam:        "credential",
+		mfaOptionsUrl:          "/login/mfa/webauthn/options",
+		mfaVerifyUrl:           "/login/mfa/webauthn",
+	}
+}
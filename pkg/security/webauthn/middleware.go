@@ -0,0 +1,335 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webauthn
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/passwd"
+	"github.com/cisco-open/go-lanai/pkg/security/session"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	sessionKeyRegistration = "WebAuthnRegistration"
+	sessionKeyLogin        = "WebAuthnLogin"
+	sessionKeyMFA          = "WebAuthnMFA"
+)
+
+const (
+	registrationNameParam = "name"
+)
+
+// ceremonyState is kept in session between "options" and "finish" requests
+type ceremonyState struct {
+	Username string               `json:"username"`
+	Data     webauthn.SessionData `json:"data"`
+}
+
+// CredentialInfo is the registration result
+type CredentialInfo struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type WebAuthnMiddleware struct {
+	manager         *CeremonyManager
+	accountStore    security.AccountStore
+	authenticator   security.Authenticator
+	successHandler  security.AuthenticationSuccessHandler
+	usernameParam   string
+	credentialParam string
+}
+
+type MWOptionsFunc func(*MWOptions)
+
+type MWOptions struct {
+	Manager         *CeremonyManager
+	AccountStore    security.AccountStore
+	Authenticator   security.Authenticator
+	SuccessHandler  security.AuthenticationSuccessHandler
+	UsernameParam   string
+	CredentialParam string
+}
+
+func NewWebAuthnMiddleware(optionFuncs ...MWOptionsFunc) *WebAuthnMiddleware {
+	opts := MWOptions{}
+	for _, fn := range optionFuncs {
+		if fn != nil {
+			fn(&opts)
+		}
+	}
+	return &WebAuthnMiddleware{
+		manager:         opts.Manager,
+		accountStore:    opts.AccountStore,
+		authenticator:   opts.Authenticator,
+		successHandler:  opts.SuccessHandler,
+		usernameParam:   opts.UsernameParam,
+		credentialParam: opts.CredentialParam,
+	}
+}
+
+// RegistrationOptionsHandlerFunc responds PublicKeyCredentialCreationOptions for current authenticated user
+func (mw *WebAuthnMiddleware) RegistrationOptionsHandlerFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		acct, e := mw.currentAccount(c)
+		if e != nil {
+			mw.handleError(c, e)
+			return
+		}
+		creation, data, e := mw.manager.BeginRegistration(c, acct)
+		if e != nil {
+			mw.handleError(c, e)
+			return
+		}
+		if e := saveCeremonyState(c, sessionKeyRegistration, acct.Username(), data); e != nil {
+			mw.handleError(c, e)
+			return
+		}
+		c.JSON(http.StatusOK, creation)
+	}
+}
+
+// RegistrationHandlerFunc verifies the attestation JSON in request body and registers the new credential
+func (mw *WebAuthnMiddleware) RegistrationHandlerFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		acct, e := mw.currentAccount(c)
+		if e != nil {
+			mw.handleError(c, e)
+			return
+		}
+		state, e := loadCeremonyState(c, sessionKeyRegistration)
+		if e != nil || state.Username != acct.Username() {
+			mw.handleError(c, web.NewBadRequestError(errors.New("registration ceremony is not in progress")))
+			return
+		}
+		cred, e := mw.manager.FinishRegistration(c, acct, state.Data, c.Request.Body, c.Query(registrationNameParam))
+		if e != nil {
+			mw.handleError(c, web.NewBadRequestError(e))
+			return
+		}
+		c.JSON(http.StatusCreated, &CredentialInfo{
+			Id:        base64.RawURLEncoding.EncodeToString(cred.ID),
+			Name:      cred.Name,
+			CreatedAt: cred.CreatedAt,
+		})
+	}
+}
+
+// LoginOptionsHandlerFunc responds PublicKeyCredentialRequestOptions for passwordless login.
+// If username is not provided, the options are for discoverable credentials
+func (mw *WebAuthnMiddleware) LoginOptionsHandlerFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		username := strings.TrimSpace(c.PostForm(mw.usernameParam))
+		assertion, data, e := mw.manager.BeginLogin(c, username)
+		if e != nil {
+			mw.handleError(c, e)
+			return
+		}
+		if e := saveCeremonyState(c, sessionKeyLogin, username, data); e != nil {
+			mw.handleError(c, e)
+			return
+		}
+		c.JSON(http.StatusOK, assertion)
+	}
+}
+
+// LoginHandlerFunc verifies the assertion JSON in form parameter and authenticate the user
+func (mw *WebAuthnMiddleware) LoginHandlerFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		state, e := loadCeremonyState(c, sessionKeyLogin)
+		if e != nil {
+			mw.handleAuthError(c, security.NewBadCredentialsError(MessageInvalidAssertion, e))
+			return
+		}
+		parsed, e := mw.parseAssertion(c)
+		if e != nil {
+			mw.handleAuthError(c, security.NewBadCredentialsError(MessageInvalidAssertion, e))
+			return
+		}
+
+		before := security.Get(c)
+		candidate := AssertionCandidate{
+			Username:   state.Username,
+			Session:    state.Data,
+			Response:   parsed,
+			DetailsMap: map[string]interface{}{},
+		}
+		auth, e := mw.authenticator.Authenticate(c, &candidate)
+		if e != nil {
+			mw.handleAuthError(c, e)
+			return
+		}
+		mw.handleSuccess(c, before, auth)
+	}
+}
+
+// MfaOptionsHandlerFunc responds PublicKeyCredentialRequestOptions for user with pending MFA
+func (mw *WebAuthnMiddleware) MfaOptionsHandlerFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		current, e := mw.currentMfaAuth(c)
+		if e != nil {
+			mw.handleError(c, e)
+			return
+		}
+		assertion, data, e := mw.manager.BeginLogin(c, current.Username())
+		if e != nil {
+			mw.handleError(c, e)
+			return
+		}
+		if e := saveCeremonyState(c, sessionKeyMFA, current.Username(), data); e != nil {
+			mw.handleError(c, e)
+			return
+		}
+		c.JSON(http.StatusOK, assertion)
+	}
+}
+
+// MfaVerifyHandlerFunc verifies the assertion JSON in form parameter and complete pending MFA
+func (mw *WebAuthnMiddleware) MfaVerifyHandlerFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		current, e := mw.currentMfaAuth(c)
+		if e != nil {
+			mw.handleError(c, e)
+			return
+		}
+		state, e := loadCeremonyState(c, sessionKeyMFA)
+		if e == nil && state.Username != current.Username() {
+			e = errors.New("username mismatch")
+		}
+		if e != nil {
+			mw.handleError(c, security.NewBadCredentialsError(MessageInvalidAssertion, e))
+			return
+		}
+		parsed, e := mw.parseAssertion(c)
+		if e != nil {
+			mw.handleError(c, security.NewBadCredentialsError(MessageInvalidAssertion, e))
+			return
+		}
+
+		candidate := MFAAssertionCandidate{
+			CurrentAuth: current,
+			Session:     state.Data,
+			Response:    parsed,
+			DetailsMap:  map[string]interface{}{},
+		}
+		auth, e := mw.authenticator.Authenticate(c, &candidate)
+		if e != nil {
+			mw.handleError(c, e)
+			return
+		}
+		mw.handleSuccess(c, current, auth)
+	}
+}
+
+func (mw *WebAuthnMiddleware) parseAssertion(c *gin.Context) (*protocol.ParsedCredentialAssertionData, error) {
+	v := c.PostForm(mw.credentialParam)
+	if v == "" {
+		return nil, errors.New("missing credential")
+	}
+	return protocol.ParseCredentialRequestResponseBody(strings.NewReader(v))
+}
+
+func (mw *WebAuthnMiddleware) currentAccount(c *gin.Context) (security.Account, error) {
+	auth := security.Get(c)
+	if !security.IsFullyAuthenticated(auth) {
+		return nil, security.NewInsufficientAuthError("not authenticated")
+	}
+	if acct, ok := auth.Principal().(security.Account); ok {
+		return acct, nil
+	}
+	username, e := security.GetUsername(auth)
+	if e != nil {
+		return nil, security.NewInsufficientAuthError(e)
+	}
+	return mw.accountStore.LoadAccountByUsername(c, username)
+}
+
+func (mw *WebAuthnMiddleware) currentMfaAuth(c *gin.Context) (passwd.UsernamePasswordAuthentication, error) {
+	if current, ok := security.Get(c).(passwd.UsernamePasswordAuthentication); ok && current.IsMFAPending() {
+		return current, nil
+	}
+	return nil, security.NewAccessDeniedError("MFA is not in progress")
+}
+
+func (mw *WebAuthnMiddleware) handleSuccess(c *gin.Context, before, new security.Authentication) {
+	if new != nil {
+		security.MustSet(c, new)
+	}
+	mw.successHandler.HandleAuthenticationSuccess(c, c.Request, c.Writer, before, new)
+	if c.Writer.Written() {
+		c.Abort()
+	}
+}
+
+func (mw *WebAuthnMiddleware) handleAuthError(c *gin.Context, err error) {
+	security.MustClear(c)
+	mw.handleError(c, err)
+}
+
+func (mw *WebAuthnMiddleware) handleError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+/************************
+	Helpers
+ ************************/
+
+func saveCeremonyState(c *gin.Context, key, username string, data *webauthn.SessionData) error {
+	s := session.Get(c)
+	if s == nil {
+		return security.NewInternalError("session is not available")
+	}
+	// Note: webauthn.SessionData contains extension map, so we store it as JSON to avoid gob registrations
+	v, e := json.Marshal(&ceremonyState{
+		Username: username,
+		Data:     *data,
+	})
+	if e != nil {
+		return e
+	}
+	s.Set(key, v)
+	return nil
+}
+
+// loadCeremonyState load and remove ceremony state from session. ceremony state can only be used once
+func loadCeremonyState(c *gin.Context, key string) (*ceremonyState, error) {
+	s := session.Get(c)
+	if s == nil {
+		return nil, security.NewInternalError("session is not available")
+	}
+	v, ok := s.Get(key).([]byte)
+	if !ok {
+		return nil, errors.New("ceremony is not in progress")
+	}
+	s.Delete(key)
+	var state ceremonyState
+	if e := json.Unmarshal(v, &state); e != nil {
+		return nil, e
+	}
+	return &state, nil
+}
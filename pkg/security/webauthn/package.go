@@ -0,0 +1,76 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package webauthn provides WebAuthn (passkey / security key) registration and assertion ceremonies,
+// which can be used for passwordless login or as the second factor of passwd MFA.
+// Credentials are persisted via CredentialStore, which applications are required to provide.
+package webauthn
+
+import (
+	"encoding/gob"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"go.uber.org/fx"
+)
+
+var logger = log.New("SEC.WebAuthn")
+
+var Module = &bootstrap.Module{
+	Name:       "WebAuthn",
+	Precedence: security.MinSecurityPrecedence + 30,
+	Options: []fx.Option{
+		fx.Provide(BindWebAuthnProperties, provideCeremonyManager),
+		fx.Invoke(register),
+	},
+}
+
+func init() {
+	gob.Register((*webAuthnAuthentication)(nil))
+}
+
+func Use() {
+	bootstrap.Register(Module)
+}
+
+type managerDI struct {
+	fx.In
+	Properties WebAuthnProperties
+	Store      CredentialStore `optional:"true"`
+}
+
+// provideCeremonyManager fails if CredentialStore is not provided. Credentials must survive restarts and be shared by all instances
+func provideCeremonyManager(di managerDI) (*CeremonyManager, error) {
+	if di.Store == nil {
+		return nil, fmt.Errorf("CredentialStore is required by WebAuthn module")
+	}
+	return NewCeremonyManager(WithProperties(&di.Properties), WithCredentialStore(di.Store))
+}
+
+type initDI struct {
+	fx.In
+	SecRegistrar security.Registrar    `optional:"true"`
+	AccountStore security.AccountStore `optional:"true"`
+	Manager      *CeremonyManager
+}
+
+func register(di initDI) {
+	if di.SecRegistrar != nil {
+		configurer := newWebAuthnConfigurer(di.AccountStore, di.Manager)
+		di.SecRegistrar.(security.FeatureRegistrar).RegisterFeature(FeatureId, configurer)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webauthn
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	PropertiesPrefix = "security.webauthn"
+)
+
+type WebAuthnProperties struct {
+	// RPID is the relying party ID, typically the effective domain of the login pages
+	RPID string `json:"rp-id"`
+	// RPDisplayName is the relying party name displayed by authenticators
+	RPDisplayName string `json:"rp-display-name"`
+	// RPOrigins are the fully qualified origins allowed to perform ceremonies
+	RPOrigins []string `json:"rp-origins"`
+	// Timeout of registration and assertion ceremonies
+	Timeout utils.Duration `json:"timeout"`
+	// UserVerification is one of "required", "preferred" or "discouraged"
+	UserVerification string `json:"user-verification"`
+	// ResidentKey is one of "required", "preferred" or "discouraged". Discoverable credentials are needed for username-less login
+	ResidentKey string `json:"resident-key"`
+	// AuthenticatorAttachment is one of "platform", "cross-platform" or empty for no preference
+	AuthenticatorAttachment string                `json:"authenticator-attachment"`
+	Attestation             AttestationProperties `json:"attestation"`
+}
+
+type AttestationProperties struct {
+	// Conveyance is one of "none", "indirect", "direct" or "enterprise"
+	Conveyance string `json:"conveyance"`
+	// Required rejects credentials registered without attestation statement (attestation format "none")
+	Required bool `json:"required"`
+	// AllowedAAGUIDs limits registration to given authenticator models. Empty means any model is allowed.
+	// AAGUID is self-reported by authenticators, so TrustedRoots is required to verify the attestation certificate chain
+	AllowedAAGUIDs []string `json:"allowed-aaguids"`
+	// TrustedRoots are paths of PEM files containing trusted attestation root certificates, e.g. from FIDO metadata service
+	TrustedRoots []string `json:"trusted-roots"`
+}
+
+func NewWebAuthnProperties() *WebAuthnProperties {
+	return &WebAuthnProperties{
+		RPID:             "localhost",
+		RPDisplayName:    "localhost",
+		RPOrigins:        []string{"http://localhost:8900"},
+		Timeout:          utils.Duration(5 * time.Minute),
+		UserVerification: "preferred",
+		ResidentKey:      "preferred",
+		Attestation: AttestationProperties{
+			Conveyance: "none",
+		},
+	}
+}
+
+func BindWebAuthnProperties(ctx *bootstrap.ApplicationContext) WebAuthnProperties {
+	props := NewWebAuthnProperties()
+	if err := ctx.Config().Bind(props, PropertiesPrefix); err != nil {
+		panic(errors.Wrap(err, "failed to bind WebAuthnProperties"))
+	}
+	return *props
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webauthn
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"sync"
+	"time"
+)
+
+var (
+	ErrCredentialNotFound = errors.New("webauthn credential not found")
+)
+
+// Credential is a registered WebAuthn public key credential owned by an account
+type Credential struct {
+	webauthn.Credential
+	Username   string    `json:"username"`
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
+// CredentialStore persists WebAuthn credentials per account.
+// Credential ID is globally unique, and LoadCredentialById should return ErrCredentialNotFound when no credential is found
+type CredentialStore interface {
+	LoadCredentials(ctx context.Context, username string) ([]*Credential, error)
+	LoadCredentialById(ctx context.Context, id []byte) (*Credential, error)
+	// SaveCredential creates or updates given credential. It's also invoked after each successful assertion to update signature counter
+	SaveCredential(ctx context.Context, cred *Credential) error
+	DeleteCredential(ctx context.Context, username string, id []byte) error
+}
+
+// InMemoryCredentialStore implements CredentialStore. Credentials are lost on restart, so it's intended for tests only
+type InMemoryCredentialStore struct {
+	mtx   sync.RWMutex
+	creds []*Credential
+}
+
+func NewInMemoryCredentialStore() *InMemoryCredentialStore {
+	return &InMemoryCredentialStore{}
+}
+
+func (s *InMemoryCredentialStore) LoadCredentials(_ context.Context, username string) ([]*Credential, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	ret := make([]*Credential, 0, 2)
+	for _, cred := range s.creds {
+		if cred.Username == username {
+			cp := *cred
+			ret = append(ret, &cp)
+		}
+	}
+	return ret, nil
+}
+
+func (s *InMemoryCredentialStore) LoadCredentialById(_ context.Context, id []byte) (*Credential, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if i := s.indexOf(id); i >= 0 {
+		cp := *s.creds[i]
+		return &cp, nil
+	}
+	return nil, ErrCredentialNotFound
+}
+
+func (s *InMemoryCredentialStore) SaveCredential(_ context.Context, cred *Credential) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	cp := *cred
+	if i := s.indexOf(cred.ID); i >= 0 {
+		s.creds[i] = &cp
+	} else {
+		s.creds = append(s.creds, &cp)
+	}
+	return nil
+}
+
+func (s *InMemoryCredentialStore) DeleteCredential(_ context.Context, username string, id []byte) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	i := s.indexOf(id)
+	if i < 0 || s.creds[i].Username != username {
+		return ErrCredentialNotFound
+	}
+	s.creds = append(s.creds[:i], s.creds[i+1:]...)
+	return nil
+}
+
+func (s *InMemoryCredentialStore) indexOf(id []byte) int {
+	for i, cred := range s.creds {
+		if bytes.Equal(cred.ID, id) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,212 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package x509auth
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"time"
+)
+
+const (
+	MessageInvalidCertificate = "Invalid Client Certificate"
+	MessageAccountStatus      = "Inactive Account"
+)
+
+const (
+	DetailsKeySubjectDN = "X509SubjectDN"
+)
+
+// PrincipalExtractor extracts username from verified client certificate
+type PrincipalExtractor func(cert *x509.Certificate) (string, error)
+
+// DefaultPrincipalExtractor is the default PrincipalExtractor.
+// It prefers the subject alternative name (see SubjectAltNameExtractor) and falls back to subject's common name (CN),
+// since CN is deprecated for identity purpose and SAN is what TLS verification checks against.
+func DefaultPrincipalExtractor(cert *x509.Certificate) (string, error) {
+	if username, e := SubjectAltNameExtractor(cert); e == nil {
+		return username, nil
+	}
+	return SubjectCommonNameExtractor(cert)
+}
+
+// SubjectAltNameExtractor is a PrincipalExtractor using the first subject alternative name (SAN) as username.
+// SAN types are checked in order of URI (e.g. SPIFFE ID), email address and DNS name.
+func SubjectAltNameExtractor(cert *x509.Certificate) (string, error) {
+	switch {
+	case len(cert.URIs) != 0:
+		return cert.URIs[0].String(), nil
+	case len(cert.EmailAddresses) != 0:
+		return cert.EmailAddresses[0], nil
+	case len(cert.DNSNames) != 0:
+		return cert.DNSNames[0], nil
+	default:
+		return "", fmt.Errorf("subject alternative name is missing in certificate")
+	}
+}
+
+// SubjectCommonNameExtractor is a PrincipalExtractor using subject's common name (CN) as username
+func SubjectCommonNameExtractor(cert *x509.Certificate) (string, error) {
+	if len(cert.Subject.CommonName) == 0 {
+		return "", fmt.Errorf("common name is missing in certificate subject")
+	}
+	return cert.Subject.CommonName, nil
+}
+
+/************************
+	security.Candidate
+************************/
+
+// CertificateCandidate is the security.Candidate of verified client certificate.
+// Certificate should be the leaf certificate of a verified chain (tls.ConnectionState.VerifiedChains)
+type CertificateCandidate struct {
+	Certificate *x509.Certificate
+	DetailsMap  map[string]interface{}
+}
+
+// Principal implements security.Candidate
+func (c *CertificateCandidate) Principal() interface{} {
+	return c.Certificate.Subject.String()
+}
+
+// Credentials implements security.Candidate
+func (c *CertificateCandidate) Credentials() interface{} {
+	return c.Certificate
+}
+
+// Details implements security.Candidate
+func (c *CertificateCandidate) Details() interface{} {
+	return c.DetailsMap
+}
+
+/************************
+	security.Authentication
+************************/
+
+// x509Authentication implements security.Authentication
+// Note: all fields should not be used directly. It's exported only because gob only deal with exported field
+type x509Authentication struct {
+	Acct       security.Account
+	Username   string
+	Perms      map[string]interface{}
+	DetailsMap map[string]interface{}
+}
+
+// Principal returns security.Account if the account is loaded from security.AccountStore, otherwise returns username
+func (auth *x509Authentication) Principal() interface{} {
+	if auth.Acct != nil {
+		return auth.Acct
+	}
+	return auth.Username
+}
+
+func (auth *x509Authentication) Permissions() security.Permissions {
+	return auth.Perms
+}
+
+func (auth *x509Authentication) State() security.AuthenticationState {
+	return security.StateAuthenticated
+}
+
+func (auth *x509Authentication) Details() interface{} {
+	return auth.DetailsMap
+}
+
+/************************
+	Authenticator
+************************/
+
+type AuthenticatorOptions func(opt *AuthenticatorOption)
+
+type AuthenticatorOption struct {
+	// AccountStore is optional. When set, the account of extracted username is loaded and its permissions are used.
+	// Otherwise, the authentication carries the username only without any permissions.
+	AccountStore security.AccountStore
+	// PrincipalExtractor extracts username from certificate. Default to DefaultPrincipalExtractor
+	PrincipalExtractor PrincipalExtractor
+}
+
+// Authenticator implements security.Authenticator and authenticates CertificateCandidate.
+// The certificate chain is verified by TLS server, so Authenticator only maps certificate to user identity.
+type Authenticator struct {
+	accountStore security.AccountStore
+	extractor    PrincipalExtractor
+}
+
+func NewAuthenticator(opts ...AuthenticatorOptions) *Authenticator {
+	opt := AuthenticatorOption{
+		PrincipalExtractor: DefaultPrincipalExtractor,
+	}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	return &Authenticator{
+		accountStore: opt.AccountStore,
+		extractor:    opt.PrincipalExtractor,
+	}
+}
+
+func (a *Authenticator) Authenticate(ctx context.Context, candidate security.Candidate) (security.Authentication, error) {
+	can, ok := candidate.(*CertificateCandidate)
+	if !ok {
+		return nil, nil
+	}
+	if can.Certificate == nil {
+		return nil, security.NewBadCredentialsError(MessageInvalidCertificate)
+	}
+
+	username, e := a.extractor(can.Certificate)
+	if e != nil {
+		return nil, security.NewBadCredentialsError(MessageInvalidCertificate, e)
+	}
+
+	details := map[string]interface{}{}
+	for k, v := range can.DetailsMap {
+		details[k] = v
+	}
+	details[security.DetailsKeyAuthTime] = time.Now().UTC()
+	details[security.DetailsKeyAuthMethod] = security.AuthMethodX509
+	details[DetailsKeySubjectDN] = can.Certificate.Subject.String()
+
+	if a.accountStore == nil {
+		return &x509Authentication{
+			Username:   username,
+			Perms:      map[string]interface{}{},
+			DetailsMap: details,
+		}, nil
+	}
+
+	acct, e := a.accountStore.LoadAccountByUsername(ctx, username)
+	switch {
+	case e != nil:
+		return nil, security.NewUsernameNotFoundError(MessageInvalidCertificate, e)
+	case acct.Disabled() || acct.Locked():
+		return nil, security.NewAccountStatusError(MessageAccountStatus)
+	}
+	permissions := map[string]interface{}{}
+	for _, p := range acct.Permissions() {
+		permissions[p] = true
+	}
+	return &x509Authentication{
+		Acct:       acct,
+		Username:   acct.Username(),
+		Perms:      permissions,
+		DetailsMap: details,
+	}, nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package x509auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/sectest"
+	"github.com/cisco-open/go-lanai/test/webtest"
+	"github.com/gin-gonic/gin"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"math/big"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+/*************************
+	Setup
+ *************************/
+
+const (
+	TestUsername = "test-user"
+	TestUserId   = "test-user-id"
+	TestPerm     = "TEST_PERMISSION"
+)
+
+func newTestAccountStore() security.AccountStore {
+	return sectest.NewMockedAccountStore([]*sectest.MockedAccountProperties{
+		{UserId: TestUserId, Username: TestUsername, Perms: []string{TestPerm}},
+	})
+}
+
+/*************************
+	Test
+ *************************/
+
+func TestX509Authentication(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestWithoutAccountStore(), "WithoutAccountStore"),
+		test.GomegaSubTest(SubTestWithAccountStore(), "WithAccountStore"),
+		test.GomegaSubTest(SubTestUnknownAccount(), "UnknownAccount"),
+		test.GomegaSubTest(SubTestPrincipalExtractors(), "PrincipalExtractors"),
+		test.GomegaSubTest(SubTestMiddleware(), "Middleware"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestWithoutAccountStore() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		authenticator := NewAuthenticator()
+		auth, e := authenticator.Authenticate(ctx, &CertificateCandidate{Certificate: newTestCertificate(g, TestUsername)})
+		g.Expect(e).To(Succeed(), "authenticate should succeed")
+		g.Expect(auth.State()).To(Equal(security.StateAuthenticated))
+		g.Expect(auth.Principal()).To(Equal(TestUsername), "principal should be the certificate's CN without SAN")
+		g.Expect(auth.Permissions()).To(BeEmpty())
+		g.Expect(auth.Details()).To(HaveKeyWithValue(security.DetailsKeyAuthMethod, security.AuthMethodX509))
+		g.Expect(auth.Details()).To(HaveKeyWithValue(DetailsKeySubjectDN, "CN="+TestUsername+",O=Test"))
+
+		// unsupported candidate
+		auth, e = authenticator.Authenticate(ctx, &security.AnonymousCandidate{})
+		g.Expect(e).To(Succeed())
+		g.Expect(auth).To(BeNil(), "unsupported candidate should be ignored")
+	}
+}
+
+func SubTestWithAccountStore() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		authenticator := NewAuthenticator(func(opt *AuthenticatorOption) {
+			opt.AccountStore = newTestAccountStore()
+		})
+		auth, e := authenticator.Authenticate(ctx, &CertificateCandidate{Certificate: newTestCertificate(g, TestUsername)})
+		g.Expect(e).To(Succeed(), "authenticate should succeed")
+		g.Expect(auth.Principal()).To(BeAssignableToTypeOf(&sectest.MockedAccount{}), "principal should be the account")
+		g.Expect(auth.Permissions()).To(HaveKey(TestPerm), "permissions should be loaded from account")
+	}
+}
+
+func SubTestUnknownAccount() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		authenticator := NewAuthenticator(func(opt *AuthenticatorOption) {
+			opt.AccountStore = newTestAccountStore()
+		})
+		_, e := authenticator.Authenticate(ctx, &CertificateCandidate{Certificate: newTestCertificate(g, "unknown-user")})
+		g.Expect(e).To(HaveOccurred(), "authenticate should fail for unknown account")
+		g.Expect(e).To(BeAssignableToTypeOf(&security.CodedError{}))
+	}
+}
+
+func SubTestPrincipalExtractors() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		spiffe, e := url.Parse("spiffe://example.com/test-service")
+		g.Expect(e).To(Succeed())
+		cert := newTestCertificate(g, TestUsername, func(tmpl *x509.Certificate) {
+			tmpl.URIs = []*url.URL{spiffe}
+			tmpl.EmailAddresses = []string{"test@example.com"}
+			tmpl.DNSNames = []string{"test.example.com"}
+		})
+
+		// default prefers SAN
+		auth, e := NewAuthenticator().Authenticate(ctx, &CertificateCandidate{Certificate: cert})
+		g.Expect(e).To(Succeed(), "authenticate should succeed")
+		g.Expect(auth.Principal()).To(Equal(spiffe.String()), "principal should be the certificate's URI SAN")
+
+		// SAN order
+		username, e := SubjectAltNameExtractor(newTestCertificate(g, TestUsername, func(tmpl *x509.Certificate) {
+			tmpl.EmailAddresses = []string{"test@example.com"}
+			tmpl.DNSNames = []string{"test.example.com"}
+		}))
+		g.Expect(e).To(Succeed())
+		g.Expect(username).To(Equal("test@example.com"), "email SAN should be preferred over DNS SAN")
+
+		// configured CN
+		authenticator := NewAuthenticator(func(opt *AuthenticatorOption) {
+			opt.PrincipalExtractor = SubjectCommonNameExtractor
+		})
+		auth, e = authenticator.Authenticate(ctx, &CertificateCandidate{Certificate: cert})
+		g.Expect(e).To(Succeed(), "authenticate should succeed")
+		g.Expect(auth.Principal()).To(Equal(TestUsername), "principal should be the certificate's CN when configured")
+
+		// SAN only
+		_, e = SubjectAltNameExtractor(newTestCertificate(g, TestUsername))
+		g.Expect(e).To(HaveOccurred(), "SAN extractor should fail without SAN")
+	}
+}
+
+func SubTestMiddleware() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		mw := NewX509AuthMiddleware(NewAuthenticator(), security.NewAuthenticationSuccessHandler())
+		cert := newTestCertificate(g, TestUsername)
+
+		// verified certificate
+		gc := newTestGinContext(ctx, &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		})
+		mw.HandlerFunc()(gc)
+		g.Expect(gc.IsAborted()).To(BeFalse())
+		g.Expect(security.Get(gc).Principal()).To(Equal(TestUsername), "request should be authenticated")
+
+		// certificate not verified
+		gc = newTestGinContext(ctx, &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+		})
+		mw.HandlerFunc()(gc)
+		g.Expect(security.IsFullyAuthenticated(security.Get(gc))).To(BeFalse(), "unverified certificate should be ignored")
+
+		// no TLS
+		gc = newTestGinContext(ctx, nil)
+		mw.HandlerFunc()(gc)
+		g.Expect(security.IsFullyAuthenticated(security.Get(gc))).To(BeFalse(), "plain HTTP should be ignored")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func newTestCertificate(g *gomega.WithT, cn string, customizers ...func(tmpl *x509.Certificate)) *x509.Certificate {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(e).To(Succeed(), "generating key should not fail")
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn, Organization: []string{"Test"}},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	for _, fn := range customizers {
+		fn(&tmpl)
+	}
+	raw, e := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, key.Public(), key)
+	g.Expect(e).To(Succeed(), "creating certificate should not fail")
+	cert, e := x509.ParseCertificate(raw)
+	g.Expect(e).To(Succeed(), "parsing certificate should not fail")
+	return cert
+}
+
+func newTestGinContext(ctx context.Context, tlsState *tls.ConnectionState) *gin.Context {
+	return webtest.NewGinContext(ctx, http.MethodGet, "/", nil, func(req *http.Request) {
+		req.TLS = tlsState
+	})
+}
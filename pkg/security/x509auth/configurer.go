@@ -0,0 +1,58 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package x509auth
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/web/middleware"
+)
+
+type X509AuthConfigurer struct {
+	accountStore security.AccountStore
+}
+
+func newX509AuthConfigurer(accountStore security.AccountStore) *X509AuthConfigurer {
+	return &X509AuthConfigurer{
+		accountStore: accountStore,
+	}
+}
+
+func (c *X509AuthConfigurer) Apply(feature security.Feature, ws security.WebSecurity) error {
+	f := feature.(*Feature)
+	accountStore := f.accountStore
+	if accountStore == nil {
+		accountStore = c.accountStore
+	}
+
+	authenticator := NewAuthenticator(func(opt *AuthenticatorOption) {
+		opt.AccountStore = accountStore
+		if f.extractor != nil {
+			opt.PrincipalExtractor = f.extractor
+		}
+	})
+	x509Auth := NewX509AuthMiddleware(
+		authenticator,
+		ws.Shared(security.WSSharedKeyCompositeAuthSuccessHandler).(security.AuthenticationSuccessHandler),
+	)
+
+	mw := middleware.NewBuilder("x509 auth").
+		Order(security.MWOrderX509Auth).
+		Use(x509Auth.HandlerFunc())
+
+	ws.Add(mw)
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package x509auth
+
+import (
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+)
+
+var (
+	FeatureId = security.FeatureId("X509Auth", security.FeatureOrderX509Auth)
+)
+
+// Feature enables authentication with verified TLS client certificate.
+// The web server should be configured to request and verify client certificates. See web.TLSProperties
+type Feature struct {
+	accountStore security.AccountStore
+	extractor    PrincipalExtractor
+}
+
+// Identifier Standard security.Feature entrypoint
+func (f *Feature) Identifier() security.FeatureIdentifier {
+	return FeatureId
+}
+
+// AccountStore overrides the security.AccountStore used to load account of the certificate.
+func (f *Feature) AccountStore(store security.AccountStore) *Feature {
+	f.accountStore = store
+	return f
+}
+
+// PrincipalExtractor overrides how username is extracted from certificate. Default to DefaultPrincipalExtractor,
+// which prefers subject alternative name over common name. Use SubjectCommonNameExtractor or SubjectAltNameExtractor
+// to rely on one of them only.
+func (f *Feature) PrincipalExtractor(extractor PrincipalExtractor) *Feature {
+	f.extractor = extractor
+	return f
+}
+
+func Configure(ws security.WebSecurity) *Feature {
+	feature := New()
+	if fc, ok := ws.(security.FeatureModifier); ok {
+		return fc.Enable(feature).(*Feature)
+	}
+	panic(fmt.Errorf("unable to configure x509 auth: provided WebSecurity [%T] doesn't support FeatureModifier", ws))
+}
+
+// New Standard security.Feature entrypoint, DSL style. Used with security.WebSecurity
+func New() *Feature {
+	return &Feature{
+		extractor: DefaultPrincipalExtractor,
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package x509auth
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/gin-gonic/gin"
+)
+
+// X509AuthMiddleware authenticates the request using verified client certificate of TLS connection.
+// Requests without verified client certificate or already authenticated are passed through.
+type X509AuthMiddleware struct {
+	authenticator  security.Authenticator
+	successHandler security.AuthenticationSuccessHandler
+}
+
+func NewX509AuthMiddleware(authenticator security.Authenticator, successHandler security.AuthenticationSuccessHandler) *X509AuthMiddleware {
+	return &X509AuthMiddleware{
+		authenticator:  authenticator,
+		successHandler: successHandler,
+	}
+}
+
+func (mw *X509AuthMiddleware) HandlerFunc() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tlsState := ctx.Request.TLS
+		if tlsState == nil || len(tlsState.VerifiedChains) == 0 || len(tlsState.VerifiedChains[0]) == 0 {
+			// no verified client certificate, bail
+			return
+		}
+
+		before := security.Get(ctx)
+		if security.IsFullyAuthenticated(before) {
+			// already authenticated by other means
+			return
+		}
+
+		candidate := CertificateCandidate{
+			Certificate: tlsState.VerifiedChains[0][0],
+		}
+		auth, err := mw.authenticator.Authenticate(ctx, &candidate)
+		if err != nil {
+			security.MustClear(ctx)
+			_ = ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+
+		security.MustSet(ctx, auth)
+		mw.successHandler.HandleAuthenticationSuccess(ctx, ctx.Request, ctx.Writer, before, auth)
+		ctx.Next()
+	}
+}
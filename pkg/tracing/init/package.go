@@ -18,13 +18,17 @@ package tracing
 
 import (
 	"context"
+	"fmt"
 	"github.com/cisco-open/go-lanai/pkg/bootstrap"
 	"github.com/cisco-open/go-lanai/pkg/log"
 	"github.com/cisco-open/go-lanai/pkg/tracing"
 	"github.com/cisco-open/go-lanai/pkg/tracing/instrument"
 	jaegertracing "github.com/cisco-open/go-lanai/pkg/tracing/jaeger"
+	oteltracing "github.com/cisco-open/go-lanai/pkg/tracing/otel"
 	"github.com/opentracing/opentracing-go"
 	"go.uber.org/fx"
+	"io"
+	"strings"
 )
 
 var logger = log.New("Tracing")
@@ -97,20 +101,19 @@ func provideTracer(ctx *bootstrap.ApplicationContext, props tracing.TracingPrope
 	}
 
 	tracers := make([]opentracing.Tracer, 0, 2)
-	if props.Jaeger.Enabled {
-		tracer, closer := jaegertracing.NewTracer(ctx, &props.Jaeger, &props.Sampler)
+	switch strings.ToLower(props.Provider) {
+	case tracing.ProviderOpenTelemetry:
+		tracer, closer := oteltracing.NewTracer(ctx, &props.OTel, &props.Sampler)
 		tracers = append(tracers, tracer)
-		ret.FxHook = &fx.Hook{
-			OnStop: func(ctx context.Context) error {
-				logger.WithContext(ctx).Infof("closing Jaeger Tracer...")
-				e := closer.Close()
-				if e != nil {
-					logger.WithContext(ctx).Errorf("failed to close Jaeger Tracer: %v", e)
-				}
-				logger.WithContext(ctx).Infof("Jaeger Tracer closed")
-				return e
-			},
+		ret.FxHook = newTracerClosingHook("OpenTelemetry", closer)
+	case tracing.ProviderJaeger, "":
+		if props.Jaeger.Enabled {
+			tracer, closer := jaegertracing.NewTracer(ctx, &props.Jaeger, &props.Sampler)
+			tracers = append(tracers, tracer)
+			ret.FxHook = newTracerClosingHook("Jaeger", closer)
 		}
+	default:
+		panic(fmt.Sprintf("unsupported tracing provider [%s]", props.Provider))
 	}
 
 	if props.Zipkin.Enabled {
@@ -128,6 +131,20 @@ func provideTracer(ctx *bootstrap.ApplicationContext, props tracing.TracingPrope
 	}
 }
 
+func newTracerClosingHook(name string, closer io.Closer) TracerClosingHook {
+	return &fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			logger.WithContext(ctx).Infof("closing %s Tracer...", name)
+			e := closer.Close()
+			if e != nil {
+				logger.WithContext(ctx).Errorf("failed to close %s Tracer: %v", name, e)
+			}
+			logger.WithContext(ctx).Infof("%s Tracer closed", name)
+			return e
+		},
+	}
+}
+
 /**************************
 	Setup
 ***************************/
@@ -26,7 +26,10 @@ import (
 	"github.com/onsi/gomega"
 	. "github.com/onsi/gomega"
 	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 	"go.uber.org/fx"
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -128,6 +131,25 @@ func TestTracerWithInvalidSampler(t *testing.T) {
 	)
 }
 
+func TestOpenTelemetryTracer(t *testing.T) {
+	di := TestTracerDI{}
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		apptest.WithModules(tracinginit.Module),
+		apptest.WithProperties(
+			"tracing.provider: otel",
+			"tracing.otel.exporter.protocol: none",
+			"tracing.otel.resource-attributes.deployment.environment: test",
+			"tracing.sampler.limit-per-second: 50",
+		),
+		apptest.WithDI(&di),
+		test.Setup(SetupBootstrapTracing()),
+		test.GomegaSubTest(SubTestApplicationSpan(&di), "TestApplicationSpan"),
+		test.GomegaSubTest(SubTestW3CHttpHeadersPropagation(&di), "TestW3CHttpHeadersPropagation"),
+		test.GomegaSubTest(SubTestW3CTextMapPropagation(&di), "TestW3CTextMapPropagation"),
+	)
+}
+
 /*************************
 	Sub-Test Cases
  *************************/
@@ -142,8 +164,70 @@ func SubTestApplicationSpan(di *TestTracerDI) test.GomegaSubTestFunc {
 	}
 }
 
+func SubTestW3CHttpHeadersPropagation(di *TestTracerDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		ctx = tracing.WithTracer(di.Tracer).
+			WithOpName("client").
+			WithOptions(tracing.SpanBaggageItem("tenant", "my-tenant")).
+			ForceNewSpan(ctx)
+		traceId := tracing.TraceIdFromContext(ctx)
+		spanId := tracing.SpanIdFromContext(ctx)
+		g.Expect(traceId).To(HaveLen(32), "trace ID should be W3C compatible")
+		g.Expect(spanId).To(HaveLen(16), "span ID should be W3C compatible")
+
+		header := http.Header{}
+		span := tracing.SpanFromContext(ctx)
+		e := di.Tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+		g.Expect(e).To(Succeed(), "inject should not fail")
+		g.Expect(header.Get("traceparent")).To(Equal("00-"+traceId.(string)+"-"+spanId.(string)+"-01"), "traceparent header should be correct")
+		g.Expect(header.Get("baggage")).To(ContainSubstring("tenant=my-tenant"), "baggage header should be correct")
+		g.Expect(header.Get("b3")).To(BeEmpty(), "B3 header should not be used")
+
+		spanCtx, e := di.Tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+		g.Expect(e).To(Succeed(), "extract should not fail")
+		serverCtx := tracing.WithTracer(di.Tracer).
+			WithOpName("server").
+			WithStartOptions(ext.RPCServerOption(spanCtx)).
+			ForceNewSpan(context.Background())
+		assertChildSpan(g, serverCtx, traceId, spanId)
+		g.Expect(tracing.SpanFromContext(serverCtx).BaggageItem("tenant")).To(Equal("my-tenant"), "baggage should be propagated")
+	}
+}
+
+func SubTestW3CTextMapPropagation(di *TestTracerDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		ctx = tracing.WithTracer(di.Tracer).WithOpName("producer").ForceNewSpan(ctx)
+		traceId := tracing.TraceIdFromContext(ctx)
+		spanId := tracing.SpanIdFromContext(ctx)
+
+		carrier := opentracing.TextMapCarrier{}
+		e := di.Tracer.Inject(tracing.SpanFromContext(ctx).Context(), opentracing.TextMap, carrier)
+		g.Expect(e).To(Succeed(), "inject should not fail")
+		g.Expect(carrier).To(HaveKey("traceparent"), "traceparent should be injected")
+
+		spanCtx, e := di.Tracer.Extract(opentracing.TextMap, carrier)
+		g.Expect(e).To(Succeed(), "extract should not fail")
+		consumerCtx := tracing.WithTracer(di.Tracer).
+			WithOpName("consumer").
+			WithStartOptions(opentracing.ChildOf(spanCtx)).
+			ForceNewSpan(context.Background())
+		assertChildSpan(g, consumerCtx, traceId, spanId)
+
+		_, e = di.Tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier{})
+		g.Expect(e).To(HaveOccurred(), "extract without traceparent should fail")
+	}
+}
+
 /*************************
 	Helper
  *************************/
 
+func assertChildSpan(g *gomega.WithT, ctx context.Context, expectedTraceId, expectedParentId interface{}) {
+	g.Expect(tracing.TraceIdFromContext(ctx)).To(Equal(expectedTraceId), "child span should have same trace ID")
+	g.Expect(tracing.ParentIdFromContext(ctx)).To(Equal(expectedParentId), "child span should have correct parent ID")
+	spanId := tracing.SpanIdFromContext(ctx)
+	g.Expect(spanId).ToNot(Equal(expectedParentId), "child span should have new span ID")
+	g.Expect(strings.Trim(spanId.(string), "0")).ToNot(BeEmpty(), "child span ID should be valid")
+}
+
 
@@ -0,0 +1,109 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package oteltracing
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/tracing"
+	// jaegertracing replaces tracing.DefaultLogValuers in its init(). Importing it guarantees it's initialized first,
+	// so it's chained instead of overriding the valuers of this package
+	_ "github.com/cisco-open/go-lanai/pkg/tracing/jaeger"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// init chains OpenTelemetry aware valuers in front of existing ones, so both tracer implementations are supported
+func init() {
+	prev := tracing.DefaultLogValuers
+	tracing.DefaultLogValuers = tracing.LogValuers{
+		TraceIDValuer:  traceIdContextValuer(prev.TraceIDValuer),
+		SpanIDValuer:   spanIdContextValuer(prev.SpanIDValuer),
+		ParentIDValuer: parentIdContextValuer(prev.ParentIDValuer),
+	}
+}
+
+// bridgedSpanContext is implemented by span context created by OpenTracing bridge
+type bridgedSpanContext interface {
+	TraceID() trace.TraceID
+	SpanID() trace.SpanID
+}
+
+func traceIdContextValuer(next log.ContextValuer) log.ContextValuer {
+	return func(ctx context.Context) interface{} {
+		sc, ok := spanContextFromContext(ctx)
+		switch {
+		case !ok:
+			return next(ctx)
+		case !sc.TraceID().IsValid():
+			return ""
+		default:
+			return sc.TraceID().String()
+		}
+	}
+}
+
+func spanIdContextValuer(next log.ContextValuer) log.ContextValuer {
+	return func(ctx context.Context) interface{} {
+		sc, ok := spanContextFromContext(ctx)
+		switch {
+		case !ok:
+			return next(ctx)
+		case !sc.SpanID().IsValid():
+			return ""
+		default:
+			return sc.SpanID().String()
+		}
+	}
+}
+
+// parentIdContextValuer relies on the SDK span, which is stored in context by the bridge when opentracing.ContextWithSpan is used.
+func parentIdContextValuer(next log.ContextValuer) log.ContextValuer {
+	return func(ctx context.Context) interface{} {
+		sc, ok := spanContextFromContext(ctx)
+		if !ok {
+			return next(ctx)
+		}
+		span, ok := otelSpanFromContext(ctx).(sdktrace.ReadOnlySpan)
+		if !ok || span.SpanContext().SpanID() != sc.SpanID() || !span.Parent().SpanID().IsValid() {
+			return ""
+		}
+		return span.Parent().SpanID().String()
+	}
+}
+
+func spanContextFromContext(ctx context.Context) (bridgedSpanContext, bool) {
+	span := tracing.SpanFromContext(ctx)
+	if span == nil {
+		return nil, false
+	}
+	sc, ok := span.Context().(bridgedSpanContext)
+	return sc, ok
+}
+
+//nolint:contextcheck
+func otelSpanFromContext(ctx context.Context) trace.Span {
+	if span := trace.SpanFromContext(ctx); span.SpanContext().IsValid() {
+		return span
+	}
+	// try to get from Request's context if given context contains gin.Context
+	if gc := web.GinContext(ctx); gc != nil {
+		return trace.SpanFromContext(gc.Request.Context())
+	}
+	return nil
+}
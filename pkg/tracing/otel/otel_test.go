@@ -0,0 +1,114 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package oteltracing
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/tracing"
+	jaegertracing "github.com/cisco-open/go-lanai/pkg/tracing/jaeger"
+	. "github.com/onsi/gomega"
+	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+/*************************
+	Tests
+ *************************/
+
+func TestRateLimitingSampler(t *testing.T) {
+	g := NewWithT(t)
+	sampler := NewRateLimitingSampler(2)
+	g.Expect(countSampled(sampler, 10)).To(Equal(2), "sampled count should be limited")
+}
+
+func TestGuaranteedThroughputSampler(t *testing.T) {
+	g := NewWithT(t)
+	sampler := NewGuaranteedThroughputSampler(2, 0)
+	g.Expect(countSampled(sampler, 10)).To(Equal(2), "lower bound should be sampled")
+
+	sampler = NewGuaranteedThroughputSampler(2, 1)
+	g.Expect(countSampled(sampler, 10)).To(Equal(10), "all traces should be sampled with probability 1")
+}
+
+func TestRootSamplerSelection(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	g.Expect(newRootSampler(ctx, &tracing.SamplerProperties{Enabled: false, RateLimit: 10}).Description()).
+		To(Equal(sdktrace.NeverSample().Description()))
+	g.Expect(newRootSampler(ctx, &tracing.SamplerProperties{Enabled: true, LowestRate: 1, Probability: 0.5}).Description()).
+		To(HavePrefix("GuaranteedThroughputSampler"))
+	g.Expect(newRootSampler(ctx, &tracing.SamplerProperties{Enabled: true, Probability: 0.5}).Description()).
+		To(HavePrefix("TraceIDRatioBased"))
+	g.Expect(newRootSampler(ctx, &tracing.SamplerProperties{Enabled: true, RateLimit: 10}).Description()).
+		To(HavePrefix("RateLimitingSampler"))
+	g.Expect(newRootSampler(ctx, &tracing.SamplerProperties{Enabled: true}).Description()).
+		To(Equal(sdktrace.NeverSample().Description()))
+}
+
+func TestResourceAttributes(t *testing.T) {
+	g := NewWithT(t)
+	res := newResource(context.Background(), "test-service", &tracing.OTelProperties{
+		ResourceAttributes: map[string]interface{}{
+			"deployment": map[string]interface{}{"environment": "test"},
+			"team":       "platform",
+		},
+	})
+	attrs := map[attribute.Key]string{}
+	for _, kv := range res.Attributes() {
+		attrs[kv.Key] = kv.Value.Emit()
+	}
+	g.Expect(attrs).To(HaveKeyWithValue(attribute.Key("service.name"), "test-service"))
+	g.Expect(attrs).To(HaveKeyWithValue(attribute.Key("deployment.environment"), "test"))
+	g.Expect(attrs).To(HaveKeyWithValue(attribute.Key("team"), "platform"))
+}
+
+func TestLogValuers(t *testing.T) {
+	g := NewWithT(t)
+	otelTracer, closer := NewBridgeTracer("test-service", sdktrace.NewTracerProvider())
+	defer func() { _ = closer.Close() }()
+	jaegerTracer, jaegerCloser := jaegertracing.NewDefaultTracer()
+	defer func() { _ = jaegerCloser.Close() }()
+
+	for name, tracer := range map[string]opentracing.Tracer{"otel": otelTracer, "jaeger": jaegerTracer} {
+		ctx := tracing.WithTracer(tracer).WithOpName("test").ForceNewSpan(context.Background())
+		g.Expect(tracing.TraceIdFromContext(ctx)).ToNot(BeEmpty(), "trace ID of [%s] span should be available", name)
+		g.Expect(tracing.SpanIdFromContext(ctx)).ToNot(BeEmpty(), "span ID of [%s] span should be available", name)
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func countSampled(sampler sdktrace.Sampler, n int) (count int) {
+	for i := 0; i < n; i++ {
+		var traceId trace.TraceID
+		traceId[0], traceId[15] = byte(i+1), byte(i+1)
+		result := sampler.ShouldSample(sdktrace.SamplingParameters{
+			ParentContext: context.Background(),
+			TraceID:       traceId,
+			Name:          "test",
+		})
+		if result.Decision == sdktrace.RecordAndSample {
+			count++
+		}
+	}
+	return
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package oteltracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// newResource builds OpenTelemetry resource describing this service.
+// Attributes from OTEL_RESOURCE_ATTRIBUTES are honored, and properties take precedence over them
+func newResource(ctx context.Context, name string, op *tracing.OTelProperties) *resource.Resource {
+	attrs := []attribute.KeyValue{semconv.ServiceName(name)}
+	if bootstrap.BuildVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(bootstrap.BuildVersion))
+	}
+	attrs = appendAttributes(attrs, "", op.ResourceAttributes)
+
+	res, e := resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithFromEnv(),
+		resource.WithAttributes(attrs...),
+	)
+	if e != nil {
+		logger.WithContext(ctx).Warnf("OpenTelemetry resource is partially detected: %v", e)
+	}
+	if res == nil {
+		return resource.Default()
+	}
+	return res
+}
+
+func appendAttributes(attrs []attribute.KeyValue, prefix string, values map[string]interface{}) []attribute.KeyValue {
+	for k, v := range values {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			attrs = appendAttributes(attrs, prefix+k+".", v)
+		default:
+			attrs = append(attrs, attribute.String(prefix+k, fmt.Sprint(v)))
+		}
+	}
+	return attrs
+}
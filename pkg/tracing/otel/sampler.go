@@ -0,0 +1,127 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package oteltracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+	"math"
+)
+
+// newSampler mirrors sampling strategies of Jaeger tracer, applied on root spans only.
+// Child spans follow sampling decision of their parents, including remote parents
+func newSampler(ctx context.Context, sp *tracing.SamplerProperties) sdktrace.Sampler {
+	return sdktrace.ParentBased(newRootSampler(ctx, sp))
+}
+
+func newRootSampler(ctx context.Context, sp *tracing.SamplerProperties) sdktrace.Sampler {
+	if !sp.Enabled {
+		return sdktrace.NeverSample()
+	}
+
+	if sp.LowestRate > 0 && sp.Probability > 0 && sp.Probability <= 1.0 {
+		logger.WithContext(ctx).
+			Infof("Use GuaranteedThroughputSampler with lowest rate %.3f/s and probability %%%2.1f",
+				sp.LowestRate, sp.Probability*100)
+		return NewGuaranteedThroughputSampler(sp.LowestRate, sp.Probability)
+	}
+
+	if sp.Probability > 0 && sp.Probability <= 1.0 {
+		logger.WithContext(ctx).
+			Infof("Use TraceIDRatioBasedSampler with probability %%%2.1f", sp.Probability*100)
+		return sdktrace.TraceIDRatioBased(sp.Probability)
+	}
+
+	if sp.RateLimit > 0 {
+		logger.WithContext(ctx).
+			Infof("Use RateLimitingSampler with rate limit %.3f/s", sp.RateLimit)
+		return NewRateLimitingSampler(sp.RateLimit)
+	}
+
+	logger.WithContext(ctx).Warnf("both rate limit and probability are not valid, tracing sampling is disabled")
+	return sdktrace.NeverSample()
+}
+
+// NewRateLimitingSampler samples at most given number of traces per second
+func NewRateLimitingSampler(perSecond float64) sdktrace.Sampler {
+	return &rateLimitingSampler{
+		limiter:     newLimiter(perSecond),
+		description: fmt.Sprintf("RateLimitingSampler{%g}", perSecond),
+	}
+}
+
+// NewGuaranteedThroughputSampler samples traces with given probability,
+// and guarantees at least given number of traces per second are sampled.
+func NewGuaranteedThroughputSampler(lowestPerSecond, probability float64) sdktrace.Sampler {
+	return &guaranteedThroughputSampler{
+		probabilistic: sdktrace.TraceIDRatioBased(probability),
+		lowerBound:    newLimiter(lowestPerSecond),
+		description:   fmt.Sprintf("GuaranteedThroughputSampler{%g,%g}", lowestPerSecond, probability),
+	}
+}
+
+type rateLimitingSampler struct {
+	limiter     *rate.Limiter
+	description string
+}
+
+func (s *rateLimitingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return samplingResult(p, s.limiter.Allow())
+}
+
+func (s *rateLimitingSampler) Description() string {
+	return s.description
+}
+
+type guaranteedThroughputSampler struct {
+	probabilistic sdktrace.Sampler
+	lowerBound    *rate.Limiter
+	description   string
+}
+
+func (s *guaranteedThroughputSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.probabilistic.ShouldSample(p)
+	// lower bound budget is always consumed, same as Jaeger's GuaranteedThroughputProbabilisticSampler
+	allowed := s.lowerBound.Allow()
+	if result.Decision == sdktrace.RecordAndSample {
+		return result
+	}
+	return samplingResult(p, allowed)
+}
+
+func (s *guaranteedThroughputSampler) Description() string {
+	return s.description
+}
+
+func newLimiter(perSecond float64) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(perSecond), int(math.Max(1, math.Ceil(perSecond))))
+}
+
+func samplingResult(p sdktrace.SamplingParameters, sampled bool) sdktrace.SamplingResult {
+	decision := sdktrace.Drop
+	if sampled {
+		decision = sdktrace.RecordAndSample
+	}
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package oteltracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/tracing"
+	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel"
+	otelbridge "go.opentelemetry.io/otel/bridge/opentracing"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"io"
+	"strings"
+	"time"
+)
+
+var logger = log.New("Tracing")
+
+// NewTracer creates an opentracing.Tracer backed by OpenTelemetry SDK through the OpenTracing bridge.
+// Spans are exported via OTLP, and span context is propagated with W3C "traceparent" and "baggage" headers.
+// The bridged TracerProvider and propagator are also registered as OpenTelemetry globals,
+// so libraries instrumented with OpenTelemetry API would join the same traces.
+func NewTracer(ctx *bootstrap.ApplicationContext, op *tracing.OTelProperties, sp *tracing.SamplerProperties) (opentracing.Tracer, io.Closer) {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(newResource(ctx, ctx.Name(), op)),
+		sdktrace.WithSampler(newSampler(ctx, sp)),
+	}
+	if exporter := newExporter(ctx, &op.Exporter, sp); exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	return NewBridgeTracer(ctx.Name(), sdktrace.NewTracerProvider(opts...))
+}
+
+// NewBridgeTracer wraps given OpenTelemetry SDK TracerProvider as opentracing.Tracer.
+// The returned io.Closer flushes pending spans and shutdown the provider.
+func NewBridgeTracer(name string, provider *sdktrace.TracerProvider) (opentracing.Tracer, io.Closer) {
+	propagator := NewPropagator()
+	bridge := otelbridge.NewBridgeTracer()
+	bridge.SetTextMapPropagator(propagator)
+	bridge.SetWarningHandler(func(msg string) {
+		logger.Debugf("OpenTracing bridge: %s", strings.TrimSpace(msg))
+	})
+	wrapped := otelbridge.NewTracerProvider(bridge, provider)
+	bridge.SetOpenTelemetryTracer(wrapped.Tracer(name))
+
+	otel.SetTracerProvider(wrapped)
+	otel.SetTextMapPropagator(propagator)
+	return bridge, providerCloser{provider: provider}
+}
+
+// NewPropagator returns W3C trace-context and baggage propagator
+func NewPropagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+func newExporter(ctx context.Context, ep *tracing.OTelExporterProperties, sp *tracing.SamplerProperties) sdktrace.SpanExporter {
+	if !sp.Enabled {
+		return nil
+	}
+
+	var client otlptrace.Client
+	switch protocol := strings.ToLower(ep.Protocol); protocol {
+	case tracing.OTLPProtocolNone:
+		return nil
+	case tracing.OTLPProtocolHTTP:
+		client = newHttpClient(ep)
+	case tracing.OTLPProtocolGRPC, "":
+		client = newGrpcClient(ep)
+	default:
+		panic(fmt.Sprintf("unsupported OTLP protocol [%s]", ep.Protocol))
+	}
+
+	exporter, e := otlptrace.New(ctx, client)
+	if e != nil {
+		panic(fmt.Sprintf("unable to create OTLP exporter: %v", e))
+	}
+	logger.WithContext(ctx).Infof("Use OTLP exporter with [%s] protocol and endpoint [%s]", ep.Protocol, ep.Endpoint)
+	return exporter
+}
+
+func newGrpcClient(ep *tracing.OTelExporterProperties) otlptrace.Client {
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithHeaders(ep.Headers),
+	}
+	if ep.Endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(ep.Endpoint))
+	}
+	if ep.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if ep.Timeout > 0 {
+		opts = append(opts, otlptracegrpc.WithTimeout(time.Duration(ep.Timeout)))
+	}
+	return otlptracegrpc.NewClient(opts...)
+}
+
+func newHttpClient(ep *tracing.OTelExporterProperties) otlptrace.Client {
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithHeaders(ep.Headers),
+	}
+	if ep.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(ep.Endpoint))
+	}
+	if ep.URLPath != "" {
+		opts = append(opts, otlptracehttp.WithURLPath(ep.URLPath))
+	}
+	if ep.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if ep.Timeout > 0 {
+		opts = append(opts, otlptracehttp.WithTimeout(time.Duration(ep.Timeout)))
+	}
+	return otlptracehttp.NewClient(opts...)
+}
+
+type providerCloser struct {
+	provider *sdktrace.TracerProvider
+}
+
+func (c providerCloser) Close() error {
+	return c.provider.Shutdown(context.Background())
+}
@@ -18,18 +18,34 @@ package tracing
 
 import (
 	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/utils"
 	"github.com/pkg/errors"
+	"time"
 )
 
 const (
 	TracingPropertiesPrefix = "tracing"
 )
 
+const (
+	ProviderJaeger        = "jaeger"
+	ProviderOpenTelemetry = "otel"
+)
+
+const (
+	OTLPProtocolGRPC = "grpc"
+	OTLPProtocolHTTP = "http"
+	OTLPProtocolNone = "none"
+)
+
 type TracingProperties struct {
-	Enabled bool              `json:"enabled"`
-	Jaeger  JaegerProperties  `json:"jaeger"`
-	Zipkin  ZipkinProperties  `json:"zipkin"`
-	Sampler SamplerProperties `json:"sampler"`
+	Enabled bool `json:"enabled"`
+	// Provider selects the tracer implementation. Supported values are "jaeger" (default) and "otel"
+	Provider string            `json:"provider"`
+	Jaeger   JaegerProperties  `json:"jaeger"`
+	Zipkin   ZipkinProperties  `json:"zipkin"`
+	OTel     OTelProperties    `json:"otel"`
+	Sampler  SamplerProperties `json:"sampler"`
 }
 
 type JaegerProperties struct {
@@ -42,6 +58,25 @@ type ZipkinProperties struct {
 	Enabled bool `json:"enabled"`
 }
 
+type OTelProperties struct {
+	Exporter OTelExporterProperties `json:"exporter"`
+	// ResourceAttributes are additional OpenTelemetry resource attributes, e.g. "deployment.environment".
+	// Nested keys are joined with "."
+	ResourceAttributes map[string]interface{} `json:"resource-attributes"`
+}
+
+type OTelExporterProperties struct {
+	// Protocol is the OTLP transport. Supported values are "grpc" (default), "http" and "none"
+	Protocol string `json:"protocol"`
+	// Endpoint is the collector's "host:port". When empty, OTLP defaults or OTEL_EXPORTER_OTLP_* env variables are used
+	Endpoint string `json:"endpoint"`
+	// URLPath is the collector's URL path, applicable to "http" protocol only
+	URLPath  string            `json:"url-path"`
+	Insecure bool              `json:"insecure"`
+	Headers  map[string]string `json:"headers"`
+	Timeout  utils.Duration    `json:"timeout"`
+}
+
 type SamplerProperties struct {
 	Enabled     bool    `json:"enabled"`
 	RateLimit   float64 `json:"limit-per-second"`
@@ -52,11 +87,18 @@ type SamplerProperties struct {
 // NewTracingProperties create a SessionProperties with default values
 func NewTracingProperties() *TracingProperties {
 	return &TracingProperties{
-		Enabled: true,
+		Enabled:  true,
+		Provider: ProviderJaeger,
 		Jaeger: JaegerProperties{
 			Enabled: true,
 		},
 		Zipkin: ZipkinProperties{},
+		OTel: OTelProperties{
+			Exporter: OTelExporterProperties{
+				Protocol: OTLPProtocolGRPC,
+				Timeout:  utils.Duration(10 * time.Second),
+			},
+		},
 		Sampler: SamplerProperties{
 			Enabled:   false,
 			RateLimit: 10.0,
@@ -95,6 +95,38 @@ func GetForwardedHostName(request *http.Request) string {
 	return host
 }
 
+// ParseIPNets parses IPs and CIDRs, e.g. trusted proxies. A single IP is parsed as a network of that IP only
+func ParseIPNets(values []string) ([]*net.IPNet, error) {
+	ret := make([]*net.IPNet, 0, len(values))
+	for _, v := range values {
+		cidr := strings.TrimSpace(v)
+		if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+			cidr = cidr + "/32"
+		} else if ip != nil {
+			cidr = cidr + "/128"
+		}
+		_, ipNet, e := net.ParseCIDR(cidr)
+		if e != nil {
+			return nil, fmt.Errorf("invalid IP or CIDR [%s]: %v", v, e)
+		}
+		ret = append(ret, ipNet)
+	}
+	return ret, nil
+}
+
+// IPNetsContain returns true if the ip is in any of given networks
+func IPNetsContain(nets []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 func AppendRedirectUrl(redirectUrl string, params map[string]string) (string, error) {
 	loc, e := url.ParseRequestURI(redirectUrl)
 	if e != nil || !loc.IsAbs() {
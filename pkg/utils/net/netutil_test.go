@@ -46,6 +46,21 @@ func TestGetForwardedHostName(t *testing.T) {
 	g.Expect(host).To(gomega.Equal("saml.ciscomsx.com"))
 }
 
+func TestParseIPNets(t *testing.T) {
+	g := gomega.NewWithT(t)
+	nets, e := ParseIPNets([]string{"10.0.0.1", " 192.168.0.0/16 ", "::1"})
+	g.Expect(e).To(Succeed(), "parsing IPs and CIDRs should not fail")
+	g.Expect(nets).To(HaveLen(3), "all values should be parsed")
+	g.Expect(IPNetsContain(nets, net.ParseIP("10.0.0.1"))).To(BeTrue(), "single IP should be contained")
+	g.Expect(IPNetsContain(nets, net.ParseIP("10.0.0.2"))).To(BeFalse(), "single IP should not cover its neighbours")
+	g.Expect(IPNetsContain(nets, net.ParseIP("192.168.1.1"))).To(BeTrue(), "IP in CIDR should be contained")
+	g.Expect(IPNetsContain(nets, net.ParseIP("::1"))).To(BeTrue(), "IPv6 should be contained")
+	g.Expect(IPNetsContain(nets, nil)).To(BeFalse(), "nil IP should not be contained")
+
+	_, e = ParseIPNets([]string{"not-an-ip"})
+	g.Expect(e).To(HaveOccurred(), "invalid value should fail")
+}
+
 func TestGetIP(t *testing.T) {
 	g := gomega.NewWithT(t)
 	ifaces, e := net.Interfaces()
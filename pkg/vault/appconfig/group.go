@@ -22,6 +22,7 @@ import (
 	appconfiginit "github.com/cisco-open/go-lanai/pkg/appconfig/init"
 	"github.com/cisco-open/go-lanai/pkg/bootstrap"
 	"github.com/cisco-open/go-lanai/pkg/vault"
+	"time"
 )
 
 type ProviderGroupOptions func(opt *ProviderGroupOption)
@@ -33,6 +34,9 @@ type ProviderGroupOption struct {
 	Path             string
 	ProfileSeparator string
 	VaultClient      *vault.Client
+	// Watch enables polling secrets for changes, see PollInterval
+	Watch        bool
+	PollInterval time.Duration
 }
 
 // NewProviderGroup create a Vault KV engine backed appconfig.ProviderGroup.
@@ -50,6 +54,7 @@ func NewProviderGroup(opts ...ProviderGroupOptions) (appconfig.ProviderGroup, er
 		BackendVersion:   DefaultBackendVersion,
 		Path:             DefaultConfigPath,
 		ProfileSeparator: DefaultProfileSeparator,
+		PollInterval:     DefaultPollInterval,
 	}
 	for _, fn := range opts {
 		fn(&opt)
@@ -68,7 +73,11 @@ func NewProviderGroup(opts ...ProviderGroupOptions) (appconfig.ProviderGroup, er
 		return fmt.Sprintf("%s%s%s", opt.Path, opt.ProfileSeparator, profile)
 	}
 	group.CreateFunc = func(name string, order int, _ bootstrap.ApplicationConfig) appconfig.Provider {
-		return NewVaultKvProvider(order, name, kvSecretEngine)
+		var providerOpts []KvProviderOptions
+		if opt.Watch {
+			providerOpts = append(providerOpts, WithPolling(opt.PollInterval))
+		}
+		return NewVaultKvProvider(order, name, kvSecretEngine, providerOpts...)
 	}
 	return group, nil
 }
@@ -22,6 +22,7 @@ import (
     "fmt"
     "github.com/cisco-open/go-lanai/pkg/utils"
     "github.com/cisco-open/go-lanai/pkg/vault"
+    "time"
 )
 
 type KvSecretEngine interface {
@@ -29,6 +30,13 @@ type KvSecretEngine interface {
 	ListSecrets(ctx context.Context, secretPath string) (results map[string]interface{}, err error)
 }
 
+// LeasedKvSecretEngine is a KvSecretEngine that also reports lease duration of the secrets.
+// Lease duration is used to schedule polling when watching is enabled
+type LeasedKvSecretEngine interface {
+	KvSecretEngine
+	ListSecretsWithLease(ctx context.Context, secretPath string) (results map[string]interface{}, lease time.Duration, err error)
+}
+
 func NewKvSecretEngine(version int, backend string, client *vault.Client) (KvSecretEngine, error) {
 	switch version {
 	case 1:
@@ -82,15 +90,22 @@ as opposed to the v2 API where the response is
 }
 */
 func (engine *KvSecretEngineV1) ListSecrets(ctx context.Context, secretPath string) (results map[string]interface{}, err error) {
+	results, _, err = engine.ListSecretsWithLease(ctx, secretPath)
+	return
+}
+
+// ListSecretsWithLease implements LeasedKvSecretEngine
+func (engine *KvSecretEngineV1) ListSecretsWithLease(ctx context.Context, secretPath string) (results map[string]interface{}, lease time.Duration, err error) {
 	path := engine.ContextPath(secretPath)
 	results = make(map[string]interface{})
 	//nolint:contextcheck // false positive
 	if secrets, err := engine.client.Logical(ctx).Read(path); err != nil {
-		return nil, err
+		return nil, 0, err
 	} else if secrets != nil {
 		for key, val := range secrets.Data {
 			results[key] = utils.ParseString(val.(string))
 		}
+		lease = time.Duration(secrets.LeaseDuration) * time.Second
 	}
-	return results, nil
+	return results, lease, nil
 }
\ No newline at end of file
@@ -22,6 +22,7 @@ import (
 	"github.com/cisco-open/go-lanai/pkg/bootstrap"
 	"github.com/cisco-open/go-lanai/pkg/vault"
 	"go.uber.org/fx"
+	"time"
 )
 
 var Module = &bootstrap.Module{
@@ -54,6 +55,8 @@ func withProperties(props *VaultConfigProperties) ProviderGroupOptions {
 		opt.BackendVersion = props.BackendVersion
 		opt.Path = props.DefaultContext
 		opt.ProfileSeparator = props.ProfileSeparator
+		opt.Watch = props.Watch.Enabled
+		opt.PollInterval = time.Duration(props.Watch.PollInterval)
 	}
 }
 
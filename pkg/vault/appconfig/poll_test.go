@@ -0,0 +1,190 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package vaultappconfig_test
+
+import (
+	"context"
+	"errors"
+	vaultappconfig "github.com/cisco-open/go-lanai/pkg/vault/appconfig"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/*************************
+	Tests
+ *************************/
+
+func TestConfigPolling(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestPollingDisabled(), "PollingDisabled"),
+		test.GomegaSubTest(SubTestPollingWithLease(), "PollingWithLease"),
+		test.GomegaSubTest(SubTestPollingWithError(), "PollingWithError"),
+		test.GomegaSubTest(SubTestPollingWithoutSecrets(), "PollingWithoutSecrets"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestPollingDisabled() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		engine := NewMockedSecretEngine(time.Hour)
+		p := vaultappconfig.NewVaultKvProvider(0, "test-app", engine)
+		e := p.Watch(ctx, func() {
+			t.Errorf("notify should not be invoked")
+		})
+		g.Expect(e).To(Succeed(), "watch should return immediately when polling is disabled")
+		g.Expect(engine.Reads()).To(BeZero(), "secrets should not be polled")
+	}
+}
+
+func SubTestPollingWithLease() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		// polling interval is shortened to the lease duration
+		engine := NewMockedSecretEngine(50 * time.Millisecond)
+		p := vaultappconfig.NewVaultKvProvider(0, "test-app", engine, vaultappconfig.WithPolling(time.Hour))
+		var count int64
+		stop := StartPolling(ctx, p, &count)
+		defer stop()
+
+		g.Eventually(engine.Reads).Should(BeNumerically(">", 2), "secrets should be polled with lease duration")
+		g.Expect(atomic.LoadInt64(&count)).To(BeZero(), "notify should not be invoked without changes")
+
+		engine.Set("test.value", "changed")
+		g.Eventually(func() int64 { return atomic.LoadInt64(&count) }).
+			Should(Equal(int64(1)), "notify should be invoked when secrets are changed")
+		g.Consistently(func() int64 { return atomic.LoadInt64(&count) }, 200*time.Millisecond).
+			Should(Equal(int64(1)), "notify should be invoked only once per change")
+	}
+}
+
+func SubTestPollingWithError() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		engine := NewMockedSecretEngine(0)
+		p := vaultappconfig.NewVaultKvProvider(0, "test-app", engine, vaultappconfig.WithPolling(50*time.Millisecond))
+		var count int64
+		stop := StartPolling(ctx, p, &count)
+		defer stop()
+
+		g.Eventually(engine.Reads).Should(BeNumerically(">", 1), "secrets should be polled with interval")
+		engine.SetError(errors.New("oops"))
+		reads := engine.Reads()
+		g.Eventually(engine.Reads).Should(BeNumerically(">", reads+1), "polling should continue after error")
+		g.Expect(atomic.LoadInt64(&count)).To(BeZero(), "notify should not be invoked when polling fails")
+
+		engine.Set("test.value", "changed")
+		engine.SetError(nil)
+		g.Eventually(func() int64 { return atomic.LoadInt64(&count) }).
+			Should(Equal(int64(1)), "notify should be invoked after recovered")
+	}
+}
+
+func SubTestPollingWithoutSecrets() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		// secret path doesn't exist initially
+		engine := NewMockedSecretEngine(0)
+		engine.secrets = nil
+		p := vaultappconfig.NewVaultKvProvider(0, "test-app", engine, vaultappconfig.WithPolling(50*time.Millisecond))
+		var count int64
+		stop := StartPolling(ctx, p, &count)
+		defer stop()
+
+		g.Eventually(engine.Reads).Should(BeNumerically(">", 1), "secrets should be polled with interval")
+		engine.Set("test.value", "created")
+		g.Eventually(func() int64 { return atomic.LoadInt64(&count) }).
+			Should(Equal(int64(1)), "notify should be invoked when secrets are created")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func StartPolling(ctx context.Context, p *vaultappconfig.KeyValueConfigProvider, count *int64) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = p.Watch(ctx, func() { atomic.AddInt64(count, 1) })
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// MockedSecretEngine implements vaultappconfig.LeasedKvSecretEngine
+type MockedSecretEngine struct {
+	mtx     sync.Mutex
+	secrets map[string]interface{}
+	lease   time.Duration
+	err     error
+	reads   atomic.Int64
+}
+
+func NewMockedSecretEngine(lease time.Duration) *MockedSecretEngine {
+	return &MockedSecretEngine{
+		secrets: map[string]interface{}{"test.value": "initial"},
+		lease:   lease,
+	}
+}
+
+func (e *MockedSecretEngine) Set(key string, value interface{}) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	secrets := map[string]interface{}{}
+	for k, v := range e.secrets {
+		secrets[k] = v
+	}
+	secrets[key] = value
+	e.secrets = secrets
+}
+
+func (e *MockedSecretEngine) SetError(err error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.err = err
+}
+
+func (e *MockedSecretEngine) Reads() int64 {
+	return e.reads.Load()
+}
+
+func (e *MockedSecretEngine) ContextPath(secretPath string) string {
+	return "secret/" + secretPath
+}
+
+func (e *MockedSecretEngine) ListSecrets(ctx context.Context, secretPath string) (map[string]interface{}, error) {
+	secrets, _, err := e.ListSecretsWithLease(ctx, secretPath)
+	return secrets, err
+}
+
+func (e *MockedSecretEngine) ListSecretsWithLease(_ context.Context, _ string) (map[string]interface{}, time.Duration, error) {
+	e.reads.Add(1)
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	if e.err != nil {
+		return nil, 0, e.err
+	}
+	return e.secrets, e.lease, nil
+}
@@ -16,7 +16,10 @@
 
 package vaultappconfig
 
-import "github.com/cisco-open/go-lanai/pkg/appconfig"
+import (
+	"github.com/cisco-open/go-lanai/pkg/appconfig"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+)
 
 const (
 	PropertiesPrefix = "cloud.vault.kv"
@@ -34,6 +37,14 @@ type VaultConfigProperties struct {
 	BackendVersion   int    `json:"backend-version"`
 	DefaultContext   string `json:"default-context"`
 	ProfileSeparator string `json:"profile-separator"`
+	Watch            WatchProperties `json:"watch"`
+}
+
+// WatchProperties controls whether application properties are refreshed when secrets are changed in Vault.
+// Secrets are polled with PollInterval, or with their lease duration if it's shorter
+type WatchProperties struct {
+	Enabled      bool           `json:"enabled"`
+	PollInterval utils.Duration `json:"poll-interval"`
 }
 
 func bindVaultConfigProperties(bootstrapConfig *appconfig.BootstrapConfig) VaultConfigProperties {
@@ -43,6 +54,9 @@ func bindVaultConfigProperties(bootstrapConfig *appconfig.BootstrapConfig) Vault
 		BackendVersion:   DefaultBackendVersion,
 		DefaultContext:   DefaultConfigPath,
 		ProfileSeparator: DefaultProfileSeparator,
+		Watch: WatchProperties{
+			PollInterval: utils.Duration(DefaultPollInterval),
+		},
 	}
 	if e := bootstrapConfig.Bind(&p, PropertiesPrefix); e != nil {
 		panic(e)
@@ -21,10 +21,26 @@ import (
     "fmt"
     "github.com/cisco-open/go-lanai/pkg/appconfig"
     "github.com/cisco-open/go-lanai/pkg/log"
+    "reflect"
+    "time"
 )
 
 var logger = log.New("Config.Vault")
 
+const (
+	DefaultPollInterval = time.Minute
+)
+
+type KvProviderOptions func(p *KeyValueConfigProvider)
+
+// WithPolling enables watching the secret path by polling it with given interval.
+// The interval is shortened to the lease duration of the secrets, if the lease is shorter.
+func WithPolling(interval time.Duration) KvProviderOptions {
+	return func(p *KeyValueConfigProvider) {
+		p.pollInterval = interval
+	}
+}
+
 // KeyValueConfigProvider
 //Vault kv v1 differs with v2 API both in how the context path is constructed and how the response is parsed.
 //https://www.vaultproject.io/docs/secrets/kv/kv-v1
@@ -32,6 +48,7 @@ type KeyValueConfigProvider struct {
 	appconfig.ProviderMeta
 	secretPath	string
 	secretEngine KvSecretEngine
+	pollInterval time.Duration
 }
 
 
@@ -70,10 +87,56 @@ func (p *KeyValueConfigProvider) Load(ctx context.Context) (loadError error) {
 	return nil
 }
 
-func NewVaultKvProvider(precedence int, secretPath string, secretEngine KvSecretEngine) *KeyValueConfigProvider {
-	return &KeyValueConfigProvider{
+// Watch implements appconfig.WatchableProvider. It returns immediately if polling is not enabled.
+func (p *KeyValueConfigProvider) Watch(ctx context.Context, notify func()) error {
+	if p.pollInterval <= 0 {
+		return nil
+	}
+	logger.WithContext(ctx).Debugf("Polling vault path: %s", p.secretEngine.ContextPath(p.secretPath))
+	var last map[string]interface{}
+	var polled bool
+	var delay time.Duration
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		secrets, lease, e := p.listSecrets(ctx)
+		delay = p.pollInterval
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case e != nil:
+			logger.WithContext(ctx).Warnf("Failed to poll vault path [%s], retry in %v: %v", p.secretEngine.ContextPath(p.secretPath), delay, e)
+			continue
+		case polled && !reflect.DeepEqual(last, secrets):
+			logger.WithContext(ctx).Debugf("Detected changes in vault path: %s", p.secretEngine.ContextPath(p.secretPath))
+			notify()
+		}
+		last, polled = secrets, true
+		if lease > 0 && lease < delay {
+			delay = lease
+		}
+	}
+}
+
+func (p *KeyValueConfigProvider) listSecrets(ctx context.Context) (map[string]interface{}, time.Duration, error) {
+	if engine, ok := p.secretEngine.(LeasedKvSecretEngine); ok {
+		return engine.ListSecretsWithLease(ctx, p.secretPath)
+	}
+	secrets, e := p.secretEngine.ListSecrets(ctx, p.secretPath)
+	return secrets, 0, e
+}
+
+func NewVaultKvProvider(precedence int, secretPath string, secretEngine KvSecretEngine, opts ...KvProviderOptions) *KeyValueConfigProvider {
+	p := &KeyValueConfigProvider{
 		ProviderMeta: appconfig.ProviderMeta{Precedence: precedence},
 		secretPath: secretPath,
 		secretEngine: secretEngine,
 	}
+	for _, fn := range opts {
+		fn(p)
+	}
+	return p
 }
\ No newline at end of file
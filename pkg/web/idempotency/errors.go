@@ -0,0 +1,85 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package idempotency
+
+import (
+	"errors"
+	errorutils "github.com/cisco-open/go-lanai/pkg/utils/error"
+)
+
+const (
+	// Reserved idempotency reserved error range
+	Reserved = 0x2b << errorutils.ReservedOffset
+)
+
+// All "Type" values are used as mask
+const (
+	_                        = iota
+	ErrorTypeCodeIdempotency = Reserved + iota<<errorutils.ErrorTypeOffset
+)
+
+// All "SubType" values are used as mask
+// sub types of ErrorTypeCodeIdempotency
+const (
+	_                   = iota
+	ErrorSubTypeCodeKey = ErrorTypeCodeIdempotency + iota<<errorutils.ErrorSubTypeOffset
+	ErrorSubTypeCodeConflict
+	ErrorSubTypeCodeInternal
+)
+
+// ErrorSubTypeCodeKey
+const (
+	_                    = iota
+	ErrorCodeKeyRequired = ErrorSubTypeCodeKey + iota
+	ErrorCodeKeyInvalid
+	ErrorCodeKeyReused
+)
+
+// ErrorSubTypeCodeConflict
+const (
+	_                        = iota
+	ErrorCodeRequestInFlight = ErrorSubTypeCodeConflict + iota
+	ErrorCodeLockUnavailable
+)
+
+// ErrorSubTypeCodeInternal
+const (
+	_                       = iota
+	ErrorCodeNotInitialized = ErrorSubTypeCodeInternal + iota
+	ErrorCodeStoreFailure
+)
+
+// ErrorTypes, can be used in errors.Is
+var (
+	ErrorCategoryIdempotency = errorutils.NewErrorCategory(Reserved, errors.New("error type: idempotency"))
+	ErrorTypeIdempotency     = errorutils.NewErrorType(ErrorTypeCodeIdempotency, errors.New("error type: idempotency"))
+	ErrorSubTypeKey          = errorutils.NewErrorSubType(ErrorSubTypeCodeKey, errors.New("error sub-type: idempotency key"))
+	ErrorSubTypeConflict     = errorutils.NewErrorSubType(ErrorSubTypeCodeConflict, errors.New("error sub-type: conflict"))
+	ErrorSubTypeInternal     = errorutils.NewErrorSubType(ErrorSubTypeCodeInternal, errors.New("error sub-type: internal"))
+
+	ErrKeyRequired     = errorutils.NewCodedError(ErrorCodeKeyRequired, HeaderIdempotencyKey+" header is required")
+	ErrKeyInvalid      = errorutils.NewCodedError(ErrorCodeKeyInvalid, HeaderIdempotencyKey+" header is invalid")
+	ErrKeyReused       = errorutils.NewCodedError(ErrorCodeKeyReused, HeaderIdempotencyKey+" is already used with a different request")
+	ErrRequestInFlight = errorutils.NewCodedError(ErrorCodeRequestInFlight, "a request with the same "+HeaderIdempotencyKey+" is being processed")
+	ErrLockUnavailable = errorutils.NewCodedError(ErrorCodeLockUnavailable, "unable to acquire idempotency lock")
+	ErrNotInitialized  = errorutils.NewCodedError(ErrorCodeNotInitialized, "idempotency support is not initialized")
+	ErrStoreFailure    = errorutils.NewCodedError(ErrorCodeStoreFailure, "idempotency store failure")
+)
+
+func init() {
+	errorutils.Reserve(ErrorCategoryIdempotency)
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package idempotency provides "Idempotency-Key" support for unsafe REST endpoints.
+// Endpoints opt in via rest.MappingBuilder, e.g. rest.Post("/orders").EndpointFunc(fn).Decorate(Idempotent()).Build().
+// When a request carries the header, its fingerprint and the encoded response are stored and replayed for retries.
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const (
+	HeaderIdempotencyKey = `Idempotency-Key`
+	// HeaderIdempotentReplayed is set to "true" when the response is replayed from a stored record
+	HeaderIdempotentReplayed = `Idempotent-Replayed`
+	// MaxKeyLength is the maximum accepted length of Idempotency-Key header
+	MaxKeyLength = 255
+)
+
+// Record is the stored state of an idempotency key.
+// A Record that is not Completed marks a request still in flight.
+type Record struct {
+	Fingerprint string      `json:"fingerprint"`
+	Completed   bool        `json:"completed"`
+	StatusCode  int         `json:"status,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// Store persists Record by key. Load returns nil Record without error when the key doesn't exist or is expired.
+type Store interface {
+	Load(ctx context.Context, key string) (*Record, error)
+	Save(ctx context.Context, key string, record *Record, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+type Options func(opt *Option)
+
+// Option of an idempotent endpoint
+type Option struct {
+	// Required rejects requests without Idempotency-Key header
+	Required bool
+	// TTL overrides how long the completed response is kept. Default to "server.idempotency.ttl"
+	TTL time.Duration
+}
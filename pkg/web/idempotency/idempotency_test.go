@@ -0,0 +1,296 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package idempotency_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/redis"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"github.com/cisco-open/go-lanai/pkg/web/idempotency"
+	"github.com/cisco-open/go-lanai/pkg/web/rest"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/apptest"
+	"github.com/cisco-open/go-lanai/test/embedded"
+	"github.com/cisco-open/go-lanai/test/webtest"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"go.uber.org/fx"
+	"gorm.io/gorm/schema"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/*************************
+	Setup Test
+ *************************/
+
+type StoreTestDI struct {
+	fx.In
+	ClientFactory redis.ClientFactory
+}
+
+func ProvideTestController() *TestController {
+	return &TestController{
+		started: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+}
+
+func RegisterTestController(reg *web.Registrar, c *TestController) error {
+	return reg.Register(c)
+}
+
+/*************************
+	Tests
+ *************************/
+
+func TestIdempotentEndpoint(t *testing.T) {
+	var c *TestController
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		webtest.WithMockedServer(),
+		apptest.WithModules(idempotency.Module),
+		apptest.WithFxOptions(
+			fx.Provide(ProvideTestController),
+			fx.Invoke(RegisterTestController),
+			fx.Populate(&c),
+		),
+		test.GomegaSubTest(SubTestWithoutKey(), "TestWithoutKey"),
+		test.GomegaSubTest(SubTestReplay(&c), "TestReplay"),
+		test.GomegaSubTest(SubTestKeyReused(), "TestKeyReused"),
+		test.GomegaSubTest(SubTestInvalidKey(), "TestInvalidKey"),
+		test.GomegaSubTest(SubTestKeyRequired(), "TestKeyRequired"),
+		test.GomegaSubTest(SubTestInFlight(&c), "TestInFlight"),
+	)
+}
+
+func TestRedisStore(t *testing.T) {
+	di := &StoreTestDI{}
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		embedded.WithRedis(),
+		apptest.WithModules(redis.Module),
+		apptest.WithDI(di),
+		test.GomegaSubTest(SubTestRedisStore(di), "TestSaveLoadDelete"),
+	)
+}
+
+func TestGormStoreMigration(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestMigrationMatchesModel(), "MigrationMatchesModel"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestWithoutKey() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		first := AssertCreated(ctx, g, "", `{"item":"apple"}`, false)
+		second := AssertCreated(ctx, g, "", `{"item":"apple"}`, false)
+		g.Expect(second.ID).To(Equal(first.ID+1), "requests without key should be processed every time")
+	}
+}
+
+func SubTestReplay(c **TestController) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		const key = "replay-key"
+		first := AssertCreated(ctx, g, key, `{"item":"apple"}`, false)
+		count := atomic.LoadInt64(&(*c).count)
+		second := AssertCreated(ctx, g, key, `{"item":"apple"}`, true)
+		g.Expect(second).To(Equal(first), "replayed response should be same")
+		g.Expect(atomic.LoadInt64(&(*c).count)).To(Equal(count), "endpoint should not be invoked for retry")
+	}
+}
+
+func SubTestKeyReused() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		const key = "reused-key"
+		AssertCreated(ctx, g, key, `{"item":"apple"}`, false)
+		resp := PostOrder(ctx, "/orders", key, `{"item":"orange"}`)
+		g.Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity), "status code should be correct")
+	}
+}
+
+func SubTestInvalidKey() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		resp := PostOrder(ctx, "/orders", strings.Repeat("k", idempotency.MaxKeyLength+1), `{"item":"apple"}`)
+		g.Expect(resp.StatusCode).To(Equal(http.StatusBadRequest), "status code should be correct")
+	}
+}
+
+func SubTestKeyRequired() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		resp := PostOrder(ctx, "/required/orders", "", `{"item":"apple"}`)
+		g.Expect(resp.StatusCode).To(Equal(http.StatusBadRequest), "status code should be correct")
+		resp = PostOrder(ctx, "/required/orders", "required-key", `{"item":"apple"}`)
+		g.Expect(resp.StatusCode).To(Equal(http.StatusCreated), "status code should be correct")
+	}
+}
+
+func SubTestInFlight(c **TestController) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		const key = "in-flight-key"
+		done := make(chan *http.Response, 1)
+		go func() {
+			done <- PostOrder(ctx, "/slow/orders", key, `{"item":"apple"}`)
+		}()
+		select {
+		case <-(*c).started:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("slow endpoint is not invoked")
+		}
+
+		resp := PostOrder(ctx, "/slow/orders", key, `{"item":"apple"}`)
+		g.Expect(resp.StatusCode).To(Equal(http.StatusConflict), "concurrent request should be rejected")
+
+		close((*c).release)
+		resp = <-done
+		g.Expect(resp.StatusCode).To(Equal(http.StatusCreated), "first request should succeed")
+		resp = PostOrder(ctx, "/slow/orders", key, `{"item":"apple"}`)
+		g.Expect(resp.StatusCode).To(Equal(http.StatusCreated), "retry should be replayed")
+		g.Expect(resp.Header.Get(idempotency.HeaderIdempotentReplayed)).To(Equal("true"), "retry should be replayed")
+	}
+}
+
+func SubTestRedisStore(di *StoreTestDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		client, e := di.ClientFactory.New(ctx, func(opt *redis.ClientOption) {
+			opt.DbIndex = 5
+		})
+		g.Expect(e).To(Succeed(), "creating redis client should not fail")
+		store := idempotency.NewRedisStore(client)
+
+		record, e := store.Load(ctx, "test-key")
+		g.Expect(e).To(Succeed(), "Load should not fail")
+		g.Expect(record).To(BeNil(), "record should not exist")
+
+		expected := &idempotency.Record{
+			Fingerprint: "test-fingerprint",
+			Completed:   true,
+			StatusCode:  http.StatusCreated,
+			Header:      http.Header{"Location": []string{"/orders/1"}},
+			Body:        []byte(`{"id":1}`),
+		}
+		g.Expect(store.Save(ctx, "test-key", expected, time.Minute)).To(Succeed(), "Save should not fail")
+		record, e = store.Load(ctx, "test-key")
+		g.Expect(e).To(Succeed(), "Load should not fail")
+		g.Expect(record).To(Equal(expected), "loaded record should be correct")
+
+		g.Expect(store.Delete(ctx, "test-key")).To(Succeed(), "Delete should not fail")
+		record, e = store.Load(ctx, "test-key")
+		g.Expect(e).To(Succeed(), "Load should not fail")
+		g.Expect(record).To(BeNil(), "record should be deleted")
+	}
+}
+
+func SubTestMigrationMatchesModel() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		sql, e := fs.ReadFile(idempotency.MigrationFS, "migrations/create_idempotency_records.sql")
+		g.Expect(e).To(Succeed(), "migration SQL should be embedded")
+
+		s, e := schema.Parse(&idempotency.GormRecord{}, &sync.Map{}, schema.NamingStrategy{})
+		g.Expect(e).To(Succeed(), "parsing model schema should not fail")
+		g.Expect(string(sql)).To(ContainSubstring(s.Table), "migration should create table [%s]", s.Table)
+		for _, name := range s.DBNames {
+			g.Expect(strings.Contains(string(sql), name+" ")).To(BeTrue(), "migration should have column [%s]", name)
+		}
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func PostOrder(ctx context.Context, path, key, body string) *http.Response {
+	var opts []webtest.RequestOptions
+	if key != "" {
+		opts = append(opts, webtest.Headers(idempotency.HeaderIdempotencyKey, key))
+	}
+	req := webtest.NewRequest(ctx, http.MethodPost, path, strings.NewReader(body), opts...)
+	req.Header.Set("Content-Type", "application/json")
+	return webtest.MustExec(ctx, req).Response
+}
+
+func AssertCreated(ctx context.Context, g *gomega.WithT, key, body string, expectReplayed bool) *OrderResponse {
+	resp := PostOrder(ctx, "/orders", key, body)
+	g.Expect(resp.StatusCode).To(Equal(http.StatusCreated), "status code should be correct")
+	if expectReplayed {
+		g.Expect(resp.Header.Get(idempotency.HeaderIdempotentReplayed)).To(Equal("true"), "response should be replayed")
+	} else {
+		g.Expect(resp.Header.Get(idempotency.HeaderIdempotentReplayed)).To(BeEmpty(), "response should not be replayed")
+	}
+	g.Expect(resp.Header.Get("Location")).ToNot(BeEmpty(), "Location header should be set")
+	data, e := io.ReadAll(resp.Body)
+	g.Expect(e).To(Succeed(), "reading body should not fail")
+	var ret OrderResponse
+	g.Expect(json.Unmarshal(data, &ret)).To(Succeed(), "body should be valid JSON")
+	return &ret
+}
+
+/*************************
+	Dummy Controller
+ *************************/
+
+type OrderRequest struct {
+	Item string `json:"item"`
+}
+
+type OrderResponse struct {
+	ID   int64  `json:"id"`
+	Item string `json:"item"`
+}
+
+type TestController struct {
+	count   int64
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *TestController) Mappings() []web.Mapping {
+	return []web.Mapping{
+		rest.Post("/orders").EndpointFunc(c.Create).Decorate(idempotency.Idempotent()).Build(),
+		rest.Post("/required/orders").EndpointFunc(c.Create).Decorate(idempotency.Idempotent(func(opt *idempotency.Option) {
+			opt.Required = true
+		})).Build(),
+		rest.Post("/slow/orders").EndpointFunc(c.CreateSlow).Decorate(idempotency.Idempotent()).Build(),
+	}
+}
+
+func (c *TestController) Create(_ context.Context, req *OrderRequest) (interface{}, error) {
+	id := atomic.AddInt64(&c.count, 1)
+	return &web.Response{
+		SC: http.StatusCreated,
+		H:  http.Header{"Location": []string{fmt.Sprintf("/orders/%d", id)}},
+		B:  &OrderResponse{ID: id, Item: req.Item},
+	}, nil
+}
+
+func (c *TestController) CreateSlow(ctx context.Context, req *OrderRequest) (interface{}, error) {
+	c.started <- struct{}{}
+	<-c.release
+	return c.Create(ctx, req)
+}
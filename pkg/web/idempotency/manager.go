@@ -0,0 +1,142 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/dsync"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// lockStripes bounds the number of local mutexes and distributed locks used for check-and-mark of idempotency keys
+const lockStripes = 64
+
+type ManagerOptions func(opt *ManagerOption)
+
+type ManagerOption struct {
+	Store Store
+	// SyncManager is used to guard check-and-mark across instances. Only local locks are used if nil
+	SyncManager dsync.SyncManager
+	// TTL is how long completed responses are kept
+	TTL time.Duration
+	// InFlightTimeout is how long an in-flight mark is kept, in case the processing instance died
+	InFlightTimeout time.Duration
+	// LockTimeout is how long to wait for the distributed lock
+	LockTimeout time.Duration
+}
+
+// Manager coordinates idempotency keys. Keys are claimed with an in-flight Record under a lock,
+// so concurrent requests with the same key on any instance get ErrRequestInFlight.
+type Manager struct {
+	store           Store
+	syncManager     dsync.SyncManager
+	ttl             time.Duration
+	inFlightTimeout time.Duration
+	lockTimeout     time.Duration
+	mutexes         [lockStripes]sync.Mutex
+}
+
+func NewManager(opts ...ManagerOptions) *Manager {
+	opt := ManagerOption{
+		TTL:             24 * time.Hour,
+		InFlightTimeout: time.Minute,
+		LockTimeout:     5 * time.Second,
+	}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	if opt.Store == nil {
+		opt.Store = NewInMemoryStore()
+	}
+	return &Manager{
+		store:           opt.Store,
+		syncManager:     opt.SyncManager,
+		ttl:             opt.TTL,
+		inFlightTimeout: opt.InFlightTimeout,
+		lockTimeout:     opt.LockTimeout,
+	}
+}
+
+// Begin claims the key for a request with given fingerprint.
+// It returns the completed Record if the request was processed before, or nil if the caller should process the request.
+// ErrKeyReused is returned if the key was used with a different fingerprint, ErrRequestInFlight if the key is claimed by another request.
+func (m *Manager) Begin(ctx context.Context, key, fingerprint string) (*Record, error) {
+	unlock, e := m.lock(ctx, key)
+	if e != nil {
+		return nil, e
+	}
+	defer unlock()
+
+	record, e := m.store.Load(ctx, key)
+	switch {
+	case e != nil:
+		return nil, e
+	case record == nil:
+		return nil, m.store.Save(ctx, key, &Record{Fingerprint: fingerprint}, m.inFlightTimeout)
+	case record.Fingerprint != fingerprint:
+		return nil, ErrKeyReused
+	case !record.Completed:
+		return nil, ErrRequestInFlight
+	default:
+		return record, nil
+	}
+}
+
+// Complete stores the response of a claimed key. ttl <= 0 means the Manager's default
+func (m *Manager) Complete(ctx context.Context, key string, record *Record, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = m.ttl
+	}
+	record.Completed = true
+	return m.store.Save(ctx, key, record, ttl)
+}
+
+// Abort releases a claimed key, so the request can be retried
+func (m *Manager) Abort(ctx context.Context, key string) error {
+	return m.store.Delete(ctx, key)
+}
+
+func (m *Manager) lock(ctx context.Context, key string) (unlock func(), err error) {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	stripe := h.Sum32() % lockStripes
+	mtx := &m.mutexes[stripe]
+	mtx.Lock()
+	if m.syncManager == nil {
+		return mtx.Unlock, nil
+	}
+
+	lock, e := m.syncManager.Lock(fmt.Sprintf("idempotency-%d", stripe))
+	if e != nil {
+		mtx.Unlock()
+		return nil, ErrLockUnavailable.WithCause(e, "unable to acquire idempotency lock: %v", e)
+	}
+	lockCtx, cancel := context.WithTimeout(ctx, m.lockTimeout)
+	defer cancel()
+	if e := lock.Lock(lockCtx); e != nil {
+		_ = lock.Release()
+		mtx.Unlock()
+		return nil, ErrLockUnavailable.WithCause(e, "unable to acquire idempotency lock: %v", e)
+	}
+	return func() {
+		_ = lock.Release()
+		mtx.Unlock()
+	}, nil
+}
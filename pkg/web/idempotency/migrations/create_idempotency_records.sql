@@ -0,0 +1,13 @@
+-- Table idempotency_records for stored responses of requests with Idempotency-Key
+CREATE TABLE IF NOT EXISTS idempotency_records
+(
+    idempotency_key TEXT        NOT NULL,
+    fingerprint     TEXT        NOT NULL,
+    completed       BOOL        NOT NULL DEFAULT false,
+    status_code     INT,
+    header          BYTEA,
+    body            BYTEA,
+    expires_at      timestamptz NOT NULL,
+    CONSTRAINT "primary" PRIMARY KEY (idempotency_key),
+    INDEX idx_idempotency_records_expires_at (expires_at)
+);
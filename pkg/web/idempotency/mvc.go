@@ -0,0 +1,279 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"github.com/cisco-open/go-lanai/pkg/web/rest"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// Idempotent returns rest.MappingDecorator that enables "Idempotency-Key" header support.
+// Retries with the same key and payload get the stored response. Requires Module. See NewMvcMapping for details.
+// e.g. rest.Post("/orders").EndpointFunc(fn).Decorate(idempotency.Idempotent()).Build()
+func Idempotent(opts ...Options) rest.MappingDecorator {
+	return func(m web.MvcMapping) web.MvcMapping {
+		return NewMvcMapping(m, opts...)
+	}
+}
+
+// NewMvcMapping decorates given web.MvcMapping with Idempotency-Key support.
+// It's recommended to use Idempotent with rest.MappingBuilder instead of this function.
+//
+// Keys are scoped by mapping name and current username. Only successfully encoded responses are stored,
+// failed requests release the key so the client may retry.
+func NewMvcMapping(m web.MvcMapping, opts ...Options) web.MvcMapping {
+	opt := Option{}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	return &mvcMapping{
+		MvcMapping: m,
+		option:     opt,
+	}
+}
+
+// replayed is passed through handler and encoder when the request is a retry of a completed one
+type replayed struct {
+	record *Record
+}
+
+// inFlight is passed through handler and encoder when the request claimed the key
+type inFlight struct {
+	manager     *Manager
+	key         string
+	fingerprint string
+	payload     interface{}
+}
+
+type mvcMapping struct {
+	web.MvcMapping
+	option Option
+}
+
+func (m *mvcMapping) DecodeRequestFunc() web.DecodeRequestFunc {
+	decode := m.MvcMapping.DecodeRequestFunc()
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		key := r.Header.Get(HeaderIdempotencyKey)
+		switch {
+		case key == "" && m.option.Required:
+			return nil, web.NewHttpError(http.StatusBadRequest, ErrKeyRequired)
+		case key == "":
+			return decode(ctx, r)
+		case !isValidKey(key):
+			return nil, web.NewHttpError(http.StatusBadRequest, ErrKeyInvalid)
+		case manager == nil:
+			return nil, web.NewHttpError(http.StatusInternalServerError,
+				ErrNotInitialized.WithMessage("idempotency support is not initialized. Hint: add 'idempotency.Use()' in main()"))
+		}
+
+		fingerprint, e := requestFingerprint(r)
+		if e != nil {
+			return nil, web.NewHttpError(http.StatusBadRequest, e)
+		}
+		mgr := manager
+		scopedKey := m.scopedKey(ctx, key)
+		record, e := mgr.Begin(ctx, scopedKey, fingerprint)
+		switch {
+		case e != nil:
+			return nil, translateError(e)
+		case record != nil:
+			return &replayed{record: record}, nil
+		}
+
+		payload, e := decode(ctx, r)
+		if e != nil {
+			m.abort(ctx, mgr, scopedKey)
+			return nil, e
+		}
+		return &inFlight{manager: mgr, key: scopedKey, fingerprint: fingerprint, payload: payload}, nil
+	}
+}
+
+func (m *mvcMapping) HandlerFunc() web.MvcHandlerFunc {
+	handle := m.MvcMapping.HandlerFunc()
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		switch v := request.(type) {
+		case *replayed:
+			return v, nil
+		case *inFlight:
+			resp, e := handle(ctx, v.payload)
+			if e != nil {
+				m.abort(ctx, v.manager, v.key)
+				return nil, e
+			}
+			v.payload = resp
+			return v, nil
+		default:
+			return handle(ctx, request)
+		}
+	}
+}
+
+func (m *mvcMapping) EncodeResponseFunc() web.EncodeResponseFunc {
+	encode := m.MvcMapping.EncodeResponseFunc()
+	return func(ctx context.Context, rw http.ResponseWriter, response interface{}) error {
+		switch v := response.(type) {
+		case *replayed:
+			return writeRecord(rw, v.record)
+		case *inFlight:
+			recorder := newResponseRecorder(rw)
+			if e := encode(ctx, recorder, v.payload); e != nil {
+				m.abort(ctx, v.manager, v.key)
+				return e
+			}
+			if e := v.manager.Complete(ctx, v.key, recorder.Record(v.fingerprint), m.option.TTL); e != nil {
+				logger.WithContext(ctx).Warnf("unable to store response of %s [%s]: %v", HeaderIdempotencyKey, v.key, e)
+			}
+			return nil
+		default:
+			return encode(ctx, rw, response)
+		}
+	}
+}
+
+func (m *mvcMapping) abort(ctx context.Context, mgr *Manager, key string) {
+	if e := mgr.Abort(ctx, key); e != nil {
+		logger.WithContext(ctx).Warnf("unable to release %s [%s]: %v", HeaderIdempotencyKey, key, e)
+	}
+}
+
+// scopedKey hashes the key with mapping name and username, so the same key from different users or on different
+// endpoints don't collide
+func (m *mvcMapping) scopedKey(ctx context.Context, key string) string {
+	var username string
+	if auth := security.Get(ctx); auth.State() >= security.StatePrincipalKnown {
+		username, _ = security.GetUsername(auth)
+	}
+	h := sha256.New()
+	for _, v := range []string{m.Name(), username, key} {
+		_, _ = h.Write([]byte(v))
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+/**********************
+	Helpers
+ **********************/
+
+func isValidKey(key string) bool {
+	if len(key) > MaxKeyLength {
+		return false
+	}
+	for _, c := range []byte(key) {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// requestFingerprint hashes method, URI and body. The body is restored for subsequent decoding
+func requestFingerprint(r *http.Request) (string, error) {
+	h := sha256.New()
+	_, _ = h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	if r.Body != nil && r.Body != http.NoBody {
+		body, e := io.ReadAll(r.Body)
+		_ = r.Body.Close()
+		if e != nil {
+			return "", e
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		_, _ = h.Write(body)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func translateError(err error) error {
+	switch {
+	case errors.Is(err, ErrKeyReused):
+		return web.NewHttpError(http.StatusUnprocessableEntity, err)
+	case errors.Is(err, ErrRequestInFlight):
+		return web.NewHttpError(http.StatusConflict, err)
+	case errors.Is(err, ErrLockUnavailable):
+		return web.NewHttpError(http.StatusServiceUnavailable, err)
+	default:
+		return web.NewHttpError(http.StatusInternalServerError, err)
+	}
+}
+
+func writeRecord(rw http.ResponseWriter, record *Record) error {
+	for k, v := range record.Header {
+		rw.Header()[k] = v
+	}
+	rw.Header().Set(HeaderIdempotentReplayed, "true")
+	rw.WriteHeader(record.StatusCode)
+	_, e := rw.Write(record.Body)
+	return e
+}
+
+// responseRecorder captures status code, body and headers set by the response encoder
+type responseRecorder struct {
+	http.ResponseWriter
+	original   http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func newResponseRecorder(rw http.ResponseWriter) *responseRecorder {
+	return &responseRecorder{
+		ResponseWriter: rw,
+		original:       rw.Header().Clone(),
+	}
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	r.statusCode = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.statusCode == 0 {
+		r.statusCode = http.StatusOK
+	}
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
+
+// Record returns the captured response. Only headers added or changed by the encoder are included
+func (r *responseRecorder) Record(fingerprint string) *Record {
+	header := http.Header{}
+	for k, v := range r.Header() {
+		if orig, ok := r.original[k]; !ok || !reflect.DeepEqual(orig, v) {
+			header[k] = v
+		}
+	}
+	sc := r.statusCode
+	if sc == 0 {
+		sc = http.StatusOK
+	}
+	return &Record{
+		Fingerprint: fingerprint,
+		StatusCode:  sc,
+		Header:      header,
+		Body:        r.body.Bytes(),
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package idempotency
+
+import (
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/dsync"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/redis"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+var logger = log.New("Idempotency")
+
+var manager *Manager
+
+var Module = &bootstrap.Module{
+	Name:       "idempotency",
+	Precedence: web.MinWebPrecedence + 1,
+	Options: []fx.Option{
+		fx.Provide(BindIdempotencyProperties, provideManager),
+		fx.Invoke(initialize),
+	},
+}
+
+// Use Allow service to include this module in main()
+func Use() {
+	bootstrap.Register(Module)
+}
+
+type managerDI struct {
+	fx.In
+	AppCtx        *bootstrap.ApplicationContext
+	Properties    IdempotencyProperties
+	Store         Store               `optional:"true"`
+	SyncManager   dsync.SyncManager   `optional:"true"`
+	ClientFactory redis.ClientFactory `optional:"true"`
+	GormDB        *gorm.DB            `optional:"true"`
+}
+
+func provideManager(di managerDI) (*Manager, error) {
+	store := di.Store
+	if store == nil {
+		var e error
+		if store, e = newStore(di); e != nil {
+			return nil, e
+		}
+	}
+	if di.SyncManager == nil {
+		logger.Infof("dsync.SyncManager is not available, idempotency keys are only locked within current instance")
+	}
+	return NewManager(func(opt *ManagerOption) {
+		opt.Store = store
+		opt.SyncManager = di.SyncManager
+		opt.TTL = time.Duration(di.Properties.TTL)
+		opt.InFlightTimeout = time.Duration(di.Properties.InFlightTimeout)
+		opt.LockTimeout = time.Duration(di.Properties.LockTimeout)
+	}), nil
+}
+
+func newStore(di managerDI) (Store, error) {
+	switch StoreType(strings.ToLower(string(di.Properties.Store))) {
+	case StoreMemory, "":
+		return NewInMemoryStore(), nil
+	case StoreRedis:
+		if di.ClientFactory == nil {
+			return nil, fmt.Errorf("idempotency store is [%s], but redis is not available", di.Properties.Store)
+		}
+		client, e := di.ClientFactory.New(di.AppCtx, func(opt *redis.ClientOption) {
+			opt.DbIndex = di.Properties.DbIndex
+		})
+		if e != nil {
+			return nil, e
+		}
+		return NewRedisStore(client), nil
+	case StoreData:
+		if di.GormDB == nil {
+			return nil, fmt.Errorf("idempotency store is [%s], but data is not available", di.Properties.Store)
+		}
+		return NewGormStore(di.GormDB), nil
+	default:
+		return nil, fmt.Errorf("unsupported idempotency store [%s]", di.Properties.Store)
+	}
+}
+
+func initialize(m *Manager) {
+	// set global variable
+	manager = m
+}
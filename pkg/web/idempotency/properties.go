@@ -0,0 +1,68 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package idempotency
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	PropertiesPrefix = "server.idempotency"
+)
+
+const (
+	StoreMemory StoreType = "memory"
+	StoreRedis  StoreType = "redis"
+	StoreData   StoreType = "data"
+)
+
+// StoreType is where idempotency records are kept. StoreRedis and StoreData share records across all instances of the service
+type StoreType string
+
+type IdempotencyProperties struct {
+	Store StoreType `json:"store"`
+	// DbIndex is the Redis DB index when Store is "redis"
+	DbIndex int `json:"db-index"`
+	// TTL is how long completed responses are kept for replay
+	TTL utils.Duration `json:"ttl"`
+	// InFlightTimeout is how long a key stays claimed if the processing instance never completes the request
+	InFlightTimeout utils.Duration `json:"in-flight-timeout"`
+	// LockTimeout is how long to wait for the distributed lock when claiming a key
+	LockTimeout utils.Duration `json:"lock-timeout"`
+}
+
+// NewIdempotencyProperties create a IdempotencyProperties with default values
+func NewIdempotencyProperties() *IdempotencyProperties {
+	return &IdempotencyProperties{
+		Store:           StoreMemory,
+		TTL:             utils.Duration(24 * time.Hour),
+		InFlightTimeout: utils.Duration(time.Minute),
+		LockTimeout:     utils.Duration(5 * time.Second),
+	}
+}
+
+// BindIdempotencyProperties create and bind IdempotencyProperties, with a optional prefix
+func BindIdempotencyProperties(ctx *bootstrap.ApplicationContext) IdempotencyProperties {
+	props := NewIdempotencyProperties()
+	if err := ctx.Config().Bind(props, PropertiesPrefix); err != nil {
+		panic(errors.Wrap(err, "failed to bind IdempotencyProperties"))
+	}
+	return *props
+}
@@ -0,0 +1,108 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// GormRecord is the table model used by the Store returned by NewGormStore.
+// The table "idempotency_records" is expected to be created by the application's migration, see MigrationFS.
+// Expired rows are ignored and overwritten, but not purged automatically.
+type GormRecord struct {
+	Key         string    `gorm:"column:idempotency_key;primaryKey;type:TEXT;"`
+	Fingerprint string    `gorm:"column:fingerprint;type:TEXT;not null;"`
+	Completed   bool      `gorm:"column:completed;not null;"`
+	StatusCode  int       `gorm:"column:status_code;"`
+	Header      []byte    `gorm:"column:header;"`
+	Body        []byte    `gorm:"column:body;"`
+	ExpiresAt   time.Time `gorm:"column:expires_at;not null;index;"`
+}
+
+func (GormRecord) TableName() string {
+	return "idempotency_records"
+}
+
+// NewGormStore returns a Store backed by the database configured via pkg/data.
+func NewGormStore(db *gorm.DB) Store {
+	return &gormStore{db: db}
+}
+
+type gormStore struct {
+	db *gorm.DB
+}
+
+func (s *gormStore) Load(ctx context.Context, key string) (*Record, error) {
+	var model GormRecord
+	rs := s.db.WithContext(ctx).
+		Where("idempotency_key = ? AND expires_at > ?", key, time.Now()).
+		Take(&model)
+	switch {
+	case errors.Is(rs.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case rs.Error != nil:
+		return nil, ErrStoreFailure.WithCause(rs.Error, "idempotency store failure: %v", rs.Error)
+	}
+	record := Record{
+		Fingerprint: model.Fingerprint,
+		Completed:   model.Completed,
+		StatusCode:  model.StatusCode,
+		Body:        model.Body,
+	}
+	if len(model.Header) != 0 {
+		if e := json.Unmarshal(model.Header, &record.Header); e != nil {
+			return nil, ErrStoreFailure.WithCause(e, "idempotency store failure: %v", e)
+		}
+	}
+	return &record, nil
+}
+
+func (s *gormStore) Save(ctx context.Context, key string, record *Record, ttl time.Duration) error {
+	model := GormRecord{
+		Key:         key,
+		Fingerprint: record.Fingerprint,
+		Completed:   record.Completed,
+		StatusCode:  record.StatusCode,
+		Body:        record.Body,
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+	if len(record.Header) != 0 {
+		data, e := json.Marshal(record.Header)
+		if e != nil {
+			return ErrStoreFailure.WithCause(e, "idempotency store failure: %v", e)
+		}
+		model.Header = data
+	}
+	rs := s.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&model)
+	if rs.Error != nil {
+		return ErrStoreFailure.WithCause(rs.Error, "idempotency store failure: %v", rs.Error)
+	}
+	return nil
+}
+
+func (s *gormStore) Delete(ctx context.Context, key string) error {
+	rs := s.db.WithContext(ctx).Where("idempotency_key = ?", key).Delete(&GormRecord{})
+	if rs.Error != nil {
+		return ErrStoreFailure.WithCause(rs.Error, "idempotency store failure: %v", rs.Error)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const memorySweepInterval = time.Minute
+
+// NewInMemoryStore returns a Store that keeps records in memory. Records are not shared between instances,
+// so it's only suitable for single instance services or testing.
+func NewInMemoryStore() Store {
+	return &memoryStore{
+		entries:   map[string]memoryEntry{},
+		lastSweep: time.Now(),
+	}
+}
+
+type memoryEntry struct {
+	record Record
+	expiry time.Time
+}
+
+type memoryStore struct {
+	mtx       sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+func (s *memoryStore) Load(_ context.Context, key string) (*Record, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	entry, ok := s.entries[key]
+	if !ok || entry.expiry.Before(time.Now()) {
+		return nil, nil
+	}
+	record := entry.record
+	return &record, nil
+}
+
+func (s *memoryStore) Save(_ context.Context, key string, record *Record, ttl time.Duration) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	now := time.Now()
+	s.sweep(now)
+	s.entries[key] = memoryEntry{record: *record, expiry: now.Add(ttl)}
+	return nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, key string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// sweep removes expired entries periodically
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	for k, v := range s.entries {
+		if v.expiry.Before(now) {
+			delete(s.entries, k)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/redis"
+	goredis "github.com/go-redis/redis/v8"
+	"time"
+)
+
+const defaultRedisKeyPrefix = "LANAI:IDEMPOTENCY:"
+
+type RedisStoreOptions func(opt *RedisStoreOption)
+
+type RedisStoreOption struct {
+	// KeyPrefix is prepended to all keys. Default "LANAI:IDEMPOTENCY:"
+	KeyPrefix string
+}
+
+// NewRedisStore returns a Store that keeps records in Redis as JSON values, so that records are shared by all service instances.
+func NewRedisStore(client redis.Client, opts ...RedisStoreOptions) Store {
+	opt := RedisStoreOption{
+		KeyPrefix: defaultRedisKeyPrefix,
+	}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	return &redisStore{
+		client: client,
+		prefix: opt.KeyPrefix,
+	}
+}
+
+type redisStore struct {
+	client redis.Client
+	prefix string
+}
+
+func (s *redisStore) Load(ctx context.Context, key string) (*Record, error) {
+	data, e := s.client.Get(ctx, s.prefix+key).Bytes()
+	switch {
+	case errors.Is(e, goredis.Nil):
+		return nil, nil
+	case e != nil:
+		return nil, ErrStoreFailure.WithCause(e, "idempotency store failure: %v", e)
+	}
+	var record Record
+	if e := json.Unmarshal(data, &record); e != nil {
+		return nil, ErrStoreFailure.WithCause(e, "idempotency store failure: %v", e)
+	}
+	return &record, nil
+}
+
+func (s *redisStore) Save(ctx context.Context, key string, record *Record, ttl time.Duration) error {
+	data, e := json.Marshal(record)
+	if e != nil {
+		return ErrStoreFailure.WithCause(e, "idempotency store failure: %v", e)
+	}
+	if e := s.client.Set(ctx, s.prefix+key, data, ttl).Err(); e != nil {
+		return ErrStoreFailure.WithCause(e, "idempotency store failure: %v", e)
+	}
+	return nil
+}
+
+func (s *redisStore) Delete(ctx context.Context, key string) error {
+	if e := s.client.Del(ctx, s.prefix+key).Err(); e != nil {
+		return ErrStoreFailure.WithCause(e, "idempotency store failure: %v", e)
+	}
+	return nil
+}
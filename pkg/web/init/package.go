@@ -21,8 +21,10 @@ import (
 	"embed"
 	appconfig "github.com/cisco-open/go-lanai/pkg/appconfig/init"
 	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/certs"
 	"github.com/cisco-open/go-lanai/pkg/web"
 	"github.com/cisco-open/go-lanai/pkg/web/cors"
+	webmetrics "github.com/cisco-open/go-lanai/pkg/web/metrics"
 	webtracing "github.com/cisco-open/go-lanai/pkg/web/tracing"
 	"go.uber.org/fx"
 )
@@ -42,7 +44,7 @@ var Module = &bootstrap.Module{
 		fx.Invoke(setup),
 	},
 	Modules: []*bootstrap.Module{
-		cors.Module, webtracing.Module,
+		cors.Module, webtracing.Module, webmetrics.Module,
 	},
 }
 
@@ -69,12 +71,14 @@ type initDI struct {
 	Controllers      []web.Controller      `group:"controllers"`
 	Customizers      []web.Customizer      `group:"customizers"`
 	ErrorTranslators []web.ErrorTranslator `group:"error_translators"`
+	CertsManager     certs.Manager         `optional:"true"`
 }
 
 func setup(lc fx.Lifecycle, di initDI) {
 	di.Registrar.MustRegister(web.NewLoggingCustomizer(di.Properties))
 	di.Registrar.MustRegister(web.NewRecoveryCustomizer())
 	di.Registrar.MustRegister(web.NewGinErrorHandlingCustomizer())
+	di.Registrar.MustRegister(web.NewTLSCustomizer(di.Properties, di.CertsManager))
 
 	di.Registrar.MustRegister(di.Controllers)
 	di.Registrar.MustRegister(di.Customizers)
@@ -0,0 +1,95 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webmetrics
+
+import (
+	"context"
+	"github.com/cisco-open/go-lanai/pkg/metrics"
+	"github.com/cisco-open/go-lanai/pkg/utils/order"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"github.com/cisco-open/go-lanai/pkg/web/matcher"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	MetricServerRequests = "http.server.requests"
+	TagMethod            = "method"
+	TagRoute             = "route"
+	TagStatus            = "status"
+	// RouteUnknown is the route tag value of requests not matching any mapping
+	RouteUnknown = "UNKNOWN"
+)
+
+var excludeRequest = matcher.RequestWithMethods(http.MethodOptions)
+
+type metricsWebCustomizer struct {
+	registry metrics.Registry
+}
+
+func newMetricsWebCustomizer(registry metrics.Registry) *metricsWebCustomizer {
+	return &metricsWebCustomizer{
+		registry: registry,
+	}
+}
+
+// Order we want metricsWebCustomizer right after tracing
+func (c metricsWebCustomizer) Order() int {
+	return order.Highest + 1
+}
+
+func (c metricsWebCustomizer) Customize(_ context.Context, r *web.Registrar) error {
+	//nolint:contextcheck // false positive
+	if e := r.AddGlobalMiddlewares(GinMetrics(c.registry, excludeRequest)); e != nil {
+		return e
+	}
+	return nil
+}
+
+// GinMetrics records duration of each request, tagged by method, route pattern, status code and outcome.
+// Outcome is "error" for 5xx responses
+func GinMetrics(registry metrics.Registry, excludes web.RequestMatcher) gin.HandlerFunc {
+	timer := registry.Timer(MetricServerRequests,
+		metrics.WithDescription("HTTP server requests"),
+		metrics.WithTags(TagMethod, TagRoute, TagStatus, metrics.TagOutcome),
+	)
+	return func(gc *gin.Context) {
+		if m, e := excludes.Matches(gc.Request); e == nil && m {
+			return
+		}
+		start := time.Now()
+		gc.Next()
+
+		route := gc.FullPath()
+		if route == "" {
+			route = RouteUnknown
+		}
+		status := gc.Writer.Status()
+		outcome := metrics.OutcomeSuccess
+		if status >= http.StatusInternalServerError {
+			outcome = metrics.OutcomeError
+		}
+		timer.Record(time.Since(start), metrics.Tags{
+			TagMethod:          gc.Request.Method,
+			TagRoute:           route,
+			TagStatus:          strconv.Itoa(status),
+			metrics.TagOutcome: outcome,
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package webmetrics
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/metrics"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"go.uber.org/fx"
+)
+
+var Module = &bootstrap.Module{
+	Name:       "web-metrics",
+	Precedence: web.MinWebPrecedence,
+	Options: []fx.Option{
+		fx.Invoke(setup),
+	},
+}
+
+type initDI struct {
+	fx.In
+	Registrar *web.Registrar   `optional:"true"`
+	Registry  metrics.Registry `optional:"true"`
+}
+
+func setup(di initDI) {
+	if di.Registry != nil && di.Registrar != nil {
+		di.Registrar.MustRegister(newMetricsWebCustomizer(di.Registry))
+	}
+}
@@ -18,7 +18,9 @@ package web
 
 import (
 	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/certs"
 	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/utils"
 	"github.com/pkg/errors"
 )
 
@@ -34,6 +36,32 @@ type ServerProperties struct {
 	Port        int               `json:"port"`
 	ContextPath string            `json:"context-path"`
 	Logging     LoggingProperties `json:"logging"`
+	TLS         TLSProperties     `json:"tls"`
+	HTTP2       HTTP2Properties   `json:"http2"`
+	// TrustedProxies are IPs or CIDRs of reverse proxies whose forwarding headers (e.g. X-Forwarded-For) are trusted
+	// when resolving client IP. When not set, gin's default is used by gin.Context.ClientIP, which trusts all proxies.
+	// IP based rate limiting and DPoP proof verification only trust proxies listed here.
+	TrustedProxies utils.CommaSeparatedSlice `json:"trusted-proxies"`
+}
+
+// TLSProperties configures HTTPS of the embedded web server.
+// The server certificate is loaded from certs.Manager, and renewed certificates take effect without restart.
+type TLSProperties struct {
+	Enabled bool                   `json:"enabled"`
+	Certs   certs.SourceProperties `json:"certs"`
+	// ClientAuth controls client certificate verification.
+	// Supported values are "none", "optional" and "required". Default is "none"
+	ClientAuth ClientAuthMode `json:"client-auth"`
+	// ClientCAFile is the PEM file of CAs used to verify client certificates. Required when ClientAuth is not "none"
+	ClientCAFile string `json:"client-ca-file"`
+}
+
+// HTTP2Properties configures HTTP/2 support.
+type HTTP2Properties struct {
+	// Enabled enables HTTP/2 over TLS (h2) via ALPN. It takes effect only when TLS is enabled
+	Enabled bool `json:"enabled"`
+	// H2C enables HTTP/2 over cleartext TCP (h2c). It takes effect only when TLS is disabled
+	H2C bool `json:"h2c"`
 }
 
 type LoggingProperties struct {
@@ -61,6 +89,12 @@ func NewServerProperties() *ServerProperties {
 			DefaultLevel: log.LevelDebug,
 			Levels:       map[string]LoggingLevelProperties{},
 		},
+		TLS: TLSProperties{
+			ClientAuth: ClientAuthNone,
+		},
+		HTTP2: HTTP2Properties{
+			Enabled: true,
+		},
 	}
 }
 
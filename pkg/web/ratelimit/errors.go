@@ -0,0 +1,59 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"errors"
+	errorutils "github.com/cisco-open/go-lanai/pkg/utils/error"
+)
+
+const (
+	// Reserved rate limit reserved error range
+	Reserved = 0x2a << errorutils.ReservedOffset
+)
+
+// All "Type" values are used as mask
+const (
+	_                      = iota
+	ErrorTypeCodeRateLimit = Reserved + iota<<errorutils.ErrorTypeOffset
+)
+
+// All "SubType" values are used as mask
+// sub types of ErrorTypeCodeRateLimit
+const (
+	_                             = iota
+	ErrorSubTypeCodeLimitExceeded = ErrorTypeCodeRateLimit + iota<<errorutils.ErrorSubTypeOffset
+)
+
+// ErrorSubTypeCodeLimitExceeded
+const (
+	_                          = iota
+	ErrorCodeRateLimitExceeded = ErrorSubTypeCodeLimitExceeded + iota
+)
+
+// ErrorTypes, can be used in errors.Is
+var (
+	ErrorCategoryRateLimit    = errorutils.NewErrorCategory(Reserved, errors.New("error type: rate limit"))
+	ErrorTypeRateLimit        = errorutils.NewErrorType(ErrorTypeCodeRateLimit, errors.New("error type: rate limit"))
+	ErrorSubTypeLimitExceeded = errorutils.NewErrorSubType(ErrorSubTypeCodeLimitExceeded, errors.New("error sub-type: limit exceeded"))
+
+	ErrRateLimitExceeded = errorutils.NewCodedError(ErrorCodeRateLimitExceeded, "rate limit exceeded")
+)
+
+func init() {
+	errorutils.Reserve(ErrorCategoryRateLimit)
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/security/oauth2"
+	netutil "github.com/cisco-open/go-lanai/pkg/utils/net"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	KeyTypeClient KeyType = "client"
+	KeyTypeUser   KeyType = "user"
+	KeyTypeTenant KeyType = "tenant"
+	KeyTypeIP     KeyType = "ip"
+)
+
+// KeyType is the predefined KeyFunc that can be used in properties
+type KeyType string
+
+// KeyFunc returns the KeyFunc of this type. Forwarding headers are only honored for KeyTypeIP when the request comes
+// from one of given trustedProxies. See KeyByIP
+func (t KeyType) KeyFunc(trustedProxies ...string) (KeyFunc, error) {
+	switch KeyType(strings.ToLower(string(t))) {
+	case KeyTypeClient:
+		return KeyByClientID(), nil
+	case KeyTypeUser:
+		return KeyByUsername(), nil
+	case KeyTypeTenant:
+		return KeyByTenant(), nil
+	case KeyTypeIP:
+		if _, e := netutil.ParseIPNets(trustedProxies); e != nil {
+			return nil, e
+		}
+		return KeyByIP(trustedProxies...), nil
+	default:
+		return nil, fmt.Errorf(`unsupported rate limit key type [%s]`, t)
+	}
+}
+
+// KeyFunc extracts the rate limiting key from current request. Empty key means the request is not subject to the limit.
+// The given context.Context is the request's gin.Context, which carries current security.Authentication.
+type KeyFunc func(ctx context.Context, r *http.Request) string
+
+// KeyByClientID uses OAuth2 client ID of current security.Authentication
+func KeyByClientID() KeyFunc {
+	return func(ctx context.Context, _ *http.Request) string {
+		auth, ok := security.Get(ctx).(oauth2.Authentication)
+		if !ok || auth.OAuth2Request() == nil || auth.OAuth2Request().ClientId() == "" {
+			return ""
+		}
+		return "client:" + auth.OAuth2Request().ClientId()
+	}
+}
+
+// KeyByUsername uses username of current security.Authentication
+func KeyByUsername() KeyFunc {
+	return func(ctx context.Context, _ *http.Request) string {
+		auth := security.Get(ctx)
+		if auth.State() < security.StatePrincipalKnown {
+			return ""
+		}
+		if username, e := security.GetUsername(auth); e == nil && username != "" {
+			return "user:" + username
+		}
+		return ""
+	}
+}
+
+// KeyByTenant uses tenant ID of current security.Authentication
+func KeyByTenant() KeyFunc {
+	return func(ctx context.Context, _ *http.Request) string {
+		details, ok := security.Get(ctx).Details().(security.TenantDetails)
+		if !ok || details.TenantId() == "" {
+			return ""
+		}
+		return "tenant:" + details.TenantId()
+	}
+}
+
+// KeyByIP uses client IP. By default, the remote address of the request is used and forwarding headers are ignored,
+// so clients cannot spoof their IP to bypass the limit.
+// When the remote address is one of given trustedProxies (IPs or CIDRs), the client IP is resolved from "X-Forwarded-For",
+// as the right-most address that is not a trusted proxy.
+func KeyByIP(trustedProxies ...string) KeyFunc {
+	trusted, e := netutil.ParseIPNets(trustedProxies)
+	if e != nil {
+		panic(e)
+	}
+	return func(_ context.Context, r *http.Request) string {
+		host, _, e := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+		if e != nil {
+			return ""
+		}
+		if ip := forwardedClientIP(r, host, trusted); ip != "" {
+			return "ip:" + ip
+		}
+		return "ip:" + host
+	}
+}
+
+// KeyByHeader uses value of given request header
+func KeyByHeader(name string) KeyFunc {
+	return func(_ context.Context, r *http.Request) string {
+		if v := r.Header.Get(name); v != "" {
+			return "header:" + name + ":" + v
+		}
+		return ""
+	}
+}
+
+// KeyFallback returns the first non-empty key of given KeyFunc, e.g. KeyFallback(KeyByUsername(), KeyByIP())
+func KeyFallback(fns ...KeyFunc) KeyFunc {
+	return func(ctx context.Context, r *http.Request) string {
+		for _, fn := range fns {
+			if k := fn(ctx, r); k != "" {
+				return k
+			}
+		}
+		return ""
+	}
+}
+
+// forwardedClientIP returns the right-most address of "X-Forwarded-For" that is not a trusted proxy,
+// only when the request comes from a trusted proxy. Otherwise, empty string is returned.
+func forwardedClientIP(r *http.Request, remote string, trusted []*net.IPNet) string {
+	if !netutil.IPNetsContain(trusted, net.ParseIP(remote)) {
+		return ""
+	}
+	var addrs []string
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		addrs = append(addrs, strings.Split(v, ",")...)
+	}
+	for i := len(addrs) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(addrs[i]))
+		if ip == nil {
+			return ""
+		}
+		if !netutil.IPNetsContain(trusted, ip) {
+			return ip.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	AlgorithmTokenBucket   Algorithm = "token-bucket"
+	AlgorithmSlidingWindow Algorithm = "sliding-window"
+)
+
+// Algorithm of rate limiting.
+//   - AlgorithmTokenBucket: bucket of Limit.Burst tokens refilled at Limit.Limit per Limit.Window. Allows short bursts.
+//   - AlgorithmSlidingWindow: at most Limit.Limit requests within any Limit.Window. Limit.Burst is ignored.
+type Algorithm string
+
+func (a *Algorithm) UnmarshalText(data []byte) error {
+	switch v := Algorithm(strings.ToLower(string(data))); v {
+	case AlgorithmTokenBucket, AlgorithmSlidingWindow:
+		*a = v
+		return nil
+	default:
+		return fmt.Errorf(`unsupported rate limit algorithm [%s]`, data)
+	}
+}
+
+// Limit defines the allowed rate
+type Limit struct {
+	Limit  int
+	Window time.Duration
+	// Burst is the bucket capacity of AlgorithmTokenBucket. Default to Limit if not set
+	Burst int
+}
+
+func (l Limit) burst() int {
+	if l.Burst <= 0 {
+		return l.Limit
+	}
+	return l.Burst
+}
+
+// Result is the outcome of a single Limiter.Allow call
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is the duration until the quota is fully restored
+	ResetAfter time.Duration
+	// RetryAfter is the duration until next request would be allowed. Zero if Allowed
+	RetryAfter time.Duration
+}
+
+// Limiter consumes quota of given key
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
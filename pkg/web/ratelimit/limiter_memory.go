@@ -0,0 +1,150 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const memorySweepInterval = time.Minute
+
+// NewInMemoryLimiter returns a Limiter that keeps state in memory. The state is not shared between instances,
+// so it's only suitable for single instance services or as a best-effort fallback.
+func NewInMemoryLimiter(algorithm Algorithm) Limiter {
+	return &memoryLimiter{
+		algorithm: algorithm,
+		entries:   map[string]*memoryEntry{},
+		lastSweep: time.Now(),
+	}
+}
+
+type memoryEntry struct {
+	tokens float64
+	last   time.Time
+	hits   []time.Time
+	expiry time.Time
+}
+
+type memoryLimiter struct {
+	mtx       sync.Mutex
+	algorithm Algorithm
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+}
+
+func (l *memoryLimiter) Allow(_ context.Context, key string, limit Limit) (*Result, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	now := time.Now()
+	l.sweep(now)
+	entry, ok := l.entries[key]
+	if !ok {
+		entry = &memoryEntry{tokens: float64(limit.burst()), last: now}
+		l.entries[key] = entry
+	}
+	if l.algorithm == AlgorithmSlidingWindow {
+		return l.slidingWindow(entry, limit, now), nil
+	}
+	return l.tokenBucket(entry, limit, now), nil
+}
+
+func (l *memoryLimiter) tokenBucket(entry *memoryEntry, limit Limit, now time.Time) *Result {
+	rate := tokenRate(limit)
+	entry.tokens = math.Min(float64(limit.burst()), entry.tokens+float64(now.Sub(entry.last))*rate)
+	entry.last = now
+	allowed := entry.tokens >= 1
+	if allowed {
+		entry.tokens--
+	}
+	ret := tokenBucketResult(limit, entry.tokens, allowed)
+	entry.expiry = now.Add(ret.ResetAfter)
+	return ret
+}
+
+func (l *memoryLimiter) slidingWindow(entry *memoryEntry, limit Limit, now time.Time) *Result {
+	cutoff := now.Add(-limit.Window)
+	i := 0
+	for i < len(entry.hits) && !entry.hits[i].After(cutoff) {
+		i++
+	}
+	entry.hits = entry.hits[i:]
+	allowed := len(entry.hits) < limit.Limit
+	if allowed {
+		entry.hits = append(entry.hits, now)
+	}
+	oldest := now
+	if len(entry.hits) != 0 {
+		oldest = entry.hits[0]
+		entry.expiry = entry.hits[len(entry.hits)-1].Add(limit.Window)
+	}
+	return slidingWindowResult(limit, len(entry.hits), oldest, now, allowed)
+}
+
+// sweep removes expired entries periodically
+func (l *memoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < memorySweepInterval {
+		return
+	}
+	for k, v := range l.entries {
+		if v.expiry.Before(now) {
+			delete(l.entries, k)
+		}
+	}
+	l.lastSweep = now
+}
+
+/**********************
+	Common Helpers
+ **********************/
+
+// tokenRate returns refill rate in tokens per nanosecond
+func tokenRate(limit Limit) float64 {
+	return float64(limit.Limit) / float64(limit.Window)
+}
+
+func tokenBucketResult(limit Limit, tokens float64, allowed bool) *Result {
+	rate := tokenRate(limit)
+	ret := &Result{
+		Allowed:    allowed,
+		Limit:      limit.burst(),
+		Remaining:  int(tokens),
+		ResetAfter: time.Duration((float64(limit.burst()) - tokens) / rate),
+	}
+	if !allowed {
+		ret.RetryAfter = time.Duration((1 - tokens) / rate)
+	}
+	return ret
+}
+
+func slidingWindowResult(limit Limit, count int, oldest, now time.Time, allowed bool) *Result {
+	ret := &Result{
+		Allowed:    allowed,
+		Limit:      limit.Limit,
+		Remaining:  limit.Limit - count,
+		ResetAfter: oldest.Add(limit.Window).Sub(now),
+	}
+	if ret.Remaining < 0 {
+		ret.Remaining = 0
+	}
+	if !allowed {
+		ret.RetryAfter = ret.ResetAfter
+	}
+	return ret
+}
@@ -0,0 +1,151 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/redis"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	goredis "github.com/go-redis/redis/v8"
+	"strconv"
+	"time"
+)
+
+const defaultRedisKeyPrefix = "LANAI:RATELIMIT:"
+
+// tokenBucketScript refills and consumes token atomically.
+// KEYS[1]: bucket key. ARGV: capacity, refill rate (tokens per millisecond), now (ms), ttl (ms)
+// Returns {allowed (0/1), remaining tokens as string}
+var tokenBucketScript = goredis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return {allowed, tostring(tokens)}
+`)
+
+// slidingWindowScript records request timestamps in a sorted set.
+// KEYS[1]: window key. ARGV: now (ms), window (ms), limit, unique member
+// Returns {allowed (0/1), count within window, oldest timestamp (ms)}
+var slidingWindowScript = goredis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+local oldest = now
+local first = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if #first > 0 then
+	oldest = tonumber(first[2])
+end
+return {allowed, count, oldest}
+`)
+
+type RedisLimiterOptions func(opt *RedisLimiterOption)
+
+type RedisLimiterOption struct {
+	Algorithm Algorithm
+	// KeyPrefix is prepended to all keys. Default "LANAI:RATELIMIT:"
+	KeyPrefix string
+}
+
+// NewRedisLimiter returns a Limiter that keeps state in Redis, so that the quota is shared by all service instances.
+// Timestamps are taken from the local clock, so instances are expected to have reasonably synchronized clocks.
+func NewRedisLimiter(client redis.Client, opts ...RedisLimiterOptions) Limiter {
+	opt := RedisLimiterOption{
+		Algorithm: AlgorithmTokenBucket,
+		KeyPrefix: defaultRedisKeyPrefix,
+	}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	return &redisLimiter{
+		client:    client,
+		algorithm: opt.Algorithm,
+		prefix:    opt.KeyPrefix,
+	}
+}
+
+type redisLimiter struct {
+	client    redis.Client
+	algorithm Algorithm
+	prefix    string
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	now := time.Now()
+	if l.algorithm == AlgorithmSlidingWindow {
+		return l.slidingWindow(ctx, l.prefix+key, limit, now)
+	}
+	return l.tokenBucket(ctx, l.prefix+key, limit, now)
+}
+
+func (l *redisLimiter) tokenBucket(ctx context.Context, key string, limit Limit, now time.Time) (*Result, error) {
+	ratePerMs := tokenRate(limit) * float64(time.Millisecond)
+	ttl := time.Duration(float64(limit.burst())/tokenRate(limit)) + time.Second
+	vals, e := tokenBucketScript.Run(ctx, l.client, []string{key},
+		limit.burst(), strconv.FormatFloat(ratePerMs, 'f', -1, 64), now.UnixMilli(), ttl.Milliseconds(),
+	).Slice()
+	if e != nil {
+		return nil, fmt.Errorf("unable to evaluate rate limit: %v", e)
+	}
+	if len(vals) != 2 {
+		return nil, fmt.Errorf("unable to evaluate rate limit: unexpected result %v", vals)
+	}
+	tokens, e := strconv.ParseFloat(fmt.Sprint(vals[1]), 64)
+	if e != nil {
+		return nil, fmt.Errorf("unable to evaluate rate limit: %v", e)
+	}
+	return tokenBucketResult(limit, tokens, vals[0] == int64(1)), nil
+}
+
+func (l *redisLimiter) slidingWindow(ctx context.Context, key string, limit Limit, now time.Time) (*Result, error) {
+	member := fmt.Sprintf("%d-%s", now.UnixNano(), utils.RandomString(8))
+	vals, e := slidingWindowScript.Run(ctx, l.client, []string{key},
+		now.UnixMilli(), limit.Window.Milliseconds(), limit.Limit, member,
+	).Int64Slice()
+	if e != nil {
+		return nil, fmt.Errorf("unable to evaluate rate limit: %v", e)
+	}
+	if len(vals) != 3 {
+		return nil, fmt.Errorf("unable to evaluate rate limit: unexpected result %v", vals)
+	}
+	oldest := time.UnixMilli(vals[2])
+	return slidingWindowResult(limit, int(vals[1]), oldest, now, vals[0] == 1), nil
+}
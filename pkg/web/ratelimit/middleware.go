@@ -0,0 +1,130 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/log"
+	"github.com/cisco-open/go-lanai/pkg/security"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+var logger = log.New("RateLimit")
+
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+// MWOrderRateLimit runs rate limiting after authentication and before access control,
+// so security.Authentication is available to KeyFunc
+const MWOrderRateLimit = security.MWOrderAccessControl - 10
+
+type MiddlewareOptions func(opt *MiddlewareOption)
+
+type MiddlewareOption struct {
+	// Name of the policy. Used as part of the limiter key, so policies with different names don't share quota
+	Name    string
+	Limiter Limiter
+	Limit   Limit
+	KeyFunc KeyFunc
+	// FailOpen allows requests when Limiter returns error. Default false, requests are rejected with 503 in such case
+	FailOpen bool
+}
+
+// Middleware provides rate limiting gin.HandlerFunc. Use it with middleware.MappingBuilder, e.g.
+// <code>
+// middleware.NewBuilder("rate-limit").ApplyTo(matcher.RouteWithPattern("/api/**")).Order(ratelimit.MWOrderRateLimit).Use(ratelimit.NewMiddleware(...).HandlerFunc()).Build()
+// </code>
+type Middleware struct {
+	name     string
+	limiter  Limiter
+	limit    atomic.Pointer[Limit]
+	keyFunc  KeyFunc
+	failOpen atomic.Bool
+}
+
+func NewMiddleware(opts ...MiddlewareOptions) *Middleware {
+	opt := MiddlewareOption{
+		Name:    "default",
+		Limiter: NewInMemoryLimiter(AlgorithmTokenBucket),
+		KeyFunc: KeyByIP(),
+	}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	mw := &Middleware{
+		name:    opt.Name,
+		limiter: opt.Limiter,
+		keyFunc: opt.KeyFunc,
+	}
+	mw.Update(opt.Limit, opt.FailOpen)
+	return mw
+}
+
+// Update changes Limit and FailOpen at runtime, e.g. when properties are refreshed. Safe for concurrent use
+func (m *Middleware) Update(limit Limit, failOpen bool) {
+	m.limit.Store(&limit)
+	m.failOpen.Store(failOpen)
+}
+
+func (m *Middleware) HandlerFunc() gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		key := m.keyFunc(gc, gc.Request)
+		limit := *m.limit.Load()
+		if key == "" || limit.Limit <= 0 || limit.Window <= 0 {
+			return
+		}
+		result, e := m.limiter.Allow(gc.Request.Context(), m.name+":"+key, limit)
+		if e != nil {
+			logger.WithContext(gc).Warnf("rate limit [%s] is not evaluated: %v", m.name, e)
+			if !m.failOpen.Load() {
+				_ = gc.Error(web.NewHttpError(http.StatusServiceUnavailable, e))
+				gc.Abort()
+			}
+			return
+		}
+
+		header := gc.Writer.Header()
+		header.Set(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+		header.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+		header.Set(HeaderRateLimitReset, seconds(result.ResetAfter))
+		header.Set(HeaderRateLimitPolicy, fmt.Sprintf("%d;w=%s", limit.Limit, seconds(limit.Window)))
+		if result.Allowed {
+			return
+		}
+		retryHeaders := http.Header{}
+		retryHeaders.Set(HeaderRetryAfter, seconds(result.RetryAfter))
+		err := ErrRateLimitExceeded.WithMessage("rate limit [%s] exceeded, retry after %s seconds", m.name, seconds(result.RetryAfter))
+		_ = gc.Error(web.NewHttpError(http.StatusTooManyRequests, err, retryHeaders))
+		gc.Abort()
+	}
+}
+
+// seconds rounds up given duration to whole seconds
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
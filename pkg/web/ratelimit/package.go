@@ -0,0 +1,207 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/appconfig"
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/redis"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"github.com/cisco-open/go-lanai/pkg/web/matcher"
+	"github.com/cisco-open/go-lanai/pkg/web/middleware"
+	"go.uber.org/fx"
+	"sort"
+	"strings"
+	"time"
+)
+
+var Module = &bootstrap.Module{
+	Name:       "rate-limit",
+	Precedence: web.MinWebPrecedence + 1,
+	Options: []fx.Option{
+		fx.Provide(BindRateLimitProperties),
+		web.FxCustomizerProviders(newCustomizer),
+	},
+}
+
+// Use Allow service to include this module in main()
+func Use() {
+	bootstrap.Register(Module)
+}
+
+type customizerDI struct {
+	fx.In
+	AppCtx        *bootstrap.ApplicationContext
+	Properties    RateLimitProperties
+	ServerProps   web.ServerProperties
+	ClientFactory redis.ClientFactory `optional:"true"`
+}
+
+// Customizer implements web.Customizer. It installs rate limit middlewares of all configured policies.
+// When Store is "redis", all policies share one redis.Client.
+// IP based keys honor forwarding headers only from web.ServerProperties.TrustedProxies.
+// When application config is refreshable, limits and FailOpen of installed policies are updated on refresh.
+type Customizer struct {
+	appCtx         *bootstrap.ApplicationContext
+	properties     RateLimitProperties
+	trustedProxies []string
+	clientFactory  redis.ClientFactory
+	redisClient    redis.Client
+	middlewares    map[string]*Middleware
+}
+
+func newCustomizer(di customizerDI) web.Customizer {
+	return &Customizer{
+		appCtx:         di.AppCtx,
+		properties:     di.Properties,
+		trustedProxies: di.ServerProps.TrustedProxies,
+		clientFactory:  di.ClientFactory,
+		middlewares:    map[string]*Middleware{},
+	}
+}
+
+func (c *Customizer) Customize(ctx context.Context, r *web.Registrar) error {
+	if !c.properties.Enabled || len(c.properties.Policies) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(c.properties.Policies))
+	for name := range c.properties.Policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		mapping, e := c.newPolicyMiddleware(name, c.properties.Policies[name])
+		if e != nil {
+			return e
+		}
+		if e := r.Register(mapping); e != nil {
+			return e
+		}
+		logger.WithContext(ctx).Debugf("rate limit policy [%s] installed", name)
+	}
+	return c.watchRefresh()
+}
+
+// watchRefresh updates installed policies when properties are refreshed.
+// Adding/removing policies or changing patterns, keys, algorithm or store requires restart.
+func (c *Customizer) watchRefresh() error {
+	cfg, ok := c.appCtx.Config().(appconfig.RefreshableConfig)
+	if !ok {
+		return nil
+	}
+	refreshable, e := appconfig.NewRefreshable(cfg, PropertiesPrefix, func() RateLimitProperties {
+		return *NewRateLimitProperties()
+	})
+	if e != nil {
+		return e
+	}
+	refreshable.OnRefresh(func(ctx context.Context, _, current RateLimitProperties) {
+		current.applyPolicyDefaults()
+		for name, mw := range c.middlewares {
+			policy, ok := current.Policies[name]
+			if !ok || !current.Enabled {
+				logger.WithContext(ctx).Warnf("rate limit policy [%s] cannot be removed without restart", name)
+				continue
+			}
+			mw.Update(policyLimit(policy), current.FailOpen)
+			logger.WithContext(ctx).Infof("rate limit policy [%s] updated", name)
+		}
+	})
+	return nil
+}
+
+func (c *Customizer) newPolicyMiddleware(name string, props PolicyProperties) (web.MiddlewareMapping, error) {
+	limiter, e := c.newLimiter(props.Algorithm)
+	if e != nil {
+		return nil, e
+	}
+	keyFuncs := make([]KeyFunc, len(props.Keys))
+	for i, k := range props.Keys {
+		if keyFuncs[i], e = KeyType(k).KeyFunc(c.trustedProxies...); e != nil {
+			return nil, fmt.Errorf("invalid rate limit policy [%s]: %v", name, e)
+		}
+	}
+
+	var routeMatcher web.RouteMatcher
+	for _, p := range props.Patterns {
+		m := matcher.RouteWithPattern(p, props.Methods...)
+		if routeMatcher == nil {
+			routeMatcher = m
+		} else {
+			routeMatcher = routeMatcher.Or(m)
+		}
+	}
+
+	mw := NewMiddleware(func(opt *MiddlewareOption) {
+		opt.Name = name
+		opt.Limiter = limiter
+		opt.KeyFunc = KeyFallback(keyFuncs...)
+		opt.FailOpen = c.properties.FailOpen
+		opt.Limit = policyLimit(props)
+	})
+	c.middlewares[name] = mw
+	return middleware.NewBuilder("rate-limit-" + name).
+		ApplyTo(routeMatcher).
+		Order(MWOrderRateLimit).
+		Use(mw.HandlerFunc()).
+		Build(), nil
+}
+
+func policyLimit(props PolicyProperties) Limit {
+	return Limit{
+		Limit:  props.Limit,
+		Window: time.Duration(props.Window),
+		Burst:  props.Burst,
+	}
+}
+
+func (c *Customizer) newLimiter(algorithm Algorithm) (Limiter, error) {
+	switch StoreType(strings.ToLower(string(c.properties.Store))) {
+	case StoreMemory, "":
+		return NewInMemoryLimiter(algorithm), nil
+	case StoreRedis:
+		if c.clientFactory == nil {
+			return nil, fmt.Errorf("rate limit store is [%s], but redis is not available", c.properties.Store)
+		}
+		client, e := c.sharedRedisClient()
+		if e != nil {
+			return nil, e
+		}
+		return NewRedisLimiter(client, func(opt *RedisLimiterOption) {
+			opt.Algorithm = algorithm
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported rate limit store [%s]", c.properties.Store)
+	}
+}
+
+func (c *Customizer) sharedRedisClient() (redis.Client, error) {
+	if c.redisClient != nil {
+		return c.redisClient, nil
+	}
+	client, e := c.clientFactory.New(c.appCtx, func(opt *redis.ClientOption) {
+		opt.DbIndex = c.properties.DbIndex
+	})
+	if e != nil {
+		return nil, e
+	}
+	c.redisClient = client
+	return client, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"github.com/cisco-open/go-lanai/pkg/bootstrap"
+	"github.com/cisco-open/go-lanai/pkg/utils"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	PropertiesPrefix = "server.rate-limit"
+)
+
+const (
+	StoreMemory StoreType = "memory"
+	StoreRedis  StoreType = "redis"
+)
+
+// StoreType is where the rate limiting state is kept. StoreRedis shares quota across all instances of the service
+type StoreType string
+
+type RateLimitProperties struct {
+	Enabled bool      `json:"enabled"`
+	Store   StoreType `json:"store"`
+	// DbIndex is the Redis DB index when Store is "redis"
+	DbIndex int `json:"db-index"`
+	// FailOpen allows requests when the rate limit cannot be evaluated, e.g. Redis is unavailable. Default false
+	FailOpen bool                        `json:"fail-open"`
+	Policies map[string]PolicyProperties `json:"policies"`
+}
+
+// PolicyProperties defines a rate limit applied to requests matching Patterns and Methods.
+type PolicyProperties struct {
+	// Patterns are comma separated path patterns without context-path. Default "/**"
+	Patterns utils.CommaSeparatedSlice `json:"patterns"`
+	// Methods are comma separated HTTP methods. Empty means all methods
+	Methods utils.CommaSeparatedSlice `json:"methods"`
+	// Keys are comma separated key types, the first available key is used. e.g. "user, ip"
+	// Supported values are "client", "user", "tenant" and "ip"
+	Keys      utils.CommaSeparatedSlice `json:"keys"`
+	Algorithm Algorithm                 `json:"algorithm"`
+	Limit     int                       `json:"limit"`
+	Window    utils.Duration            `json:"window"`
+	// Burst is the bucket capacity of "token-bucket" algorithm. Default to Limit
+	Burst int `json:"burst"`
+}
+
+// NewRateLimitProperties create a RateLimitProperties with default values
+func NewRateLimitProperties() *RateLimitProperties {
+	return &RateLimitProperties{
+		Store:    StoreMemory,
+		Policies: map[string]PolicyProperties{},
+	}
+}
+
+// BindRateLimitProperties create and bind RateLimitProperties, with a optional prefix
+func BindRateLimitProperties(ctx *bootstrap.ApplicationContext) RateLimitProperties {
+	props := NewRateLimitProperties()
+	if err := ctx.Config().Bind(props, PropertiesPrefix); err != nil {
+		panic(errors.Wrap(err, "failed to bind RateLimitProperties"))
+	}
+	props.applyPolicyDefaults()
+	return *props
+}
+
+// applyPolicyDefaults fills default values of policies that are not set
+func (p *RateLimitProperties) applyPolicyDefaults() {
+	for k, v := range p.Policies {
+		if len(v.Patterns) == 0 {
+			v.Patterns = utils.CommaSeparatedSlice{"/**"}
+		}
+		if len(v.Keys) == 0 {
+			v.Keys = utils.CommaSeparatedSlice{string(KeyTypeIP)}
+		}
+		if v.Algorithm == "" {
+			v.Algorithm = AlgorithmTokenBucket
+		}
+		if v.Window <= 0 {
+			v.Window = utils.Duration(time.Minute)
+		}
+		p.Policies[k] = v
+	}
+}
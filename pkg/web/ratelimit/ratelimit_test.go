@@ -0,0 +1,347 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-open/go-lanai/pkg/appconfig"
+	"github.com/cisco-open/go-lanai/pkg/redis"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"github.com/cisco-open/go-lanai/pkg/web/ratelimit"
+	"github.com/cisco-open/go-lanai/pkg/web/rest"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/apptest"
+	"github.com/cisco-open/go-lanai/test/embedded"
+	"github.com/cisco-open/go-lanai/test/webtest"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"go.uber.org/fx"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/*************************
+	Setup Test
+ *************************/
+
+type LimiterTestDI struct {
+	fx.In
+	ClientFactory redis.ClientFactory
+}
+
+type RefreshTestDI struct {
+	fx.In
+	AppConfig *appconfig.ApplicationConfig
+}
+
+func RegisterTestController(reg *web.Registrar) error {
+	return reg.Register(TestController{})
+}
+
+/*************************
+	Tests
+ *************************/
+
+func TestInMemoryLimiter(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestTokenBucket(ratelimit.NewInMemoryLimiter(ratelimit.AlgorithmTokenBucket)), "TestTokenBucket"),
+		test.GomegaSubTest(SubTestSlidingWindow(ratelimit.NewInMemoryLimiter(ratelimit.AlgorithmSlidingWindow)), "TestSlidingWindow"),
+	)
+}
+
+func TestRedisLimiter(t *testing.T) {
+	di := &LimiterTestDI{}
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		embedded.WithRedis(),
+		apptest.WithModules(redis.Module),
+		apptest.WithDI(di),
+		test.GomegaSubTest(SubTestRedisLimiter(di, ratelimit.AlgorithmTokenBucket, SubTestTokenBucket), "TestTokenBucket"),
+		test.GomegaSubTest(SubTestRedisLimiter(di, ratelimit.AlgorithmSlidingWindow, SubTestSlidingWindow), "TestSlidingWindow"),
+	)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		webtest.WithMockedServer(),
+		apptest.WithModules(ratelimit.Module),
+		apptest.WithProperties(
+			"server.rate-limit.enabled: true",
+			"server.rate-limit.policies.default.patterns: /limited/**",
+			"server.rate-limit.policies.default.limit: 2",
+			"server.rate-limit.policies.default.window: 1m",
+			"server.rate-limit.policies.spoofed.patterns: /spoofed/**",
+			"server.rate-limit.policies.spoofed.limit: 1",
+		),
+		apptest.WithFxOptions(
+			fx.Invoke(RegisterTestController),
+		),
+		test.GomegaSubTest(SubTestLimitedEndpoint(), "TestLimitedEndpoint"),
+		test.GomegaSubTest(SubTestUnlimitedEndpoint(), "TestUnlimitedEndpoint"),
+		test.GomegaSubTest(SubTestSpoofedForwardedFor(), "TestSpoofedForwardedFor"),
+	)
+}
+
+func TestRateLimitRefresh(t *testing.T) {
+	di := RefreshTestDI{}
+	limit := atomic.Int64{}
+	limit.Store(1)
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		webtest.WithMockedServer(),
+		apptest.WithModules(ratelimit.Module),
+		apptest.WithProperties(
+			"server.rate-limit.enabled: true",
+			"server.rate-limit.policies.default.patterns: /limited/**",
+		),
+		apptest.WithDynamicProperties(map[string]apptest.PropertyValuerFunc{
+			"server.rate-limit.policies.default.limit": func(ctx context.Context) interface{} {
+				return limit.Load()
+			},
+		}),
+		apptest.WithDI(&di),
+		apptest.WithFxOptions(
+			fx.Invoke(RegisterTestController),
+		),
+		test.GomegaSubTest(SubTestRefreshedLimit(&di, &limit), "TestRefreshedLimit"),
+	)
+}
+
+func TestRateLimitFailure(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestFailClosed(), "TestFailClosed"),
+		test.GomegaSubTest(SubTestFailOpen(), "TestFailOpen"),
+	)
+}
+
+func TestKeyByIP(t *testing.T) {
+	test.RunTest(context.Background(), t,
+		test.GomegaSubTest(SubTestKeyByIPWithoutTrustedProxies(), "TestWithoutTrustedProxies"),
+		test.GomegaSubTest(SubTestKeyByIPWithTrustedProxies(), "TestWithTrustedProxies"),
+	)
+}
+
+/*************************
+	Sub-Test Cases
+ *************************/
+
+func SubTestTokenBucket(limiter ratelimit.Limiter) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		limit := ratelimit.Limit{Limit: 1, Window: time.Minute, Burst: 2}
+		AssertAllowed(ctx, g, limiter, "test-key", limit, 1)
+		AssertAllowed(ctx, g, limiter, "test-key", limit, 0)
+		AssertDenied(ctx, g, limiter, "test-key", limit)
+		// other keys are not affected
+		AssertAllowed(ctx, g, limiter, "another-key", limit, 1)
+	}
+}
+
+func SubTestSlidingWindow(limiter ratelimit.Limiter) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		limit := ratelimit.Limit{Limit: 2, Window: time.Minute}
+		AssertAllowed(ctx, g, limiter, "test-key", limit, 1)
+		AssertAllowed(ctx, g, limiter, "test-key", limit, 0)
+		AssertDenied(ctx, g, limiter, "test-key", limit)
+		// other keys are not affected
+		AssertAllowed(ctx, g, limiter, "another-key", limit, 1)
+	}
+}
+
+func SubTestRedisLimiter(di *LimiterTestDI, algorithm ratelimit.Algorithm, fn func(ratelimit.Limiter) test.GomegaSubTestFunc) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		client, e := di.ClientFactory.New(ctx, func(opt *redis.ClientOption) {
+			opt.DbIndex = 5
+		})
+		g.Expect(e).To(Succeed(), "creating redis client should not fail")
+		limiter := ratelimit.NewRedisLimiter(client, func(opt *ratelimit.RedisLimiterOption) {
+			opt.Algorithm = algorithm
+			opt.KeyPrefix = "TEST:" + string(algorithm) + ":"
+		})
+		fn(limiter)(ctx, t, g)
+	}
+}
+
+func SubTestLimitedEndpoint() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		for i := 1; i >= 0; i-- {
+			resp := webtest.MustExec(ctx, webtest.NewRequest(ctx, http.MethodGet, "/limited/hello", nil)).Response
+			g.Expect(resp.StatusCode).To(Equal(http.StatusOK), "status code should be correct")
+			g.Expect(resp.Header.Get(ratelimit.HeaderRateLimitLimit)).To(Equal("2"), "%s header should be correct", ratelimit.HeaderRateLimitLimit)
+			g.Expect(resp.Header.Get(ratelimit.HeaderRateLimitRemaining)).To(Equal(strconv.Itoa(i)), "%s header should be correct", ratelimit.HeaderRateLimitRemaining)
+			g.Expect(resp.Header.Get(ratelimit.HeaderRateLimitPolicy)).To(Equal("2;w=60"), "%s header should be correct", ratelimit.HeaderRateLimitPolicy)
+		}
+
+		resp := webtest.MustExec(ctx, webtest.NewRequest(ctx, http.MethodGet, "/limited/hello", nil)).Response
+		g.Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests), "status code should be correct")
+		g.Expect(resp.Header.Get(ratelimit.HeaderRateLimitRemaining)).To(Equal("0"), "%s header should be correct", ratelimit.HeaderRateLimitRemaining)
+		g.Expect(resp.Header.Get(ratelimit.HeaderRetryAfter)).ToNot(BeEmpty(), "%s header should be set", ratelimit.HeaderRetryAfter)
+	}
+}
+
+func SubTestRefreshedLimit(di *RefreshTestDI, limit *atomic.Int64) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		resp := webtest.MustExec(ctx, webtest.NewRequest(ctx, http.MethodGet, "/limited/hello", nil)).Response
+		g.Expect(resp.StatusCode).To(Equal(http.StatusOK), "status code should be correct")
+		g.Expect(resp.Header.Get(ratelimit.HeaderRateLimitLimit)).To(Equal("1"), "%s header should be correct", ratelimit.HeaderRateLimitLimit)
+
+		limit.Store(5)
+		event, e := di.AppConfig.Refresh(ctx)
+		g.Expect(e).To(Succeed(), "refresh should not fail")
+		g.Expect(event).ToNot(BeNil(), "refresh should have changes")
+
+		// Note: quota already consumed is kept by the limiter, we only verify the refreshed limit is applied
+		resp = webtest.MustExec(ctx, webtest.NewRequest(ctx, http.MethodGet, "/limited/hello", nil)).Response
+		g.Expect(resp.Header.Get(ratelimit.HeaderRateLimitLimit)).To(Equal("5"), "%s header should be refreshed", ratelimit.HeaderRateLimitLimit)
+		g.Expect(resp.Header.Get(ratelimit.HeaderRateLimitPolicy)).To(Equal("5;w=60"), "%s header should be refreshed", ratelimit.HeaderRateLimitPolicy)
+	}
+}
+
+func SubTestUnlimitedEndpoint() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		for i := 0; i < 3; i++ {
+			resp := webtest.MustExec(ctx, webtest.NewRequest(ctx, http.MethodGet, "/hello", nil)).Response
+			g.Expect(resp.StatusCode).To(Equal(http.StatusOK), "status code should be correct")
+			g.Expect(resp.Header.Get(ratelimit.HeaderRateLimitLimit)).To(BeEmpty(), "%s header should not be set", ratelimit.HeaderRateLimitLimit)
+		}
+	}
+}
+
+func SubTestSpoofedForwardedFor() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		for i, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+			req := webtest.NewRequest(ctx, http.MethodGet, "/spoofed/hello", nil, func(req *http.Request) {
+				req.Header.Set("X-Forwarded-For", ip)
+			})
+			resp := webtest.MustExec(ctx, req).Response
+			if i == 0 {
+				g.Expect(resp.StatusCode).To(Equal(http.StatusOK), "status code should be correct")
+			} else {
+				g.Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests), "forwarded IP from untrusted proxy should be ignored")
+			}
+		}
+	}
+}
+
+func SubTestKeyByIPWithoutTrustedProxies() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		keyFunc := ratelimit.KeyByIP()
+		req := NewForwardedRequest("10.0.0.1:8080", "192.168.0.1")
+		g.Expect(keyFunc(ctx, req)).To(Equal("ip:10.0.0.1"), "forwarding headers should be ignored")
+	}
+}
+
+func SubTestKeyByIPWithTrustedProxies() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		keyFunc := ratelimit.KeyByIP("10.0.0.0/8", "172.16.0.1")
+		req := NewForwardedRequest("10.0.0.1:8080", "192.168.0.1, 172.16.0.1")
+		g.Expect(keyFunc(ctx, req)).To(Equal("ip:192.168.0.1"), "right-most untrusted forwarded IP should be used")
+		req = NewForwardedRequest("10.0.0.1:8080", "1.2.3.4, 192.168.0.1")
+		g.Expect(keyFunc(ctx, req)).To(Equal("ip:192.168.0.1"), "spoofed left-most forwarded IP should be ignored")
+		req = NewForwardedRequest("192.168.0.2:8080", "192.168.0.1")
+		g.Expect(keyFunc(ctx, req)).To(Equal("ip:192.168.0.2"), "forwarding headers from untrusted proxy should be ignored")
+
+		_, e := ratelimit.KeyTypeIP.KeyFunc("not-an-ip")
+		g.Expect(e).To(HaveOccurred(), "invalid trusted proxy should fail")
+	}
+}
+
+func SubTestFailClosed() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		mw := newFailingMiddleware()
+		gc := webtest.NewGinContext(ctx, http.MethodGet, "/hello", nil)
+		mw.HandlerFunc()(gc)
+		g.Expect(gc.IsAborted()).To(BeTrue(), "request should be rejected when limiter fails")
+		g.Expect(gc.Errors).To(HaveLen(1), "error should be recorded")
+	}
+}
+
+func SubTestFailOpen() test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		mw := newFailingMiddleware(func(opt *ratelimit.MiddlewareOption) {
+			opt.FailOpen = true
+		})
+		gc := webtest.NewGinContext(ctx, http.MethodGet, "/hello", nil)
+		mw.HandlerFunc()(gc)
+		g.Expect(gc.IsAborted()).To(BeFalse(), "request should be allowed when limiter fails with fail-open")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(_ context.Context, _ string, _ ratelimit.Limit) (*ratelimit.Result, error) {
+	return nil, errors.New("limiter unavailable")
+}
+
+func NewForwardedRequest(remoteAddr, forwardedFor string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set("X-Forwarded-For", forwardedFor)
+	return req
+}
+
+func newFailingMiddleware(opts ...ratelimit.MiddlewareOptions) *ratelimit.Middleware {
+	opts = append([]ratelimit.MiddlewareOptions{func(opt *ratelimit.MiddlewareOption) {
+		opt.Limiter = failingLimiter{}
+		opt.Limit = ratelimit.Limit{Limit: 1, Window: time.Minute}
+	}}, opts...)
+	return ratelimit.NewMiddleware(opts...)
+}
+
+func AssertAllowed(ctx context.Context, g *gomega.WithT, limiter ratelimit.Limiter, key string, limit ratelimit.Limit, expectedRemaining int) {
+	result, e := limiter.Allow(ctx, key, limit)
+	g.Expect(e).To(Succeed(), "Allow should not fail")
+	g.Expect(result.Allowed).To(BeTrue(), "request should be allowed")
+	g.Expect(result.Remaining).To(Equal(expectedRemaining), "remaining should be correct")
+	g.Expect(result.RetryAfter).To(BeZero(), "retry after should be zero")
+}
+
+func AssertDenied(ctx context.Context, g *gomega.WithT, limiter ratelimit.Limiter, key string, limit ratelimit.Limit) {
+	result, e := limiter.Allow(ctx, key, limit)
+	g.Expect(e).To(Succeed(), "Allow should not fail")
+	g.Expect(result.Allowed).To(BeFalse(), "request should be denied")
+	g.Expect(result.Remaining).To(BeZero(), "remaining should be zero")
+	g.Expect(result.RetryAfter).To(BeNumerically(">", 0), "retry after should be positive")
+	g.Expect(result.RetryAfter).To(BeNumerically("<=", limit.Window), "retry after should not exceed window")
+}
+
+/*************************
+	Dummy Controller
+ *************************/
+
+type TestController struct{}
+
+func (c TestController) Mappings() []web.Mapping {
+	return []web.Mapping{
+		rest.Get("/limited/hello").EndpointFunc(c.Hello).Build(),
+		rest.Get("/hello").EndpointFunc(c.Hello).Build(),
+		rest.Get("/spoofed/hello").EndpointFunc(c.Hello).Build(),
+	}
+}
+
+func (TestController) Hello(_ context.Context, _ *http.Request) (interface{}, error) {
+	return map[string]string{"message": "hello"}, nil
+}
@@ -18,6 +18,7 @@ package web
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"github.com/cisco-open/go-lanai/pkg/utils"
@@ -26,6 +27,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"go.uber.org/fx"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"html/template"
 	"io/fs"
 	"net"
@@ -46,6 +49,7 @@ type Registrar struct {
 	engine           *Engine
 	router           gin.IRouter
 	server           *http.Server
+	tlsConfig        *tls.Config
 	port             int
 	properties       ServerProperties
 	validator        *Validate
@@ -85,6 +89,14 @@ func (r *Registrar) Initialize(ctx context.Context) (err error) {
 		return fmt.Errorf("attempting to initialize web engine multiple times")
 	}
 
+	// when trusted proxies are set, client IP is resolved from forwarding headers only when the request comes from them.
+	// Otherwise, gin's default is kept
+	if len(r.properties.TrustedProxies) != 0 {
+		if err = r.engine.SetTrustedProxies(r.properties.TrustedProxies); err != nil {
+			return fmt.Errorf("invalid trusted proxies: %v", err)
+		}
+	}
+
 	// first, we add some mandatory customizers and middleware
 	r.MustRegister(NewPriorityGinContextCustomizer(&r.properties))
 
@@ -166,10 +178,14 @@ func (r *Registrar) Run(ctx context.Context) (err error) {
 	r.server = &http.Server{
 		Addr:           addr,
 		Handler:        r.engine,
+		TLSConfig:      r.tlsConfig,
 		ReadTimeout:    60 * time.Second,
 		WriteTimeout:   60 * time.Second,
 		MaxHeaderBytes: 1 << 20,
 	}
+	if err = r.configureHTTP2(); err != nil {
+		return
+	}
 
 	// start the server
 	tcpAddr, e := r.listenAndServe()
@@ -193,6 +209,21 @@ func (r *Registrar) Stop(ctx context.Context) (err error) {
 	return
 }
 
+// ServerTLSConfig returns the effective tls.Config of the server, returns nil if TLS is not enabled
+func (r *Registrar) ServerTLSConfig() *tls.Config {
+	return r.tlsConfig
+}
+
+// SetServerTLSConfig enables TLS with given tls.Config. It has to be called before the server is started, typically in Customizer.
+// tls.Config.Certificates or tls.Config.GetCertificate should be set.
+func (r *Registrar) SetServerTLSConfig(cfg *tls.Config) error {
+	if r.server != nil {
+		return fmt.Errorf("attempting to change TLS configuration after server is started")
+	}
+	r.tlsConfig = cfg
+	return nil
+}
+
 // ServerPort returns the port of started server, returns 0 if server is not initialized
 func (r *Registrar) ServerPort() int {
 	return r.port
@@ -232,11 +263,30 @@ func (r *Registrar) listenAndServe() (*net.TCPAddr, error) {
 	}
 
 	go func() {
-		_ = r.server.Serve(ln)
+		if r.server.TLSConfig != nil {
+			_ = r.server.ServeTLS(ln, "", "")
+		} else {
+			_ = r.server.Serve(ln)
+		}
 	}()
 	return ln.Addr().(*net.TCPAddr), nil
 }
 
+// configureHTTP2 enables or disables HTTP/2 based on properties.
+// With TLS, HTTP/2 is negotiated via ALPN. Without TLS, h2c is used when enabled.
+func (r *Registrar) configureHTTP2() error {
+	switch {
+	case r.server.TLSConfig != nil && r.properties.HTTP2.Enabled:
+		return http2.ConfigureServer(r.server, &http2.Server{})
+	case r.server.TLSConfig != nil:
+		// non-nil empty map disables the automatic HTTP/2 support of net/http
+		r.server.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	case r.properties.HTTP2.H2C:
+		r.server.Handler = h2c.NewHandler(r.server.Handler, &http2.Server{})
+	}
+	return nil
+}
+
 func (r *Registrar) register(i interface{}) (err error) {
 	if r.initialized {
 		return errors.New("attempting to register mappings/middlewares/pre-processors after web engine initialization")
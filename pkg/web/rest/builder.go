@@ -40,6 +40,10 @@ import (
 // e.g.: func(context.Context, request *AnyStructWithTag) (response *AnyStructWithTag, error) {...}
 type EndpointFunc interface{}
 
+// MappingDecorator wraps the web.MvcMapping built by MappingBuilder to add cross-cutting behaviors to the endpoint.
+// e.g. idempotency.Idempotent
+type MappingDecorator func(m web.MvcMapping) web.MvcMapping
+
 // MappingBuilder builds web.EndpointMapping using web.GinBindingRequestDecoder, web.JsonResponseEncoder and web.JsonErrorEncoder
 // MappingBuilder.Path, MappingBuilder.Method and MappingBuilder.EndpointFunc are required to successfully build a mapping.
 // See EndpointFunc for supported strongly typed function signatures.
@@ -57,6 +61,7 @@ type MappingBuilder struct {
 	decodeRequestFunc  web.DecodeRequestFunc
 	encodeResponseFunc web.EncodeResponseFunc
 	encodeErrorFunc    web.EncodeErrorFunc
+	decorators         []MappingDecorator
 }
 
 func New(names ...string) *MappingBuilder {
@@ -185,12 +190,23 @@ func (b *MappingBuilder) EncodeErrorFunc(f web.EncodeErrorFunc) *MappingBuilder
 	return b
 }
 
+// Decorate adds MappingDecorator to be applied on the built mapping, in given order
+func (b *MappingBuilder) Decorate(decorators ...MappingDecorator) *MappingBuilder {
+	b.decorators = append(b.decorators, decorators...)
+	return b
+}
 
 func (b *MappingBuilder) Build() web.EndpointMapping {
 	if err := b.validate(); err != nil {
 		panic(err)
 	}
-	return b.buildMapping()
+	m := b.buildMapping()
+	for _, decorator := range b.decorators {
+		if decorator != nil {
+			m = decorator(m)
+		}
+	}
+	return m
 }
 
 /*****************************
@@ -0,0 +1,124 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package web
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/certs"
+	"os"
+	"strings"
+)
+
+const (
+	ClientAuthNone     ClientAuthMode = "none"
+	ClientAuthOptional ClientAuthMode = "optional"
+	ClientAuthRequired ClientAuthMode = "required"
+)
+
+// ClientAuthMode controls how the server verifies client certificates.
+//   - ClientAuthNone: client certificate is not requested
+//   - ClientAuthOptional: client certificate is requested and verified if given
+//   - ClientAuthRequired: verified client certificate is required to establish connection
+//
+// Verified certificates are available via http.Request.TLS (VerifiedChains)
+type ClientAuthMode string
+
+func (m ClientAuthMode) TLSClientAuthType() (tls.ClientAuthType, error) {
+	switch ClientAuthMode(strings.ToLower(string(m))) {
+	case ClientAuthNone, "":
+		return tls.NoClientCert, nil
+	case ClientAuthOptional:
+		return tls.VerifyClientCertIfGiven, nil
+	case ClientAuthRequired:
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return tls.NoClientCert, fmt.Errorf(`unsupported client-auth mode [%s]`, m)
+	}
+}
+
+// TLSCustomizer implements Customizer. It enables server TLS based on TLSProperties using certificate source from certs.Manager
+type TLSCustomizer struct {
+	props    TLSProperties
+	certsMgr certs.Manager
+}
+
+func NewTLSCustomizer(props ServerProperties, certsMgr certs.Manager) *TLSCustomizer {
+	return &TLSCustomizer{
+		props:    props.TLS,
+		certsMgr: certsMgr,
+	}
+}
+
+func (c *TLSCustomizer) Customize(ctx context.Context, r *Registrar) error {
+	if !c.props.Enabled {
+		return nil
+	}
+	if c.certsMgr == nil {
+		return fmt.Errorf("server TLS is enabled, but certificate manager is not available")
+	}
+	tlsCfg, e := NewServerTLSConfig(ctx, c.props, c.certsMgr)
+	if e != nil {
+		return fmt.Errorf("unable to initialize server TLS: %v", e)
+	}
+	logger.WithContext(ctx).Infof("server TLS enabled with client-auth [%s]", c.props.ClientAuth)
+	return r.SetServerTLSConfig(tlsCfg)
+}
+
+// NewServerTLSConfig creates server side tls.Config from TLSProperties using certificate source from certs.Manager.
+// Client certificates are verified against TLSProperties.ClientCAFile when client auth is enabled.
+func NewServerTLSConfig(ctx context.Context, props TLSProperties, certsMgr certs.Manager) (*tls.Config, error) {
+	clientAuth, e := props.ClientAuth.TLSClientAuthType()
+	if e != nil {
+		return nil, e
+	}
+	src, e := certsMgr.Source(ctx, certs.WithSourceProperties(&props.Certs))
+	if e != nil {
+		return nil, e
+	}
+	tlsOpts := []certs.TLSOptions{certs.AsServer()}
+	if clientAuth != tls.NoClientCert {
+		clientCAs, e := loadClientCAs(props)
+		if e != nil {
+			return nil, e
+		}
+		tlsOpts = append(tlsOpts, certs.WithClientCAs(clientCAs))
+	}
+	tlsCfg, e := src.TLSConfig(ctx, tlsOpts...)
+	if e != nil {
+		return nil, e
+	}
+	tlsCfg.ClientAuth = clientAuth
+	return tlsCfg, nil
+}
+
+func loadClientCAs(props TLSProperties) (*x509.CertPool, error) {
+	if len(props.ClientCAFile) == 0 {
+		return nil, fmt.Errorf(`"client-ca-file" is required when client-auth is [%s]`, props.ClientAuth)
+	}
+	caPem, e := os.ReadFile(props.ClientCAFile)
+	if e != nil {
+		return nil, e
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPem) {
+		return nil, fmt.Errorf(`no valid CA certificate found in [%s]`, props.ClientCAFile)
+	}
+	return pool, nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2023 Cisco Systems, Inc. and its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package web_test
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/cisco-open/go-lanai/pkg/certs"
+	certsinit "github.com/cisco-open/go-lanai/pkg/certs/init"
+	"github.com/cisco-open/go-lanai/pkg/web"
+	"github.com/cisco-open/go-lanai/pkg/web/mapping"
+	"github.com/cisco-open/go-lanai/test"
+	"github.com/cisco-open/go-lanai/test/apptest"
+	"github.com/gin-gonic/gin"
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"go.uber.org/fx"
+	"golang.org/x/net/http2"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+)
+
+/*************************
+	Setup
+ *************************/
+
+const (
+	TestTLSPath = "/tls"
+	TestCertSrc = `{"type":"file","ca-cert-file":"testdata/certs/ca-cert-test.pem","cert-file":"testdata/certs/client-cert-signed-test.pem","key-file":"testdata/certs/client-key-test.pem","key-pass":"foobar"}`
+)
+
+type TLSTestDI struct {
+	TestDI
+	CertsManager certs.Manager
+}
+
+/*************************
+	Tests
+ *************************/
+
+func TestServerTLS(t *testing.T) {
+	var di TLSTestDI
+	test.RunTest(context.Background(), t,
+		apptest.Bootstrap(),
+		apptest.WithModules(certsinit.Module),
+		apptest.WithDI(&di),
+		apptest.WithFxOptions(
+			fx.Provide(web.NewEngine, web.BindServerProperties),
+		),
+		test.SubTestSetup(ResetEngine(&di.TestDI)),
+		test.GomegaSubTest(SubTestTLSWithHTTP2(&di), "TestTLSWithHTTP2"),
+		test.GomegaSubTest(SubTestTLSWithoutHTTP2(&di), "TestTLSWithoutHTTP2"),
+		test.GomegaSubTest(SubTestMutualTLS(&di), "TestMutualTLS"),
+		test.GomegaSubTest(SubTestMutualTLSWithoutClientCAs(&di), "TestMutualTLSWithoutClientCAs"),
+		test.GomegaSubTest(SubTestH2C(&di), "TestH2C"),
+	)
+}
+
+/*************************
+	Sub Tests
+ *************************/
+
+func SubTestTLSWithHTTP2(di *TLSTestDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		props := newTLSTestProperties(web.ClientAuthNone)
+		reg := startTestServer(ctx, g, di, props)
+		defer func() { _ = reg.Stop(ctx) }()
+
+		client := newTLSTestClient(ctx, g, di, false)
+		resp := executeTLSTestRequest(g, client, "https", reg.ServerPort())
+		g.Expect(resp.TLS).ToNot(BeNil(), "response should be over TLS")
+		g.Expect(resp.ProtoMajor).To(Equal(2), "response should be HTTP/2")
+		g.Expect(readTLSTestBody(g, resp)).To(Equal("no client cert"))
+	}
+}
+
+func SubTestTLSWithoutHTTP2(di *TLSTestDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		props := newTLSTestProperties(web.ClientAuthNone)
+		props.HTTP2.Enabled = false
+		reg := startTestServer(ctx, g, di, props)
+		defer func() { _ = reg.Stop(ctx) }()
+
+		client := newTLSTestClient(ctx, g, di, false)
+		resp := executeTLSTestRequest(g, client, "https", reg.ServerPort())
+		g.Expect(resp.TLS).ToNot(BeNil(), "response should be over TLS")
+		g.Expect(resp.ProtoMajor).To(Equal(1), "response should be HTTP/1.1")
+	}
+}
+
+func SubTestMutualTLS(di *TLSTestDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		props := newTLSTestProperties(web.ClientAuthRequired)
+		reg := startTestServer(ctx, g, di, props)
+		defer func() { _ = reg.Stop(ctx) }()
+
+		// with client certificate
+		client := newTLSTestClient(ctx, g, di, true)
+		resp := executeTLSTestRequest(g, client, "https", reg.ServerPort())
+		g.Expect(readTLSTestBody(g, resp)).To(Equal("localhost"), "handler should see verified client certificate")
+
+		// without client certificate
+		client = newTLSTestClient(ctx, g, di, false)
+		_, e := client.Get(fmt.Sprintf("https://localhost:%d%s", reg.ServerPort(), TestTLSPath))
+		g.Expect(e).To(HaveOccurred(), "request without client certificate should fail")
+	}
+}
+
+func SubTestMutualTLSWithoutClientCAs(di *TLSTestDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		props := newTLSTestProperties(web.ClientAuthRequired)
+		props.TLS.ClientCAFile = ""
+		reg := web.NewRegistrar(di.Engine, props)
+		e := web.NewTLSCustomizer(props, di.CertsManager).Customize(ctx, reg)
+		g.Expect(e).To(HaveOccurred(), "mutual TLS without client CAs should fail")
+	}
+}
+
+func SubTestH2C(di *TLSTestDI) test.GomegaSubTestFunc {
+	return func(ctx context.Context, t *testing.T, g *gomega.WithT) {
+		props := web.NewServerProperties()
+		props.Port = 0
+		props.HTTP2.H2C = true
+		reg := startTestServer(ctx, g, di, *props)
+		defer func() { _ = reg.Stop(ctx) }()
+
+		client := &http.Client{
+			Transport: &http2.Transport{
+				AllowHTTP: true,
+				DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, network, addr)
+				},
+			},
+		}
+		resp := executeTLSTestRequest(g, client, "http", reg.ServerPort())
+		g.Expect(resp.TLS).To(BeNil(), "response should not be over TLS")
+		g.Expect(resp.ProtoMajor).To(Equal(2), "response should be HTTP/2")
+	}
+}
+
+/*************************
+	Helpers
+ *************************/
+
+func newTLSTestProperties(clientAuth web.ClientAuthMode) web.ServerProperties {
+	props := web.NewServerProperties()
+	props.Port = 0
+	props.TLS.Enabled = true
+	props.TLS.ClientAuth = clientAuth
+	props.TLS.ClientCAFile = "testdata/certs/ca-cert-test.pem"
+	props.TLS.Certs = certs.SourceProperties{
+		Type: certs.SourceFile,
+		Raw:  []byte(TestCertSrc),
+	}
+	return *props
+}
+
+func startTestServer(ctx context.Context, g *gomega.WithT, di *TLSTestDI, props web.ServerProperties) *web.Registrar {
+	reg := web.NewRegistrar(di.Engine, props)
+	reg.MustRegister(web.NewTLSCustomizer(props, di.CertsManager))
+	reg.MustRegister(mapping.Get(TestTLSPath).HandlerFunc(gin.HandlerFunc(func(gc *gin.Context) {
+		if gc.Request.TLS == nil || len(gc.Request.TLS.VerifiedChains) == 0 {
+			gc.String(http.StatusOK, "no client cert")
+			return
+		}
+		gc.String(http.StatusOK, gc.Request.TLS.VerifiedChains[0][0].Subject.CommonName)
+	})).Build())
+	g.Expect(reg.Run(ctx)).To(Succeed(), "server should start")
+	return reg
+}
+
+func newTLSTestClient(ctx context.Context, g *gomega.WithT, di *TLSTestDI, withClientCert bool) *http.Client {
+	src, e := di.CertsManager.Source(ctx, certs.WithType(certs.SourceFile, TestCertSrc))
+	g.Expect(e).To(Succeed(), "certificate source should be available")
+	tlsCfg, e := src.TLSConfig(ctx)
+	g.Expect(e).To(Succeed(), "client TLS config should be available")
+	if !withClientCert {
+		tlsCfg.GetClientCertificate = nil
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:   tlsCfg,
+			ForceAttemptHTTP2: true,
+		},
+	}
+}
+
+func executeTLSTestRequest(g *gomega.WithT, client *http.Client, scheme string, port int) *http.Response {
+	resp, e := client.Get(fmt.Sprintf("%s://localhost:%d%s", scheme, port, TestTLSPath))
+	g.Expect(e).To(Succeed(), "request should succeed")
+	g.Expect(resp.StatusCode).To(Equal(http.StatusOK), "response should have correct status code")
+	return resp
+}
+
+func readTLSTestBody(g *gomega.WithT, resp *http.Response) string {
+	defer func() { _ = resp.Body.Close() }()
+	body, e := io.ReadAll(resp.Body)
+	g.Expect(e).To(Succeed(), "response body should be readable")
+	return string(body)
+}
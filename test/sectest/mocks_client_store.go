@@ -35,6 +35,12 @@ var (
 		oauth2.GrantTypeSwitchUser,
 		oauth2.GrantTypeSwitchTenant,
 		oauth2.GrantTypeSamlSSO,
+		oauth2.GrantTypeDeviceCode,
+		oauth2.GrantTypeTokenExchange,
+	)
+
+	defaultClientExchangeTypes = utils.NewStringSet(
+		oauth2.TokenTypeIdAccessToken, oauth2.TokenTypeIdJwt,
 	)
 
 	defaultClientScopes = utils.NewStringSet(
@@ -146,6 +152,29 @@ func (m MockedClient) ResourceIDs() utils.StringSet {
 	return utils.NewStringSet()
 }
 
+func (m MockedClient) TokenExchangeSubjectTypes() utils.StringSet {
+	if m.MockedClientProperties.ExchangeTypes == nil {
+		return defaultClientExchangeTypes
+	}
+	return utils.NewStringSet(m.MockedClientProperties.ExchangeTypes...)
+}
+
+func (m MockedClient) TokenEndpointAuthMethod() string {
+	return m.MockedClientProperties.AuthMethod
+}
+
+func (m MockedClient) JwkSetUri() string {
+	return m.MockedClientProperties.JwkSetUri
+}
+
+func (m MockedClient) TLSClientAuthSubjectDN() string {
+	return m.MockedClientProperties.TLSSubjectDN
+}
+
+func (m MockedClient) DPoPBoundAccessTokens() bool {
+	return m.MockedClientProperties.DPoPBound
+}
+
 type MockedClientStore struct {
 	idLookup map[string]*MockedClient
 }
@@ -93,6 +93,11 @@ type MockedClientProperties struct {
 	ATValidity        utils.Duration            `json:"access-token-validity"`
 	RTValidity        utils.Duration            `json:"refresh-token-validity"`
 	AssignedTenantIds utils.CommaSeparatedSlice `json:"tenants"`
+	ExchangeTypes     utils.CommaSeparatedSlice `json:"token-exchange-subject-types"`
+	AuthMethod        string                    `json:"token-endpoint-auth-method"`
+	JwkSetUri         string                    `json:"jwks-uri"`
+	TLSSubjectDN      string                    `json:"tls-client-auth-subject-dn"`
+	DPoPBound         bool                      `json:"dpop-bound-access-tokens"`
 }
 
 type MockedPropertiesAccounts struct {
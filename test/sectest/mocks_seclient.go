@@ -156,6 +156,39 @@ func (c *mockedAuthClient) SwitchTenant(_ context.Context, opts ...seclient.Auth
 	}, nil
 }
 
+func (c *mockedAuthClient) TokenExchange(_ context.Context, opts ...seclient.AuthOptions) (*seclient.Result, error) {
+	opt, e := c.option(opts)
+	if e != nil {
+		return nil, e
+	}
+
+	if opt.Username != "" || opt.UserId != "" || opt.TenantId != "" || opt.TenantExternalId != "" {
+		return nil, fmt.Errorf("[Mocked Error] user and tenant cannot be changed in token exchange")
+	}
+
+	mt, e := c.parseMockedToken(opt.AccessToken)
+	if e != nil || mt.UName == "" {
+		return nil, fmt.Errorf("[Mocked Error] invalid subject token")
+	}
+
+	acct := c.accounts.find(mt.UName, mt.UID)
+	if acct == nil {
+		return nil, fmt.Errorf("[Mocked Error] deleted user")
+	}
+
+	tenant := c.tenants.find(mt.TID, mt.TExternalId)
+	if tenant == nil {
+		return nil, fmt.Errorf("[Mocked Error] subject token is not associated with a tenant")
+	}
+
+	exp := time.Now().UTC().Add(c.tokenExp)
+	token := c.newMockedToken(acct, tenant, exp, mt.OrigU)
+	token.MockedTokenInfo.Scopes = opt.Scopes
+	return &seclient.Result{
+		Token: token,
+	}, nil
+}
+
 func (c *mockedAuthClient) option(opts []seclient.AuthOptions) (*seclient.AuthOption, error) {
 	opt := seclient.AuthOption{}
 	for _, fn := range opts {